@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+const cardExportVersion = 1
+
+// cardExport is the file written by cards export and read by cards import.
+type cardExport struct {
+	Version    int                 `json:"version"`
+	ExportedAt time.Time           `json:"exported_at"`
+	UserID     uuid.UUID           `json:"user_id"`
+	Cards      []*models.BingoCard `json:"cards"`
+}
+
+func (e *env) exportCards(ctx context.Context, args []string) error {
+	fs := newFlagSet("cards export")
+	ref := fs.String("user", "", "user to export")
+	output := fs.String("o", "-", "output file (- for stdout)")
+	if err := fs.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if *ref == "" {
+		return usagef("cards export needs -user")
+	}
+
+	db, err := e.db()
+	if err != nil {
+		return err
+	}
+	user, err := findUser(ctx, services.NewUserService(db), *ref)
+	if err != nil {
+		return err
+	}
+	cards, err := services.NewCardService(db).ListByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if cards == nil {
+		cards = []*models.BingoCard{}
+	}
+
+	out := e.out
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("creating export file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(cardExport{
+		Version:    cardExportVersion,
+		ExportedAt: time.Now().UTC(),
+		UserID:     user.ID,
+		Cards:      cards,
+	}); err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	if *output != "-" {
+		e.printf("Exported %d cards for %s to %s\n", len(cards), user.Email, *output)
+	}
+	return nil
+}
+
+func (e *env) importCards(ctx context.Context, args []string) error {
+	fs := newFlagSet("cards import")
+	ref := fs.String("user", "", "user who will own the imported cards")
+	input := fs.String("i", "-", "export file (- for stdin)")
+	if err := fs.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if *ref == "" {
+		return usagef("cards import needs -user")
+	}
+
+	in := e.stdin
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return fmt.Errorf("opening export file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	export, err := readCardExport(in)
+	if err != nil {
+		return err
+	}
+
+	db, err := e.db()
+	if err != nil {
+		return err
+	}
+	user, err := findUser(ctx, services.NewUserService(db), *ref)
+	if err != nil {
+		return err
+	}
+
+	cardService := services.NewCardService(db)
+	var imported, skipped int
+	for _, card := range export.Cards {
+		created, err := cardService.Import(ctx, importParams(user.ID, card))
+		if errors.Is(err, services.ErrCardTitleExists) || errors.Is(err, services.ErrCardAlreadyExists) {
+			e.printf("Skipped %d %q: the user already has a card with that title\n", card.Year, card.DisplayName())
+			skipped++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("importing %d %q: %w", card.Year, card.DisplayName(), err)
+		}
+		if err := restoreCompletions(ctx, cardService, user.ID, created, card); err != nil {
+			return fmt.Errorf("restoring completions for %d %q: %w", card.Year, card.DisplayName(), err)
+		}
+		imported++
+	}
+
+	e.printf("Imported %d cards for %s (%d skipped)\n", imported, user.Email, skipped)
+	return nil
+}
+
+func readCardExport(r io.Reader) (*cardExport, error) {
+	var export cardExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("reading export: %w", err)
+	}
+	if export.Version != cardExportVersion {
+		return nil, fmt.Errorf("unsupported export version %d", export.Version)
+	}
+	return &export, nil
+}
+
+// importParams maps an exported card onto a new card for userID. Items a
+// moderator hid are left out, as they are when a card is published as a
+// template.
+func importParams(userID uuid.UUID, card *models.BingoCard) models.ImportCardParams {
+	items := make([]models.ImportItem, 0, len(card.Items))
+	for _, item := range card.Items {
+		if item.HiddenAt != nil {
+			continue
+		}
+		items = append(items, models.ImportItem{Position: item.Position, Content: item.Content})
+	}
+	visible := card.VisibleToFriends
+	return models.ImportCardParams{
+		UserID:           userID,
+		Year:             card.Year,
+		Title:            card.Title,
+		Category:         card.Category,
+		Items:            items,
+		Finalize:         card.IsFinalized,
+		VisibleToFriends: &visible,
+		GridSize:         card.GridSize,
+		HeaderText:       card.HeaderText,
+		HasFreeSpace:     card.HasFreeSpace,
+		FreeSpacePos:     card.FreeSpacePos,
+	}
+}
+
+// restoreCompletions marks the items that were completed in the export.
+// Completion dates are reset to now; notes and proof links carry over.
+func restoreCompletions(ctx context.Context, cards *services.CardService, userID uuid.UUID, created, exported *models.BingoCard) error {
+	if !created.IsFinalized {
+		return nil
+	}
+	for _, item := range exported.Items {
+		if !item.IsCompleted || item.HiddenAt != nil {
+			continue
+		}
+		params := models.CompleteItemParams{Notes: item.Notes, ProofURL: item.ProofURL}
+		if _, err := cards.CompleteItem(ctx, userID, created.ID, item.Position, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
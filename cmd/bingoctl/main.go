@@ -0,0 +1,189 @@
+// Command bingoctl runs operational tasks against a Year of Bingo database:
+// migrations, account maintenance, card export/import and data cleanup. It
+// reads the same environment variables as the server.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/database"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+const usage = `Usage: bingoctl <command> [arguments]
+
+Migrations:
+  migrate up                          apply all pending migrations
+  migrate down [-steps N] [-all]      roll back N migrations (default 1)
+  migrate version                     print the current version
+  migrate force VERSION               set the version and clear the dirty flag
+
+Users (USER is an email address or user ID):
+  user create -email E -username U [-password-stdin] [-verified] [-admin]
+  user verify-email USER              mark the email address verified
+  user promote USER                   grant admin access
+  user demote USER                    remove admin access
+  user revoke USER                    log out everywhere and delete API tokens
+
+Data:
+  cards export -user USER [-o FILE]   write the user's cards as JSON
+  cards import -user USER [-i FILE]   recreate cards from an export
+  notifications cleanup               delete notifications older than a year
+  suggestions reseed                  replace suggestions with the curated set
+
+Migration and seed files are read from ./migrations unless -migrations DIR
+is given after the command name.
+`
+
+var errUsage = errors.New("invalid usage")
+
+func usagef(format string, args ...any) error {
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), errUsage)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout)
+	if err == nil {
+		return
+	}
+	if err != errUsage {
+		fmt.Fprintf(os.Stderr, "bingoctl: %v\n", err)
+	}
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(os.Stderr, "\n"+usage)
+		os.Exit(2)
+	}
+	os.Exit(1)
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	e := &env{stdin: stdin, out: stdout}
+	defer e.close()
+
+	group, command, rest := args[0], args[1], args[2:]
+	switch group + " " + command {
+	case "migrate up", "migrate down", "migrate version", "migrate force":
+		return e.migrate(command, rest)
+	case "user create":
+		return e.createUser(ctx, rest)
+	case "user verify-email":
+		return e.verifyEmail(ctx, rest)
+	case "user promote":
+		return e.setAdmin(ctx, rest, true)
+	case "user demote":
+		return e.setAdmin(ctx, rest, false)
+	case "user revoke":
+		return e.revokeAccess(ctx, rest)
+	case "cards export":
+		return e.exportCards(ctx, rest)
+	case "cards import":
+		return e.importCards(ctx, rest)
+	case "notifications cleanup":
+		return e.cleanupNotifications(ctx, rest)
+	case "suggestions reseed":
+		return e.reseedSuggestions(ctx, rest)
+	default:
+		return usagef("unknown command %q", group+" "+command)
+	}
+}
+
+// env holds the configuration and connections a command needs. Connections
+// are opened on first use so that, for example, migrate never touches Redis.
+type env struct {
+	stdin io.Reader
+	out   io.Writer
+
+	cfg   *config.Config
+	pg    *database.PostgresDB
+	redis *database.RedisDB
+}
+
+func (e *env) config() (*config.Config, error) {
+	if e.cfg == nil {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+		e.cfg = cfg
+	}
+	return e.cfg, nil
+}
+
+func (e *env) db() (services.DB, error) {
+	if e.pg == nil {
+		cfg, err := e.config()
+		if err != nil {
+			return nil, err
+		}
+		pg, err := database.NewPostgresDB(cfg.Database.DSN(), database.PoolOptions{StatementTimeout: cfg.Database.StatementTimeout})
+		if err != nil {
+			return nil, fmt.Errorf("connecting to postgres: %w", err)
+		}
+		e.pg = pg
+	}
+	return services.NewPoolAdapter(e.pg.Pool), nil
+}
+
+func (e *env) redisClient() (services.RedisClient, error) {
+	if e.redis == nil {
+		cfg, err := e.config()
+		if err != nil {
+			return nil, err
+		}
+		redisDB, err := database.NewRedisDB(cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to redis: %w", err)
+		}
+		e.redis = redisDB
+	}
+	return services.NewRedisAdapter(e.redis.Client), nil
+}
+
+func (e *env) close() {
+	if e.redis != nil {
+		_ = e.redis.Close()
+	}
+	if e.pg != nil {
+		e.pg.Close()
+	}
+}
+
+func (e *env) printf(format string, args ...any) {
+	fmt.Fprintf(e.out, format, args...)
+}
+
+// findUser resolves a user argument, which may be an email address or an ID.
+func findUser(ctx context.Context, users *services.UserService, ref string) (*models.User, error) {
+	var user *models.User
+	var err error
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		user, err = users.GetByID(ctx, id)
+	} else {
+		user, err = users.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(ref)))
+	}
+	if errors.Is(err, services.ErrUserNotFound) {
+		return nil, fmt.Errorf("user %q not found", ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
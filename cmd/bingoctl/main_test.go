@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestRun_UsageErrors(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"migrate"},
+		{"migrate", "sideways"},
+		{"migrate", "down", "-steps", "0"},
+		{"migrate", "force"},
+		{"migrate", "force", "abc"},
+		{"user", "promote"},
+		{"user", "revoke", "a@example.com", "b@example.com"},
+		{"user", "create", "-email", "not-an-email", "-username", "al"},
+		{"user", "create", "-email", "al@example.com", "-username", "a"},
+		{"cards", "export"},
+		{"cards", "import", "-bogus"},
+		{"notifications", "cleanup", "extra"},
+	}
+	for _, args := range tests {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			err := run(context.Background(), args, strings.NewReader(""), &bytes.Buffer{})
+			if !errors.Is(err, errUsage) {
+				t.Fatalf("expected usage error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReadPassword(t *testing.T) {
+	e := &env{stdin: strings.NewReader("Password1\nignored\n")}
+	password, generated, err := e.readPassword(true)
+	if err != nil || password != "Password1" || generated {
+		t.Fatalf("unexpected result: %q %v %v", password, generated, err)
+	}
+
+	e = &env{stdin: strings.NewReader("short\n")}
+	if _, _, err := e.readPassword(true); err == nil {
+		t.Fatal("expected short password to be rejected")
+	}
+
+	password, generated, err = (&env{}).readPassword(false)
+	if err != nil || !generated || len(password) < minPasswordLength {
+		t.Fatalf("expected a generated password, got %q %v %v", password, generated, err)
+	}
+}
+
+func TestReadCardExport_RejectsUnknownVersion(t *testing.T) {
+	if _, err := readCardExport(strings.NewReader(`{"version": 99, "cards": []}`)); err == nil {
+		t.Fatal("expected error")
+	}
+	export, err := readCardExport(strings.NewReader(`{"version": 1, "cards": [{"year": 2025, "grid_size": 3}]}`))
+	if err != nil || len(export.Cards) != 1 {
+		t.Fatalf("unexpected result: %+v %v", export, err)
+	}
+}
+
+func TestImportParams(t *testing.T) {
+	userID := uuid.New()
+	hidden := time.Now()
+	title := "Goals"
+	free := 4
+	card := &models.BingoCard{
+		UserID:           uuid.New(),
+		Year:             2024,
+		Title:            &title,
+		GridSize:         3,
+		HeaderText:       "BIN",
+		HasFreeSpace:     true,
+		FreeSpacePos:     &free,
+		IsFinalized:      true,
+		VisibleToFriends: false,
+		Items: []models.BingoItem{
+			{Position: 0, Content: "Run a 5k", IsCompleted: true},
+			{Position: 1, Content: "removed", HiddenAt: &hidden},
+			{Position: 2, Content: "Read 12 books"},
+		},
+	}
+
+	params := importParams(userID, card)
+	if params.UserID != userID || params.Year != 2024 || !params.Finalize || params.GridSize != 3 || *params.FreeSpacePos != 4 {
+		t.Fatalf("unexpected params: %+v", params)
+	}
+	if params.VisibleToFriends == nil || *params.VisibleToFriends {
+		t.Fatalf("expected visibility to carry over, got %v", params.VisibleToFriends)
+	}
+	if len(params.Items) != 2 || params.Items[1].Position != 2 {
+		t.Fatalf("expected hidden item to be dropped, got %+v", params.Items)
+	}
+}
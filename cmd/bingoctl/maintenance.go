@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// suggestionSeedFile is the migration that first loaded the curated
+// suggestions. Reseeding replays it rather than keeping a second copy.
+const suggestionSeedFile = "000002_seed_suggestions.up.sql"
+
+func (e *env) cleanupNotifications(ctx context.Context, args []string) error {
+	if err := noArgs("notifications cleanup", args); err != nil {
+		return err
+	}
+	db, err := e.db()
+	if err != nil {
+		return err
+	}
+	if err := services.NewNotificationService(db, nil, "").CleanupOld(ctx); err != nil {
+		return err
+	}
+	e.printf("Deleted notifications older than a year\n")
+	return nil
+}
+
+func (e *env) reseedSuggestions(ctx context.Context, args []string) error {
+	fs := newFlagSet("suggestions reseed")
+	dir := fs.String("migrations", "migrations", "migrations directory")
+	if err := fs.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if fs.NArg() != 0 {
+		return usagef("suggestions reseed takes no arguments")
+	}
+
+	seed, err := os.ReadFile(filepath.Join(*dir, suggestionSeedFile))
+	if err != nil {
+		return fmt.Errorf("reading suggestion seed: %w", err)
+	}
+	db, err := e.db()
+	if err != nil {
+		return err
+	}
+	count, err := services.NewSuggestionService(db).Reseed(ctx, string(seed))
+	if err != nil {
+		return err
+	}
+	e.printf("Reseeded %d suggestions\n", count)
+	return nil
+}
+
+func noArgs(name string, args []string) error {
+	fs := newFlagSet(name)
+	if err := fs.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if fs.NArg() != 0 {
+		return usagef("%s takes no arguments", name)
+	}
+	return nil
+}
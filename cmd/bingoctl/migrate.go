@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/database"
+)
+
+func (e *env) migrate(command string, args []string) error {
+	fs := newFlagSet("migrate " + command)
+	dir := fs.String("migrations", "migrations", "migrations directory")
+	steps := fs.Int("steps", 1, "number of migrations to roll back")
+	all := fs.Bool("all", false, "roll back every migration")
+	if err := fs.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+
+	forceVersion := 0
+	switch command {
+	case "down":
+		if !*all && *steps < 1 {
+			return usagef("-steps must be at least 1")
+		}
+	case "force":
+		if fs.NArg() != 1 {
+			return usagef("migrate force takes one VERSION")
+		}
+		version, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || version < -1 {
+			return usagef("invalid version %q", fs.Arg(0))
+		}
+		forceVersion = version
+	}
+
+	cfg, err := e.config()
+	if err != nil {
+		return err
+	}
+	migrator, err := database.NewMigrator(cfg.Database.DSN(), *dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = migrator.Close() }()
+
+	switch command {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		if *all {
+			err = migrator.Down()
+		} else {
+			err = migrator.Steps(-*steps)
+		}
+	case "force":
+		err = migrator.Force(forceVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	return printVersion(e.out, migrator)
+}
+
+func printVersion(out io.Writer, migrator *database.Migrator) error {
+	version, dirty, err := migrator.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Fprintln(out, "No migrations applied")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading migration version: %w", err)
+	}
+	if dirty {
+		fmt.Fprintf(out, "Version %d (dirty: fix the schema by hand, then run migrate force)\n", version)
+		return nil
+	}
+	fmt.Fprintf(out, "Version %d\n", version)
+	return nil
+}
+
+// newFlagSet returns a flag set that reports parse errors to the caller
+// instead of exiting, so run can print the shared usage text.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
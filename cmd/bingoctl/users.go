@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+const minPasswordLength = 8
+
+func (e *env) userService() (*services.UserService, error) {
+	db, err := e.db()
+	if err != nil {
+		return nil, err
+	}
+	return services.NewUserService(db), nil
+}
+
+// userArg parses the single USER argument shared by the user subcommands.
+func userArg(name string, args []string) (string, error) {
+	fs := newFlagSet(name)
+	if err := fs.Parse(args); err != nil {
+		return "", usagef("%v", err)
+	}
+	if fs.NArg() != 1 {
+		return "", usagef("%s takes one USER", name)
+	}
+	return fs.Arg(0), nil
+}
+
+func (e *env) createUser(ctx context.Context, args []string) error {
+	fs := newFlagSet("user create")
+	email := fs.String("email", "", "email address")
+	username := fs.String("username", "", "display name")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin")
+	verified := fs.Bool("verified", false, "mark the email address verified")
+	admin := fs.Bool("admin", false, "grant admin access")
+	if err := fs.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+
+	*email = strings.TrimSpace(strings.ToLower(*email))
+	if _, err := mail.ParseAddress(*email); err != nil {
+		return usagef("invalid email address %q", *email)
+	}
+	*username = strings.TrimSpace(*username)
+	if len(*username) < 2 || len(*username) > 100 {
+		return usagef("username must be between 2 and 100 characters")
+	}
+
+	password, generated, err := e.readPassword(*passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	auth := services.NewAuthService(nil, nil)
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	users, err := e.userService()
+	if err != nil {
+		return err
+	}
+	user, err := users.Create(ctx, models.CreateUserParams{
+		Email:        *email,
+		PasswordHash: passwordHash,
+		Username:     *username,
+	})
+	if err != nil {
+		return err
+	}
+	if *verified {
+		if err := users.MarkEmailVerified(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+	if *admin {
+		if err := users.SetAdmin(ctx, user.ID, true); err != nil {
+			return err
+		}
+	}
+
+	e.printf("Created user %s (%s)\n", user.Email, user.ID)
+	if generated {
+		e.printf("Password: %s\n", password)
+	}
+	return nil
+}
+
+// readPassword reads a password from stdin, or generates one when the
+// caller did not ask for stdin. Passwords are never taken as flags so they
+// stay out of shell history and process listings.
+func (e *env) readPassword(fromStdin bool) (password string, generated bool, err error) {
+	if !fromStdin {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return "", false, fmt.Errorf("generating password: %w", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(buf), true, nil
+	}
+
+	line, err := bufio.NewReader(e.stdin).ReadString('\n')
+	password = strings.TrimRight(line, "\r\n")
+	if password == "" && err != nil {
+		return "", false, fmt.Errorf("reading password from stdin: %w", err)
+	}
+	if len(password) < minPasswordLength {
+		return "", false, fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return password, false, nil
+}
+
+func (e *env) verifyEmail(ctx context.Context, args []string) error {
+	ref, err := userArg("user verify-email", args)
+	if err != nil {
+		return err
+	}
+	users, err := e.userService()
+	if err != nil {
+		return err
+	}
+	user, err := findUser(ctx, users, ref)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		e.printf("%s is already verified\n", user.Email)
+		return nil
+	}
+	if err := users.MarkEmailVerified(ctx, user.ID); err != nil {
+		return err
+	}
+	e.printf("Verified %s\n", user.Email)
+	return nil
+}
+
+func (e *env) setAdmin(ctx context.Context, args []string, isAdmin bool) error {
+	name := "user promote"
+	if !isAdmin {
+		name = "user demote"
+	}
+	ref, err := userArg(name, args)
+	if err != nil {
+		return err
+	}
+	users, err := e.userService()
+	if err != nil {
+		return err
+	}
+	user, err := findUser(ctx, users, ref)
+	if err != nil {
+		return err
+	}
+	if err := users.SetAdmin(ctx, user.ID, isAdmin); err != nil {
+		return err
+	}
+	if isAdmin {
+		e.printf("%s is now an admin. They need to log in again for it to take effect.\n", user.Email)
+	} else {
+		e.printf("%s is no longer an admin\n", user.Email)
+	}
+	return nil
+}
+
+// revokeAccess logs the user out of every session and deletes their API
+// tokens. Their password still works; pair it with a reset if the account
+// is compromised.
+func (e *env) revokeAccess(ctx context.Context, args []string) error {
+	ref, err := userArg("user revoke", args)
+	if err != nil {
+		return err
+	}
+	db, err := e.db()
+	if err != nil {
+		return err
+	}
+	redis, err := e.redisClient()
+	if err != nil {
+		return err
+	}
+	user, err := findUser(ctx, services.NewUserService(db), ref)
+	if err != nil {
+		return err
+	}
+
+	if err := services.NewAuthService(db, redis).DeleteAllUserSessions(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := services.NewApiTokenService(db).DeleteAll(ctx, user.ID); err != nil {
+		return err
+	}
+	e.printf("Revoked all sessions and API tokens for %s\n", user.Email)
+	return nil
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -21,25 +23,43 @@ import (
 )
 
 func main() {
-	if err := run(); err != nil {
+	skipMigrations := flag.Bool("skip-migrations", false, "do not apply database migrations at startup; run bingoctl migrate up instead")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file; environment variables override it")
+	printConfig := flag.Bool("print-config", false, "print the resolved configuration with secrets redacted, then exit")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.LoadFile(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *printConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := run(cfg, *skipMigrations); err != nil {
 		logging.Error("Application error", map[string]interface{}{"error": err.Error()})
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func run(cfg *config.Config, skipMigrations bool) error {
 	// Initialize logger
 	logger := logging.New()
 
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
-	}
+	// Everything logs through one handler: package-level logging calls via
+	// Default, and the standard log package via slog's default logger.
+	logger.SetFormat(logging.Format(cfg.Server.LogFormat))
+	logging.Default = logger
+	slog.SetDefault(logger.Slog())
 
 	if cfg.Server.Debug {
 		logger.SetLevel(logging.LevelDebug)
-		logging.SetDefaultLevel(logging.LevelDebug)
 		logger.Debug("Debug logging enabled", map[string]interface{}{
 			"max_chars": cfg.Server.DebugMaxChars,
 			"env":       cfg.Server.Environment,
@@ -53,25 +73,39 @@ func run() error {
 		"host": cfg.Database.Host,
 		"port": cfg.Database.Port,
 	})
-	db, err := database.NewPostgresDB(cfg.Database.DSN())
+	db, err := database.NewPostgresDB(cfg.Database.DSN(), poolOptions(cfg.Database, cfg.Database.StatementTimeout))
 	if err != nil {
 		return fmt.Errorf("connecting to postgres: %w", err)
 	}
 	defer db.Close()
 	logger.Info("Connected to PostgreSQL")
 
-	// Run migrations
-	logger.Info("Running database migrations...")
-	migrator, err := database.NewMigrator(cfg.Database.DSN(), "migrations")
-	if err != nil {
-		return fmt.Errorf("creating migrator: %w", err)
+	var replicaDB *database.PostgresDB
+	if cfg.Database.ReplicaURL != "" {
+		replicaDB, err = database.NewPostgresDB(cfg.Database.ReplicaURL, poolOptions(cfg.Database, cfg.Database.ReplicaStatementTimeout))
+		if err != nil {
+			return fmt.Errorf("connecting to postgres replica: %w", err)
+		}
+		defer replicaDB.Close()
+		logger.Info("Connected to PostgreSQL read replica")
 	}
-	if err := migrator.Up(); err != nil {
+
+	// Run migrations
+	if skipMigrations {
+		logger.Info("Skipping database migrations")
+	} else {
+		logger.Info("Running database migrations...")
+		migrator, err := database.NewMigrator(cfg.Database.DSN(), "migrations")
+		if err != nil {
+			return fmt.Errorf("creating migrator: %w", err)
+		}
+		if err := migrator.Up(); err != nil {
+			_ = migrator.Close()
+			return fmt.Errorf("running migrations: %w", err)
+		}
 		_ = migrator.Close()
-		return fmt.Errorf("running migrations: %w", err)
+		logger.Info("Migrations completed")
 	}
-	_ = migrator.Close()
-	logger.Info("Migrations completed")
 
 	// Connect to Redis
 	logger.Info("Connecting to Redis", map[string]interface{}{
@@ -85,87 +119,219 @@ func run() error {
 	logger.Info("Connected to Redis")
 
 	// Initialize services
-	dbAdapter := services.NewPoolAdapter(db.Pool)
+	var dbAdapter services.DB = services.NewPoolAdapter(db.Pool)
+	if replicaDB != nil {
+		dbAdapter = services.NewReplicaDB(dbAdapter, services.NewPoolAdapter(replicaDB.Pool))
+	}
 	redisAdapter := services.NewRedisAdapter(redisDB.Client)
 
 	userService := services.NewUserService(dbAdapter)
 	authService := services.NewAuthService(dbAdapter, redisAdapter)
+	authService.SetSessionDuration(cfg.Server.SessionDuration)
 	emailService := services.NewEmailService(&cfg.Email, dbAdapter)
 	cardService := services.NewCardService(dbAdapter)
 	suggestionService := services.NewSuggestionService(dbAdapter)
 	friendService := services.NewFriendService(dbAdapter)
 	reactionService := services.NewReactionService(dbAdapter, friendService)
+	activityService := services.NewActivityService(dbAdapter)
+	commentService := services.NewCommentService(dbAdapter, friendService)
+	friendGroupService := services.NewFriendGroupService(dbAdapter, friendService)
+	profileService := services.NewProfileService(dbAdapter, cardService)
+	leaderboardService := services.NewLeaderboardService(dbAdapter, cardService)
+	challengeService := services.NewChallengeService(dbAdapter, friendService)
+	templateService := services.NewTemplateService(dbAdapter, cardService)
+	organizationService := services.NewOrganizationService(dbAdapter, cardService)
+	cardService.SetActivityRecorder(activityService)
+	reactionService.SetActivityRecorder(activityService)
 	apiTokenService := services.NewApiTokenService(dbAdapter)
 	blockService := services.NewBlockService(dbAdapter)
 	inviteService := services.NewFriendInviteService(dbAdapter)
 	notificationService := services.NewNotificationService(dbAdapter, emailService, cfg.Email.BaseURL)
 	aiService := ai.NewService(cfg, dbAdapter)
+	adminService := services.NewAdminService(dbAdapter, authService, cardService)
+	moderationService := services.NewModerationService(dbAdapter, blockService, authService)
+	accountEventService := services.NewAccountEventService(dbAdapter, emailService)
+	calendarService := services.NewCalendarService(dbAdapter, cfg.Email.BaseURL)
+
+	var throttleService *services.ThrottleService
+	if cfg.Throttle.Enabled {
+		throttleRules, err := services.ThrottleRulesFromConfig(cfg.Throttle.Rules)
+		if err != nil {
+			return fmt.Errorf("loading throttle rules: %w", err)
+		}
+		throttleService = services.NewThrottleService(services.NewRedisThrottleStore(redisDB.Client), throttleRules, emailService)
+	}
 
 	cardService.SetNotificationService(notificationService)
 	friendService.SetNotificationService(notificationService)
 	inviteService.SetNotificationService(notificationService)
+	commentService.SetNotificationService(notificationService)
+	challengeService.SetNotificationService(notificationService)
+	templateService.SetNotificationService(notificationService)
+	apiTokenService.SetAccountEvents(accountEventService)
+	blockService.SetAccountEvents(accountEventService)
+	calendarService.SetAccountEvents(accountEventService)
 
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(db, redisDB)
 	authHandler := handlers.NewAuthHandler(userService, authService, emailService, cfg.Server.Secure)
+	authHandler.SetSessionDuration(cfg.Server.SessionDuration)
+	authHandler.SetAccountEventService(accountEventService)
+	if throttleService != nil {
+		authHandler.SetThrottle(throttleService)
+	}
 	cardHandler := handlers.NewCardHandler(cardService)
 	suggestionHandler := handlers.NewSuggestionHandler(suggestionService)
 	friendHandler := handlers.NewFriendHandler(friendService, cardService)
+	friendHandler.SetFriendGroupService(friendGroupService)
+	friendGroupHandler := handlers.NewFriendGroupHandler(friendGroupService)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService)
+	challengeHandler := handlers.NewChallengeHandler(challengeService)
+	templateHandler := handlers.NewTemplateHandler(templateService, cardService)
 	reactionHandler := handlers.NewReactionHandler(reactionService)
+	activityHandler := handlers.NewActivityHandler(activityService)
+	commentHandler := handlers.NewCommentHandler(commentService)
 	supportHandler := handlers.NewSupportHandler(emailService, redisDB.Client)
+	supportHandler.SetRateLimit(cfg.RateLimit.SupportPerIP, cfg.RateLimit.SupportWindow)
 	apiTokenHandler := handlers.NewApiTokenHandler(apiTokenService)
 	blockHandler := handlers.NewBlockHandler(blockService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
 	inviteHandler := handlers.NewFriendInviteHandler(inviteService)
+	if throttleService != nil {
+		inviteHandler.SetThrottle(throttleService)
+	}
+	organizationHandler := handlers.NewOrganizationHandler(organizationService)
+	if throttleService != nil {
+		organizationHandler.SetThrottle(throttleService)
+	}
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
 	aiHandler := handlers.NewAIHandler(aiService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
 	pageHandler, err := handlers.NewPageHandler("web/templates")
 	if err != nil {
 		return fmt.Errorf("loading templates: %w", err)
 	}
+	pageHandler.SetProfileService(profileService, cfg.Email.BaseURL)
+
+	// The outbox worker only starts when enabled, but old rows are purged
+	// either way.
+	emailOutbox := services.NewEmailOutbox(dbAdapter, emailService.Provider(), services.EmailOutboxConfig{
+		Provider:      cfg.Email.Provider,
+		MaxAttempts:   cfg.Email.OutboxMaxAttempts,
+		RatePerSecond: cfg.Email.OutboxRatePerSecond,
+	})
+	// Every replica runs the outbox worker, so the provider's send rate is
+	// shared through Redis rather than applied per replica.
+	emailOutbox.SetSendStore(services.NewRedisEmailSendStore(redisDB.Client))
 
-	if err := notificationService.CleanupOld(context.Background()); err != nil {
-		logger.Warn("Notification cleanup failed", map[string]interface{}{"error": err.Error()})
+	// Background jobs. Every replica runs the scheduler; each tick runs on
+	// only one of them. Times are UTC.
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+	scheduler := services.NewScheduler(dbAdapter, instance)
+	for _, job := range []services.ScheduledJob{
+		{Name: "notification_cleanup", Schedule: "0 3 * * *", Run: notificationService.CleanupOld},
+		{Name: "challenge_announcements", Schedule: "*/5 * * * *", Run: discardCount(challengeService.AnnounceFinished)},
+		{Name: "card_trash_purge", Schedule: "0 * * * *", Run: discardCount(cardService.PurgeTrash)},
+		{Name: "card_revision_prune", Schedule: "5 * * * *", Run: discardCount(cardService.PruneRevisions)},
+		{Name: "expired_session_purge", Schedule: "10 * * * *", Run: discardCount(authService.PurgeExpiredSessions)},
+		{Name: "expired_api_token_purge", Schedule: "20 3 * * *", Run: discardCount(apiTokenService.PurgeExpired)},
+		{Name: "friend_invite_purge", Schedule: "30 3 * * *", Run: discardCount(inviteService.PurgeInactive)},
+		{Name: "org_invite_purge", Schedule: "35 3 * * *", Run: discardCount(organizationService.PurgeInactive)},
+		{Name: "email_token_purge", Schedule: "40 3 * * *", Run: discardCount(emailService.PurgeExpiredTokens)},
+		{Name: "email_outbox_purge", Schedule: "45 3 * * *", Run: discardCount(emailOutbox.Purge)},
+	} {
+		if err := scheduler.Register(job); err != nil {
+			return fmt.Errorf("registering job: %w", err)
+		}
 	}
+	adminHandler.SetJobStatusProvider(scheduler)
+
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
 	notificationService.SetAsyncContext(cleanupCtx)
-	go func() {
-		ticker := time.NewTicker(24 * time.Hour)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-cleanupCtx.Done():
-				return
-			case <-ticker.C:
-				if err := notificationService.CleanupOld(context.Background()); err != nil {
-					logger.Warn("Notification cleanup failed", map[string]interface{}{"error": err.Error()})
-				}
-			}
-		}
-	}()
+	if cfg.Email.OutboxEnabled {
+		emailService.SetOutbox(emailOutbox)
+		go emailOutbox.Run(cleanupCtx)
+		logger.Info("Email outbox worker started", map[string]interface{}{"provider": cfg.Email.Provider})
+	}
+	go scheduler.Run(cleanupCtx)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authService, userService, apiTokenService)
 	csrfMiddleware := middleware.NewCSRFMiddleware(cfg.Server.Secure)
 	securityHeaders := middleware.NewSecurityHeaders(cfg.Server.Secure)
+	if err := securityHeaders.SetCSP(cfg.CSP.Directives, cfg.CSP.ReportOnly); err != nil {
+		cleanupCancel()
+		return fmt.Errorf("configuring CSP: %w", err)
+	}
+	csrfMiddleware.SetExemptPaths(middleware.CSPReportPath)
+	cspReportHandler := handlers.NewCSPReportHandler()
 	cacheControl := middleware.NewCacheControl()
 	compress := middleware.NewCompress()
-	requestLogger := middleware.NewRequestLogger(logger)
+	requestLogger := middleware.NewRequestLogger(logger).SetHealthCheckSampling(cfg.Server.HealthLogSampleRate)
+	requestMeta := middleware.NewRequestMeta(middleware.NewClientIPResolver(cfg.Server.TrustedProxies, cfg.Server.TrustCFConnectingIP))
 
 	// AI Rate Limit configuration
 	aiRateLimit := resolveAIRateLimit(cfg, logger, os.LookupEnv)
 
-	aiRateLimiter := middleware.NewRateLimiter(redisDB.Client, aiRateLimit, 1*time.Hour, "ratelimit:ai:", func(r *http.Request) string {
-		user := handlers.GetUserFromContext(r.Context())
-		if user != nil {
-			return user.ID.String()
-		}
-		return ""
-	}, false)
+	var rateLimitStore middleware.RateLimitStore = middleware.NewRedisRateLimitStore(redisDB.Client)
+	if cfg.RateLimit.Backend == "memory" {
+		rateLimitStore = middleware.NewMemoryRateLimitStore()
+	}
+
+	aiRateLimiter := middleware.NewPolicyRateLimiter(rateLimitStore, "ratelimit:ai:", false, middleware.RateLimitPolicy{
+		Name:      "user",
+		Algorithm: middleware.SlidingWindow,
+		Limit:     aiRateLimit,
+		Window:    time.Hour,
+		Key:       middleware.RateLimitByUser,
+	})
+
+	// Comment writes get a tighter per-user limit to keep threads from being
+	// flooded.
+	commentRateLimiter := middleware.NewPolicyRateLimiter(rateLimitStore, "ratelimit:comments:", true, middleware.RateLimitPolicy{
+		Name:      "user",
+		Algorithm: middleware.SlidingWindow,
+		Limit:     30,
+		Window:    10 * time.Minute,
+		Key:       middleware.RateLimitByUser,
+	})
+
+	// Contact discovery is capped per user so hashes can't be used to probe
+	// the user base for addresses.
+	discoveryRateLimiter := middleware.NewPolicyRateLimiter(rateLimitStore, "ratelimit:discovery:", true, middleware.RateLimitPolicy{
+		Name:      "user",
+		Algorithm: middleware.SlidingWindow,
+		Limit:     10,
+		Window:    time.Hour,
+		Key:       middleware.RateLimitByUser,
+	})
+
+	// CSP reports are unauthenticated, so they get their own per-IP limit.
+	cspReportRateLimiter := middleware.NewPolicyRateLimiter(rateLimitStore, "ratelimit:csp:", true, middleware.RateLimitPolicy{
+		Name:      "ip",
+		Algorithm: middleware.SlidingWindow,
+		Limit:     60,
+		Window:    time.Minute,
+		Key:       middleware.RateLimitByIP,
+	})
+
+	// API-wide limits. Tokens get their own bucket so scripts can't starve
+	// the owner's browser session, and vice versa.
+	apiRateLimiter := middleware.NewPolicyRateLimiter(rateLimitStore, "ratelimit:api:", true, apiRateLimitPolicies(cfg.RateLimit)...)
+	apiRateLimiter.SetPathPrefix("/api/")
 
 	// Helper middlewares for API token scope enforcement
 	requireRead := authMiddleware.RequireScope(models.ScopeRead)
 	requireWrite := authMiddleware.RequireScope(models.ScopeWrite)
 	requireSession := authMiddleware.RequireSession
+	requireAdmin := func(h http.HandlerFunc) http.Handler {
+		return requireSession(authMiddleware.RequireAdmin(h))
+	}
 
 	// Set up router
 	mux := http.NewServeMux()
@@ -191,6 +357,9 @@ func run() error {
 	mux.Handle("POST /api/auth/forgot-password", requireSession(http.HandlerFunc(authHandler.ForgotPassword)))
 	mux.Handle("POST /api/auth/reset-password", requireSession(http.HandlerFunc(authHandler.ResetPassword)))
 	mux.Handle("PUT /api/auth/searchable", requireSession(http.HandlerFunc(authHandler.UpdateSearchable)))
+	mux.Handle("PUT /api/auth/public-profile", requireSession(http.HandlerFunc(authHandler.UpdatePublicProfile)))
+	mux.Handle("PUT /api/auth/leaderboard-opt-out", requireSession(http.HandlerFunc(authHandler.UpdateLeaderboardOptOut)))
+	mux.Handle("GET /api/auth/activity", requireSession(http.HandlerFunc(authHandler.Activity)))
 
 	// API Token endpoints
 	mux.Handle("GET /api/tokens", requireSession(http.HandlerFunc(apiTokenHandler.List)))
@@ -198,6 +367,11 @@ func run() error {
 	mux.Handle("DELETE /api/tokens/{id}", requireSession(http.HandlerFunc(apiTokenHandler.Delete)))
 	mux.Handle("DELETE /api/tokens", requireSession(http.HandlerFunc(apiTokenHandler.DeleteAll)))
 
+	// Calendar feed endpoints
+	mux.Handle("GET /api/calendar/feed", requireSession(http.HandlerFunc(calendarHandler.GetFeed)))
+	mux.Handle("POST /api/calendar/feed", requireSession(http.HandlerFunc(calendarHandler.CreateFeed)))
+	mux.Handle("DELETE /api/calendar/feed", requireSession(http.HandlerFunc(calendarHandler.RevokeFeed)))
+
 	// Card endpoints
 	mux.Handle("POST /api/cards", requireWrite(http.HandlerFunc(cardHandler.Create)))
 	mux.Handle("GET /api/cards", requireRead(http.HandlerFunc(cardHandler.List)))
@@ -208,22 +382,47 @@ func run() error {
 	mux.Handle("PUT /api/cards/visibility/bulk", requireSession(http.HandlerFunc(cardHandler.BulkUpdateVisibility)))
 	mux.Handle("DELETE /api/cards/bulk", requireSession(http.HandlerFunc(cardHandler.BulkDelete)))
 	mux.Handle("PUT /api/cards/archive/bulk", requireSession(http.HandlerFunc(cardHandler.BulkUpdateArchive)))
+	mux.Handle("GET /api/cards/trash", requireSession(http.HandlerFunc(cardHandler.Trash)))
+	mux.Handle("POST /api/cards/trash/{id}/restore", requireSession(http.HandlerFunc(cardHandler.RestoreFromTrash)))
 	mux.Handle("GET /api/cards/{id}", requireRead(http.HandlerFunc(cardHandler.Get)))
-	mux.Handle("DELETE /api/cards/{id}", requireSession(http.HandlerFunc(cardHandler.Delete)))
+	mux.Handle("DELETE /api/cards/{id}", requireSession(cardHandler.Preconditions(cardHandler.Delete)))
 	mux.Handle("GET /api/cards/{id}/stats", requireRead(http.HandlerFunc(cardHandler.Stats)))
-	mux.Handle("PUT /api/cards/{id}/meta", requireSession(http.HandlerFunc(cardHandler.UpdateMeta)))
-	mux.Handle("PUT /api/cards/{id}/visibility", requireSession(http.HandlerFunc(cardHandler.UpdateVisibility)))
-	mux.Handle("PUT /api/cards/{id}/config", requireWrite(http.HandlerFunc(cardHandler.UpdateConfig)))
+	mux.Handle("PUT /api/cards/{id}/meta", requireSession(cardHandler.Preconditions(cardHandler.UpdateMeta)))
+	mux.Handle("PUT /api/cards/{id}/visibility", requireSession(cardHandler.Preconditions(cardHandler.UpdateVisibility)))
+	mux.Handle("GET /api/cards/{id}/sharing", requireSession(http.HandlerFunc(cardHandler.GetSharing)))
+	mux.Handle("PUT /api/cards/{id}/config", requireWrite(cardHandler.Preconditions(cardHandler.UpdateConfig)))
 	mux.Handle("POST /api/cards/{id}/clone", requireWrite(http.HandlerFunc(cardHandler.Clone)))
-	mux.Handle("POST /api/cards/{id}/items", requireWrite(http.HandlerFunc(cardHandler.AddItem)))
-	mux.Handle("PUT /api/cards/{id}/items/{pos}", requireWrite(http.HandlerFunc(cardHandler.UpdateItem)))
-	mux.Handle("DELETE /api/cards/{id}/items/{pos}", requireWrite(http.HandlerFunc(cardHandler.RemoveItem)))
-	mux.Handle("POST /api/cards/{id}/shuffle", requireWrite(http.HandlerFunc(cardHandler.Shuffle)))
-	mux.Handle("POST /api/cards/{id}/swap", requireWrite(http.HandlerFunc(cardHandler.SwapItems)))
-	mux.Handle("POST /api/cards/{id}/finalize", requireWrite(http.HandlerFunc(cardHandler.Finalize)))
-	mux.Handle("PUT /api/cards/{id}/items/{pos}/complete", requireWrite(http.HandlerFunc(cardHandler.CompleteItem)))
-	mux.Handle("PUT /api/cards/{id}/items/{pos}/uncomplete", requireWrite(http.HandlerFunc(cardHandler.UncompleteItem)))
-	mux.Handle("PUT /api/cards/{id}/items/{pos}/notes", requireWrite(http.HandlerFunc(cardHandler.UpdateNotes)))
+	mux.Handle("POST /api/cards/{id}/items", requireWrite(cardHandler.Preconditions(cardHandler.AddItem)))
+	mux.Handle("PUT /api/cards/{id}/items/{pos}", requireWrite(cardHandler.Preconditions(cardHandler.UpdateItem)))
+	mux.Handle("DELETE /api/cards/{id}/items/{pos}", requireWrite(cardHandler.Preconditions(cardHandler.RemoveItem)))
+	mux.Handle("POST /api/cards/{id}/shuffle", requireWrite(cardHandler.Preconditions(cardHandler.Shuffle)))
+	mux.Handle("POST /api/cards/{id}/swap", requireWrite(cardHandler.Preconditions(cardHandler.SwapItems)))
+	mux.Handle("GET /api/cards/{id}/history", requireRead(http.HandlerFunc(cardHandler.History)))
+	mux.Handle("POST /api/cards/{id}/undo", requireWrite(cardHandler.Preconditions(cardHandler.Undo)))
+	mux.Handle("POST /api/cards/{id}/history/{revisionId}/restore", requireWrite(cardHandler.Preconditions(cardHandler.RestoreRevision)))
+	mux.Handle("POST /api/cards/{id}/finalize", requireWrite(cardHandler.Preconditions(cardHandler.Finalize)))
+	mux.Handle("PUT /api/cards/{id}/items/{pos}/complete", requireWrite(cardHandler.Preconditions(cardHandler.CompleteItem)))
+	mux.Handle("PUT /api/cards/{id}/items/{pos}/uncomplete", requireWrite(cardHandler.Preconditions(cardHandler.UncompleteItem)))
+	mux.Handle("PUT /api/cards/{id}/items/{pos}/notes", requireWrite(cardHandler.Preconditions(cardHandler.UpdateNotes)))
+	mux.Handle("PUT /api/cards/{id}/items/{pos}/due", requireWrite(cardHandler.Preconditions(cardHandler.UpdateSchedule)))
+
+	// Cards shared by link (no session needed)
+	mux.Handle("GET /api/shared/{token}", http.HandlerFunc(cardHandler.GetShared))
+
+	// Calendar feed, fetched by calendar apps with the secret token (no session needed)
+	mux.Handle("GET /api/calendar/feed.ics", http.HandlerFunc(calendarHandler.Feed))
+
+	// Public profiles (no session needed)
+	mux.Handle("GET /api/profiles/{username}", http.HandlerFunc(profileHandler.Get))
+
+	// Template library (browsing needs no session)
+	mux.Handle("GET /api/templates", http.HandlerFunc(templateHandler.List))
+	mux.Handle("GET /api/templates/mine", requireSession(http.HandlerFunc(templateHandler.ListMine)))
+	mux.Handle("POST /api/templates", requireSession(http.HandlerFunc(templateHandler.Publish)))
+	mux.Handle("GET /api/templates/{id}", http.HandlerFunc(templateHandler.Get))
+	mux.Handle("PUT /api/templates/{id}", requireSession(http.HandlerFunc(templateHandler.Update)))
+	mux.Handle("DELETE /api/templates/{id}", requireSession(http.HandlerFunc(templateHandler.Delete)))
+	mux.Handle("POST /api/templates/{id}/use", requireSession(http.HandlerFunc(templateHandler.Use)))
 
 	// Suggestion endpoints
 	mux.Handle("GET /api/suggestions", http.HandlerFunc(suggestionHandler.GetAll))
@@ -232,6 +431,10 @@ func run() error {
 	// Friend endpoints
 	mux.Handle("GET /api/friends", requireSession(http.HandlerFunc(friendHandler.List)))
 	mux.Handle("GET /api/friends/search", requireSession(http.HandlerFunc(friendHandler.Search)))
+	mux.Handle("GET /api/friends/suggestions", requireSession(http.HandlerFunc(friendHandler.Suggestions)))
+	mux.Handle("POST /api/friends/discover", requireSession(discoveryRateLimiter.Middleware(http.HandlerFunc(friendHandler.Discover))))
+	mux.Handle("GET /api/friends/activity", requireSession(http.HandlerFunc(activityHandler.Feed)))
+	mux.Handle("GET /api/friends/leaderboard", requireSession(http.HandlerFunc(leaderboardHandler.Get)))
 	mux.Handle("POST /api/friends/requests", requireSession(http.HandlerFunc(friendHandler.SendRequest)))
 	mux.Handle("PUT /api/friends/requests/{id}/accept", requireSession(http.HandlerFunc(friendHandler.AcceptRequest)))
 	mux.Handle("PUT /api/friends/requests/{id}/reject", requireSession(http.HandlerFunc(friendHandler.RejectRequest)))
@@ -239,6 +442,37 @@ func run() error {
 	mux.Handle("DELETE /api/friends/requests/{id}/cancel", requireSession(http.HandlerFunc(friendHandler.CancelRequest)))
 	mux.Handle("GET /api/friends/{id}/card", requireSession(http.HandlerFunc(friendHandler.GetFriendCard)))
 	mux.Handle("GET /api/friends/{id}/cards", requireSession(http.HandlerFunc(friendHandler.GetFriendCards)))
+	mux.Handle("GET /api/friend-groups", requireSession(http.HandlerFunc(friendGroupHandler.List)))
+	mux.Handle("POST /api/friend-groups", requireSession(http.HandlerFunc(friendGroupHandler.Create)))
+	mux.Handle("PUT /api/friend-groups/{id}", requireSession(http.HandlerFunc(friendGroupHandler.Rename)))
+	mux.Handle("DELETE /api/friend-groups/{id}", requireSession(http.HandlerFunc(friendGroupHandler.Delete)))
+	mux.Handle("POST /api/friend-groups/{id}/members", requireSession(http.HandlerFunc(friendGroupHandler.AddMember)))
+	mux.Handle("DELETE /api/friend-groups/{id}/members/{userId}", requireSession(http.HandlerFunc(friendGroupHandler.RemoveMember)))
+	mux.Handle("GET /api/orgs", requireSession(http.HandlerFunc(organizationHandler.List)))
+	mux.Handle("POST /api/orgs", requireSession(http.HandlerFunc(organizationHandler.Create)))
+	mux.Handle("GET /api/orgs/joinable", requireSession(http.HandlerFunc(organizationHandler.Joinable)))
+	mux.Handle("POST /api/orgs/invites/accept", requireSession(http.HandlerFunc(organizationHandler.AcceptInvite)))
+	mux.Handle("GET /api/orgs/{id}", requireSession(http.HandlerFunc(organizationHandler.Get)))
+	mux.Handle("PUT /api/orgs/{id}", requireSession(http.HandlerFunc(organizationHandler.Rename)))
+	mux.Handle("DELETE /api/orgs/{id}", requireSession(http.HandlerFunc(organizationHandler.Delete)))
+	mux.Handle("POST /api/orgs/{id}/join", requireSession(http.HandlerFunc(organizationHandler.JoinByDomain)))
+	mux.Handle("GET /api/orgs/{id}/members", requireSession(http.HandlerFunc(organizationHandler.ListMembers)))
+	mux.Handle("PUT /api/orgs/{id}/members/{userId}", requireSession(http.HandlerFunc(organizationHandler.SetMemberRole)))
+	mux.Handle("DELETE /api/orgs/{id}/members/{userId}", requireSession(http.HandlerFunc(organizationHandler.RemoveMember)))
+	mux.Handle("GET /api/orgs/{id}/members/{userId}/cards", requireSession(http.HandlerFunc(organizationHandler.MemberCards)))
+	mux.Handle("GET /api/orgs/{id}/invites", requireSession(http.HandlerFunc(organizationHandler.ListInvites)))
+	mux.Handle("POST /api/orgs/{id}/invites", requireSession(http.HandlerFunc(organizationHandler.CreateInvite)))
+	mux.Handle("DELETE /api/orgs/{id}/invites/{inviteId}", requireSession(http.HandlerFunc(organizationHandler.RevokeInvite)))
+	mux.Handle("POST /api/orgs/{id}/domains", requireSession(http.HandlerFunc(organizationHandler.AddDomain)))
+	mux.Handle("DELETE /api/orgs/{id}/domains/{domain}", requireSession(http.HandlerFunc(organizationHandler.RemoveDomain)))
+	mux.Handle("GET /api/orgs/{id}/templates", requireSession(http.HandlerFunc(organizationHandler.Templates)))
+	mux.Handle("GET /api/orgs/{id}/stats", requireSession(http.HandlerFunc(organizationHandler.Stats)))
+	mux.Handle("GET /api/challenges", requireSession(http.HandlerFunc(challengeHandler.List)))
+	mux.Handle("POST /api/challenges", requireSession(http.HandlerFunc(challengeHandler.Create)))
+	mux.Handle("GET /api/challenges/{id}", requireSession(http.HandlerFunc(challengeHandler.Get)))
+	mux.Handle("DELETE /api/challenges/{id}", requireSession(http.HandlerFunc(challengeHandler.Delete)))
+	mux.Handle("POST /api/challenges/{id}/join", requireSession(http.HandlerFunc(challengeHandler.Join)))
+	mux.Handle("POST /api/challenges/{id}/leave", requireSession(http.HandlerFunc(challengeHandler.Leave)))
 	mux.Handle("POST /api/blocks", requireSession(http.HandlerFunc(blockHandler.Block)))
 	mux.Handle("DELETE /api/blocks/{id}", requireSession(http.HandlerFunc(blockHandler.Unblock)))
 	mux.Handle("GET /api/blocks", requireSession(http.HandlerFunc(blockHandler.List)))
@@ -261,6 +495,15 @@ func run() error {
 	mux.Handle("GET /api/items/{id}/reactions", requireSession(http.HandlerFunc(reactionHandler.GetReactions)))
 	mux.Handle("GET /api/reactions/emojis", requireSession(http.HandlerFunc(reactionHandler.GetAllowedEmojis)))
 
+	// Comment endpoints
+	mux.Handle("GET /api/items/{id}/comments", requireSession(http.HandlerFunc(commentHandler.List)))
+	mux.Handle("POST /api/items/{id}/comments", requireSession(commentRateLimiter.Middleware(http.HandlerFunc(commentHandler.Create))))
+	mux.Handle("PUT /api/comments/{id}", requireSession(commentRateLimiter.Middleware(http.HandlerFunc(commentHandler.Update))))
+	mux.Handle("DELETE /api/comments/{id}", requireSession(http.HandlerFunc(commentHandler.Delete)))
+
+	// Report endpoint
+	mux.Handle("POST /api/reports", requireSession(http.HandlerFunc(moderationHandler.Report)))
+
 	// Support endpoint
 	mux.Handle("POST /api/support", requireSession(http.HandlerFunc(supportHandler.Submit)))
 
@@ -268,6 +511,30 @@ func run() error {
 	mux.Handle("POST /api/ai/generate", requireSession(aiRateLimiter.Middleware(http.HandlerFunc(aiHandler.Generate))))
 	mux.Handle("POST /api/ai/guide", requireSession(aiRateLimiter.Middleware(http.HandlerFunc(aiHandler.Guide))))
 
+	// Admin endpoints
+	mux.Handle("GET /api/admin/users", requireAdmin(adminHandler.SearchUsers))
+	mux.Handle("GET /api/admin/users/{id}", requireAdmin(adminHandler.GetUser))
+	mux.Handle("GET /api/admin/users/{id}/cards", requireAdmin(adminHandler.ListUserCards))
+	mux.Handle("POST /api/admin/users/{id}/disable", requireAdmin(adminHandler.DisableUser))
+	mux.Handle("POST /api/admin/users/{id}/enable", requireAdmin(adminHandler.EnableUser))
+	mux.Handle("POST /api/admin/users/{id}/logout", requireAdmin(adminHandler.ForceLogout))
+	mux.Handle("POST /api/admin/users/{id}/reset-ai-generations", requireAdmin(adminHandler.ResetAIGenerations))
+	mux.Handle("GET /api/admin/ai-logs", requireAdmin(adminHandler.ListAILogs))
+	mux.Handle("GET /api/admin/audit-log", requireAdmin(adminHandler.ListAuditLog))
+	mux.Handle("GET /api/admin/jobs", requireAdmin(adminHandler.ListJobs))
+	mux.Handle("GET /api/admin/csp-reports", requireAdmin(cspReportHandler.Stats))
+	mux.Handle("GET /api/admin/reports", requireAdmin(moderationHandler.ListReports))
+	mux.Handle("POST /api/admin/reports/{id}/dismiss", requireAdmin(moderationHandler.DismissReport))
+	mux.Handle("POST /api/admin/reports/{id}/hide-item", requireAdmin(moderationHandler.HideItem))
+	mux.Handle("POST /api/admin/reports/{id}/suspend-user", requireAdmin(moderationHandler.SuspendUser))
+	mux.Handle("POST /api/admin/items/{id}/unhide", requireAdmin(moderationHandler.UnhideItem))
+	mux.Handle("POST /api/admin/reports/{id}/hide-template", requireAdmin(moderationHandler.HideTemplate))
+	mux.Handle("POST /api/admin/templates/{id}/unhide", requireAdmin(moderationHandler.UnhideTemplate))
+
+	// CSP violation reports; outside /api so report bursts don't count
+	// against the client's API rate limit
+	mux.Handle("POST "+middleware.CSPReportPath, cspReportRateLimiter.Middleware(http.HandlerFunc(cspReportHandler.Report)))
+
 	// Static files
 	fs := http.FileServer(http.Dir("web/static"))
 	mux.Handle("GET /static/", http.StripPrefix("/static/", fs))
@@ -278,10 +545,13 @@ func run() error {
 	// SPA route - serve index.html for the root path
 	// Hash-based routing (#home, #login, etc.) is handled client-side
 	mux.Handle("GET /{$}", requireSession(http.HandlerFunc(pageHandler.Index)))
+	mux.Handle("GET /u/{username}", http.HandlerFunc(pageHandler.PublicProfile))
 
 	// Build middleware chain (order matters: outermost first)
 	var handler http.Handler = mux
+	handler = apiRateLimiter.Middleware(handler)
 	handler = authMiddleware.Authenticate(handler)
+	handler = requestMeta.Apply(handler)
 	handler = csrfMiddleware.Protect(handler)
 	handler = cacheControl.Apply(handler)
 	handler = compress.Apply(handler)
@@ -334,6 +604,45 @@ func run() error {
 	return nil
 }
 
+// discardCount adapts a purge method that reports how many rows it removed
+// to a scheduled job.
+func discardCount(fn func(context.Context) (int, error)) services.JobFunc {
+	return func(ctx context.Context) error {
+		_, err := fn(ctx)
+		return err
+	}
+}
+
+// poolOptions sizes a pool from the database config; the primary and the
+// replica each have their own statement timeout.
+func poolOptions(cfg config.DatabaseConfig, statementTimeout time.Duration) database.PoolOptions {
+	return database.PoolOptions{
+		MaxConns:         int32(cfg.MaxConns),
+		MinConns:         int32(cfg.MinConns),
+		StatementTimeout: statementTimeout,
+	}
+}
+
+func apiRateLimitPolicies(cfg config.RateLimitConfig) []middleware.RateLimitPolicy {
+	var policies []middleware.RateLimitPolicy
+	if cfg.AnonymousPerIP > 0 {
+		policies = append(policies, middleware.RateLimitPolicy{
+			Name: "anon", Algorithm: middleware.SlidingWindow, Limit: int64(cfg.AnonymousPerIP), Window: time.Minute, Key: middleware.RateLimitAnonymous,
+		})
+	}
+	if cfg.SessionPerUser > 0 {
+		policies = append(policies, middleware.RateLimitPolicy{
+			Name: "session", Algorithm: middleware.SlidingWindow, Limit: int64(cfg.SessionPerUser), Window: time.Minute, Key: middleware.RateLimitBySession,
+		})
+	}
+	if cfg.TokenPerToken > 0 {
+		policies = append(policies, middleware.RateLimitPolicy{
+			Name: "token", Algorithm: middleware.TokenBucket, Limit: int64(cfg.TokenPerToken), Window: time.Minute, Key: middleware.RateLimitByToken,
+		})
+	}
+	return policies
+}
+
 func resolveAIRateLimit(cfg *config.Config, logger *logging.Logger, lookupEnv func(string) (string, bool)) int64 {
 	aiRateLimit := int64(10)
 	if cfg.Server.Environment == "development" {
@@ -55,3 +55,13 @@ func TestResolveAIRateLimit_InvalidEnv(t *testing.T) {
 		t.Fatalf("expected fallback limit 10, got %d", limit)
 	}
 }
+
+func TestAPIRateLimitPolicies_SkipsDisabled(t *testing.T) {
+	policies := apiRateLimitPolicies(config.RateLimitConfig{AnonymousPerIP: 120, TokenPerToken: 60})
+	if len(policies) != 2 || policies[0].Name != "anon" || policies[1].Name != "token" {
+		t.Fatalf("unexpected policies: %+v", policies)
+	}
+	if policies[1].Limit != 60 {
+		t.Fatalf("expected token limit 60, got %d", policies[1].Limit)
+	}
+}
@@ -1,18 +1,28 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/netip"
 	"os"
-	"strconv"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Email    EmailConfig
-	AI       AIConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Email     EmailConfig
+	AI        AIConfig
+	Throttle  ThrottleConfig
+	RateLimit RateLimitConfig
+	CSP       CSPConfig
+
+	settings []setting
 }
 
 type ServerConfig struct {
@@ -22,6 +32,19 @@ type ServerConfig struct {
 	Environment   string // "development", "production", "test"
 	Debug         bool
 	DebugMaxChars int
+	// LogFormat is "json" or "text"; it defaults to text in development.
+	LogFormat string
+	// HealthLogSampleRate logs one of every N successful health checks.
+	HealthLogSampleRate int
+	// SessionDuration is how long a sign-in lasts.
+	SessionDuration time.Duration
+	// TrustedProxies are the reverse proxies (IPs or CIDR ranges) whose
+	// X-Forwarded-For and X-Real-IP headers are believed. Requests from any
+	// other address are keyed on the connection's address.
+	TrustedProxies []netip.Prefix
+	// TrustCFConnectingIP takes the client IP from Cloudflare's
+	// CF-Connecting-IP header on requests from a trusted proxy.
+	TrustCFConnectingIP bool
 }
 
 type DatabaseConfig struct {
@@ -31,6 +54,14 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// Pool sizing and per-statement limits; a zero timeout disables it.
+	MaxConns         int
+	MinConns         int
+	StatementTimeout time.Duration
+	// ReplicaURL is an optional read replica; read-only queries go there
+	// unless the request has already written.
+	ReplicaURL              string
+	ReplicaStatementTimeout time.Duration
 }
 
 type RedisConfig struct {
@@ -59,6 +90,42 @@ type EmailConfig struct {
 	// SMTP settings (for Mailpit in local dev)
 	SMTPHost string
 	SMTPPort int
+	// Outbox settings; when enabled, email is queued and sent by a background worker
+	OutboxEnabled       bool
+	OutboxMaxAttempts   int
+	OutboxRatePerSecond float64 // 0 uses the provider default
+}
+
+// ThrottleConfig controls brute-force protection on auth endpoints.
+type ThrottleConfig struct {
+	Enabled bool
+	// Rules holds per-route overrides from THROTTLE_<ROUTE> variables, keyed by
+	// lower-case route name. Values use the "ip=20,account=5,window=15m" form.
+	Rules map[string]string
+}
+
+// RateLimitConfig controls request rate limits on the API. Limits are per
+// minute; zero disables that limit.
+type RateLimitConfig struct {
+	Backend        string // "redis" or "memory"
+	AnonymousPerIP int
+	SessionPerUser int
+	TokenPerToken  int
+	// SupportPerIP caps support requests per IP over SupportWindow, rather
+	// than per minute.
+	SupportPerIP  int
+	SupportWindow time.Duration
+}
+
+// CSPConfig adjusts the Content-Security-Policy header.
+type CSPConfig struct {
+	// ReportOnly sends the policy as Content-Security-Policy-Report-Only,
+	// so violations are reported without being blocked.
+	ReportOnly bool
+	// Directives holds overrides from CSP_<DIRECTIVE> settings, keyed by
+	// directive name ("script-src" for CSP_SCRIPT_SRC). An empty value
+	// removes the directive.
+	Directives map[string]string
 }
 
 func (d DatabaseConfig) DSN() string {
@@ -72,93 +139,167 @@ func (r RedisConfig) Addr() string {
 	return fmt.Sprintf("%s:%d", r.Host, r.Port)
 }
 
+// Load reads the configuration from the environment and, if CONFIG_FILE is
+// set, the config file it names.
 func Load() (*Config, error) {
+	return LoadFile(os.Getenv("CONFIG_FILE"))
+}
+
+// LoadFile reads the configuration from the environment and the YAML or TOML
+// file at path, if path isn't empty. Environment variables win over the
+// file. Every problem found is reported in the returned error, one per line.
+func LoadFile(path string) (*Config, error) {
+	var file map[string]string
+	if path != "" {
+		var err error
+		if file, err = readConfigFile(path); err != nil {
+			return nil, err
+		}
+	}
+	src := newSource(file, path)
+
 	cfg := &Config{
 		Server: ServerConfig{
-			Host:          getEnv("SERVER_HOST", "0.0.0.0"),
-			Port:          getEnvInt("SERVER_PORT", 8080),
-			Secure:        getEnvBool("SERVER_SECURE", false),
-			Environment:   getEnv("APP_ENV", "development"),
-			Debug:         getEnvBool("DEBUG", false),
-			DebugMaxChars: getEnvInt("DEBUG_LOG_MAX_CHARS", 8000),
+			Host:          src.str("SERVER_HOST", "0.0.0.0"),
+			Port:          src.int("SERVER_PORT", 8080),
+			Secure:        src.bool("SERVER_SECURE", false),
+			Environment:   src.str("APP_ENV", "development"),
+			Debug:         src.bool("DEBUG", false),
+			DebugMaxChars: src.int("DEBUG_LOG_MAX_CHARS", 8000),
+
+			HealthLogSampleRate: src.int("LOG_HEALTH_SAMPLE_RATE", 100),
+			SessionDuration:     src.duration("SESSION_DURATION", 30*24*time.Hour),
+			TrustedProxies:      src.prefixes("TRUSTED_PROXIES"),
+			TrustCFConnectingIP: src.bool("TRUST_CF_CONNECTING_IP", false),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvInt("DB_PORT", 5432),
-			User:     getEnv("DB_USER", "bingo"),
-			Password: getEnv("DB_PASSWORD", "bingo"),
-			DBName:   getEnv("DB_NAME", "nye_bingo"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:     src.str("DB_HOST", "localhost"),
+			Port:     src.int("DB_PORT", 5432),
+			User:     src.str("DB_USER", "bingo"),
+			Password: src.str("DB_PASSWORD", "bingo"),
+			DBName:   src.str("DB_NAME", "nye_bingo"),
+			SSLMode:  src.str("DB_SSLMODE", "disable"),
+
+			MaxConns:         src.int("DB_MAX_CONNS", 25),
+			MinConns:         src.int("DB_MIN_CONNS", 5),
+			StatementTimeout: src.duration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+			ReplicaURL:       src.str("DB_REPLICA_URL", ""),
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnvInt("REDIS_PORT", 6379),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvInt("REDIS_DB", 0),
+			Host:     src.str("REDIS_HOST", "localhost"),
+			Port:     src.int("REDIS_PORT", 6379),
+			Password: src.str("REDIS_PASSWORD", ""),
+			DB:       src.int("REDIS_DB", 0),
 		},
 		Email: EmailConfig{
-			Provider:     getEnv("EMAIL_PROVIDER", "console"),
-			FromAddress:  getEnv("EMAIL_FROM_ADDRESS", "noreply@yearofbingo.com"),
-			FromName:     getEnv("EMAIL_FROM_NAME", "Year of Bingo"),
-			BaseURL:      getEnv("APP_BASE_URL", "http://localhost:8080"),
-			ResendAPIKey: getEnv("RESEND_API_KEY", ""),
-			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
-			SMTPPort:     getEnvInt("SMTP_PORT", 1025),
+			Provider:     src.str("EMAIL_PROVIDER", "console"),
+			FromAddress:  src.str("EMAIL_FROM_ADDRESS", "noreply@yearofbingo.com"),
+			FromName:     src.str("EMAIL_FROM_NAME", "Year of Bingo"),
+			BaseURL:      src.str("APP_BASE_URL", "http://localhost:8080"),
+			ResendAPIKey: src.str("RESEND_API_KEY", ""),
+			SMTPHost:     src.str("SMTP_HOST", "localhost"),
+			SMTPPort:     src.int("SMTP_PORT", 1025),
+
+			OutboxEnabled:       src.bool("EMAIL_OUTBOX_ENABLED", true),
+			OutboxMaxAttempts:   src.int("EMAIL_OUTBOX_MAX_ATTEMPTS", 8),
+			OutboxRatePerSecond: src.float64("EMAIL_OUTBOX_RATE_PER_SECOND", 0),
 		},
 		AI: AIConfig{
-			GeminiAPIKey:          getEnv("GEMINI_API_KEY", ""),
-			GeminiModel:           getEnvNonEmpty("GEMINI_MODEL", "gemini-3-flash-preview"),
-			GeminiThinkingLevel:   getEnvNonEmpty("GEMINI_THINKING_LEVEL", "minimal"),
-			GeminiThinkingBudget:  getEnvInt("GEMINI_THINKING_BUDGET", 0),
-			GeminiTemperature:     getEnvFloat64("GEMINI_TEMPERATURE", 0.8),
-			GeminiMaxOutputTokens: getEnvInt("GEMINI_MAX_OUTPUT_TOKENS", 4096),
-			Stub:                  getEnvBool("AI_STUB", false),
+			GeminiAPIKey:          src.str("GEMINI_API_KEY", ""),
+			GeminiModel:           src.nonEmpty("GEMINI_MODEL", "gemini-3-flash-preview"),
+			GeminiThinkingLevel:   src.nonEmpty("GEMINI_THINKING_LEVEL", "minimal"),
+			GeminiThinkingBudget:  src.int("GEMINI_THINKING_BUDGET", 0),
+			GeminiTemperature:     src.float64("GEMINI_TEMPERATURE", 0.8),
+			GeminiMaxOutputTokens: src.int("GEMINI_MAX_OUTPUT_TOKENS", 4096),
+			Stub:                  src.bool("AI_STUB", false),
+		},
+		Throttle: ThrottleConfig{
+			Enabled: src.bool("THROTTLE_ENABLED", true),
+			Rules:   src.prefixed("THROTTLE_", "THROTTLE_ENABLED"),
+		},
+		RateLimit: RateLimitConfig{
+			Backend:        strings.ToLower(src.nonEmpty("RATE_LIMIT_BACKEND", "redis")),
+			AnonymousPerIP: src.int("RATE_LIMIT_ANONYMOUS", 120),
+			SessionPerUser: src.int("RATE_LIMIT_SESSION", 300),
+			TokenPerToken:  src.int("RATE_LIMIT_TOKEN", 60),
+			SupportPerIP:   src.int("RATE_LIMIT_SUPPORT", 5),
+			SupportWindow:  src.duration("RATE_LIMIT_SUPPORT_WINDOW", time.Hour),
 		},
+		CSP: CSPConfig{
+			ReportOnly: src.bool("CSP_REPORT_ONLY", false),
+			Directives: map[string]string{},
+		},
+	}
+	for name, value := range src.prefixed("CSP_", "CSP_REPORT_ONLY") {
+		cfg.CSP.Directives[strings.ReplaceAll(name, "_", "-")] = value
 	}
 
-	return cfg, nil
-}
+	defaultLogFormat := "json"
+	if cfg.Server.Environment == "development" {
+		defaultLogFormat = "text"
+	}
+	cfg.Server.LogFormat = strings.ToLower(src.nonEmpty("LOG_FORMAT", defaultLogFormat))
+	cfg.Database.ReplicaStatementTimeout = src.duration("DB_REPLICA_STATEMENT_TIMEOUT", cfg.Database.StatementTimeout)
 
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+	if path != "" {
+		src.checkUnused()
 	}
-	return defaultValue
+	errs := append(src.errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+
+	cfg.settings = src.settings
+	return cfg, nil
 }
 
-func getEnvNonEmpty(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		if strings.TrimSpace(value) != "" {
-			return value
+// validate checks settings against each other and against what production
+// needs. Each error says which setting to change.
+func (c *Config) validate() []error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
 		}
-		return defaultValue
 	}
-	return defaultValue
-}
 
-func getEnvInt(key string, defaultValue int) int {
-	if value, exists := os.LookupEnv(key); exists {
-		if intVal, err := strconv.Atoi(value); err == nil {
-			return intVal
-		}
+	check(slices.Contains([]string{"development", "production", "test"}, c.Server.Environment),
+		"APP_ENV must be development, production or test, got %q", c.Server.Environment)
+	check(c.Server.Port >= 1 && c.Server.Port <= 65535,
+		"SERVER_PORT must be between 1 and 65535, got %d", c.Server.Port)
+	if _, err := logging.ParseFormat(c.Server.LogFormat); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT: %w", err))
 	}
-	return defaultValue
-}
+	check(c.Server.SessionDuration > 0,
+		"SESSION_DURATION must be positive, got %s", c.Server.SessionDuration)
 
-func getEnvBool(key string, defaultValue bool) bool {
-	if value, exists := os.LookupEnv(key); exists {
-		if boolVal, err := strconv.ParseBool(value); err == nil {
-			return boolVal
-		}
+	check(c.Database.MaxConns >= 1 && c.Database.MinConns >= 0 && c.Database.MinConns <= c.Database.MaxConns,
+		"DB_MIN_CONNS (%d) must be between 0 and DB_MAX_CONNS (%d), which must be at least 1", c.Database.MinConns, c.Database.MaxConns)
+
+	check(slices.Contains([]string{"resend", "smtp", "console"}, c.Email.Provider),
+		"EMAIL_PROVIDER must be resend, smtp or console, got %q", c.Email.Provider)
+	check(c.Email.Provider != "resend" || c.Email.ResendAPIKey != "",
+		"EMAIL_PROVIDER=resend needs RESEND_API_KEY (or RESEND_API_KEY_FILE)")
+	check(c.Email.OutboxMaxAttempts >= 1,
+		"EMAIL_OUTBOX_MAX_ATTEMPTS must be at least 1, got %d", c.Email.OutboxMaxAttempts)
+
+	check(c.RateLimit.Backend == "redis" || c.RateLimit.Backend == "memory",
+		"RATE_LIMIT_BACKEND must be redis or memory, got %q", c.RateLimit.Backend)
+	check(c.RateLimit.AnonymousPerIP >= 0 && c.RateLimit.SessionPerUser >= 0 && c.RateLimit.TokenPerToken >= 0 && c.RateLimit.SupportPerIP >= 0,
+		"RATE_LIMIT_ANONYMOUS, RATE_LIMIT_SESSION, RATE_LIMIT_TOKEN and RATE_LIMIT_SUPPORT must not be negative")
+	check(c.RateLimit.SupportWindow > 0,
+		"RATE_LIMIT_SUPPORT_WINDOW must be positive, got %s", c.RateLimit.SupportWindow)
+
+	for name, value := range c.CSP.Directives {
+		check(!strings.ContainsAny(value, ";,"),
+			"CSP_%s must be a single directive's sources, without ';' or ','", strings.ToUpper(strings.ReplaceAll(name, "-", "_")))
 	}
-	return defaultValue
-}
 
-func getEnvFloat64(key string, defaultValue float64) float64 {
-	if value, exists := os.LookupEnv(key); exists {
-		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
-			return floatVal
-		}
+	if c.Server.Environment == "production" {
+		check(c.Server.Secure,
+			"SERVER_SECURE must be true when APP_ENV=production, or session and CSRF cookies are sent over plain HTTP")
+		check(c.AI.Stub || c.AI.GeminiAPIKey != "",
+			"GEMINI_API_KEY (or GEMINI_API_KEY_FILE) is required when APP_ENV=production; set AI_STUB=true to run without AI")
 	}
-	return defaultValue
+	return errs
 }
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readConfigFile loads a YAML or TOML config file, chosen by extension, into
+// settings keyed by environment variable name. Nested sections are joined to
+// their keys with underscores and upper-cased, so
+//
+//	db:
+//	  max_conns: 40
+//
+// in YAML, or
+//
+//	[db]
+//	max_conns = 40
+//
+// in TOML, both set DB_MAX_CONNS. The file is read by a full parser for its
+// format, so quoting, multi-line strings, flow mappings, inline tables and
+// YAML anchors all work. Every setting is a single value, though: lists,
+// arrays of tables, TOML dates and YAML merge keys are rejected, as are
+// YAML files with more than one document.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		values, err = parseYAML(data)
+	case ".toml":
+		values, err = parseTOML(data)
+	default:
+		return nil, fmt.Errorf("config file %s: unknown format, want .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func settingName(parts ...string) string {
+	return strings.ToUpper(strings.Join(parts, "_"))
+}
+
+func addSetting(values map[string]string, name, value string) error {
+	if _, ok := values[name]; ok {
+		return fmt.Errorf("%s is set more than once", name)
+	}
+	values[name] = value
+	return nil
+}
+
+func parseYAML(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	var doc yaml.Node
+	if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+		return values, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var next yaml.Node
+	if err := dec.Decode(&next); err == nil {
+		return nil, fmt.Errorf("line %d: only one document is supported", next.Line)
+	} else if !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 {
+		return values, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected \"key: value\" settings", root.Line)
+	}
+	return values, flattenYAML(values, nil, root)
+}
+
+func flattenYAML(values map[string]string, path []string, mapping *yaml.Node) error {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if key.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: keys must be plain values", key.Line)
+		}
+		if key.Tag == "!!merge" {
+			return fmt.Errorf("line %d: merge keys are not supported", key.Line)
+		}
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+
+		parts := append(append([]string{}, path...), key.Value)
+		switch value.Kind {
+		case yaml.MappingNode:
+			if err := flattenYAML(values, parts, value); err != nil {
+				return err
+			}
+		case yaml.ScalarNode:
+			// A key with nothing after it is an empty section.
+			if value.Tag == "!!null" {
+				continue
+			}
+			if err := addSetting(values, settingName(parts...), value.Value); err != nil {
+				return fmt.Errorf("line %d: %w", key.Line, err)
+			}
+		default:
+			return fmt.Errorf("line %d: %s: lists are not supported", key.Line, strings.Join(parts, "."))
+		}
+	}
+	return nil
+}
+
+func parseTOML(data []byte) (map[string]string, error) {
+	var doc map[string]any
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys come back in file order, sections included, so problems are
+	// reported in the order they appear.
+	values := map[string]string{}
+	for _, key := range md.Keys() {
+		var value string
+		switch typ := md.Type(key...); typ {
+		case "Hash":
+			continue
+		case "Array", "ArrayHash":
+			return nil, fmt.Errorf("%s: arrays are not supported", key)
+		case "Datetime":
+			return nil, fmt.Errorf("%s: dates and times are not supported, quote the value", key)
+		default:
+			value, err = tomlValue(lookupTOML(doc, key))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		if err := addSetting(values, settingName(key...), value); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return values, nil
+}
+
+func lookupTOML(doc map[string]any, key toml.Key) any {
+	var value any = doc
+	for _, part := range key {
+		table, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = table[part]
+	}
+	return value
+}
+
+func tomlValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported value %v", v)
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	values, err := parseYAML([]byte(`
+# Year of Bingo
+---
+app_env: production
+server:
+  port: 8443   # behind the proxy
+  secure: true
+db:
+  host: "db.internal"
+  password: 'it''s # secret'
+  replica:
+    url: postgres://replica/bingo
+throttle:
+  login: ip=10,account=3
+smtp: {host: mail.internal, port: 2525}
+defaults: &defaults
+  timeout: 5s
+redis: *defaults
+motd: |
+  Happy new year!
+  Good luck.
+empty:
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"APP_ENV":          "production",
+		"SERVER_PORT":      "8443",
+		"SERVER_SECURE":    "true",
+		"DB_HOST":          "db.internal",
+		"DB_PASSWORD":      "it's # secret",
+		"DB_REPLICA_URL":   "postgres://replica/bingo",
+		"THROTTLE_LOGIN":   "ip=10,account=3",
+		"SMTP_HOST":        "mail.internal",
+		"SMTP_PORT":        "2525",
+		"DEFAULTS_TIMEOUT": "5s",
+		"REDIS_TIMEOUT":    "5s",
+		"MOTD":             "Happy new year!\nGood luck.\n",
+	}
+	if len(values) != len(want) {
+		t.Fatalf("expected %d values, got %v", len(want), values)
+	}
+	for k, v := range want {
+		if values[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, values[k])
+		}
+	}
+}
+
+func TestParseYAML_Errors(t *testing.T) {
+	tests := map[string]struct {
+		doc  string
+		want string
+	}{
+		"list":       {"db:\n  hosts:\n    - a\n    - b\n", "line 2: db.hosts: lists are not supported"},
+		"flow list":  {"hosts: [a, b]\n", "line 1: hosts: lists are not supported"},
+		"tabs":       {"db:\n\thost: x\n", "line 2"},
+		"duplicate":  {"db:\n  host: x\ndb_host: y\n", "line 3: DB_HOST is set more than once"},
+		"no colon":   {"just words\n", "line 1: expected \"key: value\" settings"},
+		"merge key":  {"base: &base\n  host: x\ndb:\n  <<: *base\n", "line 4: merge keys are not supported"},
+		"documents":  {"a: 1\n---\nb: 2\n", "line 2: only one document is supported"},
+		"mapped key": {"? {a: 1}\n: x\n", "line 1: keys must be plain values"},
+	}
+	for name, tt := range tests {
+		_, err := parseYAML([]byte(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tt.want, err)
+		}
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	values, err := parseTOML([]byte(`
+app_env = "production" # inline comment
+debug_log_max_chars = 10_000
+
+[server]
+secure = true
+
+[db]
+password = 'a#b'
+statement_timeout = "5s"
+
+[db.replica]
+url = "postgres://replica/bingo"
+
+[smtp]
+server = { host = "mail.internal", port = 2525 }
+motd = """
+Happy new year!"""
+rate = 0.5
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"APP_ENV":              "production",
+		"DEBUG_LOG_MAX_CHARS":  "10000",
+		"SERVER_SECURE":        "true",
+		"DB_PASSWORD":          "a#b",
+		"DB_STATEMENT_TIMEOUT": "5s",
+		"DB_REPLICA_URL":       "postgres://replica/bingo",
+		"SMTP_SERVER_HOST":     "mail.internal",
+		"SMTP_SERVER_PORT":     "2525",
+		"SMTP_MOTD":            "Happy new year!",
+		"SMTP_RATE":            "0.5",
+	}
+	if len(values) != len(want) {
+		t.Fatalf("expected %d values, got %v", len(want), values)
+	}
+	for k, v := range want {
+		if values[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, values[k])
+		}
+	}
+}
+
+func TestParseTOML_Errors(t *testing.T) {
+	tests := map[string]struct {
+		doc  string
+		want string
+	}{
+		"array":        {"[db]\nhosts = [\"a\", \"b\"]\n", "db.hosts: arrays are not supported"},
+		"table array":  {"[[hosts]]\n", "hosts: arrays are not supported"},
+		"date":         {"starts = 2026-01-01\n", "starts: dates and times are not supported"},
+		"bare string":  {"host = localhost\n", "line 1"},
+		"missing":      {"\nhost =\n", "line 2"},
+		"duplicate":    {"[db]\nhost = \"a\"\n[db]\nhost = \"b\"\n", "line 3"},
+		"collision":    {"db_host = \"a\"\n[db]\nhost = \"b\"\n", "db.host: DB_HOST is set more than once"},
+		"no equals":    {"host\n", "line 1"},
+		"unterminated": {"motd = \"\"\"\n", "line 1"},
+	}
+	for name, tt := range tests {
+		_, err := parseTOML([]byte(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tt.want, err)
+		}
+	}
+}
+
+func TestReadConfigFile_ErrorNamesFileAndLine(t *testing.T) {
+	path := writeFile(t, "config.yaml", "db:\n  host: x\n  hosts:\n    - a\n")
+	_, err := readConfigFile(path)
+	if err == nil || !strings.Contains(err.Error(), "config file "+path+": line 3: db.hosts") {
+		t.Fatalf("expected the file and line in the error, got %v", err)
+	}
+}
+
+func TestReadConfigFile_UnknownFormat(t *testing.T) {
+	path := writeFile(t, "config.json", "{}")
+	if _, err := readConfigFile(path); err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Fatalf("expected unknown format error, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+// Where a setting's value came from, as shown by Config.Print.
+const (
+	originEnv     = "env"
+	originSecret  = "secret file"
+	originFile    = "config file"
+	originDefault = "default"
+)
+
+// source resolves settings by their environment variable name. A setting is
+// taken from the first of:
+//
+//   - the environment variable NAME
+//   - the file named by the environment variable NAME_FILE, for container
+//     secrets
+//   - NAME in the config file
+//   - the file named by NAME_FILE in the config file
+//
+// and falls back to its default. Malformed values are collected as errors
+// rather than silently replaced by the default.
+type source struct {
+	file     map[string]string
+	filePath string
+	used     map[string]bool
+	settings []setting
+	errs     []error
+}
+
+// setting is one resolved value, kept for Config.Print.
+type setting struct {
+	key    string
+	value  string
+	origin string
+}
+
+func newSource(file map[string]string, filePath string) *source {
+	return &source{file: file, filePath: filePath, used: map[string]bool{}}
+}
+
+func (s *source) errorf(format string, args ...any) {
+	s.errs = append(s.errs, fmt.Errorf(format, args...))
+}
+
+// lookup returns the raw value for key and where it came from, or an empty
+// origin when key isn't set anywhere.
+func (s *source) lookup(key string) (string, string) {
+	fileKey := key + "_FILE"
+	s.used[key] = true
+	s.used[fileKey] = true
+
+	value, inEnv := os.LookupEnv(key)
+	path, inEnvFile := os.LookupEnv(fileKey)
+	switch {
+	case inEnv && inEnvFile:
+		s.errorf("%s and %s are both set; set only one", key, fileKey)
+		return value, originEnv
+	case inEnv:
+		return value, originEnv
+	case inEnvFile:
+		return s.readSecret(fileKey, path), originSecret
+	}
+
+	if value, ok := s.file[key]; ok {
+		return value, originFile
+	}
+	if path, ok := s.file[fileKey]; ok {
+		return s.readSecret(fileKey, path), originSecret
+	}
+	return "", ""
+}
+
+func (s *source) readSecret(fileKey, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		s.errorf("%s: reading secret: %v", fileKey, err)
+		return ""
+	}
+	return strings.TrimRight(string(data), "\r\n")
+}
+
+func (s *source) record(key, value, origin string) {
+	s.settings = append(s.settings, setting{key: key, value: value, origin: origin})
+}
+
+func (s *source) str(key, defaultValue string) string {
+	value, origin := s.lookup(key)
+	if origin == "" {
+		value, origin = defaultValue, originDefault
+	}
+	s.record(key, value, origin)
+	return value
+}
+
+// nonEmpty is like str but also uses the default for a blank value.
+func (s *source) nonEmpty(key, defaultValue string) string {
+	value, origin := s.lookup(key)
+	if origin == "" || strings.TrimSpace(value) == "" {
+		value, origin = defaultValue, originDefault
+	}
+	s.record(key, value, origin)
+	return value
+}
+
+func (s *source) int(key string, defaultValue int) int {
+	return parseSetting(s, key, defaultValue, strconv.Atoi, "a whole number")
+}
+
+func (s *source) bool(key string, defaultValue bool) bool {
+	return parseSetting(s, key, defaultValue, strconv.ParseBool, "true or false")
+}
+
+func (s *source) float64(key string, defaultValue float64) float64 {
+	return parseSetting(s, key, defaultValue, func(v string) (float64, error) {
+		return strconv.ParseFloat(v, 64)
+	}, "a number")
+}
+
+func (s *source) duration(key string, defaultValue time.Duration) time.Duration {
+	return parseSetting(s, key, defaultValue, time.ParseDuration, `a duration such as "90s" or "12h"`)
+}
+
+func parseSetting[T any](s *source, key string, defaultValue T, parse func(string) (T, error), want string) T {
+	raw, origin := s.lookup(key)
+	if origin == "" {
+		s.record(key, fmt.Sprint(defaultValue), originDefault)
+		return defaultValue
+	}
+	value, err := parse(strings.TrimSpace(raw))
+	if err != nil {
+		s.errorf("%s (%s): %q is not %s", key, origin, raw, want)
+		return defaultValue
+	}
+	s.record(key, raw, origin)
+	return value
+}
+
+// prefixes reads a comma-separated list of IP addresses and CIDR ranges. A
+// bare address is treated as a single-host range.
+func (s *source) prefixes(key string) []netip.Prefix {
+	return parseSetting(s, key, nil, func(v string) ([]netip.Prefix, error) {
+		var list []netip.Prefix
+		for _, part := range strings.Split(v, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if strings.Contains(part, "/") {
+				prefix, err := netip.ParsePrefix(part)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, prefix.Masked())
+				continue
+			}
+			addr, err := netip.ParseAddr(part)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+		return list, nil
+	}, "a comma-separated list of IP addresses or CIDR ranges")
+}
+
+// prefixed returns every setting whose name starts with prefix, keyed by the
+// lower-cased remainder of its name. Names listed in skip are ignored, and
+// the environment wins over the config file.
+func (s *source) prefixed(prefix string, skip ...string) map[string]string {
+	values := map[string]string{}
+	add := func(name, value, origin string) {
+		if !strings.HasPrefix(name, prefix) || slices.Contains(skip, name) || strings.HasSuffix(name, "_FILE") {
+			return
+		}
+		rule := strings.ToLower(strings.TrimPrefix(name, prefix))
+		if _, ok := values[rule]; ok {
+			return
+		}
+		values[rule] = value
+		s.used[name] = true
+		s.record(name, value, origin)
+	}
+
+	for _, entry := range os.Environ() {
+		if name, value, ok := strings.Cut(entry, "="); ok {
+			add(name, value, originEnv)
+		}
+	}
+	keys := make([]string, 0, len(s.file))
+	for name := range s.file {
+		keys = append(keys, name)
+	}
+	slices.Sort(keys)
+	for _, name := range keys {
+		add(name, s.file[name], originFile)
+	}
+	return values
+}
+
+// checkUnused reports config file keys that no setting reads, which are
+// usually typos.
+func (s *source) checkUnused() {
+	var unknown []string
+	for key := range s.file {
+		if !s.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	for _, key := range unknown {
+		s.errorf("%s: unknown setting %s", s.filePath, key)
+	}
+}
+
+// Print writes every setting as NAME=value with where the value came from.
+// Secrets are redacted, as is the password in any URL, so the output is safe
+// to paste into a bug report.
+func (c *Config) Print(w io.Writer) error {
+	for _, st := range c.settings {
+		value := st.value
+		if logging.IsSensitiveKey(st.key) && value != "" {
+			value = "[REDACTED]"
+		} else if u, err := url.Parse(value); err == nil && u.User != nil {
+			value = u.Redacted()
+		}
+		if strings.ContainsAny(value, " #\"'") {
+			value = strconv.Quote(value)
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s # %s\n", st.key, value, st.origin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -41,6 +41,24 @@ func (m *Migrator) Down() error {
 	return nil
 }
 
+// Steps applies n migrations, or rolls back -n when n is negative.
+func (m *Migrator) Steps(n int) error {
+	err := m.m.Steps(n)
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("stepping migrations: %w", err)
+	}
+	return nil
+}
+
+// Force sets the recorded version without running any migration and clears
+// the dirty flag. Use it after repairing a migration that failed halfway.
+func (m *Migrator) Force(version int) error {
+	if err := m.m.Force(version); err != nil {
+		return fmt.Errorf("forcing migration version: %w", err)
+	}
+	return nil
+}
+
 func (m *Migrator) Version() (uint, bool, error) {
 	return m.m.Version()
 }
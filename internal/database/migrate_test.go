@@ -222,6 +222,39 @@ func TestMigratorVersion_NilVersion(t *testing.T) {
 	}
 }
 
+func TestMigratorSteps_ErrorWrapped(t *testing.T) {
+	db := &stubDB{
+		lockFn: func() error {
+			return errors.New("lock failed")
+		},
+	}
+
+	m := newTestMigrator(t, &stubSource{}, db)
+	err := m.Steps(-1)
+	if err == nil || !strings.Contains(err.Error(), "stepping migrations") || !strings.Contains(err.Error(), "lock failed") {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
+
+func TestMigratorForce_SetsCleanVersion(t *testing.T) {
+	var gotVersion int
+	var gotDirty bool
+	db := &stubDB{
+		setVersionFn: func(version int, dirty bool) error {
+			gotVersion, gotDirty = version, dirty
+			return nil
+		},
+	}
+
+	m := newTestMigrator(t, &stubSource{}, db)
+	if err := m.Force(7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotVersion != 7 || gotDirty {
+		t.Fatalf("expected clean version 7, got %d dirty=%t", gotVersion, gotDirty)
+	}
+}
+
 func TestMigratorClose_SourceErrorWins(t *testing.T) {
 	srcErr := errors.New("source close failed")
 	dbErr := errors.New("db close failed")
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -23,14 +24,40 @@ var (
 	}
 )
 
-func NewPostgresDB(dsn string) (*PostgresDB, error) {
+// PoolOptions sizes a connection pool and limits its statements. Zero
+// connection counts use the defaults; a zero StatementTimeout leaves
+// statements unlimited.
+type PoolOptions struct {
+	MaxConns         int32
+	MinConns         int32
+	StatementTimeout time.Duration
+}
+
+const (
+	defaultMaxConns = 25
+	defaultMinConns = 5
+)
+
+func NewPostgresDB(dsn string, opts PoolOptions) (*PostgresDB, error) {
 	config, err := parsePGConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("parsing database config: %w", err)
 	}
 
-	config.MaxConns = 25
-	config.MinConns = 5
+	config.MaxConns = defaultMaxConns
+	if opts.MaxConns > 0 {
+		config.MaxConns = opts.MaxConns
+	}
+	config.MinConns = min(int32(defaultMinConns), config.MaxConns)
+	if opts.MinConns > 0 {
+		config.MinConns = opts.MinConns
+	}
+	if opts.StatementTimeout > 0 {
+		if config.ConnConfig.RuntimeParams == nil {
+			config.ConnConfig.RuntimeParams = map[string]string{}
+		}
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
 	config.MaxConnLifetime = time.Hour
 	config.MaxConnIdleTime = 30 * time.Minute
 	config.HealthCheckPeriod = time.Minute
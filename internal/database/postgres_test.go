@@ -18,7 +18,7 @@ func TestNewPostgresDB_ParseError(t *testing.T) {
 		return nil, parseErr
 	}
 
-	_, err := NewPostgresDB("bad")
+	_, err := NewPostgresDB("bad", PoolOptions{})
 	if err == nil {
 		t.Fatal("expected parse error")
 	}
@@ -56,7 +56,7 @@ func TestNewPostgresDB_PingError(t *testing.T) {
 	}
 	closePGPool = func(pool *pgxpool.Pool) {}
 
-	_, err := NewPostgresDB("dsn")
+	_, err := NewPostgresDB("dsn", PoolOptions{})
 	if err == nil {
 		t.Fatal("expected ping error")
 	}
@@ -84,7 +84,7 @@ func TestNewPostgresDB_NewPoolError(t *testing.T) {
 		return nil, newErr
 	}
 
-	_, err := NewPostgresDB("dsn")
+	_, err := NewPostgresDB("dsn", PoolOptions{})
 	if err == nil {
 		t.Fatal("expected new pool error")
 	}
@@ -121,7 +121,7 @@ func TestNewPostgresDB_SuccessConfigValues(t *testing.T) {
 	}
 	closePGPool = func(pool *pgxpool.Pool) {}
 
-	db, err := NewPostgresDB("dsn")
+	db, err := NewPostgresDB("dsn", PoolOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -145,6 +145,41 @@ func TestNewPostgresDB_SuccessConfigValues(t *testing.T) {
 	}
 }
 
+func TestNewPostgresDB_PoolOptions(t *testing.T) {
+	origParse := parsePGConfig
+	origNew := newPGPool
+	origPing := pingPGPool
+	t.Cleanup(func() {
+		parsePGConfig = origParse
+		newPGPool = origNew
+		pingPGPool = origPing
+	})
+
+	cfg, err := pgxpool.ParseConfig("postgres://bingo@localhost/bingo")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	parsePGConfig = func(dsn string) (*pgxpool.Config, error) {
+		return cfg, nil
+	}
+	newPGPool = func(ctx context.Context, config *pgxpool.Config) (*pgxpool.Pool, error) {
+		return &pgxpool.Pool{}, nil
+	}
+	pingPGPool = func(ctx context.Context, pool *pgxpool.Pool) error {
+		return nil
+	}
+
+	if _, err := NewPostgresDB("dsn", PoolOptions{MaxConns: 3, StatementTimeout: 1500 * time.Millisecond}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MaxConns != 3 || cfg.MinConns != 3 {
+		t.Fatalf("expected 3/3 connections, got %d/%d", cfg.MaxConns, cfg.MinConns)
+	}
+	if got := cfg.ConnConfig.RuntimeParams["statement_timeout"]; got != "1500" {
+		t.Fatalf("expected statement_timeout 1500, got %q", got)
+	}
+}
+
 func TestPostgresDB_Close_CallsPoolClose(t *testing.T) {
 	origClose := closePGPool
 	t.Cleanup(func() { closePGPool = origClose })
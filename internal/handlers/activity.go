@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type ActivityHandler struct {
+	activityService services.ActivityServiceInterface
+}
+
+func NewActivityHandler(activityService services.ActivityServiceInterface) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+type FeedResponse struct {
+	Items      []models.FeedItem `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// Feed returns recent activity from the user's friends.
+func (h *ActivityHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	params := services.FeedParams{Limit: 50, Cursor: r.URL.Query().Get("cursor")}
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 100 {
+			writeError(w, http.StatusBadRequest, "Limit must be between 1 and 100")
+			return
+		}
+		params.Limit = limit
+	}
+
+	items, next, err := h.activityService.Feed(r.Context(), user.ID, params)
+	if errors.Is(err, services.ErrInvalidFeedCursor) {
+		writeError(w, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if err != nil {
+		logError(r, "Error loading activity feed", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, FeedResponse{Items: items, NextCursor: next})
+}
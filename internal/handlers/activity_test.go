@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func feedRequest(url string, user *models.User) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	if user == nil {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), userContextKey, user))
+}
+
+func TestActivityHandler_Feed_Unauthenticated(t *testing.T) {
+	handler := NewActivityHandler(&mockActivityService{})
+	rr := httptest.NewRecorder()
+	handler.Feed(rr, feedRequest("/api/friends/activity", nil))
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestActivityHandler_Feed_InvalidParams(t *testing.T) {
+	handler := NewActivityHandler(&mockActivityService{
+		FeedFunc: func(ctx context.Context, viewerID uuid.UUID, params services.FeedParams) ([]models.FeedItem, string, error) {
+			return nil, "", services.ErrInvalidFeedCursor
+		},
+	})
+	user := &models.User{ID: uuid.New()}
+
+	rr := httptest.NewRecorder()
+	handler.Feed(rr, feedRequest("/api/friends/activity?limit=0", user))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Limit must be between 1 and 100")
+
+	rr = httptest.NewRecorder()
+	handler.Feed(rr, feedRequest("/api/friends/activity?cursor=bogus", user))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid cursor")
+}
+
+func TestActivityHandler_Feed_Success(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewActivityHandler(&mockActivityService{
+		FeedFunc: func(ctx context.Context, viewerID uuid.UUID, params services.FeedParams) ([]models.FeedItem, string, error) {
+			if viewerID != user.ID || params.Limit != 10 || params.Cursor != "abc" {
+				t.Fatalf("unexpected feed call: %v %+v", viewerID, params)
+			}
+			return []models.FeedItem{{ID: uuid.New(), Type: models.ActivityBingo}}, "next", nil
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	handler.Feed(rr, feedRequest("/api/friends/activity?limit=10&cursor=abc", user))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp FeedResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].Type != models.ActivityBingo || resp.NextCursor != "next" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type AdminHandler struct {
+	adminService services.AdminServiceInterface
+	jobs         services.JobStatusProvider
+}
+
+func NewAdminHandler(adminService services.AdminServiceInterface) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+func (h *AdminHandler) SetJobStatusProvider(jobs services.JobStatusProvider) {
+	h.jobs = jobs
+}
+
+type AdminUserListResponse struct {
+	Users []models.AdminUserSummary `json:"users"`
+}
+
+type AdminUserResponse struct {
+	User *models.AdminUserSummary `json:"user"`
+}
+
+type AdminUserCardsResponse struct {
+	Cards []*models.BingoCard `json:"cards"`
+}
+
+type AdminAILogListResponse struct {
+	Logs []models.AIGenerationLog `json:"logs"`
+}
+
+type AdminAuditLogResponse struct {
+	Entries []models.AdminAuditEntry `json:"entries"`
+}
+
+type AdminJobListResponse struct {
+	Jobs []models.JobStatus `json:"jobs"`
+}
+
+type AdminMessageResponse struct {
+	Message string `json:"message"`
+}
+
+func (h *AdminHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	page, ok := parseAdminListParams(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.adminService.SearchUsers(r.Context(), admin.ID, services.AdminUserSearchParams{
+		Query:           r.URL.Query().Get("q"),
+		AdminListParams: page,
+	})
+	if err != nil {
+		logError(r, "Error searching users", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminUserListResponse{Users: users})
+}
+
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.adminService.GetUser(r.Context(), admin.ID, userID)
+	if errors.Is(err, services.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error getting user", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminUserResponse{User: user})
+}
+
+func (h *AdminHandler) ListUserCards(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	cards, err := h.adminService.ListUserCards(r.Context(), admin.ID, userID)
+	if errors.Is(err, services.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error listing user cards", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if cards == nil {
+		cards = []*models.BingoCard{}
+	}
+
+	writeJSON(w, http.StatusOK, AdminUserCardsResponse{Cards: cards})
+}
+
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, true)
+}
+
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, false)
+}
+
+func (h *AdminHandler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	err = h.adminService.SetDisabled(r.Context(), admin.ID, userID, disabled)
+	if errors.Is(err, services.ErrAdminCannotModifySelf) {
+		writeError(w, http.StatusBadRequest, "Cannot change your own account status")
+		return
+	}
+	if errors.Is(err, services.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error updating user status", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	message := "User enabled"
+	if disabled {
+		message = "User disabled"
+	}
+	writeJSON(w, http.StatusOK, AdminMessageResponse{Message: message})
+}
+
+func (h *AdminHandler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	err = h.adminService.ForceLogout(r.Context(), admin.ID, userID)
+	if errors.Is(err, services.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error forcing logout", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminMessageResponse{Message: "User sessions revoked"})
+}
+
+func (h *AdminHandler) ResetAIGenerations(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	err = h.adminService.ResetAIFreeGenerations(r.Context(), admin.ID, userID)
+	if errors.Is(err, services.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error resetting AI generations", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminMessageResponse{Message: "AI generation quota reset"})
+}
+
+func (h *AdminHandler) ListAILogs(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	page, ok := parseAdminListParams(w, r)
+	if !ok {
+		return
+	}
+
+	params := services.AdminAILogParams{AdminListParams: page}
+	if userIDParam := r.URL.Query().Get("user_id"); userIDParam != "" {
+		userID, err := uuid.Parse(userIDParam)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		params.UserID = &userID
+	}
+
+	logs, err := h.adminService.ListAIGenerationLogs(r.Context(), admin.ID, params)
+	if err != nil {
+		logError(r, "Error listing AI logs", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminAILogListResponse{Logs: logs})
+}
+
+func (h *AdminHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	page, ok := parseAdminListParams(w, r)
+	if !ok {
+		return
+	}
+
+	params := services.AdminAuditLogParams{AdminListParams: page}
+	if userIDParam := r.URL.Query().Get("user_id"); userIDParam != "" {
+		userID, err := uuid.Parse(userIDParam)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		params.TargetUserID = &userID
+	}
+
+	entries, err := h.adminService.ListAuditLog(r.Context(), params)
+	if err != nil {
+		logError(r, "Error listing audit log", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminAuditLogResponse{Entries: entries})
+}
+
+// parseAdminListParams reads limit and offset query parameters, writing a 400
+// and returning false when either is invalid.
+func parseAdminListParams(w http.ResponseWriter, r *http.Request) (services.AdminListParams, bool) {
+	params := services.AdminListParams{Limit: 50}
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return params, false
+		}
+		params.Limit = parsed
+	}
+	if offsetParam := r.URL.Query().Get("offset"); offsetParam != "" {
+		parsed, err := strconv.Atoi(offsetParam)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid offset")
+			return params, false
+		}
+		params.Offset = parsed
+	}
+	return params, true
+}
+
+// ListJobs reports each scheduled background job with its latest run on any
+// replica.
+func (h *AdminHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if h.jobs == nil {
+		writeJSON(w, http.StatusOK, AdminJobListResponse{Jobs: []models.JobStatus{}})
+		return
+	}
+
+	jobs, err := h.jobs.Status(r.Context())
+	if err != nil {
+		logError(r, "Error listing jobs", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminJobListResponse{Jobs: jobs})
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type mockAdminService struct {
+	SearchUsersFunc          func(ctx context.Context, adminID uuid.UUID, params services.AdminUserSearchParams) ([]models.AdminUserSummary, error)
+	GetUserFunc              func(ctx context.Context, adminID, userID uuid.UUID) (*models.AdminUserSummary, error)
+	ListUserCardsFunc        func(ctx context.Context, adminID, userID uuid.UUID) ([]*models.BingoCard, error)
+	SetDisabledFunc          func(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error
+	ForceLogoutFunc          func(ctx context.Context, adminID, userID uuid.UUID) error
+	ResetAIFreeGenerationsFn func(ctx context.Context, adminID, userID uuid.UUID) error
+	ListAIGenerationLogsFunc func(ctx context.Context, adminID uuid.UUID, params services.AdminAILogParams) ([]models.AIGenerationLog, error)
+	ListAuditLogFunc         func(ctx context.Context, params services.AdminAuditLogParams) ([]models.AdminAuditEntry, error)
+}
+
+func (m *mockAdminService) SearchUsers(ctx context.Context, adminID uuid.UUID, params services.AdminUserSearchParams) ([]models.AdminUserSummary, error) {
+	if m.SearchUsersFunc != nil {
+		return m.SearchUsersFunc(ctx, adminID, params)
+	}
+	return []models.AdminUserSummary{}, nil
+}
+
+func (m *mockAdminService) GetUser(ctx context.Context, adminID, userID uuid.UUID) (*models.AdminUserSummary, error) {
+	if m.GetUserFunc != nil {
+		return m.GetUserFunc(ctx, adminID, userID)
+	}
+	return &models.AdminUserSummary{ID: userID}, nil
+}
+
+func (m *mockAdminService) ListUserCards(ctx context.Context, adminID, userID uuid.UUID) ([]*models.BingoCard, error) {
+	if m.ListUserCardsFunc != nil {
+		return m.ListUserCardsFunc(ctx, adminID, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminService) SetDisabled(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error {
+	if m.SetDisabledFunc != nil {
+		return m.SetDisabledFunc(ctx, adminID, userID, disabled)
+	}
+	return nil
+}
+
+func (m *mockAdminService) ForceLogout(ctx context.Context, adminID, userID uuid.UUID) error {
+	if m.ForceLogoutFunc != nil {
+		return m.ForceLogoutFunc(ctx, adminID, userID)
+	}
+	return nil
+}
+
+func (m *mockAdminService) ResetAIFreeGenerations(ctx context.Context, adminID, userID uuid.UUID) error {
+	if m.ResetAIFreeGenerationsFn != nil {
+		return m.ResetAIFreeGenerationsFn(ctx, adminID, userID)
+	}
+	return nil
+}
+
+func (m *mockAdminService) ListAIGenerationLogs(ctx context.Context, adminID uuid.UUID, params services.AdminAILogParams) ([]models.AIGenerationLog, error) {
+	if m.ListAIGenerationLogsFunc != nil {
+		return m.ListAIGenerationLogsFunc(ctx, adminID, params)
+	}
+	return []models.AIGenerationLog{}, nil
+}
+
+func (m *mockAdminService) ListAuditLog(ctx context.Context, params services.AdminAuditLogParams) ([]models.AdminAuditEntry, error) {
+	if m.ListAuditLogFunc != nil {
+		return m.ListAuditLogFunc(ctx, params)
+	}
+	return []models.AdminAuditEntry{}, nil
+}
+
+func withAdmin(req *http.Request, id uuid.UUID) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: id, IsAdmin: true}))
+}
+
+func TestAdminHandler_SearchUsers_Unauthenticated(t *testing.T) {
+	handler := NewAdminHandler(&mockAdminService{})
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	rr := httptest.NewRecorder()
+	handler.SearchUsers(rr, req)
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestAdminHandler_SearchUsers_PassesParams(t *testing.T) {
+	adminID := uuid.New()
+	handler := NewAdminHandler(&mockAdminService{
+		SearchUsersFunc: func(ctx context.Context, gotAdmin uuid.UUID, params services.AdminUserSearchParams) ([]models.AdminUserSummary, error) {
+			if gotAdmin != adminID {
+				t.Fatalf("expected admin id %s, got %s", adminID, gotAdmin)
+			}
+			if params.Query != "bob" || params.Limit != 10 || params.Offset != 20 {
+				t.Fatalf("unexpected params: %+v", params)
+			}
+			return []models.AdminUserSummary{{Username: "bob"}}, nil
+		},
+	})
+
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/users?q=bob&limit=10&offset=20", nil), adminID)
+	rr := httptest.NewRecorder()
+	handler.SearchUsers(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp AdminUserListResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].Username != "bob" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminHandler_SearchUsers_InvalidPagination(t *testing.T) {
+	handler := NewAdminHandler(&mockAdminService{})
+
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/users?limit=abc", nil), uuid.New())
+	rr := httptest.NewRecorder()
+	handler.SearchUsers(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid limit")
+
+	req = withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/users?offset=-1", nil), uuid.New())
+	rr = httptest.NewRecorder()
+	handler.SearchUsers(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid offset")
+}
+
+func TestAdminHandler_GetUser_NotFound(t *testing.T) {
+	handler := NewAdminHandler(&mockAdminService{
+		GetUserFunc: func(ctx context.Context, adminID, userID uuid.UUID) (*models.AdminUserSummary, error) {
+			return nil, services.ErrUserNotFound
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/users/x", nil), uuid.New())
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.GetUser(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "User not found")
+}
+
+func TestAdminHandler_GetUser_InvalidID(t *testing.T) {
+	handler := NewAdminHandler(&mockAdminService{})
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/users/x", nil), uuid.New())
+	req.SetPathValue("id", "not-a-uuid")
+	rr := httptest.NewRecorder()
+	handler.GetUser(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid user ID")
+}
+
+func TestAdminHandler_ListUserCards_EmptyArray(t *testing.T) {
+	handler := NewAdminHandler(&mockAdminService{})
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/users/x/cards", nil), uuid.New())
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.ListUserCards(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); body != "{\"cards\":[]}\n" {
+		t.Fatalf("expected empty cards array, got %q", body)
+	}
+}
+
+func TestAdminHandler_DisableUser(t *testing.T) {
+	targetID := uuid.New()
+	var gotDisabled *bool
+	handler := NewAdminHandler(&mockAdminService{
+		SetDisabledFunc: func(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error {
+			if userID != targetID {
+				t.Fatalf("unexpected target %s", userID)
+			}
+			gotDisabled = &disabled
+			return nil
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/users/x/disable", nil), uuid.New())
+	req.SetPathValue("id", targetID.String())
+	rr := httptest.NewRecorder()
+	handler.DisableUser(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotDisabled == nil || !*gotDisabled {
+		t.Fatal("expected SetDisabled(true)")
+	}
+}
+
+func TestAdminHandler_EnableUser_Errors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		msg    string
+	}{
+		{services.ErrAdminCannotModifySelf, http.StatusBadRequest, "Cannot change your own account status"},
+		{services.ErrUserNotFound, http.StatusNotFound, "User not found"},
+		{errors.New("boom"), http.StatusInternalServerError, "Internal server error"},
+	}
+	for _, tt := range tests {
+		handler := NewAdminHandler(&mockAdminService{
+			SetDisabledFunc: func(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error {
+				if disabled {
+					t.Fatal("expected SetDisabled(false)")
+				}
+				return tt.err
+			},
+		})
+		req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/users/x/enable", nil), uuid.New())
+		req.SetPathValue("id", uuid.New().String())
+		rr := httptest.NewRecorder()
+		handler.EnableUser(rr, req)
+		assertErrorResponse(t, rr, tt.status, tt.msg)
+	}
+}
+
+func TestAdminHandler_ForceLogout(t *testing.T) {
+	called := false
+	handler := NewAdminHandler(&mockAdminService{
+		ForceLogoutFunc: func(ctx context.Context, adminID, userID uuid.UUID) error {
+			called = true
+			return nil
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/users/x/logout", nil), uuid.New())
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.ForceLogout(rr, req)
+
+	if rr.Code != http.StatusOK || !called {
+		t.Fatalf("expected 200 and service call, got %d called=%v", rr.Code, called)
+	}
+}
+
+func TestAdminHandler_ResetAIGenerations_NotFound(t *testing.T) {
+	handler := NewAdminHandler(&mockAdminService{
+		ResetAIFreeGenerationsFn: func(ctx context.Context, adminID, userID uuid.UUID) error {
+			return services.ErrUserNotFound
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/users/x/reset-ai-generations", nil), uuid.New())
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.ResetAIGenerations(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "User not found")
+}
+
+func TestAdminHandler_ListAILogs_UserFilter(t *testing.T) {
+	targetID := uuid.New()
+	handler := NewAdminHandler(&mockAdminService{
+		ListAIGenerationLogsFunc: func(ctx context.Context, adminID uuid.UUID, params services.AdminAILogParams) ([]models.AIGenerationLog, error) {
+			if params.UserID == nil || *params.UserID != targetID {
+				t.Fatalf("expected user filter %s, got %v", targetID, params.UserID)
+			}
+			return []models.AIGenerationLog{}, nil
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/ai-logs?user_id="+targetID.String(), nil), uuid.New())
+	rr := httptest.NewRecorder()
+	handler.ListAILogs(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	req = withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/ai-logs?user_id=bad", nil), uuid.New())
+	rr = httptest.NewRecorder()
+	handler.ListAILogs(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid user ID")
+}
+
+func TestAdminHandler_ListAuditLog_Error(t *testing.T) {
+	handler := NewAdminHandler(&mockAdminService{
+		ListAuditLogFunc: func(ctx context.Context, params services.AdminAuditLogParams) ([]models.AdminAuditEntry, error) {
+			return nil, errors.New("db down")
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/audit-log", nil), uuid.New())
+	rr := httptest.NewRecorder()
+	handler.ListAuditLog(rr, req)
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
+
+type mockJobStatusProvider struct {
+	statuses []models.JobStatus
+	err      error
+}
+
+func (m *mockJobStatusProvider) Status(ctx context.Context) ([]models.JobStatus, error) {
+	return m.statuses, m.err
+}
+
+func TestAdminHandler_ListJobs(t *testing.T) {
+	handler := NewAdminHandler(&mockAdminService{})
+
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/jobs", nil), uuid.New())
+	rr := httptest.NewRecorder()
+	handler.ListJobs(rr, req)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"jobs":[]`) {
+		t.Fatalf("expected empty job list without a scheduler, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	handler.SetJobStatusProvider(&mockJobStatusProvider{statuses: []models.JobStatus{{Name: "notification_cleanup", Schedule: "0 3 * * *", RunCount: 2}}})
+	rr = httptest.NewRecorder()
+	handler.ListJobs(rr, req)
+	var resp AdminJobListResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Jobs) != 1 || resp.Jobs[0].Name != "notification_cleanup" || resp.Jobs[0].RunCount != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	handler.SetJobStatusProvider(&mockJobStatusProvider{err: errors.New("db down")})
+	rr = httptest.NewRecorder()
+	handler.ListJobs(rr, req)
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
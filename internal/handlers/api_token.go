@@ -3,7 +3,6 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
-	"log"
 	"net/http"
 	"strings"
 
@@ -63,7 +62,7 @@ func (h *ApiTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	token, rawToken, err := h.apiTokenService.Create(r.Context(), user.ID, req.Name, req.Scope, req.ExpiresInDays)
 	if err != nil {
-		log.Printf("Error creating api token: %v", err)
+		logError(r, "Error creating api token", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -84,7 +83,7 @@ func (h *ApiTokenHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	tokens, err := h.apiTokenService.List(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error listing api tokens: %v", err)
+		logError(r, "Error listing api tokens", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -123,7 +122,7 @@ func (h *ApiTokenHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error deleting api token: %v", err)
+		logError(r, "Error deleting api token", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -140,7 +139,7 @@ func (h *ApiTokenHandler) DeleteAll(w http.ResponseWriter, r *http.Request) {
 
 	err := h.apiTokenService.DeleteAll(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error deleting all api tokens: %v", err)
+		logError(r, "Error deleting all api tokens", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
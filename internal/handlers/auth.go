@@ -4,38 +4,62 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
 	"net/http"
 	"net/mail"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
 
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/i18n"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 const (
-	sessionCookieName = "session_token"
-	cookieMaxAge      = 30 * 24 * 60 * 60 // 30 days in seconds
+	sessionCookieName    = "session_token"
+	defaultSessionMaxAge = 30 * 24 * time.Hour
 )
 
 type AuthHandler struct {
-	userService  services.UserServiceInterface
-	authService  services.AuthServiceInterface
-	emailService services.EmailServiceInterface
-	secure       bool // Use secure cookies (HTTPS only)
+	userService   services.UserServiceInterface
+	authService   services.AuthServiceInterface
+	emailService  services.EmailServiceInterface
+	accountEvents services.AccountEventServiceInterface
+	throttle      services.ThrottleServiceInterface
+	secure        bool // Use secure cookies (HTTPS only)
+	sessionMaxAge time.Duration
 }
 
 func NewAuthHandler(userService services.UserServiceInterface, authService services.AuthServiceInterface, emailService services.EmailServiceInterface, secure bool) *AuthHandler {
 	return &AuthHandler{
-		userService:  userService,
-		authService:  authService,
-		emailService: emailService,
-		secure:       secure,
+		userService:   userService,
+		authService:   authService,
+		emailService:  emailService,
+		secure:        secure,
+		sessionMaxAge: defaultSessionMaxAge,
 	}
 }
 
+// SetSessionDuration sets the session cookie lifetime; it should match the
+// auth service's session duration.
+func (h *AuthHandler) SetSessionDuration(d time.Duration) {
+	h.sessionMaxAge = d
+}
+
+// SetThrottle enables brute-force protection on the login and recovery endpoints.
+func (h *AuthHandler) SetThrottle(throttle services.ThrottleServiceInterface) {
+	h.throttle = throttle
+}
+
+// SetAccountEventService enables the account activity log and new device alerts.
+func (h *AuthHandler) SetAccountEventService(accountEvents services.AccountEventServiceInterface) {
+	h.accountEvents = accountEvents
+}
+
 type RegisterRequest struct {
 	Email      string `json:"email"`
 	Password   string `json:"password"`
@@ -91,7 +115,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "Password is too long")
 			return
 		}
-		log.Printf("Error hashing password: %v", err)
+		logError(r, "Error hashing password", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -102,6 +126,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		PasswordHash: passwordHash,
 		Username:     req.Username,
 		Searchable:   req.Searchable,
+		Locale:       i18n.MatchAcceptLanguage(r.Header.Get("Accept-Language")),
 	})
 	if errors.Is(err, services.ErrEmailAlreadyExists) {
 		writeError(w, http.StatusConflict, "Email already registered")
@@ -112,7 +137,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error creating user: %v", err)
+		logError(r, "Error creating user", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -120,7 +145,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	// Create session
 	token, err := h.authService.CreateSession(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error creating session: %v", err)
+		logError(r, "Error creating session", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -130,11 +155,12 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	if h.emailService != nil {
 		go func() {
 			if err := h.emailService.SendVerificationEmail(context.Background(), user.ID, user.Email); err != nil {
-				log.Printf("Error sending verification email: %v", err)
+				logError(r, "Error sending verification email", err)
 			}
 		}()
 	}
 
+	h.recordLogin(r.Context(), user, models.AccountEventRegister)
 	h.setSessionCookie(w, token)
 	writeJSON(w, http.StatusCreated, AuthResponse{User: user})
 }
@@ -148,32 +174,49 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
 
+	target := services.ThrottleTarget{IP: getClientIP(r), Account: req.Email}
+	if checkThrottle(w, r, h.throttle, services.ThrottleLogin, target) {
+		return
+	}
+
 	// Get user by email
 	user, err := h.userService.GetByEmail(r.Context(), req.Email)
 	if errors.Is(err, services.ErrUserNotFound) {
+		failThrottle(r.Context(), h.throttle, services.ThrottleLogin, target)
 		writeError(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 	if err != nil {
-		log.Printf("Error getting user: %v", err)
+		logError(r, "Error getting user", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
 	// Verify password
 	if !h.authService.VerifyPassword(user.PasswordHash, req.Password) {
+		target.Email = user.Email
+		failThrottle(r.Context(), h.throttle, services.ThrottleLogin, target)
+		h.recordFailure(r.Context(), user.ID, models.AccountEventLogin, "invalid_password")
 		writeError(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
+	if user.IsDisabled() {
+		h.recordFailure(r.Context(), user.ID, models.AccountEventLogin, "account_disabled")
+		writeError(w, http.StatusForbidden, "Account disabled")
+		return
+	}
+
 	// Create session
 	token, err := h.authService.CreateSession(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error creating session: %v", err)
+		logError(r, "Error creating session", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	succeedThrottle(r.Context(), h.throttle, services.ThrottleLogin, target)
+	h.recordLogin(r.Context(), user, models.AccountEventLogin)
 	h.setSessionCookie(w, token)
 	writeJSON(w, http.StatusOK, AuthResponse{User: user})
 }
@@ -183,6 +226,9 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	if err == nil && cookie.Value != "" {
 		_ = h.authService.DeleteSession(r.Context(), cookie.Value)
 	}
+	if user := GetUserFromContext(r.Context()); user != nil {
+		h.recordEvent(r.Context(), user.ID, models.AccountEventLogout, models.AccountEventSuccess, nil)
+	}
 
 	h.clearSessionCookie(w)
 	writeJSON(w, http.StatusOK, AuthResponse{Message: "Logged out successfully"})
@@ -216,6 +262,7 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 	// Verify current password
 	if !h.authService.VerifyPassword(user.PasswordHash, req.CurrentPassword) {
+		h.recordFailure(r.Context(), user.ID, models.AccountEventPasswordChange, "invalid_password")
 		writeError(w, http.StatusUnauthorized, "Current password is incorrect")
 		return
 	}
@@ -233,25 +280,27 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "Password is too long")
 			return
 		}
-		log.Printf("Error hashing password: %v", err)
+		logError(r, "Error hashing password", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
 	// Update password
 	if err := h.userService.UpdatePassword(r.Context(), user.ID, newHash); err != nil {
-		log.Printf("Error updating password: %v", err)
+		logError(r, "Error updating password", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	h.recordEvent(r.Context(), user.ID, models.AccountEventPasswordChange, models.AccountEventSuccess, nil)
+
 	// Invalidate all other sessions
 	_ = h.authService.DeleteAllUserSessions(r.Context(), user.ID)
 
 	// Create new session
 	token, err := h.authService.CreateSession(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error creating session: %v", err)
+		logError(r, "Error creating session", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -275,7 +324,13 @@ func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	target := services.ThrottleTarget{IP: getClientIP(r)}
+	if checkThrottle(w, r, h.throttle, services.ThrottleVerifyEmail, target) {
+		return
+	}
+
 	if err := h.emailService.VerifyEmail(r.Context(), req.Token); err != nil {
+		failThrottle(r.Context(), h.throttle, services.ThrottleVerifyEmail, target)
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -297,7 +352,7 @@ func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := h.emailService.SendVerificationEmail(r.Context(), user.ID, user.Email); err != nil {
-		log.Printf("Error sending verification email: %v", err)
+		logError(r, "Error sending verification email", err)
 		writeError(w, http.StatusInternalServerError, "Failed to send verification email")
 		return
 	}
@@ -321,12 +376,16 @@ func (h *AuthHandler) MagicLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if hitThrottle(w, r, h.throttle, services.ThrottleMagicLink, services.ThrottleTarget{IP: getClientIP(r), Account: req.Email}) {
+		return
+	}
+
 	// Check if user exists - but always return success to prevent email enumeration
 	user, err := h.userService.GetByEmail(r.Context(), req.Email)
 	if err == nil && user != nil {
 		// User exists, send magic link
 		if err := h.emailService.SendMagicLinkEmail(r.Context(), req.Email); err != nil {
-			log.Printf("Error sending magic link email: %v", err)
+			logError(r, "Error sending magic link email", err)
 		}
 	}
 
@@ -354,11 +413,16 @@ func (h *AuthHandler) MagicLinkVerify(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "User not found")
 		return
 	}
+	if user.IsDisabled() {
+		h.recordFailure(r.Context(), user.ID, models.AccountEventMagicLinkLogin, "account_disabled")
+		writeError(w, http.StatusForbidden, "Account disabled")
+		return
+	}
 
 	// Mark email as verified since they clicked a link sent to their email
 	if !user.EmailVerified {
 		if err := h.userService.MarkEmailVerified(r.Context(), user.ID); err != nil {
-			log.Printf("Error marking email verified: %v", err)
+			logError(r, "Error marking email verified", err)
 		} else {
 			// Re-fetch user to get updated verification status
 			user, _ = h.userService.GetByID(r.Context(), user.ID)
@@ -368,11 +432,12 @@ func (h *AuthHandler) MagicLinkVerify(w http.ResponseWriter, r *http.Request) {
 	// Create session
 	sessionToken, err := h.authService.CreateSession(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error creating session: %v", err)
+		logError(r, "Error creating session", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	h.recordLogin(r.Context(), user, models.AccountEventMagicLinkLogin)
 	h.setSessionCookie(w, sessionToken)
 	writeJSON(w, http.StatusOK, AuthResponse{User: user})
 }
@@ -393,12 +458,16 @@ func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if hitThrottle(w, r, h.throttle, services.ThrottleForgotPassword, services.ThrottleTarget{IP: getClientIP(r), Account: req.Email}) {
+		return
+	}
+
 	// Check if user exists - but always return success to prevent email enumeration
 	user, err := h.userService.GetByEmail(r.Context(), req.Email)
 	if err == nil && user != nil {
 		// User exists, send reset email
 		if err := h.emailService.SendPasswordResetEmail(r.Context(), user.ID, user.Email); err != nil {
-			log.Printf("Error sending password reset email: %v", err)
+			logError(r, "Error sending password reset email", err)
 		}
 	}
 
@@ -428,13 +497,31 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	target := services.ThrottleTarget{IP: getClientIP(r)}
+	if checkThrottle(w, r, h.throttle, services.ThrottleResetPassword, target) {
+		return
+	}
+
 	// Verify token and get user ID
 	userID, err := h.emailService.VerifyPasswordResetToken(r.Context(), req.Token)
 	if err != nil {
+		failThrottle(r.Context(), h.throttle, services.ThrottleResetPassword, target)
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	user, err := h.userService.GetByID(r.Context(), userID)
+	if err != nil {
+		logError(r, "Error getting user", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if user.IsDisabled() {
+		h.recordFailure(r.Context(), user.ID, models.AccountEventPasswordReset, "account_disabled")
+		writeError(w, http.StatusForbidden, "Account disabled")
+		return
+	}
+
 	// Hash new password
 	passwordHash, err := h.authService.HashPassword(req.Password)
 	if err != nil {
@@ -442,21 +529,21 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "Password is too long")
 			return
 		}
-		log.Printf("Error hashing password: %v", err)
+		logError(r, "Error hashing password", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
 	// Update password
 	if err := h.userService.UpdatePassword(r.Context(), userID, passwordHash); err != nil {
-		log.Printf("Error updating password: %v", err)
+		logError(r, "Error updating password", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
 	// Mark token as used
 	if err := h.emailService.MarkPasswordResetUsed(r.Context(), req.Token); err != nil {
-		log.Printf("Error marking reset token as used: %v", err)
+		logError(r, "Error marking reset token as used", err)
 	}
 
 	// Invalidate all sessions
@@ -464,13 +551,13 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 
 	// Mark email as verified since they clicked a link sent to their email
 	if err := h.userService.MarkEmailVerified(r.Context(), userID); err != nil {
-		log.Printf("Error marking email verified: %v", err)
+		logError(r, "Error marking email verified", err)
 	}
 
-	// Get user for response
-	user, err := h.userService.GetByID(r.Context(), userID)
+	// Re-fetch user for response
+	user, err = h.userService.GetByID(r.Context(), userID)
 	if err != nil {
-		log.Printf("Error getting user: %v", err)
+		logError(r, "Error getting user", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -478,11 +565,12 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	// Create new session
 	sessionToken, err := h.authService.CreateSession(r.Context(), userID)
 	if err != nil {
-		log.Printf("Error creating session: %v", err)
+		logError(r, "Error creating session", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	h.recordLogin(r.Context(), user, models.AccountEventPasswordReset)
 	h.setSessionCookie(w, sessionToken)
 	writeJSON(w, http.StatusOK, AuthResponse{User: user, Message: "Password reset successfully"})
 }
@@ -505,15 +593,56 @@ func (h *AuthHandler) UpdateSearchable(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.userService.UpdateSearchable(r.Context(), user.ID, req.Searchable); err != nil {
-		log.Printf("Error updating searchable: %v", err)
+		logError(r, "Error updating searchable", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
+	h.recordEvent(r.Context(), user.ID, models.AccountEventPrivacyChanged, models.AccountEventSuccess, map[string]any{
+		"searchable": req.Searchable,
+	})
 
 	// Fetch updated user
 	updatedUser, err := h.userService.GetByID(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error getting user: %v", err)
+		logError(r, "Error getting user", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AuthResponse{User: updatedUser, Message: "Privacy settings updated"})
+}
+
+type UpdatePublicProfileRequest struct {
+	PublicProfile bool `json:"public_profile"`
+}
+
+// UpdatePublicProfile turns the user's public profile page at /u/{username}
+// on or off.
+func (h *AuthHandler) UpdatePublicProfile(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req UpdatePublicProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.userService.UpdatePublicProfile(r.Context(), user.ID, req.PublicProfile); err != nil {
+		logError(r, "Error updating public profile", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	h.recordEvent(r.Context(), user.ID, models.AccountEventPrivacyChanged, models.AccountEventSuccess, map[string]any{
+		"public_profile": req.PublicProfile,
+	})
+
+	updatedUser, err := h.userService.GetByID(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error getting user", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -521,12 +650,125 @@ func (h *AuthHandler) UpdateSearchable(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, AuthResponse{User: updatedUser, Message: "Privacy settings updated"})
 }
 
+type UpdateLeaderboardOptOutRequest struct {
+	LeaderboardOptOut bool `json:"leaderboard_opt_out"`
+}
+
+// UpdateLeaderboardOptOut hides the user from their friends' leaderboards, or
+// shows them again.
+func (h *AuthHandler) UpdateLeaderboardOptOut(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req UpdateLeaderboardOptOutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.userService.UpdateLeaderboardOptOut(r.Context(), user.ID, req.LeaderboardOptOut); err != nil {
+		logError(r, "Error updating leaderboard opt-out", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	h.recordEvent(r.Context(), user.ID, models.AccountEventPrivacyChanged, models.AccountEventSuccess, map[string]any{
+		"leaderboard_opt_out": req.LeaderboardOptOut,
+	})
+
+	updatedUser, err := h.userService.GetByID(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error getting user", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AuthResponse{User: updatedUser, Message: "Privacy settings updated"})
+}
+
+type AccountActivityResponse struct {
+	Events     []models.AccountEvent `json:"events"`
+	NextBefore *time.Time            `json:"next_before,omitempty"`
+}
+
+// Activity returns the current user's account event history, newest first.
+// Pass next_before back as ?before= to fetch the next page.
+func (h *AuthHandler) Activity(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.accountEvents == nil {
+		writeError(w, http.StatusServiceUnavailable, "Account activity is unavailable")
+		return
+	}
+
+	params := services.AccountEventListParams{Limit: 50}
+	query := r.URL.Query()
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 100 {
+			writeError(w, http.StatusBadRequest, "Limit must be between 1 and 100")
+			return
+		}
+		params.Limit = limit
+	}
+	if raw := query.Get("before"); raw != "" {
+		before, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid before cursor")
+			return
+		}
+		params.Before = &before
+	}
+
+	events, err := h.accountEvents.List(r.Context(), user.ID, params)
+	if err != nil {
+		logError(r, "Error listing account events", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := AccountActivityResponse{Events: events}
+	if len(events) == params.Limit {
+		resp.NextBefore = &events[len(events)-1].CreatedAt
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// recordEvent appends to the user's account activity. Recording never fails
+// the request it describes.
+func (h *AuthHandler) recordEvent(ctx context.Context, userID uuid.UUID, eventType models.AccountEventType, outcome models.AccountEventOutcome, details map[string]any) {
+	if h.accountEvents == nil {
+		return
+	}
+	if err := h.accountEvents.Record(ctx, userID, eventType, outcome, details); err != nil {
+		logging.FromContext(ctx).Error("Error recording account event", map[string]interface{}{"event_type": string(eventType), "error": err.Error()})
+	}
+}
+
+func (h *AuthHandler) recordFailure(ctx context.Context, userID uuid.UUID, eventType models.AccountEventType, reason string) {
+	h.recordEvent(ctx, userID, eventType, models.AccountEventFailure, map[string]any{"reason": reason})
+}
+
+func (h *AuthHandler) recordLogin(ctx context.Context, user *models.User, eventType models.AccountEventType) {
+	if h.accountEvents == nil || user == nil {
+		return
+	}
+	if err := h.accountEvents.RecordLogin(ctx, user, eventType); err != nil {
+		logging.FromContext(ctx).Error("Error recording account event", map[string]interface{}{"event_type": string(eventType), "error": err.Error()})
+	}
+}
+
 func (h *AuthHandler) setSessionCookie(w http.ResponseWriter, token string) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     sessionCookieName,
 		Value:    token,
 		Path:     "/",
-		MaxAge:   cookieMaxAge,
+		MaxAge:   int(h.sessionMaxAge.Seconds()),
 		HttpOnly: true,
 		Secure:   h.secure,
 		SameSite: http.SameSiteStrictMode,
@@ -582,3 +824,9 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, ErrorResponse{Error: message})
 }
+
+// logError logs err with the logger attached to the request, so the entry
+// carries the request ID.
+func logError(r *http.Request, msg string, err error) {
+	logging.FromContext(r.Context()).Error(msg, map[string]interface{}{"error": err.Error()})
+}
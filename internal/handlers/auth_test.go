@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -224,6 +225,48 @@ func TestAuthHandler_Register_Success(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Register_LocaleFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"ja, de;q=0.5", "de"},
+	}
+
+	for _, tt := range tests {
+		var gotLocale string
+		mockUser := &mockUserService{
+			CreateFunc: func(ctx context.Context, params models.CreateUserParams) (*models.User, error) {
+				gotLocale = params.Locale
+				return &models.User{ID: uuid.New(), Email: params.Email, Locale: params.Locale}, nil
+			},
+		}
+		mockAuth := &mockAuthService{
+			HashPasswordFunc:  func(password string) (string, error) { return "hashed_password", nil },
+			CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) { return "session-token", nil },
+		}
+		handler := NewAuthHandler(mockUser, mockAuth, nil, false)
+
+		bodyBytes, _ := json.Marshal(RegisterRequest{Email: "test@example.com", Password: "SecurePass123", Username: "testuser"})
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/register", bytes.NewBuffer(bodyBytes))
+		if tt.header != "" {
+			req.Header.Set("Accept-Language", tt.header)
+		}
+		rr := httptest.NewRecorder()
+
+		handler.Register(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d", rr.Code)
+		}
+		if gotLocale != tt.want {
+			t.Errorf("Accept-Language %q: expected locale %q, got %q", tt.header, tt.want, gotLocale)
+		}
+	}
+}
+
 func TestAuthHandler_Register_HashPasswordError(t *testing.T) {
 	mockAuth := &mockAuthService{
 		HashPasswordFunc: func(password string) (string, error) {
@@ -480,6 +523,35 @@ func TestAuthHandler_Login_InvalidPassword(t *testing.T) {
 	assertErrorResponse(t, rr, http.StatusUnauthorized, "Invalid email or password")
 }
 
+func TestAuthHandler_Login_DisabledAccount(t *testing.T) {
+	disabledAt := time.Now()
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: "stored-hash", DisabledAt: &disabledAt}
+	mockUser := &mockUserService{
+		GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			return user, nil
+		},
+	}
+	mockAuth := &mockAuthService{
+		VerifyPasswordFunc: func(hash, password string) bool { return true },
+		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) {
+			t.Fatal("session should not be created for a disabled account")
+			return "", nil
+		},
+	}
+
+	handler := NewAuthHandler(mockUser, mockAuth, nil, false)
+
+	body := LoginRequest{Email: "test@example.com", Password: "correct"}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewBuffer(bodyBytes))
+	rr := httptest.NewRecorder()
+
+	handler.Login(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusForbidden, "Account disabled")
+}
+
 func TestAuthHandler_Login_UserNotFound(t *testing.T) {
 	mockUser := &mockUserService{
 		GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
@@ -1076,6 +1148,112 @@ func TestAuthHandler_UpdateSearchable_GetByIDError(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_UpdatePublicProfile_Unauthenticated(t *testing.T) {
+	handler := NewAuthHandler(nil, nil, nil, false)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/public-profile", nil)
+	rr := httptest.NewRecorder()
+
+	handler.UpdatePublicProfile(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestAuthHandler_UpdatePublicProfile_InvalidBody(t *testing.T) {
+	handler := NewAuthHandler(nil, nil, nil, false)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/public-profile", bytes.NewBufferString("invalid"))
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.UpdatePublicProfile(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid request body")
+}
+
+func TestAuthHandler_UpdatePublicProfile_Success(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	var got bool
+	mockUser := &mockUserService{
+		UpdatePublicProfileFunc: func(ctx context.Context, userID uuid.UUID, public bool) error {
+			got = public
+			return nil
+		},
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.User, error) {
+			return &models.User{ID: id, PublicProfile: got}, nil
+		},
+	}
+	handler := NewAuthHandler(mockUser, &mockAuthService{}, &mockEmailService{}, false)
+
+	body := `{"public_profile": true}`
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/public-profile", bytes.NewBufferString(body))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.UpdatePublicProfile(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp AuthResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.User == nil || !resp.User.PublicProfile {
+		t.Fatalf("expected public profile to be enabled, got %+v", resp.User)
+	}
+}
+
+func TestAuthHandler_UpdatePublicProfile_UpdateError(t *testing.T) {
+	mockUser := &mockUserService{
+		UpdatePublicProfileFunc: func(ctx context.Context, userID uuid.UUID, public bool) error {
+			return errors.New("update error")
+		},
+	}
+	handler := NewAuthHandler(mockUser, &mockAuthService{}, &mockEmailService{}, false)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/public-profile", bytes.NewBufferString(`{"public_profile": false}`))
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.UpdatePublicProfile(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
+
+func TestAuthHandler_UpdateLeaderboardOptOut_Success(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	var got bool
+	mockUser := &mockUserService{
+		UpdateLeaderboardOptOutFunc: func(ctx context.Context, userID uuid.UUID, optOut bool) error {
+			got = optOut
+			return nil
+		},
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.User, error) {
+			return &models.User{ID: id, LeaderboardOptOut: got}, nil
+		},
+	}
+	handler := NewAuthHandler(mockUser, &mockAuthService{}, &mockEmailService{}, false)
+
+	body := `{"leaderboard_opt_out": true}`
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/leaderboard-opt-out", bytes.NewBufferString(body))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.UpdateLeaderboardOptOut(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp AuthResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.User == nil || !resp.User.LeaderboardOptOut {
+		t.Fatalf("expected leaderboard opt-out to be set, got %+v", resp.User)
+	}
+}
+
 func TestAuthHandler_SessionCookie_SecureMode(t *testing.T) {
 	handler := NewAuthHandler(nil, nil, nil, true)
 
@@ -1501,3 +1679,126 @@ func TestAuthHandler_VerifyEmail_Success(t *testing.T) {
 		t.Fatalf("expected status 200, got %d", rr.Code)
 	}
 }
+
+func TestAuthHandler_Login_RecordsAccountEvents(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: "stored-hash"}
+	valid := false
+	mockUser := &mockUserService{
+		GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			return user, nil
+		},
+	}
+	mockAuth := &mockAuthService{
+		VerifyPasswordFunc: func(hash, password string) bool { return valid },
+		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) {
+			return "session-token", nil
+		},
+	}
+	events := &mockAccountEventService{}
+	handler := NewAuthHandler(mockUser, mockAuth, nil, false)
+	handler.SetAccountEventService(events)
+
+	for _, v := range []bool{false, true} {
+		valid = v
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewBufferString(`{"email":"test@example.com","password":"x"}`))
+		handler.Login(httptest.NewRecorder(), req)
+	}
+
+	if len(events.recorded) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events.recorded)
+	}
+	failed, succeeded := events.recorded[0], events.recorded[1]
+	if failed.EventType != models.AccountEventLogin || failed.Outcome != models.AccountEventFailure || failed.Details["reason"] != "invalid_password" {
+		t.Fatalf("unexpected failure event: %+v", failed)
+	}
+	if succeeded.EventType != models.AccountEventLogin || succeeded.Outcome != models.AccountEventSuccess || succeeded.UserID != user.ID {
+		t.Fatalf("unexpected success event: %+v", succeeded)
+	}
+}
+
+func TestAuthHandler_UpdateSearchable_RecordsAccountEvent(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	events := &mockAccountEventService{}
+	handler := NewAuthHandler(&mockUserService{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.User, error) {
+			return user, nil
+		},
+	}, &mockAuthService{}, nil, false)
+	handler.SetAccountEventService(events)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/searchable", bytes.NewBufferString(`{"searchable":true}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, user))
+	rr := httptest.NewRecorder()
+	handler.UpdateSearchable(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if len(events.recorded) != 1 || events.recorded[0].EventType != models.AccountEventPrivacyChanged || events.recorded[0].Details["searchable"] != true {
+		t.Fatalf("unexpected events: %+v", events.recorded)
+	}
+}
+
+func TestAuthHandler_Activity_Success(t *testing.T) {
+	userID := uuid.New()
+	before := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	last := before.Add(-time.Hour)
+	handler := NewAuthHandler(&mockUserService{}, &mockAuthService{}, nil, false)
+	handler.SetAccountEventService(&mockAccountEventService{
+		ListFunc: func(ctx context.Context, gotUserID uuid.UUID, params services.AccountEventListParams) ([]models.AccountEvent, error) {
+			if gotUserID != userID || params.Limit != 2 || params.Before == nil || !params.Before.Equal(before) {
+				t.Fatalf("unexpected params: %s %+v", gotUserID, params)
+			}
+			return []models.AccountEvent{
+				{ID: uuid.New(), EventType: models.AccountEventLogin, CreatedAt: before.Add(-time.Minute)},
+				{ID: uuid.New(), EventType: models.AccountEventLogout, CreatedAt: last},
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/activity?limit=2&before="+before.Format(time.RFC3339Nano), nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr := httptest.NewRecorder()
+	handler.Activity(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp AccountActivityResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Events) != 2 || resp.NextBefore == nil || !resp.NextBefore.Equal(last) {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAuthHandler_Activity_Errors(t *testing.T) {
+	handler := NewAuthHandler(&mockUserService{}, &mockAuthService{}, nil, false)
+	handler.SetAccountEventService(&mockAccountEventService{
+		ListFunc: func(ctx context.Context, userID uuid.UUID, params services.AccountEventListParams) ([]models.AccountEvent, error) {
+			return nil, errors.New("boom")
+		},
+	})
+
+	tests := []struct {
+		url    string
+		user   bool
+		status int
+		msg    string
+	}{
+		{"/api/auth/activity", false, http.StatusUnauthorized, "Authentication required"},
+		{"/api/auth/activity?limit=0", true, http.StatusBadRequest, "Limit must be between 1 and 100"},
+		{"/api/auth/activity?before=yesterday", true, http.StatusBadRequest, "Invalid before cursor"},
+		{"/api/auth/activity", true, http.StatusInternalServerError, "Internal server error"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if tt.user {
+			req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+		}
+		rr := httptest.NewRecorder()
+		handler.Activity(rr, req)
+		assertErrorResponse(t, rr, tt.status, tt.msg)
+	}
+}
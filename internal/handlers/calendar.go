@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type CalendarHandler struct {
+	calendarService services.CalendarServiceInterface
+}
+
+func NewCalendarHandler(calendarService services.CalendarServiceInterface) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+type CalendarFeedResponse struct {
+	Feed    *models.CalendarFeed `json:"feed"`
+	Message string               `json:"message,omitempty"`
+}
+
+// GetFeed reports whether the user has a calendar feed. The URL is only
+// shown when the feed is created.
+func (h *CalendarHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	feed, err := h.calendarService.GetFeed(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error getting calendar feed", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CalendarFeedResponse{Feed: feed})
+}
+
+// CreateFeed creates the user's feed, replacing any existing feed URL.
+func (h *CalendarHandler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	feed, err := h.calendarService.CreateFeed(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error creating calendar feed", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CalendarFeedResponse{
+		Feed:    feed,
+		Message: "Keep this URL private. Anyone with it can see your goals.",
+	})
+}
+
+func (h *CalendarHandler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	err := h.calendarService.RevokeFeed(r.Context(), user.ID)
+	if errors.Is(err, services.ErrCalendarFeedNotFound) {
+		writeError(w, http.StatusNotFound, "Calendar feed not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error revoking calendar feed", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CalendarFeedResponse{Message: "Calendar feed revoked"})
+}
+
+// Feed serves the iCalendar file. It needs no session; calendar apps can't
+// sign in, so the token in the query string is the only credential.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	body, err := h.calendarService.Render(r.Context(), r.URL.Query().Get("token"), time.Now())
+	if errors.Is(err, services.ErrCalendarFeedNotFound) {
+		writeError(w, http.StatusNotFound, "Calendar feed not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error rendering calendar feed", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="yearofbingo.ics"`)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
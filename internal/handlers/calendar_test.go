@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type mockCalendarService struct {
+	CreateFeedFunc func(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error)
+	RevokeFeedFunc func(ctx context.Context, userID uuid.UUID) error
+	RenderFunc     func(ctx context.Context, token string, now time.Time) ([]byte, error)
+}
+
+func (m *mockCalendarService) GetFeed(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error) {
+	return nil, nil
+}
+
+func (m *mockCalendarService) CreateFeed(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error) {
+	if m.CreateFeedFunc != nil {
+		return m.CreateFeedFunc(ctx, userID)
+	}
+	return &models.CalendarFeed{URL: "https://example.com/api/calendar/feed.ics?token=abc", CreatedAt: time.Now()}, nil
+}
+
+func (m *mockCalendarService) RevokeFeed(ctx context.Context, userID uuid.UUID) error {
+	if m.RevokeFeedFunc != nil {
+		return m.RevokeFeedFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *mockCalendarService) Render(ctx context.Context, token string, now time.Time) ([]byte, error) {
+	if m.RenderFunc != nil {
+		return m.RenderFunc(ctx, token, now)
+	}
+	return []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil
+}
+
+func TestCalendarHandler_CreateFeed(t *testing.T) {
+	handler := NewCalendarHandler(&mockCalendarService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/calendar/feed", nil)
+	rr := httptest.NewRecorder()
+	handler.CreateFeed(rr, req)
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/calendar/feed", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr = httptest.NewRecorder()
+	handler.CreateFeed(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+	var resp CalendarFeedResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Feed == nil || resp.Feed.URL == "" {
+		t.Fatal("expected the feed URL in the response")
+	}
+}
+
+func TestCalendarHandler_RevokeFeed_NotFound(t *testing.T) {
+	handler := NewCalendarHandler(&mockCalendarService{
+		RevokeFeedFunc: func(ctx context.Context, userID uuid.UUID) error {
+			return services.ErrCalendarFeedNotFound
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/calendar/feed", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.RevokeFeed(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusNotFound, "Calendar feed not found")
+}
+
+func TestCalendarHandler_Feed(t *testing.T) {
+	var gotToken string
+	handler := NewCalendarHandler(&mockCalendarService{
+		RenderFunc: func(ctx context.Context, token string, now time.Time) ([]byte, error) {
+			gotToken = token
+			if token != "secret" {
+				return nil, services.ErrCalendarFeedNotFound
+			}
+			return []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/feed.ics?token=secret", nil)
+	rr := httptest.NewRecorder()
+	handler.Feed(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotToken != "secret" {
+		t.Fatalf("expected token from query string, got %q", gotToken)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/calendar/feed.ics?token=revoked", nil)
+	rr = httptest.NewRecorder()
+	handler.Feed(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "Calendar feed not found")
+}
+
+func TestCardHandler_UpdateSchedule_ErrorMapping(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"invalid date", services.ErrInvalidDueDate, http.StatusBadRequest},
+		{"invalid reminder", services.ErrInvalidReminder, http.StatusBadRequest},
+		{"item not found", services.ErrItemNotFound, http.StatusNotFound},
+		{"not owner", services.ErrNotCardOwner, http.StatusForbidden},
+		{"unexpected", errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCardHandler(&mockCardService{
+				UpdateItemScheduleFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, position int, params models.UpdateItemScheduleParams) (*models.BingoItem, error) {
+					return nil, tt.serviceErr
+				},
+			})
+
+			bodyBytes, _ := json.Marshal(UpdateScheduleRequest{DueDate: ptrToString("2025-06-01")})
+			req := httptest.NewRequest(http.MethodPut, "/api/cards/"+cardID.String()+"/items/1/due", bytes.NewBuffer(bodyBytes))
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+			handler.UpdateSchedule(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -67,17 +66,42 @@ type UpdateNotesRequest struct {
 	ProofURL *string `json:"proof_url,omitempty"`
 }
 
+// UpdateScheduleRequest sets an item's due date (YYYY-MM-DD). A null or
+// missing due_date clears the due date and reminder.
+type UpdateScheduleRequest struct {
+	DueDate            *string `json:"due_date"`
+	ReminderDaysBefore *int    `json:"reminder_days_before"`
+}
+
 type FinalizeRequest struct {
 	VisibleToFriends *bool `json:"visible_to_friends,omitempty"`
 }
 
+// UpdateVisibilityRequest sets a card's audience. Visibility takes
+// precedence; older clients send only VisibleToFriends.
 type UpdateVisibilityRequest struct {
-	VisibleToFriends bool `json:"visible_to_friends"`
+	Visibility       models.CardVisibility `json:"visibility,omitempty"`
+	GroupIDs         []uuid.UUID           `json:"group_ids,omitempty"`
+	VisibleToFriends bool                  `json:"visible_to_friends"`
 }
 
 type BulkUpdateVisibilityRequest struct {
-	CardIDs          []string `json:"card_ids"`
-	VisibleToFriends bool     `json:"visible_to_friends"`
+	CardIDs          []string              `json:"card_ids"`
+	Visibility       models.CardVisibility `json:"visibility,omitempty"`
+	GroupIDs         []uuid.UUID           `json:"group_ids,omitempty"`
+	VisibleToFriends bool                  `json:"visible_to_friends"`
+}
+
+func visibilityParams(visibility models.CardVisibility, groupIDs []uuid.UUID, visibleToFriends bool) models.CardVisibilityParams {
+	if visibility == "" {
+		visibility = models.VisibilityFromFlag(visibleToFriends)
+	}
+	return models.CardVisibilityParams{Visibility: visibility, GroupIDs: groupIDs}
+}
+
+type CardSharingResponse struct {
+	Card    *models.BingoCard   `json:"card,omitempty"`
+	Sharing *models.CardSharing `json:"sharing"`
 }
 
 type BulkUpdateVisibilityResponse struct {
@@ -135,12 +159,38 @@ type ExistingCardInfo struct {
 	IsFinalized bool   `json:"is_finalized"`
 }
 
+// writeCardExistsConflict answers a create that would clash with one of the
+// user's existing cards, describing that card so the client can offer to open
+// it or pick another title.
+func writeCardExistsConflict(w http.ResponseWriter, existingCard *models.BingoCard) {
+	title := ""
+	if existingCard.Title != nil {
+		title = *existingCard.Title
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(ImportCardResponse{
+		Error:   "card_exists",
+		Message: "You already have a card for this year",
+		ExistingCard: &ExistingCardInfo{
+			ID:          existingCard.ID.String(),
+			Title:       title,
+			Year:        existingCard.Year,
+			ItemCount:   len(existingCard.Items),
+			IsFinalized: existingCard.IsFinalized,
+		},
+	})
+}
+
 type CardResponse struct {
 	Card    *models.BingoCard   `json:"card,omitempty"`
 	Cards   []*models.BingoCard `json:"cards,omitempty"`
 	Item    *models.BingoItem   `json:"item,omitempty"`
 	Stats   *models.CardStats   `json:"stats,omitempty"`
 	Message string              `json:"message,omitempty"`
+	// Version is the card's version after an item edit, for responses
+	// that don't include the card itself.
+	Version int64 `json:"version,omitempty"`
 }
 
 func (h *CardHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -195,29 +245,12 @@ func (h *CardHandler) Create(w http.ResponseWriter, r *http.Request) {
 	// Check for existing card for this year/title before attempting create
 	existingCard, err := h.cardService.CheckForConflict(r.Context(), user.ID, req.Year, req.Title)
 	if err != nil && !errors.Is(err, services.ErrCardNotFound) {
-		log.Printf("Error checking for conflict: %v", err)
+		logError(r, "Error checking for conflict", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 	if existingCard != nil {
-		// Return conflict response with existing card info
-		title := ""
-		if existingCard.Title != nil {
-			title = *existingCard.Title
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusConflict)
-		json.NewEncoder(w).Encode(ImportCardResponse{
-			Error:   "card_exists",
-			Message: "You already have a card for this year",
-			ExistingCard: &ExistingCardInfo{
-				ID:          existingCard.ID.String(),
-				Title:       title,
-				Year:        existingCard.Year,
-				ItemCount:   len(existingCard.Items),
-				IsFinalized: existingCard.IsFinalized,
-			},
-		})
+		writeCardExistsConflict(w, existingCard)
 		return
 	}
 
@@ -256,7 +289,7 @@ func (h *CardHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error creating card: %v", err)
+		logError(r, "Error creating card", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -273,7 +306,7 @@ func (h *CardHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	cards, err := h.cardService.ListByUser(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error listing cards: %v", err)
+		logError(r, "Error listing cards", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -282,7 +315,7 @@ func (h *CardHandler) List(w http.ResponseWriter, r *http.Request) {
 		cards = []*models.BingoCard{}
 	}
 
-	writeJSON(w, http.StatusOK, CardResponse{Cards: cards})
+	writeCacheableJSON(w, r, cardsETag(cards), CardResponse{Cards: cards})
 }
 
 func (h *CardHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -304,7 +337,7 @@ func (h *CardHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error getting card: %v", err)
+		logError(r, "Error getting card", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -315,7 +348,7 @@ func (h *CardHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, CardResponse{Card: card})
+	writeCacheableJSON(w, r, cardsETag([]*models.BingoCard{card}), CardResponse{Card: card})
 }
 
 func (h *CardHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -332,6 +365,10 @@ func (h *CardHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.cardService.Delete(r.Context(), user.ID, cardID)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -341,7 +378,7 @@ func (h *CardHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error deleting card: %v", err)
+		logError(r, "Error deleting card", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -383,6 +420,10 @@ func (h *CardHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 		Content:  req.Content,
 		Position: req.Position,
 	})
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -408,12 +449,12 @@ func (h *CardHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error adding item: %v", err)
+		logError(r, "Error adding item", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, CardResponse{Item: item})
+	writeJSON(w, http.StatusCreated, CardResponse{Item: item, Version: editedCardVersion(r)})
 }
 
 type UpdateCardConfigRequest struct {
@@ -449,6 +490,10 @@ func (h *CardHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 		HeaderText:   req.HeaderText,
 		HasFreeSpace: req.HasFreeSpace,
 	})
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -470,7 +515,7 @@ func (h *CardHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error updating card config: %v", err)
+		logError(r, "Error updating card config", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -555,7 +600,7 @@ func (h *CardHandler) Clone(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error cloning card: %v", err)
+		logError(r, "Error cloning card", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -608,6 +653,10 @@ func (h *CardHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		Content:  req.Content,
 		Position: req.Position,
 	})
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -633,12 +682,12 @@ func (h *CardHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error updating item: %v", err)
+		logError(r, "Error updating item", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, CardResponse{Item: item})
+	writeJSON(w, http.StatusOK, CardResponse{Item: item, Version: editedCardVersion(r)})
 }
 
 func (h *CardHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
@@ -661,6 +710,10 @@ func (h *CardHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.cardService.RemoveItem(r.Context(), user.ID, cardID, position)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -678,12 +731,12 @@ func (h *CardHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error removing item: %v", err)
+		logError(r, "Error removing item", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, CardResponse{Message: "Item removed"})
+	writeJSON(w, http.StatusOK, CardResponse{Message: "Item removed", Version: editedCardVersion(r)})
 }
 
 func (h *CardHandler) Shuffle(w http.ResponseWriter, r *http.Request) {
@@ -700,6 +753,10 @@ func (h *CardHandler) Shuffle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	card, err := h.cardService.Shuffle(r.Context(), user.ID, cardID)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -713,7 +770,7 @@ func (h *CardHandler) Shuffle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error shuffling card: %v", err)
+		logError(r, "Error shuffling card", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -746,6 +803,10 @@ func (h *CardHandler) SwapItems(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.cardService.SwapItems(r.Context(), user.ID, cardID, req.Position1, req.Position2)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -771,12 +832,12 @@ func (h *CardHandler) SwapItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error swapping items: %v", err)
+		logError(r, "Error swapping items", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, CardResponse{Message: "Items swapped"})
+	writeJSON(w, http.StatusOK, CardResponse{Message: "Items swapped", Version: editedCardVersion(r)})
 }
 
 func (h *CardHandler) Finalize(w http.ResponseWriter, r *http.Request) {
@@ -806,6 +867,10 @@ func (h *CardHandler) Finalize(w http.ResponseWriter, r *http.Request) {
 	}
 
 	card, err := h.cardService.Finalize(r.Context(), user.ID, cardID, params)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -815,7 +880,7 @@ func (h *CardHandler) Finalize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error finalizing card: %v", err)
+		logError(r, "Error finalizing card", err)
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -854,6 +919,10 @@ func (h *CardHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
 		Notes:    req.Notes,
 		ProofURL: req.ProofURL,
 	})
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -871,12 +940,12 @@ func (h *CardHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error completing item: %v", err)
+		logError(r, "Error completing item", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, CardResponse{Item: item})
+	writeJSON(w, http.StatusOK, CardResponse{Item: item, Version: editedCardVersion(r)})
 }
 
 func (h *CardHandler) UncompleteItem(w http.ResponseWriter, r *http.Request) {
@@ -899,6 +968,10 @@ func (h *CardHandler) UncompleteItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	item, err := h.cardService.UncompleteItem(r.Context(), user.ID, cardID, position)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -916,12 +989,12 @@ func (h *CardHandler) UncompleteItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error uncompleting item: %v", err)
+		logError(r, "Error uncompleting item", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, CardResponse{Item: item})
+	writeJSON(w, http.StatusOK, CardResponse{Item: item, Version: editedCardVersion(r)})
 }
 
 func (h *CardHandler) UpdateNotes(w http.ResponseWriter, r *http.Request) {
@@ -950,6 +1023,10 @@ func (h *CardHandler) UpdateNotes(w http.ResponseWriter, r *http.Request) {
 	}
 
 	item, err := h.cardService.UpdateItemNotes(r.Context(), user.ID, cardID, position, req.Notes, req.ProofURL)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -963,12 +1040,74 @@ func (h *CardHandler) UpdateNotes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error updating notes: %v", err)
+		logError(r, "Error updating notes", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, CardResponse{Item: item})
+	writeJSON(w, http.StatusOK, CardResponse{Item: item, Version: editedCardVersion(r)})
+}
+
+func (h *CardHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	position, err := parsePosition(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid position")
+		return
+	}
+
+	var req UpdateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	item, err := h.cardService.UpdateItemSchedule(r.Context(), user.ID, cardID, position, models.UpdateItemScheduleParams{
+		DueDate:            req.DueDate,
+		ReminderDaysBefore: req.ReminderDaysBefore,
+	})
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
+	if errors.Is(err, services.ErrInvalidDueDate) {
+		writeError(w, http.StatusBadRequest, "Due date must be a valid date (YYYY-MM-DD)")
+		return
+	}
+	if errors.Is(err, services.ErrInvalidReminder) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Reminder must be between 0 and %d days before the due date", models.MaxReminderDaysBefore))
+		return
+	}
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrItemNotFound) {
+		writeError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if err != nil {
+		logError(r, "Error updating due date", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardResponse{Item: item, Version: editedCardVersion(r)})
 }
 
 func parseCardID(r *http.Request) (uuid.UUID, error) {
@@ -1004,7 +1143,7 @@ func (h *CardHandler) Archive(w http.ResponseWriter, r *http.Request) {
 
 	cards, err := h.cardService.GetArchive(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error getting archive: %v", err)
+		logError(r, "Error getting archive", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -1039,7 +1178,7 @@ func (h *CardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error getting stats: %v", err)
+		logError(r, "Error getting stats", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -1076,6 +1215,10 @@ func (h *CardHandler) UpdateMeta(w http.ResponseWriter, r *http.Request) {
 		Category: req.Category,
 		Title:    req.Title,
 	})
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -1097,7 +1240,7 @@ func (h *CardHandler) UpdateMeta(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error updating card meta: %v", err)
+		logError(r, "Error updating card meta", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -1124,7 +1267,51 @@ func (h *CardHandler) UpdateVisibility(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	card, err := h.cardService.UpdateVisibility(r.Context(), user.ID, cardID, req.VisibleToFriends)
+	card, err := h.cardService.UpdateVisibility(r.Context(), user.ID, cardID, visibilityParams(req.Visibility, req.GroupIDs, req.VisibleToFriends))
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
+	if errors.Is(err, services.ErrInvalidVisibility) {
+		writeError(w, http.StatusBadRequest, "Visibility must be private, friends, groups, organization, link or public; groups needs at least one group")
+		return
+	}
+	if errors.Is(err, services.ErrFriendGroupNotFound) {
+		writeError(w, http.StatusBadRequest, "Friend group not found")
+		return
+	}
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if err != nil {
+		logError(r, "Error updating visibility", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardResponse{Card: card})
+}
+
+// GetSharing returns who a card is shared with, for its owner.
+func (h *CardHandler) GetSharing(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	sharing, err := h.cardService.GetSharing(r.Context(), user.ID, cardID)
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -1134,11 +1321,29 @@ func (h *CardHandler) UpdateVisibility(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error updating visibility: %v", err)
+		logError(r, "Error getting card sharing", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	writeJSON(w, http.StatusOK, CardSharingResponse{Sharing: sharing})
+}
+
+// GetShared returns a card shared by link. It needs no session; the token in
+// the path is the only credential.
+func (h *CardHandler) GetShared(w http.ResponseWriter, r *http.Request) {
+	card, err := h.cardService.GetByShareToken(r.Context(), r.PathValue("token"))
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error getting shared card", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	redactHiddenItems(card)
 	writeJSON(w, http.StatusOK, CardResponse{Card: card})
 }
 
@@ -1171,9 +1376,17 @@ func (h *CardHandler) BulkUpdateVisibility(w http.ResponseWriter, r *http.Reques
 		cardIDs = append(cardIDs, id)
 	}
 
-	count, err := h.cardService.BulkUpdateVisibility(r.Context(), user.ID, cardIDs, req.VisibleToFriends)
+	count, err := h.cardService.BulkUpdateVisibility(r.Context(), user.ID, cardIDs, visibilityParams(req.Visibility, req.GroupIDs, req.VisibleToFriends))
+	if errors.Is(err, services.ErrInvalidVisibility) {
+		writeError(w, http.StatusBadRequest, "Visibility must be private, friends, groups, organization, link or public; groups needs at least one group")
+		return
+	}
+	if errors.Is(err, services.ErrFriendGroupNotFound) {
+		writeError(w, http.StatusBadRequest, "Friend group not found")
+		return
+	}
 	if err != nil {
-		log.Printf("Error bulk updating visibility: %v", err)
+		logError(r, "Error bulk updating visibility", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -1212,7 +1425,7 @@ func (h *CardHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
 
 	count, err := h.cardService.BulkDelete(r.Context(), user.ID, cardIDs)
 	if err != nil {
-		log.Printf("Error bulk deleting cards: %v", err)
+		logError(r, "Error bulk deleting cards", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -1251,7 +1464,7 @@ func (h *CardHandler) BulkUpdateArchive(w http.ResponseWriter, r *http.Request)
 
 	count, err := h.cardService.BulkUpdateArchive(r.Context(), user.ID, cardIDs, req.IsArchived)
 	if err != nil {
-		log.Printf("Error bulk updating archive status: %v", err)
+		logError(r, "Error bulk updating archive status", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -1280,7 +1493,7 @@ func (h *CardHandler) ListExportable(w http.ResponseWriter, r *http.Request) {
 	// Get current year cards
 	currentCards, err := h.cardService.ListByUser(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error listing current cards for export: %v", err)
+		logError(r, "Error listing current cards for export", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -1288,7 +1501,7 @@ func (h *CardHandler) ListExportable(w http.ResponseWriter, r *http.Request) {
 	// Get archived cards
 	archivedCards, err := h.cardService.GetArchive(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error listing archived cards for export: %v", err)
+		logError(r, "Error listing archived cards for export", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -1375,29 +1588,12 @@ func (h *CardHandler) Import(w http.ResponseWriter, r *http.Request) {
 	// Check for existing card for this year/title
 	existingCard, err := h.cardService.CheckForConflict(r.Context(), user.ID, req.Year, req.Title)
 	if err != nil && !errors.Is(err, services.ErrCardNotFound) {
-		log.Printf("Error checking for conflict: %v", err)
+		logError(r, "Error checking for conflict", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 	if existingCard != nil {
-		// Return conflict response
-		title := ""
-		if existingCard.Title != nil {
-			title = *existingCard.Title
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusConflict)
-		json.NewEncoder(w).Encode(ImportCardResponse{
-			Error:   "card_exists",
-			Message: "You already have a card for this year",
-			ExistingCard: &ExistingCardInfo{
-				ID:          existingCard.ID.String(),
-				Title:       title,
-				Year:        existingCard.Year,
-				ItemCount:   len(existingCard.Items),
-				IsFinalized: existingCard.IsFinalized,
-			},
-		})
+		writeCardExistsConflict(w, existingCard)
 		return
 	}
 
@@ -1444,7 +1640,7 @@ func (h *CardHandler) Import(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error importing card: %v", err)
+		logError(r, "Error importing card", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -468,7 +468,7 @@ func TestCardHandler_GetDeleteStatsAndVisibility_ServiceErrors(t *testing.T) {
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
 				mockCard := &mockCardService{
-					UpdateVisibilityFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error) {
+					UpdateVisibilityFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error) {
 						return nil, tt.serviceErr
 					},
 				}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type CardHistoryResponse struct {
+	Revisions []models.CardRevision `json:"revisions"`
+}
+
+type CardTrashResponse struct {
+	Cards []models.TrashedCard `json:"cards"`
+}
+
+// History lists a card's recent edits, newest first.
+func (h *CardHandler) History(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	revisions, err := h.cardService.History(r.Context(), user.ID, cardID)
+	if err != nil {
+		writeCardHistoryError(w, r, err, "listing card history")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardHistoryResponse{Revisions: revisions})
+}
+
+// Undo reverts the card's most recent edit that has not been undone yet.
+func (h *CardHandler) Undo(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	card, err := h.cardService.Undo(r.Context(), user.ID, cardID)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
+	if err != nil {
+		writeCardHistoryError(w, r, err, "undoing card edit")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardResponse{Card: card})
+}
+
+// RestoreRevision puts the card back to how it looked after a revision.
+func (h *CardHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+	revisionID, err := uuid.Parse(r.PathValue("revisionId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid revision ID")
+		return
+	}
+
+	card, err := h.cardService.RestoreRevision(r.Context(), user.ID, cardID, revisionID)
+	if errors.Is(err, services.ErrCardVersionConflict) {
+		h.writeVersionConflict(w, r, cardID)
+		return
+	}
+	if err != nil {
+		writeCardHistoryError(w, r, err, "restoring card revision")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardResponse{Card: card})
+}
+
+// Trash lists the user's deleted cards that can still be restored.
+func (h *CardHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cards, err := h.cardService.ListTrash(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error listing trash", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardTrashResponse{Cards: cards})
+}
+
+// RestoreFromTrash brings back a deleted card.
+func (h *CardHandler) RestoreFromTrash(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	card, err := h.cardService.RestoreFromTrash(r.Context(), user.ID, cardID)
+	if err != nil {
+		writeCardHistoryError(w, r, err, "restoring card from trash")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardResponse{Card: card, Message: "Card restored"})
+}
+
+func writeCardHistoryError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrCardNotFound):
+		writeError(w, http.StatusNotFound, "Card not found")
+	case errors.Is(err, services.ErrNotCardOwner):
+		writeError(w, http.StatusForbidden, "Access denied")
+	case errors.Is(err, services.ErrCardFinalized):
+		writeError(w, http.StatusBadRequest, "Card is finalized; edits made while it was a draft can't be undone")
+	case errors.Is(err, services.ErrRevisionNotFound):
+		writeError(w, http.StatusNotFound, "Revision not found")
+	case errors.Is(err, services.ErrNothingToUndo):
+		writeError(w, http.StatusConflict, "Nothing to undo")
+	case errors.Is(err, services.ErrTrashedCardNotFound):
+		writeError(w, http.StatusNotFound, "Deleted card not found")
+	case errors.Is(err, services.ErrCardTitleExists):
+		writeError(w, http.StatusConflict, "You already have a card with this title for this year")
+	case errors.Is(err, services.ErrCardAlreadyExists):
+		writeError(w, http.StatusConflict, "You already have a card for this year. Give your new card a unique title.")
+	default:
+		logError(r, "Error "+action, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestCardHandler_History_Unauthenticated(t *testing.T) {
+	handler := NewCardHandler(&mockCardService{})
+
+	rr := httptest.NewRecorder()
+	handler.History(rr, httptest.NewRequest(http.MethodGet, "/api/cards/"+uuid.New().String()+"/history", nil))
+
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestCardHandler_History_Success(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	handler := NewCardHandler(&mockCardService{
+		HistoryFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) ([]models.CardRevision, error) {
+			if userID != user.ID || gotCardID != cardID {
+				t.Fatalf("unexpected ids: %s %s", userID, gotCardID)
+			}
+			return []models.CardRevision{{ID: uuid.New(), CardID: cardID, Action: models.RevisionShuffle}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/history", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.History(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp CardHistoryResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Revisions) != 1 || resp.Revisions[0].Action != models.RevisionShuffle {
+		t.Fatalf("unexpected revisions: %+v", resp.Revisions)
+	}
+}
+
+func TestCardHandler_Undo_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "nothing to undo", err: services.ErrNothingToUndo, wantStatus: http.StatusConflict, wantMsg: "Nothing to undo"},
+		{name: "finalized", err: services.ErrCardFinalized, wantStatus: http.StatusBadRequest, wantMsg: "Card is finalized; edits made while it was a draft can't be undone"},
+		{name: "not owner", err: services.ErrNotCardOwner, wantStatus: http.StatusForbidden, wantMsg: "Access denied"},
+		{name: "title taken", err: services.ErrCardTitleExists, wantStatus: http.StatusConflict, wantMsg: "You already have a card with this title for this year"},
+		{name: "internal", err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantMsg: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCardHandler(&mockCardService{
+				UndoFunc: func(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+					return nil, tt.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/cards/"+uuid.New().String()+"/undo", nil)
+			req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+			rr := httptest.NewRecorder()
+
+			handler.Undo(rr, req)
+
+			assertErrorResponse(t, rr, tt.wantStatus, tt.wantMsg)
+		})
+	}
+}
+
+func TestCardHandler_RestoreRevision_InvalidRevisionID(t *testing.T) {
+	handler := NewCardHandler(&mockCardService{})
+
+	cardID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/cards/"+cardID.String()+"/history/nope/restore", nil)
+	req.SetPathValue("revisionId", "nope")
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.RestoreRevision(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid revision ID")
+}
+
+func TestCardHandler_RestoreRevision_NotFound(t *testing.T) {
+	revisionID := uuid.New()
+	handler := NewCardHandler(&mockCardService{
+		RestoreRevisionFunc: func(ctx context.Context, userID, cardID, gotRevisionID uuid.UUID) (*models.BingoCard, error) {
+			if gotRevisionID != revisionID {
+				t.Fatalf("unexpected revision id: %s", gotRevisionID)
+			}
+			return nil, services.ErrRevisionNotFound
+		},
+	})
+
+	cardID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/cards/"+cardID.String()+"/history/"+revisionID.String()+"/restore", nil)
+	req.SetPathValue("revisionId", revisionID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.RestoreRevision(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusNotFound, "Revision not found")
+}
+
+func TestCardHandler_Trash_Success(t *testing.T) {
+	handler := NewCardHandler(&mockCardService{
+		ListTrashFunc: func(ctx context.Context, userID uuid.UUID) ([]models.TrashedCard, error) {
+			return []models.TrashedCard{{ID: uuid.New(), Year: 2024, ItemCount: 3}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/trash", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.Trash(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp CardTrashResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Cards) != 1 || resp.Cards[0].ItemCount != 3 {
+		t.Fatalf("unexpected trash: %+v", resp.Cards)
+	}
+}
+
+func TestCardHandler_RestoreFromTrash(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "restored", wantStatus: http.StatusOK},
+		{name: "not found", err: services.ErrTrashedCardNotFound, wantStatus: http.StatusNotFound},
+		{name: "conflict", err: services.ErrCardAlreadyExists, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cardID := uuid.New()
+			handler := NewCardHandler(&mockCardService{
+				RestoreFromTrashFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.BingoCard, error) {
+					if gotCardID != cardID {
+						t.Fatalf("unexpected card id: %s", gotCardID)
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &models.BingoCard{ID: cardID}, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/cards/trash/"+cardID.String()+"/restore", nil)
+			req.SetPathValue("id", cardID.String())
+			req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+			rr := httptest.NewRecorder()
+
+			handler.RestoreFromTrash(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
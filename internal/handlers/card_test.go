@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -597,7 +598,7 @@ func TestCardHandler_BulkUpdateVisibility_InvalidCardID(t *testing.T) {
 func TestCardHandler_BulkUpdateVisibility_ServiceError(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	mockCard := &mockCardService{
-		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error) {
+		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, params models.CardVisibilityParams) (int, error) {
 			return 0, errors.New("bulk visibility error")
 		},
 	}
@@ -1126,8 +1127,8 @@ func TestCardHandler_StatsAndUpdateVisibility_Success(t *testing.T) {
 		GetStatsFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.CardStats, error) {
 			return &models.CardStats{TotalItems: 10, CompletedItems: 3}, nil
 		},
-		UpdateVisibilityFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error) {
-			return &models.BingoCard{ID: gotCardID, UserID: userID, VisibleToFriends: visibleToFriends}, nil
+		UpdateVisibilityFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error) {
+			return &models.BingoCard{ID: gotCardID, UserID: userID, Visibility: params.Visibility, VisibleToFriends: params.Visibility.VisibleToAllFriends()}, nil
 		},
 	}
 	handler := NewCardHandler(mockCard)
@@ -1185,7 +1186,7 @@ func TestCardHandler_ListGetDeleteAndOtherEndpoints_Success(t *testing.T) {
 		GetArchiveFunc: func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
 			return nil, nil
 		},
-		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error) {
+		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, params models.CardVisibilityParams) (int, error) {
 			return len(cardIDs), nil
 		},
 		BulkDeleteFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
@@ -1488,3 +1489,140 @@ func TestParsePosition(t *testing.T) {
 		})
 	}
 }
+
+func TestCardHandler_UpdateVisibility_Groups(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	groupID := uuid.New()
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", wantStatus: http.StatusOK},
+		{name: "invalid", serviceErr: services.ErrInvalidVisibility, wantStatus: http.StatusBadRequest},
+		{name: "foreign group", serviceErr: services.ErrFriendGroupNotFound, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCard := &mockCardService{
+				UpdateVisibilityFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error) {
+					if params.Visibility != models.VisibilityGroups || len(params.GroupIDs) != 1 || params.GroupIDs[0] != groupID {
+						t.Fatalf("unexpected params: %+v", params)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &models.BingoCard{ID: gotCardID, UserID: userID, Visibility: params.Visibility}, nil
+				},
+			}
+			handler := NewCardHandler(mockCard)
+
+			bodyBytes, _ := json.Marshal(UpdateVisibilityRequest{Visibility: models.VisibilityGroups, GroupIDs: []uuid.UUID{groupID}})
+			req := httptest.NewRequest(http.MethodPut, "/api/cards/"+cardID.String()+"/visibility", bytes.NewBuffer(bodyBytes))
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+			handler.UpdateVisibility(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestCardHandler_UpdateVisibility_LegacyFlag(t *testing.T) {
+	var got models.CardVisibility
+	mockCard := &mockCardService{
+		UpdateVisibilityFunc: func(ctx context.Context, userID, cardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error) {
+			got = params.Visibility
+			return &models.BingoCard{ID: cardID, UserID: userID}, nil
+		},
+	}
+	handler := NewCardHandler(mockCard)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/"+uuid.New().String()+"/visibility", bytes.NewBufferString(`{"visible_to_friends":false}`))
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.UpdateVisibility(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if got != models.VisibilityPrivate {
+		t.Fatalf("expected private visibility from flag, got %q", got)
+	}
+}
+
+func TestCardHandler_GetSharing(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	token := "abc123"
+	mockCard := &mockCardService{
+		GetSharingFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.CardSharing, error) {
+			if userID != user.ID {
+				return nil, services.ErrNotCardOwner
+			}
+			return &models.CardSharing{Visibility: models.VisibilityLink, GroupIDs: []uuid.UUID{}, ShareToken: &token}, nil
+		},
+	}
+	handler := NewCardHandler(mockCard)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/sharing", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.GetSharing(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"share_token":"abc123"`) {
+		t.Fatalf("expected share token in body, got %s", rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/sharing", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr = httptest.NewRecorder()
+	handler.GetSharing(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestCardHandler_GetShared(t *testing.T) {
+	hiddenAt := time.Now()
+	mockCard := &mockCardService{
+		GetByShareTokenFunc: func(ctx context.Context, token string) (*models.BingoCard, error) {
+			if token != "good" {
+				return nil, services.ErrCardNotFound
+			}
+			return &models.BingoCard{
+				ID:          uuid.New(),
+				IsFinalized: true,
+				Visibility:  models.VisibilityLink,
+				Items:       []models.BingoItem{{Position: 0, Content: "secret", HiddenAt: &hiddenAt}},
+			}, nil
+		},
+	}
+	handler := NewCardHandler(mockCard)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/shared/good", nil)
+	req.SetPathValue("token", "good")
+	rr := httptest.NewRecorder()
+	handler.GetShared(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "secret") {
+		t.Fatal("expected hidden items to be redacted")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/shared/bad", nil)
+	req.SetPathValue("token", "bad")
+	rr = httptest.NewRecorder()
+	handler.GetShared(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
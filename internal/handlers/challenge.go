@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type ChallengeHandler struct {
+	challengeService services.ChallengeServiceInterface
+}
+
+func NewChallengeHandler(challengeService services.ChallengeServiceInterface) *ChallengeHandler {
+	return &ChallengeHandler{challengeService: challengeService}
+}
+
+type CreateChallengeRequest struct {
+	Name      string    `json:"name"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	FriendIDs []string  `json:"friend_ids"`
+}
+
+type ChallengeResponse struct {
+	Challenge *models.Challenge `json:"challenge,omitempty"`
+	Message   string            `json:"message,omitempty"`
+}
+
+type ChallengeDetailResponse struct {
+	Challenge *models.ChallengeDetail `json:"challenge"`
+}
+
+type ChallengeListResponse struct {
+	Challenges []models.Challenge `json:"challenges"`
+}
+
+func (h *ChallengeHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	challenges, err := h.challengeService.List(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error listing challenges", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ChallengeListResponse{Challenges: challenges})
+}
+
+func (h *ChallengeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req CreateChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	friendIDs := make([]uuid.UUID, 0, len(req.FriendIDs))
+	for _, raw := range req.FriendIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid friend ID")
+			return
+		}
+		friendIDs = append(friendIDs, id)
+	}
+
+	challenge, err := h.challengeService.Create(r.Context(), user.ID, models.CreateChallengeParams{
+		Name:      req.Name,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		FriendIDs: friendIDs,
+	})
+	if err != nil {
+		writeChallengeError(w, r, err, "creating challenge")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, ChallengeResponse{Challenge: challenge})
+}
+
+func (h *ChallengeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	challengeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid challenge ID")
+		return
+	}
+
+	challenge, err := h.challengeService.Get(r.Context(), user.ID, challengeID)
+	if err != nil {
+		writeChallengeError(w, r, err, "getting challenge")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ChallengeDetailResponse{Challenge: challenge})
+}
+
+func (h *ChallengeHandler) Join(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	challengeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid challenge ID")
+		return
+	}
+
+	if err := h.challengeService.Join(r.Context(), user.ID, challengeID); err != nil {
+		writeChallengeError(w, r, err, "joining challenge")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ChallengeResponse{Message: "Joined challenge"})
+}
+
+func (h *ChallengeHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	challengeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid challenge ID")
+		return
+	}
+
+	if err := h.challengeService.Leave(r.Context(), user.ID, challengeID); err != nil {
+		writeChallengeError(w, r, err, "leaving challenge")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ChallengeResponse{Message: "Left challenge"})
+}
+
+func (h *ChallengeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	challengeID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid challenge ID")
+		return
+	}
+
+	if err := h.challengeService.Delete(r.Context(), user.ID, challengeID); err != nil {
+		writeChallengeError(w, r, err, "deleting challenge")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ChallengeResponse{Message: "Challenge deleted"})
+}
+
+func writeChallengeError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidChallengeName):
+		writeError(w, http.StatusBadRequest, "Challenge name must be 1-100 characters")
+	case errors.Is(err, services.ErrInvalidChallengeDates):
+		writeError(w, http.StatusBadRequest, "Challenge must end after it starts, end in the future and last at most a year")
+	case errors.Is(err, services.ErrTooManyParticipants):
+		writeError(w, http.StatusBadRequest, "A challenge can have at most 20 participants")
+	case errors.Is(err, services.ErrNotFriend):
+		writeError(w, http.StatusBadRequest, "Only friends can be invited to a challenge")
+	case errors.Is(err, services.ErrChallengeNotFound):
+		writeError(w, http.StatusNotFound, "Challenge not found")
+	case errors.Is(err, services.ErrChallengeEnded):
+		writeError(w, http.StatusConflict, "This challenge has already ended")
+	case errors.Is(err, services.ErrChallengeCreatorLeave):
+		writeError(w, http.StatusBadRequest, "Delete the challenge instead of leaving it")
+	case errors.Is(err, services.ErrNotChallengeCreator):
+		writeError(w, http.StatusForbidden, "Only the creator can delete this challenge")
+	default:
+		logError(r, "Error "+action, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
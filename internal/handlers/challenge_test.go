@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestChallengeHandler_List_Unauthenticated(t *testing.T) {
+	handler := NewChallengeHandler(&mockChallengeService{})
+
+	rr := httptest.NewRecorder()
+	handler.List(rr, httptest.NewRequest(http.MethodGet, "/api/challenges", nil))
+
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestChallengeHandler_Create_InvalidFriendID(t *testing.T) {
+	handler := NewChallengeHandler(&mockChallengeService{
+		CreateFunc: func(ctx context.Context, creatorID uuid.UUID, params models.CreateChallengeParams) (*models.Challenge, error) {
+			t.Fatal("service should not be called")
+			return nil, nil
+		},
+	})
+
+	body := `{"name":"Spring sprint","starts_at":"2025-03-01T00:00:00Z","ends_at":"2025-03-31T00:00:00Z","friend_ids":["nope"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/challenges", bytes.NewBufferString(body))
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.Create(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid friend ID")
+}
+
+func TestChallengeHandler_Create(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	friendID := uuid.New()
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "created", wantStatus: http.StatusCreated},
+		{name: "invalid dates", serviceErr: services.ErrInvalidChallengeDates, wantStatus: http.StatusBadRequest},
+		{name: "not friends", serviceErr: services.ErrNotFriend, wantStatus: http.StatusBadRequest},
+		{name: "too many", serviceErr: services.ErrTooManyParticipants, wantStatus: http.StatusBadRequest},
+		{name: "internal", serviceErr: errors.New("boom"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewChallengeHandler(&mockChallengeService{
+				CreateFunc: func(ctx context.Context, creatorID uuid.UUID, params models.CreateChallengeParams) (*models.Challenge, error) {
+					if creatorID != user.ID || params.Name != "Spring sprint" {
+						t.Fatalf("unexpected args: %s %+v", creatorID, params)
+					}
+					if len(params.FriendIDs) != 1 || params.FriendIDs[0] != friendID {
+						t.Fatalf("unexpected friend ids: %v", params.FriendIDs)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &models.Challenge{ID: uuid.New(), Name: params.Name}, nil
+				},
+			})
+
+			body, _ := json.Marshal(map[string]any{
+				"name":       "Spring sprint",
+				"starts_at":  "2025-03-01T00:00:00Z",
+				"ends_at":    "2025-03-31T00:00:00Z",
+				"friend_ids": []string{friendID.String()},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/challenges", bytes.NewBuffer(body))
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			handler.Create(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestChallengeHandler_Get(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	challengeID := uuid.New()
+	handler := NewChallengeHandler(&mockChallengeService{
+		GetFunc: func(ctx context.Context, userID, id uuid.UUID) (*models.ChallengeDetail, error) {
+			if id != challengeID {
+				return nil, services.ErrChallengeNotFound
+			}
+			return &models.ChallengeDetail{
+				Challenge: models.Challenge{ID: id, Name: "Spring sprint"},
+				Standings: []models.ChallengeStanding{{Rank: 1, UserID: userID, Username: "me", Completions: 2}},
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/challenges/"+challengeID.String(), nil)
+	req.SetPathValue("id", challengeID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Get(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp ChallengeDetailResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Challenge == nil || len(resp.Challenge.Standings) != 1 || resp.Challenge.Standings[0].Completions != 2 {
+		t.Fatalf("unexpected challenge: %+v", resp.Challenge)
+	}
+
+	missing := uuid.New().String()
+	req = httptest.NewRequest(http.MethodGet, "/api/challenges/"+missing, nil)
+	req.SetPathValue("id", missing)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+
+	handler.Get(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusNotFound, "Challenge not found")
+}
+
+func TestChallengeHandler_Join_Ended(t *testing.T) {
+	handler := NewChallengeHandler(&mockChallengeService{
+		JoinFunc: func(ctx context.Context, userID, challengeID uuid.UUID) error {
+			return services.ErrChallengeEnded
+		},
+	})
+
+	id := uuid.New().String()
+	req := httptest.NewRequest(http.MethodPost, "/api/challenges/"+id+"/join", nil)
+	req.SetPathValue("id", id)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.Join(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusConflict, "This challenge has already ended")
+}
+
+func TestChallengeHandler_Leave_Creator(t *testing.T) {
+	handler := NewChallengeHandler(&mockChallengeService{
+		LeaveFunc: func(ctx context.Context, userID, challengeID uuid.UUID) error {
+			return services.ErrChallengeCreatorLeave
+		},
+	})
+
+	id := uuid.New().String()
+	req := httptest.NewRequest(http.MethodPost, "/api/challenges/"+id+"/leave", nil)
+	req.SetPathValue("id", id)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.Leave(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Delete the challenge instead of leaving it")
+}
+
+func TestChallengeHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathID     string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "deleted", pathID: uuid.New().String(), wantStatus: http.StatusOK},
+		{name: "invalid id", pathID: "bad", wantStatus: http.StatusBadRequest},
+		{name: "not creator", pathID: uuid.New().String(), serviceErr: services.ErrNotChallengeCreator, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewChallengeHandler(&mockChallengeService{
+				DeleteFunc: func(ctx context.Context, userID, challengeID uuid.UUID) error {
+					return tt.serviceErr
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/challenges/"+tt.pathID, nil)
+			req.SetPathValue("id", tt.pathID)
+			req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+			rr := httptest.NewRecorder()
+
+			handler.Delete(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
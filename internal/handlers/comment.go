@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type CommentHandler struct {
+	commentService services.CommentServiceInterface
+}
+
+func NewCommentHandler(commentService services.CommentServiceInterface) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+type CreateCommentRequest struct {
+	Body     string     `json:"body"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+type CommentResponse struct {
+	Comment  *models.ItemComment  `json:"comment,omitempty"`
+	Comments []models.ItemComment `json:"comments,omitempty"`
+	Message  string               `json:"message,omitempty"`
+}
+
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	itemID, err := parseItemID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	comments, err := h.commentService.List(r.Context(), user.ID, itemID)
+	if err != nil {
+		if !writeCommentError(w, err) {
+			logError(r, "Error listing comments", err)
+			writeError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CommentResponse{Comments: comments})
+}
+
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	itemID, err := parseItemID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	comment, err := h.commentService.Create(r.Context(), user.ID, itemID, req.ParentID, req.Body)
+	if err != nil {
+		if !writeCommentError(w, err) {
+			logError(r, "Error creating comment", err)
+			writeError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CommentResponse{Comment: comment})
+}
+
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	commentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	comment, err := h.commentService.Update(r.Context(), user.ID, commentID, req.Body)
+	if err != nil {
+		if !writeCommentError(w, err) {
+			logError(r, "Error updating comment", err)
+			writeError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CommentResponse{Comment: comment})
+}
+
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	commentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	if err := h.commentService.Delete(r.Context(), user.ID, commentID); err != nil {
+		if !writeCommentError(w, err) {
+			logError(r, "Error deleting comment", err)
+			writeError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CommentResponse{Message: "Comment deleted"})
+}
+
+// writeCommentError maps comment service errors to responses. It reports
+// whether the error was handled.
+func writeCommentError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrCommentEmpty):
+		writeError(w, http.StatusBadRequest, "Comment cannot be empty")
+	case errors.Is(err, services.ErrCommentTooLong):
+		writeError(w, http.StatusBadRequest, "Comment must be 1000 characters or fewer")
+	case errors.Is(err, services.ErrItemNotFound):
+		writeError(w, http.StatusNotFound, "Item not found")
+	case errors.Is(err, services.ErrCommentNotFound):
+		writeError(w, http.StatusNotFound, "Comment not found")
+	case errors.Is(err, services.ErrNotFriend):
+		writeError(w, http.StatusForbidden, "You must be friends to comment")
+	case errors.Is(err, services.ErrUserBlocked):
+		writeError(w, http.StatusForbidden, "You cannot comment here")
+	case errors.Is(err, services.ErrNotCommentAuthor):
+		writeError(w, http.StatusForbidden, "Only the author can edit this comment")
+	case errors.Is(err, services.ErrCommentNotPermitted):
+		writeError(w, http.StatusForbidden, "Not allowed to delete this comment")
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestCommentHandler_Create_Unauthenticated(t *testing.T) {
+	handler := NewCommentHandler(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/items/"+uuid.New().String()+"/comments", nil)
+	rr := httptest.NewRecorder()
+
+	handler.Create(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", rr.Code)
+	}
+}
+
+func TestCommentHandler_Create(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	itemID := uuid.New()
+	parentID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := &mockCommentService{
+			CreateFunc: func(ctx context.Context, userID, gotItemID uuid.UUID, gotParentID *uuid.UUID, body string) (*models.ItemComment, error) {
+				if gotItemID != itemID {
+					t.Fatalf("unexpected item id")
+				}
+				if gotParentID == nil || *gotParentID != parentID {
+					t.Fatalf("expected parent id to be passed through")
+				}
+				return &models.ItemComment{ItemID: gotItemID, UserID: userID, ParentID: gotParentID, Body: body}, nil
+			},
+		}
+		handler := NewCommentHandler(mockSvc)
+
+		bodyBytes, _ := json.Marshal(CreateCommentRequest{Body: "Go you!", ParentID: &parentID})
+		req := httptest.NewRequest(http.MethodPost, "/api/items/"+itemID.String()+"/comments", bytes.NewBuffer(bodyBytes))
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+
+		handler.Create(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d", rr.Code)
+		}
+		var resp CommentResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if resp.Comment == nil || resp.Comment.Body != "Go you!" {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "empty", err: services.ErrCommentEmpty, status: http.StatusBadRequest},
+		{name: "too long", err: services.ErrCommentTooLong, status: http.StatusBadRequest},
+		{name: "item not found", err: services.ErrItemNotFound, status: http.StatusNotFound},
+		{name: "parent not found", err: services.ErrCommentNotFound, status: http.StatusNotFound},
+		{name: "not friend", err: services.ErrNotFriend, status: http.StatusForbidden},
+		{name: "blocked", err: services.ErrUserBlocked, status: http.StatusForbidden},
+		{name: "internal", err: context.DeadlineExceeded, status: http.StatusInternalServerError},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSvc := &mockCommentService{
+				CreateFunc: func(ctx context.Context, userID, itemID uuid.UUID, parentID *uuid.UUID, body string) (*models.ItemComment, error) {
+					return nil, tc.err
+				},
+			}
+			handler := NewCommentHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/items/"+itemID.String()+"/comments", strings.NewReader(`{"body":"x"}`))
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			handler.Create(rr, req)
+			if rr.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rr.Code)
+			}
+		})
+	}
+}
+
+func TestCommentHandler_Create_InvalidBody(t *testing.T) {
+	handler := NewCommentHandler(&mockCommentService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/items/"+uuid.New().String()+"/comments", bytes.NewBufferString("invalid"))
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.Create(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rr.Code)
+	}
+}
+
+func TestCommentHandler_List(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	itemID := uuid.New()
+	mockSvc := &mockCommentService{
+		ListFunc: func(ctx context.Context, userID, gotItemID uuid.UUID) ([]models.ItemComment, error) {
+			if userID != user.ID || gotItemID != itemID {
+				t.Fatalf("unexpected ids")
+			}
+			return []models.ItemComment{{Body: "one"}, {Body: "two"}}, nil
+		},
+	}
+	handler := NewCommentHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/items/"+itemID.String()+"/comments", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.List(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp CommentResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(resp.Comments))
+	}
+}
+
+func TestCommentHandler_Update(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	commentID := uuid.New()
+
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewCommentHandler(&mockCommentService{})
+		req := httptest.NewRequest(http.MethodPut, "/api/comments/nope", strings.NewReader(`{"body":"x"}`))
+		req.SetPathValue("id", "nope")
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+
+		handler.Update(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("not author", func(t *testing.T) {
+		handler := NewCommentHandler(&mockCommentService{
+			UpdateFunc: func(ctx context.Context, userID, gotCommentID uuid.UUID, body string) (*models.ItemComment, error) {
+				return nil, services.ErrNotCommentAuthor
+			},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/api/comments/"+commentID.String(), strings.NewReader(`{"body":"x"}`))
+		req.SetPathValue("id", commentID.String())
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+
+		handler.Update(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected status 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		handler := NewCommentHandler(&mockCommentService{
+			UpdateFunc: func(ctx context.Context, userID, gotCommentID uuid.UUID, body string) (*models.ItemComment, error) {
+				if gotCommentID != commentID {
+					t.Fatalf("unexpected comment id")
+				}
+				return &models.ItemComment{ID: gotCommentID, Body: body}, nil
+			},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/api/comments/"+commentID.String(), strings.NewReader(`{"body":"edited"}`))
+		req.SetPathValue("id", commentID.String())
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+
+		handler.Update(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+	})
+}
+
+func TestCommentHandler_Delete(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	commentID := uuid.New()
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "not found", err: services.ErrCommentNotFound, status: http.StatusNotFound},
+		{name: "not permitted", err: services.ErrCommentNotPermitted, status: http.StatusForbidden},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewCommentHandler(&mockCommentService{
+				DeleteFunc: func(ctx context.Context, userID, gotCommentID uuid.UUID) error {
+					return tc.err
+				},
+			})
+			req := httptest.NewRequest(http.MethodDelete, "/api/comments/"+commentID.String(), nil)
+			req.SetPathValue("id", commentID.String())
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			handler.Delete(rr, req)
+			if rr.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rr.Code)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
@@ -11,6 +13,8 @@ type contextKey string
 const (
 	userContextKey       contextKey = "user"
 	tokenScopeContextKey contextKey = "token_scope"
+	tokenIDContextKey    contextKey = "token_id"
+	cspNonceContextKey   contextKey = "csp_nonce"
 )
 
 func SetUserInContext(ctx context.Context, user *models.User) context.Context {
@@ -30,3 +34,25 @@ func GetTokenScopeFromContext(ctx context.Context) models.ApiTokenScope {
 	scope, _ := ctx.Value(tokenScopeContextKey).(models.ApiTokenScope)
 	return scope
 }
+
+// SetTokenIDInContext records which API token authenticated the request, so
+// token traffic can be rate limited separately from the owner's sessions.
+func SetTokenIDInContext(ctx context.Context, tokenID uuid.UUID) context.Context {
+	return context.WithValue(ctx, tokenIDContextKey, tokenID)
+}
+
+func GetTokenIDFromContext(ctx context.Context) uuid.UUID {
+	id, _ := ctx.Value(tokenIDContextKey).(uuid.UUID)
+	return id
+}
+
+// SetCSPNonceInContext records the nonce the Content-Security-Policy allows
+// scripts to carry, so rendered pages can add it to their script tags.
+func SetCSPNonceInContext(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, cspNonceContextKey, nonce)
+}
+
+func GetCSPNonceFromContext(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceContextKey).(string)
+	return nonce
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+const (
+	cspReportMaxBytes = 64 << 10
+	// cspReportLogInterval is how often an identical violation is logged;
+	// every report is still counted.
+	cspReportLogInterval = time.Minute
+	cspReportMaxTracked  = 1024
+)
+
+// cspDirectives are the directive names counted by name. Reports come from
+// anyone, so everything else is counted as "other" to keep the counts map
+// from growing with whatever strings are posted.
+var cspDirectives = map[string]bool{
+	"default-src": true, "script-src": true, "script-src-elem": true, "script-src-attr": true,
+	"style-src": true, "style-src-elem": true, "style-src-attr": true, "img-src": true,
+	"font-src": true, "connect-src": true, "media-src": true, "object-src": true,
+	"frame-src": true, "child-src": true, "worker-src": true, "manifest-src": true,
+	"prefetch-src": true, "fenced-frame-src": true, "form-action": true, "frame-ancestors": true,
+	"base-uri": true, "navigate-to": true, "sandbox": true, "trusted-types": true,
+	"require-trusted-types-for": true, "upgrade-insecure-requests": true, "block-all-mixed-content": true,
+	"webrtc": true,
+}
+
+// cspDirectiveName reduces a reported directive to a known name. Older
+// browsers report the whole violated directive, sources included.
+func cspDirectiveName(directive string) string {
+	fields := strings.Fields(strings.ToLower(directive))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	if !cspDirectives[fields[0]] {
+		return "other"
+	}
+	return fields[0]
+}
+
+// CSPReportHandler receives Content-Security-Policy violation reports from
+// browsers, logs them and counts them by directive. Counts are kept in
+// memory, so each server instance reports its own since it started.
+type CSPReportHandler struct {
+	mu          sync.Mutex
+	since       time.Time
+	total       int64
+	byDirective map[string]int64
+	lastLogged  map[cspViolation]time.Time
+	now         func() time.Time
+}
+
+func NewCSPReportHandler() *CSPReportHandler {
+	return &CSPReportHandler{
+		since:       time.Now(),
+		byDirective: map[string]int64{},
+		lastLogged:  map[cspViolation]time.Time{},
+		now:         time.Now,
+	}
+}
+
+// cspViolation is the part of a report worth logging, common to the
+// report-uri and Reporting API formats.
+type cspViolation struct {
+	Directive   string
+	BlockedURL  string
+	DocumentURL string
+	SourceFile  string
+	Disposition string
+}
+
+// legacyCSPReport is the application/csp-report body sent for report-uri.
+type legacyCSPReport struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		BlockedURI         string `json:"blocked-uri"`
+		SourceFile         string `json:"source-file"`
+		Disposition        string `json:"disposition"`
+	} `json:"csp-report"`
+}
+
+// reportingAPIReport is one entry of the application/reports+json body sent
+// for report-to.
+type reportingAPIReport struct {
+	Type string `json:"type"`
+	Body struct {
+		DocumentURL        string `json:"documentURL"`
+		EffectiveDirective string `json:"effectiveDirective"`
+		BlockedURL         string `json:"blockedURL"`
+		SourceFile         string `json:"sourceFile"`
+		Disposition        string `json:"disposition"`
+	} `json:"body"`
+}
+
+type CSPReportStats struct {
+	Since       time.Time        `json:"since"`
+	Total       int64            `json:"total"`
+	ByDirective map[string]int64 `json:"by_directive"`
+}
+
+// Report accepts a violation report. It always answers 204 for a readable
+// report, since browsers ignore the response.
+func (h *CSPReportHandler) Report(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, cspReportMaxBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "Report too large")
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var violations []cspViolation
+	switch mediaType {
+	case "application/reports+json":
+		var reports []reportingAPIReport
+		if err := json.Unmarshal(body, &reports); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid report")
+			return
+		}
+		for _, report := range reports {
+			if report.Type != "csp-violation" {
+				continue
+			}
+			violations = append(violations, cspViolation{
+				Directive:   report.Body.EffectiveDirective,
+				BlockedURL:  report.Body.BlockedURL,
+				DocumentURL: report.Body.DocumentURL,
+				SourceFile:  report.Body.SourceFile,
+				Disposition: report.Body.Disposition,
+			})
+		}
+	case "application/csp-report", "application/json":
+		var report legacyCSPReport
+		if err := json.Unmarshal(body, &report); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid report")
+			return
+		}
+		directive := report.Report.EffectiveDirective
+		if directive == "" {
+			directive = report.Report.ViolatedDirective
+		}
+		violations = append(violations, cspViolation{
+			Directive:   directive,
+			BlockedURL:  report.Report.BlockedURI,
+			DocumentURL: report.Report.DocumentURI,
+			SourceFile:  report.Report.SourceFile,
+			Disposition: report.Report.Disposition,
+		})
+	default:
+		writeError(w, http.StatusUnsupportedMediaType, "Unsupported report type")
+		return
+	}
+
+	for _, v := range violations {
+		h.record(r, v)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CSPReportHandler) record(r *http.Request, v cspViolation) {
+	v.Directive = cspDirectiveName(v.Directive)
+	// Query strings and fragments can carry share tokens, so only the rest
+	// of each URL is kept.
+	v.BlockedURL = stripURLQuery(v.BlockedURL)
+	v.DocumentURL = stripURLQuery(v.DocumentURL)
+	v.SourceFile = stripURLQuery(v.SourceFile)
+
+	h.mu.Lock()
+	h.total++
+	h.byDirective[v.Directive]++
+	now := h.now()
+	shouldLog := now.Sub(h.lastLogged[v]) >= cspReportLogInterval
+	if shouldLog {
+		if len(h.lastLogged) >= cspReportMaxTracked {
+			clear(h.lastLogged)
+		}
+		h.lastLogged[v] = now
+	}
+	h.mu.Unlock()
+
+	if shouldLog {
+		logging.FromContext(r.Context()).Warn("CSP violation", map[string]interface{}{
+			"directive":    v.Directive,
+			"blocked_url":  v.BlockedURL,
+			"document_url": v.DocumentURL,
+			"source_file":  v.SourceFile,
+			"disposition":  v.Disposition,
+		})
+	}
+}
+
+// Stats returns the violation counts (admin only).
+func (h *CSPReportHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	stats := CSPReportStats{
+		Since:       h.since,
+		Total:       h.total,
+		ByDirective: make(map[string]int64, len(h.byDirective)),
+	}
+	for directive, count := range h.byDirective {
+		stats.ByDirective[directive] = count
+	}
+	h.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func stripURLQuery(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		// Keywords such as "inline" and "eval" aren't URLs.
+		return raw
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+func postCSPReport(t *testing.T, h *CSPReportHandler, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/csp-report", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rr := httptest.NewRecorder()
+	h.Report(rr, req)
+	return rr
+}
+
+func cspStats(t *testing.T, h *CSPReportHandler) CSPReportStats {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	h.Stats(rr, httptest.NewRequest(http.MethodGet, "/api/admin/csp-reports", nil))
+	var stats CSPReportStats
+	if err := json.NewDecoder(rr.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+	return stats
+}
+
+func TestCSPReportHandler_Report(t *testing.T) {
+	h := NewCSPReportHandler()
+
+	rr := postCSPReport(t, h, "application/csp-report", `{"csp-report": {
+		"document-uri": "https://yearofbingo.com/#card?share=secret",
+		"violated-directive": "script-src-elem",
+		"effective-directive": "script-src-elem",
+		"blocked-uri": "https://evil.example/x.js?token=abc"
+	}}`)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rr.Code)
+	}
+
+	rr = postCSPReport(t, h, "application/reports+json", `[
+		{"type": "csp-violation", "body": {"effectiveDirective": "style-src-attr", "blockedURL": "inline", "documentURL": "https://yearofbingo.com/"}},
+		{"type": "deprecation", "body": {}},
+		{"type": "csp-violation", "body": {"effectiveDirective": "script-src-elem", "blockedURL": "https://evil.example/y.js"}}
+	]`)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rr.Code)
+	}
+
+	stats := cspStats(t, h)
+	if stats.Total != 3 || stats.ByDirective["script-src-elem"] != 2 || stats.ByDirective["style-src-attr"] != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCSPReportHandler_UnknownDirectivesShareOneCount(t *testing.T) {
+	h := NewCSPReportHandler()
+
+	for i := 0; i < 50; i++ {
+		postCSPReport(t, h, "application/csp-report", fmt.Sprintf(`{"csp-report": {"violated-directive": "made-up-%d"}}`, i))
+	}
+	postCSPReport(t, h, "application/csp-report", `{"csp-report": {"violated-directive": "Script-Src 'self' https://cdn.example"}}`)
+	postCSPReport(t, h, "application/csp-report", `{"csp-report": {}}`)
+
+	stats := cspStats(t, h)
+	if len(stats.ByDirective) != 3 || stats.ByDirective["other"] != 50 || stats.ByDirective["script-src"] != 1 || stats.ByDirective["unknown"] != 1 {
+		t.Fatalf("unexpected stats: %+v", stats.ByDirective)
+	}
+}
+
+func TestCSPReportHandler_RejectsBadReports(t *testing.T) {
+	h := NewCSPReportHandler()
+
+	if rr := postCSPReport(t, h, "text/plain", "hello"); rr.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected 415, got %d", rr.Code)
+	}
+	if rr := postCSPReport(t, h, "application/csp-report", "{"); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rr.Code)
+	}
+	big := `{"csp-report": {"blocked-uri": "` + strings.Repeat("a", cspReportMaxBytes) + `"}}`
+	if rr := postCSPReport(t, h, "application/csp-report", big); rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", rr.Code)
+	}
+	if stats := cspStats(t, h); stats.Total != 0 {
+		t.Errorf("expected nothing counted, got %+v", stats)
+	}
+}
+
+func TestCSPReportHandler_LogsRepeatsOncePerInterval(t *testing.T) {
+	var buf bytes.Buffer
+	previous := logging.Default
+	logging.Default = logging.New().SetOutput(&buf)
+	defer func() { logging.Default = previous }()
+
+	h := NewCSPReportHandler()
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	report := `{"csp-report": {"effective-directive": "img-src", "blocked-uri": "https://img.example/a.png?sig=1"}}`
+	postCSPReport(t, h, "application/csp-report", report)
+	postCSPReport(t, h, "application/csp-report", report)
+	if got := strings.Count(buf.String(), "CSP violation"); got != 1 {
+		t.Fatalf("expected one log line for repeated reports, got %d", got)
+	}
+	if strings.Contains(buf.String(), "sig=1") {
+		t.Fatalf("expected query string stripped from logged URLs: %s", buf.String())
+	}
+
+	now = now.Add(cspReportLogInterval)
+	postCSPReport(t, h, "application/csp-report", report)
+	if got := strings.Count(buf.String(), "CSP violation"); got != 2 {
+		t.Fatalf("expected the report to be logged again after the interval, got %d", got)
+	}
+	if stats := cspStats(t, h); stats.Total != 3 {
+		t.Fatalf("expected every report counted, got %+v", stats)
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// PreconditionFailedResponse answers a card mutation whose If-Match no
+// longer matches, with the card as it is now so the client can refresh.
+type PreconditionFailedResponse struct {
+	Error string            `json:"error"`
+	Card  *models.BingoCard `json:"card,omitempty"`
+}
+
+// cardsETag returns a strong ETag for cards as they are about to be written.
+// updated_at moves whenever the card row changes, but item edits and
+// completions don't touch the card row, so each item's state is hashed too.
+// extra covers anything else in the response, such as the owner's name.
+func cardsETag(cards []*models.BingoCard, extra ...string) string {
+	h := sha256.New()
+	for _, card := range cards {
+		writeCardVersion(h, card)
+	}
+	for _, s := range extra {
+		fmt.Fprintf(h, "%q\n", s)
+	}
+	return formatETag(h)
+}
+
+func writeCardVersion(h hash.Hash, card *models.BingoCard) {
+	fmt.Fprintf(h, "card %s %d %d %d\n", card.ID, card.Version, card.UpdatedAt.UnixNano(), len(card.Items))
+	for _, item := range card.Items {
+		fmt.Fprintf(h, "item %s %d %q %t %d %q %q %d\n",
+			item.ID, item.Position, item.Content, item.IsCompleted, unixNanoOrZero(item.CompletedAt),
+			stringOrEmpty(item.Notes), stringOrEmpty(item.ProofURL), unixNanoOrZero(item.HiddenAt))
+	}
+}
+
+// reactionsETag returns a strong ETag for an item's reactions. Reactions are
+// only ever added or removed, so their IDs and reactor names are enough.
+func reactionsETag(reactions []models.ReactionWithUser) string {
+	h := sha256.New()
+	for _, reaction := range reactions {
+		fmt.Fprintf(h, "reaction %s %q %q\n", reaction.ID, reaction.Emoji, reaction.UserUsername)
+	}
+	return formatETag(h)
+}
+
+func formatETag(h hash.Hash) string {
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+func unixNanoOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// etagMatches reports whether an If-None-Match or If-Match header lists etag.
+// With weak set, W/ prefixes are ignored (If-None-Match); otherwise weak tags
+// never match (If-Match).
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCacheableJSON writes a 200 response that browsers may keep and
+// revalidate, or a bodyless 304 when the client already has this version.
+func writeCacheableJSON(w http.ResponseWriter, r *http.Request, etag string, data interface{}) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Del("Pragma")
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, data)
+}
+
+// CardVersionConflictResponse answers a card edit made against a version
+// the card has since moved past, with the card as it is now.
+type CardVersionConflictResponse struct {
+	Error   string            `json:"error"`
+	Message string            `json:"message"`
+	Card    *models.BingoCard `json:"card,omitempty"`
+}
+
+// Preconditions guards a card mutation with the request's optional
+// preconditions: an If-Match ETag and a ?version= the client last saw.
+//
+// When the card has changed since the client read it, If-Match refuses the
+// request with 412 and the current card; otherwise the mutation runs and a
+// successful response carries the card's new ETag so the client can chain
+// edits. Requests without If-Match are passed through untouched. Cards the
+// user doesn't own, or that don't exist, are left for the wrapped handler to
+// reject as usual.
+//
+// Both preconditions are also checked by the card service inside the edit
+// itself, against the version the matched ETag was taken at, so they catch
+// edits that race with this one too; handlers answer a mismatch with
+// writeVersionConflict.
+func (h *CardHandler) Preconditions(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		edit := &services.CardEdit{}
+		if raw := r.URL.Query().Get("version"); raw != "" {
+			version, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || version < 1 {
+				writeError(w, http.StatusBadRequest, "Invalid version")
+				return
+			}
+			edit.ExpectedVersion = &version
+		}
+		r = r.WithContext(services.WithCardEdit(r.Context(), edit))
+
+		ifMatch := r.Header.Get("If-Match")
+		user := GetUserFromContext(r.Context())
+		cardID, err := parseCardID(r)
+		if ifMatch == "" || user == nil || err != nil {
+			next(w, r)
+			return
+		}
+
+		card, err := h.cardService.GetByID(r.Context(), cardID)
+		if err != nil || card.UserID != user.ID {
+			next(w, r)
+			return
+		}
+		if etag := cardsETag([]*models.BingoCard{card}); !etagMatches(ifMatch, etag, false) {
+			writePreconditionFailed(w, card, etag)
+			return
+		}
+		if strings.TrimSpace(ifMatch) != "*" {
+			version := card.Version
+			edit.MatchedVersion = &version
+		}
+
+		next(&cardETagWriter{ResponseWriter: w, r: r, handler: h, cardID: cardID}, r)
+	}
+}
+
+func writePreconditionFailed(w http.ResponseWriter, card *models.BingoCard, etag string) {
+	w.Header().Set("ETag", etag)
+	writeJSON(w, http.StatusPreconditionFailed, PreconditionFailedResponse{
+		Error: "Card has changed since it was loaded",
+		Card:  card,
+	})
+}
+
+// writeVersionConflict answers an edit whose ?version= is stale with 409 and
+// the card as it is now, so the client can merge or retry. An edit whose
+// If-Match went stale while it waited for the card is answered with 412, as
+// if the card had already changed when Preconditions looked.
+func (h *CardHandler) writeVersionConflict(w http.ResponseWriter, r *http.Request, cardID uuid.UUID) {
+	card, err := h.cardService.GetByID(r.Context(), cardID)
+	if err != nil {
+		logError(r, "Error loading card after version conflict", err)
+		card = nil
+	}
+
+	if edit := services.CardEditFromContext(r.Context()); edit != nil && edit.PreconditionFailed {
+		if card == nil {
+			writeError(w, http.StatusPreconditionFailed, "Card has changed since it was loaded")
+			return
+		}
+		writePreconditionFailed(w, card, cardsETag([]*models.BingoCard{card}))
+		return
+	}
+
+	resp := CardVersionConflictResponse{
+		Error:   "card_version_conflict",
+		Message: "Card has been changed since that version",
+	}
+	if card != nil {
+		resp.Card = card
+		w.Header().Set("ETag", cardsETag([]*models.BingoCard{card}))
+	}
+	writeJSON(w, http.StatusConflict, resp)
+}
+
+// editedCardVersion returns the card's version after the edit made for r,
+// or 0 when the request didn't go through Preconditions.
+func editedCardVersion(r *http.Request) int64 {
+	if edit := services.CardEditFromContext(r.Context()); edit != nil {
+		return edit.NewVersion
+	}
+	return 0
+}
+
+// cardETagWriter sets the card's ETag on a successful response just before
+// the headers go out.
+type cardETagWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	handler     *CardHandler
+	cardID      uuid.UUID
+	wroteHeader bool
+}
+
+func (cw *cardETagWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	if status >= 200 && status < 300 {
+		card, err := cw.handler.cardService.GetByID(cw.r.Context(), cw.cardID)
+		if err == nil {
+			cw.Header().Set("ETag", cardsETag([]*models.BingoCard{card}))
+		} else if !errors.Is(err, services.ErrCardNotFound) {
+			logError(cw.r, "Error reloading card for ETag", err)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *cardETagWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
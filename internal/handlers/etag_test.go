@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		weak   bool
+		want   bool
+	}{
+		{`"abc"`, false, true},
+		{`"xyz", "abc"`, false, true},
+		{`*`, false, true},
+		{`W/"abc"`, true, true},
+		{`W/"abc"`, false, false},
+		{`"abcd"`, true, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `"abc"`, tt.weak); got != tt.want {
+			t.Errorf("etagMatches(%q, weak=%v) = %v, want %v", tt.header, tt.weak, got, tt.want)
+		}
+	}
+}
+
+func TestCardsETag_ChangesWithItems(t *testing.T) {
+	card := &models.BingoCard{ID: uuid.New(), UpdatedAt: time.Now(), Items: []models.BingoItem{{ID: uuid.New(), Position: 0, Content: "Run"}}}
+	before := cardsETag([]*models.BingoCard{card})
+
+	now := time.Now()
+	card.Items[0].IsCompleted = true
+	card.Items[0].CompletedAt = &now
+	if after := cardsETag([]*models.BingoCard{card}); after == before {
+		t.Fatal("expected completing an item to change the ETag")
+	}
+	if cardsETag([]*models.BingoCard{card}, "alice") == cardsETag([]*models.BingoCard{card}, "bob") {
+		t.Fatal("expected extra values to change the ETag")
+	}
+}
+
+func TestCardHandler_Get_NotModified(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	card := &models.BingoCard{ID: uuid.New(), UserID: user.ID, UpdatedAt: time.Now()}
+	handler := NewCardHandler(&mockCardService{
+		GetByIDFunc: func(ctx context.Context, cardID uuid.UUID) (*models.BingoCard, error) {
+			return card, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/"+card.ID.String(), nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.Get(rr, req)
+
+	etag := rr.Header().Get("ETag")
+	if rr.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", rr.Code, etag)
+	}
+	if got := rr.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Fatalf("expected private, no-cache, got %q", got)
+	}
+
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.Get(rr, req)
+	if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d %q", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCardHandler_Preconditions_IfMatch(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	card := &models.BingoCard{ID: uuid.New(), UserID: user.ID, UpdatedAt: time.Now(), Items: []models.BingoItem{{ID: uuid.New(), Content: "Old"}}}
+	etag := cardsETag([]*models.BingoCard{card})
+
+	var updates int
+	handler := NewCardHandler(&mockCardService{
+		GetByIDFunc: func(ctx context.Context, cardID uuid.UUID) (*models.BingoCard, error) {
+			return card, nil
+		},
+		UpdateItemFunc: func(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error) {
+			updates++
+			card.Items[0].Content = *params.Content
+			return &card.Items[0], nil
+		},
+	})
+	guarded := handler.Preconditions(handler.UpdateItem)
+
+	send := func(ifMatch string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(UpdateItemRequest{Content: ptrToString("New")})
+		req := httptest.NewRequest(http.MethodPut, "/api/cards/"+card.ID.String()+"/items/0", bytes.NewBuffer(body))
+		req.SetPathValue("id", card.ID.String())
+		req.SetPathValue("pos", "0")
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rr := httptest.NewRecorder()
+		guarded(rr, req)
+		return rr
+	}
+
+	rr := send(etag)
+	if rr.Code != http.StatusOK || updates != 1 {
+		t.Fatalf("expected update to run, got %d (updates=%d)", rr.Code, updates)
+	}
+	newETag := rr.Header().Get("ETag")
+	if newETag == "" || newETag == etag {
+		t.Fatalf("expected the new card ETag on the response, got %q", newETag)
+	}
+
+	// A second tab still holding the old ETag is refused.
+	rr = send(etag)
+	if rr.Code != http.StatusPreconditionFailed || updates != 1 {
+		t.Fatalf("expected 412 without updating, got %d (updates=%d)", rr.Code, updates)
+	}
+	var resp PreconditionFailedResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Card == nil || resp.Card.Items[0].Content != "New" || rr.Header().Get("ETag") != newETag {
+		t.Fatalf("expected current card in 412 response, got %+v", resp)
+	}
+
+	// Without If-Match the request goes straight through.
+	if rr = send(""); rr.Code != http.StatusOK || updates != 2 || rr.Header().Get("ETag") != "" {
+		t.Fatalf("expected unconditional update, got %d (updates=%d)", rr.Code, updates)
+	}
+}
+
+func TestCardHandler_Preconditions_IfMatchRace(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	card := &models.BingoCard{ID: uuid.New(), UserID: user.ID, Version: 3, UpdatedAt: time.Now(), Items: []models.BingoItem{{ID: uuid.New(), Content: "Old"}}}
+	etag := cardsETag([]*models.BingoCard{card})
+
+	// Both requests load the card, and so pass the ETag check, before
+	// either of them edits it. The edit itself runs under the card's row
+	// lock, like the card service's.
+	var mu sync.Mutex
+	var bothLoaded sync.WaitGroup
+	bothLoaded.Add(2)
+	loads := 0
+	current := func() *models.BingoCard {
+		mu.Lock()
+		defer mu.Unlock()
+		copied := *card
+		copied.Items = append([]models.BingoItem(nil), card.Items...)
+		return &copied
+	}
+	handler := NewCardHandler(&mockCardService{
+		GetByIDFunc: func(ctx context.Context, cardID uuid.UUID) (*models.BingoCard, error) {
+			loaded := current()
+			mu.Lock()
+			loads++
+			first := loads <= 2
+			mu.Unlock()
+			if first {
+				bothLoaded.Done()
+				bothLoaded.Wait()
+			}
+			return loaded, nil
+		},
+		UpdateItemFunc: func(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			edit := services.CardEditFromContext(ctx)
+			if edit.MatchedVersion == nil || *edit.MatchedVersion != card.Version {
+				edit.PreconditionFailed = true
+				return nil, services.ErrCardVersionConflict
+			}
+			card.Version++
+			card.Items[0].Content = *params.Content
+			edit.NewVersion = card.Version
+			item := card.Items[0]
+			return &item, nil
+		},
+	})
+	guarded := handler.Preconditions(handler.UpdateItem)
+
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, _ := json.Marshal(UpdateItemRequest{Content: ptrToString("Tab")})
+			req := httptest.NewRequest(http.MethodPut, "/api/cards/"+card.ID.String()+"/items/0", bytes.NewBuffer(body))
+			req.SetPathValue("id", card.ID.String())
+			req.SetPathValue("pos", "0")
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			req.Header.Set("If-Match", etag)
+			rr := httptest.NewRecorder()
+			guarded(rr, req)
+			codes[i] = rr.Code
+		}()
+	}
+	wg.Wait()
+
+	ok, refused := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusOK:
+			ok++
+		case http.StatusPreconditionFailed:
+			refused++
+		}
+	}
+	if ok != 1 || refused != 1 || card.Version != 4 {
+		t.Fatalf("expected one 200 and one 412, got %v (version %d)", codes, card.Version)
+	}
+}
+
+func TestCardHandler_Preconditions_Version(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	card := &models.BingoCard{ID: uuid.New(), UserID: user.ID, Version: 4}
+	handler := NewCardHandler(&mockCardService{
+		GetByIDFunc: func(ctx context.Context, cardID uuid.UUID) (*models.BingoCard, error) {
+			return card, nil
+		},
+		RemoveItemFunc: func(ctx context.Context, userID, cardID uuid.UUID, position int) error {
+			edit := services.CardEditFromContext(ctx)
+			if edit.ExpectedVersion != nil && *edit.ExpectedVersion != card.Version {
+				return services.ErrCardVersionConflict
+			}
+			card.Version++
+			edit.NewVersion = card.Version
+			return nil
+		},
+	})
+	guarded := handler.Preconditions(handler.RemoveItem)
+
+	send := func(version string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/cards/"+card.ID.String()+"/items/0?version="+version, nil)
+		req.SetPathValue("id", card.ID.String())
+		req.SetPathValue("pos", "0")
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		guarded(rr, req)
+		return rr
+	}
+
+	if rr := send("nope"); rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid version, got %d", rr.Code)
+	}
+
+	rr := send("4")
+	var resp CardResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil || rr.Code != http.StatusOK || resp.Version != 5 {
+		t.Fatalf("expected 200 with version 5, got %d %+v (%v)", rr.Code, resp, err)
+	}
+
+	rr = send("4")
+	var conflict CardVersionConflictResponse
+	if err := json.NewDecoder(rr.Body).Decode(&conflict); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if rr.Code != http.StatusConflict || conflict.Error != "card_version_conflict" || conflict.Card == nil || conflict.Card.Version != 5 {
+		t.Fatalf("expected 409 with the current card, got %d %+v", rr.Code, conflict)
+	}
+}
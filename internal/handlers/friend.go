@@ -3,7 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -14,8 +14,9 @@ import (
 )
 
 type FriendHandler struct {
-	friendService services.FriendServiceInterface
-	cardService   services.CardServiceInterface
+	friendService      services.FriendServiceInterface
+	cardService        services.CardServiceInterface
+	friendGroupService services.FriendGroupServiceInterface
 }
 
 func NewFriendHandler(friendService services.FriendServiceInterface, cardService services.CardServiceInterface) *FriendHandler {
@@ -25,6 +26,34 @@ func NewFriendHandler(friendService services.FriendServiceInterface, cardService
 	}
 }
 
+// SetFriendGroupService enables group-shared cards. Without it friends only
+// see cards shared with all friends.
+func (h *FriendHandler) SetFriendGroupService(friendGroupService services.FriendGroupServiceInterface) {
+	h.friendGroupService = friendGroupService
+}
+
+// visibleCards filters an owner's cards down to the finalized ones the viewer
+// may see: those shared with all friends, plus those shared with a group the
+// viewer is in.
+func (h *FriendHandler) visibleCards(r *http.Request, ownerID, viewerID uuid.UUID, cards []*models.BingoCard) ([]*models.BingoCard, error) {
+	var shared map[uuid.UUID]bool
+	if h.friendGroupService != nil {
+		var err error
+		shared, err = h.friendGroupService.SharedCardIDs(r.Context(), ownerID, viewerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var visible []*models.BingoCard
+	for _, card := range cards {
+		if card.IsFinalized && (card.VisibleToFriends || shared[card.ID]) {
+			visible = append(visible, card)
+		}
+	}
+	return visible, nil
+}
+
 type SendRequestRequest struct {
 	FriendID string `json:"friend_id"`
 }
@@ -40,6 +69,14 @@ type UserSearchResponse struct {
 	Users []models.UserSearchResult `json:"users"`
 }
 
+type FriendSuggestionsResponse struct {
+	Suggestions []models.FriendSuggestion `json:"suggestions"`
+}
+
+type DiscoverRequest struct {
+	EmailHashes []string `json:"email_hashes"`
+}
+
 type FriendCardResponse struct {
 	Card    *models.BingoCard `json:"card,omitempty"`
 	Owner   *FriendOwner      `json:"owner,omitempty"`
@@ -70,7 +107,57 @@ func (h *FriendHandler) Search(w http.ResponseWriter, r *http.Request) {
 
 	users, err := h.friendService.SearchUsers(r.Context(), user.ID, query)
 	if err != nil {
-		log.Printf("Error searching users: %v", err)
+		logError(r, "Error searching users", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, UserSearchResponse{Users: users})
+}
+
+// Suggestions lists people the user may know through mutual friends.
+func (h *FriendHandler) Suggestions(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	suggestions, err := h.friendService.Suggestions(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error listing friend suggestions", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, FriendSuggestionsResponse{Suggestions: suggestions})
+}
+
+// Discover finds users from hashed contact emails uploaded by the client.
+func (h *FriendHandler) Discover(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req DiscoverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	users, err := h.friendService.DiscoverByEmailHashes(r.Context(), user.ID, req.EmailHashes)
+	if errors.Is(err, services.ErrInvalidEmailHash) {
+		writeError(w, http.StatusBadRequest, "Email hashes must be hex-encoded SHA-256")
+		return
+	}
+	if errors.Is(err, services.ErrTooManyEmailHashes) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("At most %d email hashes can be checked at once", models.MaxDiscoveryEmailHashes))
+		return
+	}
+	if err != nil {
+		logError(r, "Error discovering users", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -111,7 +198,7 @@ func (h *FriendHandler) SendRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error sending friend request: %v", err)
+		logError(r, "Error sending friend request", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -146,7 +233,7 @@ func (h *FriendHandler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error accepting friend request: %v", err)
+		logError(r, "Error accepting friend request", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -181,7 +268,7 @@ func (h *FriendHandler) RejectRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error rejecting friend request: %v", err)
+		logError(r, "Error rejecting friend request", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -208,7 +295,7 @@ func (h *FriendHandler) Remove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error removing friend: %v", err)
+		logError(r, "Error removing friend", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -239,7 +326,7 @@ func (h *FriendHandler) CancelRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error canceling friend request: %v", err)
+		logError(r, "Error canceling friend request", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -256,21 +343,21 @@ func (h *FriendHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	friends, err := h.friendService.ListFriends(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error listing friends: %v", err)
+		logError(r, "Error listing friends", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
 	requests, err := h.friendService.ListPendingRequests(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error listing pending requests: %v", err)
+		logError(r, "Error listing pending requests", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
 	sent, err := h.friendService.ListSentRequests(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error listing sent requests: %v", err)
+		logError(r, "Error listing sent requests", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -306,7 +393,7 @@ func (h *FriendHandler) GetFriendCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error getting friend user ID: %v", err)
+		logError(r, "Error getting friend user ID", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -314,18 +401,23 @@ func (h *FriendHandler) GetFriendCard(w http.ResponseWriter, r *http.Request) {
 	// Get the friend's cards
 	cards, err := h.cardService.ListByUser(r.Context(), friendUserID)
 	if err != nil {
-		log.Printf("Error listing friend cards: %v", err)
+		logError(r, "Error listing friend cards", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	visible, err := h.visibleCards(r, friendUserID, user.ID, cards)
+	if err != nil {
+		logError(r, "Error filtering friend cards", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	// Find the active/current year finalized card that is visible to friends
+	// Find the active/current year card among those shared with the viewer
 	var activeCard *models.BingoCard
-	for _, card := range cards {
-		if card.IsFinalized && card.VisibleToFriends {
-			if activeCard == nil || card.Year > activeCard.Year {
-				activeCard = card
-			}
+	for _, card := range visible {
+		if activeCard == nil || card.Year > activeCard.Year {
+			activeCard = card
 		}
 	}
 
@@ -337,7 +429,7 @@ func (h *FriendHandler) GetFriendCard(w http.ResponseWriter, r *http.Request) {
 	// Get friend's username from the friendship list
 	friends, err := h.friendService.ListFriends(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error getting friends list for cards: %v", err)
+		logError(r, "Error getting friends list for cards", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -354,7 +446,9 @@ func (h *FriendHandler) GetFriendCard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	writeJSON(w, http.StatusOK, FriendCardResponse{
+	redactHiddenItems(activeCard)
+
+	writeCacheableJSON(w, r, cardsETag([]*models.BingoCard{activeCard}, ownerName), FriendCardResponse{
 		Card:  activeCard,
 		Owner: &FriendOwner{Username: ownerName},
 	})
@@ -384,7 +478,7 @@ func (h *FriendHandler) GetFriendCards(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error getting friend user ID: %v", err)
+		logError(r, "Error getting friend user ID", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -392,23 +486,23 @@ func (h *FriendHandler) GetFriendCards(w http.ResponseWriter, r *http.Request) {
 	// Get all friend's cards
 	cards, err := h.cardService.ListByUser(r.Context(), friendUserID)
 	if err != nil {
-		log.Printf("Error listing friend cards: %v", err)
+		logError(r, "Error listing friend cards", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	// Filter to only finalized cards that are visible to friends
-	var finalizedCards []*models.BingoCard
-	for _, card := range cards {
-		if card.IsFinalized && card.VisibleToFriends {
-			finalizedCards = append(finalizedCards, card)
-		}
+	// Filter to only finalized cards shared with the viewer
+	finalizedCards, err := h.visibleCards(r, friendUserID, user.ID, cards)
+	if err != nil {
+		logError(r, "Error filtering friend cards", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
 	}
 
 	// Get friend's username
 	friends, err := h.friendService.ListFriends(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error getting friends list: %v", err)
+		logError(r, "Error getting friends list", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -425,12 +519,24 @@ func (h *FriendHandler) GetFriendCards(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	writeJSON(w, http.StatusOK, FriendCardsResponse{
+	redactHiddenItems(finalizedCards...)
+
+	writeCacheableJSON(w, r, cardsETag(finalizedCards, ownerName), FriendCardsResponse{
 		Cards: finalizedCards,
 		Owner: &FriendOwner{Username: ownerName},
 	})
 }
 
+// redactHiddenItems blanks items a moderator has hidden before cards are
+// shown to anyone other than their owner.
+func redactHiddenItems(cards ...*models.BingoCard) {
+	for _, card := range cards {
+		for i := range card.Items {
+			card.Items[i].Redact()
+		}
+	}
+}
+
 func parseFriendshipID(r *http.Request) (uuid.UUID, error) {
 	if id := r.PathValue("id"); id != "" {
 		return uuid.Parse(id)
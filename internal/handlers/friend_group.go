@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type FriendGroupHandler struct {
+	friendGroupService services.FriendGroupServiceInterface
+}
+
+func NewFriendGroupHandler(friendGroupService services.FriendGroupServiceInterface) *FriendGroupHandler {
+	return &FriendGroupHandler{friendGroupService: friendGroupService}
+}
+
+type FriendGroupRequest struct {
+	Name string `json:"name"`
+}
+
+type FriendGroupMemberRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type FriendGroupResponse struct {
+	Group   *models.FriendGroup `json:"group,omitempty"`
+	Message string              `json:"message,omitempty"`
+}
+
+type FriendGroupListResponse struct {
+	Groups []models.FriendGroup `json:"groups"`
+}
+
+func (h *FriendGroupHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	groups, err := h.friendGroupService.List(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error listing friend groups", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, FriendGroupListResponse{Groups: groups})
+}
+
+func (h *FriendGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req FriendGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	group, err := h.friendGroupService.Create(r.Context(), user.ID, req.Name)
+	if err != nil {
+		writeFriendGroupError(w, r, err, "creating friend group")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, FriendGroupResponse{Group: group})
+}
+
+func (h *FriendGroupHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	var req FriendGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.friendGroupService.Rename(r.Context(), user.ID, groupID, req.Name); err != nil {
+		writeFriendGroupError(w, r, err, "renaming friend group")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, FriendGroupResponse{Message: "Group renamed"})
+}
+
+func (h *FriendGroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	if err := h.friendGroupService.Delete(r.Context(), user.ID, groupID); err != nil {
+		writeFriendGroupError(w, r, err, "deleting friend group")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, FriendGroupResponse{Message: "Group deleted"})
+}
+
+func (h *FriendGroupHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	var req FriendGroupMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	memberID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.friendGroupService.AddMember(r.Context(), user.ID, groupID, memberID); err != nil {
+		writeFriendGroupError(w, r, err, "adding group member")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, FriendGroupResponse{Message: "Member added"})
+}
+
+func (h *FriendGroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	memberID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.friendGroupService.RemoveMember(r.Context(), user.ID, groupID, memberID); err != nil {
+		writeFriendGroupError(w, r, err, "removing group member")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, FriendGroupResponse{Message: "Member removed"})
+}
+
+func writeFriendGroupError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidFriendGroupName):
+		writeError(w, http.StatusBadRequest, "Group name must be 1-50 characters")
+	case errors.Is(err, services.ErrFriendGroupExists):
+		writeError(w, http.StatusConflict, "A group with this name already exists")
+	case errors.Is(err, services.ErrFriendGroupNotFound):
+		writeError(w, http.StatusNotFound, "Group not found")
+	case errors.Is(err, services.ErrGroupMemberNotFound):
+		writeError(w, http.StatusNotFound, "Member not found in group")
+	case errors.Is(err, services.ErrNotFriend):
+		writeError(w, http.StatusBadRequest, "Only friends can be added to a group")
+	default:
+		logError(r, "Error "+action, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
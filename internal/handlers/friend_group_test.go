@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestFriendGroupHandler_List_Unauthenticated(t *testing.T) {
+	handler := NewFriendGroupHandler(&mockFriendGroupService{})
+
+	rr := httptest.NewRecorder()
+	handler.List(rr, httptest.NewRequest(http.MethodGet, "/api/friend-groups", nil))
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rr.Code)
+	}
+}
+
+func TestFriendGroupHandler_Create(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "created", wantStatus: http.StatusCreated},
+		{name: "invalid name", serviceErr: services.ErrInvalidFriendGroupName, wantStatus: http.StatusBadRequest},
+		{name: "duplicate", serviceErr: services.ErrFriendGroupExists, wantStatus: http.StatusConflict},
+		{name: "internal", serviceErr: errors.New("boom"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewFriendGroupHandler(&mockFriendGroupService{
+				CreateFunc: func(ctx context.Context, ownerID uuid.UUID, name string) (*models.FriendGroup, error) {
+					if ownerID != user.ID || name != "Family" {
+						t.Fatalf("unexpected args: %s %q", ownerID, name)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &models.FriendGroup{ID: uuid.New(), Name: name, Members: []models.FriendGroupMember{}}, nil
+				},
+			})
+
+			body, _ := json.Marshal(FriendGroupRequest{Name: "Family"})
+			req := httptest.NewRequest(http.MethodPost, "/api/friend-groups", bytes.NewBuffer(body))
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+			handler.Create(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestFriendGroupHandler_Rename_InvalidID(t *testing.T) {
+	handler := NewFriendGroupHandler(&mockFriendGroupService{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/friend-groups/nope", bytes.NewBufferString(`{"name":"Work"}`))
+	req.SetPathValue("id", "nope")
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.Rename(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestFriendGroupHandler_Delete_NotFound(t *testing.T) {
+	groupID := uuid.New()
+	handler := NewFriendGroupHandler(&mockFriendGroupService{
+		DeleteFunc: func(ctx context.Context, ownerID, gotGroupID uuid.UUID) error {
+			return services.ErrFriendGroupNotFound
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/friend-groups/"+groupID.String(), nil)
+	req.SetPathValue("id", groupID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.Delete(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestFriendGroupHandler_AddMember(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	groupID := uuid.New()
+	memberID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "added", body: `{"user_id":"` + memberID.String() + `"}`, wantStatus: http.StatusOK},
+		{name: "invalid user", body: `{"user_id":"nope"}`, wantStatus: http.StatusBadRequest},
+		{name: "not a friend", body: `{"user_id":"` + memberID.String() + `"}`, serviceErr: services.ErrNotFriend, wantStatus: http.StatusBadRequest},
+		{name: "group not found", body: `{"user_id":"` + memberID.String() + `"}`, serviceErr: services.ErrFriendGroupNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewFriendGroupHandler(&mockFriendGroupService{
+				AddMemberFunc: func(ctx context.Context, ownerID, gotGroupID, gotMemberID uuid.UUID) error {
+					if gotGroupID != groupID || gotMemberID != memberID {
+						t.Fatalf("unexpected args: %s %s", gotGroupID, gotMemberID)
+					}
+					return tt.serviceErr
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/friend-groups/"+groupID.String()+"/members", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", groupID.String())
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+			handler.AddMember(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestFriendGroupHandler_RemoveMember(t *testing.T) {
+	groupID := uuid.New()
+	memberID := uuid.New()
+	handler := NewFriendGroupHandler(&mockFriendGroupService{
+		RemoveMemberFunc: func(ctx context.Context, ownerID, gotGroupID, gotMemberID uuid.UUID) error {
+			if gotMemberID != memberID {
+				return services.ErrGroupMemberNotFound
+			}
+			return nil
+		},
+	})
+
+	for _, tc := range []struct {
+		member string
+		want   int
+	}{
+		{memberID.String(), http.StatusOK},
+		{uuid.New().String(), http.StatusNotFound},
+	} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/friend-groups/"+groupID.String()+"/members/"+tc.member, nil)
+		req.SetPathValue("id", groupID.String())
+		req.SetPathValue("userId", tc.member)
+		req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+		rr := httptest.NewRecorder()
+		handler.RemoveMember(rr, req)
+
+		if rr.Code != tc.want {
+			t.Fatalf("expected %d, got %d", tc.want, rr.Code)
+		}
+	}
+}
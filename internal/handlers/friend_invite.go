@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -15,12 +14,18 @@ import (
 
 type FriendInviteHandler struct {
 	inviteService services.FriendInviteServiceInterface
+	throttle      services.ThrottleServiceInterface
 }
 
 func NewFriendInviteHandler(inviteService services.FriendInviteServiceInterface) *FriendInviteHandler {
 	return &FriendInviteHandler{inviteService: inviteService}
 }
 
+// SetThrottle limits how many invalid invite tokens a user can try.
+func (h *FriendInviteHandler) SetThrottle(throttle services.ThrottleServiceInterface) {
+	h.throttle = throttle
+}
+
 type CreateInviteRequest struct {
 	ExpiresInDays int `json:"expires_in_days"`
 }
@@ -76,7 +81,7 @@ func (h *FriendInviteHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error creating invite: %v", err)
+		logError(r, "Error creating invite", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -94,7 +99,7 @@ func (h *FriendInviteHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	invites, err := h.inviteService.ListInvites(r.Context(), user.ID)
 	if err != nil {
-		log.Printf("Error listing invites: %v", err)
+		logError(r, "Error listing invites", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -122,7 +127,7 @@ func (h *FriendInviteHandler) Revoke(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error revoking invite: %v", err)
+		logError(r, "Error revoking invite", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -143,8 +148,14 @@ func (h *FriendInviteHandler) Accept(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	target := services.ThrottleTarget{IP: getClientIP(r), Account: user.ID.String()}
+	if checkThrottle(w, r, h.throttle, services.ThrottleInviteAccept, target) {
+		return
+	}
+
 	inviter, err := h.inviteService.AcceptInvite(r.Context(), user.ID, req.Token)
 	if errors.Is(err, services.ErrInviteNotFound) {
+		failThrottle(r.Context(), h.throttle, services.ThrottleInviteAccept, target)
 		writeError(w, http.StatusNotFound, "Invite not found or expired")
 		return
 	}
@@ -161,7 +172,7 @@ func (h *FriendInviteHandler) Accept(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		log.Printf("Error accepting invite: %v", err)
+		logError(r, "Error accepting invite", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
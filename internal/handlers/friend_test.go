@@ -3,11 +3,13 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -407,6 +409,51 @@ func TestFriendHandler_GetFriendCardAndCards(t *testing.T) {
 	}
 }
 
+func TestFriendHandler_GetFriendCard_RedactsHiddenItems(t *testing.T) {
+	currentUser := &models.User{ID: uuid.New()}
+	friendUserID := uuid.New()
+	hiddenAt := time.Now()
+	notes := "private notes"
+
+	mockFriend := &mockFriendService{
+		GetFriendUserIDFunc: func(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error) {
+			return friendUserID, nil
+		},
+	}
+	mockCard := &mockCardService{
+		ListByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
+			return []*models.BingoCard{{
+				ID: uuid.New(), UserID: friendUserID, Year: 2024, IsFinalized: true, VisibleToFriends: true,
+				Items: []models.BingoItem{
+					{Position: 0, Content: "fine"},
+					{Position: 1, Content: "abusive", Notes: &notes, HiddenAt: &hiddenAt},
+				},
+			}}, nil
+		},
+	}
+
+	handler := NewFriendHandler(mockFriend, mockCard)
+	req := httptest.NewRequest(http.MethodGet, "/api/friends/"+uuid.New().String()+"/card", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, currentUser))
+	rr := httptest.NewRecorder()
+	handler.GetFriendCard(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp FriendCardResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	items := resp.Card.Items
+	if items[0].Content != "fine" {
+		t.Fatalf("expected visible item untouched, got %q", items[0].Content)
+	}
+	if items[1].Content != "" || items[1].Notes != nil || items[1].HiddenAt == nil {
+		t.Fatalf("expected hidden item redacted, got %+v", items[1])
+	}
+}
+
 func TestFriendHandler_GetFriendCards_FriendshipNotFound(t *testing.T) {
 	friendshipID := uuid.New()
 	handler := NewFriendHandler(&mockFriendService{
@@ -760,3 +807,165 @@ func TestFriendHandler_ErrorMappings(t *testing.T) {
 		}
 	})
 }
+
+func TestFriendHandler_GetFriendCards_IncludesGroupSharedCards(t *testing.T) {
+	currentUser := &models.User{ID: uuid.New()}
+	friendUserID := uuid.New()
+
+	mockFriend := &mockFriendService{
+		GetFriendUserIDFunc: func(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error) {
+			return friendUserID, nil
+		},
+	}
+
+	public := &models.BingoCard{ID: uuid.New(), UserID: friendUserID, Year: 2024, IsFinalized: true, VisibleToFriends: true, Visibility: models.VisibilityFriends}
+	sharedWithViewer := &models.BingoCard{ID: uuid.New(), UserID: friendUserID, Year: 2025, IsFinalized: true, Visibility: models.VisibilityGroups}
+	sharedWithOthers := &models.BingoCard{ID: uuid.New(), UserID: friendUserID, Year: 2026, IsFinalized: true, Visibility: models.VisibilityGroups}
+
+	mockCard := &mockCardService{
+		ListByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
+			return []*models.BingoCard{public, sharedWithViewer, sharedWithOthers}, nil
+		},
+	}
+
+	handler := NewFriendHandler(mockFriend, mockCard)
+	handler.SetFriendGroupService(&mockFriendGroupService{
+		SharedCardIDsFunc: func(ctx context.Context, ownerID, viewerID uuid.UUID) (map[uuid.UUID]bool, error) {
+			if ownerID != friendUserID || viewerID != currentUser.ID {
+				t.Fatalf("unexpected owner/viewer: %s %s", ownerID, viewerID)
+			}
+			return map[uuid.UUID]bool{sharedWithViewer.ID: true}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/friends/"+uuid.New().String()+"/cards", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, currentUser))
+	rr := httptest.NewRecorder()
+	handler.GetFriendCards(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	var resp FriendCardsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Cards) != 2 || resp.Cards[0].ID != public.ID || resp.Cards[1].ID != sharedWithViewer.ID {
+		t.Fatalf("expected public and group-shared cards, got %+v", resp.Cards)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/friends/"+uuid.New().String()+"/card", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, currentUser))
+	rr = httptest.NewRecorder()
+	handler.GetFriendCard(rr, req)
+
+	var single FriendCardResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &single); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if single.Card == nil || single.Card.ID != sharedWithViewer.ID {
+		t.Fatalf("expected newest card shared with viewer, got %+v", single.Card)
+	}
+}
+
+func TestFriendHandler_GetFriendCards_GroupLookupError(t *testing.T) {
+	currentUser := &models.User{ID: uuid.New()}
+	mockFriend := &mockFriendService{
+		GetFriendUserIDFunc: func(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error) {
+			return uuid.New(), nil
+		},
+	}
+	handler := NewFriendHandler(mockFriend, &mockCardService{})
+	handler.SetFriendGroupService(&mockFriendGroupService{
+		SharedCardIDsFunc: func(ctx context.Context, ownerID, viewerID uuid.UUID) (map[uuid.UUID]bool, error) {
+			return nil, errors.New("db down")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/friends/"+uuid.New().String()+"/cards", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, currentUser))
+	rr := httptest.NewRecorder()
+	handler.GetFriendCards(rr, req)
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rr.Code)
+	}
+}
+
+func TestFriendHandler_Suggestions(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewFriendHandler(&mockFriendService{
+		SuggestionsFunc: func(ctx context.Context, userID uuid.UUID) ([]models.FriendSuggestion, error) {
+			if userID != user.ID {
+				t.Fatalf("unexpected user %s", userID)
+			}
+			return []models.FriendSuggestion{{ID: uuid.New(), Username: "bob", MutualFriends: 2}}, nil
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/friends/suggestions", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Suggestions(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp FriendSuggestionsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Suggestions) != 1 || resp.Suggestions[0].MutualFriends != 2 {
+		t.Fatalf("unexpected suggestions: %+v", resp.Suggestions)
+	}
+}
+
+func TestFriendHandler_Suggestions_Unauthenticated(t *testing.T) {
+	handler := NewFriendHandler(&mockFriendService{}, nil)
+
+	rr := httptest.NewRecorder()
+	handler.Suggestions(rr, httptest.NewRequest(http.MethodGet, "/api/friends/suggestions", nil))
+
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestFriendHandler_Discover(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "found", body: `{"email_hashes":["abc"]}`, wantStatus: http.StatusOK},
+		{name: "invalid body", body: `nope`, wantStatus: http.StatusBadRequest},
+		{name: "invalid hash", body: `{"email_hashes":["abc"]}`, serviceErr: services.ErrInvalidEmailHash, wantStatus: http.StatusBadRequest},
+		{name: "too many", body: `{"email_hashes":["abc"]}`, serviceErr: services.ErrTooManyEmailHashes, wantStatus: http.StatusBadRequest},
+		{name: "internal", body: `{"email_hashes":["abc"]}`, serviceErr: errors.New("boom"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewFriendHandler(&mockFriendService{
+				DiscoverByEmailHashesFunc: func(ctx context.Context, userID uuid.UUID, hashes []string) ([]models.UserSearchResult, error) {
+					if len(hashes) != 1 || hashes[0] != "abc" {
+						t.Fatalf("unexpected hashes: %v", hashes)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return []models.UserSearchResult{{ID: uuid.New(), Username: "friend"}}, nil
+				},
+			}, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/friends/discover", bytes.NewBufferString(tt.body))
+			req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+			rr := httptest.NewRecorder()
+
+			handler.Discover(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
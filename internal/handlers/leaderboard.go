@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService services.LeaderboardServiceInterface
+}
+
+func NewLeaderboardHandler(leaderboardService services.LeaderboardServiceInterface) *LeaderboardHandler {
+	return &LeaderboardHandler{leaderboardService: leaderboardService}
+}
+
+type LeaderboardResponse struct {
+	Leaderboard *models.Leaderboard `json:"leaderboard"`
+}
+
+// Get ranks the user and their friends for ?year= (default: this year).
+func (h *LeaderboardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	year := time.Now().Year()
+	if raw := r.URL.Query().Get("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 2020 || parsed > year+1 {
+			writeError(w, http.StatusBadRequest, "Year must be between 2020 and next year")
+			return
+		}
+		year = parsed
+	}
+
+	board, err := h.leaderboardService.ForYear(r.Context(), user.ID, year)
+	if err != nil {
+		logError(r, "Error getting leaderboard", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, LeaderboardResponse{Leaderboard: board})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestLeaderboardHandler_Get_Unauthenticated(t *testing.T) {
+	handler := NewLeaderboardHandler(&mockLeaderboardService{})
+
+	rr := httptest.NewRecorder()
+	handler.Get(rr, httptest.NewRequest(http.MethodGet, "/api/friends/leaderboard", nil))
+
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestLeaderboardHandler_Get_InvalidYear(t *testing.T) {
+	handler := NewLeaderboardHandler(&mockLeaderboardService{})
+
+	for _, year := range []string{"abc", "2019", "9999"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/friends/leaderboard?year="+year, nil)
+		req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+		rr := httptest.NewRecorder()
+
+		handler.Get(rr, req)
+
+		assertErrorResponse(t, rr, http.StatusBadRequest, "Year must be between 2020 and next year")
+	}
+}
+
+func TestLeaderboardHandler_Get_DefaultsToCurrentYear(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	var gotYear int
+	handler := NewLeaderboardHandler(&mockLeaderboardService{
+		ForYearFunc: func(ctx context.Context, viewerID uuid.UUID, year int) (*models.Leaderboard, error) {
+			if viewerID != user.ID {
+				t.Fatalf("unexpected viewer %s", viewerID)
+			}
+			gotYear = year
+			return &models.Leaderboard{Year: year, Entries: []models.LeaderboardEntry{
+				{Rank: 1, UserID: user.ID, Username: "me", IsYou: true, CompletedItems: 3},
+			}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/friends/leaderboard", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Get(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotYear != time.Now().Year() {
+		t.Fatalf("expected current year, got %d", gotYear)
+	}
+	var resp LeaderboardResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Leaderboard == nil || len(resp.Leaderboard.Entries) != 1 || !resp.Leaderboard.Entries[0].IsYou {
+		t.Fatalf("unexpected leaderboard: %+v", resp.Leaderboard)
+	}
+}
+
+func TestLeaderboardHandler_Get_ServiceError(t *testing.T) {
+	handler := NewLeaderboardHandler(&mockLeaderboardService{
+		ForYearFunc: func(ctx context.Context, viewerID uuid.UUID, year int) (*models.Leaderboard, error) {
+			return nil, errors.New("boom")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/friends/leaderboard?year=2024", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.Get(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
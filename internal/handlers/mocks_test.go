@@ -10,12 +10,14 @@ import (
 )
 
 type mockUserService struct {
-	CreateFunc            func(ctx context.Context, params models.CreateUserParams) (*models.User, error)
-	GetByIDFunc           func(ctx context.Context, id uuid.UUID) (*models.User, error)
-	GetByEmailFunc        func(ctx context.Context, email string) (*models.User, error)
-	UpdatePasswordFunc    func(ctx context.Context, userID uuid.UUID, newPasswordHash string) error
-	MarkEmailVerifiedFunc func(ctx context.Context, userID uuid.UUID) error
-	UpdateSearchableFunc  func(ctx context.Context, userID uuid.UUID, searchable bool) error
+	CreateFunc                  func(ctx context.Context, params models.CreateUserParams) (*models.User, error)
+	GetByIDFunc                 func(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByEmailFunc              func(ctx context.Context, email string) (*models.User, error)
+	UpdatePasswordFunc          func(ctx context.Context, userID uuid.UUID, newPasswordHash string) error
+	MarkEmailVerifiedFunc       func(ctx context.Context, userID uuid.UUID) error
+	UpdateSearchableFunc        func(ctx context.Context, userID uuid.UUID, searchable bool) error
+	UpdatePublicProfileFunc     func(ctx context.Context, userID uuid.UUID, public bool) error
+	UpdateLeaderboardOptOutFunc func(ctx context.Context, userID uuid.UUID, optOut bool) error
 }
 
 func (m *mockUserService) Create(ctx context.Context, params models.CreateUserParams) (*models.User, error) {
@@ -60,6 +62,20 @@ func (m *mockUserService) UpdateSearchable(ctx context.Context, userID uuid.UUID
 	return nil
 }
 
+func (m *mockUserService) UpdatePublicProfile(ctx context.Context, userID uuid.UUID, public bool) error {
+	if m.UpdatePublicProfileFunc != nil {
+		return m.UpdatePublicProfileFunc(ctx, userID, public)
+	}
+	return nil
+}
+
+func (m *mockUserService) UpdateLeaderboardOptOut(ctx context.Context, userID uuid.UUID, optOut bool) error {
+	if m.UpdateLeaderboardOptOutFunc != nil {
+		return m.UpdateLeaderboardOptOutFunc(ctx, userID, optOut)
+	}
+	return nil
+}
+
 type mockAuthService struct {
 	HashPasswordFunc          func(password string) (string, error)
 	VerifyPasswordFunc        func(hash, password string) bool
@@ -127,7 +143,7 @@ type mockEmailService struct {
 	SendPasswordResetEmailFunc   func(ctx context.Context, userID uuid.UUID, email string) error
 	VerifyPasswordResetTokenFunc func(ctx context.Context, token string) (uuid.UUID, error)
 	MarkPasswordResetUsedFunc    func(ctx context.Context, token string) error
-	SendNotificationEmailFunc    func(ctx context.Context, toEmail, subject, html, text string) error
+	SendNotificationEmailFunc    func(ctx context.Context, notificationID uuid.UUID, toEmail, subject, html, text string) error
 	SendSupportEmailFunc         func(ctx context.Context, fromEmail, category, message string, userID string) error
 }
 
@@ -180,9 +196,9 @@ func (m *mockEmailService) MarkPasswordResetUsed(ctx context.Context, token stri
 	return nil
 }
 
-func (m *mockEmailService) SendNotificationEmail(ctx context.Context, toEmail, subject, html, text string) error {
+func (m *mockEmailService) SendNotificationEmail(ctx context.Context, notificationID uuid.UUID, toEmail, subject, html, text string) error {
 	if m.SendNotificationEmailFunc != nil {
-		return m.SendNotificationEmailFunc(ctx, toEmail, subject, html, text)
+		return m.SendNotificationEmailFunc(ctx, notificationID, toEmail, subject, html, text)
 	}
 	return nil
 }
@@ -211,14 +227,22 @@ type mockCardService struct {
 	CompleteItemFunc         func(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error)
 	UncompleteItemFunc       func(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error)
 	UpdateItemNotesFunc      func(ctx context.Context, userID, cardID uuid.UUID, position int, notes, proofURL *string) (*models.BingoItem, error)
+	UpdateItemScheduleFunc   func(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemScheduleParams) (*models.BingoItem, error)
 	GetArchiveFunc           func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error)
 	GetStatsFunc             func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardStats, error)
 	UpdateMetaFunc           func(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error)
-	UpdateVisibilityFunc     func(ctx context.Context, userID, cardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error)
-	BulkUpdateVisibilityFunc func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error)
+	UpdateVisibilityFunc     func(ctx context.Context, userID, cardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error)
+	BulkUpdateVisibilityFunc func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, params models.CardVisibilityParams) (int, error)
+	GetSharingFunc           func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardSharing, error)
+	GetByShareTokenFunc      func(ctx context.Context, token string) (*models.BingoCard, error)
 	BulkDeleteFunc           func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error)
 	BulkUpdateArchiveFunc    func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) (int, error)
 	ImportFunc               func(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error)
+	HistoryFunc              func(ctx context.Context, userID, cardID uuid.UUID) ([]models.CardRevision, error)
+	UndoFunc                 func(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error)
+	RestoreRevisionFunc      func(ctx context.Context, userID, cardID, revisionID uuid.UUID) (*models.BingoCard, error)
+	ListTrashFunc            func(ctx context.Context, userID uuid.UUID) ([]models.TrashedCard, error)
+	RestoreFromTrashFunc     func(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error)
 }
 
 func (m *mockCardService) CheckForConflict(ctx context.Context, userID uuid.UUID, year int, title *string) (*models.BingoCard, error) {
@@ -333,6 +357,13 @@ func (m *mockCardService) UpdateItemNotes(ctx context.Context, userID, cardID uu
 	return nil, nil
 }
 
+func (m *mockCardService) UpdateItemSchedule(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemScheduleParams) (*models.BingoItem, error) {
+	if m.UpdateItemScheduleFunc != nil {
+		return m.UpdateItemScheduleFunc(ctx, userID, cardID, position, params)
+	}
+	return nil, nil
+}
+
 func (m *mockCardService) GetArchive(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
 	if m.GetArchiveFunc != nil {
 		return m.GetArchiveFunc(ctx, userID)
@@ -354,20 +385,34 @@ func (m *mockCardService) UpdateMeta(ctx context.Context, userID, cardID uuid.UU
 	return nil, nil
 }
 
-func (m *mockCardService) UpdateVisibility(ctx context.Context, userID, cardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error) {
+func (m *mockCardService) UpdateVisibility(ctx context.Context, userID, cardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error) {
 	if m.UpdateVisibilityFunc != nil {
-		return m.UpdateVisibilityFunc(ctx, userID, cardID, visibleToFriends)
+		return m.UpdateVisibilityFunc(ctx, userID, cardID, params)
 	}
 	return nil, nil
 }
 
-func (m *mockCardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error) {
+func (m *mockCardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, params models.CardVisibilityParams) (int, error) {
 	if m.BulkUpdateVisibilityFunc != nil {
-		return m.BulkUpdateVisibilityFunc(ctx, userID, cardIDs, visibleToFriends)
+		return m.BulkUpdateVisibilityFunc(ctx, userID, cardIDs, params)
 	}
 	return 0, nil
 }
 
+func (m *mockCardService) GetSharing(ctx context.Context, userID, cardID uuid.UUID) (*models.CardSharing, error) {
+	if m.GetSharingFunc != nil {
+		return m.GetSharingFunc(ctx, userID, cardID)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) GetByShareToken(ctx context.Context, token string) (*models.BingoCard, error) {
+	if m.GetByShareTokenFunc != nil {
+		return m.GetByShareTokenFunc(ctx, token)
+	}
+	return nil, services.ErrCardNotFound
+}
+
 func (m *mockCardService) BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
 	if m.BulkDeleteFunc != nil {
 		return m.BulkDeleteFunc(ctx, userID, cardIDs)
@@ -389,6 +434,41 @@ func (m *mockCardService) Import(ctx context.Context, params models.ImportCardPa
 	return nil, nil
 }
 
+func (m *mockCardService) History(ctx context.Context, userID, cardID uuid.UUID) ([]models.CardRevision, error) {
+	if m.HistoryFunc != nil {
+		return m.HistoryFunc(ctx, userID, cardID)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) Undo(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+	if m.UndoFunc != nil {
+		return m.UndoFunc(ctx, userID, cardID)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) RestoreRevision(ctx context.Context, userID, cardID, revisionID uuid.UUID) (*models.BingoCard, error) {
+	if m.RestoreRevisionFunc != nil {
+		return m.RestoreRevisionFunc(ctx, userID, cardID, revisionID)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) ListTrash(ctx context.Context, userID uuid.UUID) ([]models.TrashedCard, error) {
+	if m.ListTrashFunc != nil {
+		return m.ListTrashFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) RestoreFromTrash(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+	if m.RestoreFromTrashFunc != nil {
+		return m.RestoreFromTrashFunc(ctx, userID, cardID)
+	}
+	return nil, nil
+}
+
 type mockSuggestionService struct {
 	GetAllFunc               func(ctx context.Context) ([]*models.Suggestion, error)
 	GetByCategoryFunc        func(ctx context.Context, category string) ([]*models.Suggestion, error)
@@ -425,17 +505,19 @@ func (m *mockSuggestionService) GetGroupedByCategory(ctx context.Context) ([]ser
 }
 
 type mockFriendService struct {
-	SearchUsersFunc         func(ctx context.Context, currentUserID uuid.UUID, query string) ([]models.UserSearchResult, error)
-	SendRequestFunc         func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error)
-	AcceptRequestFunc       func(ctx context.Context, userID, friendshipID uuid.UUID) (*models.Friendship, error)
-	RejectRequestFunc       func(ctx context.Context, userID, friendshipID uuid.UUID) error
-	RemoveFriendFunc        func(ctx context.Context, userID, friendshipID uuid.UUID) error
-	CancelRequestFunc       func(ctx context.Context, userID, friendshipID uuid.UUID) error
-	ListFriendsFunc         func(ctx context.Context, userID uuid.UUID) ([]models.FriendWithUser, error)
-	ListPendingRequestsFunc func(ctx context.Context, userID uuid.UUID) ([]models.FriendRequest, error)
-	ListSentRequestsFunc    func(ctx context.Context, userID uuid.UUID) ([]models.FriendWithUser, error)
-	IsFriendFunc            func(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error)
-	GetFriendUserIDFunc     func(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error)
+	SearchUsersFunc           func(ctx context.Context, currentUserID uuid.UUID, query string) ([]models.UserSearchResult, error)
+	SuggestionsFunc           func(ctx context.Context, userID uuid.UUID) ([]models.FriendSuggestion, error)
+	DiscoverByEmailHashesFunc func(ctx context.Context, userID uuid.UUID, hashes []string) ([]models.UserSearchResult, error)
+	SendRequestFunc           func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error)
+	AcceptRequestFunc         func(ctx context.Context, userID, friendshipID uuid.UUID) (*models.Friendship, error)
+	RejectRequestFunc         func(ctx context.Context, userID, friendshipID uuid.UUID) error
+	RemoveFriendFunc          func(ctx context.Context, userID, friendshipID uuid.UUID) error
+	CancelRequestFunc         func(ctx context.Context, userID, friendshipID uuid.UUID) error
+	ListFriendsFunc           func(ctx context.Context, userID uuid.UUID) ([]models.FriendWithUser, error)
+	ListPendingRequestsFunc   func(ctx context.Context, userID uuid.UUID) ([]models.FriendRequest, error)
+	ListSentRequestsFunc      func(ctx context.Context, userID uuid.UUID) ([]models.FriendWithUser, error)
+	IsFriendFunc              func(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error)
+	GetFriendUserIDFunc       func(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error)
 }
 
 func (m *mockFriendService) SearchUsers(ctx context.Context, currentUserID uuid.UUID, query string) ([]models.UserSearchResult, error) {
@@ -445,6 +527,20 @@ func (m *mockFriendService) SearchUsers(ctx context.Context, currentUserID uuid.
 	return nil, nil
 }
 
+func (m *mockFriendService) Suggestions(ctx context.Context, userID uuid.UUID) ([]models.FriendSuggestion, error) {
+	if m.SuggestionsFunc != nil {
+		return m.SuggestionsFunc(ctx, userID)
+	}
+	return []models.FriendSuggestion{}, nil
+}
+
+func (m *mockFriendService) DiscoverByEmailHashes(ctx context.Context, userID uuid.UUID, hashes []string) ([]models.UserSearchResult, error) {
+	if m.DiscoverByEmailHashesFunc != nil {
+		return m.DiscoverByEmailHashesFunc(ctx, userID, hashes)
+	}
+	return []models.UserSearchResult{}, nil
+}
+
 func (m *mockFriendService) SendRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
 	if m.SendRequestFunc != nil {
 		return m.SendRequestFunc(ctx, userID, friendID)
@@ -614,6 +710,9 @@ type mockNotificationService struct {
 	NotifyAcceptedFunc func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
 	NotifyNewCardFunc  func(ctx context.Context, actorID, cardID uuid.UUID) error
 	NotifyBingoFunc    func(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error
+	NotifyCommentFunc  func(ctx context.Context, recipientID, actorID, cardID, commentID uuid.UUID) error
+	NotifyResultFunc   func(ctx context.Context, challengeID uuid.UUID, winnerID *uuid.UUID) error
+	NotifyOrgTmplFunc  func(ctx context.Context, actorID, templateID uuid.UUID) error
 }
 
 func (m *mockNotificationService) GetSettings(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error) {
@@ -699,3 +798,297 @@ func (m *mockNotificationService) NotifyFriendsBingo(ctx context.Context, actorI
 	}
 	return nil
 }
+
+func (m *mockNotificationService) NotifyItemComment(ctx context.Context, recipientID, actorID, cardID, commentID uuid.UUID) error {
+	if m.NotifyCommentFunc != nil {
+		return m.NotifyCommentFunc(ctx, recipientID, actorID, cardID, commentID)
+	}
+	return nil
+}
+
+func (m *mockNotificationService) NotifyChallengeResult(ctx context.Context, challengeID uuid.UUID, winnerID *uuid.UUID) error {
+	if m.NotifyResultFunc != nil {
+		return m.NotifyResultFunc(ctx, challengeID, winnerID)
+	}
+	return nil
+}
+
+func (m *mockNotificationService) NotifyOrgTemplate(ctx context.Context, actorID, templateID uuid.UUID) error {
+	if m.NotifyOrgTmplFunc != nil {
+		return m.NotifyOrgTmplFunc(ctx, actorID, templateID)
+	}
+	return nil
+}
+
+type recordedAccountEvent struct {
+	UserID    uuid.UUID
+	EventType models.AccountEventType
+	Outcome   models.AccountEventOutcome
+	Details   map[string]any
+}
+
+type mockAccountEventService struct {
+	recorded []recordedAccountEvent
+	ListFunc func(ctx context.Context, userID uuid.UUID, params services.AccountEventListParams) ([]models.AccountEvent, error)
+}
+
+func (m *mockAccountEventService) Record(ctx context.Context, userID uuid.UUID, eventType models.AccountEventType, outcome models.AccountEventOutcome, details map[string]any) error {
+	m.recorded = append(m.recorded, recordedAccountEvent{UserID: userID, EventType: eventType, Outcome: outcome, Details: details})
+	return nil
+}
+
+func (m *mockAccountEventService) RecordLogin(ctx context.Context, user *models.User, eventType models.AccountEventType) error {
+	return m.Record(ctx, user.ID, eventType, models.AccountEventSuccess, nil)
+}
+
+func (m *mockAccountEventService) List(ctx context.Context, userID uuid.UUID, params services.AccountEventListParams) ([]models.AccountEvent, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID, params)
+	}
+	return []models.AccountEvent{}, nil
+}
+
+type mockActivityService struct {
+	FeedFunc func(ctx context.Context, viewerID uuid.UUID, params services.FeedParams) ([]models.FeedItem, string, error)
+}
+
+func (m *mockActivityService) Record(ctx context.Context, event models.ActivityEvent) error {
+	return nil
+}
+
+func (m *mockActivityService) Remove(ctx context.Context, actorID uuid.UUID, eventType models.ActivityEventType, itemID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockActivityService) Feed(ctx context.Context, viewerID uuid.UUID, params services.FeedParams) ([]models.FeedItem, string, error) {
+	if m.FeedFunc != nil {
+		return m.FeedFunc(ctx, viewerID, params)
+	}
+	return []models.FeedItem{}, "", nil
+}
+
+type mockCommentService struct {
+	CreateFunc func(ctx context.Context, userID, itemID uuid.UUID, parentID *uuid.UUID, body string) (*models.ItemComment, error)
+	UpdateFunc func(ctx context.Context, userID, commentID uuid.UUID, body string) (*models.ItemComment, error)
+	DeleteFunc func(ctx context.Context, userID, commentID uuid.UUID) error
+	ListFunc   func(ctx context.Context, userID, itemID uuid.UUID) ([]models.ItemComment, error)
+}
+
+func (m *mockCommentService) Create(ctx context.Context, userID, itemID uuid.UUID, parentID *uuid.UUID, body string) (*models.ItemComment, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, itemID, parentID, body)
+	}
+	return nil, nil
+}
+
+func (m *mockCommentService) Update(ctx context.Context, userID, commentID uuid.UUID, body string) (*models.ItemComment, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, userID, commentID, body)
+	}
+	return nil, nil
+}
+
+func (m *mockCommentService) Delete(ctx context.Context, userID, commentID uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, commentID)
+	}
+	return nil
+}
+
+func (m *mockCommentService) List(ctx context.Context, userID, itemID uuid.UUID) ([]models.ItemComment, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID, itemID)
+	}
+	return nil, nil
+}
+
+type mockFriendGroupService struct {
+	ListFunc          func(ctx context.Context, ownerID uuid.UUID) ([]models.FriendGroup, error)
+	CreateFunc        func(ctx context.Context, ownerID uuid.UUID, name string) (*models.FriendGroup, error)
+	RenameFunc        func(ctx context.Context, ownerID, groupID uuid.UUID, name string) error
+	DeleteFunc        func(ctx context.Context, ownerID, groupID uuid.UUID) error
+	AddMemberFunc     func(ctx context.Context, ownerID, groupID, memberID uuid.UUID) error
+	RemoveMemberFunc  func(ctx context.Context, ownerID, groupID, memberID uuid.UUID) error
+	SharedCardIDsFunc func(ctx context.Context, ownerID, viewerID uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+func (m *mockFriendGroupService) List(ctx context.Context, ownerID uuid.UUID) ([]models.FriendGroup, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, ownerID)
+	}
+	return []models.FriendGroup{}, nil
+}
+
+func (m *mockFriendGroupService) Create(ctx context.Context, ownerID uuid.UUID, name string) (*models.FriendGroup, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, ownerID, name)
+	}
+	return nil, nil
+}
+
+func (m *mockFriendGroupService) Rename(ctx context.Context, ownerID, groupID uuid.UUID, name string) error {
+	if m.RenameFunc != nil {
+		return m.RenameFunc(ctx, ownerID, groupID, name)
+	}
+	return nil
+}
+
+func (m *mockFriendGroupService) Delete(ctx context.Context, ownerID, groupID uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, ownerID, groupID)
+	}
+	return nil
+}
+
+func (m *mockFriendGroupService) AddMember(ctx context.Context, ownerID, groupID, memberID uuid.UUID) error {
+	if m.AddMemberFunc != nil {
+		return m.AddMemberFunc(ctx, ownerID, groupID, memberID)
+	}
+	return nil
+}
+
+func (m *mockFriendGroupService) RemoveMember(ctx context.Context, ownerID, groupID, memberID uuid.UUID) error {
+	if m.RemoveMemberFunc != nil {
+		return m.RemoveMemberFunc(ctx, ownerID, groupID, memberID)
+	}
+	return nil
+}
+
+func (m *mockFriendGroupService) SharedCardIDs(ctx context.Context, ownerID, viewerID uuid.UUID) (map[uuid.UUID]bool, error) {
+	if m.SharedCardIDsFunc != nil {
+		return m.SharedCardIDsFunc(ctx, ownerID, viewerID)
+	}
+	return nil, nil
+}
+
+type mockProfileService struct {
+	GetPublicProfileFunc func(ctx context.Context, username string) (*models.PublicProfile, error)
+}
+
+func (m *mockProfileService) GetPublicProfile(ctx context.Context, username string) (*models.PublicProfile, error) {
+	if m.GetPublicProfileFunc != nil {
+		return m.GetPublicProfileFunc(ctx, username)
+	}
+	return nil, services.ErrProfileNotFound
+}
+
+type mockLeaderboardService struct {
+	ForYearFunc func(ctx context.Context, viewerID uuid.UUID, year int) (*models.Leaderboard, error)
+}
+
+func (m *mockLeaderboardService) ForYear(ctx context.Context, viewerID uuid.UUID, year int) (*models.Leaderboard, error) {
+	if m.ForYearFunc != nil {
+		return m.ForYearFunc(ctx, viewerID, year)
+	}
+	return &models.Leaderboard{Year: year, Entries: []models.LeaderboardEntry{}}, nil
+}
+
+type mockChallengeService struct {
+	CreateFunc func(ctx context.Context, creatorID uuid.UUID, params models.CreateChallengeParams) (*models.Challenge, error)
+	ListFunc   func(ctx context.Context, userID uuid.UUID) ([]models.Challenge, error)
+	GetFunc    func(ctx context.Context, userID, challengeID uuid.UUID) (*models.ChallengeDetail, error)
+	JoinFunc   func(ctx context.Context, userID, challengeID uuid.UUID) error
+	LeaveFunc  func(ctx context.Context, userID, challengeID uuid.UUID) error
+	DeleteFunc func(ctx context.Context, userID, challengeID uuid.UUID) error
+}
+
+func (m *mockChallengeService) Create(ctx context.Context, creatorID uuid.UUID, params models.CreateChallengeParams) (*models.Challenge, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, creatorID, params)
+	}
+	return &models.Challenge{}, nil
+}
+
+func (m *mockChallengeService) List(ctx context.Context, userID uuid.UUID) ([]models.Challenge, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID)
+	}
+	return []models.Challenge{}, nil
+}
+
+func (m *mockChallengeService) Get(ctx context.Context, userID, challengeID uuid.UUID) (*models.ChallengeDetail, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, userID, challengeID)
+	}
+	return nil, services.ErrChallengeNotFound
+}
+
+func (m *mockChallengeService) Join(ctx context.Context, userID, challengeID uuid.UUID) error {
+	if m.JoinFunc != nil {
+		return m.JoinFunc(ctx, userID, challengeID)
+	}
+	return nil
+}
+
+func (m *mockChallengeService) Leave(ctx context.Context, userID, challengeID uuid.UUID) error {
+	if m.LeaveFunc != nil {
+		return m.LeaveFunc(ctx, userID, challengeID)
+	}
+	return nil
+}
+
+func (m *mockChallengeService) Delete(ctx context.Context, userID, challengeID uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, challengeID)
+	}
+	return nil
+}
+
+type mockTemplateService struct {
+	PublishFunc     func(ctx context.Context, params models.PublishTemplateParams) (*models.CardTemplate, error)
+	ListFunc        func(ctx context.Context, params models.TemplateListParams) ([]models.CardTemplate, bool, error)
+	ListMineFunc    func(ctx context.Context, ownerID uuid.UUID) ([]models.CardTemplate, error)
+	GetFunc         func(ctx context.Context, viewerID, templateID uuid.UUID) (*models.CardTemplate, error)
+	UpdateFunc      func(ctx context.Context, ownerID, templateID uuid.UUID, params models.UpdateTemplateParams) (*models.CardTemplate, error)
+	DeleteFunc      func(ctx context.Context, ownerID, templateID uuid.UUID) error
+	InstantiateFunc func(ctx context.Context, templateID uuid.UUID, params models.InstantiateTemplateParams) (*models.BingoCard, error)
+}
+
+func (m *mockTemplateService) Publish(ctx context.Context, params models.PublishTemplateParams) (*models.CardTemplate, error) {
+	if m.PublishFunc != nil {
+		return m.PublishFunc(ctx, params)
+	}
+	return &models.CardTemplate{ID: uuid.New(), OwnerID: params.OwnerID}, nil
+}
+
+func (m *mockTemplateService) List(ctx context.Context, params models.TemplateListParams) ([]models.CardTemplate, bool, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, params)
+	}
+	return []models.CardTemplate{}, false, nil
+}
+
+func (m *mockTemplateService) ListMine(ctx context.Context, ownerID uuid.UUID) ([]models.CardTemplate, error) {
+	if m.ListMineFunc != nil {
+		return m.ListMineFunc(ctx, ownerID)
+	}
+	return []models.CardTemplate{}, nil
+}
+
+func (m *mockTemplateService) Get(ctx context.Context, viewerID, templateID uuid.UUID) (*models.CardTemplate, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, viewerID, templateID)
+	}
+	return nil, services.ErrTemplateNotFound
+}
+
+func (m *mockTemplateService) Update(ctx context.Context, ownerID, templateID uuid.UUID, params models.UpdateTemplateParams) (*models.CardTemplate, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, ownerID, templateID, params)
+	}
+	return &models.CardTemplate{ID: templateID, OwnerID: ownerID}, nil
+}
+
+func (m *mockTemplateService) Delete(ctx context.Context, ownerID, templateID uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, ownerID, templateID)
+	}
+	return nil
+}
+
+func (m *mockTemplateService) Instantiate(ctx context.Context, templateID uuid.UUID, params models.InstantiateTemplateParams) (*models.BingoCard, error) {
+	if m.InstantiateFunc != nil {
+		return m.InstantiateFunc(ctx, templateID, params)
+	}
+	return &models.BingoCard{ID: uuid.New(), UserID: params.UserID, Year: params.Year, Title: params.Title}, nil
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type ModerationHandler struct {
+	moderationService services.ModerationServiceInterface
+}
+
+func NewModerationHandler(moderationService services.ModerationServiceInterface) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+type CreateReportRequest struct {
+	TargetType string  `json:"target_type"`
+	TargetID   string  `json:"target_id"`
+	Reason     string  `json:"reason"`
+	Details    *string `json:"details,omitempty"`
+	BlockUser  bool    `json:"block_user"`
+}
+
+// SubmittedReport is what a reporter gets back. It leaves out the target's
+// owner and the card it belongs to, which the reporter may not otherwise know.
+type SubmittedReport struct {
+	ID         uuid.UUID               `json:"id"`
+	TargetType models.ReportTargetType `json:"target_type"`
+	Reason     models.ReportReason     `json:"reason"`
+	Details    *string                 `json:"details,omitempty"`
+	Status     models.ReportStatus     `json:"status"`
+	CreatedAt  time.Time               `json:"created_at"`
+}
+
+type ReportResponse struct {
+	Report  *SubmittedReport `json:"report"`
+	Message string           `json:"message,omitempty"`
+}
+
+type ModerationQueueResponse struct {
+	Reports []models.ModerationReport `json:"reports"`
+}
+
+type ModerationActionRequest struct {
+	Note *string `json:"note,omitempty"`
+}
+
+func (h *ModerationHandler) Report(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req CreateReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	targetID, err := uuid.Parse(req.TargetID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid target ID")
+		return
+	}
+
+	report, err := h.moderationService.CreateReport(r.Context(), models.CreateReportParams{
+		ReporterID: user.ID,
+		TargetType: models.ReportTargetType(req.TargetType),
+		TargetID:   targetID,
+		Reason:     models.ReportReason(req.Reason),
+		Details:    req.Details,
+		BlockUser:  req.BlockUser,
+	})
+	switch {
+	case errors.Is(err, services.ErrInvalidReportTarget):
+		writeError(w, http.StatusBadRequest, "Target type must be user, card, item, or template")
+		return
+	case errors.Is(err, services.ErrInvalidReportReason):
+		writeError(w, http.StatusBadRequest, "Invalid reason")
+		return
+	case errors.Is(err, services.ErrReportDetailsTooLong):
+		writeError(w, http.StatusBadRequest, "Details must be 1000 characters or fewer")
+		return
+	case errors.Is(err, services.ErrCannotReportSelf):
+		writeError(w, http.StatusBadRequest, "Cannot report yourself")
+		return
+	case errors.Is(err, services.ErrReportTargetNotFound):
+		writeError(w, http.StatusNotFound, "Reported content not found")
+		return
+	case errors.Is(err, services.ErrReportExists):
+		writeError(w, http.StatusConflict, "You have already reported this")
+		return
+	case err != nil:
+		logError(r, "Error creating report", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, ReportResponse{
+		Report: &SubmittedReport{
+			ID:         report.ID,
+			TargetType: report.TargetType,
+			Reason:     report.Reason,
+			Details:    report.Details,
+			Status:     report.Status,
+			CreatedAt:  report.CreatedAt,
+		},
+		Message: "Report submitted",
+	})
+}
+
+func (h *ModerationHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	page, ok := parseAdminListParams(w, r)
+	if !ok {
+		return
+	}
+
+	status := models.ReportStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", models.ReportStatusOpen, models.ReportStatusActioned, models.ReportStatusDismissed:
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid status")
+		return
+	}
+
+	reports, err := h.moderationService.ListReports(r.Context(), admin.ID, services.ModerationListParams{
+		Status:          status,
+		AdminListParams: page,
+	})
+	if err != nil {
+		logError(r, "Error listing reports", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ModerationQueueResponse{Reports: reports})
+}
+
+func (h *ModerationHandler) DismissReport(w http.ResponseWriter, r *http.Request) {
+	h.resolveReport(w, r, h.moderationService.DismissReport, "Report dismissed")
+}
+
+func (h *ModerationHandler) HideItem(w http.ResponseWriter, r *http.Request) {
+	h.resolveReport(w, r, h.moderationService.HideReportedItem, "Item hidden")
+}
+
+func (h *ModerationHandler) HideTemplate(w http.ResponseWriter, r *http.Request) {
+	h.resolveReport(w, r, h.moderationService.HideReportedTemplate, "Template hidden")
+}
+
+func (h *ModerationHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	h.resolveReport(w, r, h.moderationService.SuspendReportedUser, "User suspended")
+}
+
+type reportAction func(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+
+func (h *ModerationHandler) resolveReport(w http.ResponseWriter, r *http.Request, action reportAction, message string) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	reportID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid report ID")
+		return
+	}
+
+	var req ModerationActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Note != nil {
+		note := strings.TrimSpace(*req.Note)
+		req.Note = &note
+		if note == "" {
+			req.Note = nil
+		}
+	}
+
+	err = action(r.Context(), admin.ID, reportID, req.Note)
+	switch {
+	case errors.Is(err, services.ErrReportNotFound):
+		writeError(w, http.StatusNotFound, "Open report not found")
+		return
+	case errors.Is(err, services.ErrReportNotItem):
+		writeError(w, http.StatusBadRequest, "Report does not target an item")
+		return
+	case errors.Is(err, services.ErrReportNotTemplate):
+		writeError(w, http.StatusBadRequest, "Report does not target a template")
+		return
+	case errors.Is(err, services.ErrAdminCannotModifySelf):
+		writeError(w, http.StatusBadRequest, "Cannot change your own account status")
+		return
+	case err != nil:
+		logError(r, "Error resolving report", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminMessageResponse{Message: message})
+}
+
+func (h *ModerationHandler) UnhideItem(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	itemID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	err = h.moderationService.UnhideItem(r.Context(), admin.ID, itemID)
+	if errors.Is(err, services.ErrItemNotFound) {
+		writeError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error unhiding item", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminMessageResponse{Message: "Item restored"})
+}
+
+func (h *ModerationHandler) UnhideTemplate(w http.ResponseWriter, r *http.Request) {
+	admin := GetUserFromContext(r.Context())
+	if admin == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	templateID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	err = h.moderationService.UnhideTemplate(r.Context(), admin.ID, templateID)
+	if errors.Is(err, services.ErrTemplateNotFound) {
+		writeError(w, http.StatusNotFound, "Template not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error unhiding template", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AdminMessageResponse{Message: "Template restored"})
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type mockModerationService struct {
+	CreateReportFunc         func(ctx context.Context, params models.CreateReportParams) (*models.Report, error)
+	ListReportsFunc          func(ctx context.Context, adminID uuid.UUID, params services.ModerationListParams) ([]models.ModerationReport, error)
+	DismissReportFunc        func(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+	HideReportedItemFunc     func(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+	SuspendReportedUserFunc  func(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+	UnhideItemFunc           func(ctx context.Context, adminID, itemID uuid.UUID) error
+	HideReportedTemplateFunc func(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+	UnhideTemplateFunc       func(ctx context.Context, adminID, templateID uuid.UUID) error
+}
+
+func (m *mockModerationService) CreateReport(ctx context.Context, params models.CreateReportParams) (*models.Report, error) {
+	if m.CreateReportFunc != nil {
+		return m.CreateReportFunc(ctx, params)
+	}
+	return &models.Report{ID: uuid.New(), Status: models.ReportStatusOpen}, nil
+}
+
+func (m *mockModerationService) ListReports(ctx context.Context, adminID uuid.UUID, params services.ModerationListParams) ([]models.ModerationReport, error) {
+	if m.ListReportsFunc != nil {
+		return m.ListReportsFunc(ctx, adminID, params)
+	}
+	return []models.ModerationReport{}, nil
+}
+
+func (m *mockModerationService) DismissReport(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+	if m.DismissReportFunc != nil {
+		return m.DismissReportFunc(ctx, adminID, reportID, note)
+	}
+	return nil
+}
+
+func (m *mockModerationService) HideReportedItem(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+	if m.HideReportedItemFunc != nil {
+		return m.HideReportedItemFunc(ctx, adminID, reportID, note)
+	}
+	return nil
+}
+
+func (m *mockModerationService) SuspendReportedUser(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+	if m.SuspendReportedUserFunc != nil {
+		return m.SuspendReportedUserFunc(ctx, adminID, reportID, note)
+	}
+	return nil
+}
+
+func (m *mockModerationService) UnhideItem(ctx context.Context, adminID, itemID uuid.UUID) error {
+	if m.UnhideItemFunc != nil {
+		return m.UnhideItemFunc(ctx, adminID, itemID)
+	}
+	return nil
+}
+
+func (m *mockModerationService) HideReportedTemplate(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+	if m.HideReportedTemplateFunc != nil {
+		return m.HideReportedTemplateFunc(ctx, adminID, reportID, note)
+	}
+	return nil
+}
+
+func (m *mockModerationService) UnhideTemplate(ctx context.Context, adminID, templateID uuid.UUID) error {
+	if m.UnhideTemplateFunc != nil {
+		return m.UnhideTemplateFunc(ctx, adminID, templateID)
+	}
+	return nil
+}
+
+func TestModerationHandler_Report_Success(t *testing.T) {
+	userID := uuid.New()
+	targetID := uuid.New()
+	handler := NewModerationHandler(&mockModerationService{
+		CreateReportFunc: func(ctx context.Context, params models.CreateReportParams) (*models.Report, error) {
+			if params.ReporterID != userID || params.TargetID != targetID {
+				t.Fatalf("unexpected params: %+v", params)
+			}
+			if params.TargetType != models.ReportTargetItem || params.Reason != models.ReportReasonSpam || !params.BlockUser {
+				t.Fatalf("unexpected params: %+v", params)
+			}
+			cardID := uuid.New()
+			return &models.Report{ID: uuid.New(), TargetUserID: uuid.New(), CardID: &cardID, ItemID: &targetID}, nil
+		},
+	})
+
+	body := `{"target_type":"item","target_id":"` + targetID.String() + `","reason":"spam","block_user":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/reports", bytes.NewBufferString(body))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr := httptest.NewRecorder()
+	handler.Report(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); strings.Contains(body, "target_user_id") || strings.Contains(body, "card_id") {
+		t.Fatalf("expected the owner and card to be left out, got %s", body)
+	}
+}
+
+func TestModerationHandler_Report_Errors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		msg    string
+	}{
+		{services.ErrInvalidReportTarget, http.StatusBadRequest, "Target type must be user, card, item, or template"},
+		{services.ErrInvalidReportReason, http.StatusBadRequest, "Invalid reason"},
+		{services.ErrCannotReportSelf, http.StatusBadRequest, "Cannot report yourself"},
+		{services.ErrReportTargetNotFound, http.StatusNotFound, "Reported content not found"},
+		{services.ErrReportExists, http.StatusConflict, "You have already reported this"},
+		{errors.New("boom"), http.StatusInternalServerError, "Internal server error"},
+	}
+	for _, tt := range tests {
+		handler := NewModerationHandler(&mockModerationService{
+			CreateReportFunc: func(ctx context.Context, params models.CreateReportParams) (*models.Report, error) {
+				return nil, tt.err
+			},
+		})
+		body := `{"target_type":"user","target_id":"` + uuid.New().String() + `","reason":"spam"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/reports", bytes.NewBufferString(body))
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+		rr := httptest.NewRecorder()
+		handler.Report(rr, req)
+		assertErrorResponse(t, rr, tt.status, tt.msg)
+	}
+}
+
+func TestModerationHandler_Report_InvalidTargetID(t *testing.T) {
+	handler := NewModerationHandler(&mockModerationService{})
+	req := httptest.NewRequest(http.MethodPost, "/api/reports", bytes.NewBufferString(`{"target_type":"user","target_id":"x","reason":"spam"}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.Report(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid target ID")
+}
+
+func TestModerationHandler_ListReports_InvalidStatus(t *testing.T) {
+	handler := NewModerationHandler(&mockModerationService{})
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/api/admin/reports?status=weird", nil), uuid.New())
+	rr := httptest.NewRecorder()
+	handler.ListReports(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid status")
+}
+
+func TestModerationHandler_HideItem_WithNote(t *testing.T) {
+	reportID := uuid.New()
+	handler := NewModerationHandler(&mockModerationService{
+		HideReportedItemFunc: func(ctx context.Context, adminID, gotReportID uuid.UUID, note *string) error {
+			if gotReportID != reportID {
+				t.Fatalf("unexpected report id %s", gotReportID)
+			}
+			if note == nil || *note != "slur" {
+				t.Fatalf("expected trimmed note, got %v", note)
+			}
+			return nil
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/reports/x/hide-item", bytes.NewBufferString(`{"note":" slur "}`)), uuid.New())
+	req.SetPathValue("id", reportID.String())
+	rr := httptest.NewRecorder()
+	handler.HideItem(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+}
+
+func TestModerationHandler_ResolveReport_Errors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		msg    string
+	}{
+		{services.ErrReportNotFound, http.StatusNotFound, "Open report not found"},
+		{services.ErrReportNotItem, http.StatusBadRequest, "Report does not target an item"},
+		{services.ErrReportNotTemplate, http.StatusBadRequest, "Report does not target a template"},
+		{services.ErrAdminCannotModifySelf, http.StatusBadRequest, "Cannot change your own account status"},
+	}
+	for _, tt := range tests {
+		handler := NewModerationHandler(&mockModerationService{
+			SuspendReportedUserFunc: func(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+				if note != nil {
+					t.Fatal("expected nil note for empty body")
+				}
+				return tt.err
+			},
+		})
+		req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/reports/x/suspend-user", nil), uuid.New())
+		req.SetPathValue("id", uuid.New().String())
+		rr := httptest.NewRecorder()
+		handler.SuspendUser(rr, req)
+		assertErrorResponse(t, rr, tt.status, tt.msg)
+	}
+}
+
+func TestModerationHandler_DismissReport_InvalidID(t *testing.T) {
+	handler := NewModerationHandler(&mockModerationService{})
+	req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/reports/x/dismiss", nil), uuid.New())
+	req.SetPathValue("id", "bad")
+	rr := httptest.NewRecorder()
+	handler.DismissReport(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid report ID")
+}
+
+func TestModerationHandler_UnhideItem_NotFound(t *testing.T) {
+	handler := NewModerationHandler(&mockModerationService{
+		UnhideItemFunc: func(ctx context.Context, adminID, itemID uuid.UUID) error {
+			return services.ErrItemNotFound
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/items/x/unhide", nil), uuid.New())
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.UnhideItem(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "Item not found")
+}
+
+func TestModerationHandler_UnhideTemplate_NotFound(t *testing.T) {
+	handler := NewModerationHandler(&mockModerationService{
+		UnhideTemplateFunc: func(ctx context.Context, adminID, templateID uuid.UUID) error {
+			return services.ErrTemplateNotFound
+		},
+	})
+	req := withAdmin(httptest.NewRequest(http.MethodPost, "/api/admin/templates/x/unhide", nil), uuid.New())
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.UnhideTemplate(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "Template not found")
+}
@@ -0,0 +1,587 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type OrganizationHandler struct {
+	orgService services.OrganizationServiceInterface
+	throttle   services.ThrottleServiceInterface
+}
+
+func NewOrganizationHandler(orgService services.OrganizationServiceInterface) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// SetThrottle limits how many invalid invite tokens a user can try. It
+// shares the friend invite budget.
+func (h *OrganizationHandler) SetThrottle(throttle services.ThrottleServiceInterface) {
+	h.throttle = throttle
+}
+
+type OrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+type OrgMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type OrgDomainRequest struct {
+	Domain string `json:"domain"`
+}
+
+type OrganizationResponse struct {
+	Organization *models.Organization `json:"organization,omitempty"`
+	Message      string               `json:"message,omitempty"`
+}
+
+type OrganizationListResponse struct {
+	Organizations []models.Organization `json:"organizations"`
+}
+
+type JoinableOrganizationsResponse struct {
+	Organizations []models.JoinableOrganization `json:"organizations"`
+}
+
+type OrgMembersResponse struct {
+	Members []models.OrgMember `json:"members"`
+}
+
+type OrgInviteResponse struct {
+	Invite  *models.OrgInvite `json:"invite,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+type OrgInviteListResponse struct {
+	Invites []models.OrgInvite `json:"invites"`
+}
+
+type OrgStatsResponse struct {
+	Stats *models.OrgStats `json:"stats"`
+}
+
+func (h *OrganizationHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgs, err := h.orgService.List(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error listing organizations", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrganizationListResponse{Organizations: orgs})
+}
+
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	org, err := h.orgService.Create(r.Context(), user.ID, req.Name)
+	if err != nil {
+		writeOrganizationError(w, r, err, "creating organization")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, OrganizationResponse{Organization: org})
+}
+
+func (h *OrganizationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	org, err := h.orgService.Get(r.Context(), user.ID, orgID)
+	if err != nil {
+		writeOrganizationError(w, r, err, "getting organization")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrganizationResponse{Organization: org})
+}
+
+func (h *OrganizationHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	var req OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.orgService.Rename(r.Context(), user.ID, orgID, req.Name); err != nil {
+		writeOrganizationError(w, r, err, "renaming organization")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrganizationResponse{Message: "Organization renamed"})
+}
+
+func (h *OrganizationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	if err := h.orgService.Delete(r.Context(), user.ID, orgID); err != nil {
+		writeOrganizationError(w, r, err, "deleting organization")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrganizationResponse{Message: "Organization deleted"})
+}
+
+func (h *OrganizationHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	members, err := h.orgService.ListMembers(r.Context(), user.ID, orgID)
+	if err != nil {
+		writeOrganizationError(w, r, err, "listing organization members")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrgMembersResponse{Members: members})
+}
+
+func (h *OrganizationHandler) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+	memberID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req OrgMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.orgService.SetMemberRole(r.Context(), user.ID, orgID, memberID, models.OrgRole(req.Role)); err != nil {
+		writeOrganizationError(w, r, err, "updating organization member")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrganizationResponse{Message: "Role updated"})
+}
+
+// RemoveMember removes a member. Members may remove themselves to leave.
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+	memberID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.orgService.RemoveMember(r.Context(), user.ID, orgID, memberID); err != nil {
+		writeOrganizationError(w, r, err, "removing organization member")
+		return
+	}
+
+	message := "Member removed"
+	if memberID == user.ID {
+		message = "You left the organization"
+	}
+	writeJSON(w, http.StatusOK, OrganizationResponse{Message: message})
+}
+
+// MemberCards returns the cards a member shares with the organization.
+func (h *OrganizationHandler) MemberCards(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+	memberID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	cards, err := h.orgService.MemberCards(r.Context(), user.ID, orgID, memberID)
+	if err != nil {
+		writeOrganizationError(w, r, err, "getting organization member cards")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cards)
+}
+
+func (h *OrganizationHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	var req CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	expiresInDays := req.ExpiresInDays
+	if expiresInDays == 0 {
+		expiresInDays = services.OrgInviteExpiryDefaultDays
+	}
+
+	invite, token, err := h.orgService.CreateInvite(r.Context(), user.ID, orgID, expiresInDays)
+	if err != nil {
+		writeOrganizationError(w, r, err, "creating organization invite")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, OrgInviteResponse{Invite: invite, URL: "#org-invite/" + token})
+}
+
+func (h *OrganizationHandler) ListInvites(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	invites, err := h.orgService.ListInvites(r.Context(), user.ID, orgID)
+	if err != nil {
+		writeOrganizationError(w, r, err, "listing organization invites")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrgInviteListResponse{Invites: invites})
+}
+
+func (h *OrganizationHandler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+	inviteID, err := uuid.Parse(r.PathValue("inviteId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid invite ID")
+		return
+	}
+
+	if err := h.orgService.RevokeInvite(r.Context(), user.ID, orgID, inviteID); err != nil {
+		writeOrganizationError(w, r, err, "revoking organization invite")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrgInviteResponse{Message: "Invite revoked"})
+}
+
+func (h *OrganizationHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req AcceptInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	target := services.ThrottleTarget{IP: getClientIP(r), Account: user.ID.String()}
+	if checkThrottle(w, r, h.throttle, services.ThrottleInviteAccept, target) {
+		return
+	}
+
+	org, err := h.orgService.AcceptInvite(r.Context(), user.ID, req.Token)
+	if errors.Is(err, services.ErrOrgInviteNotFound) {
+		failThrottle(r.Context(), h.throttle, services.ThrottleInviteAccept, target)
+	}
+	if err != nil {
+		writeOrganizationError(w, r, err, "accepting organization invite")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrganizationResponse{Organization: org, Message: "Joined organization"})
+}
+
+func (h *OrganizationHandler) AddDomain(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	var req OrgDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.orgService.AddDomain(r.Context(), user.ID, orgID, req.Domain); err != nil {
+		writeOrganizationError(w, r, err, "adding organization domain")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, OrganizationResponse{Message: "Domain added"})
+}
+
+func (h *OrganizationHandler) RemoveDomain(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	if err := h.orgService.RemoveDomain(r.Context(), user.ID, orgID, r.PathValue("domain")); err != nil {
+		writeOrganizationError(w, r, err, "removing organization domain")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrganizationResponse{Message: "Domain removed"})
+}
+
+// Joinable lists organizations the user can join with their verified email.
+func (h *OrganizationHandler) Joinable(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgs, err := h.orgService.Joinable(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error listing joinable organizations", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, JoinableOrganizationsResponse{Organizations: orgs})
+}
+
+func (h *OrganizationHandler) JoinByDomain(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	org, err := h.orgService.JoinByDomain(r.Context(), user.ID, orgID)
+	if err != nil {
+		writeOrganizationError(w, r, err, "joining organization")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrganizationResponse{Organization: org, Message: "Joined organization"})
+}
+
+func (h *OrganizationHandler) Templates(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	templates, err := h.orgService.Templates(r.Context(), user.ID, orgID)
+	if err != nil {
+		writeOrganizationError(w, r, err, "listing organization templates")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateListResponse{Templates: templates})
+}
+
+// Stats returns aggregate stats for ?year= (default: this year). Admins only.
+func (h *OrganizationHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	orgID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	year := time.Now().Year()
+	if raw := r.URL.Query().Get("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 2020 || parsed > year+1 {
+			writeError(w, http.StatusBadRequest, "Year must be between 2020 and next year")
+			return
+		}
+		year = parsed
+	}
+
+	stats, err := h.orgService.Stats(r.Context(), user.ID, orgID, year)
+	if err != nil {
+		writeOrganizationError(w, r, err, "getting organization stats")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OrgStatsResponse{Stats: stats})
+}
+
+func writeOrganizationError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidOrganizationName):
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Organization name must be 1-%d characters", models.MaxOrganizationNameLength))
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		writeError(w, http.StatusNotFound, "Organization not found")
+	case errors.Is(err, services.ErrNotOrgAdmin):
+		writeError(w, http.StatusForbidden, "Organization admin required")
+	case errors.Is(err, services.ErrAlreadyOrgMember):
+		writeError(w, http.StatusConflict, "Already a member of this organization")
+	case errors.Is(err, services.ErrOrgMemberNotFound):
+		writeError(w, http.StatusNotFound, "Member not found")
+	case errors.Is(err, services.ErrInvalidOrgRole):
+		writeError(w, http.StatusBadRequest, "Role must be admin or member")
+	case errors.Is(err, services.ErrLastOrgAdmin):
+		writeError(w, http.StatusConflict, "An organization needs at least one admin; promote someone else or delete the organization")
+	case errors.Is(err, services.ErrOrgInviteNotFound):
+		writeError(w, http.StatusNotFound, "Invite not found or expired")
+	case errors.Is(err, services.ErrOrgInviteExpiryOutOfRange):
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("expires_in_days must be between %d and %d", services.OrgInviteExpiryMinDays, services.OrgInviteExpiryMaxDays))
+	case errors.Is(err, services.ErrOrgInviteLimitReached):
+		writeError(w, http.StatusConflict, fmt.Sprintf("Invite limit reached (max %d active)", services.OrgInviteMaxActive))
+	case errors.Is(err, services.ErrInvalidOrgDomain):
+		writeError(w, http.StatusBadRequest, "Invalid domain")
+	case errors.Is(err, services.ErrOrgDomainNotAllowed):
+		writeError(w, http.StatusBadRequest, "Public email domains can't be claimed")
+	case errors.Is(err, services.ErrOrgDomainNotVerified):
+		writeError(w, http.StatusForbidden, "You need a verified email address at this domain to claim it")
+	case errors.Is(err, services.ErrOrgDomainTaken):
+		writeError(w, http.StatusConflict, "This domain already belongs to an organization")
+	case errors.Is(err, services.ErrOrgDomainNotFound):
+		writeError(w, http.StatusNotFound, "Domain not found")
+	default:
+		logError(r, "Error "+action, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type mockOrganizationService struct {
+	CreateFunc        func(ctx context.Context, userID uuid.UUID, name string) (*models.Organization, error)
+	RemoveMemberFunc  func(ctx context.Context, userID, orgID, memberID uuid.UUID) error
+	SetMemberRoleFunc func(ctx context.Context, userID, orgID, memberID uuid.UUID, role models.OrgRole) error
+	CreateInviteFunc  func(ctx context.Context, userID, orgID uuid.UUID, expiresInDays int) (*models.OrgInvite, string, error)
+	AcceptInviteFunc  func(ctx context.Context, userID uuid.UUID, token string) (*models.Organization, error)
+	AddDomainFunc     func(ctx context.Context, userID, orgID uuid.UUID, domain string) error
+	MemberCardsFunc   func(ctx context.Context, viewerID, orgID, memberID uuid.UUID) (*models.OrgMemberCards, error)
+	StatsFunc         func(ctx context.Context, userID, orgID uuid.UUID, year int) (*models.OrgStats, error)
+}
+
+func (m *mockOrganizationService) Create(ctx context.Context, userID uuid.UUID, name string) (*models.Organization, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, name)
+	}
+	return &models.Organization{ID: uuid.New(), Name: name, Role: models.OrgRoleAdmin}, nil
+}
+
+func (m *mockOrganizationService) List(ctx context.Context, userID uuid.UUID) ([]models.Organization, error) {
+	return []models.Organization{}, nil
+}
+
+func (m *mockOrganizationService) Get(ctx context.Context, userID, orgID uuid.UUID) (*models.Organization, error) {
+	return &models.Organization{ID: orgID}, nil
+}
+
+func (m *mockOrganizationService) Rename(ctx context.Context, userID, orgID uuid.UUID, name string) error {
+	return nil
+}
+
+func (m *mockOrganizationService) Delete(ctx context.Context, userID, orgID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockOrganizationService) ListMembers(ctx context.Context, userID, orgID uuid.UUID) ([]models.OrgMember, error) {
+	return []models.OrgMember{}, nil
+}
+
+func (m *mockOrganizationService) SetMemberRole(ctx context.Context, userID, orgID, memberID uuid.UUID, role models.OrgRole) error {
+	if m.SetMemberRoleFunc != nil {
+		return m.SetMemberRoleFunc(ctx, userID, orgID, memberID, role)
+	}
+	return nil
+}
+
+func (m *mockOrganizationService) RemoveMember(ctx context.Context, userID, orgID, memberID uuid.UUID) error {
+	if m.RemoveMemberFunc != nil {
+		return m.RemoveMemberFunc(ctx, userID, orgID, memberID)
+	}
+	return nil
+}
+
+func (m *mockOrganizationService) CreateInvite(ctx context.Context, userID, orgID uuid.UUID, expiresInDays int) (*models.OrgInvite, string, error) {
+	if m.CreateInviteFunc != nil {
+		return m.CreateInviteFunc(ctx, userID, orgID, expiresInDays)
+	}
+	return &models.OrgInvite{ID: uuid.New(), OrgID: orgID, CreatedAt: time.Now()}, "token", nil
+}
+
+func (m *mockOrganizationService) ListInvites(ctx context.Context, userID, orgID uuid.UUID) ([]models.OrgInvite, error) {
+	return []models.OrgInvite{}, nil
+}
+
+func (m *mockOrganizationService) RevokeInvite(ctx context.Context, userID, orgID, inviteID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockOrganizationService) AcceptInvite(ctx context.Context, userID uuid.UUID, token string) (*models.Organization, error) {
+	if m.AcceptInviteFunc != nil {
+		return m.AcceptInviteFunc(ctx, userID, token)
+	}
+	return &models.Organization{ID: uuid.New()}, nil
+}
+
+func (m *mockOrganizationService) AddDomain(ctx context.Context, userID, orgID uuid.UUID, domain string) error {
+	if m.AddDomainFunc != nil {
+		return m.AddDomainFunc(ctx, userID, orgID, domain)
+	}
+	return nil
+}
+
+func (m *mockOrganizationService) RemoveDomain(ctx context.Context, userID, orgID uuid.UUID, domain string) error {
+	return nil
+}
+
+func (m *mockOrganizationService) Joinable(ctx context.Context, userID uuid.UUID) ([]models.JoinableOrganization, error) {
+	return []models.JoinableOrganization{}, nil
+}
+
+func (m *mockOrganizationService) JoinByDomain(ctx context.Context, userID, orgID uuid.UUID) (*models.Organization, error) {
+	return &models.Organization{ID: orgID}, nil
+}
+
+func (m *mockOrganizationService) MemberCards(ctx context.Context, viewerID, orgID, memberID uuid.UUID) (*models.OrgMemberCards, error) {
+	if m.MemberCardsFunc != nil {
+		return m.MemberCardsFunc(ctx, viewerID, orgID, memberID)
+	}
+	return &models.OrgMemberCards{Cards: []*models.BingoCard{}}, nil
+}
+
+func (m *mockOrganizationService) Templates(ctx context.Context, userID, orgID uuid.UUID) ([]models.CardTemplate, error) {
+	return []models.CardTemplate{}, nil
+}
+
+func (m *mockOrganizationService) Stats(ctx context.Context, userID, orgID uuid.UUID, year int) (*models.OrgStats, error) {
+	if m.StatsFunc != nil {
+		return m.StatsFunc(ctx, userID, orgID, year)
+	}
+	return &models.OrgStats{Year: year}, nil
+}
+
+func orgRequest(method, target, body string, user *models.User) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	if user != nil {
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+	}
+	return req
+}
+
+func TestOrganizationHandler_RequiresAuth(t *testing.T) {
+	handler := NewOrganizationHandler(&mockOrganizationService{})
+	rr := httptest.NewRecorder()
+	handler.List(rr, orgRequest(http.MethodGet, "/api/orgs", "", nil))
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestOrganizationHandler_Create_InvalidName(t *testing.T) {
+	handler := NewOrganizationHandler(&mockOrganizationService{
+		CreateFunc: func(ctx context.Context, userID uuid.UUID, name string) (*models.Organization, error) {
+			return nil, services.ErrInvalidOrganizationName
+		},
+	})
+	rr := httptest.NewRecorder()
+	handler.Create(rr, orgRequest(http.MethodPost, "/api/orgs", `{"name":""}`, &models.User{ID: uuid.New()}))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Organization name must be 1-100 characters")
+}
+
+func TestOrganizationHandler_RemoveMember(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name    string
+		member  uuid.UUID
+		err     error
+		status  int
+		message string
+	}{
+		{name: "leave", member: userID, status: http.StatusOK, message: "You left the organization"},
+		{name: "remove", member: uuid.New(), status: http.StatusOK, message: "Member removed"},
+		{name: "last admin", member: userID, err: services.ErrLastOrgAdmin, status: http.StatusConflict},
+		{name: "not admin", member: uuid.New(), err: services.ErrNotOrgAdmin, status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewOrganizationHandler(&mockOrganizationService{
+				RemoveMemberFunc: func(ctx context.Context, actorID, orgID, memberID uuid.UUID) error {
+					return tt.err
+				},
+			})
+			req := orgRequest(http.MethodDelete, "/api/orgs/x/members/y", "", &models.User{ID: userID})
+			req.SetPathValue("id", uuid.New().String())
+			req.SetPathValue("userId", tt.member.String())
+			rr := httptest.NewRecorder()
+			handler.RemoveMember(rr, req)
+
+			if rr.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, rr.Code, rr.Body.String())
+			}
+			if tt.message != "" {
+				var resp OrganizationResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil || resp.Message != tt.message {
+					t.Fatalf("expected message %q, got %s", tt.message, rr.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestOrganizationHandler_SetMemberRole_InvalidRole(t *testing.T) {
+	handler := NewOrganizationHandler(&mockOrganizationService{
+		SetMemberRoleFunc: func(ctx context.Context, userID, orgID, memberID uuid.UUID, role models.OrgRole) error {
+			if role != "owner" {
+				t.Fatalf("expected role to be passed through, got %q", role)
+			}
+			return services.ErrInvalidOrgRole
+		},
+	})
+	req := orgRequest(http.MethodPut, "/api/orgs/x/members/y", `{"role":"owner"}`, &models.User{ID: uuid.New()})
+	req.SetPathValue("id", uuid.New().String())
+	req.SetPathValue("userId", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.SetMemberRole(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Role must be admin or member")
+}
+
+func TestOrganizationHandler_CreateInvite(t *testing.T) {
+	var gotDays int
+	handler := NewOrganizationHandler(&mockOrganizationService{
+		CreateInviteFunc: func(ctx context.Context, userID, orgID uuid.UUID, expiresInDays int) (*models.OrgInvite, string, error) {
+			gotDays = expiresInDays
+			return &models.OrgInvite{ID: uuid.New(), OrgID: orgID}, "secret", nil
+		},
+	})
+	req := orgRequest(http.MethodPost, "/api/orgs/x/invites", `{}`, &models.User{ID: uuid.New()})
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.CreateInvite(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+	if gotDays != services.OrgInviteExpiryDefaultDays {
+		t.Fatalf("expected default expiry, got %d", gotDays)
+	}
+	var resp OrgInviteResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil || resp.URL != "#org-invite/secret" {
+		t.Fatalf("expected invite URL, got %s", rr.Body.String())
+	}
+}
+
+func TestOrganizationHandler_AcceptInvite_NotFoundCountsAsFailure(t *testing.T) {
+	throttle := &mockThrottleService{}
+	handler := NewOrganizationHandler(&mockOrganizationService{
+		AcceptInviteFunc: func(ctx context.Context, userID uuid.UUID, token string) (*models.Organization, error) {
+			return nil, services.ErrOrgInviteNotFound
+		},
+	})
+	handler.SetThrottle(throttle)
+
+	rr := httptest.NewRecorder()
+	handler.AcceptInvite(rr, orgRequest(http.MethodPost, "/api/orgs/invites/accept", `{"token":"abc"}`, &models.User{ID: uuid.New()}))
+	assertErrorResponse(t, rr, http.StatusNotFound, "Invite not found or expired")
+	if ops := throttle.ops(); len(ops) != 2 || ops[1] != "fail" || throttle.calls[1].Route != services.ThrottleInviteAccept {
+		t.Fatalf("expected a recorded failure, got %+v", throttle.calls)
+	}
+}
+
+func TestOrganizationHandler_AddDomain_NotVerified(t *testing.T) {
+	handler := NewOrganizationHandler(&mockOrganizationService{
+		AddDomainFunc: func(ctx context.Context, userID, orgID uuid.UUID, domain string) error {
+			return services.ErrOrgDomainNotVerified
+		},
+	})
+	req := orgRequest(http.MethodPost, "/api/orgs/x/domains", `{"domain":"acme.com"}`, &models.User{ID: uuid.New()})
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.AddDomain(rr, req)
+	assertErrorResponse(t, rr, http.StatusForbidden, "You need a verified email address at this domain to claim it")
+}
+
+func TestOrganizationHandler_MemberCards_NotFound(t *testing.T) {
+	handler := NewOrganizationHandler(&mockOrganizationService{
+		MemberCardsFunc: func(ctx context.Context, viewerID, orgID, memberID uuid.UUID) (*models.OrgMemberCards, error) {
+			return nil, services.ErrOrgMemberNotFound
+		},
+	})
+	req := orgRequest(http.MethodGet, "/api/orgs/x/members/y/cards", "", &models.User{ID: uuid.New()})
+	req.SetPathValue("id", uuid.New().String())
+	req.SetPathValue("userId", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.MemberCards(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "Member not found")
+}
+
+func TestOrganizationHandler_Stats_Year(t *testing.T) {
+	var gotYear int
+	handler := NewOrganizationHandler(&mockOrganizationService{
+		StatsFunc: func(ctx context.Context, userID, orgID uuid.UUID, year int) (*models.OrgStats, error) {
+			gotYear = year
+			return &models.OrgStats{Year: year}, nil
+		},
+	})
+
+	req := orgRequest(http.MethodGet, "/api/orgs/x/stats?year=1999", "", &models.User{ID: uuid.New()})
+	req.SetPathValue("id", uuid.New().String())
+	rr := httptest.NewRecorder()
+	handler.Stats(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Year must be between 2020 and next year")
+
+	req = orgRequest(http.MethodGet, "/api/orgs/x/stats", "", &models.User{ID: uuid.New()})
+	req.SetPathValue("id", uuid.New().String())
+	rr = httptest.NewRecorder()
+	handler.Stats(rr, req)
+	if rr.Code != http.StatusOK || gotYear != time.Now().Year() {
+		t.Fatalf("expected this year's stats, got %d for %d", rr.Code, gotYear)
+	}
+}
@@ -1,16 +1,23 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
 	"path/filepath"
+	"strings"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/assets"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 type PageHandler struct {
-	templates *template.Template
-	manifest  *assets.Manifest
+	templates      *template.Template
+	manifest       *assets.Manifest
+	profileService services.ProfileServiceInterface
+	baseURL        string
 }
 
 func NewPageHandler(templatesDir string) (*PageHandler, error) {
@@ -32,8 +39,17 @@ func NewPageHandler(templatesDir string) (*PageHandler, error) {
 	}, nil
 }
 
+// SetProfileService enables server-rendered public profile pages. baseURL
+// is used for absolute Open Graph URLs and may be empty.
+func (h *PageHandler) SetProfileService(profileService services.ProfileServiceInterface, baseURL string) {
+	h.profileService = profileService
+	h.baseURL = strings.TrimRight(baseURL, "/")
+}
+
 type PageData struct {
 	Title               string
+	Description         string
+	OG                  *OpenGraph
 	HideHeader          bool
 	Content             template.HTML
 	Scripts             template.HTML
@@ -42,12 +58,21 @@ type PageData struct {
 	AnonymousCardJSPath string
 	AppJSPath           string
 	AIWizardJSPath      string
+	// Nonce is the request's Content-Security-Policy script nonce.
+	Nonce string
 }
 
-func (h *PageHandler) Index(w http.ResponseWriter, r *http.Request) {
-	// For a SPA, we serve the same template for all routes
-	// The JavaScript router handles the actual routing
-	data := PageData{
+// OpenGraph holds link preview metadata for pages that have their own.
+type OpenGraph struct {
+	Type     string
+	Title    string
+	URL      string
+	Username string
+}
+
+func (h *PageHandler) pageData(r *http.Request) PageData {
+	return PageData{
+		Nonce:               GetCSPNonceFromContext(r.Context()),
 		Title:               "Year of Bingo",
 		CSSPath:             h.manifest.GetCSS(),
 		APIJSPath:           h.manifest.GetAPIJS(),
@@ -55,13 +80,66 @@ func (h *PageHandler) Index(w http.ResponseWriter, r *http.Request) {
 		AppJSPath:           h.manifest.GetAppJS(),
 		AIWizardJSPath:      h.manifest.GetAIWizardJS(),
 	}
+}
 
+func (h *PageHandler) render(w http.ResponseWriter, data PageData) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := h.templates.ExecuteTemplate(w, "index.html", data); err != nil {
 		http.Error(w, "Template error: "+err.Error(), http.StatusInternalServerError)
 	}
 }
 
+func (h *PageHandler) Index(w http.ResponseWriter, r *http.Request) {
+	// For a SPA, we serve the same template for all routes
+	// The JavaScript router handles the actual routing
+	h.render(w, h.pageData(r))
+}
+
+// PublicProfile serves the SPA for /u/{username} with the profile's title,
+// description and Open Graph tags filled in so link previews work without
+// JavaScript. Profiles that don't exist or aren't public get the 404 page.
+func (h *PageHandler) PublicProfile(w http.ResponseWriter, r *http.Request) {
+	if h.profileService == nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	profile, err := h.profileService.GetPublicProfile(r.Context(), r.PathValue("username"))
+	if errors.Is(err, services.ErrProfileNotFound) {
+		h.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		logError(r, "Error getting public profile", err)
+		h.InternalError(w, r)
+		return
+	}
+
+	data := h.pageData(r)
+	// The template adds " - Year of Bingo" to the page title.
+	data.Title = profile.Username + "'s goals"
+	if profile.Stats.CardCount == 0 {
+		data.Description = fmt.Sprintf("%s is playing Year of Bingo.", profile.Username)
+	} else {
+		cards := "cards"
+		if profile.Stats.CardCount == 1 {
+			cards = "card"
+		}
+		data.Description = fmt.Sprintf("%s has completed %d of %d goals across %d bingo %s.",
+			profile.Username, profile.Stats.CompletedItems, profile.Stats.TotalItems, profile.Stats.CardCount, cards)
+	}
+	data.OG = &OpenGraph{
+		Type:     "profile",
+		Title:    data.Title,
+		Username: profile.Username,
+	}
+	if h.baseURL != "" {
+		data.OG.URL = h.baseURL + "/u/" + url.PathEscape(profile.Username)
+	}
+
+	h.render(w, data)
+}
+
 // NotFound renders the 404 error page.
 func (h *PageHandler) NotFound(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 func TestPageHandler_IndexAndErrors(t *testing.T) {
@@ -28,6 +33,20 @@ func TestPageHandler_IndexAndErrors(t *testing.T) {
 		}
 	})
 
+	t.Run("index carries the CSP nonce", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(SetCSPNonceInContext(req.Context(), "abc123=="))
+		rr := httptest.NewRecorder()
+
+		handler.Index(rr, req)
+
+		body := rr.Body.String()
+		scripts := strings.Count(body, "<script")
+		if scripts == 0 || strings.Count(body, `<script nonce="abc123=="`) != scripts {
+			t.Fatalf("expected every script tag to carry the nonce")
+		}
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/nope", nil)
 		rr := httptest.NewRecorder()
@@ -76,3 +95,56 @@ func TestPageHandler_Index_TemplateError(t *testing.T) {
 		t.Fatalf("expected status 500, got %d", rr.Code)
 	}
 }
+
+func TestPageHandler_PublicProfile(t *testing.T) {
+	handler, err := NewPageHandler("../../web/templates")
+	if err != nil {
+		t.Fatalf("failed to create page handler: %v", err)
+	}
+	handler.SetProfileService(&mockProfileService{
+		GetPublicProfileFunc: func(ctx context.Context, username string) (*models.PublicProfile, error) {
+			if username != "alice" {
+				return nil, services.ErrProfileNotFound
+			}
+			return &models.PublicProfile{
+				Username: "Alice",
+				Stats:    models.ProfileStats{CardCount: 2, TotalItems: 48, CompletedItems: 30},
+			}, nil
+		},
+	}, "https://yearofbingo.com/")
+
+	t.Run("found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/u/alice", nil)
+		req.SetPathValue("username", "alice")
+		rr := httptest.NewRecorder()
+
+		handler.PublicProfile(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+		body := rr.Body.String()
+		for _, want := range []string{
+			`<title>Alice&#39;s goals - Year of Bingo</title>`,
+			`<meta property="og:title" content="Alice&#39;s goals">`,
+			`<meta property="og:url" content="https://yearofbingo.com/u/Alice">`,
+			"Alice has completed 30 of 48 goals across 2 bingo cards.",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected page to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/u/bob", nil)
+		req.SetPathValue("username", "bob")
+		rr := httptest.NewRecorder()
+
+		handler.PublicProfile(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected status 404, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type ProfileHandler struct {
+	profileService services.ProfileServiceInterface
+}
+
+func NewProfileHandler(profileService services.ProfileServiceInterface) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+type ProfileResponse struct {
+	Profile *models.PublicProfile `json:"profile"`
+}
+
+// Get returns a user's public profile. It needs no session.
+func (h *ProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.profileService.GetPublicProfile(r.Context(), r.PathValue("username"))
+	if errors.Is(err, services.ErrProfileNotFound) {
+		writeError(w, http.StatusNotFound, "Profile not found")
+		return
+	}
+	if err != nil {
+		logError(r, "Error getting public profile", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ProfileResponse{Profile: profile})
+}
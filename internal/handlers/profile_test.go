@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestProfileHandler_Get(t *testing.T) {
+	handler := NewProfileHandler(&mockProfileService{
+		GetPublicProfileFunc: func(ctx context.Context, username string) (*models.PublicProfile, error) {
+			return &models.PublicProfile{Username: username, Badges: []models.Badge{}, Cards: []*models.BingoCard{}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profiles/alice", nil)
+	req.SetPathValue("username", "alice")
+	rr := httptest.NewRecorder()
+
+	handler.Get(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp ProfileResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Profile == nil || resp.Profile.Username != "alice" {
+		t.Fatalf("unexpected profile: %+v", resp.Profile)
+	}
+}
+
+func TestProfileHandler_Get_NotFound(t *testing.T) {
+	handler := NewProfileHandler(&mockProfileService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profiles/nobody", nil)
+	req.SetPathValue("username", "nobody")
+	rr := httptest.NewRecorder()
+
+	handler.Get(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusNotFound, "Profile not found")
+}
+
+func TestProfileHandler_Get_Error(t *testing.T) {
+	handler := NewProfileHandler(&mockProfileService{
+		GetPublicProfileFunc: func(ctx context.Context, username string) (*models.PublicProfile, error) {
+			return nil, errors.New("db down")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profiles/alice", nil)
+	req.SetPathValue("username", "alice")
+	rr := httptest.NewRecorder()
+
+	handler.Get(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
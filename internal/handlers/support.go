@@ -17,14 +17,16 @@ import (
 )
 
 const (
-	supportRateLimitMax    = 5             // max requests per window
-	supportRateLimitWindow = 1 * time.Hour // rate limit window
-	supportRateLimitPrefix = "ratelimit:support:"
+	defaultSupportRateLimitMax    = 5             // max requests per window
+	defaultSupportRateLimitWindow = 1 * time.Hour // rate limit window
+	supportRateLimitPrefix        = "ratelimit:support:"
 )
 
 type SupportHandler struct {
-	emailService services.EmailServiceInterface
-	rateLimiter  rateLimitStore
+	emailService    services.EmailServiceInterface
+	rateLimiter     rateLimitStore
+	rateLimitMax    int
+	rateLimitWindow time.Duration
 }
 
 func NewSupportHandler(emailService services.EmailServiceInterface, redisClient *redis.Client) *SupportHandler {
@@ -34,11 +36,20 @@ func NewSupportHandler(emailService services.EmailServiceInterface, redisClient
 	}
 
 	return &SupportHandler{
-		emailService: emailService,
-		rateLimiter:  rateLimiter,
+		emailService:    emailService,
+		rateLimiter:     rateLimiter,
+		rateLimitMax:    defaultSupportRateLimitMax,
+		rateLimitWindow: defaultSupportRateLimitWindow,
 	}
 }
 
+// SetRateLimit allows max support requests per client IP in each window.
+// Zero disables the limit.
+func (h *SupportHandler) SetRateLimit(max int, window time.Duration) {
+	h.rateLimitMax = max
+	h.rateLimitWindow = window
+}
+
 type SupportRequest struct {
 	Email    string `json:"email"`
 	Category string `json:"category"`
@@ -98,7 +109,7 @@ func (h *SupportHandler) Submit(w http.ResponseWriter, r *http.Request) {
 
 	// Send the support email
 	if err := h.emailService.SendSupportEmail(r.Context(), req.Email, req.Category, req.Message, userID); err != nil {
-		logging.Error("Failed to send support email", map[string]interface{}{
+		logging.FromContext(r.Context()).Error("Failed to send support email", map[string]interface{}{
 			"error":    err.Error(),
 			"email":    req.Email,
 			"category": req.Category,
@@ -107,7 +118,7 @@ func (h *SupportHandler) Submit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logging.Info("Support request submitted", map[string]interface{}{
+	logging.FromContext(r.Context()).Info("Support request submitted", map[string]interface{}{
 		"email":    req.Email,
 		"category": req.Category,
 		"user_id":  userID,
@@ -140,7 +151,7 @@ func (s redisRateLimitStore) Expire(ctx context.Context, key string, expiration
 
 // checkRateLimit checks if the client has exceeded the rate limit
 func (h *SupportHandler) checkRateLimit(r *http.Request, clientIP string) bool {
-	if h.rateLimiter == nil {
+	if h.rateLimiter == nil || h.rateLimitMax == 0 {
 		return true // no rate limiting if Redis not configured
 	}
 
@@ -150,35 +161,25 @@ func (h *SupportHandler) checkRateLimit(r *http.Request, clientIP string) bool {
 	// Increment counter
 	count, err := h.rateLimiter.Incr(ctx, key)
 	if err != nil {
-		logging.Error("Rate limit Redis error", map[string]interface{}{"error": err.Error()})
+		logging.FromContext(r.Context()).Error("Rate limit Redis error", map[string]interface{}{"error": err.Error()})
 		return true // allow request on Redis error
 	}
 
 	// Set expiry on first request
 	if count == 1 {
-		_ = h.rateLimiter.Expire(ctx, key, supportRateLimitWindow)
+		_ = h.rateLimiter.Expire(ctx, key, h.rateLimitWindow)
 	}
 
-	return count <= supportRateLimitMax
+	return count <= int64(h.rateLimitMax)
 }
 
-// getClientIP extracts the client IP from the request, respecting X-Forwarded-For
+// getClientIP returns the client IP resolved by the request metadata
+// middleware, which only believes forwarding headers from trusted proxies.
+// Without it the connection address is used; headers are never read here.
 func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header (set by Cloudflare/proxies)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// X-Forwarded-For can contain multiple IPs; the first one is the client
-		if idx := strings.Index(xff, ","); idx != -1 {
-			return strings.TrimSpace(xff[:idx])
-		}
-		return strings.TrimSpace(xff)
+	if ip := services.RequestMetaFromContext(r.Context()).IPAddress; ip != "" {
+		return ip
 	}
-
-	// Check X-Real-IP header
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return strings.TrimSpace(xri)
-	}
-
-	// Fall back to RemoteAddr
 	ip, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
 		return r.RemoteAddr
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 type fakeRateLimitStore struct {
@@ -45,7 +46,7 @@ func TestSupportHandler_checkRateLimit(t *testing.T) {
 
 	t.Run("store error allows", func(t *testing.T) {
 		store := &fakeRateLimitStore{incrErr: errors.New("boom")}
-		h := &SupportHandler{emailService: &mockEmailService{}, rateLimiter: store}
+		h := &SupportHandler{emailService: &mockEmailService{}, rateLimiter: store, rateLimitMax: defaultSupportRateLimitMax, rateLimitWindow: defaultSupportRateLimitWindow}
 		req := httptest.NewRequest(http.MethodPost, "/api/support", nil)
 		if ok := h.checkRateLimit(req, "1.2.3.4"); !ok {
 			t.Fatalf("expected allowed")
@@ -54,10 +55,10 @@ func TestSupportHandler_checkRateLimit(t *testing.T) {
 
 	t.Run("enforces limit and sets expiry once", func(t *testing.T) {
 		store := &fakeRateLimitStore{}
-		h := &SupportHandler{emailService: &mockEmailService{}, rateLimiter: store}
+		h := &SupportHandler{emailService: &mockEmailService{}, rateLimiter: store, rateLimitMax: defaultSupportRateLimitMax, rateLimitWindow: defaultSupportRateLimitWindow}
 		req := httptest.NewRequest(http.MethodPost, "/api/support", nil)
 
-		for i := 0; i < supportRateLimitMax; i++ {
+		for i := 0; i < defaultSupportRateLimitMax; i++ {
 			if ok := h.checkRateLimit(req, "1.2.3.4"); !ok {
 				t.Fatalf("expected allowed on attempt %d", i+1)
 			}
@@ -70,22 +71,44 @@ func TestSupportHandler_checkRateLimit(t *testing.T) {
 			t.Fatalf("expected expire to be called once, got %d", store.expireCalls)
 		}
 	})
+
+	t.Run("configured limit", func(t *testing.T) {
+		store := &fakeRateLimitStore{}
+		h := NewSupportHandler(&mockEmailService{}, nil)
+		h.rateLimiter = store
+		h.SetRateLimit(2, time.Minute)
+		req := httptest.NewRequest(http.MethodPost, "/api/support", nil)
+
+		if !h.checkRateLimit(req, "1.2.3.4") || !h.checkRateLimit(req, "1.2.3.4") {
+			t.Fatalf("expected first two requests allowed")
+		}
+		if h.checkRateLimit(req, "1.2.3.4") {
+			t.Fatalf("expected third request rejected")
+		}
+
+		h.SetRateLimit(0, time.Minute)
+		if !h.checkRateLimit(req, "1.2.3.4") {
+			t.Fatalf("expected zero to disable the limit")
+		}
+	})
 }
 
 func TestSupportHandler_getClientIP(t *testing.T) {
-	t.Run("x-forwarded-for", func(t *testing.T) {
+	t.Run("ignores forwarding headers", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/support", nil)
+		req.RemoteAddr = "198.51.100.4:1234"
 		req.Header.Set("X-Forwarded-For", "203.0.113.1, 203.0.113.2")
-		if got := getClientIP(req); got != "203.0.113.1" {
-			t.Fatalf("expected first ip, got %q", got)
+		req.Header.Set("X-Real-IP", "203.0.113.9")
+		if got := getClientIP(req); got != "198.51.100.4" {
+			t.Fatalf("expected remote address, got %q", got)
 		}
 	})
 
-	t.Run("x-real-ip", func(t *testing.T) {
+	t.Run("request meta", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/support", nil)
-		req.Header.Set("X-Real-IP", "203.0.113.9")
+		req = req.WithContext(services.WithRequestMeta(req.Context(), services.RequestMeta{IPAddress: "203.0.113.9"}))
 		if got := getClientIP(req); got != "203.0.113.9" {
-			t.Fatalf("expected x-real-ip, got %q", got)
+			t.Fatalf("expected resolved ip, got %q", got)
 		}
 	})
 }
@@ -138,8 +161,8 @@ func TestSupportHandler_Submit_Success(t *testing.T) {
 
 func TestSupportHandler_Submit_ValidationAndErrors(t *testing.T) {
 	t.Run("rate limited", func(t *testing.T) {
-		store := &fakeRateLimitStore{incrCount: supportRateLimitMax}
-		h := &SupportHandler{emailService: &mockEmailService{}, rateLimiter: store}
+		store := &fakeRateLimitStore{incrCount: defaultSupportRateLimitMax}
+		h := &SupportHandler{emailService: &mockEmailService{}, rateLimiter: store, rateLimitMax: defaultSupportRateLimitMax, rateLimitWindow: defaultSupportRateLimitWindow}
 
 		bodyBytes, _ := json.Marshal(SupportRequest{
 			Email:    "test@example.com",
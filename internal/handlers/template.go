@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type TemplateHandler struct {
+	templateService services.TemplateServiceInterface
+	cardService     services.CardServiceInterface
+}
+
+func NewTemplateHandler(templateService services.TemplateServiceInterface, cardService services.CardServiceInterface) *TemplateHandler {
+	return &TemplateHandler{templateService: templateService, cardService: cardService}
+}
+
+type PublishTemplateRequest struct {
+	CardID      string  `json:"card_id"`
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Visibility  string  `json:"visibility"`
+	OrgID       string  `json:"org_id,omitempty"`
+}
+
+type UpdateTemplateRequest struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Visibility  *string `json:"visibility,omitempty"`
+}
+
+type UseTemplateRequest struct {
+	Year  int     `json:"year"`
+	Title *string `json:"title,omitempty"`
+}
+
+type TemplateResponse struct {
+	Template *models.CardTemplate `json:"template,omitempty"`
+	Message  string               `json:"message,omitempty"`
+}
+
+type TemplateListResponse struct {
+	Templates []models.CardTemplate `json:"templates"`
+	HasMore   bool                  `json:"has_more"`
+}
+
+// List serves the public template gallery. It needs no session.
+func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	params := models.TemplateListParams{
+		Query:    q.Get("q"),
+		Category: q.Get("category"),
+		Sort:     models.TemplateSort(q.Get("sort")),
+	}
+
+	switch params.Sort {
+	case "", models.TemplateSortPopular, models.TemplateSortNewest:
+	default:
+		writeError(w, http.StatusBadRequest, "Sort must be popular or newest")
+		return
+	}
+	if params.Category != "" && !models.IsValidCategory(params.Category) {
+		writeError(w, http.StatusBadRequest, "Invalid category")
+		return
+	}
+	if raw := q.Get("grid_size"); raw != "" {
+		gridSize, err := strconv.Atoi(raw)
+		if err != nil || !models.IsValidGridSize(gridSize) {
+			writeError(w, http.StatusBadRequest, "Grid size must be 2, 3, 4, or 5")
+			return
+		}
+		params.GridSize = gridSize
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		params.Limit = limit
+	}
+	if raw := q.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+		params.Offset = offset
+	}
+
+	templates, hasMore, err := h.templateService.List(r.Context(), params)
+	if err != nil {
+		logError(r, "Error listing templates", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateListResponse{Templates: templates, HasMore: hasMore})
+}
+
+func (h *TemplateHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	templates, err := h.templateService.ListMine(r.Context(), user.ID)
+	if err != nil {
+		logError(r, "Error listing my templates", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateListResponse{Templates: templates})
+}
+
+// Get returns a single template. Unlisted templates are reachable by link,
+// so no session is needed.
+func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	viewerID := uuid.Nil
+	if user := GetUserFromContext(r.Context()); user != nil {
+		viewerID = user.ID
+	}
+
+	template, err := h.templateService.Get(r.Context(), viewerID, templateID)
+	if err != nil {
+		writeTemplateError(w, r, err, "getting template")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateResponse{Template: template})
+}
+
+func (h *TemplateHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req PublishTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cardID, err := uuid.Parse(req.CardID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	params := models.PublishTemplateParams{
+		OwnerID:     user.ID,
+		CardID:      cardID,
+		Title:       req.Title,
+		Description: req.Description,
+		Visibility:  models.TemplateVisibility(req.Visibility),
+	}
+	if req.OrgID != "" {
+		orgID, err := uuid.Parse(req.OrgID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid organization ID")
+			return
+		}
+		params.OrgID = &orgID
+	}
+
+	template, err := h.templateService.Publish(r.Context(), params)
+	if err != nil {
+		writeTemplateError(w, r, err, "publishing template")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, TemplateResponse{Template: template})
+}
+
+func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	templateID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	var req UpdateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	params := models.UpdateTemplateParams{Title: req.Title, Description: req.Description}
+	if req.Visibility != nil {
+		visibility := models.TemplateVisibility(*req.Visibility)
+		params.Visibility = &visibility
+	}
+
+	template, err := h.templateService.Update(r.Context(), user.ID, templateID, params)
+	if err != nil {
+		writeTemplateError(w, r, err, "updating template")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateResponse{Template: template})
+}
+
+func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	templateID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	if err := h.templateService.Delete(r.Context(), user.ID, templateID); err != nil {
+		writeTemplateError(w, r, err, "deleting template")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateResponse{Message: "Template deleted"})
+}
+
+// Use creates a draft card from a template. Like card create and import it
+// answers 409 card_exists when the user already has a card with that title
+// for the year.
+func (h *TemplateHandler) Use(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	templateID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	var req UseTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	currentYear := time.Now().Year()
+	if req.Year < 2020 || req.Year > currentYear+1 {
+		writeError(w, http.StatusBadRequest, "Year must be between 2020 and next year")
+		return
+	}
+
+	title := req.Title
+	if title == nil {
+		template, err := h.templateService.Get(r.Context(), user.ID, templateID)
+		if err != nil {
+			writeTemplateError(w, r, err, "getting template")
+			return
+		}
+		title = &template.Title
+	}
+
+	existingCard, err := h.cardService.CheckForConflict(r.Context(), user.ID, req.Year, title)
+	if err != nil && !errors.Is(err, services.ErrCardNotFound) {
+		logError(r, "Error checking for conflict", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if existingCard != nil {
+		writeCardExistsConflict(w, existingCard)
+		return
+	}
+
+	card, err := h.templateService.Instantiate(r.Context(), templateID, models.InstantiateTemplateParams{
+		UserID: user.ID,
+		Year:   req.Year,
+		Title:  title,
+	})
+	if err != nil {
+		writeTemplateError(w, r, err, "creating card from template")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CardResponse{Card: card})
+}
+
+func writeTemplateError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound):
+		writeError(w, http.StatusNotFound, "Template not found")
+	case errors.Is(err, services.ErrNotTemplateOwner):
+		writeError(w, http.StatusForbidden, "Access denied")
+	case errors.Is(err, services.ErrInvalidTemplateTitle):
+		writeError(w, http.StatusBadRequest, "Title must be 1 to 100 characters")
+	case errors.Is(err, services.ErrTemplateDescriptionTooLong):
+		writeError(w, http.StatusBadRequest, "Description must be 500 characters or fewer")
+	case errors.Is(err, services.ErrInvalidTemplateVisibility):
+		writeError(w, http.StatusBadRequest, "Visibility must be public, unlisted or organization; organization needs an org_id and can't be changed later")
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		writeError(w, http.StatusNotFound, "Organization not found")
+	case errors.Is(err, services.ErrNotOrgAdmin):
+		writeError(w, http.StatusForbidden, "Only organization admins can publish organization templates")
+	case errors.Is(err, services.ErrTemplateEmpty):
+		writeError(w, http.StatusBadRequest, "Card needs at least one item to publish")
+	case errors.Is(err, services.ErrCardNotFound):
+		writeError(w, http.StatusNotFound, "Card not found")
+	case errors.Is(err, services.ErrNotCardOwner):
+		writeError(w, http.StatusForbidden, "Access denied")
+	case errors.Is(err, services.ErrTitleTooLong):
+		writeError(w, http.StatusBadRequest, "Title must be 100 characters or less")
+	case errors.Is(err, services.ErrCardTitleExists):
+		writeError(w, http.StatusConflict, "You already have a card with this title for this year")
+	case errors.Is(err, services.ErrCardAlreadyExists):
+		writeError(w, http.StatusConflict, "You already have a card for this year. Give your new card a unique title.")
+	default:
+		logError(r, "Error "+action, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestTemplateHandler_List_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		msg   string
+	}{
+		{name: "sort", query: "?sort=random", msg: "Sort must be popular or newest"},
+		{name: "category", query: "?category=nope", msg: "Invalid category"},
+		{name: "grid size", query: "?grid_size=7", msg: "Grid size must be 2, 3, 4, or 5"},
+		{name: "limit", query: "?limit=0", msg: "Invalid limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTemplateHandler(&mockTemplateService{}, &mockCardService{})
+			rr := httptest.NewRecorder()
+			handler.List(rr, httptest.NewRequest(http.MethodGet, "/api/templates"+tt.query, nil))
+			assertErrorResponse(t, rr, http.StatusBadRequest, tt.msg)
+		})
+	}
+}
+
+func TestTemplateHandler_List_Success(t *testing.T) {
+	handler := NewTemplateHandler(&mockTemplateService{
+		ListFunc: func(ctx context.Context, params models.TemplateListParams) ([]models.CardTemplate, bool, error) {
+			if params.Query != "run" || params.Category != "health" || params.GridSize != 3 || params.Sort != models.TemplateSortNewest || params.Offset != 20 {
+				t.Fatalf("unexpected params: %+v", params)
+			}
+			return []models.CardTemplate{{ID: uuid.New(), Title: "Run more"}}, true, nil
+		},
+	}, &mockCardService{})
+
+	rr := httptest.NewRecorder()
+	handler.List(rr, httptest.NewRequest(http.MethodGet, "/api/templates?q=run&category=health&grid_size=3&sort=newest&offset=20", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp TemplateListResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Templates) != 1 || !resp.HasMore {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTemplateHandler_Get_Anonymous(t *testing.T) {
+	templateID := uuid.New()
+	handler := NewTemplateHandler(&mockTemplateService{
+		GetFunc: func(ctx context.Context, viewerID, gotTemplateID uuid.UUID) (*models.CardTemplate, error) {
+			if viewerID != uuid.Nil || gotTemplateID != templateID {
+				t.Fatalf("unexpected ids: %s %s", viewerID, gotTemplateID)
+			}
+			return &models.CardTemplate{ID: templateID}, nil
+		},
+	}, &mockCardService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/templates/"+templateID.String(), nil)
+	req.SetPathValue("id", templateID.String())
+	rr := httptest.NewRecorder()
+	handler.Get(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+}
+
+func TestTemplateHandler_Publish_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		msg    string
+	}{
+		{name: "empty", err: services.ErrTemplateEmpty, status: http.StatusBadRequest, msg: "Card needs at least one item to publish"},
+		{name: "not owner", err: services.ErrNotCardOwner, status: http.StatusForbidden, msg: "Access denied"},
+		{name: "visibility", err: services.ErrInvalidTemplateVisibility, status: http.StatusBadRequest, msg: "Visibility must be public, unlisted or organization; organization needs an org_id and can't be changed later"},
+		{name: "not org admin", err: services.ErrNotOrgAdmin, status: http.StatusForbidden, msg: "Only organization admins can publish organization templates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTemplateHandler(&mockTemplateService{
+				PublishFunc: func(ctx context.Context, params models.PublishTemplateParams) (*models.CardTemplate, error) {
+					return nil, tt.err
+				},
+			}, &mockCardService{})
+
+			body := bytes.NewBufferString(`{"card_id":"` + uuid.New().String() + `","visibility":"public"}`)
+			req := httptest.NewRequest(http.MethodPost, "/api/templates", body)
+			req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+			rr := httptest.NewRecorder()
+			handler.Publish(rr, req)
+
+			assertErrorResponse(t, rr, tt.status, tt.msg)
+		})
+	}
+}
+
+func TestTemplateHandler_Use_Conflict(t *testing.T) {
+	existingID := uuid.New()
+	handler := NewTemplateHandler(&mockTemplateService{
+		GetFunc: func(ctx context.Context, viewerID, templateID uuid.UUID) (*models.CardTemplate, error) {
+			return &models.CardTemplate{ID: templateID, Title: "Weekend goals"}, nil
+		},
+		InstantiateFunc: func(ctx context.Context, templateID uuid.UUID, params models.InstantiateTemplateParams) (*models.BingoCard, error) {
+			t.Fatal("expected no card to be created")
+			return nil, nil
+		},
+	}, &mockCardService{
+		CheckForConflictFunc: func(ctx context.Context, userID uuid.UUID, year int, title *string) (*models.BingoCard, error) {
+			if title == nil || *title != "Weekend goals" {
+				t.Fatalf("expected template title, got %v", title)
+			}
+			return &models.BingoCard{ID: existingID, Year: year, Title: title}, nil
+		},
+	})
+
+	templateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/templates/"+templateID.String()+"/use", bytes.NewBufferString(`{"year":2025}`))
+	req.SetPathValue("id", templateID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.Use(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	var resp ImportCardResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.Error != "card_exists" || resp.ExistingCard == nil || resp.ExistingCard.ID != existingID.String() {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTemplateHandler_Use_CustomTitle(t *testing.T) {
+	title := "My copy"
+	handler := NewTemplateHandler(&mockTemplateService{
+		GetFunc: func(ctx context.Context, viewerID, templateID uuid.UUID) (*models.CardTemplate, error) {
+			t.Fatal("expected no template lookup when a title is given")
+			return nil, nil
+		},
+		InstantiateFunc: func(ctx context.Context, templateID uuid.UUID, params models.InstantiateTemplateParams) (*models.BingoCard, error) {
+			if params.Title == nil || *params.Title != title || params.Year != 2025 {
+				t.Fatalf("unexpected params: %+v", params)
+			}
+			return &models.BingoCard{ID: uuid.New(), Title: params.Title}, nil
+		},
+	}, &mockCardService{
+		CheckForConflictFunc: func(ctx context.Context, userID uuid.UUID, year int, title *string) (*models.BingoCard, error) {
+			return nil, services.ErrCardNotFound
+		},
+	})
+
+	templateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/templates/"+templateID.String()+"/use", bytes.NewBufferString(`{"year":2025,"title":"My copy"}`))
+	req.SetPathValue("id", templateID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.Use(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+}
+
+func TestTemplateHandler_Use_InvalidYear(t *testing.T) {
+	handler := NewTemplateHandler(&mockTemplateService{}, &mockCardService{})
+
+	templateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/templates/"+templateID.String()+"/use", bytes.NewBufferString(`{"year":1999}`))
+	req.SetPathValue("id", templateID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.Use(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Year must be between 2020 and next year")
+}
+
+func TestTemplateHandler_Delete_NotOwner(t *testing.T) {
+	handler := NewTemplateHandler(&mockTemplateService{
+		DeleteFunc: func(ctx context.Context, ownerID, templateID uuid.UUID) error {
+			return services.ErrNotTemplateOwner
+		},
+	}, &mockCardService{})
+
+	templateID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/api/templates/"+templateID.String(), nil)
+	req.SetPathValue("id", templateID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.Delete(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusForbidden, "Access denied")
+}
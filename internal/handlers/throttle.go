@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// checkThrottle writes a 429 and returns true when the target must wait before
+// trying route again. A nil throttle never blocks.
+func checkThrottle(w http.ResponseWriter, r *http.Request, throttle services.ThrottleServiceInterface, route string, target services.ThrottleTarget) bool {
+	if throttle == nil {
+		return false
+	}
+	return writeThrottled(w, throttle.Allow(r.Context(), route, target))
+}
+
+// hitThrottle is checkThrottle for routes where every request counts as an
+// attempt, such as those that send email.
+func hitThrottle(w http.ResponseWriter, r *http.Request, throttle services.ThrottleServiceInterface, route string, target services.ThrottleTarget) bool {
+	if throttle == nil {
+		return false
+	}
+	return writeThrottled(w, throttle.Hit(r.Context(), route, target))
+}
+
+func failThrottle(ctx context.Context, throttle services.ThrottleServiceInterface, route string, target services.ThrottleTarget) {
+	if throttle != nil {
+		throttle.Fail(ctx, route, target)
+	}
+}
+
+func succeedThrottle(ctx context.Context, throttle services.ThrottleServiceInterface, route string, target services.ThrottleTarget) {
+	if throttle != nil {
+		throttle.Succeed(ctx, route, target)
+	}
+}
+
+func writeThrottled(w http.ResponseWriter, wait time.Duration) bool {
+	if wait <= 0 {
+		return false
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	writeError(w, http.StatusTooManyRequests, "Too many attempts. Please try again later.")
+	return true
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type throttleCall struct {
+	Op     string
+	Route  string
+	Target services.ThrottleTarget
+}
+
+type mockThrottleService struct {
+	wait  time.Duration
+	calls []throttleCall
+}
+
+func (m *mockThrottleService) Allow(ctx context.Context, route string, target services.ThrottleTarget) time.Duration {
+	m.calls = append(m.calls, throttleCall{"allow", route, target})
+	return m.wait
+}
+
+func (m *mockThrottleService) Fail(ctx context.Context, route string, target services.ThrottleTarget) {
+	m.calls = append(m.calls, throttleCall{"fail", route, target})
+}
+
+func (m *mockThrottleService) Hit(ctx context.Context, route string, target services.ThrottleTarget) time.Duration {
+	m.calls = append(m.calls, throttleCall{"hit", route, target})
+	return m.wait
+}
+
+func (m *mockThrottleService) Succeed(ctx context.Context, route string, target services.ThrottleTarget) {
+	m.calls = append(m.calls, throttleCall{"succeed", route, target})
+}
+
+func (m *mockThrottleService) ops() []string {
+	ops := make([]string, len(m.calls))
+	for i, c := range m.calls {
+		ops[i] = c.Op
+	}
+	return ops
+}
+
+func TestAuthHandler_Login_Throttled(t *testing.T) {
+	handler := NewAuthHandler(&mockUserService{
+		GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			t.Fatal("throttled login should not look up the user")
+			return nil, nil
+		},
+	}, &mockAuthService{}, nil, false)
+	handler.SetThrottle(&mockThrottleService{wait: 1500 * time.Millisecond})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewBufferString(`{"email":"A@example.com","password":"x"}`))
+	rr := httptest.NewRecorder()
+	handler.Login(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusTooManyRequests, "Too many attempts. Please try again later.")
+	if got := rr.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("expected Retry-After 2, got %q", got)
+	}
+}
+
+func TestAuthHandler_Login_ThrottleFailureAndSuccess(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "a@example.com", PasswordHash: "hash"}
+	valid := false
+	throttle := &mockThrottleService{}
+	handler := NewAuthHandler(&mockUserService{
+		GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			return user, nil
+		},
+	}, &mockAuthService{
+		VerifyPasswordFunc: func(hash, password string) bool { return valid },
+		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) {
+			return "token", nil
+		},
+	}, nil, false)
+	handler.SetThrottle(throttle)
+
+	for _, v := range []bool{false, true} {
+		valid = v
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewBufferString(`{"email":"A@example.com","password":"x"}`))
+		req.RemoteAddr = "203.0.113.5:4000"
+		handler.Login(httptest.NewRecorder(), req)
+	}
+
+	want := []string{"allow", "fail", "allow", "succeed"}
+	if got := throttle.ops(); len(got) != len(want) || got[1] != "fail" || got[3] != "succeed" {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	failed := throttle.calls[1]
+	if failed.Route != services.ThrottleLogin || failed.Target.IP != "203.0.113.5" || failed.Target.Account != "a@example.com" || failed.Target.Email != "a@example.com" {
+		t.Fatalf("unexpected fail call: %+v", failed)
+	}
+}
+
+func TestAuthHandler_Login_UnknownEmailCountsAsFailure(t *testing.T) {
+	throttle := &mockThrottleService{}
+	handler := NewAuthHandler(&mockUserService{
+		GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			return nil, services.ErrUserNotFound
+		},
+	}, &mockAuthService{}, nil, false)
+	handler.SetThrottle(throttle)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewBufferString(`{"email":"nobody@example.com","password":"x"}`))
+	handler.Login(httptest.NewRecorder(), req)
+
+	if len(throttle.calls) != 2 || throttle.calls[1].Op != "fail" || throttle.calls[1].Target.Email != "" {
+		t.Fatalf("expected a failure without lockout email, got %+v", throttle.calls)
+	}
+}
+
+func TestAuthHandler_ForgotPassword_Throttled(t *testing.T) {
+	throttle := &mockThrottleService{wait: time.Minute}
+	handler := NewAuthHandler(&mockUserService{
+		GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			t.Fatal("throttled request should not look up the user")
+			return nil, nil
+		},
+	}, &mockAuthService{}, &mockEmailService{}, false)
+	handler.SetThrottle(throttle)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/forgot-password", bytes.NewBufferString(`{"email":"a@example.com"}`))
+	rr := httptest.NewRecorder()
+	handler.ForgotPassword(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusTooManyRequests, "Too many attempts. Please try again later.")
+	if throttle.calls[0].Op != "hit" || throttle.calls[0].Route != services.ThrottleForgotPassword {
+		t.Fatalf("unexpected throttle calls: %+v", throttle.calls)
+	}
+}
+
+func TestAuthHandler_ResetPassword_InvalidTokenCountsAsFailure(t *testing.T) {
+	throttle := &mockThrottleService{}
+	handler := NewAuthHandler(&mockUserService{}, &mockAuthService{}, &mockEmailService{
+		VerifyPasswordResetTokenFunc: func(ctx context.Context, token string) (uuid.UUID, error) {
+			return uuid.Nil, errors.New("invalid reset token")
+		},
+	}, false)
+	handler.SetThrottle(throttle)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/reset-password", bytes.NewBufferString(`{"token":"bad","password":"SecurePass123"}`))
+	rr := httptest.NewRecorder()
+	handler.ResetPassword(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	if got := throttle.ops(); len(got) != 2 || got[1] != "fail" || throttle.calls[1].Route != services.ThrottleResetPassword {
+		t.Fatalf("unexpected throttle calls: %+v", throttle.calls)
+	}
+}
+
+func TestFriendInviteHandler_Accept_Throttle(t *testing.T) {
+	userID := uuid.New()
+	throttle := &mockThrottleService{}
+	handler := NewFriendInviteHandler(&mockInviteService{
+		AcceptInviteFunc: func(ctx context.Context, recipientID uuid.UUID, token string) (*models.UserSearchResult, error) {
+			return nil, services.ErrInviteNotFound
+		},
+	})
+	handler.SetThrottle(throttle)
+
+	newReq := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/friends/invites/accept", bytes.NewBufferString(`{"token":"guess"}`))
+		return req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	}
+
+	handler.Accept(httptest.NewRecorder(), newReq())
+	if len(throttle.calls) != 2 || throttle.calls[1].Op != "fail" || throttle.calls[1].Target.Account != userID.String() {
+		t.Fatalf("expected invalid token to count as a failure, got %+v", throttle.calls)
+	}
+
+	throttle.wait = time.Second
+	rr := httptest.NewRecorder()
+	handler.Accept(rr, newReq())
+	assertErrorResponse(t, rr, http.StatusTooManyRequests, "Too many attempts. Please try again later.")
+}
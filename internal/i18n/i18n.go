@@ -0,0 +1,173 @@
+// Package i18n holds the translation catalogs used for user-facing text that
+// is rendered on the server (primarily email).
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the last entry in every fallback chain.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var catalogs = mustLoadCatalogs()
+
+func mustLoadCatalogs() map[string]map[string]string {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: reading locales: %v", err))
+	}
+
+	loaded := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		data, err := localeFS.ReadFile(path.Join("locales", name))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: reading %s: %v", name, err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: parsing %s: %v", name, err))
+		}
+		loaded[strings.ToLower(strings.TrimSuffix(name, ".json"))] = messages
+	}
+
+	if _, ok := loaded[DefaultLocale]; !ok {
+		panic("i18n: missing default locale catalog")
+	}
+	return loaded
+}
+
+// SupportedLocales returns the locales that have a catalog, sorted.
+func SupportedLocales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// IsSupported reports whether a catalog exists for exactly this locale.
+func IsSupported(locale string) bool {
+	_, ok := catalogs[strings.ToLower(locale)]
+	return ok
+}
+
+// Keys returns the message keys defined by a locale's catalog, sorted.
+func Keys(locale string) []string {
+	messages := catalogs[strings.ToLower(locale)]
+	keys := make([]string, 0, len(messages))
+	for key := range messages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Fallbacks returns the lookup chain for a locale tag, most specific first.
+// For example "es-MX" yields ["es-mx", "es", "en"].
+func Fallbacks(locale string) []string {
+	chain := expandTag(locale)
+	if len(chain) == 0 || chain[len(chain)-1] != DefaultLocale {
+		chain = append(chain, DefaultLocale)
+	}
+	return chain
+}
+
+// expandTag lowercases a tag and lists it along with its parents, without
+// the default locale.
+func expandTag(locale string) []string {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+
+	var chain []string
+	for tag != "" {
+		chain = append(chain, tag)
+		idx := strings.LastIndex(tag, "-")
+		if idx < 0 {
+			break
+		}
+		tag = tag[:idx]
+	}
+	return chain
+}
+
+// Normalize maps a locale tag to the closest supported locale.
+func Normalize(locale string) string {
+	for _, candidate := range Fallbacks(locale) {
+		if _, ok := catalogs[candidate]; ok {
+			return candidate
+		}
+	}
+	return DefaultLocale
+}
+
+// MatchAcceptLanguage picks the best supported locale for an Accept-Language
+// header, honouring q-values. It returns DefaultLocale when nothing matches.
+func MatchAcceptLanguage(header string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if value, ok := strings.CutPrefix(param, "q="); ok {
+				parsed, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					parsed = 0
+				}
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	for _, candidate := range tags {
+		for _, locale := range expandTag(candidate.tag) {
+			if _, ok := catalogs[locale]; ok {
+				return locale
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// T translates key for locale, walking the fallback chain. Arguments are
+// applied with fmt verbs; catalogs use explicit indexes (%[1]s) so
+// translations can reorder them. Unknown keys are returned unchanged.
+func T(locale, key string, args ...any) string {
+	for _, candidate := range Fallbacks(locale) {
+		if message, ok := catalogs[candidate][key]; ok {
+			if len(args) == 0 {
+				return message
+			}
+			return fmt.Sprintf(message, args...)
+		}
+	}
+	return key
+}
@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+func TestSupportedLocales(t *testing.T) {
+	want := []string{"de", "en", "es", "fr"}
+	if got := SupportedLocales(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("SupportedLocales() = %v, want %v", got, want)
+	}
+}
+
+func TestCatalogs_EveryKeyInEveryLocale(t *testing.T) {
+	reference := Keys(DefaultLocale)
+	if len(reference) == 0 {
+		t.Fatal("default catalog is empty")
+	}
+
+	for _, locale := range SupportedLocales() {
+		keys := map[string]bool{}
+		for _, key := range Keys(locale) {
+			keys[key] = true
+		}
+		for _, key := range reference {
+			if !keys[key] {
+				t.Errorf("locale %q is missing key %q", locale, key)
+			}
+			delete(keys, key)
+		}
+		for key := range keys {
+			t.Errorf("locale %q has key %q that is not in %q", locale, key, DefaultLocale)
+		}
+	}
+}
+
+var verbPattern = regexp.MustCompile(`%\[\d+\][a-z]`)
+
+func TestCatalogs_PlaceholdersMatchDefault(t *testing.T) {
+	for _, key := range Keys(DefaultLocale) {
+		want := placeholders(catalogs[DefaultLocale][key])
+		for _, locale := range SupportedLocales() {
+			message, ok := catalogs[locale][key]
+			if !ok {
+				continue
+			}
+			if got := placeholders(message); !reflect.DeepEqual(got, want) {
+				t.Errorf("locale %q key %q placeholders = %v, want %v", locale, key, got, want)
+			}
+		}
+	}
+}
+
+func placeholders(message string) []string {
+	found := verbPattern.FindAllString(message, -1)
+	sort.Strings(found)
+	return found
+}
+
+func TestFallbacks(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"es-MX", []string{"es-mx", "es", "en"}},
+		{"pt_BR", []string{"pt-br", "pt", "en"}},
+		{"en-GB", []string{"en-gb", "en"}},
+		{"en", []string{"en"}},
+		{"", []string{"en"}},
+	}
+	for _, tt := range tests {
+		if got := Fallbacks(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Fallbacks(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"de":    "de",
+		"DE-at": "de",
+		"fr-CA": "fr",
+		"pt-BR": "en",
+		"":      "en",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMatchAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"*", "en"},
+		{"fr-FR,fr;q=0.9,en;q=0.8", "fr"},
+		{"en;q=0.5, de;q=0.9", "de"},
+		{"pt-BR, es;q=0.7, en;q=0.3", "es"},
+		{"ja, zh", "en"},
+		{"es;q=0, de", "de"},
+		{"de;q=abc, fr", "fr"},
+		{"en-US,en;q=0.9", "en"},
+	}
+	for _, tt := range tests {
+		if got := MatchAcceptLanguage(tt.header); got != tt.want {
+			t.Errorf("MatchAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("es", "notification.friend_bingo.message_count", "Ana", "Mi cartón", 3); got != "Ana hizo bingo en Mi cartón (3 en total)." {
+		t.Fatalf("unexpected translation: %q", got)
+	}
+	if got := T("es-MX", "magic_link.button"); got != "Iniciar sesión" {
+		t.Fatalf("expected regional fallback to base language, got %q", got)
+	}
+	if got := T("pt", "magic_link.button"); got != "Sign In" {
+		t.Fatalf("expected fallback to default locale, got %q", got)
+	}
+	if got := T("de", "missing.key"); got != "missing.key" {
+		t.Fatalf("expected unknown key to be returned unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+package logging
+
+import "context"
+
+type loggerKey struct{}
+
+type requestIDKey struct{}
+
+// NewContext returns a copy of ctx carrying logger.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger attached with NewContext, or Default. The
+// request logging middleware attaches one tagged with the request ID, so
+// anything logged through it can be traced back to the request.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+			return logger
+		}
+	}
+	return Default
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID attached with WithRequestID,
+// or "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -1,9 +1,12 @@
 package logging
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"sort"
 	"sync"
 	"time"
 )
@@ -33,7 +36,40 @@ func (l Level) String() string {
 	}
 }
 
-// LogEntry represents a structured log entry.
+func (l Level) slog() slog.Level {
+	switch l {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Format selects how log records are written.
+type Format string
+
+const (
+	// FormatJSON writes one JSON object per line, for log collectors.
+	FormatJSON Format = "json"
+	// FormatText writes key=value lines, for reading in a terminal.
+	FormatText Format = "text"
+)
+
+// ParseFormat returns the Format named by s.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatJSON, FormatText:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("unknown log format %q (want json or text)", s)
+	}
+}
+
+// LogEntry represents a structured log entry as written in the JSON format.
 type LogEntry struct {
 	Timestamp string                 `json:"timestamp"`
 	Level     string                 `json:"level"`
@@ -41,21 +77,28 @@ type LogEntry struct {
 	Fields    map[string]interface{} `json:"fields,omitempty"`
 }
 
-// Logger provides structured JSON logging.
+// Logger provides structured logging on top of a slog.Handler. Loggers
+// derived with WithField or WithFields share their parent's output, format
+// and level.
 type Logger struct {
-	mu     sync.Mutex
-	output io.Writer
-	level  Level
-	fields map[string]interface{}
+	mu      *sync.RWMutex
+	output  io.Writer
+	format  Format
+	level   *slog.LevelVar
+	attrs   []slog.Attr
+	handler slog.Handler
 }
 
 // New creates a new Logger instance.
 func New() *Logger {
-	return &Logger{
+	l := &Logger{
+		mu:     &sync.RWMutex{},
 		output: os.Stdout,
-		level:  LevelInfo,
-		fields: make(map[string]interface{}),
+		format: FormatJSON,
+		level:  &slog.LevelVar{},
 	}
+	l.rebuild()
+	return l
 }
 
 // SetOutput sets the output writer for the logger.
@@ -63,17 +106,86 @@ func (l *Logger) SetOutput(w io.Writer) *Logger {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.output = w
+	l.rebuild()
 	return l
 }
 
-// SetLevel sets the minimum log level.
-func (l *Logger) SetLevel(level Level) *Logger {
+// SetFormat sets the output format for the logger.
+func (l *Logger) SetFormat(format Format) *Logger {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.level = level
+	l.format = format
+	l.rebuild()
 	return l
 }
 
+// SetLevel sets the minimum log level.
+func (l *Logger) SetLevel(level Level) *Logger {
+	l.level.Set(level.slog())
+	return l
+}
+
+// rebuild recreates the handler after the output or format changes. The
+// caller must hold l.mu.
+func (l *Logger) rebuild() {
+	l.handler = newHandler(l.output, l.format, l.level).WithAttrs(l.attrs)
+}
+
+// newHandler returns the slog handler for format. JSON records keep their
+// fields under a "fields" object so the top level stays fixed at timestamp,
+// level and message.
+func newHandler(w io.Writer, format Format, level slog.Leveler) slog.Handler {
+	if format == FormatText {
+		return slog.NewTextHandler(w, &slog.HandlerOptions{
+			Level:       level,
+			ReplaceAttr: replaceTextAttr,
+		})
+	}
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: replaceJSONAttr,
+	}).WithGroup("fields")
+}
+
+func replaceJSONAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 {
+		switch a.Key {
+		case slog.TimeKey:
+			return slog.String("timestamp", a.Value.Time().UTC().Format(time.RFC3339Nano))
+		case slog.MessageKey:
+			return slog.String("message", redactEmails(a.Value.String()))
+		case slog.LevelKey:
+			return a
+		}
+	}
+	return redactAttr(a)
+}
+
+func replaceTextAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 {
+		switch a.Key {
+		case slog.TimeKey, slog.LevelKey:
+			return a
+		case slog.MessageKey:
+			return slog.String(a.Key, redactEmails(a.Value.String()))
+		}
+	}
+	return redactAttr(a)
+}
+
+// Handler returns the slog handler the logger writes through.
+func (l *Logger) Handler() slog.Handler {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.handler
+}
+
+// Slog returns a *slog.Logger that writes through the same handler, for code
+// that wants the slog API directly.
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(l.Handler())
+}
+
 // WithField returns a new logger with an additional field.
 func (l *Logger) WithField(key string, value interface{}) *Logger {
 	return l.WithFields(map[string]interface{}{key: value})
@@ -81,17 +193,19 @@ func (l *Logger) WithField(key string, value interface{}) *Logger {
 
 // WithFields returns a new logger with additional fields.
 func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
-	newFields := make(map[string]interface{}, len(l.fields)+len(fields))
-	for k, v := range l.fields {
-		newFields[k] = v
-	}
-	for k, v := range fields {
-		newFields[k] = v
-	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	added := fieldAttrs(fields)
+	attrs := make([]slog.Attr, 0, len(l.attrs)+len(added))
+	attrs = append(attrs, l.attrs...)
+	attrs = append(attrs, added...)
 	return &Logger{
-		output: l.output,
-		level:  l.level,
-		fields: newFields,
+		mu:      &sync.RWMutex{},
+		output:  l.output,
+		format:  l.format,
+		level:   l.level,
+		attrs:   attrs,
+		handler: l.handler.WithAttrs(added),
 	}
 }
 
@@ -116,41 +230,41 @@ func (l *Logger) Error(msg string, fields ...map[string]interface{}) {
 }
 
 func (l *Logger) log(level Level, msg string, additionalFields ...map[string]interface{}) {
-	if level < l.level {
+	handler := l.Handler()
+	ctx := context.Background()
+	if !handler.Enabled(ctx, level.slog()) {
 		return
 	}
 
-	// Merge fields
-	allFields := make(map[string]interface{}, len(l.fields))
-	for k, v := range l.fields {
-		allFields[k] = v
-	}
-	for _, f := range additionalFields {
+	record := slog.NewRecord(time.Now(), level.slog(), msg, 0)
+	record.AddAttrs(fieldAttrs(additionalFields...)...)
+	_ = handler.Handle(ctx, record)
+}
+
+// fieldAttrs merges field maps into attributes sorted by key, later maps
+// winning on duplicate keys.
+func fieldAttrs(fields ...map[string]interface{}) []slog.Attr {
+	merged := make(map[string]interface{})
+	for _, f := range fields {
 		for k, v := range f {
-			allFields[k] = v
+			merged[k] = v
 		}
 	}
-
-	entry := LogEntry{
-		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
-		Level:     level.String(),
-		Message:   msg,
-	}
-	if len(allFields) > 0 {
-		entry.Fields = allFields
+	if len(merged) == 0 {
+		return nil
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
 
-	data, err := json.Marshal(entry)
-	if err != nil {
-		// Fallback to simple format
-		_, _ = l.output.Write([]byte(entry.Timestamp + " " + entry.Level + " " + msg + "\n"))
-		return
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, merged[k]))
 	}
-	_, _ = l.output.Write(data)
-	_, _ = l.output.Write([]byte("\n"))
+	return attrs
 }
 
 // Default is the default logger instance.
@@ -2,7 +2,9 @@ package logging
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -56,3 +58,88 @@ func TestDefaultLoggerHelpers(t *testing.T) {
 		t.Fatalf("expected default logger helper output, got %s", output)
 	}
 }
+
+func TestLoggerTextFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New().SetOutput(buf).SetFormat(FormatText)
+
+	logger.WithField("req", "123").Info("hello", map[string]interface{}{"status": 200})
+
+	output := buf.String()
+	for _, want := range []string{"level=INFO", "msg=hello", "req=123", "status=200"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in text output, got %s", want, output)
+		}
+	}
+}
+
+func TestLoggerSharesLevelWithDerivedLoggers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New().SetOutput(buf)
+	derived := logger.WithField("req", "123")
+
+	logger.SetLevel(LevelDebug)
+	derived.Debug("visible")
+	if !strings.Contains(buf.String(), "visible") {
+		t.Fatalf("expected derived logger to follow the parent's level")
+	}
+}
+
+func TestLoggerRedactsSensitiveFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New().SetOutput(buf)
+
+	logger.Error("failed sending to jane@example.com", map[string]interface{}{
+		"password":      "hunter2",
+		"refresh_token": "abc",
+		"token_id":      "t-1",
+		"tokens_total":  42,
+		"to":            "jane.doe@example.com",
+		"error":         errors.New("bounced: bob@example.org"),
+	})
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to parse log json: %v", err)
+	}
+	if entry.Message != "failed sending to j***@example.com" {
+		t.Errorf("expected email masked in message, got %q", entry.Message)
+	}
+	if entry.Fields["password"] != redacted || entry.Fields["refresh_token"] != redacted {
+		t.Errorf("expected secrets redacted, got %v", entry.Fields)
+	}
+	if entry.Fields["token_id"] != "t-1" || entry.Fields["tokens_total"] != float64(42) {
+		t.Errorf("expected non-secret token fields kept, got %v", entry.Fields)
+	}
+	if entry.Fields["to"] != "j***@example.com" || entry.Fields["error"] != "bounced: b***@example.org" {
+		t.Errorf("expected emails masked, got %v", entry.Fields)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("text"); err != nil || f != FormatText {
+		t.Errorf("expected text format, got %q (%v)", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected unknown format to be rejected")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != Default {
+		t.Error("expected Default without a logger in the context")
+	}
+
+	logger := New()
+	ctx := NewContext(context.Background(), logger)
+	if FromContext(ctx) != logger {
+		t.Error("expected the attached logger")
+	}
+
+	if RequestIDFromContext(ctx) != "" {
+		t.Error("expected no request ID")
+	}
+	if got := RequestIDFromContext(WithRequestID(ctx, "abc")); got != "abc" {
+		t.Errorf("expected request ID abc, got %q", got)
+	}
+}
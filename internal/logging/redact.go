@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys name fields whose values are never logged. A field matches
+// when its key is one of these or ends in "_" plus one of these, so
+// "refresh_token" is redacted but "token_id" and "tokens_total" are not.
+var sensitiveKeys = []string{
+	"password",
+	"password_hash",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"api_key",
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// IsSensitiveKey reports whether values logged under key are redacted.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, name := range sensitiveKeys {
+		if key == name || strings.HasSuffix(key, "_"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAttr hides the value of sensitive fields and masks email addresses
+// in string and error values.
+func redactAttr(a slog.Attr) slog.Attr {
+	if IsSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redactEmails(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, redactEmails(err.Error()))
+		}
+	}
+	return a
+}
+
+// redactEmails keeps the first character of each address's local part and
+// its domain: "jane@example.com" becomes "j***@example.com".
+func redactEmails(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return emailPattern.ReplaceAllStringFunc(s, func(addr string) string {
+		at := strings.LastIndex(addr, "@")
+		return addr[:1] + "***" + addr[at:]
+	})
+}
@@ -38,10 +38,11 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			if err == nil {
 				// Valid token, get user
 				user, err := m.userService.GetByID(r.Context(), token.UserID)
-				if err == nil {
+				if err == nil && !user.IsDisabled() {
 					// Add user and scope to context
 					ctx := handlers.SetUserInContext(r.Context(), user)
 					ctx = handlers.SetTokenScopeInContext(ctx, token.Scope)
+					ctx = handlers.SetTokenIDInContext(ctx, token.ID)
 
 					// Update last used
 					_ = m.apiTokenService.UpdateLastUsed(r.Context(), token.ID)
@@ -60,8 +61,8 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		}
 
 		user, err := m.authService.ValidateSession(r.Context(), cookie.Value)
-		if err != nil {
-			// Invalid session, continue without user
+		if err != nil || user.IsDisabled() {
+			// Invalid session or disabled account, continue without user
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -86,6 +87,26 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 	})
 }
 
+// RequireAdmin rejects requests from users who are not admins with 403.
+func (m *AuthMiddleware) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := handlers.GetUserFromContext(r.Context())
+		if user == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"Authentication required"}`))
+			return
+		}
+		if !user.IsAdmin {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"Admin access required"}`))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // RequireScope rejects requests that don't meet the required scope.
 // Session-authenticated users always have full access.
 func (m *AuthMiddleware) RequireScope(requiredScope models.ApiTokenScope) func(http.Handler) http.Handler {
@@ -131,6 +131,44 @@ func TestAuthMiddleware_RequireAuth_WithUser(t *testing.T) {
 	}
 }
 
+func TestAuthMiddleware_RequireAdmin(t *testing.T) {
+	am := &AuthMiddleware{authService: nil}
+
+	tests := []struct {
+		name   string
+		user   *models.User
+		status int
+		body   string
+	}{
+		{"no user", nil, http.StatusUnauthorized, `{"error":"Authentication required"}`},
+		{"non-admin", &models.User{ID: uuid.New()}, http.StatusForbidden, `{"error":"Admin access required"}`},
+		{"admin", &models.User{ID: uuid.New(), IsAdmin: true}, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+			if tt.user != nil {
+				req = req.WithContext(handlers.SetUserInContext(req.Context(), tt.user))
+			}
+			rr := httptest.NewRecorder()
+
+			am.RequireAdmin(handler).ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rr.Code)
+			}
+			if got := rr.Body.String(); got != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, got)
+			}
+		})
+	}
+}
+
 func TestAuthMiddleware_Authenticate_NoCookie(t *testing.T) {
 	am := &AuthMiddleware{authService: nil}
 
@@ -323,7 +361,7 @@ func TestAuthMiddleware_Authenticate_BearerToken(t *testing.T) {
 			}
 			if strings.Contains(sql, "FROM users") {
 				return middlewareFakeRow{values: []any{
-					userID, "user@example.com", "hash", "user", true, (*time.Time)(nil), 0, true, now, now,
+					userID, "user@example.com", "hash", "user", true, (*time.Time)(nil), 0, true, false, false, "en", false, (*time.Time)(nil), now, now,
 				}}
 			}
 			return middlewareFakeRow{values: []any{}}
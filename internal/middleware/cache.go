@@ -26,7 +26,8 @@ func (c *CacheControl) Apply(next http.Handler) http.Handler {
 			c.setStaticCacheHeaders(w, path)
 
 		case strings.HasPrefix(path, "/api/"):
-			// API responses should not be cached
+			// API responses should not be cached. Handlers that send an ETag
+			// relax this to "private, no-cache" so browsers can revalidate.
 			w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate")
 			w.Header().Set("Pragma", "no-cache")
 
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// ClientIPResolver works out which IP address a request came from. Anyone can
+// send X-Forwarded-For, X-Real-IP or CF-Connecting-IP, so those headers are
+// only believed when the connection itself comes from a trusted proxy.
+// Otherwise a client could dodge per-IP limits by changing the header on
+// every request, or lock out someone else's address.
+type ClientIPResolver struct {
+	trustedProxies      []netip.Prefix
+	trustCFConnectingIP bool
+}
+
+// NewClientIPResolver creates a resolver. With no trusted proxies every
+// request is keyed on its connection address.
+func NewClientIPResolver(trustedProxies []netip.Prefix, trustCFConnectingIP bool) *ClientIPResolver {
+	return &ClientIPResolver{trustedProxies: trustedProxies, trustCFConnectingIP: trustCFConnectingIP}
+}
+
+// Resolve returns the client IP for r.
+func (c *ClientIPResolver) Resolve(r *http.Request) string {
+	remote := remoteIP(r)
+	addr, err := netip.ParseAddr(remote)
+	if c == nil || err != nil || !c.trusted(addr) {
+		return remote
+	}
+
+	if c.trustCFConnectingIP {
+		if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("CF-Connecting-IP"))); err == nil {
+			return ip.Unmap().String()
+		}
+	}
+
+	// Walk X-Forwarded-For from the nearest hop back, skipping trusted
+	// proxies. The first address a trusted proxy didn't vouch for is the
+	// client; anything to its left was supplied by the client.
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	if len(hops) > 0 {
+		client := addr
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				break
+			}
+			client = hop.Unmap()
+			if !c.trusted(client) {
+				break
+			}
+		}
+		return client.String()
+	}
+
+	if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return ip.Unmap().String()
+	}
+	return remote
+}
+
+func (c *ClientIPResolver) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range c.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetClientIP returns the client IP that RequestMeta resolved for the
+// request, or the connection address when it hasn't run.
+func GetClientIP(r *http.Request) string {
+	if ip := services.RequestMetaFromContext(r.Context()).IPAddress; ip != "" {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestClientIPResolver_Resolve(t *testing.T) {
+	proxies := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("127.0.0.1/32"),
+	}
+
+	tests := []struct {
+		name     string
+		resolver *ClientIPResolver
+		headers  map[string]string
+		remote   string
+		expected string
+	}{
+		{
+			name:     "no trusted proxies ignores X-Forwarded-For",
+			resolver: NewClientIPResolver(nil, false),
+			headers:  map[string]string{"X-Forwarded-For": "203.0.113.1"},
+			remote:   "192.168.1.1:1234",
+			expected: "192.168.1.1",
+		},
+		{
+			name:     "untrusted remote ignores headers",
+			resolver: NewClientIPResolver(proxies, true),
+			headers:  map[string]string{"X-Forwarded-For": "203.0.113.1", "X-Real-IP": "203.0.113.2", "CF-Connecting-IP": "203.0.113.3"},
+			remote:   "198.51.100.7:1234",
+			expected: "198.51.100.7",
+		},
+		{
+			name:     "nil resolver uses remote address",
+			resolver: nil,
+			headers:  map[string]string{"X-Forwarded-For": "203.0.113.1"},
+			remote:   "192.168.1.1:1234",
+			expected: "192.168.1.1",
+		},
+		{
+			name:     "trusted proxy single hop",
+			resolver: NewClientIPResolver(proxies, false),
+			headers:  map[string]string{"X-Forwarded-For": "203.0.113.1"},
+			remote:   "10.0.0.5:1234",
+			expected: "203.0.113.1",
+		},
+		{
+			name:     "spoofed leftmost entry is skipped",
+			resolver: NewClientIPResolver(proxies, false),
+			headers:  map[string]string{"X-Forwarded-For": "1.2.3.4, 203.0.113.1, 10.0.0.9"},
+			remote:   "10.0.0.5:1234",
+			expected: "203.0.113.1",
+		},
+		{
+			name:     "malformed hop stops the walk",
+			resolver: NewClientIPResolver(proxies, false),
+			headers:  map[string]string{"X-Forwarded-For": "203.0.113.1, garbage"},
+			remote:   "10.0.0.5:1234",
+			expected: "10.0.0.5",
+		},
+		{
+			name:     "X-Real-IP from trusted proxy",
+			resolver: NewClientIPResolver(proxies, false),
+			headers:  map[string]string{"X-Real-IP": "203.0.113.2"},
+			remote:   "127.0.0.1:1234",
+			expected: "203.0.113.2",
+		},
+		{
+			name:     "CF-Connecting-IP ignored unless enabled",
+			resolver: NewClientIPResolver(proxies, false),
+			headers:  map[string]string{"CF-Connecting-IP": "203.0.113.3"},
+			remote:   "127.0.0.1:1234",
+			expected: "127.0.0.1",
+		},
+		{
+			name:     "CF-Connecting-IP from trusted proxy",
+			resolver: NewClientIPResolver(proxies, true),
+			headers:  map[string]string{"CF-Connecting-IP": "203.0.113.3", "X-Forwarded-For": "1.2.3.4"},
+			remote:   "127.0.0.1:1234",
+			expected: "203.0.113.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			req.RemoteAddr = tt.remote
+
+			if ip := tt.resolver.Resolve(req); ip != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, ip)
+			}
+		})
+	}
+}
+
+func TestGetClientIP(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "192.168.1.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	if ip := GetClientIP(req); ip != "192.168.1.1" {
+		t.Fatalf("expected remote address without request meta, got %s", ip)
+	}
+
+	req = req.WithContext(services.WithRequestMeta(req.Context(), services.RequestMeta{IPAddress: "203.0.113.9"}))
+	if ip := GetClientIP(req); ip != "203.0.113.9" {
+		t.Fatalf("expected resolved IP from request meta, got %s", ip)
+	}
+}
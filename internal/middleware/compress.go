@@ -8,16 +8,46 @@ import (
 	"sync"
 )
 
+// gzipETagSuffix marks the ETag of a gzipped response. A strong ETag names
+// exact bytes, so the compressed and uncompressed forms need different tags.
+const gzipETagSuffix = "-gzip"
+
 // gzipResponseWriter wraps http.ResponseWriter to provide gzip compression.
 type gzipResponseWriter struct {
 	http.ResponseWriter
-	writer io.Writer
+	writer      io.Writer
+	wroteHeader bool
+}
+
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	if etag := g.Header().Get("ETag"); strings.HasSuffix(etag, `"`) && !strings.HasSuffix(etag, gzipETagSuffix+`"`) {
+		g.Header().Set("ETag", strings.TrimSuffix(etag, `"`)+gzipETagSuffix+`"`)
+	}
+	g.ResponseWriter.WriteHeader(status)
 }
 
 func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
 	return g.writer.Write(b)
 }
 
+// stripGzipETags turns ETags we sent on gzipped responses back into the
+// handler's own tags, so conditional requests compare against what the
+// handler computes.
+func stripGzipETags(r *http.Request) {
+	for _, name := range []string{"If-None-Match", "If-Match"} {
+		if v := r.Header.Get(name); strings.Contains(v, gzipETagSuffix+`"`) {
+			r.Header.Set(name, strings.ReplaceAll(v, gzipETagSuffix+`"`, `"`))
+		}
+	}
+}
+
 // Pool of gzip writers to reduce allocations.
 var gzipPool = sync.Pool{
 	New: func() interface{} {
@@ -60,6 +90,8 @@ func (c *Compress) Apply(next http.Handler) http.Handler {
 			gzipPool.Put(gz)
 		}()
 
+		stripGzipETags(r)
+
 		w.Header().Set("Content-Encoding", "gzip")
 		w.Header().Set("Vary", "Accept-Encoding")
 		// Delete Content-Length as it will be different after compression
@@ -240,3 +240,27 @@ func TestCompress_VaryHeader(t *testing.T) {
 		t.Errorf("expected Vary: Accept-Encoding, got %q", got)
 	}
 }
+
+func TestCompress_DistinguishesGzipETags(t *testing.T) {
+	compress := NewCompress()
+
+	var gotIfNoneMatch string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = r.Header.Get("If-None-Match")
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = w.Write([]byte("body"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", `"abc-gzip"`)
+	rr := httptest.NewRecorder()
+	compress.Apply(handler).ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("ETag"); got != `"abc-gzip"` {
+		t.Errorf("expected gzip ETag, got %q", got)
+	}
+	if gotIfNoneMatch != `"abc"` {
+		t.Errorf("expected handler to see its own ETag, got %q", gotIfNoneMatch)
+	}
+}
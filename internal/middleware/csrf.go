@@ -17,12 +17,23 @@ const (
 
 type CSRFMiddleware struct {
 	secure bool
+	exempt map[string]bool
 }
 
 func NewCSRFMiddleware(secure bool) *CSRFMiddleware {
 	return &CSRFMiddleware{secure: secure}
 }
 
+// SetExemptPaths skips the token check for requests to paths that browsers
+// post to on their own, such as the CSP report endpoint. Those handlers must
+// not change anything on the user's behalf.
+func (m *CSRFMiddleware) SetExemptPaths(paths ...string) {
+	m.exempt = make(map[string]bool, len(paths))
+	for _, p := range paths {
+		m.exempt[p] = true
+	}
+}
+
 func (m *CSRFMiddleware) Protect(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Safe methods don't need CSRF protection
@@ -32,6 +43,11 @@ func (m *CSRFMiddleware) Protect(next http.Handler) http.Handler {
 			return
 		}
 
+		if m.exempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Validate CSRF token for state-changing methods
 		cookie, err := r.Cookie(csrfCookieName)
 		if err != nil {
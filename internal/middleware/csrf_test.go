@@ -278,3 +278,24 @@ func TestGenerateCSRFToken(t *testing.T) {
 		t.Errorf("token seems too short: %d chars", len(token1))
 	}
 }
+
+func TestCSRFMiddleware_ExemptPaths(t *testing.T) {
+	csrf := NewCSRFMiddleware(false)
+	csrf.SetExemptPaths(CSPReportPath)
+
+	handler := csrf.Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, CSPReportPath, nil))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected exempt path to skip the token check, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/test", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected other paths to still need a token, got %d", rr.Code)
+	}
+}
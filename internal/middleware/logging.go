@@ -2,11 +2,32 @@ package middleware
 
 import (
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 )
 
+// RequestIDHeader carries the request ID in and out. An incoming value is
+// kept when it looks like an ID, so a request can be traced through a proxy
+// that already assigned one.
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// healthCheckPaths are probed constantly by load balancers and orchestrators,
+// so successful requests to them are sampled rather than all logged.
+var healthCheckPaths = map[string]bool{
+	"/health": true,
+	"/ready":  true,
+	"/live":   true,
+}
+
 // responseRecorder wraps http.ResponseWriter to capture status code and size.
 type responseRecorder struct {
 	http.ResponseWriter
@@ -25,9 +46,13 @@ func (r *responseRecorder) Write(b []byte) (int, error) {
 	return n, err
 }
 
-// RequestLogger logs HTTP requests with timing information.
+// RequestLogger logs HTTP requests with timing information. It also assigns
+// each request an ID and attaches a logger tagged with it to the request
+// context, where handlers and services pick it up with logging.FromContext.
 type RequestLogger struct {
-	logger *logging.Logger
+	logger            *logging.Logger
+	healthSampleEvery uint64
+	healthSeen        atomic.Uint64
 }
 
 // NewRequestLogger creates a new request logging middleware.
@@ -35,7 +60,18 @@ func NewRequestLogger(logger *logging.Logger) *RequestLogger {
 	if logger == nil {
 		logger = logging.Default
 	}
-	return &RequestLogger{logger: logger}
+	return &RequestLogger{logger: logger, healthSampleEvery: 1}
+}
+
+// SetHealthCheckSampling logs one of every `every` successful health check
+// requests. Failed health checks are always logged, and values below 1 log
+// every request.
+func (l *RequestLogger) SetHealthCheckSampling(every int) *RequestLogger {
+	if every < 1 {
+		every = 1
+	}
+	l.healthSampleEvery = uint64(every)
+	return l
 }
 
 // Apply wraps the handler to log requests.
@@ -43,6 +79,17 @@ func (l *RequestLogger) Apply(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
+		requestID := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		reqLogger := l.logger.WithField("request_id", requestID)
+		ctx := logging.WithRequestID(r.Context(), requestID)
+		ctx = logging.NewContext(ctx, reqLogger)
+		r = r.WithContext(ctx)
+
 		// Wrap response writer to capture status and size
 		recorder := &responseRecorder{
 			ResponseWriter: w,
@@ -55,6 +102,11 @@ func (l *RequestLogger) Apply(next http.Handler) http.Handler {
 		// Calculate duration
 		duration := time.Since(start)
 
+		if recorder.statusCode < 400 && healthCheckPaths[r.URL.Path] &&
+			(l.healthSeen.Add(1)-1)%l.healthSampleEvery != 0 {
+			return
+		}
+
 		// Log request
 		fields := map[string]interface{}{
 			"method":      r.Method,
@@ -68,17 +120,66 @@ func (l *RequestLogger) Apply(next http.Handler) http.Handler {
 
 		// Add query string if present
 		if r.URL.RawQuery != "" {
-			fields["query"] = r.URL.RawQuery
+			fields["query"] = redactQuery(r.URL.RawQuery)
 		}
 
 		// Choose log level based on status code
 		switch {
 		case recorder.statusCode >= 500:
-			l.logger.Error("HTTP request", fields)
+			reqLogger.Error("HTTP request", fields)
 		case recorder.statusCode >= 400:
-			l.logger.Warn("HTTP request", fields)
+			reqLogger.Warn("HTTP request", fields)
 		default:
-			l.logger.Info("HTTP request", fields)
+			reqLogger.Info("HTTP request", fields)
 		}
 	})
 }
+
+// redactQuery hides the values of sensitive query parameters, such as share
+// and feed tokens, before the query string is logged.
+func redactQuery(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return redactRawQuery(raw)
+	}
+	changed := false
+	for key, vals := range values {
+		if logging.IsSensitiveKey(key) {
+			for i := range vals {
+				vals[i] = "REDACTED"
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+	return values.Encode()
+}
+
+// redactRawQuery redacts a query string url.ParseQuery rejects, such as one
+// with a bad escape or a ';' separator, pair by pair and otherwise as sent.
+// A key that can't be unescaped is checked as it is.
+func redactRawQuery(raw string) string {
+	var b strings.Builder
+	for raw != "" {
+		pair := raw
+		sep := ""
+		if i := strings.IndexAny(raw, "&;"); i >= 0 {
+			pair, sep, raw = raw[:i], raw[i:i+1], raw[i+1:]
+		} else {
+			raw = ""
+		}
+		key, _, hasValue := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if hasValue && logging.IsSensitiveKey(name) {
+			pair = key + "=REDACTED"
+		}
+		b.WriteString(pair)
+		b.WriteString(sep)
+	}
+	return b.String()
+}
@@ -59,3 +59,132 @@ func TestRequestLogger_LogsWarnWithoutQuery(t *testing.T) {
 		t.Fatal("did not expect query field for empty query string")
 	}
 }
+
+func TestRequestLogger_AssignsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New().SetOutput(&buf)
+
+	var handlerID string
+	rl := NewRequestLogger(logger)
+	handler := rl.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerID = logging.RequestIDFromContext(r.Context())
+		logging.FromContext(r.Context()).Info("from handler")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	id := rec.Header().Get(RequestIDHeader)
+	if id == "" || id != handlerID {
+		t.Fatalf("expected response request ID %q to match handler's %q", id, handlerID)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected handler and request log lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		var entry logging.LogEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("failed to parse log entry: %v", err)
+		}
+		if entry.Fields["request_id"] != id {
+			t.Fatalf("expected request_id %q on %q, got %v", id, entry.Message, entry.Fields["request_id"])
+		}
+	}
+}
+
+func TestRequestLogger_KeepsIncomingRequestID(t *testing.T) {
+	rl := NewRequestLogger(logging.New().SetOutput(&bytes.Buffer{}))
+	handler := rl.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		incoming string
+		keep     bool
+	}{
+		{"abc-123.def:ghi_4", true},
+		{"has spaces", false},
+		{string(bytes.Repeat([]byte("a"), 129)), false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, tt.incoming)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		got := rec.Header().Get(RequestIDHeader)
+		if (got == tt.incoming) != tt.keep || got == "" {
+			t.Errorf("incoming %q: got request ID %q, keep=%v", tt.incoming, got, tt.keep)
+		}
+	}
+}
+
+func TestRequestLogger_SamplesHealthChecks(t *testing.T) {
+	var buf bytes.Buffer
+	status := http.StatusOK
+	rl := NewRequestLogger(logging.New().SetOutput(&buf)).SetHealthCheckSampling(3)
+	handler := rl.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	serve := func(path string) {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	countLines := func() int {
+		return bytes.Count(buf.Bytes(), []byte("\n"))
+	}
+
+	for i := 0; i < 6; i++ {
+		serve("/health")
+	}
+	if got := countLines(); got != 2 {
+		t.Fatalf("expected 2 of 6 health checks logged, got %d", got)
+	}
+
+	serve("/api/cards")
+	if got := countLines(); got != 3 {
+		t.Fatalf("expected other paths to always be logged, got %d lines", got)
+	}
+
+	status = http.StatusServiceUnavailable
+	serve("/ready")
+	serve("/ready")
+	if got := countLines(); got != 5 {
+		t.Fatalf("expected failed health checks to always be logged, got %d lines", got)
+	}
+}
+
+func TestRequestLogger_RedactsSensitiveQuery(t *testing.T) {
+	var buf bytes.Buffer
+	rl := NewRequestLogger(logging.New().SetOutput(&buf))
+	handler := rl.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/verify?token=secret-value&page=2", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry logging.LogEntry
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("failed to parse log entry: %v", err)
+	}
+	if entry.Fields["query"] != "page=2&token=REDACTED" {
+		t.Fatalf("expected token redacted from query, got %v", entry.Fields["query"])
+	}
+}
+
+func TestRedactQuery_Malformed(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"token=feed-secret&x=%zz", "token=REDACTED&x=%zz"},
+		{"x=%zz&Share_Token=abc&page=2", "x=%zz&Share_Token=REDACTED&page=2"},
+		{"page=2;token=feed-secret", "page=2;token=REDACTED"},
+		{"to%6Ben=feed-secret&x=%zz", "to%6Ben=REDACTED&x=%zz"},
+	}
+	for _, tt := range tests {
+		if got := redactQuery(tt.raw); got != tt.want {
+			t.Errorf("redactQuery(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -1,95 +1,190 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 )
 
+// RateLimitAlgorithm selects how a policy counts requests.
+type RateLimitAlgorithm string
+
+const (
+	// SlidingWindow allows Limit requests in any Window-long span, estimated
+	// from the current and previous fixed windows.
+	SlidingWindow RateLimitAlgorithm = "sliding_window"
+	// TokenBucket allows bursts of up to Limit requests, refilling at
+	// Limit per Window.
+	TokenBucket RateLimitAlgorithm = "token_bucket"
+)
+
+// RateLimitPolicy is one limit applied by a RateLimiter. Key picks the bucket
+// for a request; an empty key means the policy doesn't apply, which is how
+// stacked policies split traffic between tokens, sessions and anonymous IPs.
+type RateLimitPolicy struct {
+	Name      string
+	Algorithm RateLimitAlgorithm
+	Limit     int64
+	Window    time.Duration
+	Key       func(r *http.Request) string
+}
+
+// RateLimitResult is the outcome of taking one request from a policy.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	Reset      time.Duration // until the quota is fully available again
+	RetryAfter time.Duration // set when the request was rejected
+}
+
+// RateLimitStore keeps rate limit state. Implementations must apply Take
+// atomically so concurrent requests can't overrun a limit.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimitByIP keys requests by client IP.
+func RateLimitByIP(r *http.Request) string {
+	return "ip:" + GetClientIP(r)
+}
+
+// RateLimitByUser keys requests by signed-in user, however they authenticated.
+func RateLimitByUser(r *http.Request) string {
+	if user := handlers.GetUserFromContext(r.Context()); user != nil {
+		return "user:" + user.ID.String()
+	}
+	return ""
+}
+
+// RateLimitBySession keys browser session requests by user. Requests made
+// with an API token are skipped so tokens don't spend the owner's quota.
+func RateLimitBySession(r *http.Request) string {
+	if handlers.GetTokenIDFromContext(r.Context()) != uuid.Nil {
+		return ""
+	}
+	return RateLimitByUser(r)
+}
+
+// RateLimitByToken keys requests by the API token that authenticated them.
+func RateLimitByToken(r *http.Request) string {
+	if id := handlers.GetTokenIDFromContext(r.Context()); id != uuid.Nil {
+		return "token:" + id.String()
+	}
+	return ""
+}
+
+// RateLimitAnonymous keys unauthenticated requests by client IP.
+func RateLimitAnonymous(r *http.Request) string {
+	if handlers.GetUserFromContext(r.Context()) != nil {
+		return ""
+	}
+	return RateLimitByIP(r)
+}
+
 type RateLimiter struct {
-	redis  *redis.Client
-	limit  int64
-	window time.Duration
-	prefix string
-	keyFn  func(r *http.Request) string
-	// failOpen controls behavior when Redis errors: when true, requests are allowed through.
+	store      RateLimitStore
+	prefix     string
+	policies   []RateLimitPolicy
+	pathPrefix string
+	now        func() time.Time
+	// failOpen controls behavior when the store errors: when true, requests are allowed through.
 	// For cost-sensitive endpoints, set to false to fail closed.
 	failOpen bool
 }
 
+// NewRateLimiter builds a single sliding-window limiter backed by Redis. An
+// empty key from keyFn falls back to the client IP.
 func NewRateLimiter(redis *redis.Client, limit int64, window time.Duration, prefix string, keyFn func(r *http.Request) string, failOpen bool) *RateLimiter {
+	var store RateLimitStore
+	if redis != nil {
+		store = NewRedisRateLimitStore(redis)
+	}
+	return NewPolicyRateLimiter(store, prefix, failOpen, RateLimitPolicy{
+		Name:      "default",
+		Algorithm: SlidingWindow,
+		Limit:     limit,
+		Window:    window,
+		Key: func(r *http.Request) string {
+			if key := keyFn(r); key != "" {
+				return key
+			}
+			return GetClientIP(r)
+		},
+	})
+}
+
+// NewPolicyRateLimiter builds a limiter that applies every policy to each
+// request. A request is rejected if any applicable policy is exhausted. A nil
+// store disables limiting.
+func NewPolicyRateLimiter(store RateLimitStore, prefix string, failOpen bool, policies ...RateLimitPolicy) *RateLimiter {
 	return &RateLimiter{
-		redis:    redis,
-		limit:    limit,
-		window:   window,
+		store:    store,
 		prefix:   prefix,
-		keyFn:    keyFn,
+		policies: policies,
+		now:      time.Now,
 		failOpen: failOpen,
 	}
 }
 
+// SetPathPrefix limits the middleware to requests under prefix, so a
+// site-wide limiter can skip static assets.
+func (rl *RateLimiter) SetPathPrefix(prefix string) {
+	rl.pathPrefix = prefix
+}
+
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if rl.redis == nil {
+		if rl.store == nil || !strings.HasPrefix(r.URL.Path, rl.pathPrefix) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		keySuffix := rl.keyFn(r)
-		if keySuffix == "" {
-			// Fallback to IP if key function returns empty string
-			keySuffix = GetClientIP(r)
-		}
+		now := rl.now()
+		var applied []RateLimitPolicy
+		var tightest, denied *RateLimitResult
+		for _, policy := range rl.policies {
+			keySuffix := policy.Key(r)
+			if keySuffix == "" {
+				continue
+			}
 
-		key := fmt.Sprintf("%s%s", rl.prefix, keySuffix)
-		ctx := r.Context()
-
-		// Use a Lua script to atomically INCR and set EXPIRE if new
-		luaScript := `
-			local current
-			current = redis.call("INCR", KEYS[1])
-			if current == 1 then
-				redis.call("EXPIRE", KEYS[1], ARGV[1])
-			end
-			return current
-		`
-		ttlSeconds := int64(rl.window.Seconds())
-		result, err := rl.redis.Eval(ctx, luaScript, []string{key}, ttlSeconds).Result()
-		if err != nil {
-			logging.Error("Rate limit Redis error", map[string]interface{}{"error": err.Error()})
-			if rl.failOpen {
-				next.ServeHTTP(w, r)
+			key := fmt.Sprintf("%s%s:%s", rl.prefix, policy.Name, keySuffix)
+			result, err := rl.store.Take(r.Context(), key, policy, now)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Rate limit store error", map[string]interface{}{"error": err.Error(), "policy": policy.Name})
+				if rl.failOpen {
+					continue
+				}
+				writeError(w, http.StatusServiceUnavailable, "Rate limiting temporarily unavailable")
 				return
 			}
-			writeError(w, http.StatusServiceUnavailable, "Rate limiting temporarily unavailable")
-			return
-		}
 
-		var count int64
-		// Redis may return int64 or float64 depending on the client/driver details for Lua, handle both
-		switch v := result.(type) {
-		case int64:
-			count = v
-		case float64:
-			count = int64(v)
-		default:
-			logging.Error("Rate limit Redis script returned unexpected type", map[string]interface{}{"type": fmt.Sprintf("%T", result)})
-			if rl.failOpen {
-				next.ServeHTTP(w, r)
-				return
+			applied = append(applied, policy)
+			if tightest == nil || result.Remaining < tightest.Remaining {
+				tightest = &result
+			}
+			if !result.Allowed && (denied == nil || result.RetryAfter > denied.RetryAfter) {
+				denied = &result
 			}
-			writeError(w, http.StatusServiceUnavailable, "Rate limiting temporarily unavailable")
-			return
 		}
 
-		if count > rl.limit {
+		if tightest != nil {
+			setRateLimitHeaders(w.Header(), applied, *tightest)
+		}
+		if denied != nil {
+			w.Header().Set("Retry-After", strconv.FormatInt(ceilSeconds(denied.RetryAfter), 10))
 			writeError(w, http.StatusTooManyRequests, "Rate limit exceeded")
 			return
 		}
@@ -98,32 +193,28 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
-}
-
-// GetClientIP extracts the client IP from the request, respecting X-Forwarded-For
-func GetClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header (set by Cloudflare/proxies)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// X-Forwarded-For can contain multiple IPs; the first one is the client
-		if idx := strings.Index(xff, ","); idx != -1 {
-			return strings.TrimSpace(xff[:idx])
-		}
-		return strings.TrimSpace(xff)
+// setRateLimitHeaders reports the most constrained policy using the IETF
+// RateLimit header fields, and lists every policy that applied.
+func setRateLimitHeaders(h http.Header, applied []RateLimitPolicy, result RateLimitResult) {
+	policies := make([]string, len(applied))
+	for i, p := range applied {
+		policies[i] = fmt.Sprintf("%d;w=%d", p.Limit, ceilSeconds(p.Window))
 	}
+	h.Set("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+	h.Set("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+	h.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.Reset), 10))
+	h.Set("RateLimit-Policy", strings.Join(policies, ", "))
+}
 
-	// Check X-Real-IP header
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return strings.TrimSpace(xri)
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
 	}
+	return int64(math.Ceil(d.Seconds()))
+}
 
-	// Fall back to RemoteAddr
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
-	}
-	return ip
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindow decides a request given the previous and current window
+// counts before it is counted. The previous window is weighted by how much of
+// it still overlaps the sliding span.
+func slidingWindow(prev, cur int64, elapsed time.Duration, policy RateLimitPolicy) RateLimitResult {
+	window := float64(policy.Window)
+	estimate := int64(math.Floor(float64(prev)*(window-float64(elapsed))/window)) + cur
+	result := RateLimitResult{Limit: policy.Limit, Reset: policy.Window - elapsed}
+
+	if estimate < policy.Limit {
+		result.Allowed = true
+		result.Remaining = policy.Limit - estimate - 1
+		return result
+	}
+
+	// Wait until enough of the previous window has slid out, or for the next
+	// window when the current one alone is full.
+	result.RetryAfter = policy.Window - elapsed
+	if cur < policy.Limit && prev > 0 {
+		need := window*(1-float64(policy.Limit-cur)/float64(prev)) - float64(elapsed)
+		result.RetryAfter = time.Duration(math.Ceil(need)) + time.Millisecond
+	}
+	if result.RetryAfter < time.Millisecond {
+		result.RetryAfter = time.Millisecond
+	}
+	return result
+}
+
+// refillTokens adds the tokens earned over elapsed, capped at the bucket size.
+func refillTokens(tokens float64, elapsed time.Duration, policy RateLimitPolicy) float64 {
+	if elapsed > 0 {
+		tokens += float64(elapsed) * float64(policy.Limit) / float64(policy.Window)
+	}
+	if tokens > float64(policy.Limit) {
+		return float64(policy.Limit)
+	}
+	return tokens
+}
+
+// tokenBucket decides a request given the refilled token count before it is
+// taken.
+func tokenBucket(tokens float64, policy RateLimitPolicy) RateLimitResult {
+	perToken := float64(policy.Window) / float64(policy.Limit)
+	result := RateLimitResult{Limit: policy.Limit}
+
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - tokens) * perToken))
+	}
+	result.Remaining = int64(math.Floor(tokens))
+	result.Reset = time.Duration(math.Ceil((float64(policy.Limit) - tokens) * perToken))
+	return result
+}
+
+// MemoryRateLimitStore keeps rate limit state in process memory. It suits
+// single-node deployments; with several instances each enforces its own limit.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryRateEntry
+	takes   int
+}
+
+type memoryRateEntry struct {
+	window  int64 // sliding window index
+	prev    int64
+	cur     int64
+	tokens  float64
+	last    time.Time
+	expires time.Time
+}
+
+// memorySweepEvery controls how often expired entries are dropped.
+const memorySweepEvery = 1000
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{entries: make(map[string]*memoryRateEntry)}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%memorySweepEvery == 0 {
+		for k, e := range s.entries {
+			if now.After(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+
+	entry, ok := s.entries[key]
+	if ok && now.After(entry.expires) {
+		ok = false
+	}
+
+	switch policy.Algorithm {
+	case TokenBucket:
+		if !ok {
+			entry = &memoryRateEntry{tokens: float64(policy.Limit), last: now}
+			s.entries[key] = entry
+		}
+		tokens := refillTokens(entry.tokens, now.Sub(entry.last), policy)
+		result := tokenBucket(tokens, policy)
+		if result.Allowed {
+			tokens--
+		}
+		entry.tokens = tokens
+		entry.last = now
+		entry.expires = now.Add(policy.Window)
+		return result, nil
+
+	case SlidingWindow:
+		index := now.UnixNano() / int64(policy.Window)
+		if !ok {
+			entry = &memoryRateEntry{window: index}
+			s.entries[key] = entry
+		}
+		switch {
+		case index == entry.window+1:
+			entry.prev, entry.cur = entry.cur, 0
+		case index > entry.window+1:
+			entry.prev, entry.cur = 0, 0
+		}
+		entry.window = index
+
+		elapsed := time.Duration(now.UnixNano() - index*int64(policy.Window))
+		result := slidingWindow(entry.prev, entry.cur, elapsed, policy)
+		if result.Allowed {
+			entry.cur++
+		}
+		entry.expires = now.Add(2 * policy.Window)
+		return result, nil
+	}
+	return RateLimitResult{}, fmt.Errorf("unknown rate limit algorithm %q", policy.Algorithm)
+}
+
+// RedisRateLimitStore keeps rate limit state in Redis so limits hold across
+// instances. The scripts mirror slidingWindow and tokenBucket and return the
+// state before the request; the Go functions then build the result.
+type RedisRateLimitStore struct {
+	client *redis.Client
+}
+
+func NewRedisRateLimitStore(client *redis.Client) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client}
+}
+
+var slidingWindowScript = redis.NewScript(`
+	local prev = tonumber(redis.call("GET", KEYS[2]) or "0")
+	local cur = tonumber(redis.call("GET", KEYS[1]) or "0")
+	local limit = tonumber(ARGV[1])
+	local window = tonumber(ARGV[2])
+	local elapsed = tonumber(ARGV[3])
+	if math.floor(prev * (window - elapsed) / window) + cur < limit then
+		redis.call("INCR", KEYS[1])
+		redis.call("PEXPIRE", KEYS[1], window * 2)
+	end
+	return {prev, cur}
+`)
+
+var tokenBucketScript = redis.NewScript(`
+	local limit = tonumber(ARGV[1])
+	local window = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+	local state = redis.call("HMGET", KEYS[1], "tokens", "last")
+	local tokens = tonumber(state[1]) or limit
+	local last = tonumber(state[2]) or now
+	if now > last then
+		tokens = math.min(limit, tokens + (now - last) * limit / window)
+	end
+	local before = tokens
+	if tokens >= 1 then
+		tokens = tokens - 1
+	end
+	redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "last", tostring(now))
+	redis.call("PEXPIRE", KEYS[1], window)
+	return tostring(before)
+`)
+
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error) {
+	windowMs := policy.Window.Milliseconds()
+	if windowMs <= 0 {
+		return RateLimitResult{}, fmt.Errorf("rate limit window must be at least 1ms")
+	}
+
+	switch policy.Algorithm {
+	case TokenBucket:
+		raw, err := tokenBucketScript.Run(ctx, s.client, []string{key}, policy.Limit, windowMs, now.UnixMilli()).Text()
+		if err != nil {
+			return RateLimitResult{}, err
+		}
+		tokens, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return RateLimitResult{}, fmt.Errorf("parse token bucket state: %w", err)
+		}
+		return tokenBucket(tokens, policy), nil
+
+	case SlidingWindow:
+		nowMs := now.UnixMilli()
+		index := nowMs / windowMs
+		keys := []string{fmt.Sprintf("%s:%d", key, index), fmt.Sprintf("%s:%d", key, index-1)}
+		counts, err := slidingWindowScript.Run(ctx, s.client, keys, policy.Limit, windowMs, nowMs-index*windowMs).Int64Slice()
+		if err != nil {
+			return RateLimitResult{}, err
+		}
+		if len(counts) != 2 {
+			return RateLimitResult{}, fmt.Errorf("unexpected sliding window reply length %d", len(counts))
+		}
+		elapsed := time.Duration(nowMs-index*windowMs) * time.Millisecond
+		return slidingWindow(counts[0], counts[1], elapsed, policy), nil
+	}
+	return RateLimitResult{}, fmt.Errorf("unknown rate limit algorithm %q", policy.Algorithm)
+}
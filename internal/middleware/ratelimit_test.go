@@ -1,10 +1,18 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
 func TestRateLimiter_Middleware_NilRedis(t *testing.T) {
@@ -27,61 +35,6 @@ func TestRateLimiter_Middleware_NilRedis(t *testing.T) {
 	}
 }
 
-func TestGetClientIP(t *testing.T) {
-	tests := []struct {
-		name     string
-		headers  map[string]string
-		remote   string
-		expected string
-	}{
-		{
-			name:     "X-Forwarded-For Single",
-			headers:  map[string]string{"X-Forwarded-For": "10.0.0.1"},
-			remote:   "192.168.1.1:1234",
-			expected: "10.0.0.1",
-		},
-		{
-			name:     "X-Forwarded-For Multiple",
-			headers:  map[string]string{"X-Forwarded-For": "10.0.0.1, 10.0.0.2"},
-			remote:   "192.168.1.1:1234",
-			expected: "10.0.0.1",
-		},
-		{
-			name:     "X-Real-IP",
-			headers:  map[string]string{"X-Real-IP": "10.0.0.2"},
-			remote:   "192.168.1.1:1234",
-			expected: "10.0.0.2",
-		},
-		{
-			name:     "XFF Preference over X-Real-IP",
-			headers:  map[string]string{"X-Forwarded-For": "10.0.0.1", "X-Real-IP": "10.0.0.2"},
-			remote:   "192.168.1.1:1234",
-			expected: "10.0.0.1",
-		},
-		{
-			name:     "No Headers",
-			headers:  map[string]string{},
-			remote:   "192.168.1.1:1234",
-			expected: "192.168.1.1",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest("GET", "/", nil)
-			for k, v := range tt.headers {
-				req.Header.Set(k, v)
-			}
-			req.RemoteAddr = tt.remote
-
-			ip := GetClientIP(req)
-			if ip != tt.expected {
-				t.Errorf("expected %s, got %s", tt.expected, ip)
-			}
-		})
-	}
-}
-
 func TestWriteError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	writeError(rr, http.StatusTooManyRequests, "Rate limit exceeded")
@@ -97,6 +50,133 @@ func TestWriteError(t *testing.T) {
 	}
 }
 
-// Note: Full integration testing of RateLimiter requires a running Redis instance
-// or a mock that implements the go-redis interface, which is not trivial without
-// external libraries like redismock.
+// Note: The Redis store runs the same decisions through Lua scripts and needs a
+// running Redis instance to test; the memory store below covers the algorithms.
+
+type errRateLimitStore struct{}
+
+func (errRateLimitStore) Take(ctx context.Context, key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("store down")
+}
+
+func newTestLimiter(store RateLimitStore, failOpen bool, now *time.Time, policies ...RateLimitPolicy) http.Handler {
+	limiter := NewPolicyRateLimiter(store, "test:", failOpen, policies...)
+	limiter.now = func() time.Time { return *now }
+	return limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func serveLimited(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimiter_SlidingWindow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler := newTestLimiter(NewMemoryRateLimitStore(), true, &now, RateLimitPolicy{
+		Name: "ip", Algorithm: SlidingWindow, Limit: 4, Window: time.Minute, Key: RateLimitByIP,
+	})
+
+	for i := 0; i < 4; i++ {
+		rr := serveLimited(handler, httptest.NewRequest("GET", "/api/cards", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, rr.Code)
+		}
+		if got := rr.Header().Get("RateLimit-Remaining"); got != strconv.Itoa(3-i) {
+			t.Fatalf("request %d: expected remaining %d, got %s", i+1, 3-i, got)
+		}
+	}
+
+	rr := serveLimited(handler, httptest.NewRequest("GET", "/api/cards", nil))
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rr.Code)
+	}
+	if rr.Header().Get("Retry-After") != "60" || rr.Header().Get("RateLimit-Policy") != "4;w=60" {
+		t.Fatalf("unexpected headers: %v", rr.Header())
+	}
+
+	// Halfway into the next window, half of the previous window still counts.
+	now = now.Add(90 * time.Second)
+	for i := 0; i < 2; i++ {
+		if rr := serveLimited(handler, httptest.NewRequest("GET", "/api/cards", nil)); rr.Code != http.StatusOK {
+			t.Fatalf("request %d after slide: expected 200, got %d", i+1, rr.Code)
+		}
+	}
+	if rr := serveLimited(handler, httptest.NewRequest("GET", "/api/cards", nil)); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected weighted window to reject, got %d", rr.Code)
+	}
+}
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler := newTestLimiter(NewMemoryRateLimitStore(), true, &now, RateLimitPolicy{
+		Name: "ip", Algorithm: TokenBucket, Limit: 2, Window: time.Minute, Key: RateLimitByIP,
+	})
+
+	for i := 0; i < 2; i++ {
+		if rr := serveLimited(handler, httptest.NewRequest("GET", "/api/cards", nil)); rr.Code != http.StatusOK {
+			t.Fatalf("burst request %d: expected 200, got %d", i+1, rr.Code)
+		}
+	}
+	rr := serveLimited(handler, httptest.NewRequest("GET", "/api/cards", nil))
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") != "30" {
+		t.Fatalf("expected 429 with Retry-After 30, got %d %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+
+	now = now.Add(30 * time.Second)
+	if rr := serveLimited(handler, httptest.NewRequest("GET", "/api/cards", nil)); rr.Code != http.StatusOK {
+		t.Fatalf("expected refilled token, got %d", rr.Code)
+	}
+}
+
+func TestRateLimiter_TokensAndSessionsHaveSeparateQuotas(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler := newTestLimiter(NewMemoryRateLimitStore(), true, &now,
+		RateLimitPolicy{Name: "session", Algorithm: SlidingWindow, Limit: 1, Window: time.Minute, Key: RateLimitBySession},
+		RateLimitPolicy{Name: "token", Algorithm: TokenBucket, Limit: 1, Window: time.Minute, Key: RateLimitByToken},
+	)
+	user := &models.User{ID: uuid.New()}
+
+	sessionReq := func() *http.Request {
+		req := httptest.NewRequest("GET", "/api/cards", nil)
+		return req.WithContext(handlers.SetUserInContext(req.Context(), user))
+	}
+	tokenReq := func() *http.Request {
+		ctx := handlers.SetTokenIDInContext(sessionReq().Context(), uuid.New())
+		return httptest.NewRequest("GET", "/api/cards", nil).WithContext(ctx)
+	}
+
+	if rr := serveLimited(handler, sessionReq()); rr.Code != http.StatusOK {
+		t.Fatalf("expected session request allowed, got %d", rr.Code)
+	}
+	if rr := serveLimited(handler, tokenReq()); rr.Code != http.StatusOK {
+		t.Fatalf("expected token request to use its own quota, got %d", rr.Code)
+	}
+	if rr := serveLimited(handler, sessionReq()); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected session quota exhausted, got %d", rr.Code)
+	}
+}
+
+func TestRateLimiter_PathPrefixAndStoreErrors(t *testing.T) {
+	now := time.Now()
+	policy := RateLimitPolicy{Name: "ip", Algorithm: SlidingWindow, Limit: 1, Window: time.Minute, Key: RateLimitByIP}
+
+	limiter := NewPolicyRateLimiter(NewMemoryRateLimitStore(), "test:", true, policy)
+	limiter.SetPathPrefix("/api/")
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for i := 0; i < 3; i++ {
+		rr := serveLimited(handler, httptest.NewRequest("GET", "/static/app.js", nil))
+		if rr.Code != http.StatusOK || rr.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("expected static request to bypass limiter, got %d %v", rr.Code, rr.Header())
+		}
+	}
+
+	if rr := serveLimited(newTestLimiter(errRateLimitStore{}, true, &now, policy), httptest.NewRequest("GET", "/", nil)); rr.Code != http.StatusOK {
+		t.Fatalf("expected fail open, got %d", rr.Code)
+	}
+	if rr := serveLimited(newTestLimiter(errRateLimitStore{}, false, &now, policy), httptest.NewRequest("GET", "/", nil)); rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected fail closed, got %d", rr.Code)
+	}
+}
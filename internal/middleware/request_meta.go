@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// RequestMeta stores the client IP and user agent in the request context so
+// services can attach them to account events, and so rate limits and
+// throttles key on the same resolved IP. It also starts read-your-writes
+// tracking, so a request that writes reads from the primary afterwards.
+type RequestMeta struct {
+	clientIP *ClientIPResolver
+}
+
+// NewRequestMeta creates a new request metadata middleware. A nil resolver
+// trusts no proxies.
+func NewRequestMeta(clientIP *ClientIPResolver) *RequestMeta {
+	return &RequestMeta{clientIP: clientIP}
+}
+
+// Apply wraps the handler to record request metadata.
+func (m *RequestMeta) Apply(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := services.WithRequestMeta(r.Context(), services.RequestMeta{
+			IPAddress: m.clientIP.Resolve(r),
+			UserAgent: r.UserAgent(),
+		})
+		ctx = services.WithReadAffinity(ctx)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
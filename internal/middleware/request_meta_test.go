@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestRequestMeta_Apply(t *testing.T) {
+	var got services.RequestMeta
+	resolver := NewClientIPResolver([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, false)
+	handler := NewRequestMeta(resolver).Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = services.RequestMetaFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:5555"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	req.Header.Set("User-Agent", "TestAgent/1.0")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got.IPAddress != "203.0.113.7" || got.UserAgent != "TestAgent/1.0" {
+		t.Fatalf("unexpected request meta: %+v", got)
+	}
+}
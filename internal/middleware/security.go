@@ -1,17 +1,127 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
 )
 
+// CSPReportPath is where browsers send Content-Security-Policy violation
+// reports under the default policy.
+const CSPReportPath = "/csp-report"
+
+// cspNoncePlaceholder marks where each request's nonce goes in the policy.
+const cspNoncePlaceholder = "{nonce}"
+
+type cspDirective struct {
+	name  string
+	value string
+}
+
+// defaultCSP is the policy the app's own pages need: its scripts, the
+// cdnjs libraries and Cloudflare Insights beacon, and inline styles, which
+// the front end sets on many elements.
+var defaultCSP = []cspDirective{
+	{"default-src", "'self'"},
+	{"script-src", "'self' https://static.cloudflareinsights.com https://cdnjs.cloudflare.com"},
+	{"style-src", "'self' 'unsafe-inline' https://fonts.googleapis.com https://fonts.cdnfonts.com https://cdnjs.cloudflare.com"},
+	{"font-src", "'self' https://fonts.gstatic.com https://fonts.cdnfonts.com https://cdnjs.cloudflare.com data:"},
+	{"img-src", "'self' data:"},
+	{"connect-src", "'self'"},
+	{"frame-ancestors", "'none'"},
+	{"base-uri", "'self'"},
+	{"form-action", "'self'"},
+	{"report-uri", CSPReportPath},
+	{"report-to", "csp"},
+}
+
+var cspDirectiveName = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+
 // SecurityHeaders adds security-related HTTP headers to responses.
 type SecurityHeaders struct {
-	secure bool
+	secure     bool
+	policy     string
+	reportOnly bool
+	// reportingEndpoints names the report-to group's URL for the Reporting
+	// API; empty when the policy has no report-to.
+	reportingEndpoints string
 }
 
 // NewSecurityHeaders creates a new security headers middleware.
 func NewSecurityHeaders(secure bool) *SecurityHeaders {
-	return &SecurityHeaders{secure: secure}
+	s := &SecurityHeaders{secure: secure}
+	s.setPolicy(defaultCSP)
+	return s
+}
+
+// SetCSP changes the Content-Security-Policy. Each override replaces the
+// default value of the directive it names, adds the directive if the default
+// policy lacks it, or removes it when the value is empty. With reportOnly
+// the policy is sent as Content-Security-Policy-Report-Only, so violations
+// are reported but nothing is blocked.
+func (s *SecurityHeaders) SetCSP(overrides map[string]string, reportOnly bool) error {
+	directives := slices.Clone(defaultCSP)
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		value := strings.TrimSpace(overrides[name])
+		if !cspDirectiveName.MatchString(name) {
+			return fmt.Errorf("invalid CSP directive name %q", name)
+		}
+		if strings.ContainsAny(value, ";,") {
+			return fmt.Errorf("CSP directive %s: value must not contain ';' or ','", name)
+		}
+
+		i := slices.IndexFunc(directives, func(d cspDirective) bool { return d.name == name })
+		switch {
+		case value == "" && i >= 0:
+			directives = slices.Delete(directives, i, i+1)
+		case value == "":
+		case i >= 0:
+			directives[i].value = value
+		default:
+			directives = append(directives, cspDirective{name: name, value: value})
+		}
+	}
+
+	s.setPolicy(directives)
+	s.reportOnly = reportOnly
+	return nil
+}
+
+func (s *SecurityHeaders) setPolicy(directives []cspDirective) {
+	parts := make([]string, 0, len(directives))
+	var reportURI, reportTo string
+	for _, d := range directives {
+		value := d.value
+		switch d.name {
+		case "script-src":
+			// Pages mark their own script tags with the request's nonce.
+			value += " 'nonce-" + cspNoncePlaceholder + "'"
+		case "report-uri":
+			reportURI = strings.Fields(value)[0]
+		case "report-to":
+			reportTo = value
+		}
+		parts = append(parts, d.name+" "+value)
+	}
+
+	s.policy = strings.Join(parts, "; ")
+	s.reportingEndpoints = ""
+	if reportURI != "" && reportTo != "" {
+		s.reportingEndpoints = fmt.Sprintf("%s=%q", reportTo, reportURI)
+	}
 }
 
 // Apply adds security headers to all responses.
@@ -32,17 +142,21 @@ func (s *SecurityHeaders) Apply(next http.Handler) http.Handler {
 		// Permissions policy - disable unnecessary browser features
 		w.Header().Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
 
-		// Content Security Policy
-		csp := "default-src 'self'; " +
-			"script-src 'self' https://static.cloudflareinsights.com https://cdnjs.cloudflare.com; " +
-			"style-src 'self' 'unsafe-inline' https://fonts.googleapis.com https://fonts.cdnfonts.com https://cdnjs.cloudflare.com; " +
-			"font-src 'self' https://fonts.gstatic.com https://fonts.cdnfonts.com https://cdnjs.cloudflare.com data:; " +
-			"img-src 'self' data:; " +
-			"connect-src 'self'; " +
-			"frame-ancestors 'none'; " +
-			"base-uri 'self'; " +
-			"form-action 'self'"
-		w.Header().Set("Content-Security-Policy", csp)
+		// Content Security Policy, with a fresh nonce for the page's scripts
+		nonce, err := generateCSPNonce()
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		header := "Content-Security-Policy"
+		if s.reportOnly {
+			header = "Content-Security-Policy-Report-Only"
+		}
+		w.Header().Set(header, strings.ReplaceAll(s.policy, cspNoncePlaceholder, nonce))
+		if s.reportingEndpoints != "" {
+			w.Header().Set("Reporting-Endpoints", s.reportingEndpoints)
+		}
+		r = r.WithContext(handlers.SetCSPNonceInContext(r.Context(), nonce))
 
 		// HSTS - only in secure mode (production)
 		if s.secure {
@@ -53,3 +167,11 @@ func (s *SecurityHeaders) Apply(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func generateCSPNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
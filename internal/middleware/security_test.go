@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
 )
 
 func TestSecurityHeaders_Apply(t *testing.T) {
@@ -163,3 +165,83 @@ func TestSecurityHeaders_AllMethods(t *testing.T) {
 		})
 	}
 }
+
+func serveSecurityHeaders(t *testing.T, sec *SecurityHeaders) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	var nonce string
+	handler := sec.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce = handlers.GetCSPNonceFromContext(r.Context())
+	}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rr, nonce
+}
+
+func TestSecurityHeaders_CSPNonce(t *testing.T) {
+	sec := NewSecurityHeaders(false)
+
+	rr, nonce := serveSecurityHeaders(t, sec)
+	if nonce == "" {
+		t.Fatal("expected a nonce in the request context")
+	}
+	csp := rr.Header().Get("Content-Security-Policy")
+	if !strings.Contains(csp, "script-src 'self' https://static.cloudflareinsights.com https://cdnjs.cloudflare.com 'nonce-"+nonce+"'") {
+		t.Errorf("expected script-src to allow the nonce, got %q", csp)
+	}
+	if strings.Contains(csp, "style-src 'self' 'unsafe-inline' 'nonce-") || strings.Contains(csp, cspNoncePlaceholder) {
+		t.Errorf("expected the nonce only in script-src, got %q", csp)
+	}
+	if !strings.Contains(csp, "report-uri "+CSPReportPath) || !strings.Contains(csp, "report-to csp") {
+		t.Errorf("expected reporting directives, got %q", csp)
+	}
+	if got := rr.Header().Get("Reporting-Endpoints"); got != `csp="/csp-report"` {
+		t.Errorf("unexpected Reporting-Endpoints %q", got)
+	}
+
+	_, other := serveSecurityHeaders(t, sec)
+	if other == nonce {
+		t.Error("expected a fresh nonce per request")
+	}
+}
+
+func TestSecurityHeaders_SetCSP(t *testing.T) {
+	sec := NewSecurityHeaders(false)
+	err := sec.SetCSP(map[string]string{
+		"script-src":                "'self' https://plausible.io",
+		"upgrade-insecure-requests": "",
+		"worker-src":                "'self' blob:",
+		"report-to":                 "",
+		"report-uri":                "https://reports.example/csp",
+	}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rr, nonce := serveSecurityHeaders(t, sec)
+	if rr.Header().Get("Content-Security-Policy") != "" {
+		t.Error("expected no enforced policy in report-only mode")
+	}
+	csp := rr.Header().Get("Content-Security-Policy-Report-Only")
+	for _, want := range []string{
+		"script-src 'self' https://plausible.io 'nonce-" + nonce + "'",
+		"worker-src 'self' blob:",
+		"report-uri https://reports.example/csp",
+	} {
+		if !strings.Contains(csp, want) {
+			t.Errorf("expected %q in %q", want, csp)
+		}
+	}
+	if strings.Contains(csp, "cloudflareinsights") || strings.Contains(csp, "report-to") || strings.Contains(csp, "upgrade-insecure-requests") {
+		t.Errorf("expected overridden and removed directives to be gone, got %q", csp)
+	}
+	if got := rr.Header().Get("Reporting-Endpoints"); got != "" {
+		t.Errorf("expected no Reporting-Endpoints without report-to, got %q", got)
+	}
+
+	if err := sec.SetCSP(map[string]string{"Script_Src": "'self'"}, false); err == nil {
+		t.Error("expected invalid directive name to be rejected")
+	}
+	if err := sec.SetCSP(map[string]string{"script-src": "'self'; object-src *"}, false); err == nil {
+		t.Error("expected a value with ';' to be rejected")
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountEventType identifies a security-relevant event on a user's account.
+type AccountEventType string
+
+const (
+	AccountEventRegister       AccountEventType = "register"
+	AccountEventLogin          AccountEventType = "login"
+	AccountEventLogout         AccountEventType = "logout"
+	AccountEventMagicLinkLogin AccountEventType = "magic_link_login"
+	AccountEventPasswordChange AccountEventType = "password_change"
+	AccountEventPasswordReset  AccountEventType = "password_reset"
+	AccountEventTokenCreated   AccountEventType = "api_token_created"
+	AccountEventTokenDeleted   AccountEventType = "api_token_deleted"
+	AccountEventTokensRevoked  AccountEventType = "api_tokens_revoked"
+	AccountEventUserBlocked    AccountEventType = "user_blocked"
+	AccountEventUserUnblocked  AccountEventType = "user_unblocked"
+	AccountEventPrivacyChanged AccountEventType = "privacy_changed"
+
+	AccountEventCalendarFeedCreated AccountEventType = "calendar_feed_created"
+	AccountEventCalendarFeedRevoked AccountEventType = "calendar_feed_revoked"
+)
+
+// AccountEventOutcome records whether the attempted action succeeded.
+type AccountEventOutcome string
+
+const (
+	AccountEventSuccess AccountEventOutcome = "success"
+	AccountEventFailure AccountEventOutcome = "failure"
+)
+
+// AccountEvent is a row from the append-only account_events table.
+type AccountEvent struct {
+	ID        uuid.UUID           `json:"id"`
+	UserID    uuid.UUID           `json:"user_id"`
+	EventType AccountEventType    `json:"event_type"`
+	Outcome   AccountEventOutcome `json:"outcome"`
+	IPAddress *string             `json:"ip_address,omitempty"`
+	UserAgent *string             `json:"user_agent,omitempty"`
+	Details   map[string]any      `json:"details"`
+	CreatedAt time.Time           `json:"created_at"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActivityEventType identifies an entry in the friend activity feed.
+type ActivityEventType string
+
+const (
+	ActivityItemCompleted ActivityEventType = "item_completed"
+	ActivityBingo         ActivityEventType = "bingo"
+	ActivityCardFinalized ActivityEventType = "card_finalized"
+	ActivityReaction      ActivityEventType = "reaction"
+)
+
+// ActivityEvent is what services record when something feed-worthy happens.
+type ActivityEvent struct {
+	ActorID    uuid.UUID
+	Type       ActivityEventType
+	CardID     uuid.UUID
+	ItemID     *uuid.UUID
+	BingoCount *int
+	Emoji      *string
+}
+
+// FeedItem is an activity event as shown to a friend, joined with the card
+// and item it refers to.
+type FeedItem struct {
+	ID                uuid.UUID         `json:"id"`
+	Type              ActivityEventType `json:"type"`
+	ActorID           uuid.UUID         `json:"actor_id"`
+	ActorUsername     string            `json:"actor_username"`
+	CardID            uuid.UUID         `json:"card_id"`
+	CardOwnerID       uuid.UUID         `json:"card_owner_id"`
+	CardOwnerUsername string            `json:"card_owner_username"`
+	CardTitle         *string           `json:"card_title,omitempty"`
+	CardYear          int               `json:"card_year"`
+	ItemID            *uuid.UUID        `json:"item_id,omitempty"`
+	ItemContent       *string           `json:"item_content,omitempty"`
+	ItemPosition      *int              `json:"item_position,omitempty"`
+	Notes             *string           `json:"notes,omitempty"`
+	BingoCount        *int              `json:"bingo_count,omitempty"`
+	Emoji             *string           `json:"emoji,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminAction identifies an entry in the admin audit log.
+type AdminAction string
+
+const (
+	AdminActionSearchUsers        AdminAction = "search_users"
+	AdminActionViewUser           AdminAction = "view_user"
+	AdminActionViewUserCards      AdminAction = "view_user_cards"
+	AdminActionDisableUser        AdminAction = "disable_user"
+	AdminActionEnableUser         AdminAction = "enable_user"
+	AdminActionForceLogout        AdminAction = "force_logout"
+	AdminActionResetAIGenerations AdminAction = "reset_ai_generations"
+	AdminActionViewAILogs         AdminAction = "view_ai_logs"
+	AdminActionViewReports        AdminAction = "view_reports"
+	AdminActionDismissReport      AdminAction = "dismiss_report"
+	AdminActionHideItem           AdminAction = "hide_item"
+	AdminActionUnhideItem         AdminAction = "unhide_item"
+	AdminActionSuspendUser        AdminAction = "suspend_user"
+	AdminActionHideTemplate       AdminAction = "hide_template"
+	AdminActionUnhideTemplate     AdminAction = "unhide_template"
+)
+
+// AdminUserSummary is the user view returned to admins.
+type AdminUserSummary struct {
+	ID                    uuid.UUID  `json:"id"`
+	Email                 string     `json:"email"`
+	Username              string     `json:"username"`
+	EmailVerified         bool       `json:"email_verified"`
+	IsAdmin               bool       `json:"is_admin"`
+	DisabledAt            *time.Time `json:"disabled_at,omitempty"`
+	AIFreeGenerationsUsed int        `json:"ai_free_generations_used"`
+	CardCount             int        `json:"card_count"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+// AdminAuditEntry records a single admin action.
+type AdminAuditEntry struct {
+	ID             uuid.UUID      `json:"id"`
+	AdminUserID    *uuid.UUID     `json:"admin_user_id,omitempty"`
+	AdminUsername  *string        `json:"admin_username,omitempty"`
+	Action         AdminAction    `json:"action"`
+	TargetUserID   *uuid.UUID     `json:"target_user_id,omitempty"`
+	TargetUsername *string        `json:"target_username,omitempty"`
+	Details        map[string]any `json:"details"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// AIGenerationLog is a row from ai_generation_logs.
+type AIGenerationLog struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Username     string    `json:"username"`
+	Model        string    `json:"model"`
+	TokensInput  int       `json:"tokens_input"`
+	TokensOutput int       `json:"tokens_output"`
+	DurationMs   int       `json:"duration_ms"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+}
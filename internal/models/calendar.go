@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// CalendarFeed is a user's secret iCalendar feed. URL holds the token and is
+// only set when the feed is created.
+type CalendarFeed struct {
+	URL        string     `json:"url,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
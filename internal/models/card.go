@@ -48,23 +48,88 @@ func ValidateHeaderText(headerText string, gridSize int) error {
 	return nil
 }
 
+// CardVisibility controls who can see a finalized card.
+type CardVisibility string
+
+const (
+	VisibilityPrivate CardVisibility = "private"
+	VisibilityFriends CardVisibility = "friends"
+	VisibilityGroups  CardVisibility = "groups"
+	// VisibilityOrganization shows the card to all friends and to everyone
+	// who shares an organization with the owner.
+	VisibilityOrganization CardVisibility = "organization"
+	VisibilityLink         CardVisibility = "link"
+	// VisibilityPublic shows the card on the owner's public profile, if they
+	// have one, as well as to all friends.
+	VisibilityPublic CardVisibility = "public"
+)
+
+func (v CardVisibility) IsValid() bool {
+	switch v {
+	case VisibilityPrivate, VisibilityFriends, VisibilityGroups, VisibilityOrganization, VisibilityLink, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// SharedWithFriends reports whether at least some friends can see the card.
+func (v CardVisibility) SharedWithFriends() bool {
+	return v != VisibilityPrivate && v != ""
+}
+
+// VisibleToAllFriends reports whether every friend can see the card. This is
+// what the visible_to_friends column holds.
+func (v CardVisibility) VisibleToAllFriends() bool {
+	return v == VisibilityFriends || v == VisibilityOrganization || v == VisibilityLink || v == VisibilityPublic
+}
+
+// VisibleToOrganizations reports whether members of the owner's
+// organizations can see the card.
+func (v CardVisibility) VisibleToOrganizations() bool {
+	return v == VisibilityOrganization || v == VisibilityPublic
+}
+
+// VisibilityFromFlag maps the older visible_to_friends flag to a visibility.
+func VisibilityFromFlag(visibleToFriends bool) CardVisibility {
+	if visibleToFriends {
+		return VisibilityFriends
+	}
+	return VisibilityPrivate
+}
+
+// CardVisibilityParams sets who can see a card. GroupIDs is required for
+// VisibilityGroups and ignored otherwise.
+type CardVisibilityParams struct {
+	Visibility CardVisibility
+	GroupIDs   []uuid.UUID
+}
+
+// CardSharing describes a card's audience for its owner.
+type CardSharing struct {
+	Visibility CardVisibility `json:"visibility"`
+	GroupIDs   []uuid.UUID    `json:"group_ids"`
+	ShareToken *string        `json:"share_token,omitempty"`
+}
+
 type BingoCard struct {
-	ID               uuid.UUID   `json:"id"`
-	UserID           uuid.UUID   `json:"user_id"`
-	Year             int         `json:"year"`
-	Category         *string     `json:"category,omitempty"`
-	Title            *string     `json:"title,omitempty"`
-	GridSize         int         `json:"grid_size"`
-	HeaderText       string      `json:"header_text"`
-	HasFreeSpace     bool        `json:"has_free_space"`
-	FreeSpacePos     *int        `json:"free_space_position,omitempty"`
-	IsActive         bool        `json:"is_active"`
-	IsFinalized      bool        `json:"is_finalized"`
-	VisibleToFriends bool        `json:"visible_to_friends"`
-	IsArchived       bool        `json:"is_archived"`
-	CreatedAt        time.Time   `json:"created_at"`
-	UpdatedAt        time.Time   `json:"updated_at"`
-	Items            []BingoItem `json:"items,omitempty"`
+	ID               uuid.UUID      `json:"id"`
+	UserID           uuid.UUID      `json:"user_id"`
+	Year             int            `json:"year"`
+	Category         *string        `json:"category,omitempty"`
+	Title            *string        `json:"title,omitempty"`
+	GridSize         int            `json:"grid_size"`
+	HeaderText       string         `json:"header_text"`
+	HasFreeSpace     bool           `json:"has_free_space"`
+	FreeSpacePos     *int           `json:"free_space_position,omitempty"`
+	IsActive         bool           `json:"is_active"`
+	IsFinalized      bool           `json:"is_finalized"`
+	VisibleToFriends bool           `json:"visible_to_friends"`
+	Visibility       CardVisibility `json:"visibility"`
+	IsArchived       bool           `json:"is_archived"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Version          int64          `json:"version"`
+	Items            []BingoItem    `json:"items,omitempty"`
 }
 
 func (c *BingoCard) TotalSquares() int {
@@ -152,15 +217,29 @@ func IsValidCategory(category string) bool {
 }
 
 type BingoItem struct {
-	ID          uuid.UUID  `json:"id"`
-	CardID      uuid.UUID  `json:"card_id"`
-	Position    int        `json:"position"`
-	Content     string     `json:"content"`
-	IsCompleted bool       `json:"is_completed"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	Notes       *string    `json:"notes,omitempty"`
-	ProofURL    *string    `json:"proof_url,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
+	ID                 uuid.UUID  `json:"id"`
+	CardID             uuid.UUID  `json:"card_id"`
+	Position           int        `json:"position"`
+	Content            string     `json:"content"`
+	IsCompleted        bool       `json:"is_completed"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+	Notes              *string    `json:"notes,omitempty"`
+	ProofURL           *string    `json:"proof_url,omitempty"`
+	DueDate            *string    `json:"due_date,omitempty"`
+	ReminderDaysBefore *int       `json:"reminder_days_before,omitempty"`
+	HiddenAt           *time.Time `json:"hidden_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// Redact blanks the content of an item hidden by a moderator so it can be
+// shown to viewers other than the owner.
+func (i *BingoItem) Redact() {
+	if i.HiddenAt == nil {
+		return
+	}
+	i.Content = ""
+	i.Notes = nil
+	i.ProofURL = nil
 }
 
 type CreateCardParams struct {
@@ -194,6 +273,17 @@ type UpdateItemParams struct {
 	Position *int
 }
 
+// MaxReminderDaysBefore is how early a due date reminder can be set.
+const MaxReminderDaysBefore = 30
+
+// UpdateItemScheduleParams sets or clears an item's due date, a calendar
+// date (YYYY-MM-DD) with no time zone. A nil DueDate clears both the date
+// and the reminder.
+type UpdateItemScheduleParams struct {
+	DueDate            *string
+	ReminderDaysBefore *int
+}
+
 type CompleteItemParams struct {
 	Notes    *string
 	ProofURL *string
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxCardRevisions is how many revisions are kept per card. Older ones are
+	// pruned in the background.
+	MaxCardRevisions = 100
+	// TrashRetention is how long a deleted card can be restored before it is
+	// purged for good.
+	TrashRetention = 30 * 24 * time.Hour
+)
+
+// RevisionAction names the edit a card revision records.
+type RevisionAction string
+
+const (
+	RevisionAddItem      RevisionAction = "add_item"
+	RevisionUpdateItem   RevisionAction = "update_item"
+	RevisionRemoveItem   RevisionAction = "remove_item"
+	RevisionSwapItems    RevisionAction = "swap_items"
+	RevisionShuffle      RevisionAction = "shuffle"
+	RevisionUpdateConfig RevisionAction = "update_config"
+	RevisionUpdateMeta   RevisionAction = "update_meta"
+	RevisionRestore      RevisionAction = "restore"
+
+	RevisionCompleteItem   RevisionAction = "complete_item"
+	RevisionUncompleteItem RevisionAction = "uncomplete_item"
+	RevisionUpdateNotes    RevisionAction = "update_notes"
+	RevisionUpdateSchedule RevisionAction = "update_schedule"
+	RevisionVisibility     RevisionAction = "update_visibility"
+	RevisionArchive        RevisionAction = "archive"
+)
+
+// CardSnapshot is the editable state of a card: its title, layout, sharing
+// and the state of each item. Revisions recorded before sharing and item
+// state were captured leave those fields nil, and restoring such a revision
+// keeps the card's current values for them.
+type CardSnapshot struct {
+	Title        *string         `json:"title,omitempty"`
+	Category     *string         `json:"category,omitempty"`
+	HeaderText   string          `json:"header_text"`
+	HasFreeSpace bool            `json:"has_free_space"`
+	FreeSpacePos *int            `json:"free_space_position,omitempty"`
+	Visibility   *CardVisibility `json:"visibility,omitempty"`
+	GroupIDs     []uuid.UUID     `json:"group_ids,omitempty"`
+	IsArchived   *bool           `json:"is_archived,omitempty"`
+	Items        []SnapshotItem  `json:"items"`
+}
+
+type SnapshotItem struct {
+	ID       uuid.UUID          `json:"id"`
+	Position int                `json:"position"`
+	Content  string             `json:"content"`
+	State    *SnapshotItemState `json:"state,omitempty"`
+}
+
+// SnapshotItemState is the part of an item that changes as the owner works
+// through a card.
+type SnapshotItemState struct {
+	IsCompleted        bool       `json:"is_completed"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+	Notes              *string    `json:"notes,omitempty"`
+	ProofURL           *string    `json:"proof_url,omitempty"`
+	DueDate            *string    `json:"due_date,omitempty"`
+	ReminderDaysBefore *int       `json:"reminder_days_before,omitempty"`
+}
+
+// SnapshotOf captures the editable state of card. The groups a card is
+// shared with aren't on BingoCard, so callers fill in GroupIDs.
+func SnapshotOf(card *BingoCard) CardSnapshot {
+	visibility := card.Visibility
+	isArchived := card.IsArchived
+	snap := CardSnapshot{
+		Title:        card.Title,
+		Category:     card.Category,
+		HeaderText:   card.HeaderText,
+		HasFreeSpace: card.HasFreeSpace,
+		FreeSpacePos: card.FreeSpacePos,
+		Visibility:   &visibility,
+		IsArchived:   &isArchived,
+		Items:        make([]SnapshotItem, 0, len(card.Items)),
+	}
+	for _, item := range card.Items {
+		snap.Items = append(snap.Items, SnapshotItem{
+			ID:       item.ID,
+			Position: item.Position,
+			Content:  item.Content,
+			State: &SnapshotItemState{
+				IsCompleted:        item.IsCompleted,
+				CompletedAt:        item.CompletedAt,
+				Notes:              item.Notes,
+				ProofURL:           item.ProofURL,
+				DueDate:            item.DueDate,
+				ReminderDaysBefore: item.ReminderDaysBefore,
+			},
+		})
+	}
+	return snap
+}
+
+// SameLayout reports whether two snapshots have the same grid: header, FREE
+// space and item contents and positions. A finalized card's layout is fixed,
+// so only snapshots with its current layout can be applied to it.
+func (s CardSnapshot) SameLayout(other CardSnapshot) bool {
+	if s.HeaderText != other.HeaderText || s.HasFreeSpace != other.HasFreeSpace || len(s.Items) != len(other.Items) {
+		return false
+	}
+	if (s.FreeSpacePos == nil) != (other.FreeSpacePos == nil) || (s.FreeSpacePos != nil && *s.FreeSpacePos != *other.FreeSpacePos) {
+		return false
+	}
+	positions := make(map[uuid.UUID]SnapshotItem, len(s.Items))
+	for _, item := range s.Items {
+		positions[item.ID] = item
+	}
+	for _, item := range other.Items {
+		mine, ok := positions[item.ID]
+		if !ok || mine.Position != item.Position || mine.Content != item.Content {
+			return false
+		}
+	}
+	return true
+}
+
+// CardRevision is one entry in a card's edit history. UndoneAt is set once
+// the revision has been reverted with undo.
+type CardRevision struct {
+	ID        uuid.UUID      `json:"id"`
+	CardID    uuid.UUID      `json:"card_id"`
+	Action    RevisionAction `json:"action"`
+	Before    CardSnapshot   `json:"before"`
+	After     CardSnapshot   `json:"after"`
+	UndoneAt  *time.Time     `json:"undone_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// TrashedCard summarizes a deleted card that can still be restored.
+type TrashedCard struct {
+	ID          uuid.UUID `json:"id"`
+	Year        int       `json:"year"`
+	Title       *string   `json:"title,omitempty"`
+	Category    *string   `json:"category,omitempty"`
+	GridSize    int       `json:"grid_size"`
+	IsFinalized bool      `json:"is_finalized"`
+	ItemCount   int       `json:"item_count"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+}
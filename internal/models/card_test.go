@@ -123,3 +123,43 @@ func TestIsValidCategory(t *testing.T) {
 		t.Fatal("expected invalid category")
 	}
 }
+
+func TestCardVisibility(t *testing.T) {
+	tests := []struct {
+		visibility CardVisibility
+		valid      bool
+		shared     bool
+		allFriends bool
+		orgs       bool
+	}{
+		{VisibilityPrivate, true, false, false, false},
+		{VisibilityFriends, true, true, true, false},
+		{VisibilityGroups, true, true, false, false},
+		{VisibilityOrganization, true, true, true, true},
+		{VisibilityLink, true, true, true, false},
+		{VisibilityPublic, true, true, true, true},
+		{CardVisibility("everyone"), false, false, false, false},
+		{CardVisibility(""), false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.visibility.IsValid(); got != tt.valid {
+			t.Errorf("%q.IsValid() = %v, want %v", tt.visibility, got, tt.valid)
+		}
+		if tt.valid {
+			if got := tt.visibility.SharedWithFriends(); got != tt.shared {
+				t.Errorf("%q.SharedWithFriends() = %v, want %v", tt.visibility, got, tt.shared)
+			}
+		}
+		if got := tt.visibility.VisibleToAllFriends(); got != tt.allFriends {
+			t.Errorf("%q.VisibleToAllFriends() = %v, want %v", tt.visibility, got, tt.allFriends)
+		}
+		if got := tt.visibility.VisibleToOrganizations(); got != tt.orgs {
+			t.Errorf("%q.VisibleToOrganizations() = %v, want %v", tt.visibility, got, tt.orgs)
+		}
+	}
+
+	if VisibilityFromFlag(true) != VisibilityFriends || VisibilityFromFlag(false) != VisibilityPrivate {
+		t.Fatal("unexpected visibility from flag")
+	}
+}
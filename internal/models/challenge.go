@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxChallengeNameLength is the longest challenge name accepted, in characters.
+	MaxChallengeNameLength = 100
+	// MaxChallengeParticipants caps the creator plus invited friends.
+	MaxChallengeParticipants = 20
+	// MaxChallengeDuration is the longest a challenge may run.
+	MaxChallengeDuration = 366 * 24 * time.Hour
+)
+
+type ChallengeStatus string
+
+const (
+	ChallengeUpcoming ChallengeStatus = "upcoming"
+	ChallengeActive   ChallengeStatus = "active"
+	ChallengeFinished ChallengeStatus = "finished"
+)
+
+// Challenge is a time-boxed competition between friends on items completed.
+// Joined reports whether the viewer has accepted the invitation.
+type Challenge struct {
+	ID               uuid.UUID       `json:"id"`
+	CreatorID        uuid.UUID       `json:"creator_id"`
+	CreatorUsername  string          `json:"creator_username"`
+	Name             string          `json:"name"`
+	StartsAt         time.Time       `json:"starts_at"`
+	EndsAt           time.Time       `json:"ends_at"`
+	Status           ChallengeStatus `json:"status"`
+	Joined           bool            `json:"joined"`
+	ParticipantCount int             `json:"participant_count"`
+	AnnouncedAt      *time.Time      `json:"announced_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+// StatusAt reports where the challenge stands at the given time.
+func (c *Challenge) StatusAt(now time.Time) ChallengeStatus {
+	switch {
+	case now.Before(c.StartsAt):
+		return ChallengeUpcoming
+	case now.Before(c.EndsAt):
+		return ChallengeActive
+	default:
+		return ChallengeFinished
+	}
+}
+
+// ChallengeParticipant is a friend invited to a challenge. Only joined
+// participants are ranked.
+type ChallengeParticipant struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Joined   bool      `json:"joined"`
+}
+
+// ChallengeStanding is a joined participant's score: items completed on their
+// finalized cards between the challenge's start and end. Ties go to whoever
+// reached the score first.
+type ChallengeStanding struct {
+	Rank           int        `json:"rank"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Username       string     `json:"username"`
+	Completions    int        `json:"completions"`
+	LastCompletion *time.Time `json:"last_completion,omitempty"`
+}
+
+type ChallengeDetail struct {
+	Challenge
+	Participants []ChallengeParticipant `json:"participants"`
+	Standings    []ChallengeStanding    `json:"standings"`
+}
+
+type CreateChallengeParams struct {
+	Name      string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	FriendIDs []uuid.UUID
+}
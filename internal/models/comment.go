@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxCommentLength is the longest comment body accepted, in characters.
+const MaxCommentLength = 1000
+
+// ItemComment is a comment on a bingo item. Replies carry the ID of the
+// top-level comment they belong to.
+type ItemComment struct {
+	ID        uuid.UUID  `json:"id"`
+	ItemID    uuid.UUID  `json:"item_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Username  string     `json:"username"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxFriendGroupNameLength is the longest group name accepted, in characters.
+const MaxFriendGroupNameLength = 50
+
+// FriendGroup is a named set of the owner's friends that cards can be shared
+// with.
+type FriendGroup struct {
+	ID        uuid.UUID           `json:"id"`
+	Name      string              `json:"name"`
+	Members   []FriendGroupMember `json:"members"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+type FriendGroupMember struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+}
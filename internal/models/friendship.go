@@ -36,3 +36,19 @@ type UserSearchResult struct {
 	ID       uuid.UUID `json:"id"`
 	Username string    `json:"username"`
 }
+
+// FriendSuggestion is a user the viewer isn't connected to yet, ranked by how
+// many friends they have in common.
+type FriendSuggestion struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	MutualFriends int       `json:"mutual_friends"`
+}
+
+const (
+	// MaxFriendSuggestions caps the "people you may know" list.
+	MaxFriendSuggestions = 20
+	// MaxDiscoveryEmailHashes caps how many contact hashes one discovery
+	// request may upload.
+	MaxDiscoveryEmailHashes = 500
+)
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// JobStatus describes a scheduled background job: its schedule from this
+// server and the outcome of its latest run on any replica.
+type JobStatus struct {
+	Name           string     `json:"name"`
+	Schedule       string     `json:"schedule"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	Running        bool       `json:"running"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastDurationMs *int64     `json:"last_duration_ms,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	LastSuccessAt  *time.Time `json:"last_success_at,omitempty"`
+	LastInstance   *string    `json:"last_instance,omitempty"`
+	RunCount       int64      `json:"run_count"`
+}
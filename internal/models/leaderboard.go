@@ -0,0 +1,66 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Leaderboard ranks the viewer and their friends by how far they got on
+// their cards for one year. Users who opted out are left off; OptedOut tells
+// the viewer when that includes them.
+type Leaderboard struct {
+	Year     int                `json:"year"`
+	Entries  []LeaderboardEntry `json:"entries"`
+	OptedOut bool               `json:"opted_out"`
+}
+
+// LeaderboardEntry totals one user's cards for the year. Users with several
+// cards are scored on all of them together.
+type LeaderboardEntry struct {
+	Rank           int       `json:"rank"`
+	UserID         uuid.UUID `json:"user_id"`
+	Username       string    `json:"username"`
+	IsYou          bool      `json:"is_you"`
+	CardCount      int       `json:"card_count"`
+	TotalItems     int       `json:"total_items"`
+	CompletedItems int       `json:"completed_items"`
+	CompletionRate float64   `json:"completion_rate"`
+	Bingos         int       `json:"bingos"`
+}
+
+// RankLeaderboard sorts entries by completion rate, then bingos, then items
+// completed, and numbers them. Entries that tie on all three share a rank.
+func RankLeaderboard(entries []LeaderboardEntry) {
+	for i := range entries {
+		if entries[i].TotalItems > 0 {
+			entries[i].CompletionRate = float64(entries[i].CompletedItems) / float64(entries[i].TotalItems) * 100
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.CompletionRate != b.CompletionRate {
+			return a.CompletionRate > b.CompletionRate
+		}
+		if a.Bingos != b.Bingos {
+			return a.Bingos > b.Bingos
+		}
+		if a.CompletedItems != b.CompletedItems {
+			return a.CompletedItems > b.CompletedItems
+		}
+		return a.Username < b.Username
+	})
+
+	for i := range entries {
+		if i > 0 && sameLeaderboardScore(entries[i], entries[i-1]) {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+}
+
+func sameLeaderboardScore(a, b LeaderboardEntry) bool {
+	return a.CompletionRate == b.CompletionRate && a.Bingos == b.Bingos && a.CompletedItems == b.CompletedItems
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRankLeaderboard(t *testing.T) {
+	entries := []LeaderboardEntry{
+		{Username: "dana", TotalItems: 24, CompletedItems: 6},
+		{Username: "ben", TotalItems: 24, CompletedItems: 12, Bingos: 1},
+		{Username: "cat", TotalItems: 24, CompletedItems: 12, Bingos: 2},
+		{Username: "al", TotalItems: 24, CompletedItems: 12, Bingos: 1},
+		{Username: "eve"},
+	}
+
+	RankLeaderboard(entries)
+
+	want := []struct {
+		name string
+		rank int
+	}{{"cat", 1}, {"al", 2}, {"ben", 2}, {"dana", 4}, {"eve", 5}}
+	for i, w := range want {
+		if entries[i].Username != w.name || entries[i].Rank != w.rank {
+			t.Fatalf("position %d: expected %s at rank %d, got %s at rank %d", i, w.name, w.rank, entries[i].Username, entries[i].Rank)
+		}
+	}
+	if entries[0].CompletionRate != 50 {
+		t.Fatalf("expected completion rate 50, got %v", entries[0].CompletionRate)
+	}
+}
+
+func TestChallenge_StatusAt(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	c := &Challenge{StartsAt: start, EndsAt: start.Add(7 * 24 * time.Hour)}
+
+	if got := c.StatusAt(start.Add(-time.Second)); got != ChallengeUpcoming {
+		t.Fatalf("expected upcoming, got %s", got)
+	}
+	if got := c.StatusAt(start); got != ChallengeActive {
+		t.Fatalf("expected active, got %s", got)
+	}
+	if got := c.StatusAt(c.EndsAt); got != ChallengeFinished {
+		t.Fatalf("expected finished, got %s", got)
+	}
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportTargetType string
+
+const (
+	ReportTargetUser     ReportTargetType = "user"
+	ReportTargetCard     ReportTargetType = "card"
+	ReportTargetItem     ReportTargetType = "item"
+	ReportTargetTemplate ReportTargetType = "template"
+)
+
+type ReportReason string
+
+const (
+	ReportReasonSpam       ReportReason = "spam"
+	ReportReasonHarassment ReportReason = "harassment"
+	ReportReasonHate       ReportReason = "hate"
+	ReportReasonSexual     ReportReason = "sexual"
+	ReportReasonSelfHarm   ReportReason = "self_harm"
+	ReportReasonOther      ReportReason = "other"
+)
+
+// ValidReportReasons lists the reason codes accepted by the report endpoint.
+var ValidReportReasons = []ReportReason{
+	ReportReasonSpam,
+	ReportReasonHarassment,
+	ReportReasonHate,
+	ReportReasonSexual,
+	ReportReasonSelfHarm,
+	ReportReasonOther,
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusActioned  ReportStatus = "actioned"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+type Report struct {
+	ID             uuid.UUID        `json:"id"`
+	ReporterID     uuid.UUID        `json:"reporter_id"`
+	TargetType     ReportTargetType `json:"target_type"`
+	TargetUserID   uuid.UUID        `json:"target_user_id"`
+	CardID         *uuid.UUID       `json:"card_id,omitempty"`
+	ItemID         *uuid.UUID       `json:"item_id,omitempty"`
+	TemplateID     *uuid.UUID       `json:"template_id,omitempty"`
+	Reason         ReportReason     `json:"reason"`
+	Details        *string          `json:"details,omitempty"`
+	Status         ReportStatus     `json:"status"`
+	ResolvedBy     *uuid.UUID       `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time       `json:"resolved_at,omitempty"`
+	ResolutionNote *string          `json:"resolution_note,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// ModerationReport is a report as shown in the admin moderation queue.
+type ModerationReport struct {
+	Report
+	ReporterUsername string  `json:"reporter_username"`
+	TargetUsername   string  `json:"target_username"`
+	TargetDisabled   bool    `json:"target_disabled"`
+	CardTitle        *string `json:"card_title,omitempty"`
+	ItemContent      *string `json:"item_content,omitempty"`
+	ItemHidden       bool    `json:"item_hidden"`
+	TemplateTitle    *string `json:"template_title,omitempty"`
+	TemplateHidden   bool    `json:"template_hidden"`
+}
+
+type CreateReportParams struct {
+	ReporterID uuid.UUID
+	TargetType ReportTargetType
+	TargetID   uuid.UUID
+	Reason     ReportReason
+	Details    *string
+	// BlockUser also blocks the reported user for the reporter.
+	BlockUser bool
+}
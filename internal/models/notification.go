@@ -13,6 +13,9 @@ const (
 	NotificationTypeFriendRequestAccepted NotificationType = "friend_request_accepted"
 	NotificationTypeFriendBingo           NotificationType = "friend_bingo"
 	NotificationTypeFriendNewCard         NotificationType = "friend_new_card"
+	NotificationTypeItemComment           NotificationType = "item_comment"
+	NotificationTypeChallengeResult       NotificationType = "challenge_result"
+	NotificationTypeOrgTemplate           NotificationType = "org_template"
 )
 
 type Notification struct {
@@ -26,6 +29,11 @@ type Notification struct {
 	CardTitle      *string          `json:"card_title,omitempty"`
 	CardYear       *int             `json:"card_year,omitempty"`
 	BingoCount     *int             `json:"bingo_count,omitempty"`
+	ChallengeID    *uuid.UUID       `json:"challenge_id,omitempty"`
+	ChallengeName  *string          `json:"challenge_name,omitempty"`
+	TemplateID     *uuid.UUID       `json:"template_id,omitempty"`
+	TemplateTitle  *string          `json:"template_title,omitempty"`
+	OrgName        *string          `json:"org_name,omitempty"`
 	InAppDelivered bool             `json:"in_app_delivered"`
 	EmailDelivered bool             `json:"email_delivered"`
 	EmailSentAt    *time.Time       `json:"email_sent_at,omitempty"`
@@ -40,11 +48,17 @@ type NotificationSettings struct {
 	InAppFriendRequestAccepted bool      `json:"in_app_friend_request_accepted"`
 	InAppFriendBingo           bool      `json:"in_app_friend_bingo"`
 	InAppFriendNewCard         bool      `json:"in_app_friend_new_card"`
+	InAppItemComment           bool      `json:"in_app_item_comment"`
+	InAppChallengeResult       bool      `json:"in_app_challenge_result"`
+	InAppOrgTemplate           bool      `json:"in_app_org_template"`
 	EmailEnabled               bool      `json:"email_enabled"`
 	EmailFriendRequestReceived bool      `json:"email_friend_request_received"`
 	EmailFriendRequestAccepted bool      `json:"email_friend_request_accepted"`
 	EmailFriendBingo           bool      `json:"email_friend_bingo"`
 	EmailFriendNewCard         bool      `json:"email_friend_new_card"`
+	EmailItemComment           bool      `json:"email_item_comment"`
+	EmailChallengeResult       bool      `json:"email_challenge_result"`
+	EmailOrgTemplate           bool      `json:"email_org_template"`
 	CreatedAt                  time.Time `json:"created_at"`
 	UpdatedAt                  time.Time `json:"updated_at"`
 }
@@ -55,9 +69,15 @@ type NotificationSettingsPatch struct {
 	InAppFriendRequestAccepted *bool `json:"in_app_friend_request_accepted,omitempty"`
 	InAppFriendBingo           *bool `json:"in_app_friend_bingo,omitempty"`
 	InAppFriendNewCard         *bool `json:"in_app_friend_new_card,omitempty"`
+	InAppItemComment           *bool `json:"in_app_item_comment,omitempty"`
+	InAppChallengeResult       *bool `json:"in_app_challenge_result,omitempty"`
+	InAppOrgTemplate           *bool `json:"in_app_org_template,omitempty"`
 	EmailEnabled               *bool `json:"email_enabled,omitempty"`
 	EmailFriendRequestReceived *bool `json:"email_friend_request_received,omitempty"`
 	EmailFriendRequestAccepted *bool `json:"email_friend_request_accepted,omitempty"`
 	EmailFriendBingo           *bool `json:"email_friend_bingo,omitempty"`
 	EmailFriendNewCard         *bool `json:"email_friend_new_card,omitempty"`
+	EmailItemComment           *bool `json:"email_item_comment,omitempty"`
+	EmailChallengeResult       *bool `json:"email_challenge_result,omitempty"`
+	EmailOrgTemplate           *bool `json:"email_org_template,omitempty"`
 }
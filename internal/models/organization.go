@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const MaxOrganizationNameLength = 100
+
+// OrgRole is a member's role in an organization. Admins manage membership,
+// invites, domains and organization templates and can see aggregate stats;
+// they see members' cards no differently than any other member does.
+type OrgRole string
+
+const (
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleMember OrgRole = "member"
+)
+
+func (r OrgRole) IsValid() bool {
+	return r == OrgRoleAdmin || r == OrgRoleMember
+}
+
+// Organization is an organization as seen by one of its members. Role is
+// that member's role.
+type Organization struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Role        OrgRole   `json:"role"`
+	MemberCount int       `json:"member_count"`
+	Domains     []string  `json:"domains"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// JoinableOrganization is an organization the user may join because their
+// verified email is at one of its domains.
+type JoinableOrganization struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Domain      string    `json:"domain"`
+	MemberCount int       `json:"member_count"`
+}
+
+type OrgMember struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Role     OrgRole   `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// OrgInvite is a reusable invite link. The token itself is only returned
+// when the invite is created.
+type OrgInvite struct {
+	ID        uuid.UUID  `json:"id"`
+	OrgID     uuid.UUID  `json:"org_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	UseCount  int        `json:"use_count"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// OrgMemberCards is what one member shows the rest of the organization.
+type OrgMemberCards struct {
+	Username string       `json:"username"`
+	Cards    []*BingoCard `json:"cards"`
+}
+
+// OrgStats totals an organization's cards for one year. Only finalized cards
+// shared with the organization count, so the totals never reveal anything
+// about private cards.
+type OrgStats struct {
+	Year           int `json:"year"`
+	MemberCount    int `json:"member_count"`
+	SharingMembers int `json:"sharing_members"`
+	CardCount      int `json:"card_count"`
+	TotalItems     int `json:"total_items"`
+	CompletedItems int `json:"completed_items"`
+	Bingos         int `json:"bingos"`
+	// CompletionsByMonth counts completed items by the month they were
+	// completed, January first.
+	CompletionsByMonth [12]int            `json:"completions_by_month"`
+	Templates          []OrgTemplateUsage `json:"templates"`
+}
+
+type OrgTemplateUsage struct {
+	TemplateID uuid.UUID `json:"template_id"`
+	Title      string    `json:"title"`
+	UseCount   int       `json:"use_count"`
+}
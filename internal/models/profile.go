@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// PublicProfile is what anyone can see at /u/{username} once the user opts
+// in. Stats and badges are computed from public cards only.
+type PublicProfile struct {
+	Username    string       `json:"username"`
+	MemberSince time.Time    `json:"member_since"`
+	Stats       ProfileStats `json:"stats"`
+	Badges      []Badge      `json:"badges"`
+	Cards       []*BingoCard `json:"cards"`
+}
+
+// ProfileStats aggregates a user's public cards across years.
+type ProfileStats struct {
+	Years          []int   `json:"years"`
+	CardCount      int     `json:"card_count"`
+	TotalItems     int     `json:"total_items"`
+	CompletedItems int     `json:"completed_items"`
+	CompletionRate float64 `json:"completion_rate"`
+	Bingos         int     `json:"bingos"`
+}
+
+// Badge is an achievement shown on a public profile.
+type Badge struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+const (
+	BadgeFirstBingo  = "first_bingo"
+	BadgeHalfway     = "halfway"
+	BadgeBlackout    = "blackout"
+	BadgeMultiYear   = "multi_year"
+	multiYearMinimum = 3
+)
+
+// EarnedBadges returns the badges earned by a set of cards' stats, in a fixed
+// display order.
+func EarnedBadges(stats []CardStats) []Badge {
+	var bingo, halfway, blackout bool
+	years := make(map[int]bool)
+	for _, s := range stats {
+		years[s.Year] = true
+		if s.BingosAchieved > 0 {
+			bingo = true
+		}
+		if s.TotalItems > 0 && s.CompletedItems*2 >= s.TotalItems {
+			halfway = true
+		}
+		if s.TotalItems > 0 && s.CompletedItems == s.TotalItems {
+			blackout = true
+		}
+	}
+
+	badges := []Badge{}
+	if bingo {
+		badges = append(badges, Badge{ID: BadgeFirstBingo, Name: "First Bingo", Description: "Completed a full line on a card"})
+	}
+	if halfway {
+		badges = append(badges, Badge{ID: BadgeHalfway, Name: "Halfway There", Description: "Completed at least half of a card"})
+	}
+	if blackout {
+		badges = append(badges, Badge{ID: BadgeBlackout, Name: "Blackout", Description: "Completed every goal on a card"})
+	}
+	if len(years) >= multiYearMinimum {
+		badges = append(badges, Badge{ID: BadgeMultiYear, Name: "Veteran", Description: "Played bingo for three or more years"})
+	}
+	return badges
+}
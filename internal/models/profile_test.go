@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestEarnedBadges(t *testing.T) {
+	if badges := EarnedBadges(nil); len(badges) != 0 {
+		t.Fatalf("expected no badges, got %+v", badges)
+	}
+
+	badges := EarnedBadges([]CardStats{
+		{Year: 2023, TotalItems: 24, CompletedItems: 3},
+		{Year: 2024, TotalItems: 24, CompletedItems: 12, BingosAchieved: 1},
+		{Year: 2025, TotalItems: 0},
+	})
+	var ids []string
+	for _, b := range badges {
+		ids = append(ids, b.ID)
+	}
+	want := []string{BadgeFirstBingo, BadgeHalfway, BadgeMultiYear}
+	if len(ids) != len(want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, ids)
+		}
+	}
+
+	badges = EarnedBadges([]CardStats{{Year: 2024, TotalItems: 9, CompletedItems: 9}})
+	if len(badges) != 2 || badges[1].ID != BadgeBlackout {
+		t.Fatalf("expected halfway and blackout, got %+v", badges)
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxTemplateDescriptionLength is the longest template description
+	// accepted, in characters.
+	MaxTemplateDescriptionLength = 500
+	// DefaultTemplatePageSize and MaxTemplatePageSize bound a gallery page.
+	DefaultTemplatePageSize = 20
+	MaxTemplatePageSize     = 50
+)
+
+// TemplateVisibility controls whether a template is listed in the gallery.
+// Unlisted templates can still be opened by anyone with the link.
+// Organization templates are offered only to the members of one
+// organization.
+type TemplateVisibility string
+
+const (
+	TemplatePublic       TemplateVisibility = "public"
+	TemplateUnlisted     TemplateVisibility = "unlisted"
+	TemplateOrganization TemplateVisibility = "organization"
+)
+
+func (v TemplateVisibility) IsValid() bool {
+	return v == TemplatePublic || v == TemplateUnlisted || v == TemplateOrganization
+}
+
+// TemplateSort orders the template gallery.
+type TemplateSort string
+
+const (
+	TemplateSortPopular TemplateSort = "popular"
+	TemplateSortNewest  TemplateSort = "newest"
+)
+
+// CardTemplate is a card layout and item list published for others to start
+// from. UseCount is how many cards have been created from it.
+type CardTemplate struct {
+	ID            uuid.UUID          `json:"id"`
+	OwnerID       uuid.UUID          `json:"owner_id"`
+	OwnerUsername string             `json:"owner_username"`
+	SourceCardID  *uuid.UUID         `json:"source_card_id,omitempty"`
+	Title         string             `json:"title"`
+	Description   *string            `json:"description,omitempty"`
+	Category      *string            `json:"category,omitempty"`
+	GridSize      int                `json:"grid_size"`
+	HeaderText    string             `json:"header_text"`
+	HasFreeSpace  bool               `json:"has_free_space"`
+	FreeSpacePos  *int               `json:"free_space_position,omitempty"`
+	Items         []TemplateItem     `json:"items"`
+	Visibility    TemplateVisibility `json:"visibility"`
+	OrgID         *uuid.UUID         `json:"org_id,omitempty"`
+	UseCount      int                `json:"use_count"`
+	HiddenAt      *time.Time         `json:"hidden_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+type TemplateItem struct {
+	Position int    `json:"position"`
+	Content  string `json:"content"`
+}
+
+type PublishTemplateParams struct {
+	OwnerID     uuid.UUID
+	CardID      uuid.UUID
+	Title       *string // Optional; defaults to the card's title
+	Description *string
+	Visibility  TemplateVisibility
+	OrgID       *uuid.UUID // Required for TemplateOrganization, which only org admins may use
+}
+
+type UpdateTemplateParams struct {
+	Title       *string
+	Description *string
+	Visibility  *TemplateVisibility
+}
+
+// TemplateListParams filters and pages the public template gallery.
+type TemplateListParams struct {
+	Query    string
+	Category string
+	GridSize int
+	Sort     TemplateSort
+	Limit    int
+	Offset   int
+}
+
+// InstantiateTemplateParams names the card created from a template.
+type InstantiateTemplateParams struct {
+	UserID uuid.UUID
+	Year   int
+	Title  *string // Optional; defaults to the template's title
+}
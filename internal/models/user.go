@@ -15,13 +15,24 @@ type User struct {
 	EmailVerifiedAt       *time.Time `json:"email_verified_at,omitempty"`
 	AIFreeGenerationsUsed int        `json:"ai_free_generations_used"`
 	Searchable            bool       `json:"searchable"`
+	PublicProfile         bool       `json:"public_profile"`
+	LeaderboardOptOut     bool       `json:"leaderboard_opt_out"`
+	Locale                string     `json:"locale"`
+	IsAdmin               bool       `json:"is_admin"`
+	DisabledAt            *time.Time `json:"disabled_at,omitempty"`
 	CreatedAt             time.Time  `json:"created_at"`
 	UpdatedAt             time.Time  `json:"updated_at"`
 }
 
+// IsDisabled reports whether an admin has disabled the account.
+func (u *User) IsDisabled() bool {
+	return u != nil && u.DisabledAt != nil
+}
+
 type CreateUserParams struct {
 	Email        string
 	PasswordHash string
 	Username     string
 	Searchable   bool
+	Locale       string // preferred language for email; normalized to a supported locale
 }
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const maxUserAgentLength = 512
+
+// signInEvents are the event types that start a session. A device counts as
+// recognized once one of these has succeeded from it.
+var signInEvents = []string{
+	string(models.AccountEventRegister),
+	string(models.AccountEventLogin),
+	string(models.AccountEventMagicLinkLogin),
+	string(models.AccountEventPasswordReset),
+}
+
+// RequestMeta describes the client behind a request for account event records.
+type RequestMeta struct {
+	IPAddress string
+	UserAgent string
+}
+
+type requestMetaKey struct{}
+
+// WithRequestMeta returns a context carrying the client IP and user agent.
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	if len(meta.UserAgent) > maxUserAgentLength {
+		meta.UserAgent = meta.UserAgent[:maxUserAgentLength]
+	}
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext returns the request metadata, or the zero value when
+// the context carries none (background jobs, tests).
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+// AccountEventListParams controls pagination for a user's account activity.
+// Before is a created_at cursor from the previous page.
+type AccountEventListParams struct {
+	Limit  int
+	Before *time.Time
+}
+
+type AccountEventService struct {
+	db     DBConn
+	alerts LoginAlertSender
+}
+
+func NewAccountEventService(db DBConn, alerts LoginAlertSender) *AccountEventService {
+	return &AccountEventService{db: db, alerts: alerts}
+}
+
+// Record appends an event for the user, taking IP and user agent from ctx.
+func (s *AccountEventService) Record(ctx context.Context, userID uuid.UUID, eventType models.AccountEventType, outcome models.AccountEventOutcome, details map[string]any) error {
+	_, _, err := s.insert(ctx, userID, eventType, outcome, details)
+	return err
+}
+
+// RecordLogin records a successful sign-in and emails the user when it came
+// from a device that has not signed in before. Devices are recognized by user
+// agent; addresses change too often on mobile networks to be useful alone.
+// The first sign-in on an account never triggers an alert.
+func (s *AccountEventService) RecordLogin(ctx context.Context, user *models.User, eventType models.AccountEventType) error {
+	meta := RequestMetaFromContext(ctx)
+
+	var priorSignIns, fromDevice int
+	err := s.db.QueryRow(ctx,
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE user_agent IS NOT DISTINCT FROM $2)
+		 FROM account_events
+		 WHERE user_id = $1 AND outcome = 'success' AND event_type = ANY($3)`,
+		user.ID, nullableString(meta.UserAgent), signInEvents,
+	).Scan(&priorSignIns, &fromDevice)
+	if err != nil {
+		return fmt.Errorf("check known devices: %w", err)
+	}
+
+	eventID, createdAt, err := s.insert(ctx, user.ID, eventType, models.AccountEventSuccess, nil)
+	if err != nil {
+		return err
+	}
+
+	if priorSignIns == 0 || fromDevice > 0 || s.alerts == nil {
+		return nil
+	}
+	if err := s.alerts.SendNewLoginAlert(ctx, eventID, user.Email, meta, createdAt); err != nil {
+		logging.FromContext(ctx).Error("Failed to send new login alert", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": user.ID.String(),
+		})
+	}
+	return nil
+}
+
+// List returns the user's events, newest first.
+func (s *AccountEventService) List(ctx context.Context, userID uuid.UUID, params AccountEventListParams) ([]models.AccountEvent, error) {
+	limit := params.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT id, user_id, event_type, outcome, ip_address, user_agent, details, created_at
+		 FROM account_events
+		 WHERE user_id = $1 AND ($2::timestamptz IS NULL OR created_at < $2)
+		 ORDER BY created_at DESC
+		 LIMIT $3`,
+		userID, params.Before, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list account events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.AccountEvent{}
+	for rows.Next() {
+		var e models.AccountEvent
+		var eventType, outcome string
+		var details []byte
+		if err := rows.Scan(&e.ID, &e.UserID, &eventType, &outcome, &e.IPAddress, &e.UserAgent, &details, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan account event: %w", err)
+		}
+		e.EventType = models.AccountEventType(eventType)
+		e.Outcome = models.AccountEventOutcome(outcome)
+		e.Details = map[string]any{}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return nil, fmt.Errorf("decode account event details: %w", err)
+			}
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list account events: %w", err)
+	}
+	return events, nil
+}
+
+func (s *AccountEventService) insert(ctx context.Context, userID uuid.UUID, eventType models.AccountEventType, outcome models.AccountEventOutcome, details map[string]any) (uuid.UUID, time.Time, error) {
+	if details == nil {
+		details = map[string]any{}
+	}
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return uuid.Nil, time.Time{}, fmt.Errorf("encode account event details: %w", err)
+	}
+
+	meta := RequestMetaFromContext(ctx)
+	var id uuid.UUID
+	var createdAt time.Time
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO account_events (user_id, event_type, outcome, ip_address, user_agent, details)
+		 VALUES ($1, $2, $3, $4, $5, $6::jsonb)
+		 RETURNING id, created_at`,
+		userID, string(eventType), string(outcome), nullableString(meta.IPAddress), nullableString(meta.UserAgent), string(payload),
+	).Scan(&id, &createdAt)
+	if err != nil {
+		return uuid.Nil, time.Time{}, fmt.Errorf("record account event: %w", err)
+	}
+	return id, createdAt, nil
+}
+
+// recordAccountEvent records an event on behalf of another service. The action
+// being recorded has already happened, so failures are logged, not returned.
+func recordAccountEvent(ctx context.Context, recorder AccountEventRecorder, userID uuid.UUID, eventType models.AccountEventType, details map[string]any) {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.Record(ctx, userID, eventType, models.AccountEventSuccess, details); err != nil {
+		logging.FromContext(ctx).Warn("Failed to record account event", map[string]interface{}{
+			"error":      err.Error(),
+			"user_id":    userID.String(),
+			"event_type": string(eventType),
+		})
+	}
+}
+
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type fakeLoginAlertSender struct {
+	sent []RequestMeta
+	err  error
+}
+
+func (f *fakeLoginAlertSender) SendNewLoginAlert(ctx context.Context, eventID uuid.UUID, toEmail string, meta RequestMeta, at time.Time) error {
+	f.sent = append(f.sent, meta)
+	return f.err
+}
+
+type fakeAccountEventRecorder struct {
+	events  []models.AccountEventType
+	details []map[string]any
+	err     error
+}
+
+func (f *fakeAccountEventRecorder) Record(ctx context.Context, userID uuid.UUID, eventType models.AccountEventType, outcome models.AccountEventOutcome, details map[string]any) error {
+	f.events = append(f.events, eventType)
+	f.details = append(f.details, details)
+	return f.err
+}
+
+// loginEventDB answers the known-device query with the given counts and
+// accepts the event insert.
+func loginEventDB(t *testing.T, priorSignIns, fromDevice int, inserted *[]any) *fakeDB {
+	t.Helper()
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM account_events"):
+				return rowFromValues(priorSignIns, fromDevice)
+			case strings.Contains(sql, "INSERT INTO account_events"):
+				*inserted = args
+				return rowFromValues(uuid.New(), time.Now())
+			default:
+				t.Fatalf("unexpected query: %s", sql)
+				return nil
+			}
+		},
+	}
+}
+
+func TestWithRequestMeta_TruncatesUserAgent(t *testing.T) {
+	ctx := WithRequestMeta(context.Background(), RequestMeta{IPAddress: "1.2.3.4", UserAgent: strings.Repeat("a", 600)})
+	meta := RequestMetaFromContext(ctx)
+	if meta.IPAddress != "1.2.3.4" || len(meta.UserAgent) != maxUserAgentLength {
+		t.Fatalf("unexpected meta: %+v", meta)
+	}
+	if got := RequestMetaFromContext(context.Background()); got != (RequestMeta{}) {
+		t.Fatalf("expected zero meta, got %+v", got)
+	}
+}
+
+func TestAccountEventService_Record_UsesRequestMeta(t *testing.T) {
+	var args []any
+	db := loginEventDB(t, 0, 0, &args)
+	svc := NewAccountEventService(db, nil)
+	ctx := WithRequestMeta(context.Background(), RequestMeta{IPAddress: "10.0.0.1"})
+
+	err := svc.Record(ctx, uuid.New(), models.AccountEventLogin, models.AccountEventFailure, map[string]any{"reason": "invalid_password"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args[1] != "login" || args[2] != "failure" {
+		t.Fatalf("unexpected event args: %v", args)
+	}
+	if ip, ok := args[3].(*string); !ok || ip == nil || *ip != "10.0.0.1" {
+		t.Fatalf("expected ip address arg, got %v", args[3])
+	}
+	if ua, ok := args[4].(*string); !ok || ua != nil {
+		t.Fatalf("expected nil user agent, got %v", args[4])
+	}
+	if args[5] != `{"reason":"invalid_password"}` {
+		t.Fatalf("unexpected details: %v", args[5])
+	}
+}
+
+func TestAccountEventService_RecordLogin_NewDeviceAlerts(t *testing.T) {
+	var args []any
+	alerts := &fakeLoginAlertSender{}
+	svc := NewAccountEventService(loginEventDB(t, 3, 0, &args), alerts)
+	ctx := WithRequestMeta(context.Background(), RequestMeta{IPAddress: "10.0.0.1", UserAgent: "NewBrowser/1.0"})
+
+	if err := svc.RecordLogin(ctx, &models.User{ID: uuid.New(), Email: "a@example.com"}, models.AccountEventLogin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts.sent) != 1 || alerts.sent[0].UserAgent != "NewBrowser/1.0" {
+		t.Fatalf("expected one alert, got %+v", alerts.sent)
+	}
+	if args[2] != "success" {
+		t.Fatalf("expected success outcome, got %v", args[2])
+	}
+}
+
+func TestAccountEventService_RecordLogin_NoAlert(t *testing.T) {
+	tests := []struct {
+		name         string
+		priorSignIns int
+		fromDevice   int
+	}{
+		{"first sign-in", 0, 0},
+		{"known device", 4, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []any
+			alerts := &fakeLoginAlertSender{}
+			svc := NewAccountEventService(loginEventDB(t, tt.priorSignIns, tt.fromDevice, &args), alerts)
+			if err := svc.RecordLogin(context.Background(), &models.User{ID: uuid.New()}, models.AccountEventMagicLinkLogin); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(alerts.sent) != 0 {
+				t.Fatalf("expected no alert, got %d", len(alerts.sent))
+			}
+			if args == nil {
+				t.Fatal("expected event insert")
+			}
+		})
+	}
+}
+
+func TestAccountEventService_RecordLogin_AlertFailureIsNotFatal(t *testing.T) {
+	var args []any
+	alerts := &fakeLoginAlertSender{err: errors.New("smtp down")}
+	svc := NewAccountEventService(loginEventDB(t, 1, 0, &args), alerts)
+	if err := svc.RecordLogin(context.Background(), &models.User{ID: uuid.New()}, models.AccountEventLogin); err != nil {
+		t.Fatalf("expected alert failure to be logged only, got %v", err)
+	}
+}
+
+func TestAccountEventService_List(t *testing.T) {
+	userID := uuid.New()
+	before := time.Now()
+	ip := "10.0.0.1"
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if args[0] != userID || args[1] != &before || args[2] != 50 {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRows{rows: [][]any{
+				{uuid.New(), userID, "user_blocked", "success", &ip, (*string)(nil), []byte(`{"blocked_user_id":"x"}`), time.Now()},
+			}}, nil
+		},
+	}
+
+	events, err := NewAccountEventService(db, nil).List(context.Background(), userID, AccountEventListParams{Limit: 500, Before: &before})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].EventType != models.AccountEventUserBlocked || events[0].Details["blocked_user_id"] != "x" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestApiTokenService_RecordsAccountEvents(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 2}, nil
+		},
+	}
+	recorder := &fakeAccountEventRecorder{}
+	svc := NewApiTokenService(db)
+	svc.SetAccountEvents(recorder)
+
+	if err := svc.Delete(context.Background(), uuid.New(), uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.DeleteAll(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.events) != 2 || recorder.events[0] != models.AccountEventTokenDeleted || recorder.events[1] != models.AccountEventTokensRevoked {
+		t.Fatalf("unexpected events: %v", recorder.events)
+	}
+	if recorder.details[1]["count"] != int64(2) {
+		t.Fatalf("expected revoked count, got %v", recorder.details[1])
+	}
+}
+
+func TestBlockService_Unblock_RecordFailureIsNotFatal(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	recorder := &fakeAccountEventRecorder{err: errors.New("db down")}
+	svc := NewBlockService(db)
+	svc.SetAccountEvents(recorder)
+
+	if err := svc.Unblock(context.Background(), uuid.New(), uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.events) != 1 || recorder.events[0] != models.AccountEventUserUnblocked {
+		t.Fatalf("unexpected events: %v", recorder.events)
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var ErrInvalidFeedCursor = errors.New("invalid feed cursor")
+
+// FeedParams controls pagination for the friend activity feed. Cursor is the
+// opaque NextCursor from the previous page.
+type FeedParams struct {
+	Limit  int
+	Cursor string
+}
+
+type ActivityService struct {
+	db DBConn
+}
+
+func NewActivityService(db DBConn) *ActivityService {
+	return &ActivityService{db: db}
+}
+
+// Record stores a feed event. Repeats of the same completion, bingo or
+// finalization are ignored; a repeated reaction updates the emoji.
+func (s *ActivityService) Record(ctx context.Context, event models.ActivityEvent) error {
+	conflict := "ON CONFLICT DO NOTHING"
+	if event.Type == models.ActivityReaction {
+		conflict = `ON CONFLICT (item_id, actor_id) WHERE event_type = 'reaction'
+		 DO UPDATE SET emoji = EXCLUDED.emoji`
+	}
+
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO activity_events (actor_id, event_type, card_id, item_id, bingo_count, emoji)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 `+conflict,
+		event.ActorID, string(event.Type), event.CardID, event.ItemID, event.BingoCount, event.Emoji,
+	)
+	if err != nil {
+		return fmt.Errorf("record activity: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the actor's event of the given type for an item, used when a
+// completion or reaction is undone.
+func (s *ActivityService) Remove(ctx context.Context, actorID uuid.UUID, eventType models.ActivityEventType, itemID uuid.UUID) error {
+	_, err := s.db.Exec(ctx,
+		"DELETE FROM activity_events WHERE actor_id = $1 AND event_type = $2 AND item_id = $3",
+		actorID, string(eventType), itemID,
+	)
+	if err != nil {
+		return fmt.Errorf("remove activity: %w", err)
+	}
+	return nil
+}
+
+// Feed returns the viewer's friends' activity, newest first, along with the
+// cursor for the next page (empty on the last page). Events on cards that
+// aren't shared with the viewer or are archived are left out, as is anything involving a
+// user the viewer has blocked or been blocked by. Reactions are shown only
+// when the viewer can see the card they were left on.
+func (s *ActivityService) Feed(ctx context.Context, viewerID uuid.UUID, params FeedParams) ([]models.FeedItem, string, error) {
+	limit := params.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	var beforeTime *time.Time
+	var beforeID *uuid.UUID
+	if params.Cursor != "" {
+		at, id, err := decodeFeedCursor(params.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		beforeTime, beforeID = &at, &id
+	}
+
+	rows, err := s.db.Query(ctx,
+		`WITH friends AS (
+		   SELECT CASE WHEN user_id = $1 THEN friend_id ELSE user_id END AS id
+		   FROM friendships
+		   WHERE status = 'accepted' AND (user_id = $1 OR friend_id = $1)
+		 )
+		 SELECT e.id, e.event_type, e.actor_id, au.username,
+		        c.id, c.user_id, ou.username, c.title, c.year,
+		        i.id, i.content, i.position,
+		        CASE WHEN e.event_type = 'item_completed' THEN i.notes END,
+		        e.bingo_count, e.emoji, e.created_at
+		 FROM activity_events e
+		 JOIN users au ON au.id = e.actor_id
+		 JOIN bingo_cards c ON c.id = e.card_id
+		 JOIN users ou ON ou.id = c.user_id
+		 LEFT JOIN bingo_items i ON i.id = e.item_id
+		 WHERE e.actor_id IN (SELECT id FROM friends)
+		   AND (c.user_id = $1 OR (
+		        `+cardVisibleToSQL("c", "$1")+` AND c.is_finalized AND NOT c.is_archived
+		        AND c.user_id IN (SELECT id FROM friends)
+		   ))
+		   AND (e.item_id IS NULL OR i.hidden_at IS NULL)
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks b
+		     WHERE (b.blocker_id = $1 AND b.blocked_id IN (e.actor_id, c.user_id))
+		        OR (b.blocked_id = $1 AND b.blocker_id IN (e.actor_id, c.user_id))
+		   )
+		   AND ($2::timestamptz IS NULL OR (e.created_at, e.id) < ($2, $3::uuid))
+		 ORDER BY e.created_at DESC, e.id DESC
+		 LIMIT $4`,
+		viewerID, beforeTime, beforeID, limit,
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("list activity: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.FeedItem{}
+	for rows.Next() {
+		var item models.FeedItem
+		var eventType string
+		if err := rows.Scan(
+			&item.ID, &eventType, &item.ActorID, &item.ActorUsername,
+			&item.CardID, &item.CardOwnerID, &item.CardOwnerUsername, &item.CardTitle, &item.CardYear,
+			&item.ItemID, &item.ItemContent, &item.ItemPosition, &item.Notes,
+			&item.BingoCount, &item.Emoji, &item.CreatedAt,
+		); err != nil {
+			return nil, "", fmt.Errorf("scan activity: %w", err)
+		}
+		item.Type = models.ActivityEventType(eventType)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("list activity: %w", err)
+	}
+
+	next := ""
+	if len(items) == limit {
+		last := items[len(items)-1]
+		next = encodeFeedCursor(last.CreatedAt, last.ID)
+	}
+	return items, next, nil
+}
+
+// Feed cursors pair the timestamp with the event ID so events created in the
+// same instant are neither skipped nor repeated across pages.
+func encodeFeedCursor(at time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(at.UTC().Format(time.RFC3339Nano) + "|" + id.String()))
+}
+
+func decodeFeedCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidFeedCursor
+	}
+	atPart, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidFeedCursor
+	}
+	at, err := time.Parse(time.RFC3339Nano, atPart)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidFeedCursor
+	}
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidFeedCursor
+	}
+	return at, id, nil
+}
+
+// recordActivity records a feed event on behalf of another service. Like
+// account events, failures are logged rather than failing the action.
+func recordActivity(ctx context.Context, recorder ActivityRecorder, event models.ActivityEvent) {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.Record(ctx, event); err != nil {
+		logging.FromContext(ctx).Warn("Failed to record activity", map[string]interface{}{
+			"error":    err.Error(),
+			"actor_id": event.ActorID.String(),
+			"type":     string(event.Type),
+		})
+	}
+}
+
+func removeActivity(ctx context.Context, recorder ActivityRecorder, actorID uuid.UUID, eventType models.ActivityEventType, itemID uuid.UUID) {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.Remove(ctx, actorID, eventType, itemID); err != nil {
+		logging.FromContext(ctx).Warn("Failed to remove activity", map[string]interface{}{
+			"error":    err.Error(),
+			"actor_id": actorID.String(),
+			"type":     string(eventType),
+		})
+	}
+}
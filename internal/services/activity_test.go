@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type fakeActivityRecorder struct {
+	recorded []models.ActivityEvent
+	removed  []models.ActivityEventType
+	err      error
+}
+
+func (f *fakeActivityRecorder) Record(ctx context.Context, event models.ActivityEvent) error {
+	f.recorded = append(f.recorded, event)
+	return f.err
+}
+
+func (f *fakeActivityRecorder) Remove(ctx context.Context, actorID uuid.UUID, eventType models.ActivityEventType, itemID uuid.UUID) error {
+	f.removed = append(f.removed, eventType)
+	return f.err
+}
+
+// finalizedCardDB serves a finalized 2x2 card whose first item is complete.
+func finalizedCardDB(userID, cardID uuid.UUID, visible bool) *fakeDB {
+	cardRow := []any{cardID, userID, 2024, nil, nil, 2, "BI", false, nil, true, true, visible, string(models.VisibilityFromFlag(visible)), false, time.Now(), time.Now(), int64(1)}
+	items := []models.BingoItem{
+		{ID: uuid.New(), CardID: cardID, Position: 0, Content: "A", IsCompleted: true},
+		{ID: uuid.New(), CardID: cardID, Position: 1, Content: "B"},
+		{ID: uuid.New(), CardID: cardID, Position: 2, Content: "C"},
+		{ID: uuid.New(), CardID: cardID, Position: 3, Content: "D"},
+	}
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
+			return rowFromValues(cardRow...)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			rows := make([][]any, 0, len(items))
+			for _, item := range items {
+				rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, item.DueDate, item.ReminderDaysBefore, item.HiddenAt, time.Now()})
+			}
+			return &fakeRows{rows: rows}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+}
+
+func TestCardService_CompleteItem_RecordsActivity(t *testing.T) {
+	userID, cardID := uuid.New(), uuid.New()
+	recorder := &fakeActivityRecorder{}
+	svc := NewCardService(finalizedCardDB(userID, cardID, false))
+	svc.SetActivityRecorder(recorder)
+
+	item, err := svc.CompleteItem(context.Background(), userID, cardID, 1, models.CompleteItemParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Hidden cards still record; the feed applies visibility when read.
+	if len(recorder.recorded) != 2 {
+		t.Fatalf("expected completion and bingo events, got %+v", recorder.recorded)
+	}
+	completed, bingo := recorder.recorded[0], recorder.recorded[1]
+	if completed.Type != models.ActivityItemCompleted || completed.ItemID == nil || *completed.ItemID != item.ID {
+		t.Fatalf("unexpected completion event: %+v", completed)
+	}
+	if bingo.Type != models.ActivityBingo || bingo.BingoCount == nil || *bingo.BingoCount != 1 {
+		t.Fatalf("unexpected bingo event: %+v", bingo)
+	}
+}
+
+func TestCardService_CompleteItem_NoBingoEventWithoutNewLine(t *testing.T) {
+	userID, cardID := uuid.New(), uuid.New()
+	recorder := &fakeActivityRecorder{}
+	svc := NewCardService(finalizedCardDB(userID, cardID, true))
+	svc.SetActivityRecorder(recorder)
+
+	// Completing the first item again doesn't finish a line.
+	if _, err := svc.CompleteItem(context.Background(), userID, cardID, 0, models.CompleteItemParams{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.recorded) != 1 || recorder.recorded[0].Type != models.ActivityItemCompleted {
+		t.Fatalf("expected only a completion event, got %+v", recorder.recorded)
+	}
+}
+
+func TestCardService_UncompleteItem_RemovesActivity(t *testing.T) {
+	userID, cardID := uuid.New(), uuid.New()
+	recorder := &fakeActivityRecorder{err: errors.New("db down")}
+	svc := NewCardService(finalizedCardDB(userID, cardID, true))
+	svc.SetActivityRecorder(recorder)
+
+	if _, err := svc.UncompleteItem(context.Background(), userID, cardID, 0); err != nil {
+		t.Fatalf("expected recorder failure to be logged only, got %v", err)
+	}
+	if len(recorder.removed) != 1 || recorder.removed[0] != models.ActivityItemCompleted {
+		t.Fatalf("expected completion removal, got %v", recorder.removed)
+	}
+}
+
+func TestActivityService_Record_ReactionUpsertsEmoji(t *testing.T) {
+	var statements []string
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			statements = append(statements, sql)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	svc := NewActivityService(db)
+	itemID := uuid.New()
+	emoji := "🎉"
+
+	if err := svc.Record(context.Background(), models.ActivityEvent{ActorID: uuid.New(), Type: models.ActivityReaction, CardID: uuid.New(), ItemID: &itemID, Emoji: &emoji}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.Record(context.Background(), models.ActivityEvent{ActorID: uuid.New(), Type: models.ActivityCardFinalized, CardID: uuid.New()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(statements[0], "DO UPDATE SET emoji") || !strings.Contains(statements[1], "ON CONFLICT DO NOTHING") {
+		t.Fatalf("unexpected statements: %v", statements)
+	}
+}
+
+func TestActivityService_Feed_Paginates(t *testing.T) {
+	viewerID := uuid.New()
+	at := time.Date(2025, 3, 1, 9, 30, 0, 123, time.UTC)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	title := "Goals"
+
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "user_blocks") || !strings.Contains(sql, "visible_to_friends") || !strings.Contains(sql, "is_archived") {
+				t.Fatalf("feed query must filter visibility, archive and blocks: %s", sql)
+			}
+			gotArgs = args
+			rows := [][]any{}
+			for _, id := range ids {
+				rows = append(rows, []any{
+					id, "card_finalized", uuid.New(), "friend",
+					uuid.New(), uuid.New(), "friend", &title, 2025,
+					(*uuid.UUID)(nil), (*string)(nil), (*int)(nil), (*string)(nil),
+					(*int)(nil), (*string)(nil), at,
+				})
+			}
+			return &fakeRows{rows: rows}, nil
+		},
+	}
+	svc := NewActivityService(db)
+
+	items, next, err := svc.Feed(context.Background(), viewerID, FeedParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].Type != models.ActivityCardFinalized || next == "" {
+		t.Fatalf("unexpected page: %+v next=%q", items, next)
+	}
+	if gotArgs[0] != viewerID || gotArgs[1].(*time.Time) != nil || gotArgs[3] != 2 {
+		t.Fatalf("unexpected first page args: %v", gotArgs)
+	}
+
+	if _, _, err := svc.Feed(context.Background(), viewerID, FeedParams{Limit: 2, Cursor: next}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if before := gotArgs[1].(*time.Time); before == nil || !before.Equal(at) || *gotArgs[2].(*uuid.UUID) != ids[1] {
+		t.Fatalf("expected cursor from last item, got %v", gotArgs)
+	}
+
+	if _, _, err := svc.Feed(context.Background(), viewerID, FeedParams{Cursor: "not-a-cursor"}); !errors.Is(err, ErrInvalidFeedCursor) {
+		t.Fatalf("expected invalid cursor error, got %v", err)
+	}
+}
+
+func TestReactionService_AddReaction_RecordsActivity(t *testing.T) {
+	cardID, itemID, reactorID := uuid.New(), uuid.New(), uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "INSERT INTO reactions") {
+				return rowFromValues(uuid.New(), itemID, reactorID, "🎉", time.Now())
+			}
+			return rowFromValues(uuid.New(), true, cardID, true)
+		},
+	}
+	recorder := &fakeActivityRecorder{}
+	svc := NewReactionService(db, &fakeFriendChecker{isFriend: true})
+	svc.SetActivityRecorder(recorder)
+
+	if _, err := svc.AddReaction(context.Background(), reactorID, itemID, "🎉"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.recorded) != 1 {
+		t.Fatalf("expected one event, got %+v", recorder.recorded)
+	}
+	event := recorder.recorded[0]
+	if event.Type != models.ActivityReaction || event.CardID != cardID || *event.ItemID != itemID || *event.Emoji != "🎉" {
+		t.Fatalf("unexpected reaction event: %+v", event)
+	}
+}
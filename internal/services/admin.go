@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrAdminCannotModifySelf = errors.New("admins cannot perform this action on their own account")
+)
+
+// AdminListParams controls pagination for admin list endpoints.
+type AdminListParams struct {
+	Limit  int
+	Offset int
+}
+
+// AdminUserSearchParams filters the admin user search.
+type AdminUserSearchParams struct {
+	Query string
+	AdminListParams
+}
+
+// AdminAILogParams filters the AI generation log listing.
+type AdminAILogParams struct {
+	UserID *uuid.UUID
+	AdminListParams
+}
+
+// AdminAuditLogParams filters the audit log listing.
+type AdminAuditLogParams struct {
+	TargetUserID *uuid.UUID
+	AdminListParams
+}
+
+type AdminService struct {
+	db       DB
+	sessions SessionRevoker
+	cards    CardLister
+}
+
+func NewAdminService(db DB, sessions SessionRevoker, cards CardLister) *AdminService {
+	return &AdminService{db: db, sessions: sessions, cards: cards}
+}
+
+func (p AdminListParams) normalized() (limit, offset int) {
+	limit = p.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	offset = p.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+const adminUserSummaryColumns = `u.id, u.email, u.username, u.email_verified, u.is_admin, u.disabled_at, u.ai_free_generations_used,
+		        (SELECT COUNT(*) FROM bingo_cards c WHERE c.user_id = u.id), u.created_at`
+
+func scanAdminUserSummary(row Row, u *models.AdminUserSummary) error {
+	return row.Scan(&u.ID, &u.Email, &u.Username, &u.EmailVerified, &u.IsAdmin, &u.DisabledAt, &u.AIFreeGenerationsUsed, &u.CardCount, &u.CreatedAt)
+}
+
+// SearchUsers finds users by email or username. Unlike friend search it
+// ignores the searchable flag.
+func (s *AdminService) SearchUsers(ctx context.Context, adminID uuid.UUID, params AdminUserSearchParams) ([]models.AdminUserSummary, error) {
+	limit, offset := params.normalized()
+	query := strings.TrimSpace(params.Query)
+
+	rows, err := s.db.Query(ctx,
+		`SELECT `+adminUserSummaryColumns+`
+		 FROM users u
+		 WHERE LOWER(u.email) LIKE $1 OR LOWER(u.username) LIKE $1
+		 ORDER BY u.created_at DESC
+		 LIMIT $2 OFFSET $3`,
+		"%"+strings.ToLower(query)+"%", limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.AdminUserSummary{}
+	for rows.Next() {
+		var u models.AdminUserSummary
+		if err := scanAdminUserSummary(rows, &u); err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search users: %w", err)
+	}
+
+	if err := recordAdminAudit(ctx, s.db, adminID, models.AdminActionSearchUsers, nil, map[string]any{
+		"query":  query,
+		"limit":  limit,
+		"offset": offset,
+	}); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetUser returns a single user's admin summary.
+func (s *AdminService) GetUser(ctx context.Context, adminID, userID uuid.UUID) (*models.AdminUserSummary, error) {
+	u := &models.AdminUserSummary{}
+	err := scanAdminUserSummary(s.db.QueryRow(ctx,
+		`SELECT `+adminUserSummaryColumns+` FROM users u WHERE u.id = $1`,
+		userID,
+	), u)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	if err := recordAdminAudit(ctx, s.db, adminID, models.AdminActionViewUser, &userID, nil); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// ListUserCards returns every card owned by a user, regardless of visibility.
+func (s *AdminService) ListUserCards(ctx context.Context, adminID, userID uuid.UUID) ([]*models.BingoCard, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	cards, err := s.cards.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user cards: %w", err)
+	}
+
+	if err := recordAdminAudit(ctx, s.db, adminID, models.AdminActionViewUserCards, &userID, map[string]any{
+		"card_count": len(cards),
+	}); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// SetDisabled disables or re-enables an account. Disabling also revokes all
+// of the user's sessions.
+func (s *AdminService) SetDisabled(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error {
+	if adminID == userID {
+		return ErrAdminCannotModifySelf
+	}
+
+	action := models.AdminActionEnableUser
+	query := `UPDATE users SET disabled_at = NULL, updated_at = NOW() WHERE id = $1`
+	if disabled {
+		action = models.AdminActionDisableUser
+		query = `UPDATE users SET disabled_at = COALESCE(disabled_at, NOW()), updated_at = NOW() WHERE id = $1`
+	}
+
+	err := inTx(ctx, s.db, func(tx Tx) error {
+		result, err := tx.Exec(ctx, query, userID)
+		if err != nil {
+			return fmt.Errorf("update disabled: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return recordAdminAudit(ctx, tx, adminID, action, &userID, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	if disabled {
+		if err := s.sessions.DeleteAllUserSessions(ctx, userID); err != nil {
+			return fmt.Errorf("revoke sessions: %w", err)
+		}
+	}
+	return nil
+}
+
+// ForceLogout revokes every session for a user.
+func (s *AdminService) ForceLogout(ctx context.Context, adminID, userID uuid.UUID) error {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.sessions.DeleteAllUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	return recordAdminAudit(ctx, s.db, adminID, models.AdminActionForceLogout, &userID, nil)
+}
+
+// ResetAIFreeGenerations sets a user's free AI generation counter back to zero.
+func (s *AdminService) ResetAIFreeGenerations(ctx context.Context, adminID, userID uuid.UUID) error {
+	return inTx(ctx, s.db, func(tx Tx) error {
+		var previous int
+		err := tx.QueryRow(ctx,
+			`UPDATE users u SET ai_free_generations_used = 0, updated_at = NOW()
+			 FROM (SELECT id, ai_free_generations_used FROM users WHERE id = $1 FOR UPDATE) prev
+			 WHERE u.id = prev.id
+			 RETURNING prev.ai_free_generations_used`,
+			userID,
+		).Scan(&previous)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("reset ai generations: %w", err)
+		}
+		return recordAdminAudit(ctx, tx, adminID, models.AdminActionResetAIGenerations, &userID, map[string]any{
+			"previous": previous,
+		})
+	})
+}
+
+// ListAIGenerationLogs returns AI generation logs, newest first.
+func (s *AdminService) ListAIGenerationLogs(ctx context.Context, adminID uuid.UUID, params AdminAILogParams) ([]models.AIGenerationLog, error) {
+	limit, offset := params.normalized()
+
+	rows, err := s.db.Query(ctx,
+		`SELECT l.id, l.user_id, u.username, l.model, l.tokens_input, l.tokens_output, l.duration_ms, l.status, l.created_at
+		 FROM ai_generation_logs l
+		 JOIN users u ON u.id = l.user_id
+		 WHERE $1::uuid IS NULL OR l.user_id = $1
+		 ORDER BY l.created_at DESC
+		 LIMIT $2 OFFSET $3`,
+		params.UserID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list ai generation logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []models.AIGenerationLog{}
+	for rows.Next() {
+		var l models.AIGenerationLog
+		if err := rows.Scan(&l.ID, &l.UserID, &l.Username, &l.Model, &l.TokensInput, &l.TokensOutput, &l.DurationMs, &l.Status, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan ai generation log: %w", err)
+		}
+		logs = append(logs, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list ai generation logs: %w", err)
+	}
+
+	if err := recordAdminAudit(ctx, s.db, adminID, models.AdminActionViewAILogs, params.UserID, map[string]any{
+		"limit":  limit,
+		"offset": offset,
+	}); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// ListAuditLog returns audit entries, newest first.
+func (s *AdminService) ListAuditLog(ctx context.Context, params AdminAuditLogParams) ([]models.AdminAuditEntry, error) {
+	limit, offset := params.normalized()
+
+	rows, err := s.db.Query(ctx,
+		`SELECT a.id, a.admin_user_id, au.username, a.action, a.target_user_id, tu.username, a.details, a.created_at
+		 FROM admin_audit_log a
+		 LEFT JOIN users au ON au.id = a.admin_user_id
+		 LEFT JOIN users tu ON tu.id = a.target_user_id
+		 WHERE $1::uuid IS NULL OR a.target_user_id = $1
+		 ORDER BY a.created_at DESC
+		 LIMIT $2 OFFSET $3`,
+		params.TargetUserID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AdminAuditEntry{}
+	for rows.Next() {
+		var e models.AdminAuditEntry
+		var action string
+		var details []byte
+		if err := rows.Scan(&e.ID, &e.AdminUserID, &e.AdminUsername, &action, &e.TargetUserID, &e.TargetUsername, &details, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		e.Action = models.AdminAction(action)
+		e.Details = map[string]any{}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return nil, fmt.Errorf("decode audit details: %w", err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// recordAdminAudit writes an admin_audit_log row. Callers treat a failure as a
+// failure of the action itself.
+func recordAdminAudit(ctx context.Context, conn DBConn, adminID uuid.UUID, action models.AdminAction, targetUserID *uuid.UUID, details map[string]any) error {
+	if details == nil {
+		details = map[string]any{}
+	}
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("encode audit details: %w", err)
+	}
+	_, err = conn.Exec(ctx,
+		`INSERT INTO admin_audit_log (admin_user_id, action, target_user_id, details)
+		 VALUES ($1, $2, $3, $4::jsonb)`,
+		adminID, string(action), targetUserID, string(payload),
+	)
+	if err != nil {
+		return fmt.Errorf("record audit: %w", err)
+	}
+	return nil
+}
+
+func (s *AdminService) ensureUserExists(ctx context.Context, userID uuid.UUID) error {
+	var exists bool
+	if err := s.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&exists); err != nil {
+		return fmt.Errorf("check user: %w", err)
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction, committing only if fn succeeds.
+func inTx(ctx context.Context, db DB, fn func(tx Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
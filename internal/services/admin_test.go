@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type fakeSessionRevoker struct {
+	revoked []uuid.UUID
+	err     error
+}
+
+func (f *fakeSessionRevoker) DeleteAllUserSessions(ctx context.Context, userID uuid.UUID) error {
+	f.revoked = append(f.revoked, userID)
+	return f.err
+}
+
+type fakeCardLister struct {
+	cards []*models.BingoCard
+	err   error
+}
+
+func (f *fakeCardLister) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
+	return f.cards, f.err
+}
+
+type auditCapture struct {
+	actions []string
+	targets []*uuid.UUID
+	details []map[string]any
+}
+
+func (a *auditCapture) exec(t *testing.T) func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+	return func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if !strings.Contains(sql, "INSERT INTO admin_audit_log") {
+			t.Fatalf("unexpected exec: %s", sql)
+		}
+		a.actions = append(a.actions, args[1].(string))
+		a.targets = append(a.targets, args[2].(*uuid.UUID))
+		var details map[string]any
+		if err := json.Unmarshal([]byte(args[3].(string)), &details); err != nil {
+			t.Fatalf("invalid audit details: %v", err)
+		}
+		a.details = append(a.details, details)
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+}
+
+func TestAdminService_SearchUsers_RecordsAudit(t *testing.T) {
+	audit := &auditCapture{}
+	userID := uuid.New()
+	now := time.Now()
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if args[0] != "%alice%" {
+				t.Fatalf("expected lower-cased pattern, got %v", args[0])
+			}
+			if args[1] != 50 || args[2] != 0 {
+				t.Fatalf("expected default pagination, got %v %v", args[1], args[2])
+			}
+			return &fakeRows{rows: [][]any{{userID, "alice@example.com", "alice", true, false, (*time.Time)(nil), 2, 3, now}}}, nil
+		},
+		ExecFunc: audit.exec(t),
+	}
+	svc := NewAdminService(db, &fakeSessionRevoker{}, &fakeCardLister{})
+
+	users, err := svc.SearchUsers(context.Background(), uuid.New(), AdminUserSearchParams{Query: " Alice "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 1 || users[0].Username != "alice" || users[0].CardCount != 3 {
+		t.Fatalf("unexpected users: %+v", users)
+	}
+	if len(audit.actions) != 1 || audit.actions[0] != string(models.AdminActionSearchUsers) {
+		t.Fatalf("expected search audit, got %v", audit.actions)
+	}
+	if audit.details[0]["query"] != "Alice" {
+		t.Fatalf("expected query in audit details, got %v", audit.details[0])
+	}
+}
+
+func TestAdminService_SearchUsers_AuditFailure(t *testing.T) {
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return nil, errors.New("audit down")
+		},
+	}
+	svc := NewAdminService(db, &fakeSessionRevoker{}, &fakeCardLister{})
+	if _, err := svc.SearchUsers(context.Background(), uuid.New(), AdminUserSearchParams{}); err == nil {
+		t.Fatal("expected audit failure to be returned")
+	}
+}
+
+func TestAdminService_GetUser_NotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	svc := NewAdminService(db, &fakeSessionRevoker{}, &fakeCardLister{})
+	if _, err := svc.GetUser(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestAdminService_ListUserCards(t *testing.T) {
+	audit := &auditCapture{}
+	targetID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(true)
+		},
+		ExecFunc: audit.exec(t),
+	}
+	cards := &fakeCardLister{cards: []*models.BingoCard{{ID: uuid.New()}, {ID: uuid.New()}}}
+	svc := NewAdminService(db, &fakeSessionRevoker{}, cards)
+
+	got, err := svc.ListUserCards(context.Background(), uuid.New(), targetID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 cards, got %d", len(got))
+	}
+	if audit.actions[0] != string(models.AdminActionViewUserCards) || *audit.targets[0] != targetID {
+		t.Fatalf("unexpected audit: %v %v", audit.actions, audit.targets)
+	}
+	if audit.details[0]["card_count"] != float64(2) {
+		t.Fatalf("expected card_count in details, got %v", audit.details[0])
+	}
+}
+
+func TestAdminService_SetDisabled_Self(t *testing.T) {
+	svc := NewAdminService(&fakeDB{}, &fakeSessionRevoker{}, &fakeCardLister{})
+	id := uuid.New()
+	if err := svc.SetDisabled(context.Background(), id, id, true); !errors.Is(err, ErrAdminCannotModifySelf) {
+		t.Fatalf("expected ErrAdminCannotModifySelf, got %v", err)
+	}
+}
+
+func TestAdminService_SetDisabled_RevokesSessions(t *testing.T) {
+	audit := &auditCapture{}
+	var committed bool
+	targetID := uuid.New()
+	auditExec := audit.exec(t)
+	tx := &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE users SET disabled_at") {
+				if !strings.Contains(sql, "NOW()") {
+					t.Fatalf("expected disable to set timestamp: %s", sql)
+				}
+				return fakeCommandTag{rowsAffected: 1}, nil
+			}
+			return auditExec(ctx, sql, args...)
+		},
+		CommitFunc: func(ctx context.Context) error {
+			committed = true
+			return nil
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	sessions := &fakeSessionRevoker{}
+	svc := NewAdminService(db, sessions, &fakeCardLister{})
+
+	if err := svc.SetDisabled(context.Background(), uuid.New(), targetID, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !committed {
+		t.Fatal("expected commit")
+	}
+	if len(audit.actions) != 1 || audit.actions[0] != string(models.AdminActionDisableUser) {
+		t.Fatalf("expected disable audit, got %v", audit.actions)
+	}
+	if len(sessions.revoked) != 1 || sessions.revoked[0] != targetID {
+		t.Fatalf("expected sessions revoked for target, got %v", sessions.revoked)
+	}
+}
+
+func TestAdminService_SetDisabled_EnableNotFound(t *testing.T) {
+	tx := &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "disabled_at = NULL") {
+				t.Fatalf("unexpected exec: %s", sql)
+			}
+			return fakeCommandTag{rowsAffected: 0}, nil
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	sessions := &fakeSessionRevoker{}
+	svc := NewAdminService(db, sessions, &fakeCardLister{})
+
+	if err := svc.SetDisabled(context.Background(), uuid.New(), uuid.New(), false); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if len(sessions.revoked) != 0 {
+		t.Fatal("enable should not revoke sessions")
+	}
+}
+
+func TestAdminService_ForceLogout(t *testing.T) {
+	audit := &auditCapture{}
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(true)
+		},
+		ExecFunc: audit.exec(t),
+	}
+	sessions := &fakeSessionRevoker{}
+	svc := NewAdminService(db, sessions, &fakeCardLister{})
+
+	targetID := uuid.New()
+	if err := svc.ForceLogout(context.Background(), uuid.New(), targetID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions.revoked) != 1 || audit.actions[0] != string(models.AdminActionForceLogout) {
+		t.Fatalf("expected revoke and audit, got %v %v", sessions.revoked, audit.actions)
+	}
+}
+
+func TestAdminService_ForceLogout_UserNotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(false)
+		},
+	}
+	sessions := &fakeSessionRevoker{}
+	svc := NewAdminService(db, sessions, &fakeCardLister{})
+	if err := svc.ForceLogout(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if len(sessions.revoked) != 0 {
+		t.Fatal("expected no revocation for missing user")
+	}
+}
+
+func TestAdminService_ResetAIFreeGenerations(t *testing.T) {
+	audit := &auditCapture{}
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "ai_free_generations_used = 0") {
+				t.Fatalf("unexpected query: %s", sql)
+			}
+			return rowFromValues(5)
+		},
+		ExecFunc: audit.exec(t),
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	svc := NewAdminService(db, &fakeSessionRevoker{}, &fakeCardLister{})
+
+	if err := svc.ResetAIFreeGenerations(context.Background(), uuid.New(), uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audit.actions[0] != string(models.AdminActionResetAIGenerations) || audit.details[0]["previous"] != float64(5) {
+		t.Fatalf("unexpected audit: %v %v", audit.actions, audit.details)
+	}
+}
+
+func TestAdminService_ListAuditLog(t *testing.T) {
+	adminID := uuid.New()
+	adminName := "root"
+	now := time.Now()
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{
+				{uuid.New(), &adminID, &adminName, "force_logout", (*uuid.UUID)(nil), (*string)(nil), []byte(`{"k":"v"}`), now},
+			}}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatal("reading the audit log should not write an audit entry")
+			return nil, nil
+		},
+	}
+	svc := NewAdminService(db, &fakeSessionRevoker{}, &fakeCardLister{})
+
+	entries, err := svc.ListAuditLog(context.Background(), AdminAuditLogParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != models.AdminActionForceLogout || entries[0].Details["k"] != "v" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
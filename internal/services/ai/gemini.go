@@ -115,7 +115,7 @@ func (s *Service) ConsumeUnverifiedFreeGeneration(ctx context.Context, userID uu
 		return 0, ErrEmailVerificationRequired
 	}
 	if err != nil {
-		logging.Error("Failed to increment AI free generation counter", map[string]interface{}{
+		logging.FromContext(ctx).Error("Failed to increment AI free generation counter", map[string]interface{}{
 			"error":   err.Error(),
 			"user_id": userID.String(),
 		})
@@ -142,7 +142,7 @@ func (s *Service) RefundUnverifiedFreeGeneration(ctx context.Context, userID uui
 		  AND ai_free_generations_used > 0
 	`, userID)
 	if err != nil {
-		logging.Error("Failed to refund AI free generation counter", map[string]interface{}{
+		logging.FromContext(ctx).Error("Failed to refund AI free generation counter", map[string]interface{}{
 			"error":   err.Error(),
 			"user_id": userID.String(),
 		})
@@ -265,7 +265,7 @@ func (s *Service) GenerateGoals(ctx context.Context, userID uuid.UUID, prompt Go
 	}
 
 	if strings.TrimSpace(s.apiKey) == "" {
-		logging.Warn("Gemini API key missing; AI generation unavailable", map[string]interface{}{
+		logging.FromContext(ctx).Warn("Gemini API key missing; AI generation unavailable", map[string]interface{}{
 			"user_id": userID.String(),
 		})
 		return nil, UsageStats{}, ErrAINotConfigured
@@ -366,7 +366,7 @@ Output exactly %d items as a JSON array of strings.`,
 	}
 
 	// Log request metadata only (avoid logging user-provided prompt/context)
-	logging.Info("Sending request to Gemini", map[string]interface{}{
+	logging.FromContext(ctx).Info("Sending request to Gemini", map[string]interface{}{
 		"user_id":         userID.String(),
 		"model":           s.model,
 		"thinking_level":  s.thinkingLevel,
@@ -374,7 +374,7 @@ Output exactly %d items as a JSON array of strings.`,
 		"prompt_length":   len(userMessage),
 	})
 	if s.debug && s.environment == "development" {
-		logging.Debug("Gemini prompt", map[string]interface{}{
+		logging.FromContext(ctx).Debug("Gemini prompt", map[string]interface{}{
 			"user_id":         userID.String(),
 			"model":           s.model,
 			"system_prompt":   truncateForLog(systemPrompt, s.debugMaxChars),
@@ -415,14 +415,14 @@ Output exactly %d items as a JSON array of strings.`,
 		// Best-effort include a small preview of the provider error for debugging.
 		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
 		if len(bodyBytes) > 0 {
-			logging.Error("Gemini non-200 response", map[string]interface{}{
+			logging.FromContext(ctx).Error("Gemini non-200 response", map[string]interface{}{
 				"user_id": userID.String(),
 				"status":  resp.StatusCode,
 				"body":    string(bodyBytes),
 			})
 		} else {
 			if dump, dumpErr := httputil.DumpResponse(resp, false); dumpErr == nil {
-				logging.Error("Gemini non-200 response (headers only)", map[string]interface{}{
+				logging.FromContext(ctx).Error("Gemini non-200 response (headers only)", map[string]interface{}{
 					"user_id": userID.String(),
 					"status":  resp.StatusCode,
 					"dump":    string(dump),
@@ -466,14 +466,14 @@ Output exactly %d items as a JSON array of strings.`,
 	}
 
 	responseText := candidate.Content.Parts[0].Text
-	logging.Info("Received response from Gemini", map[string]interface{}{
+	logging.FromContext(ctx).Info("Received response from Gemini", map[string]interface{}{
 		"user_id":         userID.String(),
 		"response_length": len(responseText),
 		"finish_reason":   candidate.FinishReason,
 		"tokens_total":    geminiResp.Usage.TotalTokenCount,
 	})
 	if s.debug && s.environment == "development" {
-		logging.Debug("Gemini response", map[string]interface{}{
+		logging.FromContext(ctx).Debug("Gemini response", map[string]interface{}{
 			"user_id":          userID.String(),
 			"model":            s.model,
 			"finish_reason":    candidate.FinishReason,
@@ -484,7 +484,7 @@ Output exactly %d items as a JSON array of strings.`,
 	// Strip markdown code block fences if present
 	cleanedResponseText := stripMarkdownCodeBlock(responseText)
 	if cleanedResponseText != responseText {
-		logging.Info("Stripped markdown code block from Gemini response", map[string]interface{}{
+		logging.FromContext(ctx).Info("Stripped markdown code block from Gemini response", map[string]interface{}{
 			"user_id":         userID.String(),
 			"original_length": len(responseText),
 			"cleaned_length":  len(cleanedResponseText),
@@ -495,7 +495,7 @@ Output exactly %d items as a JSON array of strings.`,
 	var goals []string
 	if err := json.Unmarshal([]byte(responseText), &goals); err != nil {
 		s.logUsageWithTimeout(userID, stats, "error")
-		logging.Error("Gemini returned invalid JSON for goals array", map[string]interface{}{
+		logging.FromContext(ctx).Error("Gemini returned invalid JSON for goals array", map[string]interface{}{
 			"user_id":          userID.String(),
 			"finish_reason":    candidate.FinishReason,
 			"response_preview": truncateForLog(responseText, 1024),
@@ -512,7 +512,7 @@ Output exactly %d items as a JSON array of strings.`,
 	}
 	if len(goals) != count {
 		s.logUsageWithTimeout(userID, stats, "error")
-		logging.Error("Gemini returned wrong goal count", map[string]interface{}{
+		logging.FromContext(ctx).Error("Gemini returned wrong goal count", map[string]interface{}{
 			"user_id":          userID.String(),
 			"finish_reason":    candidate.FinishReason,
 			"expected":         count,
@@ -555,7 +555,7 @@ func (s *Service) logUsage(ctx context.Context, userID uuid.UUID, stats UsageSta
     `, userID, stats.Model, stats.TokensInput, stats.TokensOutput, stats.Duration.Milliseconds(), status)
 
 	if err != nil {
-		logging.Error("Failed to log AI usage", map[string]interface{}{
+		logging.FromContext(ctx).Error("Failed to log AI usage", map[string]interface{}{
 			"error":   err.Error(),
 			"user_id": userID.String(),
 		})
@@ -65,7 +65,7 @@ func (s *Service) GenerateGuideGoals(ctx context.Context, userID uuid.UUID, prom
 	}
 
 	if strings.TrimSpace(s.apiKey) == "" {
-		logging.Warn("Gemini API key missing; AI guide unavailable", map[string]interface{}{
+		logging.FromContext(ctx).Warn("Gemini API key missing; AI guide unavailable", map[string]interface{}{
 			"user_id": userID.String(),
 		})
 		return nil, UsageStats{}, ErrAINotConfigured
@@ -179,7 +179,7 @@ Output exactly %d items as a JSON array of strings.`,
 		return nil, UsageStats{}, fmt.Errorf("%w: failed to marshal request", ErrAIProviderUnavailable)
 	}
 
-	logging.Info("Sending AI guide request to Gemini", map[string]interface{}{
+	logging.FromContext(ctx).Info("Sending AI guide request to Gemini", map[string]interface{}{
 		"user_id":        userID.String(),
 		"model":          s.model,
 		"guide_mode":     mode,
@@ -189,7 +189,7 @@ Output exactly %d items as a JSON array of strings.`,
 		"thinking_level": s.thinkingLevel,
 	})
 	if s.debug && s.environment == "development" {
-		logging.Debug("Gemini guide prompt", map[string]interface{}{
+		logging.FromContext(ctx).Debug("Gemini guide prompt", map[string]interface{}{
 			"user_id":         userID.String(),
 			"model":           s.model,
 			"system_prompt":   truncateForLog(systemPrompt, s.debugMaxChars),
@@ -228,14 +228,14 @@ Output exactly %d items as a JSON array of strings.`,
 
 		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
 		if len(bodyBytes) > 0 {
-			logging.Error("Gemini non-200 response (guide)", map[string]interface{}{
+			logging.FromContext(ctx).Error("Gemini non-200 response (guide)", map[string]interface{}{
 				"user_id": userID.String(),
 				"status":  resp.StatusCode,
 				"body":    string(bodyBytes),
 			})
 		} else {
 			if dump, dumpErr := httputil.DumpResponse(resp, false); dumpErr == nil {
-				logging.Error("Gemini non-200 response (guide, headers only)", map[string]interface{}{
+				logging.FromContext(ctx).Error("Gemini non-200 response (guide, headers only)", map[string]interface{}{
 					"user_id": userID.String(),
 					"status":  resp.StatusCode,
 					"dump":    string(dump),
@@ -277,14 +277,14 @@ Output exactly %d items as a JSON array of strings.`,
 	}
 
 	responseText := candidate.Content.Parts[0].Text
-	logging.Info("Received AI guide response from Gemini", map[string]interface{}{
+	logging.FromContext(ctx).Info("Received AI guide response from Gemini", map[string]interface{}{
 		"user_id":         userID.String(),
 		"response_length": len(responseText),
 		"finish_reason":   candidate.FinishReason,
 		"tokens_total":    geminiResp.Usage.TotalTokenCount,
 	})
 	if s.debug && s.environment == "development" {
-		logging.Debug("Gemini guide response", map[string]interface{}{
+		logging.FromContext(ctx).Debug("Gemini guide response", map[string]interface{}{
 			"user_id":          userID.String(),
 			"model":            s.model,
 			"finish_reason":    candidate.FinishReason,
@@ -294,7 +294,7 @@ Output exactly %d items as a JSON array of strings.`,
 
 	cleanedResponseText := stripMarkdownCodeBlock(responseText)
 	if cleanedResponseText != responseText {
-		logging.Info("Stripped markdown code block from Gemini guide response", map[string]interface{}{
+		logging.FromContext(ctx).Info("Stripped markdown code block from Gemini guide response", map[string]interface{}{
 			"user_id":         userID.String(),
 			"original_length": len(responseText),
 			"cleaned_length":  len(cleanedResponseText),
@@ -305,7 +305,7 @@ Output exactly %d items as a JSON array of strings.`,
 	var goals []string
 	if err := json.Unmarshal([]byte(responseText), &goals); err != nil {
 		s.logUsageWithTimeout(userID, stats, "error")
-		logging.Error("Gemini returned invalid JSON for guide goals array", map[string]interface{}{
+		logging.FromContext(ctx).Error("Gemini returned invalid JSON for guide goals array", map[string]interface{}{
 			"user_id":          userID.String(),
 			"finish_reason":    candidate.FinishReason,
 			"response_preview": truncateForLog(responseText, 1024),
@@ -325,7 +325,7 @@ Output exactly %d items as a JSON array of strings.`,
 	}
 	if len(goals) != count {
 		s.logUsageWithTimeout(userID, stats, "error")
-		logging.Error("Gemini returned wrong guide goal count", map[string]interface{}{
+		logging.FromContext(ctx).Error("Gemini returned wrong guide goal count", map[string]interface{}{
 			"user_id":          userID.String(),
 			"finish_reason":    candidate.FinishReason,
 			"expected":         count,
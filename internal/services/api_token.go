@@ -21,13 +21,20 @@ var (
 )
 
 type ApiTokenService struct {
-	db DBConn
+	db     DBConn
+	events AccountEventRecorder
 }
 
 func NewApiTokenService(db DBConn) *ApiTokenService {
 	return &ApiTokenService{db: db}
 }
 
+// SetAccountEvents enables recording token creation and deletion in the
+// user's account activity.
+func (s *ApiTokenService) SetAccountEvents(events AccountEventRecorder) {
+	s.events = events
+}
+
 func (s *ApiTokenService) Create(ctx context.Context, userID uuid.UUID, name string, scope models.ApiTokenScope, expiresInDays int) (*models.ApiToken, string, error) {
 	// Generate token: 32 random bytes
 	bytes := make([]byte, 32)
@@ -70,6 +77,13 @@ func (s *ApiTokenService) Create(ctx context.Context, userID uuid.UUID, name str
 		return nil, "", fmt.Errorf("inserting api token: %w", err)
 	}
 
+	recordAccountEvent(ctx, s.events, userID, models.AccountEventTokenCreated, map[string]any{
+		"token_id":     apiToken.ID.String(),
+		"name":         apiToken.Name,
+		"token_prefix": apiToken.TokenPrefix,
+		"scope":        string(apiToken.Scope),
+	})
+
 	return apiToken, plainToken, nil
 }
 
@@ -107,20 +121,38 @@ func (s *ApiTokenService) Delete(ctx context.Context, userID uuid.UUID, tokenID
 	if result.RowsAffected() == 0 {
 		return ErrTokenNotFound
 	}
+	recordAccountEvent(ctx, s.events, userID, models.AccountEventTokenDeleted, map[string]any{
+		"token_id": tokenID.String(),
+	})
 	return nil
 }
 
 func (s *ApiTokenService) DeleteAll(ctx context.Context, userID uuid.UUID) error {
-	_, err := s.db.Exec(ctx,
+	result, err := s.db.Exec(ctx,
 		"DELETE FROM api_tokens WHERE user_id = $1",
 		userID,
 	)
 	if err != nil {
 		return fmt.Errorf("deleting all api tokens: %w", err)
 	}
+	recordAccountEvent(ctx, s.events, userID, models.AccountEventTokensRevoked, map[string]any{
+		"count": result.RowsAffected(),
+	})
 	return nil
 }
 
+// PurgeExpired deletes API tokens past their expiry. It returns how many
+// were removed.
+func (s *ApiTokenService) PurgeExpired(ctx context.Context) (int, error) {
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM api_tokens WHERE expires_at IS NOT NULL AND expires_at <= NOW()",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("purging expired api tokens: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
 func (s *ApiTokenService) ValidateToken(ctx context.Context, plainToken string) (*models.ApiToken, error) {
 	// Hash token
 	hashBytes := sha256.Sum256([]byte(plainToken))
@@ -149,6 +181,8 @@ func (s *ApiTokenService) ValidateToken(ctx context.Context, plainToken string)
 }
 
 func (s *ApiTokenService) UpdateLastUsed(ctx context.Context, tokenID uuid.UUID) error {
-	_, err := s.db.Exec(ctx, "UPDATE api_tokens SET last_used_at = NOW() WHERE id = $1", tokenID)
+	// Nothing in the request reads this back, so it needn't pin the request's
+	// reads to the primary.
+	_, err := s.db.Exec(withoutReadAffinity(ctx), "UPDATE api_tokens SET last_used_at = NOW() WHERE id = $1", tokenID)
 	return err
 }
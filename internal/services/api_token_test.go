@@ -287,3 +287,20 @@ func TestApiTokenService_ValidateToken_Success(t *testing.T) {
 		t.Fatalf("expected user %v, got %v", userID, token.UserID)
 	}
 }
+
+func TestApiTokenService_PurgeExpired(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "expires_at IS NOT NULL AND expires_at <= NOW()") {
+				t.Fatalf("expected tokens without expiry to be kept: %s", sql)
+			}
+			return fakeCommandTag{rowsAffected: 2}, nil
+		},
+	}
+
+	service := NewApiTokenService(db)
+	count, err := service.PurgeExpired(context.Background())
+	if err != nil || count != 2 {
+		t.Fatalf("expected 2 purged, got %d %v", count, err)
+	}
+}
@@ -17,9 +17,9 @@ import (
 )
 
 const (
-	bcryptCost       = 12
-	sessionDuration  = 30 * 24 * time.Hour // 30 days
-	sessionKeyPrefix = "session:"
+	bcryptCost             = 12
+	defaultSessionDuration = 30 * 24 * time.Hour // 30 days
+	sessionKeyPrefix       = "session:"
 )
 
 var (
@@ -30,17 +30,24 @@ var (
 )
 
 type AuthService struct {
-	db    DBConn
-	redis RedisClient
+	db              DBConn
+	redis           RedisClient
+	sessionDuration time.Duration
 }
 
 func NewAuthService(db DBConn, redis RedisClient) *AuthService {
 	return &AuthService{
-		db:    db,
-		redis: redis,
+		db:              db,
+		redis:           redis,
+		sessionDuration: defaultSessionDuration,
 	}
 }
 
+// SetSessionDuration sets how long new and refreshed sessions last.
+func (s *AuthService) SetSessionDuration(d time.Duration) {
+	s.sessionDuration = d
+}
+
 func (s *AuthService) HashPassword(password string) (string, error) {
 	if len([]byte(password)) > 72 {
 		return "", ErrPasswordTooLong
@@ -81,11 +88,11 @@ func (s *AuthService) CreateSession(ctx context.Context, userID uuid.UUID) (toke
 		return "", err
 	}
 
-	expiresAt := time.Now().Add(sessionDuration)
+	expiresAt := time.Now().Add(s.sessionDuration)
 
 	// Store in Redis for fast lookups
 	redisKey := sessionKeyPrefix + tokenHash
-	err = s.redis.Set(ctx, redisKey, userID.String(), sessionDuration)
+	err = s.redis.Set(ctx, redisKey, userID.String(), s.sessionDuration)
 	if err != nil {
 		// Fall back to PostgreSQL if Redis fails
 		_, err = s.db.Exec(ctx,
@@ -108,7 +115,7 @@ func (s *AuthService) ValidateSession(ctx context.Context, token string) (*model
 	userIDStr, err := s.redis.Get(ctx, redisKey)
 	if err == nil {
 		// Found in Redis, extend session
-		_ = s.redis.Expire(ctx, redisKey, sessionDuration)
+		_ = s.redis.Expire(ctx, redisKey, s.sessionDuration)
 
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
@@ -189,13 +196,22 @@ func (s *AuthService) DeleteAllUserSessions(ctx context.Context, userID uuid.UUI
 	return nil
 }
 
+// PurgeExpiredSessions deletes sessions past their expiry. Their Redis keys
+// expire on their own. It returns how many were removed.
+func (s *AuthService) PurgeExpiredSessions(ctx context.Context) (int, error) {
+	result, err := s.db.Exec(ctx, "DELETE FROM sessions WHERE expires_at <= NOW()")
+	if err != nil {
+		return 0, fmt.Errorf("purging expired sessions: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
 func (s *AuthService) getUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	user := &models.User{}
-	err := s.db.QueryRow(ctx,
-		`SELECT id, email, password_hash, username, email_verified, email_verified_at, ai_free_generations_used, searchable, created_at, updated_at
-		 FROM users WHERE id = $1`,
+	err := scanUser(s.db.QueryRow(ctx,
+		`SELECT `+userColumns+` FROM users WHERE id = $1`,
 		id,
-	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Username, &user.EmailVerified, &user.EmailVerifiedAt, &user.AIFreeGenerationsUsed, &user.Searchable, &user.CreatedAt, &user.UpdatedAt)
+	), user)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrUserNotFound
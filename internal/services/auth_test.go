@@ -281,6 +281,11 @@ func TestAuthService_ValidateSession_RedisHit(t *testing.T) {
 				nil,
 				1,
 				true,
+				false,
+				false,
+				"en",
+				false,
+				nil,
 				now,
 				now,
 			)
@@ -479,6 +484,11 @@ func TestAuthService_ValidateSession_DBHit(t *testing.T) {
 				nil,
 				0,
 				true,
+				false,
+				false,
+				"en",
+				false,
+				nil,
 				now,
 				now,
 			)
@@ -518,3 +528,20 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+func TestAuthService_PurgeExpiredSessions(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "DELETE FROM sessions WHERE expires_at <= NOW()") {
+				t.Fatalf("unexpected sql: %s", sql)
+			}
+			return fakeCommandTag{rowsAffected: 4}, nil
+		},
+	}
+
+	service := NewAuthService(db, nil)
+	count, err := service.PurgeExpiredSessions(context.Background())
+	if err != nil || count != 4 {
+		t.Fatalf("expected 4 purged, got %d %v", count, err)
+	}
+}
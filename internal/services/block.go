@@ -20,13 +20,20 @@ var (
 )
 
 type BlockService struct {
-	db DB
+	db     DB
+	events AccountEventRecorder
 }
 
 func NewBlockService(db DB) *BlockService {
 	return &BlockService{db: db}
 }
 
+// SetAccountEvents enables recording blocks and unblocks in the blocker's
+// account activity.
+func (s *BlockService) SetAccountEvents(events AccountEventRecorder) {
+	s.events = events
+}
+
 func (s *BlockService) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
 	if blockerID == blockedID {
 		return ErrCannotBlockSelf
@@ -76,11 +83,18 @@ func (s *BlockService) Block(ctx context.Context, blockerID, blockedID uuid.UUID
 	if err != nil {
 		return fmt.Errorf("remove friendships: %w", err)
 	}
+	if err := removeGroupMemberships(ctx, tx, blockerID, blockedID); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit block: %w", err)
 	}
 	committed = true
+
+	recordAccountEvent(ctx, s.events, blockerID, models.AccountEventUserBlocked, map[string]any{
+		"blocked_user_id": blockedID.String(),
+	})
 	return nil
 }
 
@@ -95,6 +109,9 @@ func (s *BlockService) Unblock(ctx context.Context, blockerID, blockedID uuid.UU
 	if result.RowsAffected() == 0 {
 		return ErrBlockNotFound
 	}
+	recordAccountEvent(ctx, s.events, blockerID, models.AccountEventUserUnblocked, map[string]any{
+		"blocked_user_id": blockedID.String(),
+	})
 	return nil
 }
 
@@ -155,6 +155,11 @@ func TestBlockService_Block_Success(t *testing.T) {
 					t.Fatalf("unexpected delete sql: %q", sql)
 				}
 				return fakeCommandTag{rowsAffected: 1}, nil
+			case 3:
+				if !strings.Contains(sql, "DELETE FROM friend_group_members") {
+					t.Fatalf("unexpected group cleanup sql: %q", sql)
+				}
+				return fakeCommandTag{rowsAffected: 1}, nil
 			default:
 				t.Fatalf("unexpected exec call %d", execCalls)
 				return fakeCommandTag{}, nil
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/i18n"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarService manages each user's secret iCalendar (RFC 5545) feed of
+// goal due dates and completions. Feed tokens are separate from API tokens:
+// they only grant read access to the feed and can't call the API.
+type CalendarService struct {
+	db      DB
+	baseURL string
+	events  AccountEventRecorder
+}
+
+func NewCalendarService(db DB, baseURL string) *CalendarService {
+	return &CalendarService{db: db, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// SetAccountEvents enables recording feed creation and revocation in the
+// user's account activity.
+func (s *CalendarService) SetAccountEvents(events AccountEventRecorder) {
+	s.events = events
+}
+
+// GetFeed returns the user's feed, or nil if they don't have one.
+func (s *CalendarService) GetFeed(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error) {
+	feed := &models.CalendarFeed{}
+	err := s.db.QueryRow(ctx,
+		"SELECT created_at, last_used_at FROM calendar_feeds WHERE user_id = $1",
+		userID,
+	).Scan(&feed.CreatedAt, &feed.LastUsedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// CreateFeed gives the user a new feed URL. Any previous URL stops working.
+func (s *CalendarService) CreateFeed(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error) {
+	token, err := generateInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &models.CalendarFeed{}
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO calendar_feeds (user_id, token_hash)
+		 VALUES ($1, $2)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET token_hash = EXCLUDED.token_hash, created_at = NOW(), last_used_at = NULL
+		 RETURNING created_at, last_used_at`,
+		userID, hashInviteToken(token),
+	).Scan(&feed.CreatedAt, &feed.LastUsedAt)
+	if err != nil {
+		return nil, fmt.Errorf("creating calendar feed: %w", err)
+	}
+	feed.URL = s.baseURL + "/api/calendar/feed.ics?token=" + url.QueryEscape(token)
+
+	recordAccountEvent(ctx, s.events, userID, models.AccountEventCalendarFeedCreated, nil)
+	return feed, nil
+}
+
+func (s *CalendarService) RevokeFeed(ctx context.Context, userID uuid.UUID) error {
+	result, err := s.db.Exec(ctx, "DELETE FROM calendar_feeds WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("revoking calendar feed: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrCalendarFeedNotFound
+	}
+
+	recordAccountEvent(ctx, s.events, userID, models.AccountEventCalendarFeedRevoked, nil)
+	return nil
+}
+
+// calendarEntry is an item that appears in the feed.
+type calendarEntry struct {
+	ItemID             uuid.UUID
+	CardID             uuid.UUID
+	Content            string
+	IsCompleted        bool
+	CompletedAt        *time.Time
+	DueDate            *string
+	ReminderDaysBefore *int
+	CardTitle          *string
+	CardYear           int
+}
+
+// Render builds the feed for token. Completed items become timed events at
+// the moment they were completed; open items with a due date become
+// all-day events on that date, with an alarm when a reminder is set. Items
+// on archived cards and items hidden by a moderator are left out.
+func (s *CalendarService) Render(ctx context.Context, token string, now time.Time) ([]byte, error) {
+	if token == "" {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	var userID uuid.UUID
+	var locale string
+	err := s.db.QueryRow(ctx,
+		`UPDATE calendar_feeds f SET last_used_at = NOW()
+		 FROM users u
+		 WHERE f.token_hash = $1 AND u.id = f.user_id AND u.disabled_at IS NULL
+		 RETURNING f.user_id, u.locale`,
+		hashInviteToken(token),
+	).Scan(&userID, &locale)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up calendar feed: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT i.id, i.card_id, i.content, i.is_completed, i.completed_at,
+		        to_char(i.due_date, 'YYYY-MM-DD'), i.reminder_days_before, c.title, c.year
+		 FROM bingo_items i
+		 JOIN bingo_cards c ON c.id = i.card_id
+		 WHERE c.user_id = $1 AND NOT c.is_archived AND i.hidden_at IS NULL
+		   AND ((i.is_completed AND i.completed_at IS NOT NULL) OR i.due_date IS NOT NULL)
+		 ORDER BY c.year, c.created_at, i.position`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing calendar items: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []calendarEntry
+	for rows.Next() {
+		var e calendarEntry
+		if err := rows.Scan(&e.ItemID, &e.CardID, &e.Content, &e.IsCompleted, &e.CompletedAt,
+			&e.DueDate, &e.ReminderDaysBefore, &e.CardTitle, &e.CardYear); err != nil {
+			return nil, fmt.Errorf("scanning calendar item: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating calendar items: %w", err)
+	}
+
+	return s.buildCalendar(locale, entries, now), nil
+}
+
+func (s *CalendarService) buildCalendar(locale string, entries []calendarEntry, now time.Time) []byte {
+	host := "yearofbingo"
+	if parsed, err := url.Parse(s.baseURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	var w icalWriter
+	w.prop("BEGIN", "VCALENDAR")
+	w.prop("VERSION", "2.0")
+	w.prop("PRODID", "-//Year of Bingo//Goal Calendar//EN")
+	w.prop("CALSCALE", "GREGORIAN")
+	w.prop("METHOD", "PUBLISH")
+	w.prop("X-WR-CALNAME", icalText(i18n.T(locale, "calendar.name")))
+
+	for _, e := range entries {
+		cardName := cardDisplayName(locale, e.CardTitle, &e.CardYear)
+		description := icalText(i18n.T(locale, "calendar.description", cardName))
+
+		if e.IsCompleted && e.CompletedAt != nil {
+			w.prop("BEGIN", "VEVENT")
+			w.prop("UID", e.ItemID.String()+"-completed@"+host)
+			w.prop("DTSTAMP", stamp)
+			w.prop("DTSTART", e.CompletedAt.UTC().Format("20060102T150405Z"))
+			w.prop("SUMMARY", icalText(i18n.T(locale, "calendar.completed_summary", e.Content)))
+			w.prop("DESCRIPTION", description)
+			s.cardURL(&w, e.CardID)
+			w.prop("TRANSP", "TRANSPARENT")
+			w.prop("END", "VEVENT")
+			continue
+		}
+		if e.DueDate == nil {
+			continue
+		}
+		due, err := time.Parse("2006-01-02", *e.DueDate)
+		if err != nil {
+			continue
+		}
+
+		summary := icalText(i18n.T(locale, "calendar.due_summary", e.Content))
+		w.prop("BEGIN", "VEVENT")
+		w.prop("UID", e.ItemID.String()+"-due@"+host)
+		w.prop("DTSTAMP", stamp)
+		w.prop("DTSTART;VALUE=DATE", due.Format("20060102"))
+		w.prop("DTEND;VALUE=DATE", due.AddDate(0, 0, 1).Format("20060102"))
+		w.prop("SUMMARY", summary)
+		w.prop("DESCRIPTION", description)
+		s.cardURL(&w, e.CardID)
+		w.prop("TRANSP", "TRANSPARENT")
+		if e.ReminderDaysBefore != nil {
+			trigger := "PT0S"
+			if *e.ReminderDaysBefore > 0 {
+				trigger = fmt.Sprintf("-P%dD", *e.ReminderDaysBefore)
+			}
+			w.prop("BEGIN", "VALARM")
+			w.prop("ACTION", "DISPLAY")
+			w.prop("DESCRIPTION", summary)
+			w.prop("TRIGGER", trigger)
+			w.prop("END", "VALARM")
+		}
+		w.prop("END", "VEVENT")
+	}
+
+	w.prop("END", "VCALENDAR")
+	return []byte(w.b.String())
+}
+
+func (s *CalendarService) cardURL(w *icalWriter, cardID uuid.UUID) {
+	if s.baseURL == "" {
+		return
+	}
+	w.prop("URL", s.baseURL+"/#card/"+cardID.String())
+}
+
+// icalWriter writes content lines, folding them at 75 octets without
+// splitting UTF-8 sequences and ending each with CRLF.
+type icalWriter struct {
+	b strings.Builder
+}
+
+func (w *icalWriter) prop(name, value string) {
+	line := name + ":" + value
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit.
+		limit = 74
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+// icalText escapes a TEXT value.
+func icalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
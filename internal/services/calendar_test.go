@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestCalendarService_CreateFeed_StoresHashAndReturnsURL(t *testing.T) {
+	userID := uuid.New()
+	var storedHash string
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "INSERT INTO calendar_feeds") {
+				t.Fatalf("unexpected query: %s", sql)
+			}
+			storedHash = args[1].(string)
+			return rowFromValues(time.Now(), (*time.Time)(nil))
+		},
+	}
+	events := &fakeAccountEventRecorder{}
+
+	svc := NewCalendarService(db, "https://example.com/")
+	svc.SetAccountEvents(events)
+	feed, err := svc.CreateFeed(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prefix := "https://example.com/api/calendar/feed.ics?token="
+	if !strings.HasPrefix(feed.URL, prefix) {
+		t.Fatalf("unexpected feed URL %q", feed.URL)
+	}
+	token := strings.TrimPrefix(feed.URL, prefix)
+	if storedHash != hashInviteToken(token) {
+		t.Fatal("expected the token hash, not the token, to be stored")
+	}
+	if len(events.events) != 1 || events.events[0] != models.AccountEventCalendarFeedCreated {
+		t.Fatalf("expected calendar_feed_created event, got %v", events.events)
+	}
+}
+
+func TestCalendarService_RevokeFeed_NotFound(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 0}, nil
+		},
+	}
+
+	svc := NewCalendarService(db, "")
+	err := svc.RevokeFeed(context.Background(), uuid.New())
+	if !errors.Is(err, ErrCalendarFeedNotFound) {
+		t.Fatalf("expected ErrCalendarFeedNotFound, got %v", err)
+	}
+}
+
+func TestCalendarService_Render_UnknownToken(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error {
+				return pgx.ErrNoRows
+			}}
+		},
+	}
+
+	svc := NewCalendarService(db, "")
+	for _, token := range []string{"", "wrong"} {
+		if _, err := svc.Render(context.Background(), token, time.Now()); !errors.Is(err, ErrCalendarFeedNotFound) {
+			t.Fatalf("token %q: expected ErrCalendarFeedNotFound, got %v", token, err)
+		}
+	}
+}
+
+func TestCalendarService_Render_Events(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	completedID := uuid.New()
+	dueID := uuid.New()
+	completedAt := time.Date(2025, 4, 2, 15, 30, 0, 0, time.UTC)
+	due := "2025-12-31"
+	reminder := 7
+	title := "Fitness, health; etc"
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(userID, "en")
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if args[0] != userID {
+				t.Fatalf("expected items for feed owner, got %v", args[0])
+			}
+			return &fakeRows{rows: [][]any{
+				{completedID, cardID, "Run a 5k", true, &completedAt, nil, nil, &title, 2025},
+				{dueID, cardID, "Read 12 books", false, nil, &due, &reminder, &title, 2025},
+			}}, nil
+		},
+	}
+
+	svc := NewCalendarService(db, "https://example.com")
+	body, err := svc.Render(context.Background(), "token", time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ics := string(body)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:" + completedID.String() + "-completed@example.com\r\n",
+		"DTSTART:20250402T153000Z\r\n",
+		"SUMMARY:Completed: Run a 5k\r\n",
+		`DESCRIPTION:From Fitness\, health\; etc` + "\r\n",
+		"UID:" + dueID.String() + "-due@example.com\r\n",
+		"DTSTART;VALUE=DATE:20251231\r\n",
+		"DTEND;VALUE=DATE:20260101\r\n",
+		"TRIGGER:-P7D\r\n",
+		"URL:https://example.com/#card/" + cardID.String() + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected feed to contain %q\n%s", want, ics)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 2 {
+		t.Errorf("expected 2 events, got %d", strings.Count(ics, "BEGIN:VEVENT"))
+	}
+}
+
+func TestICalWriter_FoldsLongLines(t *testing.T) {
+	var w icalWriter
+	w.prop("SUMMARY", strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(w.b.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected folded output, got %d line(s)", len(lines))
+	}
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets", i, len(line))
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %d doesn't start with a space", i)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a UTF-8 sequence", i)
+		}
+	}
+}
@@ -32,11 +32,19 @@ var (
 	ErrInvalidGridSize   = errors.New("invalid grid size")
 	ErrInvalidHeaderText = errors.New("invalid header text")
 	ErrNoSpaceForFree    = errors.New("no space available for free space")
+	ErrInvalidVisibility = errors.New("invalid visibility")
+	ErrInvalidDueDate    = errors.New("invalid due date")
+	ErrInvalidReminder   = errors.New("invalid reminder")
 )
 
 type CardService struct {
 	db                  DB
 	notificationService NotificationServiceInterface
+	activity            ActivityRecorder
+
+	// Set on services bound to a card edit's transaction; see editCard.
+	committed *CardService
+	pending   *[]func()
 }
 
 func NewCardService(db DB) *CardService {
@@ -47,6 +55,12 @@ func (s *CardService) SetNotificationService(notificationService NotificationSer
 	s.notificationService = notificationService
 }
 
+// SetActivityRecorder enables writing completions, bingos and finalized cards
+// to the friend activity feed.
+func (s *CardService) SetActivityRecorder(activity ActivityRecorder) {
+	s.activity = activity
+}
+
 func (s *CardService) Create(ctx context.Context, params models.CreateCardParams) (*models.BingoCard, error) {
 	// Validate category if provided
 	if params.Category != nil && *params.Category != "" {
@@ -113,12 +127,12 @@ func (s *CardService) Create(ctx context.Context, params models.CreateCardParams
 	err := s.db.QueryRow(ctx,
 		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		 RETURNING id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at`,
+		 RETURNING id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version`,
 		params.UserID, params.Year, params.Category, params.Title, params.GridSize, params.Header, params.HasFree, freePos,
 	).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.Visibility, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.Version,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("creating card: %w", err)
@@ -132,13 +146,13 @@ func (s *CardService) GetByID(ctx context.Context, cardID uuid.UUID) (*models.Bi
 	card := &models.BingoCard{}
 	err := s.db.QueryRow(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		        is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version
 		 FROM bingo_cards WHERE id = $1`,
 		cardID,
 	).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.Visibility, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrCardNotFound
@@ -160,13 +174,13 @@ func (s *CardService) GetByUserAndYear(ctx context.Context, userID uuid.UUID, ye
 	card := &models.BingoCard{}
 	err := s.db.QueryRow(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		        is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version
 		 FROM bingo_cards WHERE user_id = $1 AND year = $2`,
 		userID, year,
 	).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.Visibility, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrCardNotFound
@@ -187,7 +201,7 @@ func (s *CardService) GetByUserAndYear(ctx context.Context, userID uuid.UUID, ye
 func (s *CardService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		        is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version
 		 FROM bingo_cards WHERE user_id = $1 ORDER BY year DESC, created_at DESC`,
 		userID,
 	)
@@ -202,7 +216,7 @@ func (s *CardService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*mode
 		if err := rows.Scan(
 			&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 			&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.Visibility, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.Version,
 		); err != nil {
 			return nil, fmt.Errorf("scanning card: %w", err)
 		}
@@ -221,7 +235,7 @@ func (s *CardService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*mode
 	return cards, nil
 }
 
-func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params models.AddItemParams) (*models.BingoItem, error) {
+func (s *CardService) addItem(ctx context.Context, userID uuid.UUID, params models.AddItemParams) (*models.BingoItem, error) {
 	if params.Position == nil {
 		// Choose a random available position atomically (important for small grids + concurrent adds).
 		tx, err := s.db.Begin(ctx)
@@ -307,6 +321,11 @@ func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params mode
 		if err := tx.Commit(ctx); err != nil {
 			return nil, fmt.Errorf("committing transaction: %w", err)
 		}
+		if after, err := s.GetByID(ctx, params.CardID); err != nil {
+			logRevisionError(ctx, err, params.CardID, models.RevisionAddItem)
+		} else {
+			s.recordRevision(ctx, models.RevisionAddItem, withoutItem(after, item.ID), after)
+		}
 		return item, nil
 	}
 
@@ -350,10 +369,11 @@ func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params mode
 		return nil, fmt.Errorf("adding item: %w", err)
 	}
 
+	s.recordRevision(ctx, models.RevisionAddItem, card, nil)
 	return item, nil
 }
 
-func (s *CardService) UpdateItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error) {
+func (s *CardService) updateItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error) {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -412,10 +432,11 @@ func (s *CardService) UpdateItem(ctx context.Context, userID, cardID uuid.UUID,
 		item.Position = newPos
 	}
 
+	s.recordRevision(ctx, models.RevisionUpdateItem, card, nil)
 	return item, nil
 }
 
-func (s *CardService) SwapItems(ctx context.Context, userID, cardID uuid.UUID, pos1, pos2 int) error {
+func (s *CardService) swapItems(ctx context.Context, userID, cardID uuid.UUID, pos1, pos2 int) error {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -438,7 +459,11 @@ func (s *CardService) SwapItems(ctx context.Context, userID, cardID uuid.UUID, p
 
 	// If this swap involves the FREE cell, move FREE (draft-only).
 	if card.HasFreePositionSet() && (pos1 == *card.FreeSpacePos || pos2 == *card.FreeSpacePos) {
-		return s.moveFreeSpace(ctx, card, pos1, pos2)
+		if err := s.moveFreeSpace(ctx, card, pos1, pos2); err != nil {
+			return err
+		}
+		s.recordRevision(ctx, models.RevisionSwapItems, card, nil)
+		return nil
 	}
 	if !card.IsValidItemPosition(pos1) || !card.IsValidItemPosition(pos2) {
 		return ErrInvalidPosition
@@ -505,6 +530,7 @@ func (s *CardService) SwapItems(ctx context.Context, userID, cardID uuid.UUID, p
 		return fmt.Errorf("committing transaction: %w", err)
 	}
 
+	s.recordRevision(ctx, models.RevisionSwapItems, card, nil)
 	return nil
 }
 
@@ -581,7 +607,7 @@ func (s *CardService) moveFreeSpace(ctx context.Context, card *models.BingoCard,
 	return nil
 }
 
-func (s *CardService) RemoveItem(ctx context.Context, userID, cardID uuid.UUID, position int) error {
+func (s *CardService) removeItem(ctx context.Context, userID, cardID uuid.UUID, position int) error {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -605,10 +631,11 @@ func (s *CardService) RemoveItem(ctx context.Context, userID, cardID uuid.UUID,
 		return ErrItemNotFound
 	}
 
+	s.recordRevision(ctx, models.RevisionRemoveItem, card, nil)
 	return nil
 }
 
-func (s *CardService) Delete(ctx context.Context, userID, cardID uuid.UUID) error {
+func (s *CardService) delete(ctx context.Context, userID, cardID uuid.UUID) error {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -618,26 +645,19 @@ func (s *CardService) Delete(ctx context.Context, userID, cardID uuid.UUID) erro
 		return ErrNotCardOwner
 	}
 
-	// Delete items first (foreign key constraint)
-	_, err = s.db.Exec(ctx, "DELETE FROM bingo_items WHERE card_id = $1", cardID)
+	// Move the card to the trash; it can be restored until it is purged
+	count, err := s.trashCards(ctx, userID, []uuid.UUID{cardID})
 	if err != nil {
-		return fmt.Errorf("deleting card items: %w", err)
-	}
-
-	// Delete the card
-	result, err := s.db.Exec(ctx, "DELETE FROM bingo_cards WHERE id = $1", cardID)
-	if err != nil {
-		return fmt.Errorf("deleting card: %w", err)
+		return err
 	}
-	if result.RowsAffected() == 0 {
+	if count == 0 {
 		return ErrCardNotFound
 	}
 
 	return nil
 }
 
-// UpdateMeta updates the category and/or title of a card
-func (s *CardService) UpdateMeta(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error) {
+func (s *CardService) updateMeta(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error) {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -686,10 +706,15 @@ func (s *CardService) UpdateMeta(ctx context.Context, userID, cardID uuid.UUID,
 	}
 
 	// Return updated card
-	return s.GetByID(ctx, cardID)
+	updated, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordRevision(ctx, models.RevisionUpdateMeta, card, updated)
+	return updated, nil
 }
 
-func (s *CardService) Shuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+func (s *CardService) shuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -753,7 +778,12 @@ func (s *CardService) Shuffle(ctx context.Context, userID, cardID uuid.UUID) (*m
 	}
 
 	// Reload card with updated positions
-	return s.GetByID(ctx, cardID)
+	shuffled, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordRevision(ctx, models.RevisionShuffle, card, shuffled)
+	return shuffled, nil
 }
 
 // FinalizeParams contains optional parameters for finalizing a card
@@ -761,7 +791,7 @@ type FinalizeParams struct {
 	VisibleToFriends *bool // Optional; if nil, keeps current value (default true for new cards)
 }
 
-func (s *CardService) Finalize(ctx context.Context, userID, cardID uuid.UUID, params *FinalizeParams) (*models.BingoCard, error) {
+func (s *CardService) finalize(ctx context.Context, userID, cardID uuid.UUID, params *FinalizeParams) (*models.BingoCard, error) {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -780,29 +810,40 @@ func (s *CardService) Finalize(ctx context.Context, userID, cardID uuid.UUID, pa
 	}
 
 	// Determine visibility setting
-	visibleToFriends := card.VisibleToFriends // Keep current value by default
+	visibility := card.Visibility // Keep current value by default
 	if params != nil && params.VisibleToFriends != nil {
-		visibleToFriends = *params.VisibleToFriends
+		visibility = models.VisibilityFromFlag(*params.VisibleToFriends)
 	}
 
-	_, err = s.db.Exec(ctx,
-		"UPDATE bingo_cards SET is_finalized = true, visible_to_friends = $2 WHERE id = $1",
-		cardID, visibleToFriends,
-	)
+	_, err = s.db.Exec(ctx, "UPDATE bingo_cards SET is_finalized = true WHERE id = $1", cardID)
 	if err != nil {
 		return nil, fmt.Errorf("finalizing card: %w", err)
 	}
+	// A changed visibility goes through applyVisibility so a card leaving
+	// "groups" or "link" loses its group shares and share token.
+	if visibility != card.Visibility {
+		if _, err := applyVisibility(ctx, s.db, userID, []uuid.UUID{cardID}, visibility, []uuid.UUID{}, false); err != nil {
+			return nil, err
+		}
+	}
 
 	card.IsFinalized = true
-	card.VisibleToFriends = visibleToFriends
-	if card.VisibleToFriends {
+	card.Visibility = visibility
+	card.VisibleToFriends = visibility.VisibleToAllFriends()
+	s.afterCommit(func(s *CardService) {
+		recordActivity(ctx, s.activity, models.ActivityEvent{ActorID: userID, Type: models.ActivityCardFinalized, CardID: cardID})
+	})
+	if card.Visibility.SharedWithFriends() {
 		s.notifyFriendsNewCard(ctx, userID, cardID)
 	}
 	return card, nil
 }
 
-// UpdateVisibility updates the visibility of a card to friends
-func (s *CardService) UpdateVisibility(ctx context.Context, userID, cardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error) {
+func (s *CardService) updateVisibility(ctx context.Context, userID, cardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error) {
+	if err := validateVisibilityParams(params); err != nil {
+		return nil, err
+	}
+
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -812,17 +853,20 @@ func (s *CardService) UpdateVisibility(ctx context.Context, userID, cardID uuid.
 		return nil, ErrNotCardOwner
 	}
 
-	wasVisible := card.VisibleToFriends
-	_, err = s.db.Exec(ctx,
-		"UPDATE bingo_cards SET visible_to_friends = $2, updated_at = NOW() WHERE id = $1",
-		cardID, visibleToFriends,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("updating visibility: %w", err)
+	previous := card.Visibility
+	beforeSnap, snapErr := s.snapshotOf(ctx, card)
+	if _, err := s.setVisibility(ctx, userID, []uuid.UUID{cardID}, params); err != nil {
+		return nil, err
+	}
+	if snapErr != nil {
+		logRevisionError(ctx, snapErr, cardID, models.RevisionVisibility)
+	} else {
+		s.recordRevisionFrom(ctx, models.RevisionVisibility, card, beforeSnap, nil)
 	}
 
-	card.VisibleToFriends = visibleToFriends
-	if card.IsFinalized && !wasVisible && visibleToFriends {
+	card.Visibility = params.Visibility
+	card.VisibleToFriends = params.Visibility.VisibleToAllFriends()
+	if card.IsFinalized && card.Visibility.SharedWithFriends() && (previous != card.Visibility || card.Visibility == models.VisibilityGroups) {
 		s.notifyFriendsNewCard(ctx, userID, cardID)
 	}
 	return card, nil
@@ -830,17 +874,23 @@ func (s *CardService) UpdateVisibility(ctx context.Context, userID, cardID uuid.
 
 // BulkUpdateVisibility updates the visibility of multiple cards owned by the user
 // Returns the count of cards updated (cards not owned by user are silently skipped)
-func (s *CardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error) {
+func (s *CardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, params models.CardVisibilityParams) (int, error) {
 	if len(cardIDs) == 0 {
 		return 0, nil
 	}
+	if err := validateVisibilityParams(params); err != nil {
+		return 0, err
+	}
 
+	// Friends newly able to see a finalized card are told about it. Group
+	// changes always notify; recipients who were already told are skipped.
 	var notifyCardIDs []uuid.UUID
-	if visibleToFriends {
+	if params.Visibility.SharedWithFriends() {
 		rows, err := s.db.Query(ctx,
 			`SELECT id FROM bingo_cards
-			 WHERE id = ANY($1) AND user_id = $2 AND is_finalized = true AND visible_to_friends = false`,
-			cardIDs, userID,
+			 WHERE id = ANY($1) AND user_id = $2 AND is_finalized = true
+			   AND (visibility <> $3 OR $3 = 'groups')`,
+			cardIDs, userID, string(params.Visibility),
 		)
 		if err != nil {
 			return 0, fmt.Errorf("fetching visibility changes: %w", err)
@@ -856,52 +906,174 @@ func (s *CardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID
 		rows.Close()
 	}
 
-	result, err := s.db.Exec(ctx,
-		`UPDATE bingo_cards SET visible_to_friends = $1, updated_at = NOW()
-		 WHERE id = ANY($2) AND user_id = $3`,
-		visibleToFriends, cardIDs, userID,
-	)
+	befores := s.snapshotCards(ctx, models.RevisionVisibility, userID, cardIDs)
+	count, err := s.setVisibility(ctx, userID, cardIDs, params)
 	if err != nil {
-		return 0, fmt.Errorf("bulk updating visibility: %w", err)
+		return 0, err
+	}
+	for _, b := range befores {
+		s.recordRevisionFrom(ctx, models.RevisionVisibility, b.card, b.snap, nil)
+	}
+
+	for _, cardID := range notifyCardIDs {
+		s.notifyFriendsNewCard(ctx, userID, cardID)
 	}
 
-	if visibleToFriends {
-		for _, cardID := range notifyCardIDs {
-			s.notifyFriendsNewCard(ctx, userID, cardID)
+	return count, nil
+}
+
+func validateVisibilityParams(params models.CardVisibilityParams) error {
+	if !params.Visibility.IsValid() {
+		return ErrInvalidVisibility
+	}
+	if params.Visibility == models.VisibilityGroups && len(params.GroupIDs) == 0 {
+		return ErrInvalidVisibility
+	}
+	return nil
+}
+
+// setVisibility applies visibility to the user's cards in one statement so
+// the card and its group shares never disagree. Switching to "link" creates a
+// share token if the card has none; switching away revokes it.
+func (s *CardService) setVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, params models.CardVisibilityParams) (int, error) {
+	groupIDs := []uuid.UUID{}
+	if params.Visibility == models.VisibilityGroups {
+		groupIDs = uniqueUUIDs(params.GroupIDs)
+		var owned int
+		err := s.db.QueryRow(ctx,
+			"SELECT COUNT(*) FROM friend_groups WHERE id = ANY($1) AND owner_id = $2",
+			groupIDs, userID,
+		).Scan(&owned)
+		if err != nil {
+			return 0, fmt.Errorf("checking friend groups: %w", err)
+		}
+		if owned != len(groupIDs) {
+			return 0, ErrFriendGroupNotFound
 		}
 	}
 
-	return int(result.RowsAffected()), nil
+	// An edit has already bumped its card's version.
+	return applyVisibility(ctx, s.db, userID, cardIDs, params.Visibility, groupIDs, s.committed == nil)
 }
 
-// BulkDelete deletes multiple cards owned by the user
-// Returns the count of cards deleted (cards not owned by user are silently skipped)
-func (s *CardService) BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
-	if len(cardIDs) == 0 {
-		return 0, nil
+// applyVisibility sets the visibility of the user's cards and replaces their
+// group shares with groupIDs, skipping any group the user no longer owns.
+func applyVisibility(ctx context.Context, db DBConn, userID uuid.UUID, cardIDs []uuid.UUID, visibility models.CardVisibility, groupIDs []uuid.UUID, bumpVersion bool) (int, error) {
+	var count int
+	err := db.QueryRow(ctx,
+		`WITH updated AS (
+		   UPDATE bingo_cards
+		   SET visibility = $1,
+		       share_token = CASE WHEN $1 = 'link'
+		                          THEN COALESCE(share_token, REPLACE(gen_random_uuid()::text || gen_random_uuid()::text, '-', ''))
+		                     END,
+		       updated_at = NOW(),
+		       version = version + CASE WHEN $5 THEN 1 ELSE 0 END
+		   WHERE id = ANY($2) AND user_id = $3
+		   RETURNING id
+		 ), cleared AS (
+		   DELETE FROM card_group_shares
+		   WHERE card_id IN (SELECT id FROM updated) AND NOT (group_id = ANY($4))
+		 ), shared AS (
+		   INSERT INTO card_group_shares (card_id, group_id)
+		   SELECT u.id, g.id FROM updated u
+		   CROSS JOIN friend_groups g
+		   WHERE g.id = ANY($4) AND g.owner_id = $3
+		   ON CONFLICT DO NOTHING
+		 )
+		 SELECT COUNT(*) FROM updated`,
+		string(visibility), cardIDs, userID, groupIDs, bumpVersion,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("updating visibility: %w", err)
 	}
+	return count, nil
+}
 
-	// First delete items for these cards (owned by user)
-	_, err := s.db.Exec(ctx,
-		`DELETE FROM bingo_items WHERE card_id IN (
-			SELECT id FROM bingo_cards WHERE id = ANY($1) AND user_id = $2
-		)`,
-		cardIDs, userID,
-	)
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// GetSharing returns a card's audience for its owner, including the share
+// token when the card is shared by link.
+func (s *CardService) GetSharing(ctx context.Context, userID, cardID uuid.UUID) (*models.CardSharing, error) {
+	var ownerID uuid.UUID
+	sharing := &models.CardSharing{GroupIDs: []uuid.UUID{}}
+	var visibility string
+	err := s.db.QueryRow(ctx,
+		"SELECT user_id, visibility, share_token FROM bingo_cards WHERE id = $1",
+		cardID,
+	).Scan(&ownerID, &visibility, &sharing.ShareToken)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCardNotFound
+	}
 	if err != nil {
-		return 0, fmt.Errorf("bulk deleting card items: %w", err)
+		return nil, fmt.Errorf("getting card sharing: %w", err)
 	}
+	if ownerID != userID {
+		return nil, ErrNotCardOwner
+	}
+	sharing.Visibility = models.CardVisibility(visibility)
 
-	// Then delete the cards
-	result, err := s.db.Exec(ctx,
-		`DELETE FROM bingo_cards WHERE id = ANY($1) AND user_id = $2`,
-		cardIDs, userID,
-	)
+	if sharing.Visibility == models.VisibilityGroups {
+		rows, err := s.db.Query(ctx,
+			"SELECT group_id FROM card_group_shares WHERE card_id = $1 ORDER BY group_id",
+			cardID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("listing card groups: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return nil, fmt.Errorf("scanning card group: %w", err)
+			}
+			sharing.GroupIDs = append(sharing.GroupIDs, id)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("listing card groups: %w", err)
+		}
+	}
+	return sharing, nil
+}
+
+// GetByShareToken returns a finalized card shared by link. Any other card,
+// including one whose link was revoked, is reported as not found.
+func (s *CardService) GetByShareToken(ctx context.Context, token string) (*models.BingoCard, error) {
+	if token == "" {
+		return nil, ErrCardNotFound
+	}
+	var cardID uuid.UUID
+	err := s.db.QueryRow(ctx,
+		"SELECT id FROM bingo_cards WHERE share_token = $1 AND visibility = 'link' AND is_finalized = true",
+		token,
+	).Scan(&cardID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCardNotFound
+	}
 	if err != nil {
-		return 0, fmt.Errorf("bulk deleting cards: %w", err)
+		return nil, fmt.Errorf("getting shared card: %w", err)
+	}
+	return s.GetByID(ctx, cardID)
+}
+
+// BulkDelete moves multiple cards owned by the user to the trash
+// Returns the count of cards deleted (cards not owned by user are silently skipped)
+func (s *CardService) BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
+	if len(cardIDs) == 0 {
+		return 0, nil
 	}
 
-	return int(result.RowsAffected()), nil
+	return s.trashCards(ctx, userID, cardIDs)
 }
 
 // BulkUpdateArchive updates the archive status of multiple cards owned by the user
@@ -911,19 +1083,23 @@ func (s *CardService) BulkUpdateArchive(ctx context.Context, userID uuid.UUID, c
 		return 0, nil
 	}
 
+	befores := s.snapshotCards(ctx, models.RevisionArchive, userID, cardIDs)
 	result, err := s.db.Exec(ctx,
-		`UPDATE bingo_cards SET is_archived = $1, updated_at = NOW()
+		`UPDATE bingo_cards SET is_archived = $1, updated_at = NOW(), version = version + 1
 		 WHERE id = ANY($2) AND user_id = $3`,
 		isArchived, cardIDs, userID,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("bulk updating archive status: %w", err)
 	}
+	for _, b := range befores {
+		s.recordRevisionFrom(ctx, models.RevisionArchive, b.card, b.snap, nil)
+	}
 
 	return int(result.RowsAffected()), nil
 }
 
-func (s *CardService) CompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error) {
+func (s *CardService) completeItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error) {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -959,38 +1135,49 @@ func (s *CardService) CompleteItem(ctx context.Context, userID, cardID uuid.UUID
 	if err != nil {
 		return nil, fmt.Errorf("completing item: %w", err)
 	}
+	s.recordRevision(ctx, models.RevisionCompleteItem, card, nil)
 
 	item.IsCompleted = true
 	item.CompletedAt = &now
 	item.Notes = params.Notes
 	item.ProofURL = params.ProofURL
 
-	if card.VisibleToFriends {
-		updatedItems := make([]models.BingoItem, len(card.Items))
-		copy(updatedItems, card.Items)
-		for i := range updatedItems {
-			if updatedItems[i].Position == position {
-				updatedItems[i].IsCompleted = true
-				updatedItems[i].CompletedAt = &now
-				updatedItems[i].Notes = params.Notes
-				updatedItems[i].ProofURL = params.ProofURL
-				break
-			}
-		}
-		var freePos *int
-		if card.HasFreePositionSet() {
-			freePos = card.FreeSpacePos
-		}
-		bingos := s.countBingos(updatedItems, card.GridSize, freePos)
-		if bingos > 0 {
-			s.notifyFriendsBingo(ctx, userID, cardID, bingos)
+	s.afterCommit(func(s *CardService) {
+		recordActivity(ctx, s.activity, models.ActivityEvent{ActorID: userID, Type: models.ActivityItemCompleted, CardID: cardID, ItemID: &item.ID})
+	})
+
+	updatedItems := make([]models.BingoItem, len(card.Items))
+	copy(updatedItems, card.Items)
+	for i := range updatedItems {
+		if updatedItems[i].Position == position {
+			updatedItems[i].IsCompleted = true
+			updatedItems[i].CompletedAt = &now
+			updatedItems[i].Notes = params.Notes
+			updatedItems[i].ProofURL = params.ProofURL
+			break
 		}
 	}
+	var freePos *int
+	if card.HasFreePositionSet() {
+		freePos = card.FreeSpacePos
+	}
+	bingos := s.countBingos(updatedItems, card.GridSize, freePos)
+
+	// The feed gets an entry for each new line; visibility is applied when
+	// the feed is read, so record it even if friends can't see the card yet.
+	if bingos > s.countBingos(card.Items, card.GridSize, freePos) {
+		s.afterCommit(func(s *CardService) {
+			recordActivity(ctx, s.activity, models.ActivityEvent{ActorID: userID, Type: models.ActivityBingo, CardID: cardID, BingoCount: &bingos})
+		})
+	}
+	if card.Visibility.SharedWithFriends() && bingos > 0 {
+		s.notifyFriendsBingo(ctx, userID, cardID, bingos)
+	}
 
 	return item, nil
 }
 
-func (s *CardService) UncompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error) {
+func (s *CardService) uncompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error) {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -1025,14 +1212,19 @@ func (s *CardService) UncompleteItem(ctx context.Context, userID, cardID uuid.UU
 	if err != nil {
 		return nil, fmt.Errorf("uncompleting item: %w", err)
 	}
+	s.recordRevision(ctx, models.RevisionUncompleteItem, card, nil)
 
 	item.IsCompleted = false
 	item.CompletedAt = nil
 
+	s.afterCommit(func(s *CardService) {
+		removeActivity(ctx, s.activity, userID, models.ActivityItemCompleted, item.ID)
+	})
+
 	return item, nil
 }
 
-func (s *CardService) UpdateItemNotes(ctx context.Context, userID, cardID uuid.UUID, position int, notes, proofURL *string) (*models.BingoItem, error) {
+func (s *CardService) updateItemNotes(ctx context.Context, userID, cardID uuid.UUID, position int, notes, proofURL *string) (*models.BingoItem, error) {
 	// Get and verify card ownership
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
@@ -1062,6 +1254,7 @@ func (s *CardService) UpdateItemNotes(ctx context.Context, userID, cardID uuid.U
 	if err != nil {
 		return nil, fmt.Errorf("updating notes: %w", err)
 	}
+	s.recordRevision(ctx, models.RevisionUpdateNotes, card, nil)
 
 	item.Notes = notes
 	item.ProofURL = proofURL
@@ -1069,9 +1262,64 @@ func (s *CardService) UpdateItemNotes(ctx context.Context, userID, cardID uuid.U
 	return item, nil
 }
 
+// updateItemSchedule sets an item's due date and reminder. Due dates work
+// on draft and finalized cards alike.
+func (s *CardService) updateItemSchedule(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemScheduleParams) (*models.BingoItem, error) {
+	dueDate := params.DueDate
+	reminder := params.ReminderDaysBefore
+	if dueDate == nil {
+		reminder = nil
+	} else {
+		parsed, err := time.Parse("2006-01-02", *dueDate)
+		if err != nil || parsed.Year() < 2000 || parsed.Year() > 9999 {
+			return nil, ErrInvalidDueDate
+		}
+		normalized := parsed.Format("2006-01-02")
+		dueDate = &normalized
+	}
+	if reminder != nil && (*reminder < 0 || *reminder > models.MaxReminderDaysBefore) {
+		return nil, ErrInvalidReminder
+	}
+
+	card, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.UserID != userID {
+		return nil, ErrNotCardOwner
+	}
+
+	var item *models.BingoItem
+	for _, i := range card.Items {
+		if i.Position == position {
+			itemCopy := i
+			item = &itemCopy
+			break
+		}
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+
+	_, err = s.db.Exec(ctx,
+		"UPDATE bingo_items SET due_date = $1::date, reminder_days_before = $2 WHERE id = $3",
+		dueDate, reminder, item.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("updating due date: %w", err)
+	}
+	s.recordRevision(ctx, models.RevisionUpdateSchedule, card, nil)
+
+	item.DueDate = dueDate
+	item.ReminderDaysBefore = reminder
+
+	return item, nil
+}
+
 func (s *CardService) getCardItems(ctx context.Context, cardID uuid.UUID) ([]models.BingoItem, error) {
 	rows, err := s.db.Query(ctx,
-		`SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at
+		`SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url,
+		        to_char(due_date, 'YYYY-MM-DD'), reminder_days_before, hidden_at, created_at
 		 FROM bingo_items WHERE card_id = $1 ORDER BY position`,
 		cardID,
 	)
@@ -1083,7 +1331,7 @@ func (s *CardService) getCardItems(ctx context.Context, cardID uuid.UUID) ([]mod
 	var items []models.BingoItem
 	for rows.Next() {
 		var item models.BingoItem
-		if err := rows.Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.DueDate, &item.ReminderDaysBefore, &item.HiddenAt, &item.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scanning item: %w", err)
 		}
 		items = append(items, item)
@@ -1125,7 +1373,7 @@ func (s *CardService) GetArchive(ctx context.Context, userID uuid.UUID) ([]*mode
 
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		        is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version
 		 FROM bingo_cards
 		 WHERE user_id = $1 AND year < $2 AND is_finalized = true
 		 ORDER BY year DESC, created_at DESC`,
@@ -1142,7 +1390,7 @@ func (s *CardService) GetArchive(ctx context.Context, userID uuid.UUID) ([]*mode
 		if err := rows.Scan(
 			&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 			&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.Visibility, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.Version,
 		); err != nil {
 			return nil, fmt.Errorf("scanning card: %w", err)
 		}
@@ -1172,6 +1420,12 @@ func (s *CardService) GetStats(ctx context.Context, userID, cardID uuid.UUID) (*
 		return nil, ErrNotCardOwner
 	}
 
+	return s.StatsFor(card), nil
+}
+
+// StatsFor computes completion stats for a loaded card. It doesn't check
+// ownership; callers decide who may see them.
+func (s *CardService) StatsFor(card *models.BingoCard) *models.CardStats {
 	stats := &models.CardStats{
 		CardID:     card.ID,
 		Year:       card.Year,
@@ -1210,7 +1464,7 @@ func (s *CardService) GetStats(ctx context.Context, userID, cardID uuid.UUID) (*
 		return nil
 	}())
 
-	return stats, nil
+	return stats
 }
 
 // countBingos counts how many bingos (rows, columns, diagonals) are complete
@@ -1299,13 +1553,13 @@ func (s *CardService) CheckForConflict(ctx context.Context, userID uuid.UUID, ye
 	if title != nil && *title != "" {
 		// Check for card with this specific title
 		query = `SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		                is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		                is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version
 			FROM bingo_cards WHERE user_id = $1 AND year = $2 AND title = $3`
 		args = []interface{}{userID, year, *title}
 	} else {
 		// Check for any card with null title (default card)
 		query = `SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		                is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		                is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version
 			FROM bingo_cards WHERE user_id = $1 AND year = $2 AND title IS NULL`
 		args = []interface{}{userID, year}
 	}
@@ -1313,7 +1567,7 @@ func (s *CardService) CheckForConflict(ctx context.Context, userID uuid.UUID, ye
 	err := s.db.QueryRow(ctx, query, args...).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.Visibility, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrCardNotFound
@@ -1419,26 +1673,32 @@ func (s *CardService) Import(ctx context.Context, params models.ImportCardParams
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	// Determine visibility (default to true if not specified)
-	visibleToFriends := true
+	// Determine visibility (default to all friends if not specified)
+	visibility := models.VisibilityFriends
 	if params.VisibleToFriends != nil {
-		visibleToFriends = *params.VisibleToFriends
+		visibility = models.VisibilityFromFlag(*params.VisibleToFriends)
 	}
 
 	// Create the card
 	card := &models.BingoCard{}
 	err = tx.QueryRow(ctx,
-		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, is_finalized, visible_to_friends)
+		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, is_finalized, visibility)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		 RETURNING id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		           is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at`,
-		params.UserID, params.Year, params.Category, params.Title, params.GridSize, params.HeaderText, params.HasFreeSpace, params.FreeSpacePos, params.Finalize, visibleToFriends,
+		           is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version`,
+		params.UserID, params.Year, params.Category, params.Title, params.GridSize, params.HeaderText, params.HasFreeSpace, params.FreeSpacePos, params.Finalize, string(visibility),
 	).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.Visibility, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.Version,
 	)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if mapped := mapBingoCardsUniqueViolationToCardExistsError(pgErr, params.Title); mapped != nil {
+				return nil, mapped
+			}
+		}
 		return nil, fmt.Errorf("creating card: %w", err)
 	}
 
@@ -1463,14 +1723,14 @@ func (s *CardService) Import(ctx context.Context, params models.ImportCardParams
 		return nil, fmt.Errorf("committing transaction: %w", err)
 	}
 
-	if card.IsFinalized && card.VisibleToFriends {
+	if card.IsFinalized && card.Visibility.SharedWithFriends() {
 		s.notifyFriendsNewCard(ctx, card.UserID, card.ID)
 	}
 
 	return card, nil
 }
 
-func (s *CardService) UpdateConfig(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardConfigParams) (*models.BingoCard, error) {
+func (s *CardService) updateConfig(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardConfigParams) (*models.BingoCard, error) {
 	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
 		return nil, err
@@ -1571,7 +1831,12 @@ func (s *CardService) UpdateConfig(ctx context.Context, userID, cardID uuid.UUID
 		return nil, fmt.Errorf("committing transaction: %w", err)
 	}
 
-	return s.GetByID(ctx, card.ID)
+	updated, err := s.GetByID(ctx, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordRevision(ctx, models.RevisionUpdateConfig, card, updated)
+	return updated, nil
 }
 
 type CloneParams struct {
@@ -1701,12 +1966,12 @@ func (s *CardService) Clone(ctx context.Context, userID, sourceCardID uuid.UUID,
 		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		 RETURNING id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		           is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at`,
+		           is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version`,
 		userID, year, category, title, params.GridSize, params.HeaderText, hasFreeSpace, freePos,
 	).Scan(
 		&newCard.ID, &newCard.UserID, &newCard.Year, &newCard.Category, &newCard.Title,
 		&newCard.GridSize, &newCard.HeaderText, &newCard.HasFreeSpace, &newCard.FreeSpacePos,
-		&newCard.IsActive, &newCard.IsFinalized, &newCard.VisibleToFriends, &newCard.IsArchived, &newCard.CreatedAt, &newCard.UpdatedAt,
+		&newCard.IsActive, &newCard.IsFinalized, &newCard.VisibleToFriends, &newCard.Visibility, &newCard.IsArchived, &newCard.CreatedAt, &newCard.UpdatedAt, &newCard.Version,
 	)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -1746,25 +2011,30 @@ func (s *CardService) notifyFriendsNewCard(ctx context.Context, userID, cardID u
 	if s.notificationService == nil {
 		return
 	}
-	if err := s.notificationService.NotifyFriendsNewCard(ctx, userID, cardID); err != nil {
-		logging.Error("Failed to notify friends about new card", map[string]interface{}{
-			"error":   err.Error(),
-			"user_id": userID.String(),
-			"card_id": cardID.String(),
-		})
-	}
+	// Notifications read the card back, so they wait for the edit to commit.
+	s.afterCommit(func(s *CardService) {
+		if err := s.notificationService.NotifyFriendsNewCard(ctx, userID, cardID); err != nil {
+			logging.FromContext(ctx).Error("Failed to notify friends about new card", map[string]interface{}{
+				"error":   err.Error(),
+				"user_id": userID.String(),
+				"card_id": cardID.String(),
+			})
+		}
+	})
 }
 
 func (s *CardService) notifyFriendsBingo(ctx context.Context, userID, cardID uuid.UUID, bingoCount int) {
 	if s.notificationService == nil {
 		return
 	}
-	if err := s.notificationService.NotifyFriendsBingo(ctx, userID, cardID, bingoCount); err != nil {
-		logging.Error("Failed to notify friends about bingo", map[string]interface{}{
-			"error":       err.Error(),
-			"user_id":     userID.String(),
-			"card_id":     cardID.String(),
-			"bingo_count": bingoCount,
-		})
-	}
+	s.afterCommit(func(s *CardService) {
+		if err := s.notificationService.NotifyFriendsBingo(ctx, userID, cardID, bingoCount); err != nil {
+			logging.FromContext(ctx).Error("Failed to notify friends about bingo", map[string]interface{}{
+				"error":       err.Error(),
+				"user_id":     userID.String(),
+				"card_id":     cardID.String(),
+				"bingo_count": bingoCount,
+			})
+		}
+	})
 }
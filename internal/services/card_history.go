@@ -0,0 +1,727 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrRevisionNotFound    = errors.New("revision not found")
+	ErrNothingToUndo       = errors.New("nothing to undo")
+	ErrTrashedCardNotFound = errors.New("deleted card not found")
+)
+
+// recordRevision stores a history entry for an edit to a card. before is the
+// card as loaded ahead of the edit; after is loaded here when nil. Edits that
+// change which groups a card is shared with use recordRevisionFrom instead,
+// since the shares are read here rather than from before. Failures are logged
+// rather than returned so an edit never fails because its history could not
+// be written.
+func (s *CardService) recordRevision(ctx context.Context, action models.RevisionAction, before, after *models.BingoCard) {
+	if before == nil {
+		return
+	}
+	beforeSnap, err := s.snapshotOf(ctx, before)
+	if err != nil {
+		logRevisionError(ctx, err, before.ID, action)
+		return
+	}
+	s.recordRevisionFrom(ctx, action, before, beforeSnap, after)
+}
+
+// recordRevisionFrom is recordRevision with the before state captured by the
+// caller ahead of the edit.
+func (s *CardService) recordRevisionFrom(ctx context.Context, action models.RevisionAction, card *models.BingoCard, beforeSnap models.CardSnapshot, after *models.BingoCard) {
+	if after == nil {
+		var err error
+		after, err = s.GetByID(ctx, card.ID)
+		if err != nil {
+			logRevisionError(ctx, err, card.ID, action)
+			return
+		}
+	}
+	afterSnap, err := s.snapshotOf(ctx, after)
+	if err != nil {
+		logRevisionError(ctx, err, card.ID, action)
+		return
+	}
+
+	if reflect.DeepEqual(beforeSnap, afterSnap) {
+		return
+	}
+	beforeJSON, err := json.Marshal(beforeSnap)
+	if err != nil {
+		logRevisionError(ctx, err, card.ID, action)
+		return
+	}
+	afterJSON, err := json.Marshal(afterSnap)
+	if err != nil {
+		logRevisionError(ctx, err, card.ID, action)
+		return
+	}
+
+	// Written once the edit commits: a failed insert would otherwise abort
+	// the edit's transaction.
+	s.afterCommit(func(s *CardService) {
+		_, err := s.db.Exec(ctx,
+			`INSERT INTO card_revisions (card_id, user_id, action, before_state, after_state)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			card.ID, card.UserID, string(action), beforeJSON, afterJSON,
+		)
+		if err != nil {
+			logRevisionError(ctx, err, card.ID, action)
+		}
+	})
+}
+
+// snapshotOf captures card for its history, including the groups it is
+// shared with.
+func (s *CardService) snapshotOf(ctx context.Context, card *models.BingoCard) (models.CardSnapshot, error) {
+	snap := models.SnapshotOf(card)
+	if card.Visibility != models.VisibilityGroups {
+		return snap, nil
+	}
+	rows, err := s.db.Query(ctx,
+		"SELECT group_id FROM card_group_shares WHERE card_id = $1 ORDER BY group_id",
+		card.ID,
+	)
+	if err != nil {
+		return snap, fmt.Errorf("listing card groups: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return snap, fmt.Errorf("scanning card group: %w", err)
+		}
+		snap.GroupIDs = append(snap.GroupIDs, id)
+	}
+	return snap, rows.Err()
+}
+
+// cardBefore is a card and its history snapshot, captured ahead of an edit.
+type cardBefore struct {
+	card *models.BingoCard
+	snap models.CardSnapshot
+}
+
+// snapshotCards captures the cards among cardIDs that userID owns ahead of
+// a bulk edit, so each can get a revision afterwards. Cards that can't be
+// loaded are logged and skipped.
+func (s *CardService) snapshotCards(ctx context.Context, action models.RevisionAction, userID uuid.UUID, cardIDs []uuid.UUID) []cardBefore {
+	var befores []cardBefore
+	for _, id := range uniqueUUIDs(cardIDs) {
+		card, err := s.GetByID(ctx, id)
+		if errors.Is(err, ErrCardNotFound) {
+			continue
+		}
+		if err == nil {
+			if card.UserID != userID {
+				continue
+			}
+			var snap models.CardSnapshot
+			if snap, err = s.snapshotOf(ctx, card); err == nil {
+				befores = append(befores, cardBefore{card: card, snap: snap})
+				continue
+			}
+		}
+		logRevisionError(ctx, err, id, action)
+	}
+	return befores
+}
+
+func logRevisionError(ctx context.Context, err error, cardID uuid.UUID, action models.RevisionAction) {
+	logging.FromContext(ctx).Warn("Failed to record card revision", map[string]interface{}{
+		"error":   err.Error(),
+		"card_id": cardID.String(),
+		"action":  string(action),
+	})
+}
+
+// withoutItem returns a copy of card as it was before itemID was added.
+func withoutItem(card *models.BingoCard, itemID uuid.UUID) *models.BingoCard {
+	before := *card
+	before.Items = make([]models.BingoItem, 0, len(card.Items))
+	for _, item := range card.Items {
+		if item.ID != itemID {
+			before.Items = append(before.Items, item)
+		}
+	}
+	return &before
+}
+
+// History returns the newest revisions of a card, newest first.
+func (s *CardService) History(ctx context.Context, userID, cardID uuid.UUID) ([]models.CardRevision, error) {
+	card, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.UserID != userID {
+		return nil, ErrNotCardOwner
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT id, card_id, action, before_state, after_state, undone_at, created_at
+		 FROM card_revisions
+		 WHERE card_id = $1
+		 ORDER BY created_at DESC
+		 LIMIT $2`,
+		cardID, models.MaxCardRevisions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing card revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.CardRevision{}
+	for rows.Next() {
+		var rev models.CardRevision
+		var action string
+		var beforeJSON, afterJSON []byte
+		if err := rows.Scan(&rev.ID, &rev.CardID, &action, &beforeJSON, &afterJSON, &rev.UndoneAt, &rev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning card revision: %w", err)
+		}
+		rev.Action = models.RevisionAction(action)
+		if err := json.Unmarshal(beforeJSON, &rev.Before); err != nil {
+			return nil, fmt.Errorf("decoding card revision: %w", err)
+		}
+		if err := json.Unmarshal(afterJSON, &rev.After); err != nil {
+			return nil, fmt.Errorf("decoding card revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating card revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (s *CardService) undo(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	isFinalized, err := lockOwnCard(ctx, tx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisionID uuid.UUID
+	var beforeJSON []byte
+	err = tx.QueryRow(ctx,
+		`SELECT id, before_state FROM card_revisions
+		 WHERE card_id = $1 AND undone_at IS NULL
+		 ORDER BY created_at DESC
+		 LIMIT 1
+		 FOR UPDATE`,
+		cardID,
+	).Scan(&revisionID, &beforeJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNothingToUndo
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting last revision: %w", err)
+	}
+	var snap models.CardSnapshot
+	if err := json.Unmarshal(beforeJSON, &snap); err != nil {
+		return nil, fmt.Errorf("decoding card revision: %w", err)
+	}
+
+	if isFinalized {
+		if err := s.checkLayoutUnchanged(ctx, cardID, snap); err != nil {
+			return nil, err
+		}
+	}
+	if err := applySnapshot(ctx, tx, userID, cardID, snap); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE card_revisions SET undone_at = NOW() WHERE id = $1", revisionID); err != nil {
+		return nil, fmt.Errorf("marking revision undone: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return s.GetByID(ctx, cardID)
+}
+
+func (s *CardService) restoreRevision(ctx context.Context, userID, cardID, revisionID uuid.UUID) (*models.BingoCard, error) {
+	current, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if current.UserID != userID {
+		return nil, ErrNotCardOwner
+	}
+	currentSnap, err := s.snapshotOf(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	if _, err := lockOwnCard(ctx, tx, userID, cardID); err != nil {
+		return nil, err
+	}
+
+	var afterJSON []byte
+	err = tx.QueryRow(ctx,
+		"SELECT after_state FROM card_revisions WHERE id = $1 AND card_id = $2",
+		revisionID, cardID,
+	).Scan(&afterJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting revision: %w", err)
+	}
+	var snap models.CardSnapshot
+	if err := json.Unmarshal(afterJSON, &snap); err != nil {
+		return nil, fmt.Errorf("decoding card revision: %w", err)
+	}
+
+	if current.IsFinalized && !currentSnap.SameLayout(snap) {
+		return nil, ErrCardFinalized
+	}
+	if err := applySnapshot(ctx, tx, userID, cardID, snap); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	card, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordRevisionFrom(ctx, models.RevisionRestore, current, currentSnap, card)
+	return card, nil
+}
+
+// lockOwnCard locks a card row for the rest of tx, checks that userID owns
+// it and reports whether it is finalized.
+func lockOwnCard(ctx context.Context, tx Tx, userID, cardID uuid.UUID) (bool, error) {
+	var ownerID uuid.UUID
+	var isFinalized bool
+	err := tx.QueryRow(ctx,
+		"SELECT user_id, is_finalized FROM bingo_cards WHERE id = $1 FOR UPDATE",
+		cardID,
+	).Scan(&ownerID, &isFinalized)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrCardNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("locking card: %w", err)
+	}
+	if ownerID != userID {
+		return false, ErrNotCardOwner
+	}
+	return isFinalized, nil
+}
+
+// checkLayoutUnchanged returns ErrCardFinalized if applying snap would change
+// the grid of a finalized card. Undoing an edit made while the card was a
+// draft does that, so history only reaches back to finalization.
+func (s *CardService) checkLayoutUnchanged(ctx context.Context, cardID uuid.UUID, snap models.CardSnapshot) error {
+	current, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return err
+	}
+	if !models.SnapshotOf(current).SameLayout(snap) {
+		return ErrCardFinalized
+	}
+	return nil
+}
+
+// applySnapshot rewrites a card to match snap. Items keep their IDs so
+// repeated undo and restore line up with the recorded history. Sharing,
+// archive and item state are left alone when snap predates them.
+func applySnapshot(ctx context.Context, tx Tx, userID, cardID uuid.UUID, snap models.CardSnapshot) error {
+	itemIDs := make([]uuid.UUID, 0, len(snap.Items))
+	for _, item := range snap.Items {
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	if _, err := tx.Exec(ctx,
+		"DELETE FROM bingo_items WHERE card_id = $1 AND NOT (id = ANY($2))",
+		cardID, itemIDs,
+	); err != nil {
+		return fmt.Errorf("removing items: %w", err)
+	}
+	// Park the remaining items on negative positions so the inserts below
+	// cannot collide with them on the (card_id, position) constraint.
+	if _, err := tx.Exec(ctx,
+		"UPDATE bingo_items SET position = -position - 1 WHERE card_id = $1",
+		cardID,
+	); err != nil {
+		return fmt.Errorf("clearing positions: %w", err)
+	}
+	for _, item := range snap.Items {
+		state := item.State
+		if state == nil {
+			state = &models.SnapshotItemState{}
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO bingo_items (id, card_id, position, content,
+			                          is_completed, completed_at, notes, proof_url, due_date, reminder_days_before)
+			 VALUES ($1, $2, $3, $4, $6, $7, $8, $9, $10::date, $11)
+			 ON CONFLICT (id) DO UPDATE SET
+			     position = EXCLUDED.position,
+			     content = EXCLUDED.content,
+			     is_completed = CASE WHEN $5 THEN EXCLUDED.is_completed ELSE bingo_items.is_completed END,
+			     completed_at = CASE WHEN $5 THEN EXCLUDED.completed_at ELSE bingo_items.completed_at END,
+			     notes = CASE WHEN $5 THEN EXCLUDED.notes ELSE bingo_items.notes END,
+			     proof_url = CASE WHEN $5 THEN EXCLUDED.proof_url ELSE bingo_items.proof_url END,
+			     due_date = CASE WHEN $5 THEN EXCLUDED.due_date ELSE bingo_items.due_date END,
+			     reminder_days_before = CASE WHEN $5 THEN EXCLUDED.reminder_days_before ELSE bingo_items.reminder_days_before END`,
+			item.ID, cardID, item.Position, item.Content, item.State != nil,
+			state.IsCompleted, state.CompletedAt, state.Notes, state.ProofURL, state.DueDate, state.ReminderDaysBefore,
+		); err != nil {
+			return fmt.Errorf("restoring item: %w", err)
+		}
+	}
+
+	_, err := tx.Exec(ctx,
+		`UPDATE bingo_cards
+		 SET title = $1, category = $2, header_text = $3, has_free_space = $4, free_space_position = $5,
+		     is_archived = COALESCE($7, is_archived)
+		 WHERE id = $6`,
+		snap.Title, snap.Category, snap.HeaderText, snap.HasFreeSpace, snap.FreeSpacePos, cardID, snap.IsArchived,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if mapped := mapBingoCardsUniqueViolationToCardExistsError(pgErr, snap.Title); mapped != nil {
+				return mapped
+			}
+		}
+		return fmt.Errorf("restoring card: %w", err)
+	}
+
+	if snap.Visibility != nil {
+		groupIDs := snap.GroupIDs
+		if groupIDs == nil {
+			groupIDs = []uuid.UUID{}
+		}
+		// The version was bumped when the edit started.
+		if _, err := applyVisibility(ctx, tx, userID, []uuid.UUID{cardID}, *snap.Visibility, groupIDs, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trashSnapshot is everything needed to put a deleted card back.
+type trashSnapshot struct {
+	Card       models.BingoCard `json:"card"`
+	ShareToken *string          `json:"share_token,omitempty"`
+	GroupIDs   []uuid.UUID      `json:"group_ids,omitempty"`
+}
+
+// trashCards moves the given cards owned by userID to the trash and returns
+// how many were moved. Reactions, comments, activity and revisions tied to
+// the cards are not kept and are gone even if the card is restored.
+func (s *CardService) trashCards(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	snapshots, err := loadTrashSnapshots(ctx, tx, userID, cardIDs)
+	if err != nil {
+		return 0, err
+	}
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(snapshots))
+	for _, snap := range snapshots {
+		ids = append(ids, snap.Card.ID)
+		data, err := json.Marshal(snap)
+		if err != nil {
+			return 0, fmt.Errorf("encoding card snapshot: %w", err)
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO card_trash (card_id, user_id, year, title, snapshot)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (card_id) DO UPDATE SET snapshot = EXCLUDED.snapshot, deleted_at = NOW()`,
+			snap.Card.ID, userID, snap.Card.Year, snap.Card.Title, data,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("trashing card: %w", err)
+		}
+	}
+
+	// Delete items first (foreign key constraint)
+	if _, err := tx.Exec(ctx, "DELETE FROM bingo_items WHERE card_id = ANY($1)", ids); err != nil {
+		return 0, fmt.Errorf("deleting card items: %w", err)
+	}
+	result, err := tx.Exec(ctx, "DELETE FROM bingo_cards WHERE id = ANY($1)", ids)
+	if err != nil {
+		return 0, fmt.Errorf("deleting cards: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("committing transaction: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+func loadTrashSnapshots(ctx context.Context, tx Tx, userID uuid.UUID, cardIDs []uuid.UUID) ([]*trashSnapshot, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
+		        is_active, is_finalized, visible_to_friends, visibility, is_archived, created_at, updated_at, version, share_token
+		 FROM bingo_cards
+		 WHERE id = ANY($1) AND user_id = $2
+		 FOR UPDATE`,
+		cardIDs, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("locking cards: %w", err)
+	}
+	var snapshots []*trashSnapshot
+	byID := make(map[uuid.UUID]*trashSnapshot)
+	for rows.Next() {
+		snap := &trashSnapshot{}
+		card := &snap.Card
+		if err := rows.Scan(
+			&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
+			&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
+			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.Visibility, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+			&card.Version, &snap.ShareToken,
+		); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning card: %w", err)
+		}
+		snapshots = append(snapshots, snap)
+		byID[card.ID] = snap
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating cards: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(snapshots))
+	for _, snap := range snapshots {
+		ids = append(ids, snap.Card.ID)
+	}
+
+	rows, err = tx.Query(ctx,
+		`SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url,
+		        to_char(due_date, 'YYYY-MM-DD'), reminder_days_before, hidden_at, created_at
+		 FROM bingo_items WHERE card_id = ANY($1) ORDER BY position`,
+		ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting card items: %w", err)
+	}
+	for rows.Next() {
+		var item models.BingoItem
+		if err := rows.Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.DueDate, &item.ReminderDaysBefore, &item.HiddenAt, &item.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning item: %w", err)
+		}
+		if snap := byID[item.CardID]; snap != nil {
+			snap.Card.Items = append(snap.Card.Items, item)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating items: %w", err)
+	}
+
+	rows, err = tx.Query(ctx, "SELECT card_id, group_id FROM card_group_shares WHERE card_id = ANY($1)", ids)
+	if err != nil {
+		return nil, fmt.Errorf("getting card groups: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cardID, groupID uuid.UUID
+		if err := rows.Scan(&cardID, &groupID); err != nil {
+			return nil, fmt.Errorf("scanning card group: %w", err)
+		}
+		if snap := byID[cardID]; snap != nil {
+			snap.GroupIDs = append(snap.GroupIDs, groupID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating card groups: %w", err)
+	}
+	return snapshots, nil
+}
+
+// ListTrash returns the user's deleted cards that can still be restored,
+// most recently deleted first.
+func (s *CardService) ListTrash(ctx context.Context, userID uuid.UUID) ([]models.TrashedCard, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT snapshot, deleted_at FROM card_trash
+		 WHERE user_id = $1 AND deleted_at > $2
+		 ORDER BY deleted_at DESC`,
+		userID, time.Now().Add(-models.TrashRetention),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing trash: %w", err)
+	}
+	defer rows.Close()
+
+	cards := []models.TrashedCard{}
+	for rows.Next() {
+		var data []byte
+		var deletedAt time.Time
+		if err := rows.Scan(&data, &deletedAt); err != nil {
+			return nil, fmt.Errorf("scanning trashed card: %w", err)
+		}
+		var snap trashSnapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, fmt.Errorf("decoding trashed card: %w", err)
+		}
+		cards = append(cards, models.TrashedCard{
+			ID:          snap.Card.ID,
+			Year:        snap.Card.Year,
+			Title:       snap.Card.Title,
+			Category:    snap.Card.Category,
+			GridSize:    snap.Card.GridSize,
+			IsFinalized: snap.Card.IsFinalized,
+			ItemCount:   len(snap.Card.Items),
+			DeletedAt:   deletedAt,
+			PurgeAt:     deletedAt.Add(models.TrashRetention),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating trash: %w", err)
+	}
+	return cards, nil
+}
+
+// RestoreFromTrash puts a deleted card back with its items, completions and
+// sharing settings. Groups deleted in the meantime are dropped from the card.
+func (s *CardService) RestoreFromTrash(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	var data []byte
+	err = tx.QueryRow(ctx,
+		`SELECT snapshot FROM card_trash
+		 WHERE card_id = $1 AND user_id = $2 AND deleted_at > $3
+		 FOR UPDATE`,
+		cardID, userID, time.Now().Add(-models.TrashRetention),
+	).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTrashedCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting trashed card: %w", err)
+	}
+	var snap trashSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("decoding trashed card: %w", err)
+	}
+	card := snap.Card
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO bingo_cards (id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
+		                          is_active, is_finalized, visibility, is_archived, share_token, created_at, version)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+		card.ID, userID, card.Year, card.Category, card.Title, card.GridSize, card.HeaderText, card.HasFreeSpace, card.FreeSpacePos,
+		card.IsActive, card.IsFinalized, string(card.Visibility), card.IsArchived, snap.ShareToken, card.CreatedAt,
+		// Versions carry on from the deleted card so stale edits still fail.
+		card.Version+1,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if mapped := mapBingoCardsUniqueViolationToCardExistsError(pgErr, card.Title); mapped != nil {
+				return nil, mapped
+			}
+		}
+		return nil, fmt.Errorf("restoring card: %w", err)
+	}
+
+	for _, item := range card.Items {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO bingo_items (id, card_id, position, content, is_completed, completed_at, notes, proof_url,
+			                          due_date, reminder_days_before, hidden_at, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::date, $10, $11, $12)`,
+			item.ID, card.ID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL,
+			item.DueDate, item.ReminderDaysBefore, item.HiddenAt, item.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("restoring item: %w", err)
+		}
+	}
+
+	if len(snap.GroupIDs) > 0 {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO card_group_shares (card_id, group_id)
+			 SELECT $1, id FROM friend_groups WHERE id = ANY($2) AND owner_id = $3`,
+			card.ID, snap.GroupIDs, userID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("restoring card groups: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM card_trash WHERE card_id = $1", card.ID); err != nil {
+		return nil, fmt.Errorf("removing card from trash: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return s.GetByID(ctx, card.ID)
+}
+
+// PurgeTrash permanently removes cards that have been in the trash longer
+// than models.TrashRetention. It returns how many were removed.
+func (s *CardService) PurgeTrash(ctx context.Context) (int, error) {
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM card_trash WHERE deleted_at <= $1",
+		time.Now().Add(-models.TrashRetention),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("purging trash: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// PruneRevisions keeps only the newest models.MaxCardRevisions revisions of
+// each card. It returns how many were removed.
+func (s *CardService) PruneRevisions(ctx context.Context) (int, error) {
+	result, err := s.db.Exec(ctx,
+		`DELETE FROM card_revisions WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY card_id ORDER BY created_at DESC) AS rn
+				FROM card_revisions
+			) ranked
+			WHERE rn > $1
+		)`,
+		models.MaxCardRevisions,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("pruning card revisions: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
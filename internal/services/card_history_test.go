@@ -0,0 +1,528 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// newTrashTx returns a transaction in which cardID is the only card userID
+// can trash. Every Exec is handed to exec.
+func newTrashTx(cardID, userID uuid.UUID, exec func(sql string) (CommandTag, error)) *fakeTx {
+	return &fakeTx{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if strings.Contains(sql, "FROM bingo_cards") {
+				row := append(cardRowValues(cardID, userID, 5, false, nil, false), nil)
+				return &fakeRows{rows: [][]any{row}}, nil
+			}
+			return &fakeRows{rows: [][]any{}}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return exec(sql)
+		},
+	}
+}
+
+func itemRow(cardID uuid.UUID, itemID uuid.UUID, position int, content string) []any {
+	return []any{itemID, cardID, position, content, false, nil, nil, nil, nil, nil, nil, time.Now()}
+}
+
+func TestCardService_RecordRevision_OnEdit(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	itemID := uuid.New()
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+	loads := 0
+	db.QueryFunc = func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		loads++
+		content := "Old"
+		if loads > 1 {
+			content = "New"
+		}
+		return &fakeRows{rows: [][]any{itemRow(cardID, itemID, 0, content)}}, nil
+	}
+	var before, after models.CardSnapshot
+	var action string
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if strings.Contains(sql, "INSERT INTO card_revisions") {
+			action = args[2].(string)
+			if err := json.Unmarshal(args[3].([]byte), &before); err != nil {
+				t.Fatalf("decoding before: %v", err)
+			}
+			if err := json.Unmarshal(args[4].([]byte), &after); err != nil {
+				t.Fatalf("decoding after: %v", err)
+			}
+		}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+
+	svc := NewCardService(db)
+	content := "New"
+	if _, err := svc.UpdateItem(context.Background(), userID, cardID, 0, models.UpdateItemParams{Content: &content}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if action != string(models.RevisionUpdateItem) {
+		t.Fatalf("expected update_item revision, got %q", action)
+	}
+	if before.Items[0].Content != "Old" || after.Items[0].Content != "New" {
+		t.Fatalf("unexpected snapshots: before=%+v after=%+v", before.Items, after.Items)
+	}
+}
+
+func TestCardService_RecordRevision_SkipsNoChange(t *testing.T) {
+	execs := 0
+	db := &fakeDB{ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		execs++
+		return fakeCommandTag{}, nil
+	}}
+	svc := NewCardService(db)
+
+	card := &models.BingoCard{ID: uuid.New(), HeaderText: "BINGO", Items: []models.BingoItem{{ID: uuid.New(), Content: "a"}}}
+	svc.recordRevision(context.Background(), models.RevisionShuffle, card, card)
+
+	if execs != 0 {
+		t.Fatalf("expected no revision to be written, got %d", execs)
+	}
+}
+
+func TestCardService_RecordRevision_FinalizedCompletion(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	itemID := uuid.New()
+	db := newCardDB(cardID, userID, 5, false, nil, true, nil)
+	loads := 0
+	db.QueryFunc = func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		loads++
+		row := itemRow(cardID, itemID, 0, "Run")
+		if loads > 1 {
+			completedAt := time.Now()
+			row[4], row[5] = true, &completedAt
+		}
+		return &fakeRows{rows: [][]any{row}}, nil
+	}
+	var before, after models.CardSnapshot
+	var action string
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if strings.Contains(sql, "INSERT INTO card_revisions") {
+			action = args[2].(string)
+			_ = json.Unmarshal(args[3].([]byte), &before)
+			_ = json.Unmarshal(args[4].([]byte), &after)
+		}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+
+	svc := NewCardService(db)
+	if _, err := svc.CompleteItem(context.Background(), userID, cardID, 0, models.CompleteItemParams{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if action != string(models.RevisionCompleteItem) {
+		t.Fatalf("expected complete_item revision, got %q", action)
+	}
+	if before.Items[0].State.IsCompleted || !after.Items[0].State.IsCompleted {
+		t.Fatalf("unexpected item state: before=%+v after=%+v", before.Items[0].State, after.Items[0].State)
+	}
+	if after.Visibility == nil || *after.Visibility != models.VisibilityFriends {
+		t.Fatalf("expected visibility in snapshot, got %v", after.Visibility)
+	}
+}
+
+func TestCardService_History_NotOwner(t *testing.T) {
+	cardID := uuid.New()
+	db := newCardDB(cardID, uuid.New(), 5, false, nil, false, nil)
+
+	svc := NewCardService(db)
+	if _, err := svc.History(context.Background(), uuid.New(), cardID); !errors.Is(err, ErrNotCardOwner) {
+		t.Fatalf("expected ErrNotCardOwner, got %v", err)
+	}
+}
+
+func TestCardService_History_Success(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+	before, _ := json.Marshal(models.CardSnapshot{HeaderText: "BINGO", Items: []models.SnapshotItem{{ID: uuid.New(), Position: 0, Content: "a"}}})
+	after, _ := json.Marshal(models.CardSnapshot{HeaderText: "BINGO", Items: []models.SnapshotItem{}})
+	undone := time.Now()
+	db.QueryFunc = func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		if strings.Contains(sql, "FROM card_revisions") {
+			return &fakeRows{rows: [][]any{
+				{uuid.New(), cardID, "remove_item", before, after, &undone, time.Now()},
+			}}, nil
+		}
+		return &fakeRows{rows: [][]any{}}, nil
+	}
+
+	svc := NewCardService(db)
+	revisions, err := svc.History(context.Background(), userID, cardID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("expected 1 revision, got %d", len(revisions))
+	}
+	rev := revisions[0]
+	if rev.Action != models.RevisionRemoveItem || rev.UndoneAt == nil {
+		t.Fatalf("unexpected revision: %+v", rev)
+	}
+	if len(rev.Before.Items) != 1 || len(rev.After.Items) != 0 {
+		t.Fatalf("unexpected snapshots: %+v", rev)
+	}
+}
+
+func TestCardService_Undo_NothingToUndo(t *testing.T) {
+	userID := uuid.New()
+	tx := &fakeTx{QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+		if row, ok := cardLockRow(sql, userID); ok {
+			return row
+		}
+		if strings.Contains(sql, "FROM bingo_cards") {
+			return rowFromValues(userID, false)
+		}
+		return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+	}}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+
+	svc := NewCardService(db)
+	if _, err := svc.Undo(context.Background(), userID, uuid.New()); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected ErrNothingToUndo, got %v", err)
+	}
+}
+
+// newFinalizedUndoDB returns a finalized card with one completed item whose
+// newest revision has the given before state.
+func newFinalizedUndoDB(userID, cardID, itemID uuid.UUID, before []byte, exec func(sql string, args []any)) *fakeDB {
+	completedAt := time.Now()
+	row := itemRow(cardID, itemID, 0, "Run")
+	row[4], row[5] = true, &completedAt
+	db := newCardDB(cardID, userID, 5, false, nil, true, [][]any{row})
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
+			if strings.Contains(sql, "FOR UPDATE") && strings.Contains(sql, "FROM bingo_cards") {
+				return rowFromValues(userID, true)
+			}
+			if strings.Contains(sql, "FROM card_revisions") {
+				return rowFromValues(uuid.New(), before)
+			}
+			return db.QueryRow(ctx, sql, args...)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			exec(sql, args)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+	return db
+}
+
+func TestCardService_Undo_FinalizedRejectsLayoutChange(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	itemID := uuid.New()
+	before, _ := json.Marshal(models.CardSnapshot{
+		HeaderText: "BINGO",
+		Items:      []models.SnapshotItem{{ID: itemID, Position: 0, Content: "Walk"}},
+	})
+	db := newFinalizedUndoDB(userID, cardID, itemID, before, func(sql string, args []any) {
+		if !strings.Contains(sql, "card_revisions") {
+			t.Fatalf("unexpected write: %s", sql)
+		}
+	})
+
+	svc := NewCardService(db)
+	if _, err := svc.Undo(context.Background(), userID, cardID); !errors.Is(err, ErrCardFinalized) {
+		t.Fatalf("expected ErrCardFinalized, got %v", err)
+	}
+}
+
+func TestCardService_Undo_FinalizedRestoresItemState(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	itemID := uuid.New()
+	notes := "Slow"
+	before, _ := json.Marshal(models.CardSnapshot{
+		HeaderText: "BINGO",
+		Items: []models.SnapshotItem{{
+			ID: itemID, Position: 0, Content: "Run",
+			State: &models.SnapshotItemState{IsCompleted: false, Notes: &notes},
+		}},
+	})
+	var restored []any
+	db := newFinalizedUndoDB(userID, cardID, itemID, before, func(sql string, args []any) {
+		if strings.Contains(sql, "INSERT INTO bingo_items") {
+			restored = args
+		}
+	})
+
+	svc := NewCardService(db)
+	if _, err := svc.Undo(context.Background(), userID, cardID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored == nil {
+		t.Fatal("expected the item to be restored")
+	}
+	if restored[4] != true || restored[5] != false {
+		t.Fatalf("expected item marked incomplete, got %v", restored)
+	}
+	if got := restored[7].(*string); got == nil || *got != notes {
+		t.Fatalf("expected notes restored, got %v", restored[7])
+	}
+}
+
+func TestCardService_Undo_AppliesBeforeState(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	revisionID := uuid.New()
+	itemID := uuid.New()
+	title := "Restored"
+	before, _ := json.Marshal(models.CardSnapshot{
+		Title:      &title,
+		HeaderText: "BINGO",
+		Items:      []models.SnapshotItem{{ID: itemID, Position: 3, Content: "Run"}},
+	})
+
+	var sqls []string
+	var markedUndone, committed bool
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
+			if strings.Contains(sql, "FOR UPDATE") && strings.Contains(sql, "FROM bingo_cards") {
+				return rowFromValues(userID, false)
+			}
+			if strings.Contains(sql, "FROM bingo_cards") {
+				return rowFromValues(cardRowValues(cardID, userID, 5, false, nil, false)...)
+			}
+			return rowFromValues(revisionID, before)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			sqls = append(sqls, sql)
+			if strings.Contains(sql, "INSERT INTO bingo_items") && (args[0] != itemID || args[2] != 3 || args[3] != "Run") {
+				t.Fatalf("unexpected item restore args: %v", args)
+			}
+			if strings.Contains(sql, "UPDATE bingo_cards") {
+				if got := args[0].(*string); got == nil || *got != title {
+					t.Fatalf("unexpected title: %v", args[0])
+				}
+			}
+			if strings.Contains(sql, "SET undone_at") {
+				markedUndone = args[0] == revisionID
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		CommitFunc: func(ctx context.Context) error {
+			committed = true
+			return nil
+		},
+	}
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+
+	svc := NewCardService(db)
+	if _, err := svc.Undo(context.Background(), userID, cardID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !markedUndone || !committed {
+		t.Fatalf("expected revision marked undone and committed, got undone=%v committed=%v", markedUndone, committed)
+	}
+	if len(sqls) != 5 {
+		t.Fatalf("expected 5 statements, got %d: %v", len(sqls), sqls)
+	}
+}
+
+func TestCardService_RestoreRevision_NotFound(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+	tx := &fakeTx{QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+		if row, ok := cardLockRow(sql, userID); ok {
+			return row
+		}
+		if strings.Contains(sql, "FOR UPDATE") {
+			return rowFromValues(userID, false)
+		}
+		if strings.Contains(sql, "FROM bingo_cards") {
+			return db.QueryRow(ctx, sql, args...)
+		}
+		return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+	}}
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+
+	svc := NewCardService(db)
+	if _, err := svc.RestoreRevision(context.Background(), userID, cardID, uuid.New()); !errors.Is(err, ErrRevisionNotFound) {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
+
+func TestCardService_RestoreRevision_TitleConflict(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	title := "Taken"
+	after, _ := json.Marshal(models.CardSnapshot{Title: &title, HeaderText: "BINGO", Items: []models.SnapshotItem{}})
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
+			if strings.Contains(sql, "FOR UPDATE") {
+				return rowFromValues(userID, false)
+			}
+			if strings.Contains(sql, "FROM bingo_cards") {
+				return db.QueryRow(ctx, sql, args...)
+			}
+			return rowFromValues(after)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE bingo_cards") {
+				return nil, &pgconn.PgError{Code: "23505", ConstraintName: "idx_bingo_cards_user_year_title"}
+			}
+			return fakeCommandTag{}, nil
+		},
+	}
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+
+	svc := NewCardService(db)
+	if _, err := svc.RestoreRevision(context.Background(), userID, cardID, uuid.New()); !errors.Is(err, ErrCardTitleExists) {
+		t.Fatalf("expected ErrCardTitleExists, got %v", err)
+	}
+}
+
+func TestCardService_ListTrash(t *testing.T) {
+	cardID := uuid.New()
+	snap, _ := json.Marshal(trashSnapshot{Card: models.BingoCard{
+		ID:       cardID,
+		Year:     2024,
+		GridSize: 4,
+		Items:    []models.BingoItem{{ID: uuid.New()}, {ID: uuid.New()}},
+	}})
+	deletedAt := time.Now().Add(-time.Hour)
+	db := &fakeDB{QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		return &fakeRows{rows: [][]any{{snap, deletedAt}}}, nil
+	}}
+
+	svc := NewCardService(db)
+	cards, err := svc.ListTrash(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cards) != 1 {
+		t.Fatalf("expected 1 trashed card, got %d", len(cards))
+	}
+	got := cards[0]
+	if got.ID != cardID || got.ItemCount != 2 || got.GridSize != 4 {
+		t.Fatalf("unexpected trashed card: %+v", got)
+	}
+	if !got.PurgeAt.Equal(deletedAt.Add(models.TrashRetention)) {
+		t.Fatalf("unexpected purge time: %v", got.PurgeAt)
+	}
+}
+
+func TestCardService_RestoreFromTrash_NotFound(t *testing.T) {
+	tx := &fakeTx{QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+		return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+	}}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+
+	svc := NewCardService(db)
+	if _, err := svc.RestoreFromTrash(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrTrashedCardNotFound) {
+		t.Fatalf("expected ErrTrashedCardNotFound, got %v", err)
+	}
+}
+
+func TestCardService_RestoreFromTrash_Conflict(t *testing.T) {
+	snap, _ := json.Marshal(trashSnapshot{Card: models.BingoCard{ID: uuid.New(), Year: 2024}})
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(snap)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return nil, &pgconn.PgError{Code: "23505", ConstraintName: "idx_bingo_cards_user_year_null_title"}
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+
+	svc := NewCardService(db)
+	if _, err := svc.RestoreFromTrash(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrCardAlreadyExists) {
+		t.Fatalf("expected ErrCardAlreadyExists, got %v", err)
+	}
+}
+
+func TestCardService_RestoreFromTrash_Success(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	groupID := uuid.New()
+	snap, _ := json.Marshal(trashSnapshot{
+		Card: models.BingoCard{
+			ID:         cardID,
+			Year:       2024,
+			GridSize:   5,
+			Visibility: models.VisibilityGroups,
+			Items:      []models.BingoItem{{ID: uuid.New(), Position: 1, Content: "a", IsCompleted: true}},
+		},
+		GroupIDs: []uuid.UUID{groupID},
+	})
+	var items, groups, removed bool
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(snap)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			switch {
+			case strings.Contains(sql, "INSERT INTO bingo_items"):
+				items = args[4] == true
+			case strings.Contains(sql, "INSERT INTO card_group_shares"):
+				ids := args[1].([]uuid.UUID)
+				groups = len(ids) == 1 && ids[0] == groupID && args[2] == userID
+			case strings.Contains(sql, "DELETE FROM card_trash"):
+				removed = true
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+
+	svc := NewCardService(db)
+	card, err := svc.RestoreFromTrash(context.Background(), userID, cardID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card.ID != cardID {
+		t.Fatalf("expected restored card %s, got %s", cardID, card.ID)
+	}
+	if !items || !groups || !removed {
+		t.Fatalf("expected items, groups and trash removal, got items=%v groups=%v removed=%v", items, groups, removed)
+	}
+}
+
+func TestCardService_PurgeTrash(t *testing.T) {
+	db := &fakeDB{ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		cutoff := args[0].(time.Time)
+		if time.Since(cutoff) < models.TrashRetention {
+			t.Fatalf("cutoff %v is newer than the retention period", cutoff)
+		}
+		return fakeCommandTag{rowsAffected: 3}, nil
+	}}
+
+	svc := NewCardService(db)
+	count, err := svc.PurgeTrash(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 purged, got %d", count)
+	}
+}
@@ -30,9 +30,11 @@ func TestCardService_Finalize_NotifiesFriendsWhenVisible(t *testing.T) {
 		true,
 		false,
 		true,
+		"friends",
 		false,
 		createdAt,
 		updatedAt,
+		int64(1),
 	}
 
 	items := []models.BingoItem{
@@ -45,6 +47,9 @@ func TestCardService_Finalize_NotifiesFriendsWhenVisible(t *testing.T) {
 	var notified bool
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
 			if strings.Contains(sql, "FROM bingo_cards") {
 				return rowFromValues(cardRow...)
 			}
@@ -55,7 +60,7 @@ func TestCardService_Finalize_NotifiesFriendsWhenVisible(t *testing.T) {
 			if strings.Contains(sql, "FROM bingo_items") {
 				rows := make([][]any, 0, len(items))
 				for _, item := range items {
-					rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, time.Now()})
+					rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, item.DueDate, item.ReminderDaysBefore, item.HiddenAt, time.Now()})
 				}
 				return &fakeRows{rows: rows}, nil
 			}
@@ -108,9 +113,11 @@ func TestCardService_CompleteItem_NotifiesBingo(t *testing.T) {
 		true,
 		true,
 		true,
+		"friends",
 		false,
 		createdAt,
 		updatedAt,
+		int64(1),
 	}
 
 	items := []models.BingoItem{
@@ -123,6 +130,9 @@ func TestCardService_CompleteItem_NotifiesBingo(t *testing.T) {
 	var notified bool
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
 			if strings.Contains(sql, "FROM bingo_cards") {
 				return rowFromValues(cardRow...)
 			}
@@ -133,7 +143,7 @@ func TestCardService_CompleteItem_NotifiesBingo(t *testing.T) {
 			if strings.Contains(sql, "FROM bingo_items") {
 				rows := make([][]any, 0, len(items))
 				for _, item := range items {
-					rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, time.Now()})
+					rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, item.DueDate, item.ReminderDaysBefore, item.HiddenAt, time.Now()})
 				}
 				return &fakeRows{rows: rows}, nil
 			}
@@ -29,9 +29,11 @@ func cardRowValues(cardID, userID uuid.UUID, gridSize int, hasFree bool, freePos
 		true,
 		finalized,
 		true,
+		"friends",
 		false,
 		now,
 		now,
+		int64(1),
 	}
 }
 
@@ -42,6 +44,9 @@ func lockCardRowValues(cardID, userID uuid.UUID, gridSize int, hasFree bool, fre
 func newCardDB(cardID, userID uuid.UUID, gridSize int, hasFree bool, freePos *int, finalized bool, items [][]any) *fakeDB {
 	return &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
 			if strings.Contains(sql, "FROM bingo_cards") {
 				return rowFromValues(cardRowValues(cardID, userID, gridSize, hasFree, freePos, finalized)...)
 			}
@@ -317,6 +322,9 @@ func TestCardService_AddItem_InvalidPosition(t *testing.T) {
 	free := 2
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
 			return rowFromValues(cardRowValues(cardID, userID, 2, true, &free, false)...)
 		},
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -340,7 +348,7 @@ func TestCardService_AddItem_PositionOccupied(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 2, false, nil, false, [][]any{
-		{uuid.New(), cardID, 0, "Item", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "Item", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	})
 
 	svc := NewCardService(db)
@@ -361,6 +369,9 @@ func TestCardService_UpdateMeta_TitleConflict(t *testing.T) {
 	title := "Conflicting"
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
 			if strings.Contains(sql, "SELECT EXISTS") {
 				return rowFromValues(true)
 			}
@@ -393,8 +404,9 @@ func TestCardService_Shuffle_EmptyItems(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
-	db.BeginFunc = func(ctx context.Context) (Tx, error) {
-		return nil, errors.New("begin should not be called")
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		t.Fatalf("unexpected exec for empty card: %s", sql)
+		return nil, nil
 	}
 
 	svc := NewCardService(db)
@@ -425,7 +437,7 @@ func TestCardService_UpdateVisibility_NotOwner(t *testing.T) {
 	db := newCardDB(cardID, uuid.New(), 5, true, nil, false, [][]any{})
 
 	svc := NewCardService(db)
-	_, err := svc.UpdateVisibility(context.Background(), userID, cardID, true)
+	_, err := svc.UpdateVisibility(context.Background(), userID, cardID, models.CardVisibilityParams{Visibility: models.VisibilityFriends})
 	if !errors.Is(err, ErrNotCardOwner) {
 		t.Fatalf("expected ErrNotCardOwner, got %v", err)
 	}
@@ -440,7 +452,7 @@ func TestCardService_BulkUpdateVisibility_Empty(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	updated, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), nil, true)
+	updated, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), nil, models.CardVisibilityParams{Visibility: models.VisibilityFriends})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -473,6 +485,79 @@ func TestCardService_UpdateItemNotes_NotOwner(t *testing.T) {
 	}
 }
 
+func TestCardService_UpdateItemSchedule_Validation(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
+	svc := NewCardService(db)
+
+	badDate := "2025-02-30"
+	goodDate := "2025-03-01"
+	tooMany := models.MaxReminderDaysBefore + 1
+	negative := -1
+
+	tests := []struct {
+		name   string
+		params models.UpdateItemScheduleParams
+		want   error
+	}{
+		{"invalid date", models.UpdateItemScheduleParams{DueDate: &badDate}, ErrInvalidDueDate},
+		{"reminder too far", models.UpdateItemScheduleParams{DueDate: &goodDate, ReminderDaysBefore: &tooMany}, ErrInvalidReminder},
+		{"negative reminder", models.UpdateItemScheduleParams{DueDate: &goodDate, ReminderDaysBefore: &negative}, ErrInvalidReminder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.UpdateItemSchedule(context.Background(), userID, cardID, 3, tt.params)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCardService_UpdateItemSchedule_ClearingDateClearsReminder(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	oldDate := "2025-06-01"
+	oldReminder := 3
+	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, &oldDate, &oldReminder, nil, time.Now()},
+	})
+	var updateArgs []any
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if strings.Contains(sql, "UPDATE bingo_items SET due_date") {
+			updateArgs = args
+		}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+
+	reminder := 2
+	svc := NewCardService(db)
+	item, err := svc.UpdateItemSchedule(context.Background(), userID, cardID, 3, models.UpdateItemScheduleParams{ReminderDaysBefore: &reminder})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.DueDate != nil || item.ReminderDaysBefore != nil {
+		t.Fatalf("expected due date and reminder cleared, got %v %v", item.DueDate, item.ReminderDaysBefore)
+	}
+	if len(updateArgs) != 3 || updateArgs[0].(*string) != nil || updateArgs[1].(*int) != nil {
+		t.Fatalf("expected NULL due date and reminder, got %v", updateArgs)
+	}
+}
+
+func TestCardService_UpdateItemSchedule_NotOwner(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, uuid.New(), 5, true, nil, false, [][]any{})
+
+	due := "2025-06-01"
+	svc := NewCardService(db)
+	_, err := svc.UpdateItemSchedule(context.Background(), userID, cardID, 3, models.UpdateItemScheduleParams{DueDate: &due})
+	if !errors.Is(err, ErrNotCardOwner) {
+		t.Fatalf("expected ErrNotCardOwner, got %v", err)
+	}
+}
+
 func TestCardService_UpdateConfig_NotOwner(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
@@ -633,6 +718,9 @@ func TestCardService_AddItem_Random_NotOwner(t *testing.T) {
 		BeginFunc: func(ctx context.Context) (Tx, error) {
 			return &fakeTx{
 				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if row, ok := cardLockRow(sql, uuid.New()); ok {
+						return row
+					}
 					return rowFromValues(lockCardRowValues(cardID, uuid.New(), 2, false, nil, false)...)
 				},
 			}, nil
@@ -656,6 +744,9 @@ func TestCardService_AddItem_Random_Finalized(t *testing.T) {
 		BeginFunc: func(ctx context.Context) (Tx, error) {
 			return &fakeTx{
 				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if row, ok := cardLockRow(sql, userID); ok {
+						return row
+					}
 					return rowFromValues(lockCardRowValues(cardID, userID, 2, false, nil, true)...)
 				},
 			}, nil
@@ -679,6 +770,9 @@ func TestCardService_AddItem_Random_CardFull(t *testing.T) {
 		BeginFunc: func(ctx context.Context) (Tx, error) {
 			return &fakeTx{
 				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if row, ok := cardLockRow(sql, userID); ok {
+						return row
+					}
 					return rowFromValues(lockCardRowValues(cardID, userID, 2, false, nil, false)...)
 				},
 				QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -731,6 +825,9 @@ func TestCardService_AddItem_Random_InsertConflict(t *testing.T) {
 		BeginFunc: func(ctx context.Context) (Tx, error) {
 			return &fakeTx{
 				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if row, ok := cardLockRow(sql, userID); ok {
+						return row
+					}
 					if strings.Contains(sql, "FOR UPDATE") {
 						return rowFromValues(lockCardRowValues(cardID, userID, 2, false, nil, false)...)
 					}
@@ -798,10 +895,10 @@ func TestCardService_UpdateConfig_NoSpaceForFree(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -924,7 +1021,7 @@ func TestCardService_Clone_BeginError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -942,7 +1039,7 @@ func TestCardService_Clone_CreateError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -966,7 +1063,7 @@ func TestCardService_Clone_CopyItemError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -991,7 +1088,7 @@ func TestCardService_Clone_CommitError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1070,7 +1167,7 @@ func TestCardService_GetByUserAndYear_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -1176,11 +1273,11 @@ func TestCardService_ListByUser_Success(t *testing.T) {
 	cardID2 := uuid.New()
 	items := map[uuid.UUID][][]any{
 		cardID: {
-			{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+			{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 		},
 		cardID2: {
-			{uuid.New(), cardID2, 1, "B", false, nil, nil, nil, time.Now()},
-			{uuid.New(), cardID2, 2, "C", false, nil, nil, nil, time.Now()},
+			{uuid.New(), cardID2, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+			{uuid.New(), cardID2, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
 		},
 	}
 
@@ -1284,6 +1381,9 @@ func TestCardService_UpdateMeta_TitleExists(t *testing.T) {
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
 	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if row, ok := cardLockRow(sql, userID); ok {
+			return row
+		}
 		if strings.Contains(sql, "EXISTS") {
 			return fakeRow{scanFunc: func(dest ...any) error {
 				return assignRow(dest, []any{true})
@@ -1484,12 +1584,21 @@ func TestCardService_UpdateVisibility_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
-	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-		return fakeCommandTag{rowsAffected: 1}, nil
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if row, ok := cardLockRow(sql, userID); ok {
+			return row
+		}
+		if strings.Contains(sql, "WITH updated AS") {
+			if args[0] != "friends" {
+				t.Fatalf("expected friends visibility, got %v", args[0])
+			}
+			return rowFromValues(1)
+		}
+		return rowFromValues(cardRowValues(cardID, userID, 5, true, nil, false)...)
 	}
 
 	svc := NewCardService(db)
-	card, err := svc.UpdateVisibility(context.Background(), userID, cardID, true)
+	card, err := svc.UpdateVisibility(context.Background(), userID, cardID, models.CardVisibilityParams{Visibility: models.VisibilityFriends})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1502,12 +1611,15 @@ func TestCardService_UpdateVisibility_DBError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
-	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-		return fakeCommandTag{}, errors.New("boom")
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "WITH updated AS") {
+			return fakeRow{scanFunc: func(dest ...any) error { return errors.New("boom") }}
+		}
+		return rowFromValues(cardRowValues(cardID, userID, 5, true, nil, false)...)
 	}
 
 	svc := NewCardService(db)
-	_, err := svc.UpdateVisibility(context.Background(), userID, cardID, true)
+	_, err := svc.UpdateVisibility(context.Background(), userID, cardID, models.CardVisibilityParams{Visibility: models.VisibilityFriends})
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -1515,13 +1627,16 @@ func TestCardService_UpdateVisibility_DBError(t *testing.T) {
 
 func TestCardService_BulkUpdateVisibility_Count(t *testing.T) {
 	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			return fakeCommandTag{rowsAffected: 3}, nil
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{}}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(3)
 		},
 	}
 
 	svc := NewCardService(db)
-	count, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []uuid.UUID{uuid.New(), uuid.New()}, true)
+	count, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []uuid.UUID{uuid.New(), uuid.New()}, models.CardVisibilityParams{Visibility: models.VisibilityFriends})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1532,71 +1647,81 @@ func TestCardService_BulkUpdateVisibility_Count(t *testing.T) {
 
 func TestCardService_BulkUpdateVisibility_Error(t *testing.T) {
 	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			return fakeCommandTag{}, errors.New("boom")
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{}}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return errors.New("boom") }}
 		},
 	}
 
 	svc := NewCardService(db)
-	_, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []uuid.UUID{uuid.New()}, true)
+	_, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []uuid.UUID{uuid.New()}, models.CardVisibilityParams{Visibility: models.VisibilityFriends})
 	if err == nil {
 		t.Fatal("expected error")
 	}
 }
 
 func TestCardService_BulkDelete_Count(t *testing.T) {
-	call := 0
-	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			call++
-			if call == 1 {
-				return fakeCommandTag{rowsAffected: 5}, nil
-			}
-			return fakeCommandTag{rowsAffected: 2}, nil
-		},
-	}
+	userID := uuid.New()
+	cardID := uuid.New()
+	var trashed, deletedCards bool
+	tx := newTrashTx(cardID, userID, func(sql string) (CommandTag, error) {
+		switch {
+		case strings.Contains(sql, "INSERT INTO card_trash"):
+			trashed = true
+		case strings.Contains(sql, "DELETE FROM bingo_cards"):
+			deletedCards = true
+			return fakeCommandTag{rowsAffected: 1}, nil
+		}
+		return fakeCommandTag{rowsAffected: 5}, nil
+	})
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
 
 	svc := NewCardService(db)
-	count, err := svc.BulkDelete(context.Background(), uuid.New(), []uuid.UUID{uuid.New()})
+	count, err := svc.BulkDelete(context.Background(), userID, []uuid.UUID{cardID, uuid.New()})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if count != 2 {
-		t.Fatalf("expected 2 deleted, got %d", count)
+	if count != 1 {
+		t.Fatalf("expected 1 deleted, got %d", count)
 	}
-	if call != 2 {
-		t.Fatalf("expected 2 exec calls, got %d", call)
+	if !trashed || !deletedCards {
+		t.Fatalf("expected card to be trashed then deleted, trashed=%v deleted=%v", trashed, deletedCards)
 	}
 }
 
 func TestCardService_BulkDelete_ItemsError(t *testing.T) {
-	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	tx := newTrashTx(cardID, userID, func(sql string) (CommandTag, error) {
+		if strings.Contains(sql, "DELETE FROM bingo_items") {
 			return fakeCommandTag{}, errors.New("items error")
-		},
-	}
+		}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	})
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
 
 	svc := NewCardService(db)
-	_, err := svc.BulkDelete(context.Background(), uuid.New(), []uuid.UUID{uuid.New()})
+	_, err := svc.BulkDelete(context.Background(), userID, []uuid.UUID{cardID})
 	if err == nil {
 		t.Fatal("expected error")
 	}
 }
 
 func TestCardService_BulkDelete_CardsError(t *testing.T) {
-	call := 0
-	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			call++
-			if call == 1 {
-				return fakeCommandTag{rowsAffected: 1}, nil
-			}
+	userID := uuid.New()
+	cardID := uuid.New()
+	tx := newTrashTx(cardID, userID, func(sql string) (CommandTag, error) {
+		if strings.Contains(sql, "DELETE FROM bingo_cards") {
 			return fakeCommandTag{}, errors.New("cards error")
-		},
-	}
+		}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	})
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
 
 	svc := NewCardService(db)
-	_, err := svc.BulkDelete(context.Background(), uuid.New(), []uuid.UUID{uuid.New()})
+	_, err := svc.BulkDelete(context.Background(), userID, []uuid.UUID{cardID})
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -1749,8 +1874,8 @@ func TestCardService_SwapItems_BothItems(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1772,8 +1897,8 @@ func TestCardService_SwapItems_MoveTempError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1795,8 +1920,8 @@ func TestCardService_SwapItems_BeginError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1814,7 +1939,7 @@ func TestCardService_SwapItems_SingleItem(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1836,7 +1961,7 @@ func TestCardService_SwapItems_SecondItemOnly(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 1, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1858,8 +1983,8 @@ func TestCardService_SwapItems_CommitError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1883,6 +2008,9 @@ func TestCardService_AddItem_Explicit_Success(t *testing.T) {
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 2, false, nil, false, [][]any{})
 	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if row, ok := cardLockRow(sql, userID); ok {
+			return row
+		}
 		if strings.Contains(sql, "FROM bingo_cards") {
 			return rowFromValues(cardRowValues(cardID, userID, 2, false, nil, false)...)
 		}
@@ -1919,6 +2047,9 @@ func TestCardService_AddItem_Explicit_InsertConflict(t *testing.T) {
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 2, false, nil, false, [][]any{})
 	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if row, ok := cardLockRow(sql, userID); ok {
+			return row
+		}
 		if strings.Contains(sql, "FROM bingo_cards") {
 			return rowFromValues(cardRowValues(cardID, userID, 2, false, nil, false)...)
 		}
@@ -1979,8 +2110,8 @@ func TestCardService_Shuffle_WithItems(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2002,8 +2133,8 @@ func TestCardService_Shuffle_ClearError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2025,8 +2156,8 @@ func TestCardService_Shuffle_AssignError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	call := 0
@@ -2053,7 +2184,7 @@ func TestCardService_Shuffle_CommitError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2076,7 +2207,7 @@ func TestCardService_Shuffle_BeginError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2094,7 +2225,7 @@ func TestCardService_Finalize_MissingItems(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -2121,10 +2252,10 @@ func TestCardService_Finalize_UpdateError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -2203,7 +2334,7 @@ func TestCardService_UpdateConfig_EnableFree_OddGrid(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 3, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2228,7 +2359,7 @@ func TestCardService_UpdateConfig_RelocateError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 4, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 4, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 3, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2318,7 +2449,7 @@ func TestCardService_CheckForConflict_Found(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -2432,7 +2563,7 @@ func TestCardService_GetArchive_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := &fakeDB{
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -2537,9 +2668,11 @@ func TestCardService_Import_Success(t *testing.T) {
 							true,
 							false,
 							true,
+							"friends",
 							false,
 							now,
 							now,
+							int64(1),
 						)
 					}
 					return rowFromValues(
@@ -2627,6 +2760,35 @@ func TestCardService_Import_CreateCardError(t *testing.T) {
 	}
 }
 
+func TestCardService_Import_TitleExists(t *testing.T) {
+	db := &fakeDB{
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			return &fakeTx{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					return fakeRow{scanFunc: func(dest ...any) error {
+						return &pgconn.PgError{Code: "23505", ConstraintName: "idx_bingo_cards_user_year_title"}
+					}}
+				},
+			}, nil
+		},
+	}
+
+	svc := NewCardService(db)
+	title := "Weekend goals"
+	_, err := svc.Import(context.Background(), models.ImportCardParams{
+		UserID:   uuid.New(),
+		Year:     2024,
+		Title:    &title,
+		GridSize: 2,
+		Items: []models.ImportItem{
+			{Position: 0, Content: "A"},
+		},
+	})
+	if !errors.Is(err, ErrCardTitleExists) {
+		t.Fatalf("expected ErrCardTitleExists, got %v", err)
+	}
+}
+
 func TestCardService_Import_CreateItemError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
@@ -2651,9 +2813,11 @@ func TestCardService_Import_CreateItemError(t *testing.T) {
 							true,
 							false,
 							true,
+							"friends",
 							false,
 							now,
 							now,
+							int64(1),
 						)
 					}
 					return fakeRow{scanFunc: func(dest ...any) error {
@@ -2702,9 +2866,11 @@ func TestCardService_Import_CommitError(t *testing.T) {
 							true,
 							false,
 							true,
+							"friends",
 							false,
 							now,
 							now,
+							int64(1),
 						)
 					}
 					return rowFromValues(
@@ -2745,10 +2911,10 @@ func TestCardService_GetStats_AllCompleted(t *testing.T) {
 	cardID := uuid.New()
 	now := time.Now()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", true, &now, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", true, &now, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", true, &now, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", true, &now, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 2, "C", true, &now, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 3, "D", true, &now, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, true, items)
 
@@ -2831,6 +2997,9 @@ func TestCardService_AddItem_Random_Success(t *testing.T) {
 		BeginFunc: func(ctx context.Context) (Tx, error) {
 			return &fakeTx{
 				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if row, ok := cardLockRow(sql, userID); ok {
+						return row
+					}
 					if strings.Contains(sql, "FOR UPDATE") {
 						return rowFromValues(lockCardRowValues(cardID, userID, 2, false, nil, false)...)
 					}
@@ -2867,10 +3036,10 @@ func TestCardService_Finalize_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -2891,14 +3060,28 @@ func TestCardService_Finalize_VisibleOverride(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	now := time.Now()
+	visibilitySet := false
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := cardLockRow(sql, userID); ok {
+				return row
+			}
+			if strings.Contains(sql, "WITH updated AS") {
+				if args[0] != "private" {
+					t.Fatalf("expected private visibility, got %v", args[0])
+				}
+				if groups := args[3].([]uuid.UUID); len(groups) != 0 {
+					t.Fatalf("expected group shares cleared, got %v", groups)
+				}
+				visibilitySet = true
+				return rowFromValues(1)
+			}
 			return rowFromValues(
 				cardID,
 				userID,
@@ -2911,19 +3094,18 @@ func TestCardService_Finalize_VisibleOverride(t *testing.T) {
 				nil,
 				true,
 				false,
-				true,
+				false,
+				"groups",
 				false,
 				now,
 				now,
+				int64(1),
 			)
 		},
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
 			return &fakeRows{rows: items}, nil
 		},
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			if len(args) < 2 || args[1] != false {
-				t.Fatalf("expected visibleToFriends=false, got %v", args)
-			}
 			return fakeCommandTag{rowsAffected: 1}, nil
 		},
 	}
@@ -2939,6 +3121,9 @@ func TestCardService_Finalize_VisibleOverride(t *testing.T) {
 	if card.VisibleToFriends {
 		t.Fatal("expected visibleToFriends false")
 	}
+	if !visibilitySet {
+		t.Fatal("expected visibility to be applied with its group shares")
+	}
 }
 
 func TestCardService_RemoveItem_Success(t *testing.T) {
@@ -2974,7 +3159,7 @@ func TestCardService_UpdateItem_ContentSuccess(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -2998,7 +3183,7 @@ func TestCardService_UpdateItem_ContentError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3019,7 +3204,7 @@ func TestCardService_UpdateItem_PositionError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3040,8 +3225,8 @@ func TestCardService_UpdateItem_PositionOccupied(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -3059,7 +3244,7 @@ func TestCardService_UpdateItem_PositionInvalid(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -3077,7 +3262,7 @@ func TestCardService_UpdateItem_PositionSuccess(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3101,10 +3286,10 @@ func TestCardService_CompleteItem_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, true, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3126,10 +3311,10 @@ func TestCardService_UncompleteItem_Success(t *testing.T) {
 	cardID := uuid.New()
 	now := time.Now()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, true, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3150,7 +3335,7 @@ func TestCardService_UpdateItemNotes_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3172,7 +3357,7 @@ func TestCardService_UpdateItemNotes_DBError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3191,8 +3376,8 @@ func TestCardService_SwapItems_FreeSpaceMoves(t *testing.T) {
 	cardID := uuid.New()
 	free := 0
 	items := [][]any{
-		{uuid.New(), cardID, 1, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 2, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 2, true, &free, false, items)
 	var movedFree bool
@@ -3229,7 +3414,7 @@ func TestCardService_UpdateConfig_CenterOccupiedRelocates(t *testing.T) {
 	cardID := uuid.New()
 	free := (*int)(nil)
 	items := [][]any{
-		{uuid.New(), cardID, 4, "Center", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 4, "Center", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	db := newCardDB(cardID, userID, 3, false, free, false, items)
 	var relocated bool
@@ -3292,14 +3477,12 @@ func TestCardService_Delete_NotFoundAfterDelete(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
-	call := 0
-	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-		call++
-		if call == 1 {
-			return fakeCommandTag{rowsAffected: 2}, nil
-		}
-		return fakeCommandTag{rowsAffected: 0}, nil
+	tx := &fakeTx{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{}}, nil
+		},
 	}
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 
 	svc := NewCardService(db)
 	err := svc.Delete(context.Background(), userID, cardID)
@@ -3312,9 +3495,13 @@ func TestCardService_Delete_ItemsError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
-	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-		return fakeCommandTag{}, errors.New("delete items error")
-	}
+	tx := newTrashTx(cardID, userID, func(sql string) (CommandTag, error) {
+		if strings.Contains(sql, "DELETE FROM bingo_items") {
+			return fakeCommandTag{}, errors.New("delete items error")
+		}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	})
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 
 	svc := NewCardService(db)
 	err := svc.Delete(context.Background(), userID, cardID)
@@ -3327,14 +3514,13 @@ func TestCardService_Delete_CardError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
-	call := 0
-	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-		call++
-		if call == 1 {
-			return fakeCommandTag{rowsAffected: 3}, nil
+	tx := newTrashTx(cardID, userID, func(sql string) (CommandTag, error) {
+		if strings.Contains(sql, "DELETE FROM bingo_cards") {
+			return fakeCommandTag{}, errors.New("delete card error")
 		}
-		return fakeCommandTag{}, errors.New("delete card error")
-	}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	})
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 
 	svc := NewCardService(db)
 	err := svc.Delete(context.Background(), userID, cardID)
@@ -3366,16 +3552,16 @@ func TestCardService_Clone_TruncatesItems(t *testing.T) {
 	free := 4
 	fallbackTitle := "2024 Bingo Card (Copy)"
 	sourceItems := [][]any{
-		{uuid.New(), sourceCardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), sourceCardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), sourceCardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), sourceCardID, 3, "D", false, nil, nil, nil, time.Now()},
-		{uuid.New(), sourceCardID, 5, "E", false, nil, nil, nil, time.Now()},
+		{uuid.New(), sourceCardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), sourceCardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), sourceCardID, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), sourceCardID, 3, "D", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), sourceCardID, 5, "E", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 	newItems := [][]any{
-		{uuid.New(), newCardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), newCardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), newCardID, 2, "C", false, nil, nil, nil, time.Now()},
+		{uuid.New(), newCardID, 0, "A", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), newCardID, 1, "B", false, nil, nil, nil, nil, nil, nil, time.Now()},
+		{uuid.New(), newCardID, 2, "C", false, nil, nil, nil, nil, nil, nil, time.Now()},
 	}
 
 	db := &fakeDB{
@@ -3420,9 +3606,11 @@ func TestCardService_Clone_TruncatesItems(t *testing.T) {
 						true,
 						false,
 						true,
+						"friends",
 						false,
 						time.Now(),
 						time.Now(),
+						int64(1),
 					)
 				},
 				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3738,3 +3926,95 @@ func TestValidItemPosition_Default5x5WithFree(t *testing.T) {
 		}
 	}
 }
+
+func TestCardService_UpdateVisibility_InvalidParams(t *testing.T) {
+	svc := NewCardService(&fakeDB{})
+
+	for _, params := range []models.CardVisibilityParams{
+		{Visibility: "everyone"},
+		{Visibility: models.VisibilityGroups},
+	} {
+		if _, err := svc.UpdateVisibility(context.Background(), uuid.New(), uuid.New(), params); !errors.Is(err, ErrInvalidVisibility) {
+			t.Fatalf("expected ErrInvalidVisibility for %+v, got %v", params, err)
+		}
+	}
+}
+
+func TestCardService_UpdateVisibility_Groups(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	groupID := uuid.New()
+	var sharedWith []uuid.UUID
+	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if row, ok := cardLockRow(sql, userID); ok {
+			return row
+		}
+		switch {
+		case strings.Contains(sql, "FROM friend_groups"):
+			return rowFromValues(len(args[0].([]uuid.UUID)))
+		case strings.Contains(sql, "WITH updated AS"):
+			if args[0] != "groups" {
+				t.Fatalf("expected groups visibility, got %v", args[0])
+			}
+			sharedWith = args[3].([]uuid.UUID)
+			return rowFromValues(1)
+		}
+		return rowFromValues(cardRowValues(cardID, userID, 5, true, nil, false)...)
+	}
+
+	svc := NewCardService(db)
+	card, err := svc.UpdateVisibility(context.Background(), userID, cardID, models.CardVisibilityParams{
+		Visibility: models.VisibilityGroups,
+		GroupIDs:   []uuid.UUID{groupID, groupID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card.VisibleToFriends || card.Visibility != models.VisibilityGroups {
+		t.Fatalf("expected group-only visibility, got %+v", card)
+	}
+	if len(sharedWith) != 1 || sharedWith[0] != groupID {
+		t.Fatalf("expected duplicate group IDs collapsed, got %v", sharedWith)
+	}
+}
+
+func TestCardService_UpdateVisibility_ForeignGroup(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, true, nil, false, [][]any{})
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if row, ok := cardLockRow(sql, userID); ok {
+			return row
+		}
+		switch {
+		case strings.Contains(sql, "FROM friend_groups"):
+			return rowFromValues(0)
+		case strings.Contains(sql, "WITH updated AS"):
+			t.Fatal("visibility must not change when a group isn't owned")
+		}
+		return rowFromValues(cardRowValues(cardID, userID, 5, true, nil, false)...)
+	}
+
+	svc := NewCardService(db)
+	_, err := svc.UpdateVisibility(context.Background(), userID, cardID, models.CardVisibilityParams{
+		Visibility: models.VisibilityGroups,
+		GroupIDs:   []uuid.UUID{uuid.New()},
+	})
+	if !errors.Is(err, ErrFriendGroupNotFound) {
+		t.Fatalf("expected ErrFriendGroupNotFound, got %v", err)
+	}
+}
+
+func TestCardService_GetByShareToken_NotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+
+	svc := NewCardService(db)
+	if _, err := svc.GetByShareToken(context.Background(), "missing"); !errors.Is(err, ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound, got %v", err)
+	}
+}
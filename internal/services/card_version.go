@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// ErrCardVersionConflict is returned when a card edit names a version the
+// card is no longer at.
+var ErrCardVersionConflict = errors.New("card has been changed since that version")
+
+// cardEditAttempts bounds how often an edit is retried after a deadlock or
+// serialization failure.
+const cardEditAttempts = 3
+
+// CardEdit carries optimistic concurrency details for one card edit: the
+// version the client last saw, if it sent one, the version its If-Match ETag
+// was taken at, and the card's version once the edit has been made.
+// PreconditionFailed is set when the edit was refused because of
+// MatchedVersion rather than ExpectedVersion.
+type CardEdit struct {
+	ExpectedVersion    *int64
+	MatchedVersion     *int64
+	NewVersion         int64
+	PreconditionFailed bool
+}
+
+type cardEditKey struct{}
+
+// WithCardEdit attaches edit to ctx so the card service checks and reports
+// versions for the edit made with it.
+func WithCardEdit(ctx context.Context, edit *CardEdit) context.Context {
+	return context.WithValue(ctx, cardEditKey{}, edit)
+}
+
+// CardEditFromContext returns the edit attached with WithCardEdit, or nil.
+func CardEditFromContext(ctx context.Context) *CardEdit {
+	edit, _ := ctx.Value(cardEditKey{}).(*CardEdit)
+	return edit
+}
+
+// editCard runs fn as a single edit of one card. The edit happens in a
+// transaction that first bumps the card's version, which also locks the row
+// until commit, so concurrent edits of the same card queue up instead of
+// overwriting each other. fn gets a CardService bound to that transaction;
+// revisions, activity and notifications it records are held back until the
+// edit commits. Deadlocks and serialization failures are retried.
+func editCard[T any](ctx context.Context, s *CardService, userID, cardID uuid.UUID, fn func(tx *CardService) (T, error)) (T, error) {
+	var zero T
+	edit := CardEditFromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		var result T
+		var version int64
+		var pending []func()
+		err := inTx(ctx, s.db, func(tx Tx) error {
+			var ownerID uuid.UUID
+			err := tx.QueryRow(ctx,
+				"UPDATE bingo_cards SET version = version + 1 WHERE id = $1 RETURNING user_id, version",
+				cardID,
+			).Scan(&ownerID, &version)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCardNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("locking card: %w", err)
+			}
+			if ownerID != userID {
+				return ErrNotCardOwner
+			}
+			if edit != nil && edit.ExpectedVersion != nil && *edit.ExpectedVersion != version-1 {
+				return ErrCardVersionConflict
+			}
+			if edit != nil && edit.MatchedVersion != nil && *edit.MatchedVersion != version-1 {
+				edit.PreconditionFailed = true
+				return ErrCardVersionConflict
+			}
+
+			bound := &CardService{
+				db:                  txDB{tx},
+				notificationService: s.notificationService,
+				activity:            s.activity,
+				committed:           s,
+				pending:             &pending,
+			}
+			result, err = fn(bound)
+			return err
+		})
+		if err != nil {
+			if attempt < cardEditAttempts && isRetryableTxError(err) {
+				continue
+			}
+			return zero, err
+		}
+
+		if edit != nil {
+			edit.NewVersion = version
+		}
+		for _, run := range pending {
+			run()
+		}
+		return result, nil
+	}
+}
+
+// isRetryableTxError reports whether err means Postgres gave up on the
+// transaction in a way that running it again can fix.
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+// afterCommit runs fn with the pool-backed service: straight away, or, for a
+// service bound to an edit's transaction, once that edit has committed.
+func (s *CardService) afterCommit(fn func(s *CardService)) {
+	if s.committed == nil {
+		fn(s)
+		return
+	}
+	committed := s.committed
+	*s.pending = append(*s.pending, func() { fn(committed) })
+}
+
+func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params models.AddItemParams) (*models.BingoItem, error) {
+	return editCard(ctx, s, userID, params.CardID, func(tx *CardService) (*models.BingoItem, error) {
+		return tx.addItem(ctx, userID, params)
+	})
+}
+
+func (s *CardService) UpdateItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoItem, error) {
+		return tx.updateItem(ctx, userID, cardID, position, params)
+	})
+}
+
+func (s *CardService) SwapItems(ctx context.Context, userID, cardID uuid.UUID, pos1, pos2 int) error {
+	_, err := editCard(ctx, s, userID, cardID, func(tx *CardService) (struct{}, error) {
+		return struct{}{}, tx.swapItems(ctx, userID, cardID, pos1, pos2)
+	})
+	return err
+}
+
+func (s *CardService) RemoveItem(ctx context.Context, userID, cardID uuid.UUID, position int) error {
+	_, err := editCard(ctx, s, userID, cardID, func(tx *CardService) (struct{}, error) {
+		return struct{}{}, tx.removeItem(ctx, userID, cardID, position)
+	})
+	return err
+}
+
+func (s *CardService) Delete(ctx context.Context, userID, cardID uuid.UUID) error {
+	_, err := editCard(ctx, s, userID, cardID, func(tx *CardService) (struct{}, error) {
+		return struct{}{}, tx.delete(ctx, userID, cardID)
+	})
+	return err
+}
+
+// UpdateMeta updates the category and/or title of a card
+func (s *CardService) UpdateMeta(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoCard, error) {
+		return tx.updateMeta(ctx, userID, cardID, params)
+	})
+}
+
+func (s *CardService) Shuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoCard, error) {
+		return tx.shuffle(ctx, userID, cardID)
+	})
+}
+
+func (s *CardService) Finalize(ctx context.Context, userID, cardID uuid.UUID, params *FinalizeParams) (*models.BingoCard, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoCard, error) {
+		return tx.finalize(ctx, userID, cardID, params)
+	})
+}
+
+// UpdateVisibility sets who can see a card: only its owner, all friends,
+// friends in the given groups, or anyone with its share link.
+func (s *CardService) UpdateVisibility(ctx context.Context, userID, cardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error) {
+	if err := validateVisibilityParams(params); err != nil {
+		return nil, err
+	}
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoCard, error) {
+		return tx.updateVisibility(ctx, userID, cardID, params)
+	})
+}
+
+func (s *CardService) CompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoItem, error) {
+		return tx.completeItem(ctx, userID, cardID, position, params)
+	})
+}
+
+func (s *CardService) UncompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoItem, error) {
+		return tx.uncompleteItem(ctx, userID, cardID, position)
+	})
+}
+
+func (s *CardService) UpdateItemNotes(ctx context.Context, userID, cardID uuid.UUID, position int, notes, proofURL *string) (*models.BingoItem, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoItem, error) {
+		return tx.updateItemNotes(ctx, userID, cardID, position, notes, proofURL)
+	})
+}
+
+func (s *CardService) UpdateItemSchedule(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemScheduleParams) (*models.BingoItem, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoItem, error) {
+		return tx.updateItemSchedule(ctx, userID, cardID, position, params)
+	})
+}
+
+func (s *CardService) UpdateConfig(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardConfigParams) (*models.BingoCard, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoCard, error) {
+		return tx.updateConfig(ctx, userID, cardID, params)
+	})
+}
+
+// Undo reverts the most recent revision that has not already been undone.
+// Undoing repeatedly walks further back through the history.
+func (s *CardService) Undo(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoCard, error) {
+		return tx.undo(ctx, userID, cardID)
+	})
+}
+
+// RestoreRevision puts a card back to the state it was in right after the
+// given revision. The restore is itself recorded, so it can be undone.
+func (s *CardService) RestoreRevision(ctx context.Context, userID, cardID, revisionID uuid.UUID) (*models.BingoCard, error) {
+	return editCard(ctx, s, userID, cardID, func(tx *CardService) (*models.BingoCard, error) {
+		return tx.restoreRevision(ctx, userID, cardID, revisionID)
+	})
+}
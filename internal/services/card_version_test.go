@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// cardLockRow answers the version bump each card edit starts with, as if
+// the card belonged to ownerID and was at version 1.
+func cardLockRow(sql string, ownerID uuid.UUID) (Row, bool) {
+	if !strings.Contains(sql, "SET version = version + 1") {
+		return nil, false
+	}
+	return rowFromValues(ownerID, int64(2)), true
+}
+
+func TestCardService_Edit_ReportsNewVersion(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+
+	expected := int64(1)
+	edit := &CardEdit{ExpectedVersion: &expected}
+	ctx := WithCardEdit(context.Background(), edit)
+	category := "health"
+	if _, err := NewCardService(db).UpdateMeta(ctx, userID, cardID, models.UpdateCardMetaParams{Category: &category}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if edit.NewVersion != 2 {
+		t.Fatalf("expected new version 2, got %d", edit.NewVersion)
+	}
+}
+
+func TestCardService_Edit_VersionConflict(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		t.Fatalf("unexpected exec after version conflict: %s", sql)
+		return nil, nil
+	}
+	var rolledBack bool
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		return &fakeTx{
+			CommitFunc: func(ctx context.Context) error {
+				t.Fatal("unexpected commit after version conflict")
+				return nil
+			},
+			RollbackFunc: func(ctx context.Context) error {
+				rolledBack = true
+				return nil
+			},
+		}, nil
+	}
+
+	stale := int64(0)
+	ctx := WithCardEdit(context.Background(), &CardEdit{ExpectedVersion: &stale})
+	err := NewCardService(db).RemoveItem(ctx, userID, cardID, 3)
+	if !errors.Is(err, ErrCardVersionConflict) {
+		t.Fatalf("expected ErrCardVersionConflict, got %v", err)
+	}
+	if !rolledBack {
+		t.Fatal("expected the version bump to be rolled back")
+	}
+}
+
+func TestCardService_Edit_MatchedVersionRace(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+
+	// Each transaction holds the card row from its version bump until it
+	// ends, the way Postgres does, and only a committed bump sticks.
+	var rowLock, state sync.Mutex
+	version := int64(1)
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		var release sync.Once
+		var bumped int64
+		unlock := func() { release.Do(rowLock.Unlock) }
+		return &fakeTx{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				if !strings.Contains(sql, "SET version = version + 1") {
+					return db.QueryRowFunc(ctx, sql, args...)
+				}
+				rowLock.Lock()
+				state.Lock()
+				bumped = version + 1
+				state.Unlock()
+				return rowFromValues(userID, bumped)
+			},
+			CommitFunc: func(ctx context.Context) error {
+				state.Lock()
+				version = bumped
+				state.Unlock()
+				unlock()
+				return nil
+			},
+			RollbackFunc: func(ctx context.Context) error {
+				unlock()
+				return nil
+			},
+		}, nil
+	}
+
+	svc := NewCardService(db)
+	edits := []*CardEdit{{}, {}}
+	errs := make([]error, len(edits))
+	var wg sync.WaitGroup
+	for i, edit := range edits {
+		loaded := int64(1)
+		edit.MatchedVersion = &loaded
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			category := "health"
+			_, errs[i] = svc.UpdateMeta(WithCardEdit(context.Background(), edit), userID, cardID, models.UpdateCardMetaParams{Category: &category})
+		}()
+	}
+	wg.Wait()
+
+	var succeeded, refused int
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrCardVersionConflict) && edits[i].PreconditionFailed:
+			refused++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if succeeded != 1 || refused != 1 || version != 2 {
+		t.Fatalf("expected exactly one edit to win, got %d succeeded, %d refused, version %d", succeeded, refused, version)
+	}
+}
+
+func TestCardService_Edit_LockErrors(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+
+	db := &fakeDB{QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+		return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+	}}
+	if err := NewCardService(db).Delete(context.Background(), userID, cardID); !errors.Is(err, ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound, got %v", err)
+	}
+
+	db = newCardDB(cardID, uuid.New(), 5, false, nil, false, nil)
+	if _, err := NewCardService(db).Shuffle(context.Background(), userID, cardID); !errors.Is(err, ErrNotCardOwner) {
+		t.Fatalf("expected ErrNotCardOwner, got %v", err)
+	}
+}
+
+func TestCardService_Edit_RetriesDeadlock(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, false, nil, false, nil)
+
+	attempts := 0
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if strings.Contains(sql, "UPDATE bingo_cards SET category") {
+			attempts++
+			if attempts == 1 {
+				return nil, &pgconn.PgError{Code: "40P01"}
+			}
+		}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+
+	category := "health"
+	if _, err := NewCardService(db).UpdateMeta(context.Background(), userID, cardID, models.UpdateCardMetaParams{Category: &category}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected the edit to be retried once, got %d attempts", attempts)
+	}
+}
+
+func TestCardService_Edit_DefersSideEffectsUntilCommit(t *testing.T) {
+	userID, cardID := uuid.New(), uuid.New()
+	recorder := &fakeActivityRecorder{}
+	db := finalizedCardDB(userID, cardID, true)
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		return &fakeTx{CommitFunc: func(ctx context.Context) error {
+			if len(recorder.recorded) != 0 {
+				t.Fatal("expected activity to wait for the commit")
+			}
+			return errors.New("connection lost")
+		}}, nil
+	}
+	svc := NewCardService(db)
+	svc.SetActivityRecorder(recorder)
+
+	if _, err := svc.CompleteItem(context.Background(), userID, cardID, 1, models.CompleteItemParams{}); err == nil {
+		t.Fatal("expected commit failure")
+	}
+	if len(recorder.recorded) != 0 {
+		t.Fatalf("expected no activity for a failed edit, got %+v", recorder.recorded)
+	}
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	if !isRetryableTxError(fmt.Errorf("updating card: %w", &pgconn.PgError{Code: "40001"})) {
+		t.Fatal("expected serialization failures to be retried")
+	}
+	if isRetryableTxError(&pgconn.PgError{Code: "23505"}) || isRetryableTxError(errors.New("boom")) {
+		t.Fatal("expected other errors not to be retried")
+	}
+}
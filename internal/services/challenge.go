@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrChallengeNotFound     = errors.New("challenge not found")
+	ErrInvalidChallengeName  = errors.New("invalid challenge name")
+	ErrInvalidChallengeDates = errors.New("invalid challenge dates")
+	ErrTooManyParticipants   = errors.New("too many challenge participants")
+	ErrChallengeEnded        = errors.New("challenge has ended")
+	ErrChallengeCreatorLeave = errors.New("the creator cannot leave their own challenge")
+	ErrNotChallengeCreator   = errors.New("only the creator can delete this challenge")
+)
+
+// challengeAnnounceBatchSize caps how many finished challenges one
+// AnnounceFinished call claims.
+const challengeAnnounceBatchSize = 50
+
+type ChallengeService struct {
+	db                  DB
+	friendService       FriendChecker
+	notificationService NotificationServiceInterface
+}
+
+func NewChallengeService(db DB, friendService FriendChecker) *ChallengeService {
+	return &ChallengeService{db: db, friendService: friendService}
+}
+
+func (s *ChallengeService) SetNotificationService(notificationService NotificationServiceInterface) {
+	s.notificationService = notificationService
+}
+
+const challengeColumns = `ch.id, ch.creator_id, cu.username, ch.name, ch.starts_at, ch.ends_at, ch.announced_at, ch.created_at,
+		        p.joined_at IS NOT NULL,
+		        (SELECT COUNT(*) FROM challenge_participants cp WHERE cp.challenge_id = ch.id AND cp.joined_at IS NOT NULL)`
+
+func scanChallenge(row Row, c *models.Challenge) error {
+	if err := row.Scan(&c.ID, &c.CreatorID, &c.CreatorUsername, &c.Name, &c.StartsAt, &c.EndsAt, &c.AnnouncedAt, &c.CreatedAt, &c.Joined, &c.ParticipantCount); err != nil {
+		return err
+	}
+	c.Status = c.StatusAt(time.Now())
+	return nil
+}
+
+// Create starts a challenge between the creator and some of their friends.
+// The creator is in from the start; invited friends have to join.
+func (s *ChallengeService) Create(ctx context.Context, creatorID uuid.UUID, params models.CreateChallengeParams) (*models.Challenge, error) {
+	name := strings.TrimSpace(params.Name)
+	if name == "" || utf8.RuneCountInString(name) > models.MaxChallengeNameLength {
+		return nil, ErrInvalidChallengeName
+	}
+	if !params.EndsAt.After(params.StartsAt) || !params.EndsAt.After(time.Now()) ||
+		params.EndsAt.Sub(params.StartsAt) > models.MaxChallengeDuration {
+		return nil, ErrInvalidChallengeDates
+	}
+
+	seen := map[uuid.UUID]bool{creatorID: true}
+	var invitees []uuid.UUID
+	for _, id := range params.FriendIDs {
+		if !seen[id] {
+			seen[id] = true
+			invitees = append(invitees, id)
+		}
+	}
+	if len(invitees)+1 > models.MaxChallengeParticipants {
+		return nil, ErrTooManyParticipants
+	}
+	for _, id := range invitees {
+		isFriend, err := s.friendService.IsFriend(ctx, creatorID, id)
+		if err != nil {
+			return nil, err
+		}
+		if !isFriend {
+			return nil, ErrNotFriend
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin challenge transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	challenge := &models.Challenge{
+		CreatorID:        creatorID,
+		Name:             name,
+		StartsAt:         params.StartsAt,
+		EndsAt:           params.EndsAt,
+		Joined:           true,
+		ParticipantCount: 1,
+	}
+	err = tx.QueryRow(ctx,
+		`WITH inserted AS (
+		   INSERT INTO challenges (creator_id, name, starts_at, ends_at)
+		   VALUES ($1, $2, $3, $4)
+		   RETURNING id, creator_id, created_at
+		 )
+		 SELECT i.id, i.created_at, u.username
+		 FROM inserted i
+		 JOIN users u ON u.id = i.creator_id`,
+		creatorID, name, params.StartsAt, params.EndsAt,
+	).Scan(&challenge.ID, &challenge.CreatedAt, &challenge.CreatorUsername)
+	if err != nil {
+		return nil, fmt.Errorf("creating challenge: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO challenge_participants (challenge_id, user_id, joined_at)
+		 VALUES ($1, $2, NOW())`,
+		challenge.ID, creatorID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("adding challenge creator: %w", err)
+	}
+	if len(invitees) > 0 {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO challenge_participants (challenge_id, user_id)
+			 SELECT $1, unnest($2::uuid[])`,
+			challenge.ID, invitees,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("inviting challenge participants: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit challenge: %w", err)
+	}
+	committed = true
+
+	challenge.Status = challenge.StatusAt(time.Now())
+	return challenge, nil
+}
+
+// List returns the challenges the user has joined or been invited to, most
+// recently ending first.
+func (s *ChallengeService) List(ctx context.Context, userID uuid.UUID) ([]models.Challenge, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT `+challengeColumns+`
+		 FROM challenge_participants p
+		 JOIN challenges ch ON ch.id = p.challenge_id
+		 JOIN users cu ON cu.id = ch.creator_id
+		 WHERE p.user_id = $1
+		 ORDER BY ch.ends_at DESC, ch.id`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []models.Challenge{}
+	for rows.Next() {
+		var c models.Challenge
+		if err := scanChallenge(rows, &c); err != nil {
+			return nil, fmt.Errorf("scanning challenge: %w", err)
+		}
+		challenges = append(challenges, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing challenges: %w", err)
+	}
+	return challenges, nil
+}
+
+// Get returns a challenge with its participants and current standings. Only
+// participants, including those still invited, can see it.
+func (s *ChallengeService) Get(ctx context.Context, userID, challengeID uuid.UUID) (*models.ChallengeDetail, error) {
+	detail := &models.ChallengeDetail{}
+	err := scanChallenge(s.db.QueryRow(ctx,
+		`SELECT `+challengeColumns+`
+		 FROM challenge_participants p
+		 JOIN challenges ch ON ch.id = p.challenge_id
+		 JOIN users cu ON cu.id = ch.creator_id
+		 WHERE p.user_id = $1 AND ch.id = $2`,
+		userID, challengeID,
+	), &detail.Challenge)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting challenge: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT p.user_id, u.username, p.joined_at IS NOT NULL
+		 FROM challenge_participants p
+		 JOIN users u ON u.id = p.user_id
+		 WHERE p.challenge_id = $1
+		 ORDER BY LOWER(u.username)`,
+		challengeID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing challenge participants: %w", err)
+	}
+	defer rows.Close()
+
+	detail.Participants = []models.ChallengeParticipant{}
+	for rows.Next() {
+		var p models.ChallengeParticipant
+		if err := rows.Scan(&p.UserID, &p.Username, &p.Joined); err != nil {
+			return nil, fmt.Errorf("scanning challenge participant: %w", err)
+		}
+		detail.Participants = append(detail.Participants, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing challenge participants: %w", err)
+	}
+
+	detail.Standings, err = s.standings(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// standings scores joined participants on items completed within the
+// challenge window, breaking ties in favour of whoever got there first. Only
+// cards shared with all friends count, and archived cards don't: participants
+// needn't be friends with each other or in the same groups, and standings
+// are the same for every viewer and for the result announcement.
+func (s *ChallengeService) standings(ctx context.Context, challengeID uuid.UUID) ([]models.ChallengeStanding, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT u.id, u.username, COUNT(bi.id), MAX(bi.completed_at)
+		 FROM challenge_participants p
+		 JOIN challenges ch ON ch.id = p.challenge_id
+		 JOIN users u ON u.id = p.user_id
+		 LEFT JOIN bingo_cards bc ON bc.user_id = p.user_id AND bc.is_finalized
+		   AND bc.visible_to_friends AND NOT bc.is_archived
+		 LEFT JOIN bingo_items bi ON bi.card_id = bc.id AND bi.is_completed
+		   AND bi.completed_at >= ch.starts_at AND bi.completed_at < ch.ends_at
+		 WHERE p.challenge_id = $1 AND p.joined_at IS NOT NULL
+		 GROUP BY u.id, u.username
+		 ORDER BY COUNT(bi.id) DESC, MAX(bi.completed_at) ASC NULLS LAST, LOWER(u.username)`,
+		challengeID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("computing challenge standings: %w", err)
+	}
+	defer rows.Close()
+
+	standings := []models.ChallengeStanding{}
+	for rows.Next() {
+		var st models.ChallengeStanding
+		if err := rows.Scan(&st.UserID, &st.Username, &st.Completions, &st.LastCompletion); err != nil {
+			return nil, fmt.Errorf("scanning challenge standing: %w", err)
+		}
+		st.Rank = len(standings) + 1
+		standings = append(standings, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("computing challenge standings: %w", err)
+	}
+	return standings, nil
+}
+
+// challengeMembership is the viewer's place in a challenge.
+type challengeMembership struct {
+	creatorID uuid.UUID
+	endsAt    time.Time
+	joined    bool
+}
+
+func (s *ChallengeService) membership(ctx context.Context, userID, challengeID uuid.UUID) (*challengeMembership, error) {
+	m := &challengeMembership{}
+	err := s.db.QueryRow(ctx,
+		`SELECT ch.creator_id, ch.ends_at, p.joined_at IS NOT NULL
+		 FROM challenge_participants p
+		 JOIN challenges ch ON ch.id = p.challenge_id
+		 WHERE p.user_id = $1 AND ch.id = $2`,
+		userID, challengeID,
+	).Scan(&m.creatorID, &m.endsAt, &m.joined)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting challenge membership: %w", err)
+	}
+	return m, nil
+}
+
+// Join accepts an invitation. Joining twice is a no-op.
+func (s *ChallengeService) Join(ctx context.Context, userID, challengeID uuid.UUID) error {
+	m, err := s.membership(ctx, userID, challengeID)
+	if err != nil {
+		return err
+	}
+	if m.joined {
+		return nil
+	}
+	if !time.Now().Before(m.endsAt) {
+		return ErrChallengeEnded
+	}
+
+	_, err = s.db.Exec(ctx,
+		`UPDATE challenge_participants SET joined_at = NOW()
+		 WHERE challenge_id = $1 AND user_id = $2 AND joined_at IS NULL`,
+		challengeID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("joining challenge: %w", err)
+	}
+	return nil
+}
+
+// Leave declines an invitation or drops out of a challenge. The creator
+// deletes the challenge instead.
+func (s *ChallengeService) Leave(ctx context.Context, userID, challengeID uuid.UUID) error {
+	m, err := s.membership(ctx, userID, challengeID)
+	if err != nil {
+		return err
+	}
+	if m.creatorID == userID {
+		return ErrChallengeCreatorLeave
+	}
+
+	_, err = s.db.Exec(ctx,
+		"DELETE FROM challenge_participants WHERE challenge_id = $1 AND user_id = $2",
+		challengeID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("leaving challenge: %w", err)
+	}
+	return nil
+}
+
+func (s *ChallengeService) Delete(ctx context.Context, userID, challengeID uuid.UUID) error {
+	m, err := s.membership(ctx, userID, challengeID)
+	if err != nil {
+		return err
+	}
+	if m.creatorID != userID {
+		return ErrNotChallengeCreator
+	}
+
+	if _, err := s.db.Exec(ctx, "DELETE FROM challenges WHERE id = $1", challengeID); err != nil {
+		return fmt.Errorf("deleting challenge: %w", err)
+	}
+	return nil
+}
+
+// AnnounceFinished marks challenges that have ended as announced and notifies
+// their participants of the result. Claiming rows with SKIP LOCKED lets
+// several servers run it at once without announcing anything twice. It
+// returns how many challenges were announced.
+func (s *ChallengeService) AnnounceFinished(ctx context.Context) (int, error) {
+	rows, err := s.db.Query(ctx,
+		`UPDATE challenges SET announced_at = NOW()
+		 WHERE id IN (
+		   SELECT id FROM challenges
+		   WHERE announced_at IS NULL AND ends_at <= NOW()
+		   ORDER BY ends_at
+		   LIMIT $1
+		   FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id`,
+		challengeAnnounceBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("claiming finished challenges: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning finished challenge: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("claiming finished challenges: %w", err)
+	}
+
+	for _, id := range ids {
+		s.announce(ctx, id)
+	}
+	return len(ids), nil
+}
+
+func (s *ChallengeService) announce(ctx context.Context, challengeID uuid.UUID) {
+	if s.notificationService == nil {
+		return
+	}
+
+	standings, err := s.standings(ctx, challengeID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to compute challenge result", map[string]interface{}{
+			"error":        err.Error(),
+			"challenge_id": challengeID.String(),
+		})
+		return
+	}
+
+	var winnerID *uuid.UUID
+	if len(standings) > 0 && standings[0].Completions > 0 {
+		winnerID = &standings[0].UserID
+	}
+	if err := s.notificationService.NotifyChallengeResult(ctx, challengeID, winnerID); err != nil {
+		logging.FromContext(ctx).Error("Failed to notify about challenge result", map[string]interface{}{
+			"error":        err.Error(),
+			"challenge_id": challengeID.String(),
+		})
+	}
+}
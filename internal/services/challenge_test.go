@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func validChallengeParams() models.CreateChallengeParams {
+	return models.CreateChallengeParams{
+		Name:     "Spring sprint",
+		StartsAt: time.Now(),
+		EndsAt:   time.Now().Add(30 * 24 * time.Hour),
+	}
+}
+
+func TestChallengeService_Create_Validation(t *testing.T) {
+	tooMany := make([]uuid.UUID, models.MaxChallengeParticipants)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(p *models.CreateChallengeParams)
+		want   error
+	}{
+		{name: "blank name", mutate: func(p *models.CreateChallengeParams) { p.Name = "  " }, want: ErrInvalidChallengeName},
+		{name: "long name", mutate: func(p *models.CreateChallengeParams) { p.Name = strings.Repeat("a", 101) }, want: ErrInvalidChallengeName},
+		{name: "ends before start", mutate: func(p *models.CreateChallengeParams) { p.EndsAt = p.StartsAt.Add(-time.Hour) }, want: ErrInvalidChallengeDates},
+		{name: "already over", mutate: func(p *models.CreateChallengeParams) {
+			p.StartsAt = time.Now().Add(-48 * time.Hour)
+			p.EndsAt = time.Now().Add(-24 * time.Hour)
+		}, want: ErrInvalidChallengeDates},
+		{name: "too long", mutate: func(p *models.CreateChallengeParams) { p.EndsAt = p.StartsAt.Add(400 * 24 * time.Hour) }, want: ErrInvalidChallengeDates},
+		{name: "too many friends", mutate: func(p *models.CreateChallengeParams) { p.FriendIDs = tooMany }, want: ErrTooManyParticipants},
+		{name: "not a friend", mutate: func(p *models.CreateChallengeParams) { p.FriendIDs = []uuid.UUID{uuid.New()} }, want: ErrNotFriend},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validChallengeParams()
+			tt.mutate(&params)
+			service := NewChallengeService(&fakeDB{}, &fakeFriendChecker{isFriend: false})
+
+			_, err := service.Create(context.Background(), uuid.New(), params)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestChallengeService_Create_InvitesFriends(t *testing.T) {
+	creatorID := uuid.New()
+	friendID := uuid.New()
+	var invited []uuid.UUID
+	committed := false
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(uuid.New(), time.Now(), "creator")
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "unnest") {
+				invited = args[1].([]uuid.UUID)
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		CommitFunc: func(ctx context.Context) error {
+			committed = true
+			return nil
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	service := NewChallengeService(db, &fakeFriendChecker{isFriend: true})
+
+	params := validChallengeParams()
+	// Duplicates and the creator are dropped from the invite list.
+	params.FriendIDs = []uuid.UUID{friendID, friendID, creatorID}
+	challenge, err := service.Create(context.Background(), creatorID, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !committed {
+		t.Fatal("expected transaction to commit")
+	}
+	if len(invited) != 1 || invited[0] != friendID {
+		t.Fatalf("expected only the friend invited, got %v", invited)
+	}
+	if challenge.CreatorUsername != "creator" || !challenge.Joined || challenge.Status != models.ChallengeActive {
+		t.Fatalf("unexpected challenge: %+v", challenge)
+	}
+}
+
+func membershipDB(creatorID uuid.UUID, endsAt time.Time, joined bool, execs *[]string) *fakeDB {
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(creatorID, endsAt, joined)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			*execs = append(*execs, sql)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+}
+
+func TestChallengeService_Join(t *testing.T) {
+	userID := uuid.New()
+
+	var execs []string
+	service := NewChallengeService(membershipDB(uuid.New(), time.Now().Add(-time.Hour), false, &execs), &fakeFriendChecker{})
+	if err := service.Join(context.Background(), userID, uuid.New()); !errors.Is(err, ErrChallengeEnded) {
+		t.Fatalf("expected ErrChallengeEnded, got %v", err)
+	}
+
+	service = NewChallengeService(membershipDB(uuid.New(), time.Now().Add(time.Hour), false, &execs), &fakeFriendChecker{})
+	if err := service.Join(context.Background(), userID, uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(execs) != 1 || !strings.Contains(execs[0], "joined_at = NOW()") {
+		t.Fatalf("expected join update, got %v", execs)
+	}
+}
+
+func TestChallengeService_Join_NotParticipant(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	service := NewChallengeService(db, &fakeFriendChecker{})
+
+	if err := service.Join(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrChallengeNotFound) {
+		t.Fatalf("expected ErrChallengeNotFound, got %v", err)
+	}
+}
+
+func TestChallengeService_LeaveAndDelete_Permissions(t *testing.T) {
+	creatorID := uuid.New()
+	var execs []string
+	service := NewChallengeService(membershipDB(creatorID, time.Now().Add(time.Hour), true, &execs), &fakeFriendChecker{})
+
+	if err := service.Leave(context.Background(), creatorID, uuid.New()); !errors.Is(err, ErrChallengeCreatorLeave) {
+		t.Fatalf("expected ErrChallengeCreatorLeave, got %v", err)
+	}
+	if err := service.Delete(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrNotChallengeCreator) {
+		t.Fatalf("expected ErrNotChallengeCreator, got %v", err)
+	}
+	if len(execs) != 0 {
+		t.Fatalf("expected no writes, got %v", execs)
+	}
+
+	if err := service.Leave(context.Background(), uuid.New(), uuid.New()); err != nil {
+		t.Fatalf("unexpected error leaving: %v", err)
+	}
+	if err := service.Delete(context.Background(), creatorID, uuid.New()); err != nil {
+		t.Fatalf("unexpected error deleting: %v", err)
+	}
+	if len(execs) != 2 {
+		t.Fatalf("expected leave and delete writes, got %v", execs)
+	}
+}
+
+func TestChallengeService_AnnounceFinished(t *testing.T) {
+	wonID := uuid.New()
+	emptyID := uuid.New()
+	winnerID := uuid.New()
+	now := time.Now()
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			switch {
+			case strings.Contains(sql, "SKIP LOCKED"):
+				return &fakeRows{rows: [][]any{{wonID}, {emptyID}}}, nil
+			case args[0] == wonID:
+				if !strings.Contains(sql, "bc.visible_to_friends AND NOT bc.is_archived") {
+					t.Fatalf("expected standings to skip private, group-only and archived cards: %s", sql)
+				}
+				return &fakeRows{rows: [][]any{
+					{winnerID, "winner", 5, &now},
+					{uuid.New(), "runner-up", 2, &now},
+				}}, nil
+			default:
+				return &fakeRows{rows: [][]any{{uuid.New(), "idle", 0, nil}}}, nil
+			}
+		},
+	}
+	winners := map[uuid.UUID]*uuid.UUID{}
+	service := NewChallengeService(db, &fakeFriendChecker{})
+	service.SetNotificationService(&stubNotificationService{
+		NotifyChallengeResultFunc: func(ctx context.Context, challengeID uuid.UUID, winner *uuid.UUID) error {
+			winners[challengeID] = winner
+			return nil
+		},
+	})
+
+	count, err := service.AnnounceFinished(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 || len(winners) != 2 {
+		t.Fatalf("expected 2 announcements, got %d (%v)", count, winners)
+	}
+	if w := winners[wonID]; w == nil || *w != winnerID {
+		t.Fatalf("expected %s to win, got %v", winnerID, w)
+	}
+	if w := winners[emptyID]; w != nil {
+		t.Fatalf("expected no winner without completions, got %v", *w)
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrCommentEmpty        = errors.New("comment cannot be empty")
+	ErrCommentTooLong      = errors.New("comment is too long")
+	ErrNotCommentAuthor    = errors.New("only the author can edit this comment")
+	ErrCommentNotPermitted = errors.New("not allowed to delete this comment")
+)
+
+type CommentService struct {
+	db                  DBConn
+	friendService       FriendChecker
+	notificationService NotificationServiceInterface
+}
+
+func NewCommentService(db DBConn, friendService FriendChecker) *CommentService {
+	return &CommentService{
+		db:            db,
+		friendService: friendService,
+	}
+}
+
+func (s *CommentService) SetNotificationService(notificationService NotificationServiceInterface) {
+	s.notificationService = notificationService
+}
+
+// commentItem is the item a comment is attached to along with the card facts
+// needed for access checks.
+type commentItem struct {
+	cardID      uuid.UUID
+	ownerID     uuid.UUID
+	visible     bool
+	isFinalized bool
+	isArchived  bool
+}
+
+// checkItemAccess loads a visible item and confirms the user may read and
+// write comments on it: the card owner always can, friends can when the card
+// is finalized, shared with them and not archived. Blocked pairs are turned
+// away.
+func (s *CommentService) checkItemAccess(ctx context.Context, userID, itemID uuid.UUID) (*commentItem, error) {
+	item := &commentItem{}
+	err := s.db.QueryRow(ctx,
+		`SELECT bc.id, bc.user_id, `+cardVisibleToSQL("bc", "$2")+`, bc.is_finalized, bc.is_archived
+		 FROM bingo_items bi
+		 JOIN bingo_cards bc ON bi.card_id = bc.id
+		 WHERE bi.id = $1 AND bi.hidden_at IS NULL`,
+		itemID, userID,
+	).Scan(&item.cardID, &item.ownerID, &item.visible, &item.isFinalized, &item.isArchived)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting item info: %w", err)
+	}
+
+	if item.ownerID == userID {
+		return item, nil
+	}
+
+	// Items on cards the user can't see look the same as missing ones.
+	if !item.visible || !item.isFinalized || item.isArchived {
+		return nil, ErrItemNotFound
+	}
+
+	blocked, err := s.isBlocked(ctx, userID, item.ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ErrUserBlocked
+	}
+
+	isFriend, err := s.friendService.IsFriend(ctx, userID, item.ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !isFriend {
+		return nil, ErrNotFriend
+	}
+	return item, nil
+}
+
+func (s *CommentService) isBlocked(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error) {
+	var blocked bool
+	err := s.db.QueryRow(ctx,
+		`SELECT EXISTS(
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2)
+			   OR (blocker_id = $2 AND blocked_id = $1)
+		)`,
+		userID, otherUserID,
+	).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("check block status: %w", err)
+	}
+	return blocked, nil
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(body) > models.MaxCommentLength {
+		return "", ErrCommentTooLong
+	}
+	return body, nil
+}
+
+// Create adds a comment to an item. A reply to a reply is attached to the
+// top-level comment so threads stay one level deep. The card owner and the
+// author of the comment being answered are notified.
+func (s *CommentService) Create(ctx context.Context, userID, itemID uuid.UUID, parentID *uuid.UUID, body string) (*models.ItemComment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.checkItemAccess(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentAuthorID *uuid.UUID
+	if parentID != nil {
+		var rootID, authorID uuid.UUID
+		err := s.db.QueryRow(ctx,
+			`SELECT COALESCE(parent_id, id), user_id
+			 FROM item_comments
+			 WHERE id = $1 AND item_id = $2`,
+			*parentID, itemID,
+		).Scan(&rootID, &authorID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting parent comment: %w", err)
+		}
+		if authorID != userID {
+			blocked, err := s.isBlocked(ctx, userID, authorID)
+			if err != nil {
+				return nil, err
+			}
+			if blocked {
+				return nil, ErrUserBlocked
+			}
+		}
+		parentID = &rootID
+		parentAuthorID = &authorID
+	}
+
+	comment := &models.ItemComment{}
+	err = s.db.QueryRow(ctx,
+		`WITH inserted AS (
+		   INSERT INTO item_comments (item_id, user_id, parent_id, body)
+		   VALUES ($1, $2, $3, $4)
+		   RETURNING id, item_id, user_id, parent_id, body, created_at, edited_at
+		 )
+		 SELECT i.id, i.item_id, i.user_id, u.username, i.parent_id, i.body, i.created_at, i.edited_at
+		 FROM inserted i
+		 JOIN users u ON u.id = i.user_id`,
+		itemID, userID, parentID, body,
+	).Scan(&comment.ID, &comment.ItemID, &comment.UserID, &comment.Username, &comment.ParentID, &comment.Body, &comment.CreatedAt, &comment.EditedAt)
+	if err != nil {
+		return nil, fmt.Errorf("adding comment: %w", err)
+	}
+
+	s.notifyComment(ctx, item.ownerID, userID, item.cardID, comment.ID)
+	if parentAuthorID != nil && *parentAuthorID != item.ownerID {
+		s.notifyComment(ctx, *parentAuthorID, userID, item.cardID, comment.ID)
+	}
+
+	return comment, nil
+}
+
+func (s *CommentService) notifyComment(ctx context.Context, recipientID, actorID, cardID, commentID uuid.UUID) {
+	if s.notificationService == nil || recipientID == actorID {
+		return
+	}
+	if err := s.notificationService.NotifyItemComment(ctx, recipientID, actorID, cardID, commentID); err != nil {
+		logging.FromContext(ctx).Error("Failed to notify about comment", map[string]interface{}{
+			"error":        err.Error(),
+			"recipient_id": recipientID.String(),
+			"comment_id":   commentID.String(),
+		})
+	}
+}
+
+// Update replaces the body of a comment. Only its author may edit it.
+func (s *CommentService) Update(ctx context.Context, userID, commentID uuid.UUID, body string) (*models.ItemComment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var authorID uuid.UUID
+	err = s.db.QueryRow(ctx, "SELECT user_id FROM item_comments WHERE id = $1", commentID).Scan(&authorID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting comment: %w", err)
+	}
+	if authorID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+
+	comment := &models.ItemComment{}
+	err = s.db.QueryRow(ctx,
+		`UPDATE item_comments c
+		 SET body = $2, edited_at = NOW()
+		 FROM users u
+		 WHERE c.id = $1 AND u.id = c.user_id
+		 RETURNING c.id, c.item_id, c.user_id, u.username, c.parent_id, c.body, c.created_at, c.edited_at`,
+		commentID, body,
+	).Scan(&comment.ID, &comment.ItemID, &comment.UserID, &comment.Username, &comment.ParentID, &comment.Body, &comment.CreatedAt, &comment.EditedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("updating comment: %w", err)
+	}
+	return comment, nil
+}
+
+// Delete removes a comment and its replies. The author can delete their own
+// comments and the card owner can delete any comment on their card.
+func (s *CommentService) Delete(ctx context.Context, userID, commentID uuid.UUID) error {
+	var authorID, ownerID uuid.UUID
+	err := s.db.QueryRow(ctx,
+		`SELECT c.user_id, bc.user_id
+		 FROM item_comments c
+		 JOIN bingo_items bi ON bi.id = c.item_id
+		 JOIN bingo_cards bc ON bc.id = bi.card_id
+		 WHERE c.id = $1`,
+		commentID,
+	).Scan(&authorID, &ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("getting comment: %w", err)
+	}
+	if authorID != userID && ownerID != userID {
+		return ErrCommentNotPermitted
+	}
+
+	result, err := s.db.Exec(ctx, "DELETE FROM item_comments WHERE id = $1", commentID)
+	if err != nil {
+		return fmt.Errorf("deleting comment: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// List returns an item's comments oldest first. Comments written by users the
+// viewer has blocked or been blocked by are left out.
+func (s *CommentService) List(ctx context.Context, userID, itemID uuid.UUID) ([]models.ItemComment, error) {
+	if _, err := s.checkItemAccess(ctx, userID, itemID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT c.id, c.item_id, c.user_id, u.username, c.parent_id, c.body, c.created_at, c.edited_at
+		 FROM item_comments c
+		 JOIN users u ON u.id = c.user_id
+		 WHERE c.item_id = $1
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks b
+		     WHERE (b.blocker_id = $2 AND b.blocked_id = c.user_id)
+		        OR (b.blocked_id = $2 AND b.blocker_id = c.user_id)
+		   )
+		 ORDER BY c.created_at, c.id`,
+		itemID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.ItemComment{}
+	for rows.Next() {
+		var c models.ItemComment
+		if err := rows.Scan(&c.ID, &c.ItemID, &c.UserID, &c.Username, &c.ParentID, &c.Body, &c.CreatedAt, &c.EditedAt); err != nil {
+			return nil, fmt.Errorf("scanning comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing comments: %w", err)
+	}
+	return comments, nil
+}
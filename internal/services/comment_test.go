@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// commentDB fakes the queries CommentService makes. The item belongs to
+// ownerID on cardID; blocked controls the block check.
+type commentDB struct {
+	ownerID          uuid.UUID
+	cardID           uuid.UUID
+	visibleToFriends bool
+	draft            bool
+	archived         bool
+	blocked          bool
+	parentRootID     uuid.UUID
+	parentAuthorID   uuid.UUID
+	insertedParent   *uuid.UUID
+	insertedBody     string
+}
+
+func (c *commentDB) fake() *fakeDB {
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM bingo_items bi"):
+				return rowFromValues(c.cardID, c.ownerID, c.visibleToFriends, !c.draft, c.archived)
+			case strings.Contains(sql, "FROM user_blocks"):
+				return rowFromValues(c.blocked)
+			case strings.Contains(sql, "COALESCE(parent_id, id)"):
+				if c.parentAuthorID == uuid.Nil {
+					return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+				}
+				return rowFromValues(c.parentRootID, c.parentAuthorID)
+			case strings.Contains(sql, "INSERT INTO item_comments"):
+				c.insertedParent = args[2].(*uuid.UUID)
+				c.insertedBody = args[3].(string)
+				return rowFromValues(uuid.New(), args[0], args[1], "commenter", args[2], args[3], time.Now(), nil)
+			}
+			return fakeRow{scanFunc: func(dest ...any) error { return errors.New("unexpected query: " + sql) }}
+		},
+	}
+}
+
+func TestCommentService_Create_Validation(t *testing.T) {
+	service := NewCommentService(&fakeDB{}, &fakeFriendChecker{})
+
+	if _, err := service.Create(context.Background(), uuid.New(), uuid.New(), nil, "   "); !errors.Is(err, ErrCommentEmpty) {
+		t.Fatalf("expected ErrCommentEmpty, got %v", err)
+	}
+	if _, err := service.Create(context.Background(), uuid.New(), uuid.New(), nil, strings.Repeat("é", 1001)); !errors.Is(err, ErrCommentTooLong) {
+		t.Fatalf("expected ErrCommentTooLong, got %v", err)
+	}
+}
+
+func TestCommentService_Create_AccessChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		db       commentDB
+		isFriend bool
+		want     error
+	}{
+		{name: "hidden card", db: commentDB{visibleToFriends: false}, isFriend: true, want: ErrItemNotFound},
+		{name: "draft card", db: commentDB{visibleToFriends: true, draft: true}, isFriend: true, want: ErrItemNotFound},
+		{name: "archived card", db: commentDB{visibleToFriends: true, archived: true}, isFriend: true, want: ErrItemNotFound},
+		{name: "blocked", db: commentDB{visibleToFriends: true, blocked: true}, isFriend: true, want: ErrUserBlocked},
+		{name: "not friends", db: commentDB{visibleToFriends: true}, isFriend: false, want: ErrNotFriend},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.db.ownerID = uuid.New()
+			service := NewCommentService(tt.db.fake(), &fakeFriendChecker{isFriend: tt.isFriend})
+			_, err := service.Create(context.Background(), uuid.New(), uuid.New(), nil, "Nice!")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCommentService_Create_OwnerSkipsFriendCheck(t *testing.T) {
+	ownerID := uuid.New()
+	db := &commentDB{ownerID: ownerID, cardID: uuid.New()}
+	friend := &fakeFriendChecker{}
+	notified := 0
+	service := NewCommentService(db.fake(), friend)
+	service.SetNotificationService(&stubNotificationService{
+		NotifyItemCommentFunc: func(ctx context.Context, recipientID, actorID, cardID, commentID uuid.UUID) error {
+			notified++
+			return nil
+		},
+	})
+
+	comment, err := service.Create(context.Background(), ownerID, uuid.New(), nil, "  Halfway there  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.Body != "Halfway there" || db.insertedBody != "Halfway there" {
+		t.Fatalf("expected trimmed body, got %q", comment.Body)
+	}
+	if friend.calls != 0 {
+		t.Fatalf("expected no friend checks for the owner, got %d", friend.calls)
+	}
+	if notified != 0 {
+		t.Fatalf("expected no notification for the owner's own comment, got %d", notified)
+	}
+}
+
+func TestCommentService_Create_ReplyAttachesToRootAndNotifies(t *testing.T) {
+	ownerID := uuid.New()
+	parentAuthorID := uuid.New()
+	rootID := uuid.New()
+	cardID := uuid.New()
+	db := &commentDB{
+		ownerID:          ownerID,
+		cardID:           cardID,
+		visibleToFriends: true,
+		parentRootID:     rootID,
+		parentAuthorID:   parentAuthorID,
+	}
+	var recipients []uuid.UUID
+	service := NewCommentService(db.fake(), &fakeFriendChecker{isFriend: true})
+	service.SetNotificationService(&stubNotificationService{
+		NotifyItemCommentFunc: func(ctx context.Context, recipientID, actorID, gotCardID, commentID uuid.UUID) error {
+			if gotCardID != cardID {
+				t.Fatalf("unexpected card id %s", gotCardID)
+			}
+			recipients = append(recipients, recipientID)
+			return nil
+		},
+	})
+
+	replyTo := uuid.New()
+	if _, err := service.Create(context.Background(), uuid.New(), uuid.New(), &replyTo, "Same here"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.insertedParent == nil || *db.insertedParent != rootID {
+		t.Fatalf("expected reply to attach to root %s, got %v", rootID, db.insertedParent)
+	}
+	if len(recipients) != 2 || recipients[0] != ownerID || recipients[1] != parentAuthorID {
+		t.Fatalf("expected owner and parent author notified, got %v", recipients)
+	}
+}
+
+func TestCommentService_Create_ParentNotFound(t *testing.T) {
+	ownerID := uuid.New()
+	db := &commentDB{ownerID: ownerID}
+	service := NewCommentService(db.fake(), &fakeFriendChecker{})
+
+	parentID := uuid.New()
+	_, err := service.Create(context.Background(), ownerID, uuid.New(), &parentID, "Hi")
+	if !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestCommentService_Update_OnlyAuthor(t *testing.T) {
+	authorID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "UPDATE item_comments") {
+				editedAt := time.Now()
+				return rowFromValues(args[0], uuid.New(), authorID, "author", nil, args[1], time.Now(), &editedAt)
+			}
+			return rowFromValues(authorID)
+		},
+	}
+	service := NewCommentService(db, &fakeFriendChecker{})
+
+	if _, err := service.Update(context.Background(), uuid.New(), uuid.New(), "edit"); !errors.Is(err, ErrNotCommentAuthor) {
+		t.Fatalf("expected ErrNotCommentAuthor, got %v", err)
+	}
+
+	comment, err := service.Update(context.Background(), authorID, uuid.New(), "edited")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.Body != "edited" || comment.EditedAt == nil {
+		t.Fatalf("expected edited comment, got %+v", comment)
+	}
+}
+
+func TestCommentService_Delete_Permissions(t *testing.T) {
+	authorID := uuid.New()
+	ownerID := uuid.New()
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		want   error
+	}{
+		{name: "author", userID: authorID},
+		{name: "card owner", userID: ownerID},
+		{name: "someone else", userID: uuid.New(), want: ErrCommentNotPermitted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					return rowFromValues(authorID, ownerID)
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					deleted = true
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+			}
+			service := NewCommentService(db, &fakeFriendChecker{})
+
+			err := service.Delete(context.Background(), tt.userID, uuid.New())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if deleted != (tt.want == nil) {
+				t.Fatalf("expected deleted=%v", tt.want == nil)
+			}
+		})
+	}
+}
+
+func TestCommentService_Delete_NotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	service := NewCommentService(db, &fakeFriendChecker{})
+
+	if err := service.Delete(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestCommentService_List(t *testing.T) {
+	ownerID := uuid.New()
+	itemID := uuid.New()
+	parentID := uuid.New()
+	db := (&commentDB{ownerID: ownerID, cardID: uuid.New()}).fake()
+	db.QueryFunc = func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		if !strings.Contains(sql, "user_blocks") {
+			t.Fatal("expected blocked authors to be filtered")
+		}
+		return &fakeRows{rows: [][]any{
+			{parentID, itemID, uuid.New(), "alice", nil, "First", time.Now(), nil},
+			{uuid.New(), itemID, ownerID, "owner", &parentID, "Thanks", time.Now(), nil},
+		}}, nil
+	}
+	service := NewCommentService(db, &fakeFriendChecker{})
+
+	comments, err := service.List(context.Background(), ownerID, itemID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(comments))
+	}
+	if comments[1].ParentID == nil || *comments[1].ParentID != parentID {
+		t.Fatalf("expected reply to reference parent")
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@often",
+	} {
+		if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidCronExpression) {
+			t.Errorf("ParseCron(%q): expected ErrInvalidCronExpression, got %v", expr, err)
+		}
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Wednesday 15 January 2025, 10:07:30 UTC.
+	from := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/5 * * * *", time.Date(2025, 1, 15, 10, 10, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2025, 1, 16, 3, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{"15,45 10 * * *", time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 0 * * 1", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 20th or any Friday, whichever is first.
+		{"0 0 20 * 5", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: expected %s, got %s", tt.expr, tt.want, got)
+		}
+	}
+}
+
+func TestCronSchedule_Next_Impossible(t *testing.T) {
+	schedule, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Fatalf("expected no next run, got %s", next)
+	}
+}
@@ -137,3 +137,26 @@ type commandTagAdapter struct {
 func (c commandTagAdapter) RowsAffected() int64 {
 	return c.tag.RowsAffected()
 }
+
+// txDB lets code written against DB run inside a transaction that is already
+// open. Begin joins that transaction: the joined Commit and Rollback do
+// nothing, so whoever opened it decides the outcome from the errors returned.
+type txDB struct {
+	Tx
+}
+
+func (t txDB) Begin(ctx context.Context) (Tx, error) {
+	return joinedTx{t.Tx}, nil
+}
+
+type joinedTx struct {
+	Tx
+}
+
+func (joinedTx) Commit(ctx context.Context) error {
+	return nil
+}
+
+func (joinedTx) Rollback(ctx context.Context) error {
+	return nil
+}
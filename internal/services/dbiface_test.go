@@ -86,10 +86,27 @@ func (f *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) Row {
 }
 
 func (f *fakeDB) Begin(ctx context.Context) (Tx, error) {
-	if f.BeginFunc != nil {
-		return f.BeginFunc(ctx)
+	if f.BeginFunc == nil {
+		// Without BeginFunc, transactions run straight against the fake.
+		return &fakeTx{ExecFunc: f.ExecFunc, QueryFunc: f.QueryFunc, QueryRowFunc: f.QueryRowFunc}, nil
 	}
-	return nil, fmt.Errorf("beginFunc not set")
+	tx, err := f.BeginFunc(ctx)
+	if ftx, ok := tx.(*fakeTx); ok && err == nil {
+		// Statements the tx doesn't stub fall through to the fake, so tests
+		// only need to stub what happens inside the transaction.
+		fallthroughTx := *ftx
+		if fallthroughTx.ExecFunc == nil {
+			fallthroughTx.ExecFunc = f.ExecFunc
+		}
+		if fallthroughTx.QueryFunc == nil {
+			fallthroughTx.QueryFunc = f.QueryFunc
+		}
+		if fallthroughTx.QueryRowFunc == nil {
+			fallthroughTx.QueryRowFunc = f.QueryRowFunc
+		}
+		return &fallthroughTx, nil
+	}
+	return tx, err
 }
 
 type fakeTx struct {
@@ -7,7 +7,6 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"html/template"
 	"net/smtp"
 	"time"
 
@@ -15,6 +14,7 @@ import (
 	"github.com/resend/resend-go/v2"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/i18n"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 )
 
@@ -45,6 +45,7 @@ type EmailService struct {
 	fromAddress string
 	fromName    string
 	baseURL     string
+	outbox      *EmailOutbox
 }
 
 // NewEmailService creates a new email service based on configuration
@@ -69,6 +70,26 @@ func NewEmailService(cfg *config.EmailConfig, db DBConn) *EmailService {
 	}
 }
 
+// SetOutbox routes outgoing email through a durable outbox instead of sending
+// it inline. A nil outbox restores synchronous delivery.
+func (s *EmailService) SetOutbox(outbox *EmailOutbox) {
+	s.outbox = outbox
+}
+
+// Provider returns the configured email provider.
+func (s *EmailService) Provider() EmailProvider {
+	return s.provider
+}
+
+// deliver enqueues the email when an outbox is configured, otherwise it sends
+// it immediately through the provider.
+func (s *EmailService) deliver(ctx context.Context, email *Email, idempotencyKey string) error {
+	if s.outbox != nil {
+		return s.outbox.Enqueue(ctx, email, idempotencyKey)
+	}
+	return s.provider.Send(ctx, email)
+}
+
 // GenerateToken creates a secure random token and returns both the token and its hash
 func GenerateToken() (token string, hash string, err error) {
 	bytes := make([]byte, 32)
@@ -104,14 +125,18 @@ func (s *EmailService) SendVerificationEmail(ctx context.Context, userID uuid.UU
 
 	verifyURL := fmt.Sprintf("%s/#verify-email?token=%s", s.baseURL, token)
 
-	html, text := s.renderVerificationEmail(verifyURL)
+	locale := s.recipientLocale(ctx, email)
+	html, text, err := s.renderVerificationEmail(locale, verifyURL)
+	if err != nil {
+		return err
+	}
 
-	return s.provider.Send(ctx, &Email{
+	return s.deliver(ctx, &Email{
 		To:      email,
-		Subject: "Verify your Year of Bingo account",
+		Subject: i18n.T(locale, "verification.subject"),
 		HTML:    html,
 		Text:    text,
-	})
+	}, "verification:"+tokenHash)
 }
 
 // VerifyEmail verifies an email using a token
@@ -145,7 +170,7 @@ func (s *EmailService) VerifyEmail(ctx context.Context, token string) error {
 		`DELETE FROM email_verification_tokens WHERE user_id = $1`,
 		userID)
 	if err != nil {
-		logging.Error("Failed to delete verification tokens", map[string]interface{}{"error": err.Error(), "user_id": userID.String()})
+		logging.FromContext(ctx).Error("Failed to delete verification tokens", map[string]interface{}{"error": err.Error(), "user_id": userID.String()})
 	}
 
 	return nil
@@ -169,14 +194,18 @@ func (s *EmailService) SendMagicLinkEmail(ctx context.Context, email string) err
 
 	loginURL := fmt.Sprintf("%s/#magic-link?token=%s", s.baseURL, token)
 
-	html, text := s.renderMagicLinkEmail(loginURL)
+	locale := s.recipientLocale(ctx, email)
+	html, text, err := s.renderMagicLinkEmail(locale, loginURL)
+	if err != nil {
+		return err
+	}
 
-	return s.provider.Send(ctx, &Email{
+	return s.deliver(ctx, &Email{
 		To:      email,
-		Subject: "Your Year of Bingo login link",
+		Subject: i18n.T(locale, "magic_link.subject"),
 		HTML:    html,
 		Text:    text,
-	})
+	}, "magic_link:"+tokenHash)
 }
 
 // VerifyMagicLink verifies a magic link token and returns the email
@@ -208,7 +237,7 @@ func (s *EmailService) VerifyMagicLink(ctx context.Context, token string) (strin
 		`UPDATE magic_link_tokens SET used_at = NOW() WHERE id = $1`,
 		id)
 	if err != nil {
-		logging.Error("Failed to mark magic link as used", map[string]interface{}{"error": err.Error(), "id": id.String()})
+		logging.FromContext(ctx).Error("Failed to mark magic link as used", map[string]interface{}{"error": err.Error(), "id": id.String()})
 	}
 
 	return email, nil
@@ -232,14 +261,18 @@ func (s *EmailService) SendPasswordResetEmail(ctx context.Context, userID uuid.U
 
 	resetURL := fmt.Sprintf("%s/#reset-password?token=%s", s.baseURL, token)
 
-	html, text := s.renderPasswordResetEmail(resetURL)
+	locale := s.recipientLocale(ctx, email)
+	html, text, err := s.renderPasswordResetEmail(locale, resetURL)
+	if err != nil {
+		return err
+	}
 
-	return s.provider.Send(ctx, &Email{
+	return s.deliver(ctx, &Email{
 		To:      email,
-		Subject: "Reset your Year of Bingo password",
+		Subject: i18n.T(locale, "password_reset.subject"),
 		HTML:    html,
 		Text:    text,
-	})
+	}, "password_reset:"+tokenHash)
 }
 
 // VerifyPasswordResetToken verifies a password reset token and returns the user ID
@@ -278,164 +311,113 @@ func (s *EmailService) MarkPasswordResetUsed(ctx context.Context, token string)
 	return err
 }
 
-// SendNotificationEmail sends a pre-rendered notification email.
-func (s *EmailService) SendNotificationEmail(ctx context.Context, toEmail, subject, html, text string) error {
-	return s.provider.Send(ctx, &Email{
+// PurgeExpiredTokens deletes expired magic link, email verification and
+// password reset tokens. It returns how many were removed.
+func (s *EmailService) PurgeExpiredTokens(ctx context.Context) (int, error) {
+	total := 0
+	for _, table := range []string{"magic_link_tokens", "email_verification_tokens", "password_reset_tokens"} {
+		result, err := s.db.Exec(ctx, "DELETE FROM "+table+" WHERE expires_at <= NOW()")
+		if err != nil {
+			return total, fmt.Errorf("purging %s: %w", table, err)
+		}
+		total += int(result.RowsAffected())
+	}
+	return total, nil
+}
+
+// SendNotificationEmail sends a pre-rendered notification email. The
+// notification ID keys the outbox entry so each notification is mailed once.
+func (s *EmailService) SendNotificationEmail(ctx context.Context, notificationID uuid.UUID, toEmail, subject, html, text string) error {
+	return s.deliver(ctx, &Email{
 		To:      toEmail,
 		Subject: subject,
 		HTML:    html,
 		Text:    text,
-	})
+	}, "notification:"+notificationID.String())
 }
 
-// Email templates
-
-func (s *EmailService) renderVerificationEmail(verifyURL string) (html, text string) {
-	html = fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-  <meta charset="utf-8">
-  <meta name="viewport" content="width=device-width, initial-scale=1.0">
-</head>
-<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Welcome to Year of Bingo!</h1>
-
-  <p>Please verify your email address by clicking the button below:</p>
-
-  <a href="%s"
-     style="display: inline-block; background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
-    Verify Email Address
-  </a>
-
-  <p style="color: #666; font-size: 14px;">
-    This link expires in 24 hours. If you didn't create an account, you can ignore this email.
-  </p>
-
-  <p style="color: #666; font-size: 14px;">
-    Or copy this link: %s
-  </p>
-
-  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
-</body>
-</html>`, verifyURL, verifyURL)
-
-	text = fmt.Sprintf(`Welcome to Year of Bingo!
-
-Please verify your email address by visiting:
-%s
-
-This link expires in 24 hours.
-
-If you didn't create an account, you can ignore this email.
-
---
-Year of Bingo
-yearofbingo.com`, verifyURL)
+// SendNewLoginAlert warns a user about a sign-in from an unrecognized device.
+// The account event ID keys the outbox entry.
+func (s *EmailService) SendNewLoginAlert(ctx context.Context, eventID uuid.UUID, toEmail string, meta RequestMeta, at time.Time) error {
+	locale := s.recipientLocale(ctx, toEmail)
+	html, text, err := s.renderNewLoginEmail(locale, meta, at)
+	if err != nil {
+		return err
+	}
 
-	return html, text
+	return s.deliver(ctx, &Email{
+		To:      toEmail,
+		Subject: i18n.T(locale, "new_login.subject"),
+		HTML:    html,
+		Text:    text,
+	}, "new_login:"+eventID.String())
 }
 
-func (s *EmailService) renderMagicLinkEmail(loginURL string) (html, text string) {
-	html = fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-  <meta charset="utf-8">
-  <meta name="viewport" content="width=device-width, initial-scale=1.0">
-</head>
-<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Sign in to Year of Bingo</h1>
-
-  <p>Click the button below to sign in to your account:</p>
-
-  <a href="%s"
-     style="display: inline-block; background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
-    Sign In
-  </a>
-
-  <p style="color: #666; font-size: 14px;">
-    This link expires in 15 minutes and can only be used once.
-  </p>
-
-  <p style="color: #666; font-size: 14px;">
-    Or copy this link: %s
-  </p>
-
-  <p style="color: #666; font-size: 14px;">
-    If you didn't request this link, you can safely ignore this email.
-  </p>
-
-  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
-</body>
-</html>`, loginURL, loginURL)
-
-	text = fmt.Sprintf(`Sign in to Year of Bingo
-
-Click the link below to sign in:
-%s
-
-This link expires in 15 minutes and can only be used once.
-
-If you didn't request this link, you can safely ignore this email.
-
---
-Year of Bingo
-yearofbingo.com`, loginURL)
+// SendLockoutAlert tells a user that repeated failed sign-ins locked their
+// account until the given time.
+func (s *EmailService) SendLockoutAlert(ctx context.Context, toEmail string, until time.Time) error {
+	locale := s.recipientLocale(ctx, toEmail)
+	html, text, err := s.renderLockoutEmail(locale, until)
+	if err != nil {
+		return err
+	}
 
-	return html, text
+	return s.deliver(ctx, &Email{
+		To:      toEmail,
+		Subject: i18n.T(locale, "lockout.subject"),
+		HTML:    html,
+		Text:    text,
+	}, "lockout:"+HashToken(fmt.Sprintf("%s\n%d", toEmail, until.Truncate(time.Minute).Unix())))
 }
 
-func (s *EmailService) renderPasswordResetEmail(resetURL string) (html, text string) {
-	html = fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-  <meta charset="utf-8">
-  <meta name="viewport" content="width=device-width, initial-scale=1.0">
-</head>
-<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Reset Your Password</h1>
-
-  <p>We received a request to reset your password. Click the button below to choose a new password:</p>
-
-  <a href="%s"
-     style="display: inline-block; background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
-    Reset Password
-  </a>
-
-  <p style="color: #666; font-size: 14px;">
-    This link expires in 1 hour and can only be used once.
-  </p>
-
-  <p style="color: #666; font-size: 14px;">
-    Or copy this link: %s
-  </p>
-
-  <p style="color: #666; font-size: 14px;">
-    If you didn't request a password reset, you can safely ignore this email.
-  </p>
-
-  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
-</body>
-</html>`, resetURL, resetURL)
-
-	text = fmt.Sprintf(`Reset Your Password
+// recipientLocale returns the stored locale for an address, or the default
+// locale when the address has no account.
+func (s *EmailService) recipientLocale(ctx context.Context, email string) string {
+	var locale string
+	if err := s.db.QueryRow(ctx, `SELECT locale FROM users WHERE email = $1`, email).Scan(&locale); err != nil {
+		return i18n.DefaultLocale
+	}
+	return i18n.Normalize(locale)
+}
 
-We received a request to reset your password.
+// Email templates
 
-Click the link below to choose a new password:
-%s
+func (s *EmailService) renderVerificationEmail(locale, verifyURL string) (html, text string, err error) {
+	return renderEmailTemplate(emailTemplateVerification, locale, linkEmailData{Locale: locale, URL: verifyURL})
+}
 
-This link expires in 1 hour and can only be used once.
+func (s *EmailService) renderMagicLinkEmail(locale, loginURL string) (html, text string, err error) {
+	return renderEmailTemplate(emailTemplateMagicLink, locale, linkEmailData{Locale: locale, URL: loginURL})
+}
 
-If you didn't request a password reset, you can safely ignore this email.
+func (s *EmailService) renderPasswordResetEmail(locale, resetURL string) (html, text string, err error) {
+	return renderEmailTemplate(emailTemplatePasswordReset, locale, linkEmailData{Locale: locale, URL: resetURL})
+}
 
---
-Year of Bingo
-yearofbingo.com`, resetURL)
+func (s *EmailService) renderNewLoginEmail(locale string, meta RequestMeta, at time.Time) (html, text string, err error) {
+	unknown := i18n.T(locale, "new_login.unknown")
+	data := newLoginEmailData{
+		Locale:    locale,
+		URL:       fmt.Sprintf("%s/#forgot-password", s.baseURL),
+		When:      at.UTC().Format("2006-01-02 15:04 UTC"),
+		IPAddress: meta.IPAddress,
+		UserAgent: meta.UserAgent,
+	}
+	if data.IPAddress == "" {
+		data.IPAddress = unknown
+	}
+	if data.UserAgent == "" {
+		data.UserAgent = unknown
+	}
+	return renderEmailTemplate(emailTemplateNewLogin, locale, data)
+}
 
-	return html, text
+func (s *EmailService) renderLockoutEmail(locale string, until time.Time) (html, text string, err error) {
+	return renderEmailTemplate(emailTemplateLockout, locale, lockoutEmailData{
+		Locale: locale,
+		URL:    fmt.Sprintf("%s/#forgot-password", s.baseURL),
+		Until:  until.UTC().Format("2006-01-02 15:04 UTC"),
+	})
 }
 
 // ResendProvider sends emails using the Resend API
@@ -463,7 +445,7 @@ func (p *ResendProvider) Send(ctx context.Context, email *Email) error {
 		return fmt.Errorf("sending email via Resend: %w", err)
 	}
 
-	logging.Info("Email sent via Resend", map[string]interface{}{"to": email.To, "subject": email.Subject})
+	logging.FromContext(ctx).Info("Email sent via Resend", map[string]interface{}{"to": email.To, "subject": email.Subject})
 	return nil
 }
 
@@ -495,7 +477,7 @@ func (p *SMTPProvider) Send(ctx context.Context, email *Email) error {
 		return fmt.Errorf("sending email via SMTP: %w", err)
 	}
 
-	logging.Info("Email sent via SMTP", map[string]interface{}{"to": email.To, "subject": email.Subject})
+	logging.FromContext(ctx).Info("Email sent via SMTP", map[string]interface{}{"to": email.To, "subject": email.Subject})
 	return nil
 }
 
@@ -507,7 +489,7 @@ func NewConsoleProvider() *ConsoleProvider {
 }
 
 func (p *ConsoleProvider) Send(ctx context.Context, email *Email) error {
-	logging.Info("=== EMAIL (Console Provider) ===", map[string]interface{}{"to": email.To, "subject": email.Subject})
+	logging.FromContext(ctx).Info("=== EMAIL (Console Provider) ===", map[string]interface{}{"to": email.To, "subject": email.Subject})
 	fmt.Printf("\n=== EMAIL ===\n")
 	fmt.Printf("To: %s\n", email.To)
 	fmt.Printf("Subject: %s\n", email.Subject)
@@ -519,70 +501,35 @@ func (p *ConsoleProvider) Send(ctx context.Context, email *Email) error {
 
 // SendSupportEmail sends a support request to the support team
 func (s *EmailService) SendSupportEmail(ctx context.Context, fromEmail, category, message string, userID string) error {
-	html, text := s.renderSupportEmail(fromEmail, category, message, userID)
+	html, text, err := s.renderSupportEmail(fromEmail, category, message, userID)
+	if err != nil {
+		return err
+	}
+
+	// Identical submissions within the same hour are treated as retries.
+	key := "support:" + HashToken(fmt.Sprintf("%s\n%s\n%s\n%s\n%d",
+		fromEmail, category, userID, message, time.Now().Truncate(time.Hour).Unix()))
 
-	return s.provider.Send(ctx, &Email{
+	return s.deliver(ctx, &Email{
 		To:      "support@yearofbingo.com",
-		Subject: fmt.Sprintf("[Support] %s", category),
+		Subject: i18n.T(i18n.DefaultLocale, "support.subject", category),
 		HTML:    html,
 		Text:    text,
-	})
+	}, key)
 }
 
-func (s *EmailService) renderSupportEmail(fromEmail, category, message, userID string) (html, text string) {
-	userInfo := "Not logged in"
+func (s *EmailService) renderSupportEmail(fromEmail, category, message, userID string) (html, text string, err error) {
+	locale := i18n.DefaultLocale
+	userInfo := i18n.T(locale, "support.not_logged_in")
 	if userID != "" {
 		userInfo = userID
 	}
 
-	html = fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-  <meta charset="utf-8">
-  <meta name="viewport" content="width=device-width, initial-scale=1.0">
-</head>
-<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Support Request</h1>
-
-  <table style="width: 100%%; border-collapse: collapse; margin: 20px 0;">
-    <tr>
-      <td style="padding: 8px; border-bottom: 1px solid #eee; font-weight: bold; width: 120px;">From:</td>
-      <td style="padding: 8px; border-bottom: 1px solid #eee;">%s</td>
-    </tr>
-    <tr>
-      <td style="padding: 8px; border-bottom: 1px solid #eee; font-weight: bold;">Category:</td>
-      <td style="padding: 8px; border-bottom: 1px solid #eee;">%s</td>
-    </tr>
-    <tr>
-      <td style="padding: 8px; border-bottom: 1px solid #eee; font-weight: bold;">User ID:</td>
-      <td style="padding: 8px; border-bottom: 1px solid #eee;">%s</td>
-    </tr>
-  </table>
-
-  <h2 style="color: #333; font-size: 18px;">Message:</h2>
-  <div style="background: #f5f5f5; padding: 15px; border-radius: 6px; white-space: pre-wrap;">%s</div>
-
-  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #999; font-size: 12px;">Year of Bingo Support System</p>
-</body>
-</html>`, fromEmail, category, userInfo, template.HTMLEscapeString(message))
-
-	text = fmt.Sprintf(`Support Request
-===============
-
-From: %s
-Category: %s
-User ID: %s
-
-Message:
---------
-%s
-
---
-Year of Bingo Support System`, fromEmail, category, userInfo, message)
-
-	return html, text
-}
-
-// Suppress unused import warning for template package
-var _ = template.New
+	return renderEmailTemplate(emailTemplateSupport, locale, supportEmailData{
+		Locale:   locale,
+		From:     fromEmail,
+		Category: category,
+		UserInfo: userInfo,
+		Message:  message,
+	})
+}
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+// Outbox row statuses
+const (
+	EmailOutboxStatusPending = "pending"
+	EmailOutboxStatusSending = "sending"
+	EmailOutboxStatusSent    = "sent"
+	EmailOutboxStatusDead    = "dead"
+)
+
+// Outbox defaults
+const (
+	DefaultEmailOutboxMaxAttempts  = 8
+	DefaultEmailOutboxBatchSize    = 20
+	DefaultEmailOutboxPollInterval = 5 * time.Second
+
+	emailOutboxBaseBackoff = 30 * time.Second
+	emailOutboxMaxBackoff  = 6 * time.Hour
+	emailOutboxLease       = 2 * time.Minute
+	emailOutboxSendTimeout = 30 * time.Second
+	emailOutboxMaxErrorLen = 1000
+
+	// Sent and dead rows still hold the message body, which for sign-in,
+	// verification and reset emails contains a live link, so neither is
+	// kept for long. Dead rows stay a little longer for inspection.
+	emailOutboxSentRetention = 7 * 24 * time.Hour
+	emailOutboxDeadRetention = 30 * 24 * time.Hour
+)
+
+// defaultEmailProviderRates caps sends per second for each provider.
+// Providers not listed here (e.g. console) are unlimited.
+var defaultEmailProviderRates = map[string]float64{
+	"resend": 2,
+	"smtp":   10,
+}
+
+// EmailOutboxConfig configures the outbox and its worker.
+type EmailOutboxConfig struct {
+	Provider      string // provider name recorded on each row and used for rate limiting
+	MaxAttempts   int
+	BatchSize     int
+	PollInterval  time.Duration
+	RatePerSecond float64 // 0 uses the provider default, negative disables limiting
+}
+
+// EmailOutbox persists outbound email and delivers it from a background worker
+// with exponential backoff. Rows that exhaust their attempts are moved to the
+// dead state and kept for inspection until Purge removes them.
+type EmailOutbox struct {
+	db           DBConn
+	provider     EmailProvider
+	providerName string
+	maxAttempts  int
+	batchSize    int
+	pollInterval time.Duration
+	limiter      *sendLimiter
+	wakeCh       chan struct{}
+	now          func() time.Time
+}
+
+// NewEmailOutbox creates an outbox that delivers through the given provider.
+func NewEmailOutbox(db DBConn, provider EmailProvider, cfg EmailOutboxConfig) *EmailOutbox {
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultEmailOutboxMaxAttempts
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultEmailOutboxBatchSize
+	}
+	pollInterval := cfg.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultEmailOutboxPollInterval
+	}
+	providerName := cfg.Provider
+	if providerName == "" {
+		providerName = "console"
+	}
+	rate := cfg.RatePerSecond
+	if rate == 0 {
+		rate = defaultEmailProviderRates[providerName]
+	}
+
+	return &EmailOutbox{
+		db:           db,
+		provider:     provider,
+		providerName: providerName,
+		maxAttempts:  maxAttempts,
+		batchSize:    batchSize,
+		pollInterval: pollInterval,
+		limiter:      newSendLimiter(rate),
+		wakeCh:       make(chan struct{}, 1),
+		now:          time.Now,
+	}
+}
+
+// Enqueue stores an email for delivery. Enqueueing the same idempotency key
+// twice is a no-op, so callers can safely retry. An empty key never dedupes.
+func (o *EmailOutbox) Enqueue(ctx context.Context, email *Email, idempotencyKey string) error {
+	if email == nil {
+		return errors.New("email is required")
+	}
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
+
+	_, err := o.db.Exec(ctx,
+		`INSERT INTO email_outbox (idempotency_key, provider, to_address, subject, html_body, text_body, max_attempts)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (idempotency_key) DO NOTHING`,
+		idempotencyKey, o.providerName, email.To, email.Subject, email.HTML, email.Text, o.maxAttempts)
+	if err != nil {
+		return fmt.Errorf("enqueueing email: %w", err)
+	}
+
+	o.wake()
+	return nil
+}
+
+func (o *EmailOutbox) wake() {
+	select {
+	case o.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// Run polls for due emails until ctx is cancelled.
+func (o *EmailOutbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(o.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := o.ProcessBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("Email outbox batch failed", map[string]interface{}{"error": err.Error()})
+		}
+		if err == nil && processed >= o.batchSize {
+			// A full batch likely means more work is waiting.
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wakeCh:
+		}
+	}
+}
+
+type outboxEmail struct {
+	id          uuid.UUID
+	email       Email
+	attempts    int
+	maxAttempts int
+}
+
+// ProcessBatch claims due emails and attempts to deliver each one. It returns
+// the number of emails claimed.
+func (o *EmailOutbox) ProcessBatch(ctx context.Context) (int, error) {
+	// Rows left in "sending" by a crashed worker are retried once their lease
+	// expires, unless they have no attempts left.
+	if _, err := o.db.Exec(ctx,
+		`UPDATE email_outbox
+		 SET status = 'dead', last_error = COALESCE(last_error, 'delivery lease expired'), updated_at = NOW()
+		 WHERE status = 'sending' AND next_attempt_at <= NOW() AND attempts >= max_attempts`); err != nil {
+		return 0, fmt.Errorf("expiring stale outbox rows: %w", err)
+	}
+
+	rows, err := o.db.Query(ctx,
+		`UPDATE email_outbox
+		 SET status = 'sending', attempts = attempts + 1, next_attempt_at = $2, updated_at = NOW()
+		 WHERE id IN (
+		     SELECT id FROM email_outbox
+		     WHERE status IN ('pending', 'sending') AND next_attempt_at <= NOW() AND attempts < max_attempts
+		     ORDER BY next_attempt_at
+		     LIMIT $1
+		     FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id, to_address, subject, html_body, text_body, attempts, max_attempts`,
+		o.batchSize, o.now().Add(emailOutboxLease))
+	if err != nil {
+		return 0, fmt.Errorf("claiming outbox rows: %w", err)
+	}
+
+	var claimed []outboxEmail
+	for rows.Next() {
+		var item outboxEmail
+		if err := rows.Scan(&item.id, &item.email.To, &item.email.Subject, &item.email.HTML, &item.email.Text, &item.attempts, &item.maxAttempts); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning outbox row: %w", err)
+		}
+		claimed = append(claimed, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("claiming outbox rows: %w", err)
+	}
+
+	for _, item := range claimed {
+		if err := o.limiter.wait(ctx); err != nil {
+			return len(claimed), err
+		}
+		o.deliver(ctx, item)
+	}
+
+	return len(claimed), nil
+}
+
+func (o *EmailOutbox) deliver(ctx context.Context, item outboxEmail) {
+	sendCtx, cancel := context.WithTimeout(ctx, emailOutboxSendTimeout)
+	sendErr := o.provider.Send(sendCtx, &item.email)
+	cancel()
+
+	if sendErr == nil {
+		if _, err := o.db.Exec(ctx,
+			`UPDATE email_outbox SET status = 'sent', sent_at = NOW(), last_error = NULL, updated_at = NOW() WHERE id = $1`,
+			item.id); err != nil {
+			logging.FromContext(ctx).Error("Failed to mark outbox email sent", map[string]interface{}{"error": err.Error(), "outbox_id": item.id.String()})
+		}
+		return
+	}
+
+	errMsg := sendErr.Error()
+	if len(errMsg) > emailOutboxMaxErrorLen {
+		errMsg = errMsg[:emailOutboxMaxErrorLen]
+	}
+
+	if item.attempts >= item.maxAttempts {
+		logging.FromContext(ctx).Error("Email moved to dead letter", map[string]interface{}{
+			"error":     errMsg,
+			"outbox_id": item.id.String(),
+			"attempts":  item.attempts,
+		})
+		if _, err := o.db.Exec(ctx,
+			`UPDATE email_outbox SET status = 'dead', last_error = $2, updated_at = NOW() WHERE id = $1`,
+			item.id, errMsg); err != nil {
+			logging.FromContext(ctx).Error("Failed to mark outbox email dead", map[string]interface{}{"error": err.Error(), "outbox_id": item.id.String()})
+		}
+		return
+	}
+
+	retryAt := o.now().Add(emailOutboxBackoff(item.attempts))
+	logging.FromContext(ctx).Warn("Email delivery failed, will retry", map[string]interface{}{
+		"error":     errMsg,
+		"outbox_id": item.id.String(),
+		"attempts":  item.attempts,
+		"retry_at":  retryAt.UTC().Format(time.RFC3339),
+	})
+	if _, err := o.db.Exec(ctx,
+		`UPDATE email_outbox SET status = 'pending', last_error = $2, next_attempt_at = $3, updated_at = NOW() WHERE id = $1`,
+		item.id, errMsg, retryAt); err != nil {
+		logging.FromContext(ctx).Error("Failed to reschedule outbox email", map[string]interface{}{"error": err.Error(), "outbox_id": item.id.String()})
+	}
+}
+
+// RequeueDead moves every dead-lettered email back to pending with a fresh
+// attempt budget and returns how many were requeued.
+func (o *EmailOutbox) RequeueDead(ctx context.Context) (int64, error) {
+	result, err := o.db.Exec(ctx,
+		`UPDATE email_outbox
+		 SET status = 'pending', attempts = 0, next_attempt_at = NOW(), last_error = NULL, updated_at = NOW()
+		 WHERE status = 'dead'`)
+	if err != nil {
+		return 0, fmt.Errorf("requeueing dead emails: %w", err)
+	}
+	if result.RowsAffected() > 0 {
+		o.wake()
+	}
+	return result.RowsAffected(), nil
+}
+
+// Purge deletes sent emails older than a week and dead-lettered emails older
+// than 30 days. It returns how many were removed.
+func (o *EmailOutbox) Purge(ctx context.Context) (int, error) {
+	now := o.now()
+	result, err := o.db.Exec(ctx,
+		`DELETE FROM email_outbox
+		 WHERE (status = 'sent' AND updated_at < $1)
+		    OR (status = 'dead' AND updated_at < $2)`,
+		now.Add(-emailOutboxSentRetention), now.Add(-emailOutboxDeadRetention))
+	if err != nil {
+		return 0, fmt.Errorf("purging email outbox: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// emailOutboxBackoff returns the delay before the next attempt after the given
+// number of failed attempts.
+func emailOutboxBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := emailOutboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= emailOutboxMaxBackoff {
+			return emailOutboxMaxBackoff
+		}
+	}
+	return delay
+}
+
+// SetSendStore shares the provider's send rate through store with every other
+// instance using the same store, so the rate holds for the whole deployment
+// rather than for each instance. Without a store, or while the store fails,
+// each instance paces its own sends.
+func (o *EmailOutbox) SetSendStore(store EmailSendStore) {
+	if o.limiter != nil {
+		o.limiter.store = store
+		o.limiter.key = "email_outbox:send:" + o.providerName
+	}
+}
+
+// EmailSendStore hands out send slots spaced interval apart. Reserve takes
+// the next free slot for key and returns how long to wait for it.
+// Implementations must apply Reserve atomically.
+type EmailSendStore interface {
+	Reserve(ctx context.Context, key string, interval time.Duration, now time.Time) (time.Duration, error)
+}
+
+// RedisEmailSendStore keeps the next free send slot in Redis.
+type RedisEmailSendStore struct {
+	client *redis.Client
+}
+
+func NewRedisEmailSendStore(client *redis.Client) *RedisEmailSendStore {
+	return &RedisEmailSendStore{client: client}
+}
+
+var emailSendReserveScript = redis.NewScript(`
+	local now = tonumber(ARGV[1])
+	local interval = tonumber(ARGV[2])
+	local slot = tonumber(redis.call("GET", KEYS[1]) or "0")
+	if slot < now then
+		slot = now
+	end
+	redis.call("SET", KEYS[1], slot + interval, "PX", slot + interval - now)
+	return slot - now
+`)
+
+func (s *RedisEmailSendStore) Reserve(ctx context.Context, key string, interval time.Duration, now time.Time) (time.Duration, error) {
+	intervalMs := (interval + time.Millisecond - 1).Milliseconds()
+	waitMs, err := emailSendReserveScript.Run(ctx, s.client, []string{key}, now.UnixMilli(), intervalMs).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(waitMs) * time.Millisecond, nil
+}
+
+// sendLimiter spaces sends evenly to stay under a provider's rate limit,
+// through a shared store when one is set.
+type sendLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	store    EmailSendStore
+	key      string
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+func newSendLimiter(perSecond float64) *sendLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &sendLimiter{
+		interval: time.Duration(float64(time.Second) / perSecond),
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+func (l *sendLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return delay
+}
+
+// reserveShared takes a slot from the shared store, falling back to this
+// instance's own pacing when there is no store or it can't be reached.
+func (l *sendLimiter) reserveShared(ctx context.Context) time.Duration {
+	if l.store == nil {
+		return l.reserve()
+	}
+	delay, err := l.store.Reserve(ctx, l.key, l.interval, l.now())
+	if err != nil {
+		logging.FromContext(ctx).Error("Shared email send rate unavailable, pacing locally", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return l.reserve()
+	}
+	return delay
+}
+
+func (l *sendLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if delay := l.reserveShared(ctx); delay > 0 {
+		return l.sleep(ctx, delay)
+	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,442 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewEmailOutbox_Defaults(t *testing.T) {
+	outbox := NewEmailOutbox(&fakeDB{}, &fakeEmailProvider{}, EmailOutboxConfig{})
+	if outbox.providerName != "console" {
+		t.Fatalf("expected console provider name, got %q", outbox.providerName)
+	}
+	if outbox.maxAttempts != DefaultEmailOutboxMaxAttempts {
+		t.Fatalf("expected default max attempts, got %d", outbox.maxAttempts)
+	}
+	if outbox.batchSize != DefaultEmailOutboxBatchSize {
+		t.Fatalf("expected default batch size, got %d", outbox.batchSize)
+	}
+	if outbox.limiter != nil {
+		t.Fatal("expected console provider to be unlimited")
+	}
+
+	resend := NewEmailOutbox(&fakeDB{}, &fakeEmailProvider{}, EmailOutboxConfig{Provider: "resend"})
+	if resend.limiter == nil || resend.limiter.interval != 500*time.Millisecond {
+		t.Fatalf("expected resend limiter at 2/s, got %+v", resend.limiter)
+	}
+
+	disabled := NewEmailOutbox(&fakeDB{}, &fakeEmailProvider{}, EmailOutboxConfig{Provider: "resend", RatePerSecond: -1})
+	if disabled.limiter != nil {
+		t.Fatal("expected negative rate to disable limiting")
+	}
+}
+
+func TestEmailOutbox_Enqueue(t *testing.T) {
+	var gotSQL string
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			gotSQL = sql
+			gotArgs = args
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	provider := &fakeEmailProvider{}
+	outbox := NewEmailOutbox(db, provider, EmailOutboxConfig{Provider: "smtp", MaxAttempts: 4})
+
+	err := outbox.Enqueue(context.Background(), &Email{To: "a@example.com", Subject: "Hi", HTML: "<p>x</p>", Text: "x"}, "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(gotSQL, "ON CONFLICT (idempotency_key) DO NOTHING") {
+		t.Fatalf("expected idempotent insert, got %q", gotSQL)
+	}
+	if gotArgs[0] != "key-1" || gotArgs[1] != "smtp" || gotArgs[2] != "a@example.com" || gotArgs[6] != 4 {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+	if len(provider.sent) != 0 {
+		t.Fatal("expected enqueue not to send")
+	}
+	select {
+	case <-outbox.wakeCh:
+	default:
+		t.Fatal("expected enqueue to wake the worker")
+	}
+}
+
+func TestEmailOutbox_Enqueue_GeneratesKey(t *testing.T) {
+	var key string
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			key = args[0].(string)
+			return fakeCommandTag{}, nil
+		},
+	}
+	outbox := NewEmailOutbox(db, &fakeEmailProvider{}, EmailOutboxConfig{})
+	if err := outbox.Enqueue(context.Background(), &Email{To: "a@example.com"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uuid.Parse(key); err != nil {
+		t.Fatalf("expected generated uuid key, got %q", key)
+	}
+}
+
+func TestEmailOutbox_Enqueue_Errors(t *testing.T) {
+	outbox := NewEmailOutbox(&fakeDB{}, &fakeEmailProvider{}, EmailOutboxConfig{})
+	if err := outbox.Enqueue(context.Background(), nil, "k"); err == nil {
+		t.Fatal("expected error for nil email")
+	}
+
+	outbox = NewEmailOutbox(&fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return nil, errors.New("db down")
+		},
+	}, &fakeEmailProvider{}, EmailOutboxConfig{})
+	if err := outbox.Enqueue(context.Background(), &Email{To: "a@example.com"}, "k"); err == nil {
+		t.Fatal("expected db error")
+	}
+}
+
+type outboxExec struct {
+	sql  string
+	args []any
+}
+
+func newOutboxTestDB(claimed [][]any, execs *[]outboxExec) *fakeDB {
+	return &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			*execs = append(*execs, outboxExec{sql: sql, args: args})
+			return fakeCommandTag{}, nil
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "FOR UPDATE SKIP LOCKED") {
+				return nil, errors.New("unexpected query")
+			}
+			return &fakeRows{rows: claimed}, nil
+		},
+	}
+}
+
+func TestEmailOutbox_ProcessBatch_Sends(t *testing.T) {
+	id := uuid.New()
+	var execs []outboxExec
+	db := newOutboxTestDB([][]any{{id, "a@example.com", "Subject", "<p>hi</p>", "hi", 1, 8}}, &execs)
+	provider := &fakeEmailProvider{}
+	outbox := NewEmailOutbox(db, provider, EmailOutboxConfig{})
+
+	processed, err := outbox.ProcessBatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if processed != 1 {
+		t.Fatalf("expected 1 processed, got %d", processed)
+	}
+	if len(provider.sent) != 1 || provider.sent[0].To != "a@example.com" || provider.sent[0].Text != "hi" {
+		t.Fatalf("unexpected sent emails: %+v", provider.sent)
+	}
+	last := execs[len(execs)-1]
+	if !strings.Contains(last.sql, "status = 'sent'") || last.args[0] != id {
+		t.Fatalf("expected row marked sent, got %q", last.sql)
+	}
+}
+
+func TestEmailOutbox_ProcessBatch_RetriesWithBackoff(t *testing.T) {
+	id := uuid.New()
+	var execs []outboxExec
+	db := newOutboxTestDB([][]any{{id, "a@example.com", "Subject", "", "hi", 3, 8}}, &execs)
+	provider := &fakeEmailProvider{err: errors.New("provider unavailable")}
+	outbox := NewEmailOutbox(db, provider, EmailOutboxConfig{})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	outbox.now = func() time.Time { return now }
+
+	if _, err := outbox.ProcessBatch(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := execs[len(execs)-1]
+	if !strings.Contains(last.sql, "status = 'pending'") {
+		t.Fatalf("expected row rescheduled, got %q", last.sql)
+	}
+	if last.args[1] != "provider unavailable" {
+		t.Fatalf("expected last error recorded, got %v", last.args[1])
+	}
+	if retryAt := last.args[2].(time.Time); !retryAt.Equal(now.Add(2 * time.Minute)) {
+		t.Fatalf("expected retry in 2m, got %v", retryAt.Sub(now))
+	}
+}
+
+func TestEmailOutbox_ProcessBatch_DeadLetters(t *testing.T) {
+	id := uuid.New()
+	var execs []outboxExec
+	db := newOutboxTestDB([][]any{{id, "a@example.com", "Subject", "", "hi", 8, 8}}, &execs)
+	provider := &fakeEmailProvider{err: errors.New(strings.Repeat("x", 2000))}
+	outbox := NewEmailOutbox(db, provider, EmailOutboxConfig{})
+
+	if _, err := outbox.ProcessBatch(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := execs[len(execs)-1]
+	if !strings.Contains(last.sql, "status = 'dead'") || last.args[0] != id {
+		t.Fatalf("expected row dead-lettered, got %q", last.sql)
+	}
+	if msg := last.args[1].(string); len(msg) != emailOutboxMaxErrorLen {
+		t.Fatalf("expected error truncated to %d chars, got %d", emailOutboxMaxErrorLen, len(msg))
+	}
+}
+
+func TestEmailOutbox_ProcessBatch_ExpiresStaleLeases(t *testing.T) {
+	var execs []outboxExec
+	db := newOutboxTestDB(nil, &execs)
+	outbox := NewEmailOutbox(db, &fakeEmailProvider{}, EmailOutboxConfig{})
+
+	processed, err := outbox.ProcessBatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if processed != 0 {
+		t.Fatalf("expected nothing processed, got %d", processed)
+	}
+	if len(execs) != 1 || !strings.Contains(execs[0].sql, "attempts >= max_attempts") {
+		t.Fatalf("expected stale lease sweep, got %+v", execs)
+	}
+}
+
+func TestEmailOutbox_ProcessBatch_QueryErrors(t *testing.T) {
+	outbox := NewEmailOutbox(&fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return nil, errors.New("exec failed")
+		},
+	}, &fakeEmailProvider{}, EmailOutboxConfig{})
+	if _, err := outbox.ProcessBatch(context.Background()); err == nil {
+		t.Fatal("expected sweep error")
+	}
+
+	outbox = NewEmailOutbox(&fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return nil, errors.New("query failed")
+		},
+	}, &fakeEmailProvider{}, EmailOutboxConfig{})
+	if _, err := outbox.ProcessBatch(context.Background()); err == nil {
+		t.Fatal("expected claim error")
+	}
+
+	outbox = NewEmailOutbox(&fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{{"bad"}}}, nil
+		},
+	}, &fakeEmailProvider{}, EmailOutboxConfig{})
+	if _, err := outbox.ProcessBatch(context.Background()); err == nil {
+		t.Fatal("expected scan error")
+	}
+}
+
+func TestEmailOutbox_RequeueDead(t *testing.T) {
+	var gotSQL string
+	outbox := NewEmailOutbox(&fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			gotSQL = sql
+			return fakeCommandTag{rowsAffected: 3}, nil
+		},
+	}, &fakeEmailProvider{}, EmailOutboxConfig{})
+
+	count, err := outbox.RequeueDead(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 requeued, got %d", count)
+	}
+	if !strings.Contains(gotSQL, "WHERE status = 'dead'") {
+		t.Fatalf("unexpected sql: %q", gotSQL)
+	}
+}
+
+func TestEmailOutbox_Purge(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var gotSQL string
+	var gotArgs []any
+	outbox := NewEmailOutbox(&fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			gotSQL, gotArgs = sql, args
+			return fakeCommandTag{rowsAffected: 4}, nil
+		},
+	}, &fakeEmailProvider{}, EmailOutboxConfig{})
+	outbox.now = func() time.Time { return now }
+
+	count, err := outbox.Purge(context.Background())
+	if err != nil || count != 4 {
+		t.Fatalf("expected 4 purged, got %d %v", count, err)
+	}
+	if !strings.Contains(gotSQL, "DELETE FROM email_outbox") || !strings.Contains(gotSQL, "status = 'sent'") || !strings.Contains(gotSQL, "status = 'dead'") {
+		t.Fatalf("unexpected sql: %q", gotSQL)
+	}
+	if sentBefore := gotArgs[0].(time.Time); !sentBefore.Equal(now.AddDate(0, 0, -7)) {
+		t.Fatalf("expected sent cutoff 7 days ago, got %v", sentBefore)
+	}
+	if deadBefore := gotArgs[1].(time.Time); !deadBefore.Equal(now.AddDate(0, 0, -30)) {
+		t.Fatalf("expected dead cutoff 30 days ago, got %v", deadBefore)
+	}
+	for _, status := range []string{"pending", "sending"} {
+		if strings.Contains(gotSQL, status) {
+			t.Fatalf("purge must not touch %s rows: %q", status, gotSQL)
+		}
+	}
+}
+
+func TestEmailOutbox_Run_StopsOnCancel(t *testing.T) {
+	var execs []outboxExec
+	db := newOutboxTestDB(nil, &execs)
+	outbox := NewEmailOutbox(db, NewConsoleProvider(), EmailOutboxConfig{PollInterval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		outbox.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Run to return after cancel")
+	}
+}
+
+func TestEmailOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{10, 256 * time.Minute},
+		{20, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := emailOutboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("emailOutboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSendLimiter_SpacesSends(t *testing.T) {
+	limiter := newSendLimiter(2)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	var slept []time.Duration
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(slept) != 2 || slept[0] != 500*time.Millisecond || slept[1] != time.Second {
+		t.Fatalf("unexpected sleeps: %v", slept)
+	}
+
+	var nilLimiter *sendLimiter
+	if err := nilLimiter.wait(context.Background()); err != nil {
+		t.Fatalf("expected nil limiter to be a no-op, got %v", err)
+	}
+}
+
+func TestSendLimiter_SharedAcrossInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { _ = client.Close() })
+	store := NewRedisEmailSendStore(client)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	instance := func() *EmailOutbox {
+		outbox := NewEmailOutbox(&fakeDB{}, &fakeEmailProvider{}, EmailOutboxConfig{Provider: "resend"})
+		outbox.SetSendStore(store)
+		outbox.limiter.now = func() time.Time { return now }
+		outbox.limiter.sleep = func(ctx context.Context, d time.Duration) error {
+			slept = append(slept, d)
+			return nil
+		}
+		return outbox
+	}
+	a, b := instance(), instance()
+
+	// Two instances sending at the same moment share resend's 2/s.
+	for _, outbox := range []*EmailOutbox{a, b, a, b} {
+		if err := outbox.limiter.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second, 1500 * time.Millisecond}
+	if len(slept) != len(want) {
+		t.Fatalf("unexpected sleeps: %v", slept)
+	}
+	for i := range want {
+		if slept[i] != want[i] {
+			t.Fatalf("unexpected sleeps: %v", slept)
+		}
+	}
+	if !mr.Exists("email_outbox:send:resend") {
+		t.Fatal("expected the send slot to be keyed by provider")
+	}
+
+	// Without Redis each instance falls back to pacing its own sends.
+	mr.Close()
+	slept = nil
+	for i := 0; i < 2; i++ {
+		if err := a.limiter.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(slept) != 1 || slept[0] != 500*time.Millisecond {
+		t.Fatalf("expected local pacing after a Redis failure, got %v", slept)
+	}
+}
+
+func TestEmailService_DeliverUsesOutbox(t *testing.T) {
+	var outboxKeys []string
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "email_outbox") {
+				outboxKeys = append(outboxKeys, args[0].(string))
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	provider := &fakeEmailProvider{}
+	service := &EmailService{provider: provider, db: db, baseURL: "http://example.com"}
+	service.SetOutbox(NewEmailOutbox(db, provider, EmailOutboxConfig{}))
+
+	if err := service.SendVerificationEmail(context.Background(), uuid.New(), "a@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notificationID := uuid.New()
+	if err := service.SendNotificationEmail(context.Background(), notificationID, "a@example.com", "s", "h", "t"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(provider.sent) != 0 {
+		t.Fatalf("expected no synchronous sends, got %d", len(provider.sent))
+	}
+	if len(outboxKeys) != 2 {
+		t.Fatalf("expected 2 outbox inserts, got %d", len(outboxKeys))
+	}
+	if !strings.HasPrefix(outboxKeys[0], "verification:") {
+		t.Fatalf("unexpected verification key %q", outboxKeys[0])
+	}
+	if outboxKeys[1] != "notification:"+notificationID.String() {
+		t.Fatalf("unexpected notification key %q", outboxKeys[1])
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/i18n"
+)
+
+//go:embed email_templates/*.tmpl
+var emailTemplateFS embed.FS
+
+// Email template names; each has <name>.html.tmpl and <name>.txt.tmpl.
+const (
+	emailTemplateVerification  = "verification"
+	emailTemplateMagicLink     = "magic_link"
+	emailTemplatePasswordReset = "password_reset"
+	emailTemplateSupport       = "support"
+	emailTemplateNotification  = "notification"
+	emailTemplateNewLogin      = "new_login"
+	emailTemplateLockout       = "lockout"
+)
+
+type emailTemplatePair struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+var emailTemplates = mustLoadEmailTemplates(
+	emailTemplateVerification,
+	emailTemplateMagicLink,
+	emailTemplatePasswordReset,
+	emailTemplateSupport,
+	emailTemplateNotification,
+	emailTemplateNewLogin,
+	emailTemplateLockout,
+)
+
+// emailTemplateFuncs returns the template helpers bound to a locale. Templates
+// are parsed with the default locale and re-bound per render.
+func emailTemplateFuncs(locale string) map[string]any {
+	return map[string]any{
+		"t": func(key string, args ...any) string {
+			return i18n.T(locale, key, args...)
+		},
+		"button": func(url, label string) map[string]string {
+			return map[string]string{"URL": url, "Label": label}
+		},
+	}
+}
+
+func mustLoadEmailTemplates(names ...string) map[string]emailTemplatePair {
+	funcs := emailTemplateFuncs(i18n.DefaultLocale)
+	templates := make(map[string]emailTemplatePair, len(names))
+	for _, name := range names {
+		html, err := htmltemplate.New("base").Funcs(funcs).ParseFS(emailTemplateFS,
+			"email_templates/base.html.tmpl", "email_templates/"+name+".html.tmpl")
+		if err != nil {
+			panic(fmt.Sprintf("parsing %s html email template: %v", name, err))
+		}
+		text, err := texttemplate.New(name+".txt.tmpl").Funcs(funcs).ParseFS(emailTemplateFS,
+			"email_templates/"+name+".txt.tmpl")
+		if err != nil {
+			panic(fmt.Sprintf("parsing %s text email template: %v", name, err))
+		}
+		templates[name] = emailTemplatePair{html: html, text: text}
+	}
+	return templates
+}
+
+// renderEmailTemplate renders the HTML and text variants of a named email in
+// the given locale. data must expose a Locale field for the html lang attribute.
+func renderEmailTemplate(name, locale string, data any) (html, text string, err error) {
+	pair, ok := emailTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %q", name)
+	}
+	funcs := emailTemplateFuncs(locale)
+
+	htmlTmpl, err := pair.html.Clone()
+	if err != nil {
+		return "", "", fmt.Errorf("cloning %s html template: %w", name, err)
+	}
+	var htmlBuf bytes.Buffer
+	if err := htmlTmpl.Funcs(funcs).ExecuteTemplate(&htmlBuf, "base", data); err != nil {
+		return "", "", fmt.Errorf("rendering %s html email: %w", name, err)
+	}
+
+	textTmpl, err := pair.text.Clone()
+	if err != nil {
+		return "", "", fmt.Errorf("cloning %s text template: %w", name, err)
+	}
+	var textBuf bytes.Buffer
+	if err := textTmpl.Funcs(funcs).Execute(&textBuf, data); err != nil {
+		return "", "", fmt.Errorf("rendering %s text email: %w", name, err)
+	}
+
+	return htmlBuf.String(), strings.TrimSpace(textBuf.String()), nil
+}
+
+// linkEmailData is the template data for emails built around a single link.
+type linkEmailData struct {
+	Locale string
+	URL    string
+}
+
+type supportEmailData struct {
+	Locale   string
+	From     string
+	Category string
+	UserInfo string
+	Message  string
+}
+
+type notificationEmailData struct {
+	Locale      string
+	Message     string
+	ViewURL     string
+	FriendsURL  string
+	SettingsURL string
+}
+
+type newLoginEmailData struct {
+	Locale    string
+	URL       string
+	When      string
+	IPAddress string
+	UserAgent string
+}
+
+type lockoutEmailData struct {
+	Locale string
+	URL    string
+	Until  string
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/i18n"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var templateKeyPattern = regexp.MustCompile(`\{\{\s*(?:template "button" \(button \.URL \()?t "([^"]+)"`)
+
+func TestEmailTemplates_KeysExistInCatalog(t *testing.T) {
+	entries, err := emailTemplateFS.ReadDir("email_templates")
+	if err != nil {
+		t.Fatalf("reading templates: %v", err)
+	}
+
+	known := map[string]bool{}
+	for _, key := range i18n.Keys(i18n.DefaultLocale) {
+		known[key] = true
+	}
+
+	found := 0
+	for _, entry := range entries {
+		data, err := emailTemplateFS.ReadFile("email_templates/" + entry.Name())
+		if err != nil {
+			t.Fatalf("reading %s: %v", entry.Name(), err)
+		}
+		for _, match := range templateKeyPattern.FindAllStringSubmatch(string(data), -1) {
+			found++
+			if !known[match[1]] {
+				t.Errorf("%s uses unknown key %q", entry.Name(), match[1])
+			}
+		}
+	}
+	if found == 0 {
+		t.Fatal("expected templates to reference catalog keys")
+	}
+}
+
+func TestRenderEmailTemplate_AllLocales(t *testing.T) {
+	data := map[string]any{
+		emailTemplateVerification:  linkEmailData{URL: "https://example.com/v"},
+		emailTemplateMagicLink:     linkEmailData{URL: "https://example.com/m"},
+		emailTemplatePasswordReset: linkEmailData{URL: "https://example.com/r"},
+		emailTemplateSupport:       supportEmailData{From: "a@example.com", Category: "Bug", UserInfo: "u", Message: "m"},
+		emailTemplateNotification:  notificationEmailData{Message: "hello", ViewURL: "https://example.com/n"},
+		emailTemplateLockout:       lockoutEmailData{URL: "https://example.com/f", Until: "2025-01-01 10:15 UTC"},
+		emailTemplateNewLogin:      newLoginEmailData{URL: "https://example.com/f", When: "2025-01-01 10:00 UTC", IPAddress: "10.0.0.1", UserAgent: "Browser/1.0"},
+	}
+
+	for _, locale := range i18n.SupportedLocales() {
+		for name, d := range data {
+			switch v := d.(type) {
+			case linkEmailData:
+				v.Locale = locale
+				d = v
+			case supportEmailData:
+				v.Locale = locale
+				d = v
+			case notificationEmailData:
+				v.Locale = locale
+				d = v
+			case newLoginEmailData:
+				v.Locale = locale
+				d = v
+			case lockoutEmailData:
+				v.Locale = locale
+				d = v
+			}
+			html, text, err := renderEmailTemplate(name, locale, d)
+			if err != nil {
+				t.Fatalf("%s/%s: unexpected error: %v", locale, name, err)
+			}
+			if !strings.Contains(html, `<html lang="`+locale+`">`) {
+				t.Errorf("%s/%s: expected html lang attribute", locale, name)
+			}
+			if text == "" {
+				t.Errorf("%s/%s: expected text body", locale, name)
+			}
+			for _, key := range i18n.Keys(i18n.DefaultLocale) {
+				if strings.Contains(html, key) || strings.Contains(text, key) {
+					t.Errorf("%s/%s: rendered body contains untranslated key %q", locale, name, key)
+				}
+			}
+		}
+	}
+}
+
+func TestRenderEmailTemplate_Localized(t *testing.T) {
+	html, text, err := renderEmailTemplate(emailTemplateMagicLink, "de", linkEmailData{Locale: "de", URL: "https://example.com/m"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(html, "Bei Year of Bingo anmelden") || !strings.Contains(text, "15 Minuten") {
+		t.Fatalf("expected German copy, got html=%q text=%q", html, text)
+	}
+
+	// Regional tags fall back to the base language.
+	_, text, err = renderEmailTemplate(emailTemplatePasswordReset, "fr-CA", linkEmailData{Locale: "fr-CA", URL: "https://example.com/r"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(text, "Réinitialisez votre mot de passe") {
+		t.Fatalf("expected French copy, got %q", text)
+	}
+
+	if _, _, err := renderEmailTemplate("missing", "en", nil); err == nil {
+		t.Fatal("expected unknown template error")
+	}
+}
+
+func TestEmailService_SendVerificationEmail_UsesRecipientLocale(t *testing.T) {
+	provider := &fakeEmailProvider{}
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "SELECT locale FROM users") {
+				t.Fatalf("unexpected query: %s", sql)
+			}
+			return rowFromValues("es")
+		},
+	}
+	service := &EmailService{provider: provider, db: db, baseURL: "https://example.com"}
+
+	if err := service.SendVerificationEmail(context.Background(), uuid.New(), "a@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(provider.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(provider.sent))
+	}
+	if provider.sent[0].Subject != "Verifica tu cuenta de Year of Bingo" {
+		t.Fatalf("expected Spanish subject, got %q", provider.sent[0].Subject)
+	}
+}
+
+func TestEmailService_SendNewLoginAlert(t *testing.T) {
+	provider := &fakeEmailProvider{}
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues("de")
+		},
+	}
+	service := &EmailService{provider: provider, db: db, baseURL: "https://example.com"}
+	at := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	if err := service.SendNewLoginAlert(context.Background(), uuid.New(), "a@example.com", RequestMeta{IPAddress: "10.0.0.1"}, at); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(provider.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(provider.sent))
+	}
+	email := provider.sent[0]
+	if email.Subject != "Neue Anmeldung bei deinem Year of Bingo-Konto" {
+		t.Fatalf("expected German subject, got %q", email.Subject)
+	}
+	for _, want := range []string{"2025-03-01 09:30 UTC", "10.0.0.1", "Unbekannt", "https://example.com/#forgot-password"} {
+		if !strings.Contains(email.Text, want) {
+			t.Errorf("expected text to contain %q, got %q", want, email.Text)
+		}
+	}
+}
+
+func TestNotificationService_BuildNotificationEmail_Localized(t *testing.T) {
+	service := &NotificationService{baseURL: "https://example.com"}
+	actor := "Ana"
+	year := 2026
+	count := 2
+
+	subject, html, text, err := service.buildNotificationEmail("fr", models.NotificationTypeFriendBingo, &actor, nil, &year, &count, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if subject != "Votre ami a fait bingo !" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+	if !strings.Contains(text, "Ana a fait bingo sur Carte de bingo 2026 (2 au total).") {
+		t.Fatalf("unexpected text %q", text)
+	}
+	if !strings.Contains(html, "https://example.com/#notifications") {
+		t.Fatalf("expected notifications link in html")
+	}
+
+	evil := "<b>x</b>"
+	_, html, _, err = service.buildNotificationEmail("en", models.NotificationTypeFriendRequestReceived, &evil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(html, "<b>x</b>") {
+		t.Fatal("expected actor name to be escaped in html")
+	}
+
+	subject, _, text, err = service.buildNotificationEmail("en", models.NotificationType("unknown"), nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if subject != "New notification" || !strings.HasPrefix(text, "You have a new notification.") {
+		t.Fatalf("unexpected default notification: %q %q", subject, text)
+	}
+
+	winner := "alice"
+	challenge := "Spring sprint"
+	_, _, text, err = service.buildNotificationEmail("en", models.NotificationTypeChallengeResult, &winner, nil, nil, nil, &challenge, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(text, "Spring sprint has finished. alice won!") {
+		t.Fatalf("unexpected challenge result text: %q", text)
+	}
+	_, _, text, err = service.buildNotificationEmail("en", models.NotificationTypeChallengeResult, nil, nil, nil, nil, &challenge, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(text, "Nobody completed any goals") {
+		t.Fatalf("expected no-winner text, got %q", text)
+	}
+
+	admin := "carol"
+	templateTitle := "Volunteer days"
+	org := "Acme"
+	subject, _, text, err = service.buildNotificationEmail("en", models.NotificationTypeOrgTemplate, &admin, nil, nil, nil, nil, &templateTitle, &org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if subject != "New template from Acme" || !strings.HasPrefix(text, "carol published the template Volunteer days for Acme.") {
+		t.Fatalf("unexpected org template email: %q %q", subject, text)
+	}
+}
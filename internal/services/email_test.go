@@ -607,7 +607,10 @@ func TestEmailRenderingFunctions(t *testing.T) {
 	}
 
 	t.Run("verification email", func(t *testing.T) {
-		html, text := svc.renderVerificationEmail("https://example.com/verify?token=abc")
+		html, text, err := svc.renderVerificationEmail("en", "https://example.com/verify?token=abc")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
 		if !strings.Contains(html, "Verify Email Address") {
 			t.Error("HTML should contain verify button text")
@@ -621,7 +624,10 @@ func TestEmailRenderingFunctions(t *testing.T) {
 	})
 
 	t.Run("magic link email", func(t *testing.T) {
-		html, text := svc.renderMagicLinkEmail("https://example.com/magic?token=def")
+		html, text, err := svc.renderMagicLinkEmail("en", "https://example.com/magic?token=def")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
 		if !strings.Contains(html, "Sign In") {
 			t.Error("HTML should contain sign in button")
@@ -635,7 +641,10 @@ func TestEmailRenderingFunctions(t *testing.T) {
 	})
 
 	t.Run("password reset email", func(t *testing.T) {
-		html, text := svc.renderPasswordResetEmail("https://example.com/reset?token=ghi")
+		html, text, err := svc.renderPasswordResetEmail("en", "https://example.com/reset?token=ghi")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
 		if !strings.Contains(html, "Reset Password") {
 			t.Error("HTML should contain reset button")
@@ -649,7 +658,10 @@ func TestEmailRenderingFunctions(t *testing.T) {
 	})
 
 	t.Run("support email", func(t *testing.T) {
-		html, text := svc.renderSupportEmail("user@test.com", "Bug Report", "Something is broken", "user-123")
+		html, text, err := svc.renderSupportEmail("user@test.com", "Bug Report", "Something is broken", "user-123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
 		if !strings.Contains(html, "Support Request") {
 			t.Error("HTML should contain support request header")
@@ -672,7 +684,10 @@ func TestEmailRenderingFunctions(t *testing.T) {
 	})
 
 	t.Run("support email without user ID", func(t *testing.T) {
-		html, _ := svc.renderSupportEmail("anon@test.com", "Question", "How does this work?", "")
+		html, _, err := svc.renderSupportEmail("anon@test.com", "Question", "How does this work?", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
 		if !strings.Contains(html, "Not logged in") {
 			t.Error("HTML should show 'Not logged in' when user ID is empty")
@@ -680,7 +695,10 @@ func TestEmailRenderingFunctions(t *testing.T) {
 	})
 
 	t.Run("support email XSS prevention", func(t *testing.T) {
-		html, _ := svc.renderSupportEmail("test@test.com", "Test", "<script>alert('xss')</script>", "")
+		html, _, err := svc.renderSupportEmail("test@test.com", "Test", "<script>alert('xss')</script>", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
 		if strings.Contains(html, "<script>") {
 			t.Error("HTML should escape script tags to prevent XSS")
@@ -690,3 +708,25 @@ func TestEmailRenderingFunctions(t *testing.T) {
 		}
 	})
 }
+
+func TestEmailService_PurgeExpiredTokens(t *testing.T) {
+	var tables []string
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.HasSuffix(sql, "WHERE expires_at <= NOW()") {
+				t.Fatalf("unexpected sql: %s", sql)
+			}
+			tables = append(tables, strings.Fields(sql)[2])
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	service := NewEmailService(&config.EmailConfig{}, db)
+	count, err := service.PurgeExpiredTokens(context.Background())
+	if err != nil || count != 3 {
+		t.Fatalf("expected 3 purged, got %d %v", count, err)
+	}
+	if strings.Join(tables, ",") != "magic_link_tokens,email_verification_tokens,password_reset_tokens" {
+		t.Fatalf("unexpected tables: %v", tables)
+	}
+}
@@ -21,6 +21,8 @@ var (
 	ErrNotFriendshipRecipient = errors.New("only the recipient can accept/reject")
 	ErrNotFriend              = errors.New("you are not friends with this user")
 	ErrUserBlocked            = errors.New("user is blocked")
+	ErrInvalidEmailHash       = errors.New("email hashes must be hex-encoded SHA-256")
+	ErrTooManyEmailHashes     = errors.New("too many email hashes")
 )
 
 type FriendService struct {
@@ -42,21 +44,28 @@ func (s *FriendService) SearchUsers(ctx context.Context, currentUserID uuid.UUID
 		return []models.UserSearchResult{}, nil
 	}
 
-	searchPattern := "%" + strings.ToLower(query) + "%"
+	query = strings.ToLower(query)
 
+	// Substring matches come first (exact, then prefix), followed by fuzzy
+	// trigram matches so small typos still find the user.
 	rows, err := s.db.Query(ctx,
 		`SELECT id, username FROM users
 		 WHERE id != $1
-		   AND LOWER(username) LIKE $2
+		   AND (LOWER(username) LIKE '%' || $2 || '%' OR LOWER(username) % $2)
 		   AND searchable = true
+		   AND disabled_at IS NULL
 		   AND NOT EXISTS (
 		     SELECT 1 FROM user_blocks
 		     WHERE (blocker_id = $1 AND blocked_id = users.id)
 		        OR (blocker_id = users.id AND blocked_id = $1)
 		   )
-		 ORDER BY username
+		 ORDER BY LOWER(username) = $2 DESC,
+		          LOWER(username) LIKE $2 || '%' DESC,
+		          LOWER(username) LIKE '%' || $2 || '%' DESC,
+		          similarity(LOWER(username), $2) DESC,
+		          username
 		 LIMIT 20`,
-		currentUserID, searchPattern,
+		currentUserID, query,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("searching users: %w", err)
@@ -79,6 +88,137 @@ func (s *FriendService) SearchUsers(ctx context.Context, currentUserID uuid.UUID
 	return results, nil
 }
 
+// Suggestions returns "people you may know": searchable users who share at
+// least one friend with the user, most mutual friends first. Existing friends,
+// pending requests in either direction and blocked users are left out.
+func (s *FriendService) Suggestions(ctx context.Context, userID uuid.UUID) ([]models.FriendSuggestion, error) {
+	rows, err := s.db.Query(ctx,
+		`WITH my_friends AS (
+		   SELECT CASE WHEN user_id = $1 THEN friend_id ELSE user_id END AS id
+		   FROM friendships
+		   WHERE status = 'accepted' AND (user_id = $1 OR friend_id = $1)
+		 ), friends_of_friends AS (
+		   SELECT CASE WHEN f.user_id = mf.id THEN f.friend_id ELSE f.user_id END AS id
+		   FROM my_friends mf
+		   JOIN friendships f ON f.status = 'accepted' AND (f.user_id = mf.id OR f.friend_id = mf.id)
+		 )
+		 SELECT u.id, u.username, COUNT(*) AS mutual_friends
+		 FROM friends_of_friends fof
+		 JOIN users u ON u.id = fof.id
+		 WHERE u.id != $1
+		   AND u.searchable = true
+		   AND u.disabled_at IS NULL
+		   AND NOT EXISTS (
+		     SELECT 1 FROM friendships f
+		     WHERE (f.user_id = $1 AND f.friend_id = u.id)
+		        OR (f.user_id = u.id AND f.friend_id = $1)
+		   )
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks b
+		     WHERE (b.blocker_id = $1 AND b.blocked_id = u.id)
+		        OR (b.blocker_id = u.id AND b.blocked_id = $1)
+		   )
+		 GROUP BY u.id, u.username
+		 ORDER BY mutual_friends DESC, LOWER(u.username)
+		 LIMIT $2`,
+		userID, models.MaxFriendSuggestions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing friend suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []models.FriendSuggestion{}
+	for rows.Next() {
+		var suggestion models.FriendSuggestion
+		if err := rows.Scan(&suggestion.ID, &suggestion.Username, &suggestion.MutualFriends); err != nil {
+			return nil, fmt.Errorf("scanning friend suggestion: %w", err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing friend suggestions: %w", err)
+	}
+	return suggestions, nil
+}
+
+// DiscoverByEmailHashes matches hex SHA-256 hashes of lowercased contact
+// emails against verified addresses of searchable users. The server never sees
+// the contacts' addresses, and unsearchable or unverified users can't be found.
+// Users the caller is already connected to or blocked with are left out.
+func (s *FriendService) DiscoverByEmailHashes(ctx context.Context, userID uuid.UUID, hashes []string) ([]models.UserSearchResult, error) {
+	if len(hashes) > models.MaxDiscoveryEmailHashes {
+		return nil, ErrTooManyEmailHashes
+	}
+
+	seen := make(map[string]bool, len(hashes))
+	normalized := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if !isSHA256Hex(hash) {
+			return nil, ErrInvalidEmailHash
+		}
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		normalized = append(normalized, hash)
+	}
+	if len(normalized) == 0 {
+		return []models.UserSearchResult{}, nil
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT u.id, u.username FROM users u
+		 WHERE u.email_hash = ANY($2)
+		   AND u.email_verified = true
+		   AND u.searchable = true
+		   AND u.disabled_at IS NULL
+		   AND u.id != $1
+		   AND NOT EXISTS (
+		     SELECT 1 FROM friendships f
+		     WHERE (f.user_id = $1 AND f.friend_id = u.id)
+		        OR (f.user_id = u.id AND f.friend_id = $1)
+		   )
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks b
+		     WHERE (b.blocker_id = $1 AND b.blocked_id = u.id)
+		        OR (b.blocker_id = u.id AND b.blocked_id = $1)
+		   )
+		 ORDER BY LOWER(u.username)`,
+		userID, normalized,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("discovering users: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.UserSearchResult{}
+	for rows.Next() {
+		var user models.UserSearchResult
+		if err := rows.Scan(&user.ID, &user.Username); err != nil {
+			return nil, fmt.Errorf("scanning user: %w", err)
+		}
+		results = append(results, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("discovering users: %w", err)
+	}
+	return results, nil
+}
+
+func isSHA256Hex(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
 	if userID == friendID {
 		return nil, ErrCannotFriendSelf
@@ -150,7 +290,7 @@ func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.U
 
 	if s.notificationService != nil {
 		if err := s.notificationService.NotifyFriendRequestReceived(ctx, friendID, userID, friendship.ID); err != nil {
-			logging.Error("Failed to send friend request notification", map[string]interface{}{
+			logging.FromContext(ctx).Error("Failed to send friend request notification", map[string]interface{}{
 				"error":         err.Error(),
 				"user_id":       userID.String(),
 				"recipient_id":  friendID.String(),
@@ -191,7 +331,7 @@ func (s *FriendService) AcceptRequest(ctx context.Context, userID, friendshipID
 
 	if s.notificationService != nil {
 		if err := s.notificationService.NotifyFriendRequestAccepted(ctx, friendship.UserID, userID, friendship.ID); err != nil {
-			logging.Error("Failed to send friend acceptance notification", map[string]interface{}{
+			logging.FromContext(ctx).Error("Failed to send friend acceptance notification", map[string]interface{}{
 				"error":         err.Error(),
 				"user_id":       userID.String(),
 				"recipient_id":  friendship.UserID.String(),
@@ -250,7 +390,7 @@ func (s *FriendService) RemoveFriend(ctx context.Context, userID, friendshipID u
 		return fmt.Errorf("removing friendship: %w", err)
 	}
 
-	return nil
+	return removeGroupMemberships(ctx, s.db, friendship.UserID, friendship.FriendID)
 }
 
 func (s *FriendService) CancelRequest(ctx context.Context, userID, friendshipID uuid.UUID) error {
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrFriendGroupNotFound    = errors.New("friend group not found")
+	ErrFriendGroupExists      = errors.New("a group with this name already exists")
+	ErrInvalidFriendGroupName = errors.New("invalid group name")
+	ErrGroupMemberNotFound    = errors.New("group member not found")
+)
+
+type FriendGroupService struct {
+	db            DBConn
+	friendService FriendChecker
+}
+
+func NewFriendGroupService(db DBConn, friendService FriendChecker) *FriendGroupService {
+	return &FriendGroupService{db: db, friendService: friendService}
+}
+
+// cardVisibleToSQL returns a condition that holds when the card aliased as
+// card is shared with the user given by viewer: either with all friends, or
+// with one of the owner's groups the viewer belongs to. It doesn't check
+// friendship; callers do that separately.
+func cardVisibleToSQL(card, viewer string) string {
+	return fmt.Sprintf(`(%[1]s.visible_to_friends OR (%[1]s.visibility = 'groups' AND EXISTS (
+		   SELECT 1 FROM card_group_shares cgs
+		   JOIN friend_group_members fgm ON fgm.group_id = cgs.group_id
+		   WHERE cgs.card_id = %[1]s.id AND fgm.member_id = %[2]s
+		 )))`, card, viewer)
+}
+
+func normalizeFriendGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > models.MaxFriendGroupNameLength {
+		return "", ErrInvalidFriendGroupName
+	}
+	return name, nil
+}
+
+func (s *FriendGroupService) List(ctx context.Context, ownerID uuid.UUID) ([]models.FriendGroup, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT g.id, g.name, g.created_at, u.id, u.username
+		 FROM friend_groups g
+		 LEFT JOIN friend_group_members m ON m.group_id = g.id
+		 LEFT JOIN users u ON u.id = m.member_id
+		 WHERE g.owner_id = $1
+		 ORDER BY LOWER(g.name), g.id, LOWER(u.username)`,
+		ownerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing friend groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []models.FriendGroup{}
+	for rows.Next() {
+		var group models.FriendGroup
+		var memberID *uuid.UUID
+		var memberName *string
+		if err := rows.Scan(&group.ID, &group.Name, &group.CreatedAt, &memberID, &memberName); err != nil {
+			return nil, fmt.Errorf("scanning friend group: %w", err)
+		}
+		if len(groups) == 0 || groups[len(groups)-1].ID != group.ID {
+			group.Members = []models.FriendGroupMember{}
+			groups = append(groups, group)
+		}
+		if memberID != nil && memberName != nil {
+			last := &groups[len(groups)-1]
+			last.Members = append(last.Members, models.FriendGroupMember{UserID: *memberID, Username: *memberName})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing friend groups: %w", err)
+	}
+	return groups, nil
+}
+
+func (s *FriendGroupService) Create(ctx context.Context, ownerID uuid.UUID, name string) (*models.FriendGroup, error) {
+	name, err := normalizeFriendGroupName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &models.FriendGroup{Members: []models.FriendGroupMember{}}
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO friend_groups (owner_id, name)
+		 VALUES ($1, $2)
+		 RETURNING id, name, created_at`,
+		ownerID, name,
+	).Scan(&group.ID, &group.Name, &group.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrFriendGroupExists
+		}
+		return nil, fmt.Errorf("creating friend group: %w", err)
+	}
+	return group, nil
+}
+
+func (s *FriendGroupService) Rename(ctx context.Context, ownerID, groupID uuid.UUID, name string) error {
+	name, err := normalizeFriendGroupName(name)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(ctx,
+		"UPDATE friend_groups SET name = $3 WHERE id = $1 AND owner_id = $2",
+		groupID, ownerID, name,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrFriendGroupExists
+		}
+		return fmt.Errorf("renaming friend group: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrFriendGroupNotFound
+	}
+	return nil
+}
+
+// Delete removes a group. Cards shared only with this group stay set to
+// "groups" visibility and so become visible to no one but their owner.
+func (s *FriendGroupService) Delete(ctx context.Context, ownerID, groupID uuid.UUID) error {
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM friend_groups WHERE id = $1 AND owner_id = $2",
+		groupID, ownerID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting friend group: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrFriendGroupNotFound
+	}
+	return nil
+}
+
+// AddMember puts one of the owner's friends in a group.
+func (s *FriendGroupService) AddMember(ctx context.Context, ownerID, groupID, memberID uuid.UUID) error {
+	if err := s.checkOwner(ctx, ownerID, groupID); err != nil {
+		return err
+	}
+
+	isFriend, err := s.friendService.IsFriend(ctx, ownerID, memberID)
+	if err != nil {
+		return err
+	}
+	if !isFriend {
+		return ErrNotFriend
+	}
+
+	_, err = s.db.Exec(ctx,
+		`INSERT INTO friend_group_members (group_id, member_id)
+		 VALUES ($1, $2)
+		 ON CONFLICT DO NOTHING`,
+		groupID, memberID,
+	)
+	if err != nil {
+		return fmt.Errorf("adding group member: %w", err)
+	}
+	return nil
+}
+
+func (s *FriendGroupService) RemoveMember(ctx context.Context, ownerID, groupID, memberID uuid.UUID) error {
+	result, err := s.db.Exec(ctx,
+		`DELETE FROM friend_group_members m
+		 USING friend_groups g
+		 WHERE m.group_id = g.id AND g.id = $1 AND g.owner_id = $2 AND m.member_id = $3`,
+		groupID, ownerID, memberID,
+	)
+	if err != nil {
+		return fmt.Errorf("removing group member: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrGroupMemberNotFound
+	}
+	return nil
+}
+
+// SharedCardIDs returns the owner's cards shared with the viewer through a
+// group. Cards visible to all friends aren't included.
+func (s *FriendGroupService) SharedCardIDs(ctx context.Context, ownerID, viewerID uuid.UUID) (map[uuid.UUID]bool, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT DISTINCT c.id
+		 FROM bingo_cards c
+		 JOIN card_group_shares cgs ON cgs.card_id = c.id
+		 JOIN friend_group_members fgm ON fgm.group_id = cgs.group_id
+		 WHERE c.user_id = $1 AND c.visibility = 'groups' AND fgm.member_id = $2`,
+		ownerID, viewerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing shared cards: %w", err)
+	}
+	defer rows.Close()
+
+	shared := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning shared card: %w", err)
+		}
+		shared[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing shared cards: %w", err)
+	}
+	return shared, nil
+}
+
+func (s *FriendGroupService) checkOwner(ctx context.Context, ownerID, groupID uuid.UUID) error {
+	var exists bool
+	err := s.db.QueryRow(ctx,
+		"SELECT true FROM friend_groups WHERE id = $1 AND owner_id = $2",
+		groupID, ownerID,
+	).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrFriendGroupNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("getting friend group: %w", err)
+	}
+	return nil
+}
+
+// removeGroupMemberships takes two users out of each other's groups. It runs
+// when a friendship ends so that becoming friends again doesn't silently
+// restore access to group-shared cards.
+func removeGroupMemberships(ctx context.Context, db DBConn, userID, otherUserID uuid.UUID) error {
+	_, err := db.Exec(ctx,
+		`DELETE FROM friend_group_members m
+		 USING friend_groups g
+		 WHERE m.group_id = g.id
+		   AND ((g.owner_id = $1 AND m.member_id = $2) OR (g.owner_id = $2 AND m.member_id = $1))`,
+		userID, otherUserID,
+	)
+	if err != nil {
+		return fmt.Errorf("removing group memberships: %w", err)
+	}
+	return nil
+}
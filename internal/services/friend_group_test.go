@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestFriendGroupService_Create(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(uuid.New(), args[1], time.Now())
+		},
+	}
+	svc := NewFriendGroupService(db, &fakeFriendChecker{})
+
+	group, err := svc.Create(context.Background(), uuid.New(), "  Family  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if group.Name != "Family" {
+		t.Fatalf("expected trimmed name, got %q", group.Name)
+	}
+	if group.Members == nil {
+		t.Fatal("expected empty member list, got nil")
+	}
+}
+
+func TestFriendGroupService_Create_Validation(t *testing.T) {
+	svc := NewFriendGroupService(&fakeDB{}, &fakeFriendChecker{})
+
+	for _, name := range []string{"", "   ", strings.Repeat("a", 51)} {
+		if _, err := svc.Create(context.Background(), uuid.New(), name); !errors.Is(err, ErrInvalidFriendGroupName) {
+			t.Fatalf("expected ErrInvalidFriendGroupName for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestFriendGroupService_Create_Duplicate(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return &pgconn.PgError{Code: "23505"} }}
+		},
+	}
+	svc := NewFriendGroupService(db, &fakeFriendChecker{})
+
+	if _, err := svc.Create(context.Background(), uuid.New(), "Family"); !errors.Is(err, ErrFriendGroupExists) {
+		t.Fatalf("expected ErrFriendGroupExists, got %v", err)
+	}
+}
+
+func TestFriendGroupService_List_GroupsMembers(t *testing.T) {
+	groupA, groupB := uuid.New(), uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+	aliceName, bobName := "alice", "bob"
+	now := time.Now()
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{
+				{groupA, "Family", now, &alice, &aliceName},
+				{groupA, "Family", now, &bob, &bobName},
+				{groupB, "Work", now, nil, nil},
+			}}, nil
+		},
+	}
+	svc := NewFriendGroupService(db, &fakeFriendChecker{})
+
+	groups, err := svc.List(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if len(groups[0].Members) != 2 || groups[0].Members[1].Username != "bob" {
+		t.Fatalf("unexpected members for first group: %+v", groups[0].Members)
+	}
+	if groups[1].Members == nil || len(groups[1].Members) != 0 {
+		t.Fatalf("expected empty member list for second group, got %+v", groups[1].Members)
+	}
+}
+
+func TestFriendGroupService_Rename_NotFound(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 0}, nil
+		},
+	}
+	svc := NewFriendGroupService(db, &fakeFriendChecker{})
+
+	if err := svc.Rename(context.Background(), uuid.New(), uuid.New(), "Work"); !errors.Is(err, ErrFriendGroupNotFound) {
+		t.Fatalf("expected ErrFriendGroupNotFound, got %v", err)
+	}
+}
+
+func TestFriendGroupService_AddMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		owned    bool
+		isFriend bool
+		want     error
+		inserted bool
+	}{
+		{name: "not owner", owned: false, isFriend: true, want: ErrFriendGroupNotFound},
+		{name: "not a friend", owned: true, isFriend: false, want: ErrNotFriend},
+		{name: "friend", owned: true, isFriend: true, inserted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inserted := false
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if !tt.owned {
+						return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+					}
+					return rowFromValues(true)
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					inserted = strings.Contains(sql, "INSERT INTO friend_group_members")
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+			}
+			svc := NewFriendGroupService(db, &fakeFriendChecker{isFriend: tt.isFriend})
+
+			err := svc.AddMember(context.Background(), uuid.New(), uuid.New(), uuid.New())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if inserted != tt.inserted {
+				t.Fatalf("expected inserted=%v", tt.inserted)
+			}
+		})
+	}
+}
+
+func TestFriendGroupService_RemoveMember_NotFound(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 0}, nil
+		},
+	}
+	svc := NewFriendGroupService(db, &fakeFriendChecker{})
+
+	if err := svc.RemoveMember(context.Background(), uuid.New(), uuid.New(), uuid.New()); !errors.Is(err, ErrGroupMemberNotFound) {
+		t.Fatalf("expected ErrGroupMemberNotFound, got %v", err)
+	}
+}
+
+func TestFriendGroupService_SharedCardIDs(t *testing.T) {
+	cardID := uuid.New()
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "visibility = 'groups'") {
+				t.Fatalf("expected query limited to group-shared cards: %q", sql)
+			}
+			return &fakeRows{rows: [][]any{{cardID}}}, nil
+		},
+	}
+	svc := NewFriendGroupService(db, &fakeFriendChecker{})
+
+	shared, err := svc.SharedCardIDs(context.Background(), uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !shared[cardID] || len(shared) != 1 {
+		t.Fatalf("unexpected shared cards: %v", shared)
+	}
+}
+
+func TestCardVisibleToSQL(t *testing.T) {
+	sql := cardVisibleToSQL("bc", "$2")
+	for _, want := range []string{"bc.visible_to_friends", "bc.visibility = 'groups'", "cgs.card_id = bc.id", "fgm.member_id = $2"} {
+		if !strings.Contains(sql, want) {
+			t.Fatalf("expected %q in %q", want, sql)
+		}
+	}
+}
@@ -143,6 +143,21 @@ func (s *FriendInviteService) RevokeInvite(ctx context.Context, inviterID, invit
 	return nil
 }
 
+// PurgeInactive deletes invites that were revoked or expired without being
+// accepted. Accepted invites are kept as a record of how a friendship
+// started. It returns how many were removed.
+func (s *FriendInviteService) PurgeInactive(ctx context.Context) (int, error) {
+	result, err := s.db.Exec(ctx,
+		`DELETE FROM friend_invites
+		 WHERE accepted_at IS NULL
+		   AND (revoked_at IS NOT NULL OR expires_at <= NOW())`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("purge invites: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
 func (s *FriendInviteService) AcceptInvite(ctx context.Context, recipientID uuid.UUID, token string) (*models.UserSearchResult, error) {
 	tokenHash := hashInviteToken(token)
 
@@ -247,7 +262,7 @@ func (s *FriendInviteService) AcceptInvite(ctx context.Context, recipientID uuid
 
 	if s.notificationService != nil {
 		if err := s.notificationService.NotifyFriendRequestAccepted(ctx, inviterID, recipientID, friendshipID); err != nil {
-			logging.Error("Failed to send invite acceptance notification", map[string]interface{}{
+			logging.FromContext(ctx).Error("Failed to send invite acceptance notification", map[string]interface{}{
 				"error":         err.Error(),
 				"inviter_id":    inviterID.String(),
 				"recipient_id":  recipientID.String(),
@@ -305,3 +305,20 @@ func TestFriendInviteService_AcceptInvite_LockError(t *testing.T) {
 		t.Fatal("expected rollback")
 	}
 }
+
+func TestFriendInviteService_PurgeInactive(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "accepted_at IS NULL") || !strings.Contains(sql, "revoked_at IS NOT NULL OR expires_at <= NOW()") {
+				t.Fatalf("unexpected sql: %s", sql)
+			}
+			return fakeCommandTag{rowsAffected: 3}, nil
+		},
+	}
+
+	svc := NewFriendInviteService(db)
+	count, err := svc.PurgeInactive(context.Background())
+	if err != nil || count != 3 {
+		t.Fatalf("expected 3 purged, got %d %v", count, err)
+	}
+}
@@ -54,6 +54,29 @@ func TestFriendService_SearchUsers_ReturnsRows(t *testing.T) {
 	}
 }
 
+func TestFriendService_SearchUsers_FuzzyMatch(t *testing.T) {
+	var gotArgs []any
+	var gotSQL string
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			gotSQL = sql
+			gotArgs = args
+			return &fakeRows{}, nil
+		},
+	}
+
+	svc := NewFriendService(db)
+	if _, err := svc.SearchUsers(context.Background(), uuid.New(), "  AlIce "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotArgs[1] != "alice" {
+		t.Fatalf("expected lowercased, trimmed query, got %v", gotArgs[1])
+	}
+	if !strings.Contains(gotSQL, "LOWER(username) % $2") || !strings.Contains(gotSQL, "similarity(") {
+		t.Fatalf("expected trigram matching and ranking, got sql: %q", gotSQL)
+	}
+}
+
 func TestFriendService_SearchUsers_QueryError(t *testing.T) {
 	db := &fakeDB{
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -963,3 +986,116 @@ func TestFriendService_GetByID_NotFound(t *testing.T) {
 		t.Fatalf("expected ErrFriendshipNotFound, got %v", err)
 	}
 }
+
+func TestFriendService_SearchUsers_ExcludesDisabled(t *testing.T) {
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "disabled_at IS NULL") {
+				t.Fatalf("expected disabled users to be excluded: %s", sql)
+			}
+			return &fakeRows{}, nil
+		},
+	}
+	svc := NewFriendService(db)
+	if _, err := svc.SearchUsers(context.Background(), uuid.New(), "alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFriendService_Suggestions(t *testing.T) {
+	userID := uuid.New()
+	suggestedID := uuid.New()
+	var gotSQL string
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			gotSQL = sql
+			if args[0] != userID || args[1] != models.MaxFriendSuggestions {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRows{rows: [][]any{{suggestedID, "bob", 3}}}, nil
+		},
+	}
+
+	svc := NewFriendService(db)
+	suggestions, err := svc.Suggestions(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].ID != suggestedID || suggestions[0].MutualFriends != 3 {
+		t.Fatalf("unexpected suggestions: %+v", suggestions)
+	}
+	for _, want := range []string{"user_blocks", "searchable = true", "disabled_at IS NULL", "ORDER BY mutual_friends DESC"} {
+		if !strings.Contains(gotSQL, want) {
+			t.Fatalf("expected suggestions query to contain %q", want)
+		}
+	}
+}
+
+func TestFriendService_Suggestions_Empty(t *testing.T) {
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{}, nil
+		},
+	}
+
+	suggestions, err := NewFriendService(db).Suggestions(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if suggestions == nil || len(suggestions) != 0 {
+		t.Fatalf("expected empty, non-nil suggestions, got %#v", suggestions)
+	}
+}
+
+func TestFriendService_DiscoverByEmailHashes_Validation(t *testing.T) {
+	svc := NewFriendService(&fakeDB{})
+
+	if _, err := svc.DiscoverByEmailHashes(context.Background(), uuid.New(), []string{"not-a-hash"}); !errors.Is(err, ErrInvalidEmailHash) {
+		t.Fatalf("expected ErrInvalidEmailHash, got %v", err)
+	}
+
+	tooMany := make([]string, models.MaxDiscoveryEmailHashes+1)
+	for i := range tooMany {
+		tooMany[i] = hashEmail("user@example.com")
+	}
+	if _, err := svc.DiscoverByEmailHashes(context.Background(), uuid.New(), tooMany); !errors.Is(err, ErrTooManyEmailHashes) {
+		t.Fatalf("expected ErrTooManyEmailHashes, got %v", err)
+	}
+
+	results, err := svc.DiscoverByEmailHashes(context.Background(), uuid.New(), nil)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected no results without a query, got %v %v", results, err)
+	}
+}
+
+func TestFriendService_DiscoverByEmailHashes_NormalizesAndMatches(t *testing.T) {
+	matchedID := uuid.New()
+	hash := hashEmail("Friend@Example.com ")
+	var gotHashes []string
+	var gotSQL string
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			gotSQL = sql
+			gotHashes = args[1].([]string)
+			return &fakeRows{rows: [][]any{{matchedID, "friend"}}}, nil
+		},
+	}
+
+	svc := NewFriendService(db)
+	results, err := svc.DiscoverByEmailHashes(context.Background(), uuid.New(), []string{strings.ToUpper(hash), hash})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotHashes) != 1 || gotHashes[0] != hash {
+		t.Fatalf("expected one lowercased hash, got %v", gotHashes)
+	}
+	if hash != hashEmail("friend@example.com") {
+		t.Fatal("expected email hashing to ignore case and surrounding space")
+	}
+	if !strings.Contains(gotSQL, "email_verified = true") || !strings.Contains(gotSQL, "searchable = true") {
+		t.Fatalf("expected only verified, searchable users to match, got sql: %q", gotSQL)
+	}
+	if len(results) != 1 || results[0].ID != matchedID {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -16,6 +17,8 @@ type UserServiceInterface interface {
 	UpdatePassword(ctx context.Context, userID uuid.UUID, newPasswordHash string) error
 	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
 	UpdateSearchable(ctx context.Context, userID uuid.UUID, searchable bool) error
+	UpdatePublicProfile(ctx context.Context, userID uuid.UUID, public bool) error
+	UpdateLeaderboardOptOut(ctx context.Context, userID uuid.UUID, optOut bool) error
 }
 
 // AuthServiceInterface defines the contract for authentication operations.
@@ -47,14 +50,22 @@ type CardServiceInterface interface {
 	CompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error)
 	UncompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error)
 	UpdateItemNotes(ctx context.Context, userID, cardID uuid.UUID, position int, notes, proofURL *string) (*models.BingoItem, error)
+	UpdateItemSchedule(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemScheduleParams) (*models.BingoItem, error)
 	GetArchive(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error)
 	GetStats(ctx context.Context, userID, cardID uuid.UUID) (*models.CardStats, error)
 	UpdateMeta(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error)
-	UpdateVisibility(ctx context.Context, userID, cardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error)
-	BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error)
+	UpdateVisibility(ctx context.Context, userID, cardID uuid.UUID, params models.CardVisibilityParams) (*models.BingoCard, error)
+	BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, params models.CardVisibilityParams) (int, error)
+	GetSharing(ctx context.Context, userID, cardID uuid.UUID) (*models.CardSharing, error)
+	GetByShareToken(ctx context.Context, token string) (*models.BingoCard, error)
 	BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error)
 	BulkUpdateArchive(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) (int, error)
 	Import(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error)
+	History(ctx context.Context, userID, cardID uuid.UUID) ([]models.CardRevision, error)
+	Undo(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error)
+	RestoreRevision(ctx context.Context, userID, cardID, revisionID uuid.UUID) (*models.BingoCard, error)
+	ListTrash(ctx context.Context, userID uuid.UUID) ([]models.TrashedCard, error)
+	RestoreFromTrash(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error)
 }
 
 // SuggestionServiceInterface defines the contract for suggestion operations.
@@ -68,6 +79,8 @@ type SuggestionServiceInterface interface {
 // FriendServiceInterface defines the contract for friendship operations.
 type FriendServiceInterface interface {
 	SearchUsers(ctx context.Context, currentUserID uuid.UUID, query string) ([]models.UserSearchResult, error)
+	Suggestions(ctx context.Context, userID uuid.UUID) ([]models.FriendSuggestion, error)
+	DiscoverByEmailHashes(ctx context.Context, userID uuid.UUID, hashes []string) ([]models.UserSearchResult, error)
 	SendRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error)
 	AcceptRequest(ctx context.Context, userID, friendshipID uuid.UUID) (*models.Friendship, error)
 	RejectRequest(ctx context.Context, userID, friendshipID uuid.UUID) error
@@ -85,6 +98,17 @@ type FriendChecker interface {
 	IsFriend(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error)
 }
 
+// FriendGroupServiceInterface defines the contract for friend group operations.
+type FriendGroupServiceInterface interface {
+	List(ctx context.Context, ownerID uuid.UUID) ([]models.FriendGroup, error)
+	Create(ctx context.Context, ownerID uuid.UUID, name string) (*models.FriendGroup, error)
+	Rename(ctx context.Context, ownerID, groupID uuid.UUID, name string) error
+	Delete(ctx context.Context, ownerID, groupID uuid.UUID) error
+	AddMember(ctx context.Context, ownerID, groupID, memberID uuid.UUID) error
+	RemoveMember(ctx context.Context, ownerID, groupID, memberID uuid.UUID) error
+	SharedCardIDs(ctx context.Context, ownerID, viewerID uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
 // BlockServiceInterface defines the contract for blocking operations.
 type BlockServiceInterface interface {
 	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
@@ -101,6 +125,14 @@ type FriendInviteServiceInterface interface {
 	AcceptInvite(ctx context.Context, recipientID uuid.UUID, token string) (*models.UserSearchResult, error)
 }
 
+// CommentServiceInterface defines the contract for item comment operations.
+type CommentServiceInterface interface {
+	Create(ctx context.Context, userID, itemID uuid.UUID, parentID *uuid.UUID, body string) (*models.ItemComment, error)
+	Update(ctx context.Context, userID, commentID uuid.UUID, body string) (*models.ItemComment, error)
+	Delete(ctx context.Context, userID, commentID uuid.UUID) error
+	List(ctx context.Context, userID, itemID uuid.UUID) ([]models.ItemComment, error)
+}
+
 // ReactionServiceInterface defines the contract for reaction operations.
 type ReactionServiceInterface interface {
 	AddReaction(ctx context.Context, userID, itemID uuid.UUID, emoji string) (*models.Reaction, error)
@@ -125,6 +157,9 @@ type NotificationServiceInterface interface {
 	NotifyFriendRequestAccepted(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
 	NotifyFriendsNewCard(ctx context.Context, actorID, cardID uuid.UUID) error
 	NotifyFriendsBingo(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error
+	NotifyItemComment(ctx context.Context, recipientID, actorID, cardID, commentID uuid.UUID) error
+	NotifyChallengeResult(ctx context.Context, challengeID uuid.UUID, winnerID *uuid.UUID) error
+	NotifyOrgTemplate(ctx context.Context, actorID, templateID uuid.UUID) error
 }
 
 // EmailServiceInterface defines the contract for email operations.
@@ -136,7 +171,7 @@ type EmailServiceInterface interface {
 	SendPasswordResetEmail(ctx context.Context, userID uuid.UUID, email string) error
 	VerifyPasswordResetToken(ctx context.Context, token string) (uuid.UUID, error)
 	MarkPasswordResetUsed(ctx context.Context, token string) error
-	SendNotificationEmail(ctx context.Context, toEmail, subject, html, text string) error
+	SendNotificationEmail(ctx context.Context, notificationID uuid.UUID, toEmail, subject, html, text string) error
 	SendSupportEmail(ctx context.Context, fromEmail, category, message string, userID string) error
 }
 
@@ -147,3 +182,168 @@ type ApiTokenServiceInterface interface {
 	Delete(ctx context.Context, userID uuid.UUID, tokenID uuid.UUID) error
 	DeleteAll(ctx context.Context, userID uuid.UUID) error
 }
+
+// SessionRevoker is a lightweight interface for revoking a user's sessions.
+type SessionRevoker interface {
+	DeleteAllUserSessions(ctx context.Context, userID uuid.UUID) error
+}
+
+// CardLister is a lightweight interface for listing a user's cards.
+type CardLister interface {
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error)
+}
+
+// CardStatsCalculator computes completion stats for a loaded card.
+type CardStatsCalculator interface {
+	StatsFor(card *models.BingoCard) *models.CardStats
+}
+
+// CardStatsSource lists a user's cards and computes their stats.
+type CardStatsSource interface {
+	CardLister
+	CardStatsCalculator
+}
+
+// LeaderboardServiceInterface defines the contract for the friends leaderboard.
+type LeaderboardServiceInterface interface {
+	ForYear(ctx context.Context, viewerID uuid.UUID, year int) (*models.Leaderboard, error)
+}
+
+// ChallengeServiceInterface defines the contract for challenges between friends.
+type ChallengeServiceInterface interface {
+	Create(ctx context.Context, creatorID uuid.UUID, params models.CreateChallengeParams) (*models.Challenge, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.Challenge, error)
+	Get(ctx context.Context, userID, challengeID uuid.UUID) (*models.ChallengeDetail, error)
+	Join(ctx context.Context, userID, challengeID uuid.UUID) error
+	Leave(ctx context.Context, userID, challengeID uuid.UUID) error
+	Delete(ctx context.Context, userID, challengeID uuid.UUID) error
+}
+
+// ProfileServiceInterface defines the contract for public profile pages.
+type ProfileServiceInterface interface {
+	GetPublicProfile(ctx context.Context, username string) (*models.PublicProfile, error)
+}
+
+// JobStatusProvider reports the state of scheduled background jobs.
+type JobStatusProvider interface {
+	Status(ctx context.Context) ([]models.JobStatus, error)
+}
+
+// AdminServiceInterface defines the contract for admin console operations.
+type AdminServiceInterface interface {
+	SearchUsers(ctx context.Context, adminID uuid.UUID, params AdminUserSearchParams) ([]models.AdminUserSummary, error)
+	GetUser(ctx context.Context, adminID, userID uuid.UUID) (*models.AdminUserSummary, error)
+	ListUserCards(ctx context.Context, adminID, userID uuid.UUID) ([]*models.BingoCard, error)
+	SetDisabled(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error
+	ForceLogout(ctx context.Context, adminID, userID uuid.UUID) error
+	ResetAIFreeGenerations(ctx context.Context, adminID, userID uuid.UUID) error
+	ListAIGenerationLogs(ctx context.Context, adminID uuid.UUID, params AdminAILogParams) ([]models.AIGenerationLog, error)
+	ListAuditLog(ctx context.Context, params AdminAuditLogParams) ([]models.AdminAuditEntry, error)
+}
+
+// TemplateCardSource loads the card a template is published from and creates
+// cards from templates.
+type TemplateCardSource interface {
+	GetByID(ctx context.Context, cardID uuid.UUID) (*models.BingoCard, error)
+	Import(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error)
+}
+
+// TemplateServiceInterface defines the contract for the card template library.
+type TemplateServiceInterface interface {
+	Publish(ctx context.Context, params models.PublishTemplateParams) (*models.CardTemplate, error)
+	List(ctx context.Context, params models.TemplateListParams) ([]models.CardTemplate, bool, error)
+	ListMine(ctx context.Context, ownerID uuid.UUID) ([]models.CardTemplate, error)
+	Get(ctx context.Context, viewerID, templateID uuid.UUID) (*models.CardTemplate, error)
+	Update(ctx context.Context, ownerID, templateID uuid.UUID, params models.UpdateTemplateParams) (*models.CardTemplate, error)
+	Delete(ctx context.Context, ownerID, templateID uuid.UUID) error
+	Instantiate(ctx context.Context, templateID uuid.UUID, params models.InstantiateTemplateParams) (*models.BingoCard, error)
+}
+
+// OrganizationServiceInterface defines the contract for organization operations.
+type OrganizationServiceInterface interface {
+	Create(ctx context.Context, userID uuid.UUID, name string) (*models.Organization, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.Organization, error)
+	Get(ctx context.Context, userID, orgID uuid.UUID) (*models.Organization, error)
+	Rename(ctx context.Context, userID, orgID uuid.UUID, name string) error
+	Delete(ctx context.Context, userID, orgID uuid.UUID) error
+	ListMembers(ctx context.Context, userID, orgID uuid.UUID) ([]models.OrgMember, error)
+	SetMemberRole(ctx context.Context, userID, orgID, memberID uuid.UUID, role models.OrgRole) error
+	RemoveMember(ctx context.Context, userID, orgID, memberID uuid.UUID) error
+	CreateInvite(ctx context.Context, userID, orgID uuid.UUID, expiresInDays int) (*models.OrgInvite, string, error)
+	ListInvites(ctx context.Context, userID, orgID uuid.UUID) ([]models.OrgInvite, error)
+	RevokeInvite(ctx context.Context, userID, orgID, inviteID uuid.UUID) error
+	AcceptInvite(ctx context.Context, userID uuid.UUID, token string) (*models.Organization, error)
+	AddDomain(ctx context.Context, userID, orgID uuid.UUID, domain string) error
+	RemoveDomain(ctx context.Context, userID, orgID uuid.UUID, domain string) error
+	Joinable(ctx context.Context, userID uuid.UUID) ([]models.JoinableOrganization, error)
+	JoinByDomain(ctx context.Context, userID, orgID uuid.UUID) (*models.Organization, error)
+	MemberCards(ctx context.Context, viewerID, orgID, memberID uuid.UUID) (*models.OrgMemberCards, error)
+	Templates(ctx context.Context, userID, orgID uuid.UUID) ([]models.CardTemplate, error)
+	Stats(ctx context.Context, userID, orgID uuid.UUID, year int) (*models.OrgStats, error)
+}
+
+// UserBlocker is a lightweight interface for blocking users, used by the moderation service.
+type UserBlocker interface {
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+}
+
+// ModerationServiceInterface defines the contract for reporting and moderation operations.
+type ModerationServiceInterface interface {
+	CreateReport(ctx context.Context, params models.CreateReportParams) (*models.Report, error)
+	ListReports(ctx context.Context, adminID uuid.UUID, params ModerationListParams) ([]models.ModerationReport, error)
+	DismissReport(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+	HideReportedItem(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+	SuspendReportedUser(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+	UnhideItem(ctx context.Context, adminID, itemID uuid.UUID) error
+	HideReportedTemplate(ctx context.Context, adminID, reportID uuid.UUID, note *string) error
+	UnhideTemplate(ctx context.Context, adminID, templateID uuid.UUID) error
+}
+
+// AccountEventRecorder is a lightweight interface for appending account events.
+type AccountEventRecorder interface {
+	Record(ctx context.Context, userID uuid.UUID, eventType models.AccountEventType, outcome models.AccountEventOutcome, details map[string]any) error
+}
+
+// LoginAlertSender sends the security email for a sign-in from an unrecognized device.
+type LoginAlertSender interface {
+	SendNewLoginAlert(ctx context.Context, eventID uuid.UUID, toEmail string, meta RequestMeta, at time.Time) error
+}
+
+// AccountEventServiceInterface defines the contract for account activity operations.
+type AccountEventServiceInterface interface {
+	AccountEventRecorder
+	RecordLogin(ctx context.Context, user *models.User, eventType models.AccountEventType) error
+	List(ctx context.Context, userID uuid.UUID, params AccountEventListParams) ([]models.AccountEvent, error)
+}
+
+// LockoutNotifier warns a user that their account was temporarily locked.
+type LockoutNotifier interface {
+	SendLockoutAlert(ctx context.Context, toEmail string, until time.Time) error
+}
+
+// ThrottleServiceInterface defines the contract for brute-force protection used by handlers.
+type ThrottleServiceInterface interface {
+	Allow(ctx context.Context, route string, target ThrottleTarget) time.Duration
+	Fail(ctx context.Context, route string, target ThrottleTarget)
+	Hit(ctx context.Context, route string, target ThrottleTarget) time.Duration
+	Succeed(ctx context.Context, route string, target ThrottleTarget)
+}
+
+// ActivityRecorder is a lightweight interface for writing friend feed events.
+type ActivityRecorder interface {
+	Record(ctx context.Context, event models.ActivityEvent) error
+	Remove(ctx context.Context, actorID uuid.UUID, eventType models.ActivityEventType, itemID uuid.UUID) error
+}
+
+// ActivityServiceInterface defines the contract for the friend activity feed.
+type ActivityServiceInterface interface {
+	ActivityRecorder
+	Feed(ctx context.Context, viewerID uuid.UUID, params FeedParams) ([]models.FeedItem, string, error)
+}
+
+type CalendarServiceInterface interface {
+	GetFeed(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error)
+	CreateFeed(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error)
+	RevokeFeed(ctx context.Context, userID uuid.UUID) error
+	Render(ctx context.Context, token string, now time.Time) ([]byte, error)
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type LeaderboardService struct {
+	db    DBConn
+	cards CardStatsCalculator
+}
+
+func NewLeaderboardService(db DBConn, cards CardStatsCalculator) *LeaderboardService {
+	return &LeaderboardService{db: db, cards: cards}
+}
+
+// ForYear ranks the viewer and their accepted friends on their finalized
+// cards for a year. A friend's card only counts when it's shared with the
+// viewer, so the board never reveals more than the friend views do. Users who
+// opted out, are disabled, or are blocked either way are left off.
+func (s *LeaderboardService) ForYear(ctx context.Context, viewerID uuid.UUID, year int) (*models.Leaderboard, error) {
+	board := &models.Leaderboard{Year: year, Entries: []models.LeaderboardEntry{}}
+	if err := s.db.QueryRow(ctx,
+		"SELECT leaderboard_opt_out FROM users WHERE id = $1", viewerID,
+	).Scan(&board.OptedOut); err != nil {
+		return nil, fmt.Errorf("getting leaderboard opt-out: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT c.id, c.user_id, u.username, c.grid_size, c.has_free_space, c.free_space_position,
+		        bi.position, bi.is_completed, bi.completed_at
+		 FROM bingo_cards c
+		 JOIN users u ON u.id = c.user_id
+		 LEFT JOIN bingo_items bi ON bi.card_id = c.id
+		 WHERE c.year = $2
+		   AND c.is_finalized
+		   AND NOT u.leaderboard_opt_out
+		   AND u.disabled_at IS NULL
+		   AND (c.user_id = $1 OR (
+		     EXISTS (
+		       SELECT 1 FROM friendships f
+		       WHERE f.status = 'accepted'
+		         AND ((f.user_id = $1 AND f.friend_id = c.user_id) OR (f.friend_id = $1 AND f.user_id = c.user_id))
+		     )
+		     AND `+cardVisibleToSQL("c", "$1")+`
+		     AND NOT EXISTS (
+		       SELECT 1 FROM user_blocks b
+		       WHERE (b.blocker_id = $1 AND b.blocked_id = c.user_id)
+		          OR (b.blocker_id = c.user_id AND b.blocked_id = $1)
+		     )
+		   ))
+		 ORDER BY c.user_id, c.id, bi.position`,
+		viewerID, year,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("loading leaderboard cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*models.BingoCard
+	usernames := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var card models.BingoCard
+		var username string
+		var position *int
+		var completed *bool
+		var item models.BingoItem
+		if err := rows.Scan(&card.ID, &card.UserID, &username, &card.GridSize, &card.HasFreeSpace, &card.FreeSpacePos,
+			&position, &completed, &item.CompletedAt); err != nil {
+			return nil, fmt.Errorf("scanning leaderboard card: %w", err)
+		}
+		if len(cards) == 0 || cards[len(cards)-1].ID != card.ID {
+			card.Year = year
+			card.Items = []models.BingoItem{}
+			cards = append(cards, &card)
+			usernames[card.UserID] = username
+		}
+		if position != nil {
+			item.Position = *position
+			item.IsCompleted = completed != nil && *completed
+			last := cards[len(cards)-1]
+			last.Items = append(last.Items, item)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading leaderboard cards: %w", err)
+	}
+
+	byUser := make(map[uuid.UUID]int)
+	for _, card := range cards {
+		stats := s.cards.StatsFor(card)
+		idx, ok := byUser[card.UserID]
+		if !ok {
+			idx = len(board.Entries)
+			byUser[card.UserID] = idx
+			board.Entries = append(board.Entries, models.LeaderboardEntry{
+				UserID:   card.UserID,
+				Username: usernames[card.UserID],
+				IsYou:    card.UserID == viewerID,
+			})
+		}
+		entry := &board.Entries[idx]
+		entry.CardCount++
+		entry.TotalItems += stats.TotalItems
+		entry.CompletedItems += stats.CompletedItems
+		entry.Bingos += stats.BingosAchieved
+	}
+
+	models.RankLeaderboard(board.Entries)
+	return board, nil
+}
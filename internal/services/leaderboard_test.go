@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLeaderboardService_ForYear(t *testing.T) {
+	viewerID := uuid.New()
+	friendID := uuid.New()
+	viewerCard := uuid.New()
+	friendCard1 := uuid.New()
+	friendCard2 := uuid.New()
+	now := time.Now()
+	free := 4
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(false)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			for _, want := range []string{"leaderboard_opt_out", "visible_to_friends", "user_blocks", "status = 'accepted'"} {
+				if !strings.Contains(sql, want) {
+					t.Fatalf("expected query to check %q", want)
+				}
+			}
+			if args[1] != 2025 {
+				t.Fatalf("expected year 2025, got %v", args[1])
+			}
+			return &fakeRows{rows: [][]any{
+				// Viewer: 3x3 card with a free center, top row done (one bingo).
+				{viewerCard, viewerID, "viewer", 3, true, &free, itemPos(0), boolPtr(true), &now},
+				{viewerCard, viewerID, "viewer", 3, true, &free, itemPos(1), boolPtr(true), &now},
+				{viewerCard, viewerID, "viewer", 3, true, &free, itemPos(2), boolPtr(true), &now},
+				{viewerCard, viewerID, "viewer", 3, true, &free, itemPos(3), boolPtr(false), nil},
+				// Friend: two cards, one with no items yet.
+				{friendCard1, friendID, "friend", 3, false, nil, itemPos(0), boolPtr(true), &now},
+				{friendCard2, friendID, "friend", 3, false, nil, nil, nil, nil},
+			}}, nil
+		},
+	}
+
+	svc := NewLeaderboardService(db, NewCardService(nil))
+	board, err := svc.ForYear(context.Background(), viewerID, 2025)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(board.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(board.Entries))
+	}
+
+	first, second := board.Entries[0], board.Entries[1]
+	if first.UserID != viewerID || !first.IsYou || first.Rank != 1 {
+		t.Fatalf("expected viewer first, got %+v", first)
+	}
+	if first.CompletedItems != 3 || first.TotalItems != 8 || first.Bingos != 1 {
+		t.Fatalf("unexpected viewer totals: %+v", first)
+	}
+	if second.UserID != friendID || second.CardCount != 2 || second.TotalItems != 18 || second.CompletedItems != 1 {
+		t.Fatalf("expected friend cards combined, got %+v", second)
+	}
+}
+
+func TestLeaderboardService_ForYear_OptedOut(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(true)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{}, nil
+		},
+	}
+
+	board, err := NewLeaderboardService(db, NewCardService(nil)).ForYear(context.Background(), uuid.New(), 2025)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !board.OptedOut || board.Entries == nil || len(board.Entries) != 0 {
+		t.Fatalf("expected empty board flagged as opted out, got %+v", board)
+	}
+}
+
+func itemPos(v int) *int {
+	return &v
+}
@@ -0,0 +1,480 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const maxReportDetailsLength = 1000
+
+var (
+	ErrInvalidReportTarget  = errors.New("invalid report target type")
+	ErrInvalidReportReason  = errors.New("invalid report reason")
+	ErrReportDetailsTooLong = errors.New("report details too long")
+	ErrReportTargetNotFound = errors.New("reported content not found")
+	ErrCannotReportSelf     = errors.New("cannot report yourself")
+	ErrReportExists         = errors.New("report already submitted")
+	ErrReportNotFound       = errors.New("open report not found")
+	ErrReportNotItem        = errors.New("report does not target an item")
+	ErrReportNotTemplate    = errors.New("report does not target a template")
+)
+
+// ModerationListParams filters the moderation queue.
+type ModerationListParams struct {
+	Status models.ReportStatus
+	AdminListParams
+}
+
+type ModerationService struct {
+	db       DB
+	blocker  UserBlocker
+	sessions SessionRevoker
+}
+
+func NewModerationService(db DB, blocker UserBlocker, sessions SessionRevoker) *ModerationService {
+	return &ModerationService{db: db, blocker: blocker, sessions: sessions}
+}
+
+func isValidReportReason(reason models.ReportReason) bool {
+	for _, r := range models.ValidReportReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// reportableCardSQL is the condition for the card aliased card being one the
+// reporter can see: their own, or a finalized, unarchived card that is public,
+// shared with them as a friend or group member, or shared with an
+// organization they are both in, with no block between them. Cards they
+// can't see are treated as missing, so reports can't be used to probe for
+// private cards or their owners.
+func reportableCardSQL(card, reporter string) string {
+	return fmt.Sprintf(`(%[1]s.user_id = %[2]s OR (
+		   %[1]s.is_finalized AND NOT %[1]s.is_archived
+		   AND (
+		     %[1]s.visibility = 'public'
+		     OR (`+cardVisibleToSQL("%[1]s", "%[2]s")+` AND EXISTS (
+		       SELECT 1 FROM friendships f
+		       WHERE ((f.user_id = %[2]s AND f.friend_id = %[1]s.user_id) OR (f.user_id = %[1]s.user_id AND f.friend_id = %[2]s))
+		         AND f.status = 'accepted'
+		     ))
+		     OR (%[1]s.visibility = 'organization' AND EXISTS (
+		       SELECT 1 FROM organization_members om_owner
+		       JOIN organization_members om_reporter ON om_reporter.org_id = om_owner.org_id
+		       WHERE om_owner.user_id = %[1]s.user_id AND om_reporter.user_id = %[2]s
+		     ))
+		   )
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks ub
+		     WHERE (ub.blocker_id = %[2]s AND ub.blocked_id = %[1]s.user_id)
+		        OR (ub.blocker_id = %[1]s.user_id AND ub.blocked_id = %[2]s)
+		   )
+		 ))`, card, reporter)
+}
+
+// reportableTemplateSQL is the condition for the template aliased template
+// being one the reporter can report: their own, or one that hasn't been
+// hidden and is public or offered to an organization they are in. Unlisted
+// templates, reachable by anyone holding the ID, are treated as missing like
+// the rest, so reports can't confirm a template exists or name its owner.
+func reportableTemplateSQL(template, reporter string) string {
+	return fmt.Sprintf(`(%[1]s.owner_id = %[2]s OR (
+		   %[1]s.hidden_at IS NULL
+		   AND (
+		     %[1]s.visibility = 'public'
+		     OR (%[1]s.visibility = 'organization' AND EXISTS (
+		       SELECT 1 FROM organization_members om
+		       WHERE om.org_id = %[1]s.org_id AND om.user_id = %[2]s
+		     ))
+		   )
+		 ))`, template, reporter)
+}
+
+// CreateReport files a report against a user, card, item or template. When BlockUser is
+// set the reported user is also blocked for the reporter; a failed block is
+// logged but does not fail the report.
+func (s *ModerationService) CreateReport(ctx context.Context, params models.CreateReportParams) (*models.Report, error) {
+	if !isValidReportReason(params.Reason) {
+		return nil, ErrInvalidReportReason
+	}
+	if params.Details != nil {
+		trimmed := strings.TrimSpace(*params.Details)
+		if utf8.RuneCountInString(trimmed) > maxReportDetailsLength {
+			return nil, ErrReportDetailsTooLong
+		}
+		if trimmed == "" {
+			params.Details = nil
+		} else {
+			params.Details = &trimmed
+		}
+	}
+
+	report := &models.Report{
+		ReporterID: params.ReporterID,
+		TargetType: params.TargetType,
+		Reason:     params.Reason,
+		Details:    params.Details,
+	}
+
+	var err error
+	switch params.TargetType {
+	case models.ReportTargetUser:
+		var exists bool
+		err = s.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", params.TargetID).Scan(&exists)
+		if err == nil && !exists {
+			err = pgx.ErrNoRows
+		}
+		report.TargetUserID = params.TargetID
+	case models.ReportTargetCard:
+		var visible bool
+		err = s.db.QueryRow(ctx,
+			`SELECT bc.user_id, `+reportableCardSQL("bc", "$2")+`
+			 FROM bingo_cards bc
+			 WHERE bc.id = $1`,
+			params.TargetID, params.ReporterID,
+		).Scan(&report.TargetUserID, &visible)
+		if err == nil && !visible {
+			err = pgx.ErrNoRows
+		}
+		report.CardID = &params.TargetID
+	case models.ReportTargetItem:
+		var cardID uuid.UUID
+		var visible bool
+		err = s.db.QueryRow(ctx,
+			`SELECT bc.user_id, bi.card_id, `+reportableCardSQL("bc", "$2")+`
+			 FROM bingo_items bi
+			 JOIN bingo_cards bc ON bc.id = bi.card_id
+			 WHERE bi.id = $1`,
+			params.TargetID, params.ReporterID,
+		).Scan(&report.TargetUserID, &cardID, &visible)
+		if err == nil && !visible {
+			err = pgx.ErrNoRows
+		}
+		report.CardID = &cardID
+		report.ItemID = &params.TargetID
+	case models.ReportTargetTemplate:
+		var visible bool
+		err = s.db.QueryRow(ctx,
+			`SELECT t.owner_id, `+reportableTemplateSQL("t", "$2")+`
+			 FROM card_templates t
+			 WHERE t.id = $1`,
+			params.TargetID, params.ReporterID,
+		).Scan(&report.TargetUserID, &visible)
+		if err == nil && !visible {
+			err = pgx.ErrNoRows
+		}
+		report.TemplateID = &params.TargetID
+	default:
+		return nil, ErrInvalidReportTarget
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrReportTargetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve report target: %w", err)
+	}
+
+	if report.TargetUserID == params.ReporterID {
+		return nil, ErrCannotReportSelf
+	}
+
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO reports (reporter_id, target_type, target_user_id, card_id, item_id, template_id, reason, details)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING id, status, created_at`,
+		report.ReporterID, string(report.TargetType), report.TargetUserID, report.CardID, report.ItemID, report.TemplateID, string(report.Reason), report.Details,
+	).Scan(&report.ID, &report.Status, &report.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrReportExists
+		}
+		return nil, fmt.Errorf("create report: %w", err)
+	}
+
+	if params.BlockUser && s.blocker != nil {
+		if err := s.blocker.Block(ctx, params.ReporterID, report.TargetUserID); err != nil && !errors.Is(err, ErrBlockExists) {
+			logging.FromContext(ctx).Error("Failed to block reported user", map[string]interface{}{
+				"error":     err.Error(),
+				"report_id": report.ID.String(),
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// ListReports returns the moderation queue, oldest first so reports are
+// handled in the order they arrived.
+func (s *ModerationService) ListReports(ctx context.Context, adminID uuid.UUID, params ModerationListParams) ([]models.ModerationReport, error) {
+	limit, offset := params.normalized()
+	status := params.Status
+	if status == "" {
+		status = models.ReportStatusOpen
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT r.id, r.reporter_id, r.target_type, r.target_user_id, r.card_id, r.item_id, r.template_id, r.reason, r.details,
+		        r.status, r.resolved_by, r.resolved_at, r.resolution_note, r.created_at,
+		        ru.username, tu.username, tu.disabled_at IS NOT NULL,
+		        bc.title, bi.content, COALESCE(bi.hidden_at IS NOT NULL, false),
+		        ct.title, COALESCE(ct.hidden_at IS NOT NULL, false)
+		 FROM reports r
+		 JOIN users ru ON ru.id = r.reporter_id
+		 JOIN users tu ON tu.id = r.target_user_id
+		 LEFT JOIN bingo_cards bc ON bc.id = r.card_id
+		 LEFT JOIN bingo_items bi ON bi.id = r.item_id
+		 LEFT JOIN card_templates ct ON ct.id = r.template_id
+		 WHERE r.status = $1
+		 ORDER BY r.created_at ASC
+		 LIMIT $2 OFFSET $3`,
+		string(status), limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.ModerationReport{}
+	for rows.Next() {
+		var r models.ModerationReport
+		if err := rows.Scan(
+			&r.ID, &r.ReporterID, &r.TargetType, &r.TargetUserID, &r.CardID, &r.ItemID, &r.TemplateID, &r.Reason, &r.Details,
+			&r.Status, &r.ResolvedBy, &r.ResolvedAt, &r.ResolutionNote, &r.CreatedAt,
+			&r.ReporterUsername, &r.TargetUsername, &r.TargetDisabled,
+			&r.CardTitle, &r.ItemContent, &r.ItemHidden,
+			&r.TemplateTitle, &r.TemplateHidden,
+		); err != nil {
+			return nil, fmt.Errorf("scan report: %w", err)
+		}
+		reports = append(reports, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+
+	if err := recordAdminAudit(ctx, s.db, adminID, models.AdminActionViewReports, nil, map[string]any{
+		"status": string(status),
+		"limit":  limit,
+		"offset": offset,
+	}); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// DismissReport closes an open report without taking action.
+func (s *ModerationService) DismissReport(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+	return inTx(ctx, s.db, func(tx Tx) error {
+		var targetUserID uuid.UUID
+		err := tx.QueryRow(ctx,
+			`UPDATE reports
+			 SET status = 'dismissed', resolved_by = $2, resolved_at = NOW(), resolution_note = $3
+			 WHERE id = $1 AND status = 'open'
+			 RETURNING target_user_id`,
+			reportID, adminID, note,
+		).Scan(&targetUserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReportNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("dismiss report: %w", err)
+		}
+		return recordAdminAudit(ctx, tx, adminID, models.AdminActionDismissReport, &targetUserID, map[string]any{
+			"report_id": reportID.String(),
+		})
+	})
+}
+
+// HideReportedItem hides the item named by an open report from everyone but
+// its owner and resolves every open report against that item.
+func (s *ModerationService) HideReportedItem(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+	return inTx(ctx, s.db, func(tx Tx) error {
+		report, err := lockOpenReport(ctx, tx, reportID)
+		if err != nil {
+			return err
+		}
+		if report.TargetType != models.ReportTargetItem || report.ItemID == nil {
+			return ErrReportNotItem
+		}
+
+		if _, err := tx.Exec(ctx,
+			"UPDATE bingo_items SET hidden_at = COALESCE(hidden_at, NOW()) WHERE id = $1",
+			*report.ItemID,
+		); err != nil {
+			return fmt.Errorf("hide item: %w", err)
+		}
+
+		resolved, err := resolveOpenReports(ctx, tx, adminID, "item_id", *report.ItemID, note)
+		if err != nil {
+			return err
+		}
+
+		return recordAdminAudit(ctx, tx, adminID, models.AdminActionHideItem, &report.TargetUserID, map[string]any{
+			"report_id":        reportID.String(),
+			"item_id":          report.ItemID.String(),
+			"resolved_reports": resolved,
+		})
+	})
+}
+
+// HideReportedTemplate takes the template named by an open report out of the
+// gallery and off its share link, and resolves every open report against it.
+// The owner can still see it in their own list.
+func (s *ModerationService) HideReportedTemplate(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+	return inTx(ctx, s.db, func(tx Tx) error {
+		report, err := lockOpenReport(ctx, tx, reportID)
+		if err != nil {
+			return err
+		}
+		if report.TargetType != models.ReportTargetTemplate || report.TemplateID == nil {
+			return ErrReportNotTemplate
+		}
+
+		if _, err := tx.Exec(ctx,
+			"UPDATE card_templates SET hidden_at = COALESCE(hidden_at, NOW()) WHERE id = $1",
+			*report.TemplateID,
+		); err != nil {
+			return fmt.Errorf("hide template: %w", err)
+		}
+
+		resolved, err := resolveOpenReports(ctx, tx, adminID, "template_id", *report.TemplateID, note)
+		if err != nil {
+			return err
+		}
+
+		return recordAdminAudit(ctx, tx, adminID, models.AdminActionHideTemplate, &report.TargetUserID, map[string]any{
+			"report_id":        reportID.String(),
+			"template_id":      report.TemplateID.String(),
+			"resolved_reports": resolved,
+		})
+	})
+}
+
+// SuspendReportedUser disables the account named by an open report, resolves
+// every open report against that user and revokes their sessions.
+func (s *ModerationService) SuspendReportedUser(ctx context.Context, adminID, reportID uuid.UUID, note *string) error {
+	var targetUserID uuid.UUID
+	err := inTx(ctx, s.db, func(tx Tx) error {
+		report, err := lockOpenReport(ctx, tx, reportID)
+		if err != nil {
+			return err
+		}
+		if report.TargetUserID == adminID {
+			return ErrAdminCannotModifySelf
+		}
+		targetUserID = report.TargetUserID
+
+		if _, err := tx.Exec(ctx,
+			"UPDATE users SET disabled_at = COALESCE(disabled_at, NOW()), updated_at = NOW() WHERE id = $1",
+			targetUserID,
+		); err != nil {
+			return fmt.Errorf("suspend user: %w", err)
+		}
+
+		resolved, err := resolveOpenReports(ctx, tx, adminID, "target_user_id", targetUserID, note)
+		if err != nil {
+			return err
+		}
+
+		return recordAdminAudit(ctx, tx, adminID, models.AdminActionSuspendUser, &targetUserID, map[string]any{
+			"report_id":        reportID.String(),
+			"resolved_reports": resolved,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.sessions.DeleteAllUserSessions(ctx, targetUserID); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// UnhideItem reverses HideReportedItem.
+func (s *ModerationService) UnhideItem(ctx context.Context, adminID, itemID uuid.UUID) error {
+	return inTx(ctx, s.db, func(tx Tx) error {
+		var ownerID uuid.UUID
+		err := tx.QueryRow(ctx,
+			`UPDATE bingo_items bi SET hidden_at = NULL
+			 FROM bingo_cards bc
+			 WHERE bi.id = $1 AND bc.id = bi.card_id
+			 RETURNING bc.user_id`,
+			itemID,
+		).Scan(&ownerID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrItemNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("unhide item: %w", err)
+		}
+		return recordAdminAudit(ctx, tx, adminID, models.AdminActionUnhideItem, &ownerID, map[string]any{
+			"item_id": itemID.String(),
+		})
+	})
+}
+
+// UnhideTemplate reverses HideReportedTemplate.
+func (s *ModerationService) UnhideTemplate(ctx context.Context, adminID, templateID uuid.UUID) error {
+	return inTx(ctx, s.db, func(tx Tx) error {
+		var ownerID uuid.UUID
+		err := tx.QueryRow(ctx,
+			"UPDATE card_templates SET hidden_at = NULL WHERE id = $1 RETURNING owner_id",
+			templateID,
+		).Scan(&ownerID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTemplateNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("unhide template: %w", err)
+		}
+		return recordAdminAudit(ctx, tx, adminID, models.AdminActionUnhideTemplate, &ownerID, map[string]any{
+			"template_id": templateID.String(),
+		})
+	})
+}
+
+func lockOpenReport(ctx context.Context, tx Tx, reportID uuid.UUID) (*models.Report, error) {
+	report := &models.Report{ID: reportID}
+	err := tx.QueryRow(ctx,
+		`SELECT target_type, target_user_id, item_id, template_id
+		 FROM reports WHERE id = $1 AND status = 'open'
+		 FOR UPDATE`,
+		reportID,
+	).Scan(&report.TargetType, &report.TargetUserID, &report.ItemID, &report.TemplateID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get report: %w", err)
+	}
+	return report, nil
+}
+
+// resolveOpenReports marks every open report matching column = id as
+// actioned. column is always a constant supplied by this file.
+func resolveOpenReports(ctx context.Context, tx Tx, adminID uuid.UUID, column string, id uuid.UUID, note *string) (int64, error) {
+	result, err := tx.Exec(ctx,
+		`UPDATE reports
+		 SET status = 'actioned', resolved_by = $2, resolved_at = NOW(), resolution_note = $3
+		 WHERE `+column+` = $1 AND status = 'open'`,
+		id, adminID, note,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("resolve reports: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,474 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type fakeUserBlocker struct {
+	calls [][2]uuid.UUID
+	err   error
+}
+
+func (f *fakeUserBlocker) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	f.calls = append(f.calls, [2]uuid.UUID{blockerID, blockedID})
+	return f.err
+}
+
+func TestModerationService_CreateReport_Validation(t *testing.T) {
+	svc := NewModerationService(&fakeDB{}, &fakeUserBlocker{}, &fakeSessionRevoker{})
+	long := strings.Repeat("x", maxReportDetailsLength+1)
+
+	tests := []struct {
+		name   string
+		params models.CreateReportParams
+		want   error
+	}{
+		{"bad reason", models.CreateReportParams{TargetType: models.ReportTargetUser, Reason: "rude"}, ErrInvalidReportReason},
+		{"bad target", models.CreateReportParams{TargetType: "comment", Reason: models.ReportReasonSpam}, ErrInvalidReportTarget},
+		{"long details", models.CreateReportParams{TargetType: models.ReportTargetUser, Reason: models.ReportReasonSpam, Details: &long}, ErrReportDetailsTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreateReport(context.Background(), tt.params); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestModerationService_CreateReport_ItemTarget(t *testing.T) {
+	reporterID := uuid.New()
+	ownerID := uuid.New()
+	cardID := uuid.New()
+	itemID := uuid.New()
+	reportID := uuid.New()
+	blocker := &fakeUserBlocker{}
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM bingo_items"):
+				if args[1] != reporterID {
+					t.Fatalf("expected visibility checked for the reporter, got %v", args)
+				}
+				return rowFromValues(ownerID, cardID, true)
+			case strings.Contains(sql, "INSERT INTO reports"):
+				if args[1] != "item" || args[2] != ownerID || *(args[3].(*uuid.UUID)) != cardID || *(args[4].(*uuid.UUID)) != itemID {
+					t.Fatalf("unexpected insert args: %v", args)
+				}
+				if details := args[7].(*string); details == nil || *details != "bad words" {
+					t.Fatalf("expected trimmed details, got %v", args[6])
+				}
+				return rowFromValues(reportID, "open", time.Now())
+			}
+			t.Fatalf("unexpected query: %s", sql)
+			return nil
+		},
+	}
+	svc := NewModerationService(db, blocker, &fakeSessionRevoker{})
+
+	details := "  bad words  "
+	report, err := svc.CreateReport(context.Background(), models.CreateReportParams{
+		ReporterID: reporterID,
+		TargetType: models.ReportTargetItem,
+		TargetID:   itemID,
+		Reason:     models.ReportReasonHarassment,
+		Details:    &details,
+		BlockUser:  true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.ID != reportID || report.Status != models.ReportStatusOpen || report.TargetUserID != ownerID {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(blocker.calls) != 1 || blocker.calls[0] != [2]uuid.UUID{reporterID, ownerID} {
+		t.Fatalf("expected reporter to block owner, got %v", blocker.calls)
+	}
+}
+
+func TestModerationService_CreateReport_BlockFailureDoesNotFail(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "EXISTS") {
+				return rowFromValues(true)
+			}
+			return rowFromValues(uuid.New(), "open", time.Now())
+		},
+	}
+	blocker := &fakeUserBlocker{err: errors.New("boom")}
+	svc := NewModerationService(db, blocker, &fakeSessionRevoker{})
+
+	_, err := svc.CreateReport(context.Background(), models.CreateReportParams{
+		ReporterID: uuid.New(),
+		TargetType: models.ReportTargetUser,
+		TargetID:   uuid.New(),
+		Reason:     models.ReportReasonSpam,
+		BlockUser:  true,
+	})
+	if err != nil {
+		t.Fatalf("expected report to succeed despite block failure, got %v", err)
+	}
+	if len(blocker.calls) != 1 {
+		t.Fatal("expected block attempt")
+	}
+}
+
+func TestModerationService_CreateReport_Self(t *testing.T) {
+	reporterID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM bingo_cards") {
+				return rowFromValues(reporterID, true)
+			}
+			t.Fatalf("unexpected query: %s", sql)
+			return nil
+		},
+	}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+	_, err := svc.CreateReport(context.Background(), models.CreateReportParams{
+		ReporterID: reporterID,
+		TargetType: models.ReportTargetCard,
+		TargetID:   uuid.New(),
+		Reason:     models.ReportReasonSpam,
+	})
+	if !errors.Is(err, ErrCannotReportSelf) {
+		t.Fatalf("expected ErrCannotReportSelf, got %v", err)
+	}
+}
+
+func TestModerationService_CreateReport_InvisibleCardIsNotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "INSERT INTO reports") {
+				t.Fatal("report should not be filed for a card the reporter can't see")
+			}
+			if !strings.Contains(sql, "is_finalized") || !strings.Contains(sql, "user_blocks") {
+				t.Fatalf("expected a visibility check, got %s", sql)
+			}
+			if strings.Contains(sql, "FROM bingo_items") {
+				return rowFromValues(uuid.New(), uuid.New(), false)
+			}
+			return rowFromValues(uuid.New(), false)
+		},
+	}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+
+	for _, target := range []models.ReportTargetType{models.ReportTargetCard, models.ReportTargetItem} {
+		_, err := svc.CreateReport(context.Background(), models.CreateReportParams{
+			ReporterID: uuid.New(),
+			TargetType: target,
+			TargetID:   uuid.New(),
+			Reason:     models.ReportReasonSpam,
+		})
+		if !errors.Is(err, ErrReportTargetNotFound) {
+			t.Fatalf("%s: expected ErrReportTargetNotFound, got %v", target, err)
+		}
+	}
+}
+
+func TestModerationService_CreateReport_NotFoundAndDuplicate(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(false)
+		},
+	}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+	params := models.CreateReportParams{ReporterID: uuid.New(), TargetType: models.ReportTargetUser, TargetID: uuid.New(), Reason: models.ReportReasonSpam}
+	if _, err := svc.CreateReport(context.Background(), params); !errors.Is(err, ErrReportTargetNotFound) {
+		t.Fatalf("expected ErrReportTargetNotFound, got %v", err)
+	}
+
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "INSERT INTO reports") {
+			return fakeRow{scanFunc: func(dest ...any) error { return &pgconn.PgError{Code: "23505"} }}
+		}
+		return rowFromValues(true)
+	}
+	if _, err := svc.CreateReport(context.Background(), params); !errors.Is(err, ErrReportExists) {
+		t.Fatalf("expected ErrReportExists, got %v", err)
+	}
+}
+
+func TestModerationService_DismissReport_NotOpen(t *testing.T) {
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+	if err := svc.DismissReport(context.Background(), uuid.New(), uuid.New(), nil); !errors.Is(err, ErrReportNotFound) {
+		t.Fatalf("expected ErrReportNotFound, got %v", err)
+	}
+}
+
+func TestModerationService_HideReportedItem(t *testing.T) {
+	audit := &auditCapture{}
+	auditExec := audit.exec(t)
+	ownerID := uuid.New()
+	itemID := uuid.New()
+	var hid, resolved, committed bool
+
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "FOR UPDATE") {
+				t.Fatalf("expected report to be locked: %s", sql)
+			}
+			return rowFromValues("item", ownerID, &itemID, (*uuid.UUID)(nil))
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			switch {
+			case strings.Contains(sql, "UPDATE bingo_items SET hidden_at"):
+				hid = args[0] == itemID
+				return fakeCommandTag{rowsAffected: 1}, nil
+			case strings.Contains(sql, "UPDATE reports"):
+				resolved = strings.Contains(sql, "item_id = $1") && args[0] == itemID
+				return fakeCommandTag{rowsAffected: 2}, nil
+			}
+			return auditExec(ctx, sql, args...)
+		},
+		CommitFunc: func(ctx context.Context) error {
+			committed = true
+			return nil
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+
+	if err := svc.HideReportedItem(context.Background(), uuid.New(), uuid.New(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hid || !resolved || !committed {
+		t.Fatalf("expected hide, resolve and commit: hid=%v resolved=%v committed=%v", hid, resolved, committed)
+	}
+	if audit.actions[0] != string(models.AdminActionHideItem) || audit.details[0]["resolved_reports"] != float64(2) {
+		t.Fatalf("unexpected audit: %v %v", audit.actions, audit.details)
+	}
+}
+
+func TestModerationService_HideReportedItem_NotItem(t *testing.T) {
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues("user", uuid.New(), (*uuid.UUID)(nil), (*uuid.UUID)(nil))
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+	if err := svc.HideReportedItem(context.Background(), uuid.New(), uuid.New(), nil); !errors.Is(err, ErrReportNotItem) {
+		t.Fatalf("expected ErrReportNotItem, got %v", err)
+	}
+}
+
+func TestModerationService_SuspendReportedUser(t *testing.T) {
+	audit := &auditCapture{}
+	auditExec := audit.exec(t)
+	ownerID := uuid.New()
+	var suspended bool
+
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues("card", ownerID, (*uuid.UUID)(nil), (*uuid.UUID)(nil))
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			switch {
+			case strings.Contains(sql, "UPDATE users SET disabled_at"):
+				suspended = args[0] == ownerID
+				return fakeCommandTag{rowsAffected: 1}, nil
+			case strings.Contains(sql, "UPDATE reports"):
+				if !strings.Contains(sql, "target_user_id = $1") {
+					t.Fatalf("expected reports resolved by target user: %s", sql)
+				}
+				return fakeCommandTag{rowsAffected: 1}, nil
+			}
+			return auditExec(ctx, sql, args...)
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	sessions := &fakeSessionRevoker{}
+	svc := NewModerationService(db, &fakeUserBlocker{}, sessions)
+
+	if err := svc.SuspendReportedUser(context.Background(), uuid.New(), uuid.New(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !suspended {
+		t.Fatal("expected user to be suspended")
+	}
+	if len(sessions.revoked) != 1 || sessions.revoked[0] != ownerID {
+		t.Fatalf("expected sessions revoked, got %v", sessions.revoked)
+	}
+	if audit.actions[0] != string(models.AdminActionSuspendUser) {
+		t.Fatalf("unexpected audit: %v", audit.actions)
+	}
+}
+
+func TestModerationService_SuspendReportedUser_Self(t *testing.T) {
+	adminID := uuid.New()
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues("user", adminID, (*uuid.UUID)(nil), (*uuid.UUID)(nil))
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	sessions := &fakeSessionRevoker{}
+	svc := NewModerationService(db, &fakeUserBlocker{}, sessions)
+	if err := svc.SuspendReportedUser(context.Background(), adminID, uuid.New(), nil); !errors.Is(err, ErrAdminCannotModifySelf) {
+		t.Fatalf("expected ErrAdminCannotModifySelf, got %v", err)
+	}
+	if len(sessions.revoked) != 0 {
+		t.Fatal("expected no sessions revoked")
+	}
+}
+
+func TestModerationService_UnhideItem_NotFound(t *testing.T) {
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+	if err := svc.UnhideItem(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
+func TestModerationService_CreateReport_TemplateTarget(t *testing.T) {
+	ownerID := uuid.New()
+	templateID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM card_templates"):
+				return rowFromValues(ownerID, true)
+			case strings.Contains(sql, "INSERT INTO reports"):
+				if args[1] != "template" || args[3].(*uuid.UUID) != nil || *(args[5].(*uuid.UUID)) != templateID {
+					t.Fatalf("unexpected insert args: %v", args)
+				}
+				return rowFromValues(uuid.New(), "open", time.Now())
+			}
+			t.Fatalf("unexpected query: %s", sql)
+			return nil
+		},
+	}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+
+	report, err := svc.CreateReport(context.Background(), models.CreateReportParams{
+		ReporterID: uuid.New(),
+		TargetType: models.ReportTargetTemplate,
+		TargetID:   templateID,
+		Reason:     models.ReportReasonSpam,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.TargetUserID != ownerID || report.TemplateID == nil || *report.TemplateID != templateID {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestModerationService_CreateReport_UnreachableTemplateIsNotFound(t *testing.T) {
+	reporterID := uuid.New()
+	tests := []struct {
+		name       string
+		visibility string
+		hidden     bool
+	}{
+		{name: "unlisted", visibility: "unlisted"},
+		{name: "organization outsider", visibility: "organization"},
+		{name: "hidden public", visibility: "public", hidden: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if strings.Contains(sql, "INSERT INTO reports") {
+						t.Fatal("report should not be filed for a template the reporter can't reach")
+					}
+					if !strings.Contains(sql, "hidden_at IS NULL") || !strings.Contains(sql, "visibility = 'public'") ||
+						!strings.Contains(sql, "om.user_id = $2") || strings.Contains(sql, "unlisted") {
+						t.Fatalf("expected a template visibility check, got %s", sql)
+					}
+					if len(args) != 2 || args[1] != reporterID {
+						t.Fatalf("expected the reporter to be checked, got %v", args)
+					}
+					// The reporter is neither the owner nor an organization
+					// member, so only an unhidden public template is theirs to
+					// report.
+					return rowFromValues(uuid.New(), tt.visibility == "public" && !tt.hidden)
+				},
+			}
+			svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+
+			_, err := svc.CreateReport(context.Background(), models.CreateReportParams{
+				ReporterID: reporterID,
+				TargetType: models.ReportTargetTemplate,
+				TargetID:   uuid.New(),
+				Reason:     models.ReportReasonSpam,
+			})
+			if !errors.Is(err, ErrReportTargetNotFound) {
+				t.Fatalf("expected ErrReportTargetNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestModerationService_HideReportedTemplate(t *testing.T) {
+	audit := &auditCapture{}
+	auditExec := audit.exec(t)
+	templateID := uuid.New()
+	var hid, resolved bool
+
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues("template", uuid.New(), (*uuid.UUID)(nil), &templateID)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			switch {
+			case strings.Contains(sql, "UPDATE card_templates SET hidden_at"):
+				hid = args[0] == templateID
+				return fakeCommandTag{rowsAffected: 1}, nil
+			case strings.Contains(sql, "UPDATE reports"):
+				resolved = strings.Contains(sql, "template_id = $1") && args[0] == templateID
+				return fakeCommandTag{rowsAffected: 1}, nil
+			}
+			return auditExec(ctx, sql, args...)
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+
+	if err := svc.HideReportedTemplate(context.Background(), uuid.New(), uuid.New(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hid || !resolved {
+		t.Fatalf("expected hide and resolve: hid=%v resolved=%v", hid, resolved)
+	}
+	if audit.actions[0] != string(models.AdminActionHideTemplate) {
+		t.Fatalf("unexpected audit: %v", audit.actions)
+	}
+}
+
+func TestModerationService_HideReportedTemplate_NotTemplate(t *testing.T) {
+	itemID := uuid.New()
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues("item", uuid.New(), &itemID, (*uuid.UUID)(nil))
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	svc := NewModerationService(db, &fakeUserBlocker{}, &fakeSessionRevoker{})
+	if err := svc.HideReportedTemplate(context.Background(), uuid.New(), uuid.New(), nil); !errors.Is(err, ErrReportNotTemplate) {
+		t.Fatalf("expected ErrReportNotTemplate, got %v", err)
+	}
+}
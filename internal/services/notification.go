@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/i18n"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
@@ -24,11 +26,17 @@ var notificationSettingsColumns = map[string]struct{}{
 	"in_app_friend_request_accepted": {},
 	"in_app_friend_bingo":            {},
 	"in_app_friend_new_card":         {},
+	"in_app_item_comment":            {},
+	"in_app_challenge_result":        {},
+	"in_app_org_template":            {},
 	"email_enabled":                  {},
 	"email_friend_request_received":  {},
 	"email_friend_request_accepted":  {},
 	"email_friend_bingo":             {},
 	"email_friend_new_card":          {},
+	"email_item_comment":             {},
+	"email_challenge_result":         {},
+	"email_org_template":             {},
 }
 
 type NotificationListParams struct {
@@ -115,11 +123,17 @@ func (s *NotificationService) UpdateSettings(ctx context.Context, userID uuid.UU
 	addBool("in_app_friend_request_accepted", patch.InAppFriendRequestAccepted)
 	addBool("in_app_friend_bingo", patch.InAppFriendBingo)
 	addBool("in_app_friend_new_card", patch.InAppFriendNewCard)
+	addBool("in_app_item_comment", patch.InAppItemComment)
+	addBool("in_app_challenge_result", patch.InAppChallengeResult)
+	addBool("in_app_org_template", patch.InAppOrgTemplate)
 	addBool("email_enabled", patch.EmailEnabled)
 	addBool("email_friend_request_received", patch.EmailFriendRequestReceived)
 	addBool("email_friend_request_accepted", patch.EmailFriendRequestAccepted)
 	addBool("email_friend_bingo", patch.EmailFriendBingo)
 	addBool("email_friend_new_card", patch.EmailFriendNewCard)
+	addBool("email_item_comment", patch.EmailItemComment)
+	addBool("email_challenge_result", patch.EmailChallengeResult)
+	addBool("email_org_template", patch.EmailOrgTemplate)
 
 	if invalidColumn != "" {
 		return nil, fmt.Errorf("invalid notification settings column: %s", invalidColumn)
@@ -166,11 +180,15 @@ func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, params
 
 	query := fmt.Sprintf(
 		`SELECT n.id, n.user_id, n.type, n.actor_user_id, au.username,
-		        n.friendship_id, n.card_id, c.title, c.year, n.bingo_count,
+		        n.friendship_id, n.card_id, c.title, c.year, n.bingo_count, n.challenge_id, ch.name,
+		        n.template_id, t.title, o.name,
 		        n.in_app_delivered, n.email_delivered, n.email_sent_at, n.read_at, n.created_at
 		 FROM notifications n
 		 LEFT JOIN users au ON n.actor_user_id = au.id
 		 LEFT JOIN bingo_cards c ON n.card_id = c.id
+		 LEFT JOIN challenges ch ON n.challenge_id = ch.id
+		 LEFT JOIN card_templates t ON n.template_id = t.id
+		 LEFT JOIN organizations o ON t.org_id = o.id
 		 WHERE %s
 		 ORDER BY n.created_at DESC
 		 LIMIT $%d`,
@@ -200,6 +218,11 @@ func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, params
 			&n.CardTitle,
 			&n.CardYear,
 			&n.BingoCount,
+			&n.ChallengeID,
+			&n.ChallengeName,
+			&n.TemplateID,
+			&n.TemplateTitle,
+			&n.OrgName,
 			&n.InAppDelivered,
 			&n.EmailDelivered,
 			&n.EmailSentAt,
@@ -281,11 +304,11 @@ func (s *NotificationService) UnreadCount(ctx context.Context, userID uuid.UUID)
 }
 
 func (s *NotificationService) NotifyFriendRequestReceived(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error {
-	return s.notifySingle(ctx, recipientID, actorID, friendshipID, nil, nil, models.NotificationTypeFriendRequestReceived)
+	return s.notifySingle(ctx, recipientID, actorID, &friendshipID, nil, nil, nil, models.NotificationTypeFriendRequestReceived)
 }
 
 func (s *NotificationService) NotifyFriendRequestAccepted(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error {
-	return s.notifySingle(ctx, recipientID, actorID, friendshipID, nil, nil, models.NotificationTypeFriendRequestAccepted)
+	return s.notifySingle(ctx, recipientID, actorID, &friendshipID, nil, nil, nil, models.NotificationTypeFriendRequestAccepted)
 }
 
 func (s *NotificationService) NotifyFriendsNewCard(ctx context.Context, actorID, cardID uuid.UUID) error {
@@ -299,6 +322,134 @@ func (s *NotificationService) NotifyFriendsBingo(ctx context.Context, actorID, c
 	return s.notifyFriends(ctx, actorID, cardID, &bingoCount, models.NotificationTypeFriendBingo)
 }
 
+// NotifyItemComment tells a user about a comment on an item they own or a
+// reply to their comment. When the recipient isn't the card owner, the
+// notification carries their friendship with the owner so it can link to the
+// friend's card.
+func (s *NotificationService) NotifyItemComment(ctx context.Context, recipientID, actorID, cardID, commentID uuid.UUID) error {
+	var friendshipID *uuid.UUID
+	err := s.db.QueryRow(ctx,
+		`SELECT f.id
+		 FROM friendships f
+		 JOIN bingo_cards c ON c.id = $2
+		 WHERE f.status = 'accepted'
+		   AND ((f.user_id = $1 AND f.friend_id = c.user_id) OR (f.friend_id = $1 AND f.user_id = c.user_id))`,
+		recipientID, cardID,
+	).Scan(&friendshipID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("find card owner friendship: %w", err)
+	}
+	return s.notifySingle(ctx, recipientID, actorID, friendshipID, &cardID, nil, &commentID, models.NotificationTypeItemComment)
+}
+
+// NotifyChallengeResult tells everyone who joined a challenge that it has
+// finished. The winner, if anyone completed anything, is the actor.
+func (s *NotificationService) NotifyChallengeResult(ctx context.Context, challengeID uuid.UUID, winnerID *uuid.UUID) error {
+	nType := models.NotificationTypeChallengeResult
+	inAppCol, emailCol, err := notificationScenarioColumns(nType)
+	if err != nil {
+		return err
+	}
+
+	inAppEnabled := "COALESCE(ns.in_app_enabled, true)"
+	emailEnabled := "COALESCE(ns.email_enabled, false)"
+	inAppSetting := fmt.Sprintf("COALESCE(ns.%s, true)", inAppCol)
+	emailSetting := fmt.Sprintf("COALESCE(ns.%s, false)", emailCol)
+
+	query := fmt.Sprintf(
+		`INSERT INTO notifications (user_id, type, actor_user_id, challenge_id, in_app_delivered, email_delivered)
+		 SELECT p.user_id, $2, $3, $1,
+		        (%s AND %s) AS in_app_delivered,
+		        (%s AND %s AND u.email_verified) AS email_delivered
+		 FROM challenge_participants p
+		 JOIN users u ON u.id = p.user_id
+		 LEFT JOIN notification_settings ns ON ns.user_id = p.user_id
+		 WHERE p.challenge_id = $1
+		   AND p.joined_at IS NOT NULL
+		   AND ((%s AND %s) OR (%s AND %s AND u.email_verified))
+		 ON CONFLICT DO NOTHING
+		 RETURNING id, user_id, email_delivered`,
+		inAppEnabled,
+		inAppSetting,
+		emailEnabled,
+		emailSetting,
+		inAppEnabled,
+		inAppSetting,
+		emailEnabled,
+		emailSetting,
+	)
+
+	rows, err := s.db.Query(ctx, query, challengeID, string(nType), winnerID)
+	if err != nil {
+		return fmt.Errorf("insert notifications: %w", err)
+	}
+	defer rows.Close()
+
+	inserted := collectInserted(rows)
+	if len(inserted.emailIDs) > 0 {
+		s.dispatchEmails(inserted.emailIDs)
+	}
+
+	return nil
+}
+
+// NotifyOrgTemplate tells the members of an organization, other than the
+// admin who published it, about a new organization template.
+func (s *NotificationService) NotifyOrgTemplate(ctx context.Context, actorID, templateID uuid.UUID) error {
+	nType := models.NotificationTypeOrgTemplate
+	inAppCol, emailCol, err := notificationScenarioColumns(nType)
+	if err != nil {
+		return err
+	}
+
+	inAppEnabled := "COALESCE(ns.in_app_enabled, true)"
+	emailEnabled := "COALESCE(ns.email_enabled, false)"
+	inAppSetting := fmt.Sprintf("COALESCE(ns.%s, true)", inAppCol)
+	emailSetting := fmt.Sprintf("COALESCE(ns.%s, false)", emailCol)
+
+	query := fmt.Sprintf(
+		`INSERT INTO notifications (user_id, type, actor_user_id, template_id, in_app_delivered, email_delivered)
+		 SELECT m.user_id, $2, $3, $1,
+		        (%s AND %s) AS in_app_delivered,
+		        (%s AND %s AND u.email_verified) AS email_delivered
+		 FROM card_templates t
+		 JOIN organization_members m ON m.org_id = t.org_id
+		 JOIN users u ON u.id = m.user_id
+		 LEFT JOIN notification_settings ns ON ns.user_id = m.user_id
+		 WHERE t.id = $1
+		   AND m.user_id <> $3
+		   AND ((%s AND %s) OR (%s AND %s AND u.email_verified))
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks
+		     WHERE (blocker_id = $3 AND blocked_id = m.user_id)
+		        OR (blocker_id = m.user_id AND blocked_id = $3)
+		   )
+		 ON CONFLICT DO NOTHING
+		 RETURNING id, user_id, email_delivered`,
+		inAppEnabled,
+		inAppSetting,
+		emailEnabled,
+		emailSetting,
+		inAppEnabled,
+		inAppSetting,
+		emailEnabled,
+		emailSetting,
+	)
+
+	rows, err := s.db.Query(ctx, query, templateID, string(nType), actorID)
+	if err != nil {
+		return fmt.Errorf("insert notifications: %w", err)
+	}
+	defer rows.Close()
+
+	inserted := collectInserted(rows)
+	if len(inserted.emailIDs) > 0 {
+		s.dispatchEmails(inserted.emailIDs)
+	}
+
+	return nil
+}
+
 func (s *NotificationService) CleanupOld(ctx context.Context) error {
 	_, err := s.db.Exec(ctx, "DELETE FROM notifications WHERE created_at < NOW() - INTERVAL '1 year'")
 	if err != nil {
@@ -307,7 +458,7 @@ func (s *NotificationService) CleanupOld(ctx context.Context) error {
 	return nil
 }
 
-func (s *NotificationService) notifySingle(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID, cardID *uuid.UUID, bingoCount *int, nType models.NotificationType) error {
+func (s *NotificationService) notifySingle(ctx context.Context, recipientID, actorID uuid.UUID, friendshipID, cardID *uuid.UUID, bingoCount *int, commentID *uuid.UUID, nType models.NotificationType) error {
 	inAppCol, emailCol, err := notificationScenarioColumns(nType)
 	if err != nil {
 		return err
@@ -322,8 +473,8 @@ func (s *NotificationService) notifySingle(ctx context.Context, recipientID, act
 	emailSetting := fmt.Sprintf("COALESCE(ns.%s, false)", emailCol)
 
 	query := fmt.Sprintf(
-		`INSERT INTO notifications (user_id, type, actor_user_id, friendship_id, card_id, bingo_count, in_app_delivered, email_delivered)
-		 SELECT u.id, $2, $3, $4, $5, $6,
+		`INSERT INTO notifications (user_id, type, actor_user_id, friendship_id, card_id, bingo_count, comment_id, in_app_delivered, email_delivered)
+		 SELECT u.id, $2, $3, $4, $5, $6, $7,
 		        (%s AND %s) AS in_app_delivered,
 		        (%s AND %s AND u.email_verified) AS email_delivered
 		 FROM users u
@@ -347,7 +498,7 @@ func (s *NotificationService) notifySingle(ctx context.Context, recipientID, act
 		emailSetting,
 	)
 
-	rows, err := s.db.Query(ctx, query, recipientID, string(nType), actorID, friendshipID, cardID, bingoCount)
+	rows, err := s.db.Query(ctx, query, recipientID, string(nType), actorID, friendshipID, cardID, bingoCount, commentID)
 	if err != nil {
 		return fmt.Errorf("insert notification: %w", err)
 	}
@@ -388,8 +539,10 @@ func (s *NotificationService) notifyFriends(ctx context.Context, actorID, cardID
 		     AND (user_id = $1 OR friend_id = $1)
 		 ) AS f
 		 JOIN users u ON u.id = f.recipient_id
+		 JOIN bingo_cards c ON c.id = $3
 		 LEFT JOIN notification_settings ns ON ns.user_id = f.recipient_id
 		 WHERE ((%s AND %s) OR (%s AND %s AND u.email_verified))
+		   AND `+cardVisibleToSQL("c", "f.recipient_id")+`
 		   AND NOT EXISTS (
 		     SELECT 1 FROM user_blocks
 		     WHERE (blocker_id = $1 AND blocked_id = f.recipient_id)
@@ -443,16 +596,20 @@ func (s *NotificationService) dispatchEmails(notificationIDs []uuid.UUID) {
 
 func (s *NotificationService) sendNotificationEmails(ctx context.Context, notificationIDs []uuid.UUID) {
 	rows, err := s.db.Query(ctx,
-		`SELECT n.id, n.type, u.email, u.username, au.username, n.friendship_id, c.title, c.year, n.bingo_count
+		`SELECT n.id, n.type, u.email, u.locale, au.username, n.friendship_id, c.title, c.year, n.bingo_count, ch.name,
+		        t.title, o.name
 		 FROM notifications n
 		 JOIN users u ON n.user_id = u.id
 		 LEFT JOIN users au ON n.actor_user_id = au.id
 		 LEFT JOIN bingo_cards c ON n.card_id = c.id
+		 LEFT JOIN challenges ch ON n.challenge_id = ch.id
+		 LEFT JOIN card_templates t ON n.template_id = t.id
+		 LEFT JOIN organizations o ON t.org_id = o.id
 		 WHERE n.id = ANY($1) AND n.email_delivered = true`,
 		notificationIDs,
 	)
 	if err != nil {
-		logging.Error("Failed to load notification emails", map[string]interface{}{"error": err.Error()})
+		logging.FromContext(ctx).Error("Failed to load notification emails", map[string]interface{}{"error": err.Error()})
 		return
 	}
 	defer rows.Close()
@@ -461,119 +618,113 @@ func (s *NotificationService) sendNotificationEmails(ctx context.Context, notifi
 		var id uuid.UUID
 		var nType string
 		var recipientEmail string
+		var recipientLocale string
 		var actorName *string
 		var friendshipID *uuid.UUID
 		var cardTitle *string
 		var cardYear *int
 		var bingoCount *int
+		var challengeName *string
+		var templateTitle *string
+		var orgName *string
 		if err := rows.Scan(
 			&id,
 			&nType,
 			&recipientEmail,
-			new(string),
+			&recipientLocale,
 			&actorName,
 			&friendshipID,
 			&cardTitle,
 			&cardYear,
 			&bingoCount,
+			&challengeName,
+			&templateTitle,
+			&orgName,
 		); err != nil {
-			logging.Error("Failed to scan notification email", map[string]interface{}{"error": err.Error()})
+			logging.FromContext(ctx).Error("Failed to scan notification email", map[string]interface{}{"error": err.Error()})
 			continue
 		}
 
-		subject, html, text := s.buildNotificationEmail(models.NotificationType(nType), actorName, cardTitle, cardYear, bingoCount)
-		if err := s.emailService.SendNotificationEmail(ctx, recipientEmail, subject, html, text); err != nil {
-			logging.Error("Failed to send notification email", map[string]interface{}{"error": err.Error(), "notification_id": id.String()})
+		subject, html, text, err := s.buildNotificationEmail(i18n.Normalize(recipientLocale), models.NotificationType(nType), actorName, cardTitle, cardYear, bingoCount, challengeName, templateTitle, orgName)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to render notification email", map[string]interface{}{"error": err.Error(), "notification_id": id.String()})
+			continue
+		}
+		if err := s.emailService.SendNotificationEmail(ctx, id, recipientEmail, subject, html, text); err != nil {
+			logging.FromContext(ctx).Error("Failed to send notification email", map[string]interface{}{"error": err.Error(), "notification_id": id.String()})
 			continue
 		}
 		if _, err := s.db.Exec(ctx, "UPDATE notifications SET email_sent_at = NOW() WHERE id = $1", id); err != nil {
-			logging.Error("Failed to mark notification email sent", map[string]interface{}{"error": err.Error(), "notification_id": id.String()})
+			logging.FromContext(ctx).Error("Failed to mark notification email sent", map[string]interface{}{"error": err.Error(), "notification_id": id.String()})
 		}
 	}
 }
 
-func (s *NotificationService) buildNotificationEmail(nType models.NotificationType, actorName *string, cardTitle *string, cardYear *int, bingoCount *int) (string, string, string) {
-	actor := "A friend"
+func (s *NotificationService) buildNotificationEmail(locale string, nType models.NotificationType, actorName *string, cardTitle *string, cardYear *int, bingoCount *int, challengeName *string, templateTitle *string, orgName *string) (string, string, string, error) {
+	actor := i18n.T(locale, "notification.actor_fallback")
 	if actorName != nil && *actorName != "" {
 		actor = *actorName
 	}
-	cardName := cardDisplayName(cardTitle, cardYear)
+	cardName := cardDisplayName(locale, cardTitle, cardYear)
 
 	var subject string
 	var message string
 	switch nType {
-	case models.NotificationTypeFriendRequestReceived:
-		subject = "New friend request"
-		message = fmt.Sprintf("%s sent you a friend request.", actor)
-	case models.NotificationTypeFriendRequestAccepted:
-		subject = "Friend request accepted"
-		message = fmt.Sprintf("%s accepted your friend request.", actor)
+	case models.NotificationTypeFriendRequestReceived,
+		models.NotificationTypeFriendRequestAccepted:
+		subject = i18n.T(locale, "notification."+string(nType)+".subject")
+		message = i18n.T(locale, "notification."+string(nType)+".message", actor)
 	case models.NotificationTypeFriendBingo:
-		subject = "Your friend got a bingo!"
+		subject = i18n.T(locale, "notification.friend_bingo.subject")
 		if bingoCount != nil && *bingoCount > 0 {
-			message = fmt.Sprintf("%s got a bingo on %s (%d total).", actor, cardName, *bingoCount)
+			message = i18n.T(locale, "notification.friend_bingo.message_count", actor, cardName, *bingoCount)
 		} else {
-			message = fmt.Sprintf("%s got a bingo on %s.", actor, cardName)
+			message = i18n.T(locale, "notification.friend_bingo.message", actor, cardName)
 		}
 	case models.NotificationTypeFriendNewCard:
-		subject = "Your friend created a new bingo card"
-		message = fmt.Sprintf("%s created a new card: %s.", actor, cardName)
+		subject = i18n.T(locale, "notification.friend_new_card.subject")
+		message = i18n.T(locale, "notification.friend_new_card.message", actor, cardName)
+	case models.NotificationTypeItemComment:
+		subject = i18n.T(locale, "notification.item_comment.subject")
+		message = i18n.T(locale, "notification.item_comment.message", actor, cardName)
+	case models.NotificationTypeChallengeResult:
+		name := i18n.T(locale, "notification.challenge_fallback")
+		if challengeName != nil && *challengeName != "" {
+			name = *challengeName
+		}
+		subject = i18n.T(locale, "notification.challenge_result.subject")
+		if actorName != nil && *actorName != "" {
+			message = i18n.T(locale, "notification.challenge_result.message", name, actor)
+		} else {
+			message = i18n.T(locale, "notification.challenge_result.message_no_winner", name)
+		}
+	case models.NotificationTypeOrgTemplate:
+		title := i18n.T(locale, "notification.template_fallback")
+		if templateTitle != nil && *templateTitle != "" {
+			title = *templateTitle
+		}
+		org := i18n.T(locale, "notification.org_fallback")
+		if orgName != nil && *orgName != "" {
+			org = *orgName
+		}
+		subject = i18n.T(locale, "notification.org_template.subject", org)
+		message = i18n.T(locale, "notification.org_template.message", actor, title, org)
 	default:
-		subject = "New notification"
-		message = "You have a new notification."
-	}
-
-	viewURL := fmt.Sprintf("%s/#notifications", s.baseURL)
-	friendsURL := fmt.Sprintf("%s/#friends", s.baseURL)
-	settingsURL := fmt.Sprintf("%s/#profile", s.baseURL)
-	friendsLabel := "Friends page"
-	settingsLabel := "Manage notification settings"
-
-	html := fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-  <meta charset="utf-8">
-  <meta name="viewport" content="width=device-width, initial-scale=1.0">
-</head>
-<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Year of Bingo</h1>
-
-  <p style="font-size: 16px;">%s</p>
-
-  <p>
-    <a href="%s" style="display: inline-block; background: #4F46E5; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">
-      View Notifications
-    </a>
-  </p>
-
-  <p style="color: #666; font-size: 14px;">
-    Friends page: <a href="%s">%s</a>
-  </p>
-
-  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #666; font-size: 14px;">Manage notification settings: <a href="%s">%s</a></p>
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
-</body>
-</html>`,
-		templateEscape(message),
-		viewURL,
-		friendsURL,
-		friendsLabel,
-		settingsURL,
-		settingsLabel,
-	)
-
-	text := fmt.Sprintf(`%s
-
-View notifications: %s
-Friends page: %s
-Manage notification settings: %s
-
---
-Year of Bingo
-yearofbingo.com`, message, viewURL, friendsURL, settingsURL)
+		subject = i18n.T(locale, "notification.default.subject")
+		message = i18n.T(locale, "notification.default.message")
+	}
 
-	return subject, html, text
+	html, text, err := renderEmailTemplate(emailTemplateNotification, locale, notificationEmailData{
+		Locale:      locale,
+		Message:     message,
+		ViewURL:     fmt.Sprintf("%s/#notifications", s.baseURL),
+		FriendsURL:  fmt.Sprintf("%s/#friends", s.baseURL),
+		SettingsURL: fmt.Sprintf("%s/#profile", s.baseURL),
+	})
+	if err != nil {
+		return "", "", "", err
+	}
+	return subject, html, text, nil
 }
 
 func (s *NotificationService) ensureSettingsRow(ctx context.Context, userID uuid.UUID) error {
@@ -592,7 +743,9 @@ func (s *NotificationService) loadSettings(ctx context.Context, userID uuid.UUID
 	err := s.db.QueryRow(ctx,
 		`SELECT user_id, in_app_enabled, in_app_friend_request_received, in_app_friend_request_accepted,
 		        in_app_friend_bingo, in_app_friend_new_card, email_enabled, email_friend_request_received,
-		        email_friend_request_accepted, email_friend_bingo, email_friend_new_card, created_at, updated_at
+		        email_friend_request_accepted, email_friend_bingo, email_friend_new_card,
+		        in_app_item_comment, email_item_comment, in_app_challenge_result, email_challenge_result,
+		        in_app_org_template, email_org_template, created_at, updated_at
 		 FROM notification_settings WHERE user_id = $1`,
 		userID,
 	).Scan(
@@ -607,6 +760,12 @@ func (s *NotificationService) loadSettings(ctx context.Context, userID uuid.UUID
 		&settings.EmailFriendRequestAccepted,
 		&settings.EmailFriendBingo,
 		&settings.EmailFriendNewCard,
+		&settings.InAppItemComment,
+		&settings.EmailItemComment,
+		&settings.InAppChallengeResult,
+		&settings.EmailChallengeResult,
+		&settings.InAppOrgTemplate,
+		&settings.EmailOrgTemplate,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
 	)
@@ -655,19 +814,25 @@ func notificationScenarioColumns(nType models.NotificationType) (string, string,
 		return "in_app_friend_bingo", "email_friend_bingo", nil
 	case models.NotificationTypeFriendNewCard:
 		return "in_app_friend_new_card", "email_friend_new_card", nil
+	case models.NotificationTypeItemComment:
+		return "in_app_item_comment", "email_item_comment", nil
+	case models.NotificationTypeChallengeResult:
+		return "in_app_challenge_result", "email_challenge_result", nil
+	case models.NotificationTypeOrgTemplate:
+		return "in_app_org_template", "email_org_template", nil
 	default:
 		return "", "", fmt.Errorf("unsupported notification type: %s", nType)
 	}
 }
 
-func cardDisplayName(title *string, year *int) string {
+func cardDisplayName(locale string, title *string, year *int) string {
 	if title != nil && *title != "" {
 		return *title
 	}
 	if year != nil {
-		return fmt.Sprintf("%d Bingo Card", *year)
+		return i18n.T(locale, "notification.card_year", *year)
 	}
-	return "a bingo card"
+	return i18n.T(locale, "notification.card_fallback")
 }
 
 func enablesEmail(patch models.NotificationSettingsPatch) bool {
@@ -675,18 +840,10 @@ func enablesEmail(patch models.NotificationSettingsPatch) bool {
 		(patch.EmailFriendRequestReceived != nil && *patch.EmailFriendRequestReceived) ||
 		(patch.EmailFriendRequestAccepted != nil && *patch.EmailFriendRequestAccepted) ||
 		(patch.EmailFriendBingo != nil && *patch.EmailFriendBingo) ||
-		(patch.EmailFriendNewCard != nil && *patch.EmailFriendNewCard)
-}
-
-func templateEscape(value string) string {
-	replacer := strings.NewReplacer(
-		"&", "&amp;",
-		"<", "&lt;",
-		">", "&gt;",
-		"\"", "&quot;",
-		"'", "&#39;",
-	)
-	return replacer.Replace(value)
+		(patch.EmailFriendNewCard != nil && *patch.EmailFriendNewCard) ||
+		(patch.EmailItemComment != nil && *patch.EmailItemComment) ||
+		(patch.EmailChallengeResult != nil && *patch.EmailChallengeResult) ||
+		(patch.EmailOrgTemplate != nil && *patch.EmailOrgTemplate)
 }
 
 func isNotificationSettingsColumnAllowed(column string) bool {
@@ -13,6 +13,9 @@ type stubNotificationService struct {
 	NotifyFriendRequestAcceptedFunc func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
 	NotifyFriendsNewCardFunc        func(ctx context.Context, actorID, cardID uuid.UUID) error
 	NotifyFriendsBingoFunc          func(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error
+	NotifyItemCommentFunc           func(ctx context.Context, recipientID, actorID, cardID, commentID uuid.UUID) error
+	NotifyChallengeResultFunc       func(ctx context.Context, challengeID uuid.UUID, winnerID *uuid.UUID) error
+	NotifyOrgTemplateFunc           func(ctx context.Context, actorID, templateID uuid.UUID) error
 }
 
 func (s *stubNotificationService) GetSettings(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error) {
@@ -74,3 +77,24 @@ func (s *stubNotificationService) NotifyFriendsBingo(ctx context.Context, actorI
 	}
 	return nil
 }
+
+func (s *stubNotificationService) NotifyItemComment(ctx context.Context, recipientID, actorID, cardID, commentID uuid.UUID) error {
+	if s.NotifyItemCommentFunc != nil {
+		return s.NotifyItemCommentFunc(ctx, recipientID, actorID, cardID, commentID)
+	}
+	return nil
+}
+
+func (s *stubNotificationService) NotifyChallengeResult(ctx context.Context, challengeID uuid.UUID, winnerID *uuid.UUID) error {
+	if s.NotifyChallengeResultFunc != nil {
+		return s.NotifyChallengeResultFunc(ctx, challengeID, winnerID)
+	}
+	return nil
+}
+
+func (s *stubNotificationService) NotifyOrgTemplate(ctx context.Context, actorID, templateID uuid.UUID) error {
+	if s.NotifyOrgTemplateFunc != nil {
+		return s.NotifyOrgTemplateFunc(ctx, actorID, templateID)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
@@ -38,6 +39,12 @@ func TestNotificationService_GetSettings_CreatesRow(t *testing.T) {
 				false,
 				false,
 				false,
+				true,
+				false,
+				true,
+				false,
+				true,
+				false,
 				time.Now(),
 				time.Now(),
 			)
@@ -82,6 +89,12 @@ func TestNotificationService_UpdateSettings_EmailRequiresVerification(t *testing
 				false,
 				false,
 				false,
+				true,
+				false,
+				true,
+				false,
+				true,
+				false,
 				time.Now(),
 				time.Now(),
 			)
@@ -260,6 +273,110 @@ func TestNotificationService_NotifyFriendRequestReceived_UsesScenarioToggles(t *
 	}
 }
 
+func TestNotificationService_NotifyItemComment_LinksFriendship(t *testing.T) {
+	recipientID := uuid.New()
+	actorID := uuid.New()
+	cardID := uuid.New()
+	commentID := uuid.New()
+	friendshipID := uuid.New()
+
+	tests := []struct {
+		name         string
+		friendship   Row
+		wantFriendID *uuid.UUID
+	}{
+		{name: "friend of owner", friendship: rowFromValues(&friendshipID), wantFriendID: &friendshipID},
+		{name: "card owner", friendship: fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSQL string
+			var gotArgs []any
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					return tt.friendship
+				},
+				QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+					gotSQL = sql
+					gotArgs = args
+					return &fakeRows{rows: [][]any{}}, nil
+				},
+			}
+
+			svc := NewNotificationService(db, nil, "http://example.com")
+			if err := svc.NotifyItemComment(context.Background(), recipientID, actorID, cardID, commentID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(gotSQL, "in_app_item_comment") || !strings.Contains(gotSQL, "email_item_comment") {
+				t.Fatalf("expected item comment gating, got %q", gotSQL)
+			}
+			gotFriendID := gotArgs[3].(*uuid.UUID)
+			if (gotFriendID == nil) != (tt.wantFriendID == nil) || (gotFriendID != nil && *gotFriendID != *tt.wantFriendID) {
+				t.Fatalf("expected friendship %v, got %v", tt.wantFriendID, gotFriendID)
+			}
+			if got := gotArgs[6].(*uuid.UUID); got == nil || *got != commentID {
+				t.Fatalf("expected comment id %s, got %v", commentID, got)
+			}
+		})
+	}
+}
+
+func TestNotificationService_NotifyChallengeResult_JoinedParticipants(t *testing.T) {
+	challengeID := uuid.New()
+	winnerID := uuid.New()
+	var gotSQL string
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			gotSQL = sql
+			gotArgs = args
+			return &fakeRows{rows: [][]any{}}, nil
+		},
+	}
+
+	svc := NewNotificationService(db, nil, "http://example.com")
+	if err := svc.NotifyChallengeResult(context.Background(), challengeID, &winnerID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(gotSQL, "p.joined_at IS NOT NULL") {
+		t.Fatalf("expected only joined participants, got %q", gotSQL)
+	}
+	if !strings.Contains(gotSQL, "in_app_challenge_result") {
+		t.Fatalf("expected challenge result settings, got %q", gotSQL)
+	}
+	if gotArgs[0] != challengeID || gotArgs[1] != string(models.NotificationTypeChallengeResult) {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestNotificationService_NotifyOrgTemplate_MembersExceptActor(t *testing.T) {
+	actorID := uuid.New()
+	templateID := uuid.New()
+	var gotSQL string
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			gotSQL = sql
+			gotArgs = args
+			return &fakeRows{rows: [][]any{}}, nil
+		},
+	}
+
+	svc := NewNotificationService(db, nil, "http://example.com")
+	if err := svc.NotifyOrgTemplate(context.Background(), actorID, templateID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"organization_members", "m.user_id <> $3", "in_app_org_template", "user_blocks"} {
+		if !strings.Contains(gotSQL, want) {
+			t.Fatalf("expected query to contain %q, got %q", want, gotSQL)
+		}
+	}
+	if gotArgs[0] != templateID || gotArgs[1] != string(models.NotificationTypeOrgTemplate) || gotArgs[2] != actorID {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+}
+
 func boolPtr(v bool) *bool {
 	return &v
 }
@@ -0,0 +1,806 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrOrganizationNotFound      = errors.New("organization not found")
+	ErrInvalidOrganizationName   = errors.New("invalid organization name")
+	ErrNotOrgAdmin               = errors.New("organization admin required")
+	ErrAlreadyOrgMember          = errors.New("already a member of this organization")
+	ErrOrgMemberNotFound         = errors.New("organization member not found")
+	ErrInvalidOrgRole            = errors.New("invalid organization role")
+	ErrLastOrgAdmin              = errors.New("an organization needs at least one admin")
+	ErrOrgInviteNotFound         = errors.New("organization invite not found")
+	ErrOrgInviteLimitReached     = errors.New("organization invite limit reached")
+	ErrInvalidOrgDomain          = errors.New("invalid domain")
+	ErrOrgDomainNotAllowed       = errors.New("public email domains can't be claimed")
+	ErrOrgDomainTaken            = errors.New("domain already belongs to an organization")
+	ErrOrgDomainNotVerified      = errors.New("a verified email at the domain is required")
+	ErrOrgDomainNotFound         = errors.New("organization domain not found")
+	ErrOrgInviteExpiryOutOfRange = errors.New("organization invite expiry out of range")
+)
+
+const (
+	OrgInviteExpiryMinDays     = 1
+	OrgInviteExpiryMaxDays     = 90
+	OrgInviteExpiryDefaultDays = 14
+	OrgInviteMaxActive         = 20
+)
+
+var orgDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// publicEmailDomains can't be claimed by an organization: everyone can get
+// an address there, so a verified email proves nothing about membership.
+var publicEmailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "outlook.com": true, "hotmail.com": true,
+	"live.com": true, "msn.com": true, "yahoo.com": true, "icloud.com": true, "me.com": true,
+	"mac.com": true, "aol.com": true, "proton.me": true, "protonmail.com": true, "gmx.com": true,
+	"gmx.net": true, "mail.com": true, "yandex.com": true, "zoho.com": true, "fastmail.com": true,
+}
+
+// emailDomainSQL extracts the lower-cased domain of the users row aliased u.
+const emailDomainSQL = `LOWER(substring(u.email from '@([^@]+)$'))`
+
+type OrganizationService struct {
+	db    DB
+	cards CardStatsSource
+}
+
+func NewOrganizationService(db DB, cards CardStatsSource) *OrganizationService {
+	return &OrganizationService{db: db, cards: cards}
+}
+
+// orgCardVisibleSQL is the condition for a card aliased card being shown to
+// the other members of its owner's organizations.
+func orgCardVisibleSQL(card string) string {
+	return fmt.Sprintf(`%[1]s.is_finalized AND %[1]s.visibility IN ('organization', 'public')`, card)
+}
+
+func normalizeOrganizationName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > models.MaxOrganizationNameLength {
+		return "", ErrInvalidOrganizationName
+	}
+	return name, nil
+}
+
+func normalizeOrgDomain(domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "@")
+	if len(domain) > 253 || !orgDomainPattern.MatchString(domain) {
+		return "", ErrInvalidOrgDomain
+	}
+	if publicEmailDomains[domain] {
+		return "", ErrOrgDomainNotAllowed
+	}
+	return domain, nil
+}
+
+// orgRole returns the user's role in the organization. Non-members get
+// ErrOrganizationNotFound so they can't learn which organizations exist.
+func orgRole(ctx context.Context, q DBConn, orgID, userID uuid.UUID) (models.OrgRole, error) {
+	var role string
+	err := q.QueryRow(ctx,
+		"SELECT role FROM organization_members WHERE org_id = $1 AND user_id = $2",
+		orgID, userID,
+	).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrOrganizationNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("getting organization role: %w", err)
+	}
+	return models.OrgRole(role), nil
+}
+
+func requireOrgAdmin(ctx context.Context, q DBConn, orgID, userID uuid.UUID) error {
+	role, err := orgRole(ctx, q, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if role != models.OrgRoleAdmin {
+		return ErrNotOrgAdmin
+	}
+	return nil
+}
+
+// Create makes a new organization with the user as its only admin.
+func (s *OrganizationService) Create(ctx context.Context, userID uuid.UUID, name string) (*models.Organization, error) {
+	name, err := normalizeOrganizationName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	org := &models.Organization{Name: name, Role: models.OrgRoleAdmin, MemberCount: 1, Domains: []string{}}
+	err = s.db.QueryRow(ctx,
+		`WITH org AS (
+		   INSERT INTO organizations (name, created_by) VALUES ($1, $2)
+		   RETURNING id, created_at
+		 ), member AS (
+		   INSERT INTO organization_members (org_id, user_id, role)
+		   SELECT id, $2, 'admin' FROM org
+		 )
+		 SELECT id, created_at FROM org`,
+		name, userID,
+	).Scan(&org.ID, &org.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("creating organization: %w", err)
+	}
+	return org, nil
+}
+
+const organizationColumns = `o.id, o.name, m.role, o.created_at,
+		        (SELECT COUNT(*) FROM organization_members om WHERE om.org_id = o.id),
+		        COALESCE((SELECT array_agg(d.domain ORDER BY d.domain) FROM organization_domains d WHERE d.org_id = o.id), '{}')`
+
+func scanOrganization(row Row) (*models.Organization, error) {
+	var org models.Organization
+	var role string
+	if err := row.Scan(&org.ID, &org.Name, &role, &org.CreatedAt, &org.MemberCount, &org.Domains); err != nil {
+		return nil, err
+	}
+	org.Role = models.OrgRole(role)
+	if org.Domains == nil {
+		org.Domains = []string{}
+	}
+	return &org, nil
+}
+
+// List returns the organizations the user belongs to.
+func (s *OrganizationService) List(ctx context.Context, userID uuid.UUID) ([]models.Organization, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT `+organizationColumns+`
+		 FROM organization_members m
+		 JOIN organizations o ON o.id = m.org_id
+		 WHERE m.user_id = $1
+		 ORDER BY LOWER(o.name), o.id`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing organizations: %w", err)
+	}
+	defer rows.Close()
+
+	orgs := []models.Organization{}
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning organization: %w", err)
+		}
+		orgs = append(orgs, *org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+// Get returns an organization the user belongs to.
+func (s *OrganizationService) Get(ctx context.Context, userID, orgID uuid.UUID) (*models.Organization, error) {
+	org, err := scanOrganization(s.db.QueryRow(ctx,
+		`SELECT `+organizationColumns+`
+		 FROM organization_members m
+		 JOIN organizations o ON o.id = m.org_id
+		 WHERE m.org_id = $1 AND m.user_id = $2`,
+		orgID, userID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting organization: %w", err)
+	}
+	return org, nil
+}
+
+func (s *OrganizationService) Rename(ctx context.Context, userID, orgID uuid.UUID, name string) error {
+	name, err := normalizeOrganizationName(name)
+	if err != nil {
+		return err
+	}
+	if err := requireOrgAdmin(ctx, s.db, orgID, userID); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(ctx,
+		"UPDATE organizations SET name = $2, updated_at = NOW() WHERE id = $1",
+		orgID, name,
+	); err != nil {
+		return fmt.Errorf("renaming organization: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the organization with its invites, domains and templates.
+// Members' cards are untouched; ones shared with the organization stay
+// visible to friends.
+func (s *OrganizationService) Delete(ctx context.Context, userID, orgID uuid.UUID) error {
+	if err := requireOrgAdmin(ctx, s.db, orgID, userID); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(ctx, "DELETE FROM organizations WHERE id = $1", orgID); err != nil {
+		return fmt.Errorf("deleting organization: %w", err)
+	}
+	return nil
+}
+
+func (s *OrganizationService) ListMembers(ctx context.Context, userID, orgID uuid.UUID) ([]models.OrgMember, error) {
+	if _, err := orgRole(ctx, s.db, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT m.user_id, u.username, m.role, m.joined_at
+		 FROM organization_members m
+		 JOIN users u ON u.id = m.user_id
+		 WHERE m.org_id = $1
+		 ORDER BY m.role = 'admin' DESC, LOWER(u.username)`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing organization members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.OrgMember{}
+	for rows.Next() {
+		var member models.OrgMember
+		var role string
+		if err := rows.Scan(&member.UserID, &member.Username, &role, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("scanning organization member: %w", err)
+		}
+		member.Role = models.OrgRole(role)
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing organization members: %w", err)
+	}
+	return members, nil
+}
+
+// SetMemberRole promotes or demotes a member. The last admin can't be
+// demoted.
+func (s *OrganizationService) SetMemberRole(ctx context.Context, userID, orgID, memberID uuid.UUID, role models.OrgRole) error {
+	if !role.IsValid() {
+		return ErrInvalidOrgRole
+	}
+	return s.changeMember(ctx, userID, orgID, memberID, func(tx Tx, current models.OrgRole) error {
+		if current == role {
+			return nil
+		}
+		if _, err := tx.Exec(ctx,
+			"UPDATE organization_members SET role = $3 WHERE org_id = $1 AND user_id = $2",
+			orgID, memberID, string(role),
+		); err != nil {
+			return fmt.Errorf("updating organization role: %w", err)
+		}
+		return nil
+	}, role != models.OrgRoleAdmin)
+}
+
+// RemoveMember takes a member out of the organization. Admins can remove
+// anyone; other members can only remove themselves. The last admin can't
+// leave; they can delete the organization instead.
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID, orgID, memberID uuid.UUID) error {
+	return s.changeMember(ctx, userID, orgID, memberID, func(tx Tx, _ models.OrgRole) error {
+		if _, err := tx.Exec(ctx,
+			"DELETE FROM organization_members WHERE org_id = $1 AND user_id = $2",
+			orgID, memberID,
+		); err != nil {
+			return fmt.Errorf("removing organization member: %w", err)
+		}
+		return nil
+	}, true)
+}
+
+// changeMember applies change to a member in a transaction that holds the
+// organization row, so concurrent changes can't leave it without an admin.
+// losesAdmin says whether the change takes away the member's admin role.
+func (s *OrganizationService) changeMember(ctx context.Context, userID, orgID, memberID uuid.UUID, change func(tx Tx, current models.OrgRole) error, losesAdmin bool) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin organization member change: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, "SELECT 1 FROM organizations WHERE id = $1 FOR UPDATE", orgID); err != nil {
+		return fmt.Errorf("locking organization: %w", err)
+	}
+
+	actorRole, err := orgRole(ctx, tx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if actorRole != models.OrgRoleAdmin && userID != memberID {
+		return ErrNotOrgAdmin
+	}
+
+	current, err := orgRole(ctx, tx, orgID, memberID)
+	if errors.Is(err, ErrOrganizationNotFound) {
+		return ErrOrgMemberNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// Members may leave, but only admins change roles.
+	if actorRole != models.OrgRoleAdmin && !losesAdmin {
+		return ErrNotOrgAdmin
+	}
+
+	if current == models.OrgRoleAdmin && losesAdmin {
+		var admins int
+		if err := tx.QueryRow(ctx,
+			"SELECT COUNT(*) FROM organization_members WHERE org_id = $1 AND role = 'admin'",
+			orgID,
+		).Scan(&admins); err != nil {
+			return fmt.Errorf("counting organization admins: %w", err)
+		}
+		if admins <= 1 {
+			return ErrLastOrgAdmin
+		}
+	}
+
+	if err := change(tx, current); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit organization member change: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// CreateInvite makes a reusable invite link for the organization and
+// returns it with its token, which isn't stored.
+func (s *OrganizationService) CreateInvite(ctx context.Context, userID, orgID uuid.UUID, expiresInDays int) (*models.OrgInvite, string, error) {
+	if expiresInDays < OrgInviteExpiryMinDays || expiresInDays > OrgInviteExpiryMaxDays {
+		return nil, "", ErrOrgInviteExpiryOutOfRange
+	}
+	if err := requireOrgAdmin(ctx, s.db, orgID, userID); err != nil {
+		return nil, "", err
+	}
+
+	var active int
+	if err := s.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM organization_invites
+		 WHERE org_id = $1 AND revoked_at IS NULL AND expires_at > NOW()`,
+		orgID,
+	).Scan(&active); err != nil {
+		return nil, "", fmt.Errorf("counting organization invites: %w", err)
+	}
+	if active >= OrgInviteMaxActive {
+		return nil, "", ErrOrgInviteLimitReached
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	invite := &models.OrgInvite{}
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO organization_invites (org_id, token_hash, created_by, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, org_id, expires_at, revoked_at, use_count, created_at`,
+		orgID, hashInviteToken(token), userID, time.Now().Add(time.Duration(expiresInDays)*24*time.Hour),
+	).Scan(&invite.ID, &invite.OrgID, &invite.ExpiresAt, &invite.RevokedAt, &invite.UseCount, &invite.CreatedAt)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating organization invite: %w", err)
+	}
+	return invite, token, nil
+}
+
+// ListInvites returns the organization's unexpired, unrevoked invites.
+func (s *OrganizationService) ListInvites(ctx context.Context, userID, orgID uuid.UUID) ([]models.OrgInvite, error) {
+	if err := requireOrgAdmin(ctx, s.db, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT id, org_id, expires_at, revoked_at, use_count, created_at
+		 FROM organization_invites
+		 WHERE org_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		 ORDER BY created_at DESC`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing organization invites: %w", err)
+	}
+	defer rows.Close()
+
+	invites := []models.OrgInvite{}
+	for rows.Next() {
+		var invite models.OrgInvite
+		if err := rows.Scan(&invite.ID, &invite.OrgID, &invite.ExpiresAt, &invite.RevokedAt, &invite.UseCount, &invite.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning organization invite: %w", err)
+		}
+		invites = append(invites, invite)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing organization invites: %w", err)
+	}
+	return invites, nil
+}
+
+func (s *OrganizationService) RevokeInvite(ctx context.Context, userID, orgID, inviteID uuid.UUID) error {
+	if err := requireOrgAdmin(ctx, s.db, orgID, userID); err != nil {
+		return err
+	}
+	result, err := s.db.Exec(ctx,
+		`UPDATE organization_invites SET revoked_at = NOW()
+		 WHERE id = $1 AND org_id = $2 AND revoked_at IS NULL`,
+		inviteID, orgID,
+	)
+	if err != nil {
+		return fmt.Errorf("revoking organization invite: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrOrgInviteNotFound
+	}
+	return nil
+}
+
+// PurgeInactive deletes invites that were revoked or expired more than a
+// week ago. It returns how many were removed.
+func (s *OrganizationService) PurgeInactive(ctx context.Context) (int, error) {
+	result, err := s.db.Exec(ctx,
+		`DELETE FROM organization_invites
+		 WHERE COALESCE(revoked_at, expires_at) < NOW() - INTERVAL '7 days'`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("purge organization invites: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// AcceptInvite adds the user to the organization an invite token belongs
+// to.
+func (s *OrganizationService) AcceptInvite(ctx context.Context, userID uuid.UUID, token string) (*models.Organization, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin organization invite accept: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var inviteID, orgID uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT id, org_id FROM organization_invites
+		 WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		 FOR UPDATE`,
+		hashInviteToken(token),
+	).Scan(&inviteID, &orgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrgInviteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading organization invite: %w", err)
+	}
+
+	if err := addOrgMember(ctx, tx, orgID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE organization_invites SET use_count = use_count + 1 WHERE id = $1",
+		inviteID,
+	); err != nil {
+		return nil, fmt.Errorf("counting organization invite use: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit organization invite accept: %w", err)
+	}
+	committed = true
+	return s.Get(ctx, userID, orgID)
+}
+
+func addOrgMember(ctx context.Context, q DBConn, orgID, userID uuid.UUID) error {
+	_, err := q.Exec(ctx,
+		"INSERT INTO organization_members (org_id, user_id, role) VALUES ($1, $2, 'member')",
+		orgID, userID,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrAlreadyOrgMember
+	}
+	if err != nil {
+		return fmt.Errorf("adding organization member: %w", err)
+	}
+	return nil
+}
+
+// AddDomain lets people with a verified email at domain join the
+// organization without an invite. The admin adding it must have a verified
+// email there themselves, which shows the domain is really theirs.
+func (s *OrganizationService) AddDomain(ctx context.Context, userID, orgID uuid.UUID, domain string) error {
+	domain, err := normalizeOrgDomain(domain)
+	if err != nil {
+		return err
+	}
+	if err := requireOrgAdmin(ctx, s.db, orgID, userID); err != nil {
+		return err
+	}
+
+	var verified bool
+	if err := s.db.QueryRow(ctx,
+		`SELECT u.email_verified AND `+emailDomainSQL+` = $2 FROM users u WHERE u.id = $1`,
+		userID, domain,
+	).Scan(&verified); err != nil {
+		return fmt.Errorf("checking admin email domain: %w", err)
+	}
+	if !verified {
+		return ErrOrgDomainNotVerified
+	}
+
+	_, err = s.db.Exec(ctx,
+		"INSERT INTO organization_domains (domain, org_id, added_by) VALUES ($1, $2, $3)",
+		domain, orgID, userID,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrOrgDomainTaken
+	}
+	if err != nil {
+		return fmt.Errorf("adding organization domain: %w", err)
+	}
+	return nil
+}
+
+func (s *OrganizationService) RemoveDomain(ctx context.Context, userID, orgID uuid.UUID, domain string) error {
+	if err := requireOrgAdmin(ctx, s.db, orgID, userID); err != nil {
+		return err
+	}
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM organization_domains WHERE domain = $1 AND org_id = $2",
+		strings.ToLower(strings.TrimSpace(domain)), orgID,
+	)
+	if err != nil {
+		return fmt.Errorf("removing organization domain: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrOrgDomainNotFound
+	}
+	return nil
+}
+
+// Joinable returns the organizations the user can join by email domain.
+// Users without a verified email get none.
+func (s *OrganizationService) Joinable(ctx context.Context, userID uuid.UUID) ([]models.JoinableOrganization, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT o.id, o.name, d.domain,
+		        (SELECT COUNT(*) FROM organization_members om WHERE om.org_id = o.id)
+		 FROM users u
+		 JOIN organization_domains d ON d.domain = `+emailDomainSQL+`
+		 JOIN organizations o ON o.id = d.org_id
+		 WHERE u.id = $1 AND u.email_verified
+		   AND NOT EXISTS (SELECT 1 FROM organization_members m WHERE m.org_id = o.id AND m.user_id = u.id)`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing joinable organizations: %w", err)
+	}
+	defer rows.Close()
+
+	orgs := []models.JoinableOrganization{}
+	for rows.Next() {
+		var org models.JoinableOrganization
+		if err := rows.Scan(&org.ID, &org.Name, &org.Domain, &org.MemberCount); err != nil {
+			return nil, fmt.Errorf("scanning joinable organization: %w", err)
+		}
+		orgs = append(orgs, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing joinable organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+// JoinByDomain adds the user to an organization that has claimed the
+// domain of their verified email.
+func (s *OrganizationService) JoinByDomain(ctx context.Context, userID, orgID uuid.UUID) (*models.Organization, error) {
+	var eligible bool
+	if err := s.db.QueryRow(ctx,
+		`SELECT EXISTS (
+		   SELECT 1 FROM users u
+		   JOIN organization_domains d ON d.domain = `+emailDomainSQL+`
+		   WHERE u.id = $1 AND u.email_verified AND d.org_id = $2
+		 )`,
+		userID, orgID,
+	).Scan(&eligible); err != nil {
+		return nil, fmt.Errorf("checking organization domain: %w", err)
+	}
+	if !eligible {
+		return nil, ErrOrganizationNotFound
+	}
+
+	if err := addOrgMember(ctx, s.db, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, orgID)
+}
+
+// MemberCards returns the finalized cards another member shares with the
+// organization. Organization roles grant nothing extra: admins see exactly
+// what other members see. Notes and proof links are left out, as on public
+// profiles. Members who have blocked, or been blocked by, the viewer are
+// reported as not found.
+func (s *OrganizationService) MemberCards(ctx context.Context, viewerID, orgID, memberID uuid.UUID) (*models.OrgMemberCards, error) {
+	if _, err := orgRole(ctx, s.db, orgID, viewerID); err != nil {
+		return nil, err
+	}
+
+	result := &models.OrgMemberCards{Cards: []*models.BingoCard{}}
+	err := s.db.QueryRow(ctx,
+		`SELECT u.username
+		 FROM organization_members m
+		 JOIN users u ON u.id = m.user_id
+		 WHERE m.org_id = $1 AND m.user_id = $2 AND u.disabled_at IS NULL
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks b
+		     WHERE (b.blocker_id = $2 AND b.blocked_id = $3)
+		        OR (b.blocker_id = $3 AND b.blocked_id = $2)
+		   )`,
+		orgID, memberID, viewerID,
+	).Scan(&result.Username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrgMemberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting organization member: %w", err)
+	}
+
+	cards, err := s.cards.ListByUser(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		if !card.IsFinalized || !card.Visibility.VisibleToOrganizations() {
+			continue
+		}
+		for i := range card.Items {
+			card.Items[i].Redact()
+			card.Items[i].Notes = nil
+			card.Items[i].ProofURL = nil
+		}
+		result.Cards = append(result.Cards, card)
+	}
+	return result, nil
+}
+
+// Templates returns the organization's templates, newest first. Templates a
+// moderator hid are shown only to their owner.
+func (s *OrganizationService) Templates(ctx context.Context, userID, orgID uuid.UUID) ([]models.CardTemplate, error) {
+	if _, err := orgRole(ctx, s.db, orgID, userID); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT `+templateColumns+`
+		 FROM card_templates t
+		 JOIN users u ON u.id = t.owner_id
+		 WHERE t.org_id = $1 AND (t.hidden_at IS NULL OR t.owner_id = $2)
+		 ORDER BY t.created_at DESC`,
+		orgID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing organization templates: %w", err)
+	}
+	return collectTemplates(rows)
+}
+
+// Stats totals the year's cards that members share with the organization.
+// Only admins can see them.
+func (s *OrganizationService) Stats(ctx context.Context, userID, orgID uuid.UUID, year int) (*models.OrgStats, error) {
+	if err := requireOrgAdmin(ctx, s.db, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	stats := &models.OrgStats{Year: year, Templates: []models.OrgTemplateUsage{}}
+	if err := s.db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM organization_members WHERE org_id = $1",
+		orgID,
+	).Scan(&stats.MemberCount); err != nil {
+		return nil, fmt.Errorf("counting organization members: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT c.id, c.user_id, c.grid_size, c.has_free_space, c.free_space_position,
+		        bi.position, bi.is_completed, bi.completed_at
+		 FROM organization_members m
+		 JOIN bingo_cards c ON c.user_id = m.user_id
+		 LEFT JOIN bingo_items bi ON bi.card_id = c.id
+		 WHERE m.org_id = $1 AND c.year = $2 AND `+orgCardVisibleSQL("c")+`
+		 ORDER BY c.id, bi.position`,
+		orgID, year,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("loading organization cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*models.BingoCard
+	for rows.Next() {
+		var card models.BingoCard
+		var position *int
+		var completed *bool
+		var item models.BingoItem
+		if err := rows.Scan(&card.ID, &card.UserID, &card.GridSize, &card.HasFreeSpace, &card.FreeSpacePos,
+			&position, &completed, &item.CompletedAt); err != nil {
+			return nil, fmt.Errorf("scanning organization card: %w", err)
+		}
+		if len(cards) == 0 || cards[len(cards)-1].ID != card.ID {
+			card.Year = year
+			card.Items = []models.BingoItem{}
+			cards = append(cards, &card)
+		}
+		if position != nil {
+			item.Position = *position
+			item.IsCompleted = completed != nil && *completed
+			last := cards[len(cards)-1]
+			last.Items = append(last.Items, item)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading organization cards: %w", err)
+	}
+
+	sharing := make(map[uuid.UUID]bool)
+	for _, card := range cards {
+		cardStats := s.cards.StatsFor(card)
+		sharing[card.UserID] = true
+		stats.CardCount++
+		stats.TotalItems += cardStats.TotalItems
+		stats.CompletedItems += cardStats.CompletedItems
+		stats.Bingos += cardStats.BingosAchieved
+		for _, item := range card.Items {
+			if item.IsCompleted && item.CompletedAt != nil && item.CompletedAt.UTC().Year() == year {
+				stats.CompletionsByMonth[item.CompletedAt.UTC().Month()-1]++
+			}
+		}
+	}
+	stats.SharingMembers = len(sharing)
+
+	templateRows, err := s.db.Query(ctx,
+		`SELECT id, title, use_count FROM card_templates
+		 WHERE org_id = $1
+		 ORDER BY use_count DESC, created_at DESC`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("loading organization template usage: %w", err)
+	}
+	defer templateRows.Close()
+	for templateRows.Next() {
+		var usage models.OrgTemplateUsage
+		if err := templateRows.Scan(&usage.TemplateID, &usage.Title, &usage.UseCount); err != nil {
+			return nil, fmt.Errorf("scanning organization template usage: %w", err)
+		}
+		stats.Templates = append(stats.Templates, usage)
+	}
+	if err := templateRows.Err(); err != nil {
+		return nil, fmt.Errorf("loading organization template usage: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// orgRoleRow answers the membership lookup done by orgRole.
+func orgRoleRow(roles map[uuid.UUID]models.OrgRole, sql string, args []any) (Row, bool) {
+	if !strings.Contains(sql, "SELECT role FROM organization_members") {
+		return nil, false
+	}
+	role, ok := roles[args[1].(uuid.UUID)]
+	if !ok {
+		return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}, true
+	}
+	return rowFromValues(string(role)), true
+}
+
+func TestOrganizationService_Create_Validation(t *testing.T) {
+	svc := NewOrganizationService(&fakeDB{}, &fakeCardStats{})
+	for _, name := range []string{"", "   ", strings.Repeat("a", models.MaxOrganizationNameLength+1)} {
+		if _, err := svc.Create(context.Background(), uuid.New(), name); !errors.Is(err, ErrInvalidOrganizationName) {
+			t.Fatalf("expected ErrInvalidOrganizationName for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestOrganizationService_Create_AddsCreatorAsAdmin(t *testing.T) {
+	userID := uuid.New()
+	orgID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "INSERT INTO organization_members") || !strings.Contains(sql, "'admin'") {
+				t.Fatalf("expected creator to be added as admin, got %q", sql)
+			}
+			if args[0] != "Acme" || args[1] != userID {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return rowFromValues(orgID, time.Now())
+		},
+	}
+
+	org, err := NewOrganizationService(db, &fakeCardStats{}).Create(context.Background(), userID, "  Acme ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if org.ID != orgID || org.Role != models.OrgRoleAdmin || org.MemberCount != 1 {
+		t.Fatalf("unexpected organization: %+v", org)
+	}
+}
+
+func TestOrganizationService_NonMemberGetsNotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			row, _ := orgRoleRow(nil, sql, args)
+			return row
+		},
+	}
+	svc := NewOrganizationService(db, &fakeCardStats{})
+
+	if _, err := svc.ListMembers(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrOrganizationNotFound) {
+		t.Fatalf("expected ErrOrganizationNotFound, got %v", err)
+	}
+	if _, err := svc.Stats(context.Background(), uuid.New(), uuid.New(), 2025); !errors.Is(err, ErrOrganizationNotFound) {
+		t.Fatalf("expected ErrOrganizationNotFound, got %v", err)
+	}
+}
+
+func TestOrganizationService_AdminOnly(t *testing.T) {
+	memberID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			row, ok := orgRoleRow(map[uuid.UUID]models.OrgRole{memberID: models.OrgRoleMember}, sql, args)
+			if !ok {
+				t.Fatalf("unexpected query: %q", sql)
+			}
+			return row
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatalf("expected no writes, got %q", sql)
+			return fakeCommandTag{}, nil
+		},
+	}
+	svc := NewOrganizationService(db, &fakeCardStats{})
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	checks := map[string]error{
+		"rename":        svc.Rename(ctx, memberID, orgID, "New name"),
+		"delete":        svc.Delete(ctx, memberID, orgID),
+		"revoke invite": svc.RevokeInvite(ctx, memberID, orgID, uuid.New()),
+		"remove domain": svc.RemoveDomain(ctx, memberID, orgID, "example.com"),
+	}
+	_, _, err := svc.CreateInvite(ctx, memberID, orgID, OrgInviteExpiryDefaultDays)
+	checks["create invite"] = err
+	_, err = svc.Stats(ctx, memberID, orgID, 2025)
+	checks["stats"] = err
+	for name, err := range checks {
+		if !errors.Is(err, ErrNotOrgAdmin) {
+			t.Fatalf("%s: expected ErrNotOrgAdmin, got %v", name, err)
+		}
+	}
+}
+
+func TestOrganizationService_RemoveMember(t *testing.T) {
+	adminID := uuid.New()
+	memberID := uuid.New()
+	otherID := uuid.New()
+	roles := map[uuid.UUID]models.OrgRole{
+		adminID:  models.OrgRoleAdmin,
+		memberID: models.OrgRoleMember,
+		otherID:  models.OrgRoleMember,
+	}
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		target  uuid.UUID
+		admins  int
+		wantErr error
+	}{
+		{name: "admin removes member", actor: adminID, target: memberID, admins: 1},
+		{name: "member leaves", actor: memberID, target: memberID, admins: 1},
+		{name: "member removes other", actor: memberID, target: otherID, admins: 1, wantErr: ErrNotOrgAdmin},
+		{name: "last admin leaves", actor: adminID, target: adminID, admins: 1, wantErr: ErrLastOrgAdmin},
+		{name: "one of two admins leaves", actor: adminID, target: adminID, admins: 2},
+		{name: "not a member", actor: adminID, target: uuid.New(), admins: 1, wantErr: ErrOrgMemberNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted, committed bool
+			tx := &fakeTx{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if row, ok := orgRoleRow(roles, sql, args); ok {
+						return row
+					}
+					if strings.Contains(sql, "role = 'admin'") {
+						return rowFromValues(tt.admins)
+					}
+					t.Fatalf("unexpected query: %q", sql)
+					return nil
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					if strings.Contains(sql, "DELETE FROM organization_members") {
+						deleted = args[1] == tt.target
+					}
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+				CommitFunc: func(ctx context.Context) error {
+					committed = true
+					return nil
+				},
+			}
+			db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+
+			err := NewOrganizationService(db, &fakeCardStats{}).RemoveMember(context.Background(), tt.actor, uuid.New(), tt.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if wantDeleted := tt.wantErr == nil; deleted != wantDeleted || committed != wantDeleted {
+				t.Fatalf("expected deleted/committed %v, got %v/%v", wantDeleted, deleted, committed)
+			}
+		})
+	}
+}
+
+func TestOrganizationService_SetMemberRole_MemberCannotPromoteSelf(t *testing.T) {
+	memberID := uuid.New()
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			row, _ := orgRoleRow(map[uuid.UUID]models.OrgRole{memberID: models.OrgRoleMember}, sql, args)
+			return row
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE organization_members") {
+				t.Fatal("expected no role change")
+			}
+			return fakeCommandTag{}, nil
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+
+	err := NewOrganizationService(db, &fakeCardStats{}).SetMemberRole(context.Background(), memberID, uuid.New(), memberID, models.OrgRoleAdmin)
+	if !errors.Is(err, ErrNotOrgAdmin) {
+		t.Fatalf("expected ErrNotOrgAdmin, got %v", err)
+	}
+}
+
+func TestOrganizationService_CreateInvite(t *testing.T) {
+	adminID := uuid.New()
+	orgID := uuid.New()
+	var storedHash string
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := orgRoleRow(map[uuid.UUID]models.OrgRole{adminID: models.OrgRoleAdmin}, sql, args); ok {
+				return row
+			}
+			if strings.Contains(sql, "COUNT(*) FROM organization_invites") {
+				return rowFromValues(0)
+			}
+			storedHash = args[1].(string)
+			return rowFromValues(uuid.New(), orgID, time.Now().Add(24*time.Hour), nil, 0, time.Now())
+		},
+	}
+	svc := NewOrganizationService(db, &fakeCardStats{})
+
+	if _, _, err := svc.CreateInvite(context.Background(), adminID, orgID, OrgInviteExpiryMaxDays+1); !errors.Is(err, ErrOrgInviteExpiryOutOfRange) {
+		t.Fatalf("expected ErrOrgInviteExpiryOutOfRange, got %v", err)
+	}
+
+	_, token, err := svc.CreateInvite(context.Background(), adminID, orgID, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token == "" || storedHash != hashInviteToken(token) {
+		t.Fatal("expected only the token hash to be stored")
+	}
+}
+
+func TestOrganizationService_AcceptInvite_AlreadyMember(t *testing.T) {
+	var rolledBack bool
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "expires_at > NOW()") || !strings.Contains(sql, "revoked_at IS NULL") {
+				t.Fatalf("expected only live invites, got %q", sql)
+			}
+			return rowFromValues(uuid.New(), uuid.New())
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "use_count") {
+				t.Fatal("expected the use not to be counted")
+			}
+			return fakeCommandTag{}, &pgconn.PgError{Code: "23505"}
+		},
+		RollbackFunc: func(ctx context.Context) error {
+			rolledBack = true
+			return nil
+		},
+	}
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+
+	_, err := NewOrganizationService(db, &fakeCardStats{}).AcceptInvite(context.Background(), uuid.New(), "token")
+	if !errors.Is(err, ErrAlreadyOrgMember) {
+		t.Fatalf("expected ErrAlreadyOrgMember, got %v", err)
+	}
+	if !rolledBack {
+		t.Fatal("expected rollback")
+	}
+}
+
+func TestOrganizationService_AddDomain(t *testing.T) {
+	adminID := uuid.New()
+	tests := []struct {
+		name     string
+		domain   string
+		verified bool
+		insert   error
+		wantErr  error
+	}{
+		{name: "invalid", domain: "not a domain", wantErr: ErrInvalidOrgDomain},
+		{name: "public provider", domain: "Gmail.com", wantErr: ErrOrgDomainNotAllowed},
+		{name: "admin email elsewhere", domain: "acme.com", verified: false, wantErr: ErrOrgDomainNotVerified},
+		{name: "claimed by another org", domain: "acme.com", verified: true, insert: &pgconn.PgError{Code: "23505"}, wantErr: ErrOrgDomainTaken},
+		{name: "added", domain: "@ACME.com ", verified: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if row, ok := orgRoleRow(map[uuid.UUID]models.OrgRole{adminID: models.OrgRoleAdmin}, sql, args); ok {
+						return row
+					}
+					if !strings.Contains(sql, "email_verified") || args[1] != "acme.com" {
+						t.Fatalf("expected verified email check for acme.com, got %q %v", sql, args)
+					}
+					return rowFromValues(tt.verified)
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					return fakeCommandTag{rowsAffected: 1}, tt.insert
+				},
+			}
+
+			err := NewOrganizationService(db, &fakeCardStats{}).AddDomain(context.Background(), adminID, uuid.New(), tt.domain)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOrganizationService_JoinByDomain_RequiresMatchingVerifiedEmail(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "u.email_verified") || !strings.Contains(sql, "organization_domains") {
+				t.Fatalf("expected verified domain check, got %q", sql)
+			}
+			return rowFromValues(false)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatal("expected no membership to be added")
+			return fakeCommandTag{}, nil
+		},
+	}
+
+	_, err := NewOrganizationService(db, &fakeCardStats{}).JoinByDomain(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, ErrOrganizationNotFound) {
+		t.Fatalf("expected ErrOrganizationNotFound, got %v", err)
+	}
+}
+
+func TestOrganizationService_MemberCards_OnlySharedCards(t *testing.T) {
+	viewerID := uuid.New()
+	memberID := uuid.New()
+	notes := "private thoughts"
+	hidden := time.Now()
+	shared := &models.BingoCard{ID: uuid.New(), IsFinalized: true, Visibility: models.VisibilityOrganization,
+		Items: []models.BingoItem{{Content: "Run", Notes: &notes}, {Content: "rude", HiddenAt: &hidden}}}
+	public := &models.BingoCard{ID: uuid.New(), IsFinalized: true, Visibility: models.VisibilityPublic}
+	cards := &fakeCardStats{cards: []*models.BingoCard{
+		shared,
+		public,
+		{ID: uuid.New(), IsFinalized: true, Visibility: models.VisibilityPrivate},
+		{ID: uuid.New(), IsFinalized: true, Visibility: models.VisibilityFriends},
+		{ID: uuid.New(), IsFinalized: true, Visibility: models.VisibilityGroups},
+		{ID: uuid.New(), Visibility: models.VisibilityOrganization},
+	}}
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := orgRoleRow(map[uuid.UUID]models.OrgRole{viewerID: models.OrgRoleAdmin}, sql, args); ok {
+				return row
+			}
+			for _, want := range []string{"user_blocks", "disabled_at IS NULL", "organization_members"} {
+				if !strings.Contains(sql, want) {
+					t.Fatalf("expected member lookup to check %q", want)
+				}
+			}
+			return rowFromValues("bob")
+		},
+	}
+
+	result, err := NewOrganizationService(db, cards).MemberCards(context.Background(), viewerID, uuid.New(), memberID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Cards) != 2 || result.Cards[0].ID != shared.ID || result.Cards[1].ID != public.ID {
+		t.Fatalf("expected only finalized organization and public cards, got %+v", result.Cards)
+	}
+	if items := result.Cards[0].Items; items[0].Notes != nil || items[1].Content != "" {
+		t.Fatalf("expected notes stripped and hidden items redacted, got %+v", items)
+	}
+}
+
+func TestOrganizationService_Stats(t *testing.T) {
+	adminID := uuid.New()
+	aliceID := uuid.New()
+	bobID := uuid.New()
+	card1 := uuid.New()
+	card2 := uuid.New()
+	templateID := uuid.New()
+	march := time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC)
+	lastYear := time.Date(2024, time.December, 31, 12, 0, 0, 0, time.UTC)
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if row, ok := orgRoleRow(map[uuid.UUID]models.OrgRole{adminID: models.OrgRoleAdmin}, sql, args); ok {
+				return row
+			}
+			return rowFromValues(5)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if strings.Contains(sql, "FROM card_templates") {
+				return &fakeRows{rows: [][]any{{templateID, "Team goals", 4}}}, nil
+			}
+			if !strings.Contains(sql, "c.is_finalized") || !strings.Contains(sql, "'organization', 'public'") {
+				t.Fatalf("expected only shared finalized cards, got %q", sql)
+			}
+			return &fakeRows{rows: [][]any{
+				{card1, aliceID, 2, false, nil, itemPos(0), boolPtr(true), &march},
+				{card1, aliceID, 2, false, nil, itemPos(1), boolPtr(true), &lastYear},
+				{card1, aliceID, 2, false, nil, itemPos(2), boolPtr(false), nil},
+				{card2, bobID, 2, false, nil, nil, nil, nil},
+			}}, nil
+		},
+	}
+
+	stats, err := NewOrganizationService(db, NewCardService(nil)).Stats(context.Background(), adminID, uuid.New(), 2025)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.MemberCount != 5 || stats.SharingMembers != 2 || stats.CardCount != 2 {
+		t.Fatalf("unexpected counts: %+v", stats)
+	}
+	if stats.TotalItems != 8 || stats.CompletedItems != 2 || stats.Bingos != 1 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+	if stats.CompletionsByMonth[time.March-1] != 1 || stats.CompletionsByMonth[time.December-1] != 0 {
+		t.Fatalf("expected only this year's completions by month, got %v", stats.CompletionsByMonth)
+	}
+	if len(stats.Templates) != 1 || stats.Templates[0].UseCount != 4 {
+		t.Fatalf("unexpected template usage: %+v", stats.Templates)
+	}
+}
+
+func TestOrganizationService_PurgeInactive(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "DELETE FROM organization_invites") {
+				t.Fatalf("unexpected sql: %s", sql)
+			}
+			return fakeCommandTag{rowsAffected: 2}, nil
+		},
+	}
+
+	count, err := NewOrganizationService(db, &fakeCardStats{}).PurgeInactive(context.Background())
+	if err != nil || count != 2 {
+		t.Fatalf("expected 2 purged, got %d %v", count, err)
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var ErrProfileNotFound = errors.New("profile not found")
+
+type ProfileService struct {
+	db    DBConn
+	cards CardStatsSource
+}
+
+func NewProfileService(db DBConn, cards CardStatsSource) *ProfileService {
+	return &ProfileService{db: db, cards: cards}
+}
+
+// GetPublicProfile returns a user's public profile. Users who haven't opted
+// in, and disabled accounts, are reported as not found. Only finalized cards
+// with public visibility are included, and their notes and proof links are
+// left out.
+func (s *ProfileService) GetPublicProfile(ctx context.Context, username string) (*models.PublicProfile, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, ErrProfileNotFound
+	}
+
+	var userID uuid.UUID
+	profile := &models.PublicProfile{Cards: []*models.BingoCard{}}
+	err := s.db.QueryRow(ctx,
+		`SELECT id, username, created_at FROM users
+		 WHERE LOWER(username) = LOWER($1) AND public_profile = true AND disabled_at IS NULL`,
+		username,
+	).Scan(&userID, &profile.Username, &profile.MemberSince)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting profile: %w", err)
+	}
+
+	cards, err := s.cards.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var allStats []models.CardStats
+	years := make(map[int]bool)
+	for _, card := range cards {
+		if !card.IsFinalized || card.IsArchived || card.Visibility != models.VisibilityPublic {
+			continue
+		}
+
+		stats := s.cards.StatsFor(card)
+		allStats = append(allStats, *stats)
+		profile.Stats.CardCount++
+		profile.Stats.TotalItems += stats.TotalItems
+		profile.Stats.CompletedItems += stats.CompletedItems
+		profile.Stats.Bingos += stats.BingosAchieved
+		years[card.Year] = true
+
+		for i := range card.Items {
+			card.Items[i].Redact()
+			card.Items[i].Notes = nil
+			card.Items[i].ProofURL = nil
+		}
+		profile.Cards = append(profile.Cards, card)
+	}
+
+	profile.Stats.Years = make([]int, 0, len(years))
+	for year := range years {
+		profile.Stats.Years = append(profile.Stats.Years, year)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(profile.Stats.Years)))
+	if profile.Stats.TotalItems > 0 {
+		profile.Stats.CompletionRate = float64(profile.Stats.CompletedItems) / float64(profile.Stats.TotalItems) * 100
+	}
+	profile.Badges = models.EarnedBadges(allStats)
+
+	return profile, nil
+}
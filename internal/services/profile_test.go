@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// fakeCardStats serves a fixed set of cards and scores each one with the
+// stats registered for it.
+type fakeCardStats struct {
+	cards []*models.BingoCard
+	stats map[uuid.UUID]models.CardStats
+	err   error
+}
+
+func (f *fakeCardStats) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
+	return f.cards, f.err
+}
+
+func (f *fakeCardStats) StatsFor(card *models.BingoCard) *models.CardStats {
+	stats := f.stats[card.ID]
+	return &stats
+}
+
+func profileUserDB(t *testing.T) *fakeDB {
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "public_profile = true") || !strings.Contains(sql, "disabled_at IS NULL") {
+				t.Fatalf("expected opt-in and disabled checks, got %q", sql)
+			}
+			return rowFromValues(uuid.New(), "Alice", time.Now())
+		},
+	}
+}
+
+func TestProfileService_GetPublicProfile_NotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	svc := NewProfileService(db, &fakeCardStats{})
+
+	if _, err := svc.GetPublicProfile(context.Background(), "alice"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+	if _, err := svc.GetPublicProfile(context.Background(), "  "); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound for blank username, got %v", err)
+	}
+}
+
+func TestProfileService_GetPublicProfile_OnlyPublicCards(t *testing.T) {
+	notes := "private thoughts"
+	proof := "https://example.com/proof"
+	public2024 := &models.BingoCard{ID: uuid.New(), Year: 2024, IsFinalized: true, Visibility: models.VisibilityPublic,
+		Items: []models.BingoItem{{Content: "Run", IsCompleted: true, Notes: &notes, ProofURL: &proof}}}
+	public2025 := &models.BingoCard{ID: uuid.New(), Year: 2025, IsFinalized: true, Visibility: models.VisibilityPublic}
+	friendsOnly := &models.BingoCard{ID: uuid.New(), Year: 2025, IsFinalized: true, Visibility: models.VisibilityFriends}
+	draft := &models.BingoCard{ID: uuid.New(), Year: 2026, Visibility: models.VisibilityPublic}
+	archived := &models.BingoCard{ID: uuid.New(), Year: 2023, IsFinalized: true, IsArchived: true, Visibility: models.VisibilityPublic}
+
+	cards := &fakeCardStats{
+		cards: []*models.BingoCard{public2025, friendsOnly, draft, public2024, archived},
+		stats: map[uuid.UUID]models.CardStats{
+			public2024.ID:  {Year: 2024, TotalItems: 4, CompletedItems: 4, BingosAchieved: 2},
+			public2025.ID:  {Year: 2025, TotalItems: 4, CompletedItems: 1},
+			friendsOnly.ID: {Year: 2025, TotalItems: 4, CompletedItems: 4, BingosAchieved: 10},
+			archived.ID:    {Year: 2023, TotalItems: 4, CompletedItems: 4, BingosAchieved: 10},
+		},
+	}
+	svc := NewProfileService(profileUserDB(t), cards)
+
+	profile, err := svc.GetPublicProfile(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.Username != "Alice" {
+		t.Fatalf("expected stored username casing, got %q", profile.Username)
+	}
+	if len(profile.Cards) != 2 || profile.Cards[0].ID != public2025.ID || profile.Cards[1].ID != public2024.ID {
+		t.Fatalf("expected only public, finalized, unarchived cards, got %+v", profile.Cards)
+	}
+	if item := profile.Cards[1].Items[0]; item.Notes != nil || item.ProofURL != nil {
+		t.Fatalf("expected notes and proof to be stripped, got %+v", item)
+	}
+
+	want := models.ProfileStats{Years: []int{2025, 2024}, CardCount: 2, TotalItems: 8, CompletedItems: 5, CompletionRate: 62.5, Bingos: 2}
+	got := profile.Stats
+	if got.CardCount != want.CardCount || got.TotalItems != want.TotalItems || got.CompletedItems != want.CompletedItems ||
+		got.Bingos != want.Bingos || got.CompletionRate != want.CompletionRate || len(got.Years) != 2 || got.Years[0] != 2025 {
+		t.Fatalf("expected stats %+v, got %+v", want, got)
+	}
+	if len(profile.Badges) != 3 {
+		t.Fatalf("expected bingo, halfway and blackout badges, got %+v", profile.Badges)
+	}
+}
+
+func TestProfileService_GetPublicProfile_CardError(t *testing.T) {
+	svc := NewProfileService(profileUserDB(t), &fakeCardStats{err: errors.New("boom")})
+
+	if _, err := svc.GetPublicProfile(context.Background(), "alice"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -21,6 +21,7 @@ var (
 type ReactionService struct {
 	db            DBConn
 	friendService FriendChecker
+	activity      ActivityRecorder
 }
 
 func NewReactionService(db DBConn, friendService FriendChecker) *ReactionService {
@@ -30,6 +31,11 @@ func NewReactionService(db DBConn, friendService FriendChecker) *ReactionService
 	}
 }
 
+// SetActivityRecorder enables writing reactions to the friend activity feed.
+func (s *ReactionService) SetActivityRecorder(activity ActivityRecorder) {
+	s.activity = activity
+}
+
 func (s *ReactionService) AddReaction(ctx context.Context, userID, itemID uuid.UUID, emoji string) (*models.Reaction, error) {
 	// Validate emoji
 	if !isValidEmoji(emoji) {
@@ -37,15 +43,15 @@ func (s *ReactionService) AddReaction(ctx context.Context, userID, itemID uuid.U
 	}
 
 	// Get the item and its card to check ownership and completion
-	var cardUserID uuid.UUID
-	var isCompleted bool
+	var cardUserID, cardID uuid.UUID
+	var isCompleted, visible bool
 	err := s.db.QueryRow(ctx,
-		`SELECT bc.user_id, bi.is_completed
+		`SELECT bc.user_id, bi.is_completed, bc.id, `+cardVisibleToSQL("bc", "$2")+`
 		 FROM bingo_items bi
 		 JOIN bingo_cards bc ON bi.card_id = bc.id
-		 WHERE bi.id = $1`,
-		itemID,
-	).Scan(&cardUserID, &isCompleted)
+		 WHERE bi.id = $1 AND bi.hidden_at IS NULL`,
+		itemID, userID,
+	).Scan(&cardUserID, &isCompleted, &cardID, &visible)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrItemNotFound
 	}
@@ -58,6 +64,11 @@ func (s *ReactionService) AddReaction(ctx context.Context, userID, itemID uuid.U
 		return nil, ErrCannotReactToOwn
 	}
 
+	// Cards not shared with the user look the same as missing items
+	if !visible {
+		return nil, ErrItemNotFound
+	}
+
 	// Can only react to completed items
 	if !isCompleted {
 		return nil, ErrItemNotCompleted
@@ -86,6 +97,8 @@ func (s *ReactionService) AddReaction(ctx context.Context, userID, itemID uuid.U
 		return nil, fmt.Errorf("adding reaction: %w", err)
 	}
 
+	recordActivity(ctx, s.activity, models.ActivityEvent{ActorID: userID, Type: models.ActivityReaction, CardID: cardID, ItemID: &itemID, Emoji: &reaction.Emoji})
+
 	return reaction, nil
 }
 
@@ -100,6 +113,7 @@ func (s *ReactionService) RemoveReaction(ctx context.Context, userID, itemID uui
 	if result.RowsAffected() == 0 {
 		return ErrReactionNotFound
 	}
+	removeActivity(ctx, s.activity, userID, models.ActivityReaction, itemID)
 	return nil
 }
 
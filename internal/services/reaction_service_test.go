@@ -79,7 +79,7 @@ func TestReactionService_AddReaction_CannotReactToOwnItem(t *testing.T) {
 	userID := uuid.New()
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(userID, true)
+			return rowFromValues(userID, true, uuid.New(), true)
 		},
 	}
 	friend := &fakeFriendChecker{}
@@ -98,7 +98,7 @@ func TestReactionService_AddReaction_ItemNotCompleted(t *testing.T) {
 	userID := uuid.New()
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(uuid.New(), false)
+			return rowFromValues(uuid.New(), false, uuid.New(), true)
 		},
 	}
 	friend := &fakeFriendChecker{}
@@ -116,7 +116,7 @@ func TestReactionService_AddReaction_ItemNotCompleted(t *testing.T) {
 func TestReactionService_AddReaction_NotFriend(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(uuid.New(), true)
+			return rowFromValues(uuid.New(), true, uuid.New(), true)
 		},
 	}
 	friend := &fakeFriendChecker{isFriend: false}
@@ -134,7 +134,7 @@ func TestReactionService_AddReaction_NotFriend(t *testing.T) {
 func TestReactionService_AddReaction_FriendCheckError(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(uuid.New(), true)
+			return rowFromValues(uuid.New(), true, uuid.New(), true)
 		},
 	}
 	friend := &fakeFriendChecker{err: errors.New("friend error")}
@@ -152,7 +152,7 @@ func TestReactionService_AddReaction_InsertError(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if strings.Contains(sql, "FROM bingo_items") {
-				return rowFromValues(uuid.New(), true)
+				return rowFromValues(uuid.New(), true, uuid.New(), true)
 			}
 			return fakeRow{scanFunc: func(dest ...any) error {
 				return errors.New("insert error")
@@ -174,7 +174,7 @@ func TestReactionService_AddReaction_Success(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if strings.Contains(sql, "FROM bingo_items") {
-				return rowFromValues(uuid.New(), true)
+				return rowFromValues(uuid.New(), true, uuid.New(), true)
 			}
 			return rowFromValues(uuid.New(), itemID, userID, "🎉", time.Now())
 		},
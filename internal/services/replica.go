@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// ReplicaDB sends read-only statements to a read replica and everything else
+// to the primary. Once a request has written through it, the rest of that
+// request reads from the primary too, so it always sees its own writes; see
+// WithReadAffinity.
+type ReplicaDB struct {
+	primary DB
+	replica DBConn
+}
+
+// NewReplicaDB routes reads to replica and writes and transactions to primary.
+func NewReplicaDB(primary DB, replica DBConn) *ReplicaDB {
+	return &ReplicaDB{primary: primary, replica: replica}
+}
+
+func (r *ReplicaDB) Exec(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+	markWrote(ctx)
+	return r.primary.Exec(ctx, sql, args...)
+}
+
+func (r *ReplicaDB) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	return r.route(ctx, sql).Query(ctx, sql, args...)
+}
+
+func (r *ReplicaDB) QueryRow(ctx context.Context, sql string, args ...any) Row {
+	return r.route(ctx, sql).QueryRow(ctx, sql, args...)
+}
+
+// Begin always uses the primary: transactions are where writes happen.
+func (r *ReplicaDB) Begin(ctx context.Context) (Tx, error) {
+	markWrote(ctx)
+	return r.primary.Begin(ctx)
+}
+
+func (r *ReplicaDB) route(ctx context.Context, sql string) DBConn {
+	if !isReadOnlySQL(sql) {
+		markWrote(ctx)
+		return r.primary
+	}
+	if affinity, ok := ctx.Value(readAffinityKey{}).(*readAffinity); ok && affinity.wrote.Load() {
+		return r.primary
+	}
+	return r.replica
+}
+
+// writeSQL matches anything that makes a SELECT or WITH statement more than a
+// plain read: data-modifying CTEs, row locks, sequences, advisory locks and
+// notifications.
+var writeSQL = regexp.MustCompile(`(?i)\b(insert|update|delete|merge|share|nextval|setval|pg_notify|pg_(try_)?advisory_\w+)\b`)
+
+// isReadOnlySQL reports whether sql can safely run on a replica.
+func isReadOnlySQL(sql string) bool {
+	trimmed := strings.ToLower(strings.TrimSpace(sql))
+	if !strings.HasPrefix(trimmed, "select") && !strings.HasPrefix(trimmed, "with") {
+		return false
+	}
+	return !writeSQL.MatchString(trimmed)
+}
+
+type readAffinity struct {
+	wrote atomic.Bool
+}
+
+type readAffinityKey struct{}
+
+// WithReadAffinity starts read-your-writes tracking for a request: after its
+// first write, ReplicaDB sends the rest of its reads to the primary. Contexts
+// without it, such as background jobs, always read from the replica.
+func WithReadAffinity(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readAffinityKey{}, &readAffinity{})
+}
+
+// withoutReadAffinity lets a bookkeeping write skip read-your-writes tracking.
+func withoutReadAffinity(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readAffinityKey{}, nil)
+}
+
+func markWrote(ctx context.Context) {
+	if affinity, ok := ctx.Value(readAffinityKey{}).(*readAffinity); ok {
+		affinity.wrote.Store(true)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestIsReadOnlySQL(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"SELECT id, updated_at FROM bingo_cards WHERE id = $1", true},
+		{"  with recent AS (SELECT 1) SELECT * FROM recent", true},
+		{"SELECT share_token FROM bingo_cards", true},
+		{"UPDATE bingo_cards SET version = version + 1 RETURNING version", false},
+		{"INSERT INTO sessions (id) VALUES ($1) RETURNING id", false},
+		{"WITH updated AS (UPDATE bingo_cards SET visibility = $1 RETURNING id) SELECT COUNT(*) FROM updated", false},
+		{"SELECT user_id FROM bingo_cards WHERE id = $1 FOR UPDATE", false},
+		{"SELECT id FROM email_outbox FOR NO KEY UPDATE SKIP LOCKED", false},
+		{"SELECT id FROM friendships FOR SHARE", false},
+		{"SELECT pg_try_advisory_xact_lock($1)", false},
+	}
+	for _, tt := range tests {
+		if got := isReadOnlySQL(tt.sql); got != tt.want {
+			t.Errorf("isReadOnlySQL(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestReplicaDB_RoutesReadsUntilRequestWrites(t *testing.T) {
+	var primaryReads, replicaReads int
+	primary := &fakeDB{QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		primaryReads++
+		return &fakeRows{}, nil
+	}}
+	replica := &fakeDB{QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		replicaReads++
+		return &fakeRows{}, nil
+	}}
+	db := NewReplicaDB(primary, replica)
+
+	// Background work without affinity always reads from the replica.
+	_, _ = db.Exec(context.Background(), "DELETE FROM sessions")
+	_, _ = db.Query(context.Background(), "SELECT id FROM sessions")
+	if replicaReads != 1 || primaryReads != 0 {
+		t.Fatalf("expected replica read, got primary=%d replica=%d", primaryReads, replicaReads)
+	}
+
+	ctx := WithReadAffinity(context.Background())
+	_, _ = db.Query(ctx, "SELECT id FROM bingo_cards")
+	if replicaReads != 2 {
+		t.Fatalf("expected reads before a write to use the replica, got %d", replicaReads)
+	}
+
+	_, _ = db.Exec(ctx, "UPDATE bingo_cards SET title = $1")
+	_, _ = db.Query(ctx, "SELECT id FROM bingo_cards")
+	if primaryReads != 1 || replicaReads != 2 {
+		t.Fatalf("expected reads after a write to use the primary, got primary=%d replica=%d", primaryReads, replicaReads)
+	}
+
+	// Locking reads go to the primary and pin the request there.
+	other := WithReadAffinity(context.Background())
+	_, _ = db.Query(other, "SELECT id FROM bingo_cards FOR UPDATE")
+	_, _ = db.Query(other, "SELECT id FROM bingo_cards")
+	if primaryReads != 3 {
+		t.Fatalf("expected locking read to pin the request to the primary, got %d", primaryReads)
+	}
+}
+
+func TestReplicaDB_BookkeepingWritesDontPin(t *testing.T) {
+	var primaryReads int
+	primary := &fakeDB{QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		primaryReads++
+		return &fakeRows{}, nil
+	}}
+	db := NewReplicaDB(primary, &fakeDB{})
+	tokens := NewApiTokenService(db)
+
+	ctx := WithReadAffinity(context.Background())
+	_ = tokens.UpdateLastUsed(ctx, uuid.New())
+	_, _ = db.Query(ctx, "SELECT id FROM bingo_cards")
+	if primaryReads != 0 {
+		t.Fatal("expected token bookkeeping not to pin reads to the primary")
+	}
+}
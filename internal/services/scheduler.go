@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrDuplicateJob = errors.New("job already registered")
+	ErrInvalidJob   = errors.New("job needs a name and a function")
+)
+
+// JobFunc is the work a scheduled job does.
+type JobFunc func(ctx context.Context) error
+
+// ScheduledJob is a job registered with the Scheduler.
+type ScheduledJob struct {
+	Name     string
+	Schedule string // cron expression, see ParseCron
+	Run      JobFunc
+}
+
+type schedulerEntry struct {
+	job      ScheduledJob
+	schedule *CronSchedule
+	next     time.Time
+	running  bool
+}
+
+// Scheduler runs registered jobs on their cron schedules. Every replica runs
+// a Scheduler; a Postgres advisory lock and a claim on the job's
+// scheduled_jobs row make sure each tick runs on only one of them.
+type Scheduler struct {
+	db       DB
+	instance string
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*schedulerEntry
+	wg      sync.WaitGroup
+}
+
+// NewScheduler returns a scheduler that records runs under instance, usually
+// the hostname.
+func NewScheduler(db DB, instance string) *Scheduler {
+	return &Scheduler{
+		db:       db,
+		instance: instance,
+		now:      time.Now,
+		entries:  make(map[string]*schedulerEntry),
+	}
+}
+
+func (s *Scheduler) Register(job ScheduledJob) error {
+	if job.Name == "" || job.Run == nil {
+		return ErrInvalidJob
+	}
+	schedule, err := ParseCron(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[job.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateJob, job.Name)
+	}
+	s.entries[job.Name] = &schedulerEntry{job: job, schedule: schedule, next: schedule.Next(s.now())}
+	return nil
+}
+
+// Run starts due jobs until ctx is cancelled, then waits for running jobs to
+// return. Jobs receive ctx, so they should stop promptly once it is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.wg.Wait()
+	for {
+		wait := s.startDue(ctx)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// startDue starts every job whose tick has passed and returns how long to
+// sleep until the next one.
+func (s *Scheduler) startDue(ctx context.Context) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	wait := time.Minute
+	for _, entry := range s.entries {
+		if entry.next.IsZero() {
+			continue
+		}
+		if !entry.next.After(now) {
+			tick := entry.next
+			entry.next = entry.schedule.Next(now)
+			if !entry.running {
+				entry.running = true
+				s.wg.Add(1)
+				go func(entry *schedulerEntry) {
+					defer s.wg.Done()
+					_, _ = s.runTick(ctx, entry.job, tick)
+					s.mu.Lock()
+					entry.running = false
+					s.mu.Unlock()
+				}(entry)
+			}
+		}
+		if d := entry.next.Sub(now); !entry.next.IsZero() && d < wait {
+			wait = d
+		}
+	}
+	return wait
+}
+
+// runTick runs one tick of a job if this replica wins it. It reports whether
+// the job ran.
+//
+// The advisory lock is transaction-scoped, so the transaction stays open for
+// the length of the job; the job itself uses other connections. Claiming the
+// tick in scheduled_jobs stops a replica whose clock is slightly behind from
+// running the same tick again after the first one has finished.
+func (s *Scheduler) runTick(ctx context.Context, job ScheduledJob, tick time.Time) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logFailure(ctx, job, "Failed to start job", err)
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext($1))`, "scheduled_job:"+job.Name).Scan(&locked); err != nil {
+		s.logFailure(ctx, job, "Failed to lock job", err)
+		return false, fmt.Errorf("lock job: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	var claimed string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO scheduled_jobs (name, schedule, last_scheduled_at)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (name) DO UPDATE
+		 SET schedule = EXCLUDED.schedule, last_scheduled_at = EXCLUDED.last_scheduled_at
+		 WHERE scheduled_jobs.last_scheduled_at IS NULL OR scheduled_jobs.last_scheduled_at < EXCLUDED.last_scheduled_at
+		 RETURNING name`,
+		job.Name, job.Schedule, tick,
+	).Scan(&claimed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		s.logFailure(ctx, job, "Failed to claim job", err)
+		return false, fmt.Errorf("claim job: %w", err)
+	}
+
+	started := s.now()
+	runErr := job.Run(ctx)
+	finished := s.now()
+
+	var lastError *string
+	if runErr != nil {
+		msg := runErr.Error()
+		lastError = &msg
+		s.logFailure(ctx, job, "Scheduled job failed", runErr)
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE scheduled_jobs
+		 SET last_started_at = $2, last_finished_at = $3, last_duration_ms = $4, last_error = $5,
+		     last_success_at = CASE WHEN $5::text IS NULL THEN $3 ELSE last_success_at END,
+		     last_instance = $6, run_count = run_count + 1
+		 WHERE name = $1`,
+		job.Name, started, finished, finished.Sub(started).Milliseconds(), lastError, s.instance,
+	)
+	if err != nil {
+		s.logFailure(ctx, job, "Failed to record job run", err)
+		return true, fmt.Errorf("record job run: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		s.logFailure(ctx, job, "Failed to record job run", err)
+		return true, fmt.Errorf("commit transaction: %w", err)
+	}
+	return true, runErr
+}
+
+func (s *Scheduler) logFailure(ctx context.Context, job ScheduledJob, msg string, err error) {
+	logging.FromContext(ctx).Warn(msg, map[string]interface{}{
+		"job":   job.Name,
+		"error": err.Error(),
+	})
+}
+
+// Status lists the jobs registered on this server with the latest run
+// recorded by any replica, sorted by name.
+func (s *Scheduler) Status(ctx context.Context) ([]models.JobStatus, error) {
+	s.mu.Lock()
+	statuses := make([]models.JobStatus, 0, len(s.entries))
+	for _, entry := range s.entries {
+		status := models.JobStatus{Name: entry.job.Name, Schedule: entry.job.Schedule, Running: entry.running}
+		if !entry.next.IsZero() {
+			next := entry.next
+			status.NextRunAt = &next
+		}
+		statuses = append(statuses, status)
+	}
+	s.mu.Unlock()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	rows, err := s.db.Query(ctx,
+		`SELECT name, last_started_at, last_finished_at, last_duration_ms, last_error, last_success_at, last_instance, run_count
+		 FROM scheduled_jobs`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list job runs: %w", err)
+	}
+	defer rows.Close()
+
+	byName := make(map[string]*models.JobStatus, len(statuses))
+	for i := range statuses {
+		byName[statuses[i].Name] = &statuses[i]
+	}
+	for rows.Next() {
+		var name string
+		var run models.JobStatus
+		if err := rows.Scan(&name, &run.LastStartedAt, &run.LastFinishedAt, &run.LastDurationMs, &run.LastError,
+			&run.LastSuccessAt, &run.LastInstance, &run.RunCount); err != nil {
+			return nil, fmt.Errorf("scan job run: %w", err)
+		}
+		status, ok := byName[name]
+		if !ok {
+			// A job another version of the server registered; not ours to report.
+			continue
+		}
+		run.Name, run.Schedule, run.NextRunAt, run.Running = status.Name, status.Schedule, status.NextRunAt, status.Running
+		*status = run
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list job runs: %w", err)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// schedulerTx fakes the transaction runTick uses: the advisory lock result,
+// whether the tick claim succeeds, and the recorded run.
+func schedulerTx(t *testing.T, locked, claimed bool, recorded *[]any, committed *bool) *fakeTx {
+	return &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "pg_try_advisory_xact_lock"):
+				if args[0] != "scheduled_job:cleanup" {
+					t.Fatalf("unexpected lock key: %v", args[0])
+				}
+				return rowFromValues(locked)
+			case strings.Contains(sql, "INSERT INTO scheduled_jobs"):
+				if !claimed {
+					return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+				}
+				return rowFromValues("cleanup")
+			}
+			t.Fatalf("unexpected query: %s", sql)
+			return nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "UPDATE scheduled_jobs") {
+				t.Fatalf("unexpected exec: %s", sql)
+			}
+			*recorded = args
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		CommitFunc: func(ctx context.Context) error {
+			*committed = true
+			return nil
+		},
+	}
+}
+
+func newTestScheduler(tx Tx) *Scheduler {
+	db := &fakeDB{BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil }}
+	return NewScheduler(db, "web-1")
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s := NewScheduler(&fakeDB{}, "web-1")
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := s.Register(ScheduledJob{Name: "cleanup", Schedule: "0 3 * * *", Run: noop}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Register(ScheduledJob{Name: "cleanup", Schedule: "0 4 * * *", Run: noop}); !errors.Is(err, ErrDuplicateJob) {
+		t.Fatalf("expected ErrDuplicateJob, got %v", err)
+	}
+	if err := s.Register(ScheduledJob{Name: "other", Schedule: "often", Run: noop}); !errors.Is(err, ErrInvalidCronExpression) {
+		t.Fatalf("expected ErrInvalidCronExpression, got %v", err)
+	}
+	if err := s.Register(ScheduledJob{Name: "nothing", Schedule: "* * * * *"}); !errors.Is(err, ErrInvalidJob) {
+		t.Fatalf("expected ErrInvalidJob, got %v", err)
+	}
+}
+
+func TestScheduler_RunTick_SkipsWhenLockedElsewhere(t *testing.T) {
+	var recorded []any
+	var committed bool
+	s := newTestScheduler(schedulerTx(t, false, true, &recorded, &committed))
+
+	ran, err := s.runTick(context.Background(), ScheduledJob{Name: "cleanup", Schedule: "@daily", Run: func(ctx context.Context) error {
+		t.Fatal("job should not run")
+		return nil
+	}}, time.Now())
+	if ran || err != nil || committed {
+		t.Fatalf("expected skip, got ran=%v err=%v committed=%v", ran, err, committed)
+	}
+}
+
+func TestScheduler_RunTick_SkipsClaimedTick(t *testing.T) {
+	var recorded []any
+	var committed bool
+	s := newTestScheduler(schedulerTx(t, true, false, &recorded, &committed))
+
+	ran, err := s.runTick(context.Background(), ScheduledJob{Name: "cleanup", Schedule: "@daily", Run: func(ctx context.Context) error {
+		t.Fatal("job should not run")
+		return nil
+	}}, time.Now())
+	if ran || err != nil || committed {
+		t.Fatalf("expected skip, got ran=%v err=%v committed=%v", ran, err, committed)
+	}
+}
+
+func TestScheduler_RunTick_RecordsSuccess(t *testing.T) {
+	var recorded []any
+	var committed bool
+	s := newTestScheduler(schedulerTx(t, true, true, &recorded, &committed))
+
+	var ran bool
+	ok, err := s.runTick(context.Background(), ScheduledJob{Name: "cleanup", Schedule: "@daily", Run: func(ctx context.Context) error {
+		ran = true
+		return nil
+	}}, time.Now())
+	if !ok || err != nil || !ran || !committed {
+		t.Fatalf("expected run, got ok=%v err=%v ran=%v committed=%v", ok, err, ran, committed)
+	}
+	if recorded[4].(*string) != nil || recorded[5] != "web-1" {
+		t.Fatalf("unexpected recorded run: %v", recorded)
+	}
+}
+
+func TestScheduler_RunTick_RecordsFailure(t *testing.T) {
+	var recorded []any
+	var committed bool
+	s := newTestScheduler(schedulerTx(t, true, true, &recorded, &committed))
+
+	boom := errors.New("boom")
+	_, err := s.runTick(context.Background(), ScheduledJob{Name: "cleanup", Schedule: "@daily", Run: func(ctx context.Context) error {
+		return boom
+	}}, time.Now())
+	if !errors.Is(err, boom) || !committed {
+		t.Fatalf("expected job error to be returned and recorded, got err=%v committed=%v", err, committed)
+	}
+	if msg := recorded[4].(*string); msg == nil || *msg != "boom" {
+		t.Fatalf("expected error to be recorded, got %v", recorded[4])
+	}
+}
+
+func TestScheduler_Status(t *testing.T) {
+	finished := time.Now()
+	duration := int64(42)
+	instance := "web-2"
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{
+				{"cleanup", &finished, &finished, &duration, nil, &finished, &instance, int64(3)},
+				{"retired_job", nil, nil, nil, nil, nil, nil, int64(9)},
+			}}, nil
+		},
+	}
+	s := NewScheduler(db, "web-1")
+	noop := func(ctx context.Context) error { return nil }
+	_ = s.Register(ScheduledJob{Name: "purge", Schedule: "@hourly", Run: noop})
+	_ = s.Register(ScheduledJob{Name: "cleanup", Schedule: "@daily", Run: noop})
+
+	statuses, err := s.Status(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].Name != "cleanup" || statuses[1].Name != "purge" {
+		t.Fatalf("expected registered jobs sorted by name, got %+v", statuses)
+	}
+	cleanup := statuses[0]
+	if cleanup.RunCount != 3 || *cleanup.LastInstance != "web-2" || *cleanup.LastDurationMs != 42 || cleanup.Schedule != "@daily" || cleanup.NextRunAt == nil {
+		t.Fatalf("unexpected cleanup status: %+v", cleanup)
+	}
+	if statuses[1].RunCount != 0 || statuses[1].LastStartedAt != nil {
+		t.Fatalf("expected purge to have no runs yet, got %+v", statuses[1])
+	}
+}
@@ -113,3 +113,19 @@ func (s *SuggestionService) GetGroupedByCategory(ctx context.Context) ([]Suggest
 
 	return result, nil
 }
+
+// Reseed replaces every suggestion with the rows inserted by seedSQL (the
+// seed migration's INSERT statements) and returns how many are active. The
+// statements are sent as one simple-protocol batch, which PostgreSQL runs in
+// a single implicit transaction, so a failed seed leaves the old rows intact.
+func (s *SuggestionService) Reseed(ctx context.Context, seedSQL string) (int, error) {
+	if _, err := s.db.Exec(ctx, "DELETE FROM suggestions;\n"+seedSQL); err != nil {
+		return 0, fmt.Errorf("reseeding suggestions: %w", err)
+	}
+
+	var count int
+	if err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM suggestions WHERE is_active = true`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting suggestions: %w", err)
+	}
+	return count, nil
+}
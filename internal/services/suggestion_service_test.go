@@ -211,3 +211,39 @@ func TestSuggestionService_GetGroupedByCategory_Error(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestSuggestionService_Reseed(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.HasPrefix(sql, "DELETE FROM suggestions;") || !strings.Contains(sql, "INSERT INTO suggestions") {
+				t.Fatalf("expected delete then seed in one batch, got %q", sql)
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(2)
+		},
+	}
+
+	svc := NewSuggestionService(db)
+	count, err := svc.Reseed(context.Background(), "INSERT INTO suggestions (category, content) VALUES ('health', 'Walk'), ('travel', 'Fly');")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 suggestions, got %d", count)
+	}
+}
+
+func TestSuggestionService_Reseed_ExecError(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return nil, context.Canceled
+		},
+	}
+
+	svc := NewSuggestionService(db)
+	if _, err := svc.Reseed(context.Background(), "SELECT 1;"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrTemplateNotFound           = errors.New("template not found")
+	ErrNotTemplateOwner           = errors.New("not the owner of this template")
+	ErrInvalidTemplateTitle       = errors.New("invalid template title")
+	ErrTemplateDescriptionTooLong = errors.New("template description too long")
+	ErrInvalidTemplateVisibility  = errors.New("invalid template visibility")
+	ErrTemplateEmpty              = errors.New("template needs at least one item")
+)
+
+type TemplateService struct {
+	db            DB
+	cards         TemplateCardSource
+	notifications NotificationServiceInterface
+}
+
+func NewTemplateService(db DB, cards TemplateCardSource) *TemplateService {
+	return &TemplateService{db: db, cards: cards}
+}
+
+func (s *TemplateService) SetNotificationService(notifications NotificationServiceInterface) {
+	s.notifications = notifications
+}
+
+const templateColumns = `t.id, t.owner_id, u.username, t.source_card_id, t.title, t.description, t.category,
+		        t.grid_size, t.header_text, t.has_free_space, t.free_space_position, t.items,
+		        t.visibility, t.org_id, t.use_count, t.hidden_at, t.created_at, t.updated_at`
+
+func scanTemplate(row Row) (*models.CardTemplate, error) {
+	var t models.CardTemplate
+	var items []byte
+	if err := row.Scan(
+		&t.ID, &t.OwnerID, &t.OwnerUsername, &t.SourceCardID, &t.Title, &t.Description, &t.Category,
+		&t.GridSize, &t.HeaderText, &t.HasFreeSpace, &t.FreeSpacePos, &items,
+		&t.Visibility, &t.OrgID, &t.UseCount, &t.HiddenAt, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(items, &t.Items); err != nil {
+		return nil, fmt.Errorf("decode template items: %w", err)
+	}
+	return &t, nil
+}
+
+func normalizeTemplateTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" || utf8.RuneCountInString(title) > 100 {
+		return "", ErrInvalidTemplateTitle
+	}
+	return title, nil
+}
+
+func normalizeTemplateDescription(description *string) (*string, error) {
+	if description == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*description)
+	if utf8.RuneCountInString(trimmed) > models.MaxTemplateDescriptionLength {
+		return nil, ErrTemplateDescriptionTooLong
+	}
+	if trimmed == "" {
+		return nil, nil
+	}
+	return &trimmed, nil
+}
+
+// Publish copies one of the owner's cards into a new template. Items hidden
+// by a moderator are left out; completion state and notes never leave the
+// card. Organization templates can only be published by the organization's
+// admins, and its members are notified.
+func (s *TemplateService) Publish(ctx context.Context, params models.PublishTemplateParams) (*models.CardTemplate, error) {
+	if params.Visibility == "" {
+		params.Visibility = models.TemplateUnlisted
+	}
+	if !params.Visibility.IsValid() || (params.Visibility == models.TemplateOrganization) != (params.OrgID != nil) {
+		return nil, ErrInvalidTemplateVisibility
+	}
+	description, err := normalizeTemplateDescription(params.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := s.cards.GetByID(ctx, params.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.UserID != params.OwnerID {
+		return nil, ErrNotCardOwner
+	}
+	if params.OrgID != nil {
+		if err := requireOrgAdmin(ctx, s.db, *params.OrgID, params.OwnerID); err != nil {
+			return nil, err
+		}
+	}
+
+	rawTitle := card.DisplayName()
+	if params.Title != nil {
+		rawTitle = *params.Title
+	}
+	title, err := normalizeTemplateTitle(rawTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.TemplateItem, 0, len(card.Items))
+	for _, item := range card.Items {
+		if item.HiddenAt != nil {
+			continue
+		}
+		items = append(items, models.TemplateItem{Position: item.Position, Content: item.Content})
+	}
+	if len(items) == 0 {
+		return nil, ErrTemplateEmpty
+	}
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("encode template items: %w", err)
+	}
+
+	template, err := scanTemplate(s.db.QueryRow(ctx,
+		`WITH t AS (
+		   INSERT INTO card_templates (owner_id, source_card_id, title, description, category, grid_size, header_text,
+		                               has_free_space, free_space_position, items, visibility, org_id)
+		   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		   RETURNING *
+		 )
+		 SELECT `+templateColumns+`
+		 FROM t JOIN users u ON u.id = t.owner_id`,
+		params.OwnerID, card.ID, title, description, card.Category, card.GridSize, card.HeaderText,
+		card.HasFreeSpace, card.FreeSpacePos, itemsJSON, string(params.Visibility), params.OrgID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("publish template: %w", err)
+	}
+
+	if template.OrgID != nil && s.notifications != nil {
+		if err := s.notifications.NotifyOrgTemplate(ctx, params.OwnerID, template.ID); err != nil {
+			logging.FromContext(ctx).Warn("Failed to notify organization of template", map[string]interface{}{
+				"error":       err.Error(),
+				"template_id": template.ID.String(),
+			})
+		}
+	}
+	return template, nil
+}
+
+// List returns a page of the public gallery. Query matches titles by
+// substring or, for small typos, by trigram similarity. hasMore reports
+// whether another page follows.
+func (s *TemplateService) List(ctx context.Context, params models.TemplateListParams) ([]models.CardTemplate, bool, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = models.DefaultTemplatePageSize
+	}
+	if limit > models.MaxTemplatePageSize {
+		limit = models.MaxTemplatePageSize
+	}
+	offset := params.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := strings.ToLower(strings.TrimSpace(params.Query))
+	orderBy := "t.use_count DESC, t.created_at DESC"
+	if params.Sort == models.TemplateSortNewest {
+		orderBy = "t.created_at DESC"
+	}
+	if query != "" {
+		orderBy = "LOWER(t.title) LIKE '%' || $1 || '%' DESC, similarity(LOWER(t.title), $1) DESC, " + orderBy
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT `+templateColumns+`
+		 FROM card_templates t
+		 JOIN users u ON u.id = t.owner_id
+		 WHERE t.visibility = 'public'
+		   AND t.hidden_at IS NULL
+		   AND u.disabled_at IS NULL
+		   AND ($1 = '' OR LOWER(t.title) LIKE '%' || $1 || '%' OR LOWER(t.title) % $1)
+		   AND ($2 = '' OR t.category = $2)
+		   AND ($3 = 0 OR t.grid_size = $3)
+		 ORDER BY `+orderBy+`
+		 LIMIT $4 OFFSET $5`,
+		query, params.Category, params.GridSize, limit+1, offset,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("list templates: %w", err)
+	}
+	templates, err := collectTemplates(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(templates) > limit
+	if hasMore {
+		templates = templates[:limit]
+	}
+	return templates, hasMore, nil
+}
+
+// ListMine returns every template the user has published, newest first,
+// including unlisted and hidden ones.
+func (s *TemplateService) ListMine(ctx context.Context, ownerID uuid.UUID) ([]models.CardTemplate, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT `+templateColumns+`
+		 FROM card_templates t
+		 JOIN users u ON u.id = t.owner_id
+		 WHERE t.owner_id = $1
+		 ORDER BY t.created_at DESC`,
+		ownerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list my templates: %w", err)
+	}
+	return collectTemplates(rows)
+}
+
+func collectTemplates(rows Rows) ([]models.CardTemplate, error) {
+	defer rows.Close()
+
+	templates := []models.CardTemplate{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan template: %w", err)
+		}
+		templates = append(templates, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+	return templates, nil
+}
+
+// Get returns a template to viewerID, which is uuid.Nil for anonymous
+// visitors. Unlisted templates are viewable by anyone with the link and
+// organization templates by the organization's members; hidden templates
+// and those of suspended owners only by their owner.
+func (s *TemplateService) Get(ctx context.Context, viewerID, templateID uuid.UUID) (*models.CardTemplate, error) {
+	var ownerDisabled, outsider bool
+	var t models.CardTemplate
+	var items []byte
+	err := s.db.QueryRow(ctx,
+		`SELECT `+templateColumns+`, u.disabled_at IS NOT NULL,
+		        t.org_id IS NOT NULL AND NOT EXISTS (
+		          SELECT 1 FROM organization_members m WHERE m.org_id = t.org_id AND m.user_id = $2
+		        )
+		 FROM card_templates t
+		 JOIN users u ON u.id = t.owner_id
+		 WHERE t.id = $1`,
+		templateID, viewerID,
+	).Scan(
+		&t.ID, &t.OwnerID, &t.OwnerUsername, &t.SourceCardID, &t.Title, &t.Description, &t.Category,
+		&t.GridSize, &t.HeaderText, &t.HasFreeSpace, &t.FreeSpacePos, &items,
+		&t.Visibility, &t.OrgID, &t.UseCount, &t.HiddenAt, &t.CreatedAt, &t.UpdatedAt, &ownerDisabled, &outsider,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get template: %w", err)
+	}
+	if t.OwnerID != viewerID && (t.HiddenAt != nil || ownerDisabled || outsider) {
+		return nil, ErrTemplateNotFound
+	}
+	if err := json.Unmarshal(items, &t.Items); err != nil {
+		return nil, fmt.Errorf("decode template items: %w", err)
+	}
+	return &t, nil
+}
+
+// Update changes a template's title, description or visibility. Only the
+// owner may update it. Templates can't be moved into or out of an
+// organization.
+func (s *TemplateService) Update(ctx context.Context, ownerID, templateID uuid.UUID, params models.UpdateTemplateParams) (*models.CardTemplate, error) {
+	var title *string
+	if params.Title != nil {
+		normalized, err := normalizeTemplateTitle(*params.Title)
+		if err != nil {
+			return nil, err
+		}
+		title = &normalized
+	}
+	var description *string
+	if params.Description != nil {
+		normalized, err := normalizeTemplateDescription(params.Description)
+		if err != nil {
+			return nil, err
+		}
+		// An empty description clears it.
+		empty := ""
+		description = &empty
+		if normalized != nil {
+			description = normalized
+		}
+	}
+	var visibility *string
+	if params.Visibility != nil {
+		if !params.Visibility.IsValid() || *params.Visibility == models.TemplateOrganization {
+			return nil, ErrInvalidTemplateVisibility
+		}
+		v := string(*params.Visibility)
+		visibility = &v
+	}
+
+	if err := s.checkOwner(ctx, ownerID, templateID); err != nil {
+		return nil, err
+	}
+
+	template, err := scanTemplate(s.db.QueryRow(ctx,
+		`WITH t AS (
+		   UPDATE card_templates
+		   SET title = COALESCE($3, title),
+		       description = CASE WHEN $4::text IS NULL THEN description ELSE NULLIF($4, '') END,
+		       visibility = COALESCE($5, visibility),
+		       updated_at = NOW()
+		   WHERE id = $1 AND owner_id = $2
+		   RETURNING *
+		 )
+		 SELECT `+templateColumns+`
+		 FROM t JOIN users u ON u.id = t.owner_id`,
+		templateID, ownerID, title, description, visibility,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "card_templates_org_check" {
+		return nil, ErrInvalidTemplateVisibility
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update template: %w", err)
+	}
+	return template, nil
+}
+
+// Delete removes a template. Cards already created from it are unaffected.
+func (s *TemplateService) Delete(ctx context.Context, ownerID, templateID uuid.UUID) error {
+	if err := s.checkOwner(ctx, ownerID, templateID); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(ctx, "DELETE FROM card_templates WHERE id = $1 AND owner_id = $2", templateID, ownerID); err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+	return nil
+}
+
+func (s *TemplateService) checkOwner(ctx context.Context, userID, templateID uuid.UUID) error {
+	var ownerID uuid.UUID
+	err := s.db.QueryRow(ctx, "SELECT owner_id FROM card_templates WHERE id = $1", templateID).Scan(&ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrTemplateNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get template owner: %w", err)
+	}
+	if ownerID != userID {
+		return ErrNotTemplateOwner
+	}
+	return nil
+}
+
+// Instantiate creates a draft card for the user from a template and counts
+// the use. Callers check CheckForConflict first so a clash with an existing
+// card can be reported with its details.
+func (s *TemplateService) Instantiate(ctx context.Context, templateID uuid.UUID, params models.InstantiateTemplateParams) (*models.BingoCard, error) {
+	template, err := s.Get(ctx, params.UserID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := params.Title
+	if title == nil {
+		title = &template.Title
+	}
+	items := make([]models.ImportItem, len(template.Items))
+	for i, item := range template.Items {
+		items[i] = models.ImportItem{Position: item.Position, Content: item.Content}
+	}
+
+	card, err := s.cards.Import(ctx, models.ImportCardParams{
+		UserID:       params.UserID,
+		Year:         params.Year,
+		Title:        title,
+		Category:     template.Category,
+		Items:        items,
+		GridSize:     template.GridSize,
+		HeaderText:   template.HeaderText,
+		HasFreeSpace: template.HasFreeSpace,
+		FreeSpacePos: template.FreeSpacePos,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(ctx,
+		"UPDATE card_templates SET use_count = use_count + 1 WHERE id = $1",
+		templateID,
+	); err != nil {
+		logging.FromContext(ctx).Warn("Failed to count template use", map[string]interface{}{
+			"error":       err.Error(),
+			"template_id": templateID.String(),
+		})
+	}
+
+	return card, nil
+}
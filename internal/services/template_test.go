@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type fakeTemplateCards struct {
+	card     *models.BingoCard
+	imported []models.ImportCardParams
+	err      error
+}
+
+func (f *fakeTemplateCards) GetByID(ctx context.Context, cardID uuid.UUID) (*models.BingoCard, error) {
+	if f.card == nil {
+		return nil, ErrCardNotFound
+	}
+	return f.card, nil
+}
+
+func (f *fakeTemplateCards) Import(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error) {
+	f.imported = append(f.imported, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.BingoCard{ID: uuid.New(), UserID: params.UserID, Year: params.Year, Title: params.Title}, nil
+}
+
+// templateRowValues returns the columns scanned by scanTemplate.
+func templateRowValues(id, ownerID uuid.UUID, hiddenAt *time.Time, items ...models.TemplateItem) []any {
+	data, _ := json.Marshal(items)
+	free := 12
+	return []any{
+		id, ownerID, "owner", (*uuid.UUID)(nil), "Weekend goals", (*string)(nil), (*string)(nil),
+		5, "BINGO", true, &free, data,
+		"public", (*uuid.UUID)(nil), 3, hiddenAt, time.Now(), time.Now(),
+	}
+}
+
+func TestTemplateService_Publish_SkipsHiddenItems(t *testing.T) {
+	ownerID := uuid.New()
+	hidden := time.Now()
+	title := "Weekend goals"
+	cards := &fakeTemplateCards{card: &models.BingoCard{
+		ID: uuid.New(), UserID: ownerID, Year: 2025, Title: &title, GridSize: 3, HeaderText: "BIN",
+		Items: []models.BingoItem{
+			{Position: 0, Content: "Run a 5k", IsCompleted: true},
+			{Position: 1, Content: "rude", HiddenAt: &hidden},
+		},
+	}}
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "INSERT INTO card_templates") {
+				t.Fatalf("unexpected query: %s", sql)
+			}
+			var items []models.TemplateItem
+			if err := json.Unmarshal(args[9].([]byte), &items); err != nil {
+				t.Fatalf("decoding items: %v", err)
+			}
+			if len(items) != 1 || items[0].Content != "Run a 5k" {
+				t.Fatalf("expected hidden item to be skipped, got %+v", items)
+			}
+			if args[2] != title || args[10] != string(models.TemplateUnlisted) {
+				t.Fatalf("unexpected insert args: %v", args)
+			}
+			return rowFromValues(templateRowValues(uuid.New(), ownerID, nil, items...)...)
+		},
+	}
+	svc := NewTemplateService(db, cards)
+
+	template, err := svc.Publish(context.Background(), models.PublishTemplateParams{OwnerID: ownerID, CardID: cards.card.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(template.Items) != 1 || template.OwnerUsername != "owner" {
+		t.Fatalf("unexpected template: %+v", template)
+	}
+}
+
+func TestTemplateService_Publish_Errors(t *testing.T) {
+	ownerID := uuid.New()
+	longDescription := strings.Repeat("x", models.MaxTemplateDescriptionLength+1)
+	blank := "  "
+
+	tests := []struct {
+		name   string
+		card   *models.BingoCard
+		params models.PublishTemplateParams
+		want   error
+	}{
+		{name: "not owner", card: &models.BingoCard{UserID: uuid.New(), Items: []models.BingoItem{{Content: "a"}}}, want: ErrNotCardOwner},
+		{name: "no items", card: &models.BingoCard{UserID: ownerID}, want: ErrTemplateEmpty},
+		{name: "blank title", card: &models.BingoCard{UserID: ownerID}, params: models.PublishTemplateParams{Title: &blank}, want: ErrInvalidTemplateTitle},
+		{name: "bad visibility", params: models.PublishTemplateParams{Visibility: "friends"}, want: ErrInvalidTemplateVisibility},
+		{name: "long description", params: models.PublishTemplateParams{Description: &longDescription}, want: ErrTemplateDescriptionTooLong},
+		{name: "missing card", want: ErrCardNotFound},
+		{name: "organization without org", params: models.PublishTemplateParams{Visibility: models.TemplateOrganization}, want: ErrInvalidTemplateVisibility},
+		{name: "org on public template", params: models.PublishTemplateParams{Visibility: models.TemplatePublic, OrgID: &ownerID}, want: ErrInvalidTemplateVisibility},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewTemplateService(&fakeDB{}, &fakeTemplateCards{card: tt.card})
+			tt.params.OwnerID = ownerID
+			if _, err := svc.Publish(context.Background(), tt.params); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTemplateService_Publish_Organization(t *testing.T) {
+	ownerID := uuid.New()
+	orgID := uuid.New()
+	card := &models.BingoCard{ID: uuid.New(), UserID: ownerID, GridSize: 3, Items: []models.BingoItem{{Content: "Volunteer"}}}
+
+	for _, role := range []models.OrgRole{models.OrgRoleMember, models.OrgRoleAdmin} {
+		t.Run(string(role), func(t *testing.T) {
+			var notified bool
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if strings.Contains(sql, "FROM organization_members") {
+						return rowFromValues(string(role))
+					}
+					if args[11] != &orgID {
+						t.Fatalf("expected org_id to be stored, got %v", args[11])
+					}
+					values := templateRowValues(uuid.New(), ownerID, nil)
+					values[12] = "organization"
+					values[13] = &orgID
+					return rowFromValues(values...)
+				},
+			}
+			svc := NewTemplateService(db, &fakeTemplateCards{card: card})
+			svc.SetNotificationService(&stubNotificationService{
+				NotifyOrgTemplateFunc: func(ctx context.Context, actorID, templateID uuid.UUID) error {
+					notified = actorID == ownerID
+					return nil
+				},
+			})
+
+			_, err := svc.Publish(context.Background(), models.PublishTemplateParams{
+				OwnerID: ownerID, CardID: card.ID, Visibility: models.TemplateOrganization, OrgID: &orgID,
+			})
+			if role == models.OrgRoleMember {
+				if !errors.Is(err, ErrNotOrgAdmin) || notified {
+					t.Fatalf("expected ErrNotOrgAdmin without notification, got %v %v", err, notified)
+				}
+				return
+			}
+			if err != nil || !notified {
+				t.Fatalf("expected published and notified, got %v %v", err, notified)
+			}
+		})
+	}
+}
+
+func TestTemplateService_List_HasMore(t *testing.T) {
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "t.visibility = 'public'") || !strings.Contains(sql, "t.hidden_at IS NULL") {
+				t.Fatalf("expected public, visible templates only: %s", sql)
+			}
+			if args[0] != "goals" || args[3] != 3 || args[4] != 2 {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			rows := make([][]any, 3)
+			for i := range rows {
+				rows[i] = templateRowValues(uuid.New(), uuid.New(), nil)
+			}
+			return &fakeRows{rows: rows}, nil
+		},
+	}
+	svc := NewTemplateService(db, &fakeTemplateCards{})
+
+	templates, hasMore, err := svc.List(context.Background(), models.TemplateListParams{Query: "  Goals ", Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 2 || !hasMore {
+		t.Fatalf("expected 2 templates and more to come, got %d %v", len(templates), hasMore)
+	}
+}
+
+func TestTemplateService_Get_HiddenOnlyForOwner(t *testing.T) {
+	ownerID := uuid.New()
+	hidden := time.Now()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(append(templateRowValues(args[0].(uuid.UUID), ownerID, &hidden), false, false)...)
+		},
+	}
+	svc := NewTemplateService(db, &fakeTemplateCards{})
+
+	if _, err := svc.Get(context.Background(), uuid.Nil, uuid.New()); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound for anonymous viewer, got %v", err)
+	}
+	if _, err := svc.Get(context.Background(), ownerID, uuid.New()); err != nil {
+		t.Fatalf("expected owner to see hidden template, got %v", err)
+	}
+}
+
+func TestTemplateService_Get_OrganizationMembersOnly(t *testing.T) {
+	ownerID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "organization_members") || args[1] == nil {
+				t.Fatalf("expected membership check for the viewer, got %q %v", sql, args)
+			}
+			outsider := args[1] != ownerID
+			return rowFromValues(append(templateRowValues(args[0].(uuid.UUID), ownerID, nil), false, outsider)...)
+		},
+	}
+	svc := NewTemplateService(db, &fakeTemplateCards{})
+
+	if _, err := svc.Get(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound for non-member, got %v", err)
+	}
+	if _, err := svc.Get(context.Background(), ownerID, uuid.New()); err != nil {
+		t.Fatalf("expected member to see template, got %v", err)
+	}
+}
+
+func TestTemplateService_Update_CannotMoveIntoOrganization(t *testing.T) {
+	svc := NewTemplateService(&fakeDB{}, &fakeTemplateCards{})
+	visibility := models.TemplateOrganization
+	_, err := svc.Update(context.Background(), uuid.New(), uuid.New(), models.UpdateTemplateParams{Visibility: &visibility})
+	if !errors.Is(err, ErrInvalidTemplateVisibility) {
+		t.Fatalf("expected ErrInvalidTemplateVisibility, got %v", err)
+	}
+}
+
+func TestTemplateService_Update_NotOwner(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "UPDATE") {
+				t.Fatal("expected no update")
+			}
+			return rowFromValues(uuid.New())
+		},
+	}
+	svc := NewTemplateService(db, &fakeTemplateCards{})
+
+	title := "Mine now"
+	if _, err := svc.Update(context.Background(), uuid.New(), uuid.New(), models.UpdateTemplateParams{Title: &title}); !errors.Is(err, ErrNotTemplateOwner) {
+		t.Fatalf("expected ErrNotTemplateOwner, got %v", err)
+	}
+}
+
+func TestTemplateService_Instantiate(t *testing.T) {
+	userID := uuid.New()
+	templateID := uuid.New()
+	var counted bool
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			values := templateRowValues(templateID, uuid.New(), nil,
+				models.TemplateItem{Position: 0, Content: "Run a 5k"},
+				models.TemplateItem{Position: 4, Content: "Read 12 books"},
+			)
+			return rowFromValues(append(values, false, false)...)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			counted = strings.Contains(sql, "use_count = use_count + 1") && args[0] == templateID
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	cards := &fakeTemplateCards{}
+	svc := NewTemplateService(db, cards)
+
+	card, err := svc.Instantiate(context.Background(), templateID, models.InstantiateTemplateParams{UserID: userID, Year: 2025})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card.UserID != userID || !counted {
+		t.Fatalf("expected card for user and use counted: %+v counted=%v", card, counted)
+	}
+	imported := cards.imported[0]
+	if *imported.Title != "Weekend goals" || imported.GridSize != 5 || imported.Finalize || len(imported.Items) != 2 || imported.Items[1].Position != 4 {
+		t.Fatalf("unexpected import params: %+v", imported)
+	}
+}
+
+func TestTemplateService_Instantiate_ConflictNotCounted(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(append(templateRowValues(uuid.New(), uuid.New(), nil), false, false)...)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatal("expected use not to be counted")
+			return nil, nil
+		},
+	}
+	svc := NewTemplateService(db, &fakeTemplateCards{err: ErrCardTitleExists})
+
+	_, err := svc.Instantiate(context.Background(), uuid.New(), models.InstantiateTemplateParams{UserID: uuid.New(), Year: 2025})
+	if !errors.Is(err, ErrCardTitleExists) {
+		t.Fatalf("expected ErrCardTitleExists, got %v", err)
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+// Throttled routes. The names double as THROTTLE_<ROUTE> config keys.
+const (
+	ThrottleLogin          = "login"
+	ThrottleMagicLink      = "magic_link"
+	ThrottleForgotPassword = "forgot_password"
+	ThrottleResetPassword  = "reset_password"
+	ThrottleVerifyEmail    = "verify_email"
+	ThrottleInviteAccept   = "invite_accept"
+)
+
+// ThrottleRule configures brute-force protection for one route. Failures are
+// counted per IP and per account over Window, and reaching either limit blocks
+// for the full Lockout. Before that, once FreeAttempts is exceeded, each
+// further failure blocks for Backoff, doubling every time. Backoff applies to
+// the account when the route limits accounts and to the IP otherwise, so a
+// shared address isn't slowed down by one user's typos. A zero limit disables
+// that dimension.
+type ThrottleRule struct {
+	IPLimit         int
+	AccountLimit    int
+	FreeAttempts    int
+	Window          time.Duration
+	Backoff         time.Duration
+	Lockout         time.Duration
+	NotifyOnLockout bool
+}
+
+// DefaultThrottleRules are used for any route without a config override.
+var DefaultThrottleRules = map[string]ThrottleRule{
+	ThrottleLogin: {
+		IPLimit: 50, AccountLimit: 10, FreeAttempts: 3,
+		Window: 15 * time.Minute, Backoff: time.Second, Lockout: 15 * time.Minute,
+		NotifyOnLockout: true,
+	},
+	ThrottleMagicLink: {
+		IPLimit: 20, AccountLimit: 5, FreeAttempts: 3,
+		Window: time.Hour, Backoff: 30 * time.Second, Lockout: time.Hour,
+	},
+	ThrottleForgotPassword: {
+		IPLimit: 20, AccountLimit: 5, FreeAttempts: 3,
+		Window: time.Hour, Backoff: 30 * time.Second, Lockout: time.Hour,
+	},
+	ThrottleResetPassword: {
+		IPLimit: 20, FreeAttempts: 5,
+		Window: 15 * time.Minute, Backoff: time.Second, Lockout: 15 * time.Minute,
+	},
+	ThrottleVerifyEmail: {
+		IPLimit: 20, FreeAttempts: 5,
+		Window: 15 * time.Minute, Backoff: time.Second, Lockout: 15 * time.Minute,
+	},
+	ThrottleInviteAccept: {
+		IPLimit: 30, AccountLimit: 10, FreeAttempts: 5,
+		Window: 15 * time.Minute, Backoff: time.Second, Lockout: 15 * time.Minute,
+	},
+}
+
+// ThrottleRulesFromConfig applies THROTTLE_<ROUTE> overrides on top of the
+// defaults. Overrides only need to name the fields they change, e.g.
+// "account=5,lockout=30m".
+func ThrottleRulesFromConfig(overrides map[string]string) (map[string]ThrottleRule, error) {
+	rules := make(map[string]ThrottleRule, len(DefaultThrottleRules))
+	for route, rule := range DefaultThrottleRules {
+		rules[route] = rule
+	}
+	for route, spec := range overrides {
+		base, ok := rules[route]
+		if !ok {
+			return nil, fmt.Errorf("unknown throttle route %q", route)
+		}
+		rule, err := parseThrottleRule(base, spec)
+		if err != nil {
+			return nil, fmt.Errorf("throttle route %q: %w", route, err)
+		}
+		rules[route] = rule
+	}
+	return rules, nil
+}
+
+func parseThrottleRule(rule ThrottleRule, spec string) (ThrottleRule, error) {
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return rule, fmt.Errorf("expected key=value, got %q", part)
+		}
+		var err error
+		switch strings.TrimSpace(key) {
+		case "ip":
+			rule.IPLimit, err = parseNonNegative(value)
+		case "account":
+			rule.AccountLimit, err = parseNonNegative(value)
+		case "free":
+			rule.FreeAttempts, err = parseNonNegative(value)
+		case "window":
+			rule.Window, err = parsePositiveDuration(value)
+		case "backoff":
+			rule.Backoff, err = parsePositiveDuration(value)
+		case "lockout":
+			rule.Lockout, err = parsePositiveDuration(value)
+		case "notify":
+			rule.NotifyOnLockout, err = strconv.ParseBool(strings.TrimSpace(value))
+		default:
+			return rule, fmt.Errorf("unknown setting %q", key)
+		}
+		if err != nil {
+			return rule, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	return rule, nil
+}
+
+func parseNonNegative(value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return n, nil
+}
+
+func parsePositiveDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+	return d, nil
+}
+
+// ThrottleTarget identifies who is making an attempt. Account is a normalized
+// email or user ID and may be empty for routes that only limit by IP. Email,
+// when set, is warned if the account gets locked out.
+type ThrottleTarget struct {
+	IP      string
+	Account string
+	Email   string
+}
+
+// ThrottleStore is the counter storage behind ThrottleService. It follows
+// Redis semantics: keys expire on their own and missing keys read as zero.
+type ThrottleStore interface {
+	// Incr increments key and returns the new value, setting ttl when the key is new.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Block creates or replaces a marker key that expires after ttl.
+	Block(ctx context.Context, key string, ttl time.Duration) error
+	// TTL returns the time left on key, or zero when it does not exist.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	Del(ctx context.Context, keys ...string) error
+}
+
+type ThrottleService struct {
+	store    ThrottleStore
+	rules    map[string]ThrottleRule
+	notifier LockoutNotifier
+	now      func() time.Time
+}
+
+func NewThrottleService(store ThrottleStore, rules map[string]ThrottleRule, notifier LockoutNotifier) *ThrottleService {
+	return &ThrottleService{store: store, rules: rules, notifier: notifier, now: time.Now}
+}
+
+// Allow reports how long the target must wait before trying route again; zero
+// means the attempt may proceed. Storage errors fail open.
+func (s *ThrottleService) Allow(ctx context.Context, route string, target ThrottleTarget) time.Duration {
+	rule, ok := s.rules[route]
+	if !ok {
+		return 0
+	}
+
+	var wait time.Duration
+	for _, key := range s.blockKeys(route, rule, target) {
+		ttl, err := s.store.TTL(ctx, key)
+		if err != nil {
+			logThrottleError(ctx, "check", route, err)
+			return 0
+		}
+		if ttl > wait {
+			wait = ttl
+		}
+	}
+	return wait
+}
+
+// Fail records a failed attempt, applying backoff or lockout as needed.
+func (s *ThrottleService) Fail(ctx context.Context, route string, target ThrottleTarget) {
+	rule, ok := s.rules[route]
+	if !ok {
+		return
+	}
+
+	if rule.IPLimit > 0 && target.IP != "" {
+		s.fail(ctx, route, rule, "ip:"+target.IP, rule.IPLimit, rule.AccountLimit == 0, nil)
+	}
+	if rule.AccountLimit > 0 && target.Account != "" {
+		s.fail(ctx, route, rule, "account:"+target.Account, rule.AccountLimit, true, func(until time.Time) {
+			if !rule.NotifyOnLockout || target.Email == "" || s.notifier == nil {
+				return
+			}
+			if err := s.notifier.SendLockoutAlert(ctx, target.Email, until); err != nil {
+				logging.FromContext(ctx).Error("Failed to send lockout alert", map[string]interface{}{"error": err.Error(), "route": route})
+			}
+		})
+	}
+}
+
+// Hit counts an attempt that should be limited whether or not it succeeds,
+// such as a request that sends email. It returns the wait as Allow does.
+func (s *ThrottleService) Hit(ctx context.Context, route string, target ThrottleTarget) time.Duration {
+	if wait := s.Allow(ctx, route, target); wait > 0 {
+		return wait
+	}
+	s.Fail(ctx, route, target)
+	return 0
+}
+
+// Succeed clears the account's failure count after a successful attempt. IP
+// counters are left alone so one good password doesn't reset a spray.
+func (s *ThrottleService) Succeed(ctx context.Context, route string, target ThrottleTarget) {
+	if _, ok := s.rules[route]; !ok || target.Account == "" {
+		return
+	}
+	if err := s.store.Del(ctx, throttleKey(route, "account:"+target.Account, "fails")); err != nil {
+		logThrottleError(ctx, "reset", route, err)
+	}
+}
+
+func (s *ThrottleService) fail(ctx context.Context, route string, rule ThrottleRule, subject string, limit int, backoff bool, onLockout func(until time.Time)) {
+	count, err := s.store.Incr(ctx, throttleKey(route, subject, "fails"), rule.Window)
+	if err != nil {
+		logThrottleError(ctx, "record", route, err)
+		return
+	}
+
+	var block time.Duration
+	switch {
+	case count >= int64(limit):
+		block = rule.Lockout
+	case backoff && count > int64(rule.FreeAttempts):
+		block = backoffDelay(rule, count-int64(rule.FreeAttempts))
+	default:
+		return
+	}
+
+	if err := s.store.Block(ctx, throttleKey(route, subject, "block"), block); err != nil {
+		logThrottleError(ctx, "block", route, err)
+		return
+	}
+	// Only the failure that reaches the limit notifies; later failures inside
+	// the same window extend the lockout quietly.
+	if count == int64(limit) && onLockout != nil {
+		onLockout(s.now().Add(block))
+	}
+}
+
+// backoffDelay doubles the base delay for each failure past the free
+// attempts, capped at the lockout duration.
+func backoffDelay(rule ThrottleRule, over int64) time.Duration {
+	delay := rule.Backoff
+	for i := int64(1); i < over && delay < rule.Lockout; i++ {
+		delay *= 2
+	}
+	if delay > rule.Lockout {
+		return rule.Lockout
+	}
+	return delay
+}
+
+func (s *ThrottleService) blockKeys(route string, rule ThrottleRule, target ThrottleTarget) []string {
+	var keys []string
+	if rule.IPLimit > 0 && target.IP != "" {
+		keys = append(keys, throttleKey(route, "ip:"+target.IP, "block"))
+	}
+	if rule.AccountLimit > 0 && target.Account != "" {
+		keys = append(keys, throttleKey(route, "account:"+target.Account, "block"))
+	}
+	return keys
+}
+
+func throttleKey(route, subject, kind string) string {
+	return fmt.Sprintf("throttle:%s:%s:%s", route, subject, kind)
+}
+
+func logThrottleError(ctx context.Context, op, route string, err error) {
+	logging.FromContext(ctx).Error("Throttle store error", map[string]interface{}{"error": err.Error(), "op": op, "route": route})
+}
+
+// RedisThrottleStore keeps throttle counters in Redis.
+type RedisThrottleStore struct {
+	client *redis.Client
+}
+
+func NewRedisThrottleStore(client *redis.Client) *RedisThrottleStore {
+	return &RedisThrottleStore{client: client}
+}
+
+var throttleIncrScript = redis.NewScript(`
+	local current = redis.call("INCR", KEYS[1])
+	if current == 1 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	end
+	return current
+`)
+
+func (s *RedisThrottleStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return throttleIncrScript.Run(ctx, s.client, []string{key}, ttl.Milliseconds()).Int64()
+}
+
+func (s *RedisThrottleStore) Block(ctx context.Context, key string, ttl time.Duration) error {
+	return s.client.Set(ctx, key, 1, ttl).Err()
+}
+
+func (s *RedisThrottleStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL reports missing keys and keys without expiry as negative values.
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s *RedisThrottleStore) Del(ctx context.Context, keys ...string) error {
+	return s.client.Del(ctx, keys...).Err()
+}
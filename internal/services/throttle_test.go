@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// throttleClock drives both the service clock and miniredis key expiry, so
+// tests can step past backoffs and lockouts without sleeping.
+type throttleClock struct {
+	mr  *miniredis.Miniredis
+	now time.Time
+}
+
+func (c *throttleClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.mr.FastForward(d)
+}
+
+type fakeLockoutNotifier struct {
+	sent  []string
+	until []time.Time
+}
+
+func (f *fakeLockoutNotifier) SendLockoutAlert(ctx context.Context, toEmail string, until time.Time) error {
+	f.sent = append(f.sent, toEmail)
+	f.until = append(f.until, until)
+	return nil
+}
+
+// newTestThrottle runs the service against RedisThrottleStore backed by a
+// local miniredis, so the Lua increment script and key expiry are exercised.
+func newTestThrottle(t *testing.T, rule ThrottleRule, notifier LockoutNotifier) (*ThrottleService, *throttleClock) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { _ = client.Close() })
+
+	clock := &throttleClock{mr: mr, now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	svc := NewThrottleService(NewRedisThrottleStore(client), map[string]ThrottleRule{ThrottleLogin: rule}, notifier)
+	svc.now = func() time.Time { return clock.now }
+	return svc, clock
+}
+
+var testLoginRule = ThrottleRule{
+	IPLimit: 100, AccountLimit: 5, FreeAttempts: 2,
+	Window: 15 * time.Minute, Backoff: time.Second, Lockout: 10 * time.Minute,
+	NotifyOnLockout: true,
+}
+
+func TestThrottleService_ProgressiveBackoff(t *testing.T) {
+	svc, clock := newTestThrottle(t, testLoginRule, nil)
+	ctx := context.Background()
+	target := ThrottleTarget{IP: "10.0.0.1", Account: "a@example.com"}
+
+	// Free attempts don't block.
+	for i := 0; i < 2; i++ {
+		svc.Fail(ctx, ThrottleLogin, target)
+		if wait := svc.Allow(ctx, ThrottleLogin, target); wait != 0 {
+			t.Fatalf("attempt %d: expected no wait, got %s", i+1, wait)
+		}
+	}
+
+	// Each further failure doubles the wait.
+	for _, want := range []time.Duration{time.Second, 2 * time.Second} {
+		svc.Fail(ctx, ThrottleLogin, target)
+		if wait := svc.Allow(ctx, ThrottleLogin, target); wait != want {
+			t.Fatalf("expected wait %s, got %s", want, wait)
+		}
+		clock.advance(want)
+		if wait := svc.Allow(ctx, ThrottleLogin, target); wait != 0 {
+			t.Fatalf("expected backoff to expire, got %s", wait)
+		}
+	}
+}
+
+func TestThrottleService_LockoutNotifiesOnce(t *testing.T) {
+	notifier := &fakeLockoutNotifier{}
+	svc, clock := newTestThrottle(t, testLoginRule, notifier)
+	ctx := context.Background()
+	target := ThrottleTarget{IP: "10.0.0.1", Account: "a@example.com", Email: "a@example.com"}
+
+	for i := 0; i < 5; i++ {
+		svc.Fail(ctx, ThrottleLogin, target)
+	}
+	if wait := svc.Allow(ctx, ThrottleLogin, target); wait != 10*time.Minute {
+		t.Fatalf("expected full lockout, got %s", wait)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != "a@example.com" || !notifier.until[0].Equal(clock.now.Add(10*time.Minute)) {
+		t.Fatalf("expected one lockout alert, got %v %v", notifier.sent, notifier.until)
+	}
+
+	// Another failure inside the window keeps the lock without re-notifying.
+	svc.Fail(ctx, ThrottleLogin, target)
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected no second alert, got %d", len(notifier.sent))
+	}
+
+	// A different account from the same IP is unaffected.
+	other := ThrottleTarget{IP: "10.0.0.1", Account: "b@example.com"}
+	if wait := svc.Allow(ctx, ThrottleLogin, other); wait != 0 {
+		t.Fatalf("expected other account to be allowed, got %s", wait)
+	}
+
+	// Once lockout and window pass, the account starts fresh.
+	clock.advance(15 * time.Minute)
+	if wait := svc.Allow(ctx, ThrottleLogin, target); wait != 0 {
+		t.Fatalf("expected lockout to expire, got %s", wait)
+	}
+	svc.Fail(ctx, ThrottleLogin, target)
+	if wait := svc.Allow(ctx, ThrottleLogin, target); wait != 0 {
+		t.Fatalf("expected fresh window, got %s", wait)
+	}
+}
+
+func TestThrottleService_IPLimitSpansAccounts(t *testing.T) {
+	rule := testLoginRule
+	rule.IPLimit = 3
+	svc, _ := newTestThrottle(t, rule, &fakeLockoutNotifier{})
+	ctx := context.Background()
+
+	for _, account := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		svc.Fail(ctx, ThrottleLogin, ThrottleTarget{IP: "10.0.0.9", Account: account})
+	}
+	if wait := svc.Allow(ctx, ThrottleLogin, ThrottleTarget{IP: "10.0.0.9", Account: "d@example.com"}); wait != rule.Lockout {
+		t.Fatalf("expected IP lockout, got %s", wait)
+	}
+	if wait := svc.Allow(ctx, ThrottleLogin, ThrottleTarget{IP: "10.0.0.10", Account: "d@example.com"}); wait != 0 {
+		t.Fatalf("expected other IP to be allowed, got %s", wait)
+	}
+}
+
+func TestThrottleService_SucceedResetsAccount(t *testing.T) {
+	svc, _ := newTestThrottle(t, testLoginRule, nil)
+	ctx := context.Background()
+	target := ThrottleTarget{IP: "10.0.0.1", Account: "a@example.com"}
+
+	for i := 0; i < 2; i++ {
+		svc.Fail(ctx, ThrottleLogin, target)
+	}
+	svc.Succeed(ctx, ThrottleLogin, target)
+	svc.Fail(ctx, ThrottleLogin, target)
+	if wait := svc.Allow(ctx, ThrottleLogin, target); wait != 0 {
+		t.Fatalf("expected counter reset after success, got %s", wait)
+	}
+}
+
+func TestThrottleService_Hit(t *testing.T) {
+	svc, _ := newTestThrottle(t, ThrottleRule{IPLimit: 3, FreeAttempts: 2, Window: time.Hour, Backoff: time.Minute, Lockout: time.Hour}, nil)
+	ctx := context.Background()
+	target := ThrottleTarget{IP: "10.0.0.1"}
+
+	if wait := svc.Hit(ctx, ThrottleLogin, target); wait != 0 {
+		t.Fatalf("expected first hit allowed, got %s", wait)
+	}
+	if wait := svc.Hit(ctx, ThrottleLogin, target); wait != 0 {
+		t.Fatalf("expected second hit allowed, got %s", wait)
+	}
+	if wait := svc.Hit(ctx, ThrottleLogin, target); wait != 0 {
+		t.Fatalf("expected third hit allowed, got %s", wait)
+	}
+	if wait := svc.Hit(ctx, ThrottleLogin, target); wait != time.Hour {
+		t.Fatalf("expected fourth hit blocked, got %s", wait)
+	}
+}
+
+func TestThrottleService_FailsOpen(t *testing.T) {
+	svc, clock := newTestThrottle(t, testLoginRule, nil)
+	clock.mr.Close()
+	ctx := context.Background()
+	target := ThrottleTarget{IP: "10.0.0.1", Account: "a@example.com"}
+
+	svc.Fail(ctx, ThrottleLogin, target)
+	if wait := svc.Allow(ctx, ThrottleLogin, target); wait != 0 {
+		t.Fatalf("expected store errors to fail open, got %s", wait)
+	}
+	if wait := svc.Allow(ctx, "unknown", target); wait != 0 {
+		t.Fatalf("expected unknown route to be allowed, got %s", wait)
+	}
+}
+
+func TestRedisThrottleStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	store := NewRedisThrottleStore(client)
+	ctx := context.Background()
+
+	// Only the first increment sets the expiry, so the window doesn't slide.
+	for want := int64(1); want <= 2; want++ {
+		got, err := store.Incr(ctx, "k", time.Minute)
+		if err != nil || got != want {
+			t.Fatalf("incr %d: got %d, %v", want, got, err)
+		}
+		mr.FastForward(10 * time.Second)
+	}
+	if ttl, err := store.TTL(ctx, "k"); err != nil || ttl != 40*time.Second {
+		t.Fatalf("expected 40s left, got %s, %v", ttl, err)
+	}
+	mr.FastForward(40 * time.Second)
+	if got, _ := store.Incr(ctx, "k", time.Minute); got != 1 {
+		t.Fatalf("expected counter to restart after expiry, got %d", got)
+	}
+
+	if err := store.Block(ctx, "b", 5*time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl, _ := store.TTL(ctx, "b"); ttl != 5*time.Minute {
+		t.Fatalf("expected block ttl, got %s", ttl)
+	}
+	if err := store.Del(ctx, "b", "k"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl, err := store.TTL(ctx, "b"); err != nil || ttl != 0 {
+		t.Fatalf("expected missing key to report zero, got %s, %v", ttl, err)
+	}
+}
+
+func TestBackoffDelay_CapsAtLockout(t *testing.T) {
+	rule := ThrottleRule{Backoff: time.Second, Lockout: 5 * time.Second}
+	if got := backoffDelay(rule, 3); got != 4*time.Second {
+		t.Fatalf("expected 4s, got %s", got)
+	}
+	if got := backoffDelay(rule, 10); got != 5*time.Second {
+		t.Fatalf("expected cap at lockout, got %s", got)
+	}
+}
+
+func TestThrottleRulesFromConfig(t *testing.T) {
+	rules, err := ThrottleRulesFromConfig(map[string]string{
+		"login": "account=3, lockout=30m, notify=false",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	login := rules[ThrottleLogin]
+	if login.AccountLimit != 3 || login.Lockout != 30*time.Minute || login.NotifyOnLockout {
+		t.Fatalf("unexpected login rule: %+v", login)
+	}
+	if login.IPLimit != DefaultThrottleRules[ThrottleLogin].IPLimit {
+		t.Fatalf("expected unset fields to keep defaults, got %+v", login)
+	}
+	if rules[ThrottleMagicLink] != DefaultThrottleRules[ThrottleMagicLink] {
+		t.Fatal("expected routes without overrides to use defaults")
+	}
+
+	for spec, want := range map[string]string{
+		"bogus":        "expected key=value",
+		"ip=-1":        "invalid ip",
+		"window=0s":    "invalid window",
+		"speed=fast":   "unknown setting",
+		"notify=maybe": "invalid notify",
+	} {
+		if _, err := ThrottleRulesFromConfig(map[string]string{"login": spec}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error containing %q, got %v", spec, want, err)
+		}
+	}
+	if _, err := ThrottleRulesFromConfig(map[string]string{"signup": "ip=1"}); err == nil {
+		t.Error("expected unknown route error")
+	}
+}
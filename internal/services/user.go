@@ -2,12 +2,16 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/i18n"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
@@ -17,6 +21,20 @@ var (
 	ErrUsernameAlreadyExists = errors.New("username already taken")
 )
 
+// userColumns lists the users columns read by scanUser, in scan order.
+const userColumns = `id, email, password_hash, username, email_verified, email_verified_at, ai_free_generations_used, searchable, public_profile, leaderboard_opt_out, locale, is_admin, disabled_at, created_at, updated_at`
+
+func scanUser(row Row, user *models.User) error {
+	return row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Username, &user.EmailVerified, &user.EmailVerifiedAt, &user.AIFreeGenerationsUsed, &user.Searchable, &user.PublicProfile, &user.LeaderboardOptOut, &user.Locale, &user.IsAdmin, &user.DisabledAt, &user.CreatedAt, &user.UpdatedAt)
+}
+
+// hashEmail returns the hex SHA-256 of a lowercased email. Clients hash their
+// contacts the same way for discovery, so addresses never leave the device.
+func hashEmail(email string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(hash[:])
+}
+
 type UserService struct {
 	db DBConn
 }
@@ -46,12 +64,12 @@ func (s *UserService) Create(ctx context.Context, params models.CreateUserParams
 	}
 
 	user := &models.User{}
-	err = s.db.QueryRow(ctx,
-		`INSERT INTO users (email, password_hash, username, email_verified, searchable)
-		 VALUES ($1, $2, $3, false, $4)
-		 RETURNING id, email, password_hash, username, email_verified, email_verified_at, ai_free_generations_used, searchable, created_at, updated_at`,
-		params.Email, params.PasswordHash, params.Username, params.Searchable,
-	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Username, &user.EmailVerified, &user.EmailVerifiedAt, &user.AIFreeGenerationsUsed, &user.Searchable, &user.CreatedAt, &user.UpdatedAt)
+	err = scanUser(s.db.QueryRow(ctx,
+		`INSERT INTO users (email, password_hash, username, email_verified, searchable, locale, email_hash)
+		 VALUES ($1, $2, $3, false, $4, $5, $6)
+		 RETURNING `+userColumns,
+		params.Email, params.PasswordHash, params.Username, params.Searchable, i18n.Normalize(params.Locale), hashEmail(params.Email),
+	), user)
 
 	if err != nil {
 		return nil, fmt.Errorf("creating user: %w", err)
@@ -62,11 +80,10 @@ func (s *UserService) Create(ctx context.Context, params models.CreateUserParams
 
 func (s *UserService) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	user := &models.User{}
-	err := s.db.QueryRow(ctx,
-		`SELECT id, email, password_hash, username, email_verified, email_verified_at, ai_free_generations_used, searchable, created_at, updated_at
-		 FROM users WHERE id = $1`,
+	err := scanUser(s.db.QueryRow(ctx,
+		`SELECT `+userColumns+` FROM users WHERE id = $1`,
 		id,
-	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Username, &user.EmailVerified, &user.EmailVerifiedAt, &user.AIFreeGenerationsUsed, &user.Searchable, &user.CreatedAt, &user.UpdatedAt)
+	), user)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrUserNotFound
@@ -80,11 +97,10 @@ func (s *UserService) GetByID(ctx context.Context, id uuid.UUID) (*models.User,
 
 func (s *UserService) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	user := &models.User{}
-	err := s.db.QueryRow(ctx,
-		`SELECT id, email, password_hash, username, email_verified, email_verified_at, ai_free_generations_used, searchable, created_at, updated_at
-		 FROM users WHERE email = $1`,
+	err := scanUser(s.db.QueryRow(ctx,
+		`SELECT `+userColumns+` FROM users WHERE email = $1`,
 		email,
-	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Username, &user.EmailVerified, &user.EmailVerifiedAt, &user.AIFreeGenerationsUsed, &user.Searchable, &user.CreatedAt, &user.UpdatedAt)
+	), user)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrUserNotFound
@@ -123,6 +139,24 @@ func (s *UserService) MarkEmailVerified(ctx context.Context, userID uuid.UUID) e
 	return nil
 }
 
+// SetAdmin grants or removes access to the admin API. There is no endpoint
+// for it; operators call it from bingoctl.
+func (s *UserService) SetAdmin(ctx context.Context, userID uuid.UUID, isAdmin bool) error {
+	result, err := s.db.Exec(ctx,
+		`UPDATE users SET is_admin = $1, updated_at = NOW() WHERE id = $2`,
+		isAdmin, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating admin flag: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (s *UserService) UpdateSearchable(ctx context.Context, userID uuid.UUID, searchable bool) error {
 	result, err := s.db.Exec(ctx,
 		`UPDATE users SET searchable = $1, updated_at = NOW() WHERE id = $2`,
@@ -138,3 +172,39 @@ func (s *UserService) UpdateSearchable(ctx context.Context, userID uuid.UUID, se
 
 	return nil
 }
+
+// UpdatePublicProfile turns the user's public profile page on or off. Cards
+// only appear there when their visibility is also public.
+func (s *UserService) UpdatePublicProfile(ctx context.Context, userID uuid.UUID, public bool) error {
+	result, err := s.db.Exec(ctx,
+		`UPDATE users SET public_profile = $1, updated_at = NOW() WHERE id = $2`,
+		public, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating public profile: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateLeaderboardOptOut hides the user from (or returns them to) their
+// friends' leaderboards.
+func (s *UserService) UpdateLeaderboardOptOut(ctx context.Context, userID uuid.UUID, optOut bool) error {
+	result, err := s.db.Exec(ctx,
+		`UPDATE users SET leaderboard_opt_out = $1, updated_at = NOW() WHERE id = $2`,
+		optOut, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating leaderboard opt-out: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
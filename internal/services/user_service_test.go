@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -256,6 +257,72 @@ func TestUserService_UpdateSearchable_ExecError(t *testing.T) {
 	}
 }
 
+func TestUserService_SetAdmin(t *testing.T) {
+	userID := uuid.New()
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "is_admin = $1") || args[0] != true || args[1] != userID {
+				t.Fatalf("unexpected exec: %s %v", sql, args)
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	service := NewUserService(db)
+	if err := service.SetAdmin(context.Background(), userID, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUserService_SetAdmin_NotFound(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 0}, nil
+		},
+	}
+
+	service := NewUserService(db)
+	err := service.SetAdmin(context.Background(), uuid.New(), true)
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestUserService_UpdatePublicProfile(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "public_profile") {
+				t.Fatalf("unexpected sql: %q", sql)
+			}
+			gotArgs = args
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	userID := uuid.New()
+	service := NewUserService(db)
+	if err := service.UpdatePublicProfile(context.Background(), userID, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotArgs) != 2 || gotArgs[0] != true || gotArgs[1] != userID {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestUserService_UpdatePublicProfile_NotFound(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 0}, nil
+		},
+	}
+
+	service := NewUserService(db)
+	if err := service.UpdatePublicProfile(context.Background(), uuid.New(), true); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
 func TestUserService_Create_Success(t *testing.T) {
 	call := 0
 	now := time.Now()
@@ -269,6 +336,9 @@ func TestUserService_Create_Success(t *testing.T) {
 			case 2:
 				return rowFromValues(false)
 			default:
+				if args[5] != hashEmail("test@example.com") {
+					t.Fatalf("expected email hash to be stored, got %v", args[5])
+				}
 				return rowFromValues(
 					userID,
 					"test@example.com",
@@ -278,6 +348,11 @@ func TestUserService_Create_Success(t *testing.T) {
 					nil,
 					0,
 					true,
+					false,
+					false,
+					"en",
+					false,
+					nil,
 					now,
 					now,
 				)
@@ -314,6 +389,11 @@ func TestUserService_GetByID_Success(t *testing.T) {
 				nil,
 				0,
 				true,
+				false,
+				false,
+				"en",
+				false,
+				nil,
 				now,
 				now,
 			)
@@ -344,6 +424,11 @@ func TestUserService_GetByEmail_Success(t *testing.T) {
 				nil,
 				2,
 				false,
+				false,
+				false,
+				"en",
+				false,
+				nil,
 				now,
 				now,
 			)
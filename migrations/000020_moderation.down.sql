@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reports;
+
+ALTER TABLE bingo_items
+DROP COLUMN IF EXISTS hidden_at;
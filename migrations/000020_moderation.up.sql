@@ -0,0 +1,25 @@
+ALTER TABLE bingo_items
+ADD COLUMN hidden_at TIMESTAMPTZ;
+
+CREATE TABLE reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type TEXT NOT NULL CHECK (target_type IN ('user', 'card', 'item')),
+    target_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    card_id UUID REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    item_id UUID REFERENCES bingo_items(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL CHECK (reason IN ('spam', 'harassment', 'hate', 'sexual', 'self_harm', 'other')),
+    details TEXT,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'actioned', 'dismissed')),
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    resolution_note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One open report per reporter and target.
+CREATE UNIQUE INDEX idx_reports_open_unique
+    ON reports(reporter_id, target_type, COALESCE(item_id, card_id, target_user_id))
+    WHERE status = 'open';
+CREATE INDEX idx_reports_status_created ON reports(status, created_at);
+CREATE INDEX idx_reports_target_user ON reports(target_user_id);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS account_events_no_update ON account_events;
+DROP FUNCTION IF EXISTS account_events_reject_update();
+DROP TABLE IF EXISTS account_events;
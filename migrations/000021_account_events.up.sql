@@ -0,0 +1,23 @@
+CREATE TABLE account_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    outcome TEXT NOT NULL CHECK (outcome IN ('success', 'failure')),
+    ip_address TEXT,
+    user_agent TEXT,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_account_events_user_created ON account_events(user_id, created_at DESC);
+
+-- Events are append-only. Rows still go away with their user via the cascade.
+CREATE FUNCTION account_events_reject_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'account_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER account_events_no_update
+    BEFORE UPDATE ON account_events
+    FOR EACH ROW EXECUTE FUNCTION account_events_reject_update();
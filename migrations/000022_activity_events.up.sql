@@ -0,0 +1,24 @@
+-- Friend activity feed. Rows are written by the card and reaction services;
+-- visibility, archiving and blocks are applied when the feed is read so that
+-- later changes take effect on existing events.
+CREATE TABLE activity_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL CHECK (event_type IN ('item_completed', 'bingo', 'card_finalized', 'reaction')),
+    card_id UUID NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    item_id UUID REFERENCES bingo_items(id) ON DELETE CASCADE,
+    bingo_count INT,
+    emoji TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_activity_events_actor_created ON activity_events(actor_id, created_at DESC, id DESC);
+
+CREATE UNIQUE INDEX idx_activity_events_item_completed ON activity_events(item_id)
+    WHERE event_type = 'item_completed';
+CREATE UNIQUE INDEX idx_activity_events_bingo ON activity_events(card_id, bingo_count)
+    WHERE event_type = 'bingo';
+CREATE UNIQUE INDEX idx_activity_events_card_finalized ON activity_events(card_id)
+    WHERE event_type = 'card_finalized';
+CREATE UNIQUE INDEX idx_activity_events_reaction ON activity_events(item_id, actor_id)
+    WHERE event_type = 'reaction';
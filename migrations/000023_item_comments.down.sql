@@ -0,0 +1,12 @@
+ALTER TABLE notification_settings
+    DROP COLUMN IF EXISTS in_app_item_comment,
+    DROP COLUMN IF EXISTS email_item_comment;
+
+DELETE FROM notifications WHERE type = 'item_comment';
+DROP INDEX IF EXISTS idx_notifications_item_comment;
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card'));
+ALTER TABLE notifications DROP COLUMN IF EXISTS comment_id;
+
+DROP TABLE IF EXISTS item_comments;
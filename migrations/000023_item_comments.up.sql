@@ -0,0 +1,25 @@
+-- Comment threads on bingo items. Replies point at the top-level comment
+-- they answer, so threads are one level deep.
+CREATE TABLE item_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id UUID NOT NULL REFERENCES bingo_items(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES item_comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 1000),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    edited_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_item_comments_item_created ON item_comments(item_id, created_at);
+CREATE INDEX idx_item_comments_parent ON item_comments(parent_id) WHERE parent_id IS NOT NULL;
+
+ALTER TABLE notifications ADD COLUMN comment_id UUID REFERENCES item_comments(id) ON DELETE CASCADE;
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'item_comment'));
+CREATE UNIQUE INDEX idx_notifications_item_comment ON notifications(user_id, comment_id)
+    WHERE type = 'item_comment';
+
+ALTER TABLE notification_settings
+    ADD COLUMN in_app_item_comment BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN email_item_comment BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_bingo_cards_visibility;
+ALTER TABLE bingo_cards DROP COLUMN visible_to_friends;
+ALTER TABLE bingo_cards ADD COLUMN visible_to_friends BOOLEAN NOT NULL DEFAULT true;
+UPDATE bingo_cards SET visible_to_friends = visibility IN ('friends', 'link');
+CREATE INDEX idx_bingo_cards_visibility ON bingo_cards(user_id, is_finalized, visible_to_friends);
+
+ALTER TABLE bingo_cards DROP COLUMN IF EXISTS share_token;
+ALTER TABLE bingo_cards DROP COLUMN IF EXISTS visibility;
+
+DROP TABLE IF EXISTS card_group_shares;
+DROP TABLE IF EXISTS friend_group_members;
+DROP TABLE IF EXISTS friend_groups;
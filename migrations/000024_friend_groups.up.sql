@@ -0,0 +1,41 @@
+-- User-defined friend groups, used to share cards with some friends only.
+CREATE TABLE friend_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_friend_groups_owner_name ON friend_groups(owner_id, LOWER(name));
+
+CREATE TABLE friend_group_members (
+    group_id UUID NOT NULL REFERENCES friend_groups(id) ON DELETE CASCADE,
+    member_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, member_id)
+);
+
+CREATE INDEX idx_friend_group_members_member ON friend_group_members(member_id);
+
+CREATE TABLE card_group_shares (
+    card_id UUID NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    group_id UUID NOT NULL REFERENCES friend_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (card_id, group_id)
+);
+
+CREATE INDEX idx_card_group_shares_group ON card_group_shares(group_id);
+
+-- visibility replaces the visible_to_friends flag. The flag is kept as a
+-- generated column meaning "every friend can see this card" so existing
+-- readers keep working.
+ALTER TABLE bingo_cards ADD COLUMN visibility TEXT NOT NULL DEFAULT 'friends'
+    CHECK (visibility IN ('private', 'friends', 'groups', 'link'));
+UPDATE bingo_cards SET visibility = CASE WHEN visible_to_friends THEN 'friends' ELSE 'private' END;
+
+ALTER TABLE bingo_cards ADD COLUMN share_token TEXT UNIQUE;
+
+DROP INDEX IF EXISTS idx_bingo_cards_visibility;
+ALTER TABLE bingo_cards DROP COLUMN visible_to_friends;
+ALTER TABLE bingo_cards ADD COLUMN visible_to_friends BOOLEAN
+    GENERATED ALWAYS AS (visibility IN ('friends', 'link')) STORED;
+CREATE INDEX idx_bingo_cards_visibility ON bingo_cards(user_id, is_finalized, visibility);
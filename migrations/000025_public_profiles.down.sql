@@ -0,0 +1,13 @@
+UPDATE bingo_cards SET visibility = 'friends' WHERE visibility = 'public';
+
+DROP INDEX IF EXISTS idx_bingo_cards_visibility;
+ALTER TABLE bingo_cards DROP COLUMN visible_to_friends;
+ALTER TABLE bingo_cards ADD COLUMN visible_to_friends BOOLEAN
+    GENERATED ALWAYS AS (visibility IN ('friends', 'link')) STORED;
+CREATE INDEX idx_bingo_cards_visibility ON bingo_cards(user_id, is_finalized, visibility);
+
+ALTER TABLE bingo_cards DROP CONSTRAINT IF EXISTS bingo_cards_visibility_check;
+ALTER TABLE bingo_cards ADD CONSTRAINT bingo_cards_visibility_check
+    CHECK (visibility IN ('private', 'friends', 'groups', 'link'));
+
+ALTER TABLE users DROP COLUMN IF EXISTS public_profile;
@@ -0,0 +1,14 @@
+-- Opt-in public profile pages at /u/{username}.
+ALTER TABLE users ADD COLUMN public_profile BOOLEAN NOT NULL DEFAULT false;
+
+-- Cards gain a "public" visibility: shown on the owner's public profile and
+-- to all friends. The generated flag has to be rebuilt to include it.
+ALTER TABLE bingo_cards DROP CONSTRAINT IF EXISTS bingo_cards_visibility_check;
+ALTER TABLE bingo_cards ADD CONSTRAINT bingo_cards_visibility_check
+    CHECK (visibility IN ('private', 'friends', 'groups', 'link', 'public'));
+
+DROP INDEX IF EXISTS idx_bingo_cards_visibility;
+ALTER TABLE bingo_cards DROP COLUMN visible_to_friends;
+ALTER TABLE bingo_cards ADD COLUMN visible_to_friends BOOLEAN
+    GENERATED ALWAYS AS (visibility IN ('friends', 'link', 'public')) STORED;
+CREATE INDEX idx_bingo_cards_visibility ON bingo_cards(user_id, is_finalized, visibility);
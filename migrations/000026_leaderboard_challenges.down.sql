@@ -0,0 +1,15 @@
+ALTER TABLE notification_settings
+    DROP COLUMN IF EXISTS in_app_challenge_result,
+    DROP COLUMN IF EXISTS email_challenge_result;
+
+DELETE FROM notifications WHERE type = 'challenge_result';
+DROP INDEX IF EXISTS idx_notifications_challenge_result;
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'item_comment'));
+ALTER TABLE notifications DROP COLUMN IF EXISTS challenge_id;
+
+DROP TABLE IF EXISTS challenge_participants;
+DROP TABLE IF EXISTS challenges;
+
+ALTER TABLE users DROP COLUMN IF EXISTS leaderboard_opt_out;
@@ -0,0 +1,38 @@
+-- Friends leaderboard opt-out and time-boxed challenges between friends.
+ALTER TABLE users ADD COLUMN leaderboard_opt_out BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    creator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL CHECK (char_length(name) > 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    announced_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_challenges_creator ON challenges(creator_id);
+CREATE INDEX idx_challenges_unannounced ON challenges(ends_at) WHERE announced_at IS NULL;
+
+-- Invited friends have a NULL joined_at until they accept.
+CREATE TABLE challenge_participants (
+    challenge_id UUID NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (challenge_id, user_id)
+);
+
+CREATE INDEX idx_challenge_participants_user ON challenge_participants(user_id);
+
+ALTER TABLE notifications ADD COLUMN challenge_id UUID REFERENCES challenges(id) ON DELETE CASCADE;
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'item_comment', 'challenge_result'));
+CREATE UNIQUE INDEX idx_notifications_challenge_result ON notifications(user_id, challenge_id)
+    WHERE type = 'challenge_result';
+
+ALTER TABLE notification_settings
+    ADD COLUMN in_app_challenge_result BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN email_challenge_result BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_email_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS email_hash;
+
+DROP INDEX IF EXISTS idx_users_username_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
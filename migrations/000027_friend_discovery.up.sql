@@ -0,0 +1,14 @@
+-- Trigram index for fuzzy username search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_users_username_trgm ON users USING gin (LOWER(username) gin_trgm_ops);
+
+-- SHA-256 (hex) of the lowercased email, matched against hashes uploaded by
+-- clients for contact discovery. Only verified addresses are indexed.
+ALTER TABLE users ADD COLUMN email_hash VARCHAR(64);
+
+UPDATE users SET email_hash = encode(sha256(convert_to(LOWER(email), 'UTF8')), 'hex');
+
+ALTER TABLE users ALTER COLUMN email_hash SET NOT NULL;
+
+CREATE INDEX idx_users_email_hash ON users(email_hash) WHERE email_verified = true;
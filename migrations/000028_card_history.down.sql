@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS card_trash;
+DROP TABLE IF EXISTS card_revisions;
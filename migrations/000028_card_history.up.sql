@@ -0,0 +1,30 @@
+-- Revision log for draft card edits, and a trash for deleted cards.
+CREATE TABLE card_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    card_id UUID NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN (
+        'add_item', 'update_item', 'remove_item', 'swap_items', 'shuffle',
+        'update_config', 'update_meta', 'restore'
+    )),
+    before_state JSONB NOT NULL,
+    after_state JSONB NOT NULL,
+    undone_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_revisions_card ON card_revisions(card_id, created_at DESC);
+
+-- Deleted cards are removed from bingo_cards and kept here as a snapshot
+-- until they are restored or purged.
+CREATE TABLE card_trash (
+    card_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    title VARCHAR(100),
+    snapshot JSONB NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_trash_user ON card_trash(user_id, deleted_at DESC);
+CREATE INDEX idx_card_trash_deleted ON card_trash(deleted_at);
@@ -0,0 +1,14 @@
+DELETE FROM reports WHERE target_type = 'template';
+
+DROP INDEX idx_reports_open_unique;
+CREATE UNIQUE INDEX idx_reports_open_unique
+    ON reports(reporter_id, target_type, COALESCE(item_id, card_id, target_user_id))
+    WHERE status = 'open';
+
+ALTER TABLE reports DROP CONSTRAINT reports_target_type_check;
+ALTER TABLE reports ADD CONSTRAINT reports_target_type_check
+    CHECK (target_type IN ('user', 'card', 'item'));
+
+ALTER TABLE reports DROP COLUMN template_id;
+
+DROP TABLE IF EXISTS card_templates;
@@ -0,0 +1,39 @@
+-- Cards published as reusable templates. Templates are copies, so editing or
+-- deleting the source card does not change them.
+CREATE TABLE card_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_card_id UUID REFERENCES bingo_cards(id) ON DELETE SET NULL,
+    title VARCHAR(100) NOT NULL,
+    description VARCHAR(500),
+    category VARCHAR(50),
+    grid_size INT NOT NULL CHECK (grid_size IN (2, 3, 4, 5)),
+    header_text VARCHAR(5) NOT NULL,
+    has_free_space BOOLEAN NOT NULL,
+    free_space_position INT,
+    -- [{"position": 0, "content": "..."}], in position order
+    items JSONB NOT NULL,
+    visibility TEXT NOT NULL DEFAULT 'unlisted' CHECK (visibility IN ('public', 'unlisted')),
+    use_count INT NOT NULL DEFAULT 0,
+    hidden_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_templates_owner ON card_templates(owner_id, created_at DESC);
+CREATE INDEX idx_card_templates_public_popular ON card_templates(use_count DESC, created_at DESC)
+    WHERE visibility = 'public' AND hidden_at IS NULL;
+CREATE INDEX idx_card_templates_title_trgm ON card_templates USING gin (LOWER(title) gin_trgm_ops);
+
+-- Templates can be reported like cards and items.
+ALTER TABLE reports
+ADD COLUMN template_id UUID REFERENCES card_templates(id) ON DELETE CASCADE;
+
+ALTER TABLE reports DROP CONSTRAINT reports_target_type_check;
+ALTER TABLE reports ADD CONSTRAINT reports_target_type_check
+    CHECK (target_type IN ('user', 'card', 'item', 'template'));
+
+DROP INDEX idx_reports_open_unique;
+CREATE UNIQUE INDEX idx_reports_open_unique
+    ON reports(reporter_id, target_type, COALESCE(template_id, item_id, card_id, target_user_id))
+    WHERE status = 'open';
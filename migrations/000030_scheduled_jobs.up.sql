@@ -0,0 +1,14 @@
+-- One row per scheduled job. last_scheduled_at is the cron tick a replica
+-- claimed; a tick is only run once across all replicas.
+CREATE TABLE scheduled_jobs (
+    name VARCHAR(100) PRIMARY KEY,
+    schedule VARCHAR(100) NOT NULL,
+    last_scheduled_at TIMESTAMPTZ,
+    last_started_at TIMESTAMPTZ,
+    last_finished_at TIMESTAMPTZ,
+    last_duration_ms BIGINT,
+    last_error TEXT,
+    last_success_at TIMESTAMPTZ,
+    last_instance VARCHAR(255),
+    run_count BIGINT NOT NULL DEFAULT 0
+);
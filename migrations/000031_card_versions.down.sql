@@ -0,0 +1 @@
+ALTER TABLE bingo_cards DROP COLUMN IF EXISTS version;
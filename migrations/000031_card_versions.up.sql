@@ -0,0 +1,3 @@
+-- Incremented by every card or item edit made through the card service, so
+-- clients can send the version they edited and learn of concurrent changes.
+ALTER TABLE bingo_cards ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,35 @@
+ALTER TABLE notification_settings
+    DROP COLUMN IF EXISTS in_app_org_template,
+    DROP COLUMN IF EXISTS email_org_template;
+
+DELETE FROM notifications WHERE type = 'org_template';
+DROP INDEX IF EXISTS idx_notifications_org_template;
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'item_comment', 'challenge_result'));
+ALTER TABLE notifications DROP COLUMN IF EXISTS template_id;
+
+DELETE FROM card_templates WHERE visibility = 'organization';
+DROP INDEX IF EXISTS idx_card_templates_org;
+ALTER TABLE card_templates DROP CONSTRAINT IF EXISTS card_templates_org_check;
+ALTER TABLE card_templates DROP CONSTRAINT IF EXISTS card_templates_visibility_check;
+ALTER TABLE card_templates ADD CONSTRAINT card_templates_visibility_check
+    CHECK (visibility IN ('public', 'unlisted'));
+ALTER TABLE card_templates DROP COLUMN IF EXISTS org_id;
+
+UPDATE bingo_cards SET visibility = 'friends' WHERE visibility = 'organization';
+
+DROP INDEX IF EXISTS idx_bingo_cards_visibility;
+ALTER TABLE bingo_cards DROP COLUMN visible_to_friends;
+ALTER TABLE bingo_cards ADD COLUMN visible_to_friends BOOLEAN
+    GENERATED ALWAYS AS (visibility IN ('friends', 'link', 'public')) STORED;
+CREATE INDEX idx_bingo_cards_visibility ON bingo_cards(user_id, is_finalized, visibility);
+
+ALTER TABLE bingo_cards DROP CONSTRAINT IF EXISTS bingo_cards_visibility_check;
+ALTER TABLE bingo_cards ADD CONSTRAINT bingo_cards_visibility_check
+    CHECK (visibility IN ('private', 'friends', 'groups', 'link', 'public'));
+
+DROP TABLE IF EXISTS organization_domains;
+DROP TABLE IF EXISTS organization_invites;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,80 @@
+-- Organizations group users beyond friendships, e.g. a workplace running a
+-- bingo program. Admins manage membership; members join by invite link or
+-- by having a verified email at one of the organization's domains.
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL CHECK (char_length(name) > 0),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE organization_members (
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'member')),
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user ON organization_members(user_id);
+
+-- Invite links can be used by any number of people until they expire or
+-- are revoked. Only the SHA-256 of the token is stored.
+CREATE TABLE organization_invites (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    use_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_organization_invites_org ON organization_invites(org_id, created_at DESC);
+
+-- A domain belongs to at most one organization, so joining by domain is
+-- never ambiguous.
+CREATE TABLE organization_domains (
+    domain VARCHAR(253) PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    added_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_organization_domains_org ON organization_domains(org_id);
+
+-- Cards gain an "organization" visibility: every friend plus everyone who
+-- shares an organization with the owner. The generated flag is rebuilt to
+-- include it.
+ALTER TABLE bingo_cards DROP CONSTRAINT IF EXISTS bingo_cards_visibility_check;
+ALTER TABLE bingo_cards ADD CONSTRAINT bingo_cards_visibility_check
+    CHECK (visibility IN ('private', 'friends', 'groups', 'organization', 'link', 'public'));
+
+DROP INDEX IF EXISTS idx_bingo_cards_visibility;
+ALTER TABLE bingo_cards DROP COLUMN visible_to_friends;
+ALTER TABLE bingo_cards ADD COLUMN visible_to_friends BOOLEAN
+    GENERATED ALWAYS AS (visibility IN ('friends', 'organization', 'link', 'public')) STORED;
+CREATE INDEX idx_bingo_cards_visibility ON bingo_cards(user_id, is_finalized, visibility);
+
+-- Organization templates are offered to the organization's members only.
+ALTER TABLE card_templates ADD COLUMN org_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE card_templates DROP CONSTRAINT IF EXISTS card_templates_visibility_check;
+ALTER TABLE card_templates ADD CONSTRAINT card_templates_visibility_check
+    CHECK (visibility IN ('public', 'unlisted', 'organization'));
+ALTER TABLE card_templates ADD CONSTRAINT card_templates_org_check
+    CHECK ((visibility = 'organization') = (org_id IS NOT NULL));
+CREATE INDEX idx_card_templates_org ON card_templates(org_id, created_at DESC) WHERE org_id IS NOT NULL;
+
+-- Members are told when an admin publishes an organization template.
+ALTER TABLE notifications ADD COLUMN template_id UUID REFERENCES card_templates(id) ON DELETE CASCADE;
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'item_comment', 'challenge_result', 'org_template'));
+CREATE UNIQUE INDEX idx_notifications_org_template ON notifications(user_id, template_id)
+    WHERE type = 'org_template';
+
+ALTER TABLE notification_settings
+    ADD COLUMN in_app_org_template BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN email_org_template BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS calendar_feeds;
+
+ALTER TABLE bingo_items DROP CONSTRAINT IF EXISTS bingo_items_reminder_check;
+ALTER TABLE bingo_items DROP COLUMN IF EXISTS reminder_days_before;
+ALTER TABLE bingo_items DROP COLUMN IF EXISTS due_date;
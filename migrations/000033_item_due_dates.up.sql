@@ -0,0 +1,15 @@
+-- Optional due dates on goals, with a reminder some days before. They are
+-- only surfaced through the calendar feed.
+ALTER TABLE bingo_items ADD COLUMN due_date DATE;
+ALTER TABLE bingo_items ADD COLUMN reminder_days_before SMALLINT;
+ALTER TABLE bingo_items ADD CONSTRAINT bingo_items_reminder_check
+    CHECK (reminder_days_before IS NULL OR (due_date IS NOT NULL AND reminder_days_before BETWEEN 0 AND 30));
+
+-- One secret iCalendar feed per user. Only the token hash is stored;
+-- creating a new feed replaces the old token.
+CREATE TABLE calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
@@ -166,6 +166,14 @@ const API = {
     async updateSearchable(searchable) {
       return API.request('PUT', '/api/auth/searchable', { searchable });
     },
+
+    async updatePublicProfile(publicProfile) {
+      return API.request('PUT', '/api/auth/public-profile', { public_profile: publicProfile });
+    },
+
+    async updateLeaderboardOptOut(optOut) {
+      return API.request('PUT', '/api/auth/leaderboard-opt-out', { leaderboard_opt_out: optOut });
+    },
   },
 
   // Card endpoints
@@ -249,6 +257,23 @@ const API = {
       });
     },
 
+    // visibility is one of private, friends, groups, organization, link or public;
+    // groupIds are required for groups.
+    async setVisibility(cardId, visibility, groupIds = []) {
+      return API.request('PUT', `/api/cards/${cardId}/visibility`, {
+        visibility,
+        group_ids: groupIds,
+      });
+    },
+
+    async getSharing(cardId) {
+      return API.request('GET', `/api/cards/${cardId}/sharing`);
+    },
+
+    async getShared(token) {
+      return API.request('GET', `/api/shared/${encodeURIComponent(token)}`);
+    },
+
     async bulkUpdateVisibility(cardIds, visibleToFriends) {
       return API.request('PUT', '/api/cards/visibility/bulk', {
         card_ids: cardIds,
@@ -256,6 +281,14 @@ const API = {
       });
     },
 
+    async bulkSetVisibility(cardIds, visibility, groupIds = []) {
+      return API.request('PUT', '/api/cards/visibility/bulk', {
+        card_ids: cardIds,
+        visibility,
+        group_ids: groupIds,
+      });
+    },
+
     async bulkDelete(cardIds) {
       return API.request('DELETE', '/api/cards/bulk', {
         card_ids: cardIds,
@@ -269,6 +302,26 @@ const API = {
       });
     },
 
+    async history(cardId) {
+      return API.request('GET', `/api/cards/${cardId}/history`);
+    },
+
+    async undo(cardId) {
+      return API.request('POST', `/api/cards/${cardId}/undo`);
+    },
+
+    async restoreRevision(cardId, revisionId) {
+      return API.request('POST', `/api/cards/${cardId}/history/${revisionId}/restore`);
+    },
+
+    async trash() {
+      return API.request('GET', '/api/cards/trash');
+    },
+
+    async restoreFromTrash(cardId) {
+      return API.request('POST', `/api/cards/trash/${cardId}/restore`);
+    },
+
     async completeItem(cardId, position, notes = null, proofUrl = null) {
       const body = {};
       if (notes) body.notes = notes;
@@ -287,6 +340,14 @@ const API = {
       });
     },
 
+    // Pass a null dueDate to clear both the due date and the reminder.
+    async setDueDate(cardId, position, dueDate, reminderDaysBefore = null) {
+      return API.request('PUT', `/api/cards/${cardId}/items/${position}/due`, {
+        due_date: dueDate,
+        reminder_days_before: reminderDaysBefore,
+      });
+    },
+
     async getArchive() {
       return API.request('GET', '/api/cards/archive');
     },
@@ -323,6 +384,49 @@ const API = {
     },
   },
 
+  // Template library endpoints (browsing needs no session)
+  templates: {
+    async list(params = {}) {
+      const query = new URLSearchParams();
+      Object.entries(params).forEach(([key, value]) => {
+        if (value !== undefined && value !== null && value !== '') query.set(key, value);
+      });
+      const qs = query.toString();
+      return API.request('GET', `/api/templates${qs ? `?${qs}` : ''}`);
+    },
+
+    async mine() {
+      return API.request('GET', '/api/templates/mine');
+    },
+
+    async get(templateId) {
+      return API.request('GET', `/api/templates/${templateId}`);
+    },
+
+    async publish(cardId, options = {}) {
+      const body = { card_id: cardId };
+      if (options.title) body.title = options.title;
+      if (options.description) body.description = options.description;
+      if (options.visibility) body.visibility = options.visibility;
+      if (options.org_id) body.org_id = options.org_id;
+      return API.request('POST', '/api/templates', body);
+    },
+
+    async update(templateId, fields) {
+      return API.request('PUT', `/api/templates/${templateId}`, fields);
+    },
+
+    async remove(templateId) {
+      return API.request('DELETE', `/api/templates/${templateId}`);
+    },
+
+    async use(templateId, year, title = null) {
+      const body = { year };
+      if (title) body.title = title;
+      return API.request('POST', `/api/templates/${templateId}/use`, body);
+    },
+  },
+
   // Friend endpoints
   friends: {
     async list() {
@@ -333,6 +437,14 @@ const API = {
       return API.request('GET', `/api/friends/search?q=${encodeURIComponent(query)}`);
     },
 
+    async suggestions() {
+      return API.request('GET', '/api/friends/suggestions');
+    },
+
+    async discover(emailHashes) {
+      return API.request('POST', '/api/friends/discover', { email_hashes: emailHashes });
+    },
+
     async sendRequest(friendId) {
       return API.request('POST', '/api/friends/requests', { friend_id: friendId });
     },
@@ -349,6 +461,11 @@ const API = {
       return API.request('DELETE', `/api/friends/${friendshipId}`);
     },
 
+    async leaderboard(year) {
+      const query = year ? `?year=${encodeURIComponent(year)}` : '';
+      return API.request('GET', `/api/friends/leaderboard${query}`);
+    },
+
     async cancelRequest(friendshipId) {
       return API.request('DELETE', `/api/friends/requests/${friendshipId}/cancel`);
     },
@@ -450,6 +567,176 @@ const API = {
     },
   },
 
+  // Comment endpoints
+  comments: {
+    async list(itemId) {
+      return API.request('GET', `/api/items/${itemId}/comments`);
+    },
+
+    async create(itemId, body, parentId = null) {
+      const payload = { body };
+      if (parentId) {
+        payload.parent_id = parentId;
+      }
+      return API.request('POST', `/api/items/${itemId}/comments`, payload);
+    },
+
+    async update(commentId, body) {
+      return API.request('PUT', `/api/comments/${commentId}`, { body });
+    },
+
+    async remove(commentId) {
+      return API.request('DELETE', `/api/comments/${commentId}`);
+    },
+  },
+
+  // Public profile endpoints (no auth)
+  profiles: {
+    async get(username) {
+      return API.request('GET', `/api/profiles/${encodeURIComponent(username)}`);
+    },
+  },
+
+  // Friend group endpoints
+  friendGroups: {
+    async list() {
+      return API.request('GET', '/api/friend-groups');
+    },
+
+    async create(name) {
+      return API.request('POST', '/api/friend-groups', { name });
+    },
+
+    async rename(groupId, name) {
+      return API.request('PUT', `/api/friend-groups/${groupId}`, { name });
+    },
+
+    async remove(groupId) {
+      return API.request('DELETE', `/api/friend-groups/${groupId}`);
+    },
+
+    async addMember(groupId, userId) {
+      return API.request('POST', `/api/friend-groups/${groupId}/members`, { user_id: userId });
+    },
+
+    async removeMember(groupId, userId) {
+      return API.request('DELETE', `/api/friend-groups/${groupId}/members/${userId}`);
+    },
+  },
+
+  // Organization endpoints
+  orgs: {
+    async list() {
+      return API.request('GET', '/api/orgs');
+    },
+
+    async create(name) {
+      return API.request('POST', '/api/orgs', { name });
+    },
+
+    async get(orgId) {
+      return API.request('GET', `/api/orgs/${orgId}`);
+    },
+
+    async rename(orgId, name) {
+      return API.request('PUT', `/api/orgs/${orgId}`, { name });
+    },
+
+    async remove(orgId) {
+      return API.request('DELETE', `/api/orgs/${orgId}`);
+    },
+
+    async members(orgId) {
+      return API.request('GET', `/api/orgs/${orgId}/members`);
+    },
+
+    async setRole(orgId, userId, role) {
+      return API.request('PUT', `/api/orgs/${orgId}/members/${userId}`, { role });
+    },
+
+    async removeMember(orgId, userId) {
+      return API.request('DELETE', `/api/orgs/${orgId}/members/${userId}`);
+    },
+
+    async memberCards(orgId, userId) {
+      return API.request('GET', `/api/orgs/${orgId}/members/${userId}/cards`);
+    },
+
+    async invites(orgId) {
+      return API.request('GET', `/api/orgs/${orgId}/invites`);
+    },
+
+    async createInvite(orgId, expiresInDays) {
+      return API.request('POST', `/api/orgs/${orgId}/invites`, {
+        expires_in_days: parseInt(expiresInDays, 10),
+      });
+    },
+
+    async revokeInvite(orgId, inviteId) {
+      return API.request('DELETE', `/api/orgs/${orgId}/invites/${inviteId}`);
+    },
+
+    async acceptInvite(token) {
+      return API.request('POST', '/api/orgs/invites/accept', { token });
+    },
+
+    async addDomain(orgId, domain) {
+      return API.request('POST', `/api/orgs/${orgId}/domains`, { domain });
+    },
+
+    async removeDomain(orgId, domain) {
+      return API.request('DELETE', `/api/orgs/${orgId}/domains/${encodeURIComponent(domain)}`);
+    },
+
+    async joinable() {
+      return API.request('GET', '/api/orgs/joinable');
+    },
+
+    async join(orgId) {
+      return API.request('POST', `/api/orgs/${orgId}/join`);
+    },
+
+    async templates(orgId) {
+      return API.request('GET', `/api/orgs/${orgId}/templates`);
+    },
+
+    async stats(orgId, year) {
+      return API.request('GET', `/api/orgs/${orgId}/stats?year=${encodeURIComponent(year)}`);
+    },
+  },
+
+  // Challenge endpoints
+  challenges: {
+    async list() {
+      return API.request('GET', '/api/challenges');
+    },
+
+    async create(name, startsAt, endsAt, friendIds = []) {
+      return API.request('POST', '/api/challenges', {
+        name,
+        starts_at: startsAt,
+        ends_at: endsAt,
+        friend_ids: friendIds,
+      });
+    },
+
+    async get(challengeId) {
+      return API.request('GET', `/api/challenges/${challengeId}`);
+    },
+
+    async join(challengeId) {
+      return API.request('POST', `/api/challenges/${challengeId}/join`);
+    },
+
+    async leave(challengeId) {
+      return API.request('POST', `/api/challenges/${challengeId}/leave`);
+    },
+
+    async remove(challengeId) {
+      return API.request('DELETE', `/api/challenges/${challengeId}`);
+    },
+  },
+
   // Token endpoints
   tokens: {
     async list() {
@@ -473,6 +760,21 @@ const API = {
     },
   },
 
+  // Calendar feed endpoints
+  calendar: {
+    async getFeed() {
+      return API.request('GET', '/api/calendar/feed');
+    },
+
+    async createFeed() {
+      return API.request('POST', '/api/calendar/feed');
+    },
+
+    async revokeFeed() {
+      return API.request('DELETE', '/api/calendar/feed');
+    },
+  },
+
   // Support endpoint
   support: {
     async submit(email, category, message) {
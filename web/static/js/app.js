@@ -171,15 +171,60 @@ const App = {
         if (cardId) this.toggleCardVisibility(cardId, visible);
         break;
       }
+      case 'toggle-card-public': {
+        const cardId = target.dataset.cardId;
+        const isPublic = target.dataset.public === 'true';
+        if (cardId) this.toggleCardPublic(cardId, isPublic);
+        break;
+      }
+      case 'toggle-card-org': {
+        const cardId = target.dataset.cardId;
+        const shared = target.dataset.org === 'true';
+        if (cardId) this.toggleCardOrg(cardId, shared);
+        break;
+      }
+      case 'switch-public-card':
+        this.switchPublicProfileCard(target.dataset.cardId);
+        break;
       case 'confirm-clear-card-items':
         this.confirmClearCardItems();
         break;
       case 'shuffle-card':
         this.shuffleCard();
         break;
+      case 'undo-card-edit':
+        this.undoCardEdit();
+        break;
+      case 'show-card-history':
+        this.showCardHistory();
+        break;
+      case 'restore-card-revision':
+        this.restoreCardRevision(target.dataset.revisionId);
+        break;
+      case 'show-trash':
+        this.showTrash();
+        break;
+      case 'restore-trashed-card':
+        this.restoreTrashedCard(target.dataset.cardId);
+        break;
       case 'show-clone-card-modal':
         this.showCloneCardModal();
         break;
+      case 'show-publish-template-modal':
+        this.showPublishTemplateModal();
+        break;
+      case 'show-use-template-modal':
+        this.showUseTemplateModal();
+        break;
+      case 'load-more-templates':
+        this.loadTemplates(true);
+        break;
+      case 'toggle-template-visibility':
+        this.toggleTemplateVisibility(target.dataset.templateId, target.dataset.visibility);
+        break;
+      case 'delete-template':
+        this.deleteTemplate(target.dataset.templateId);
+        break;
       case 'finalize-card':
         this.finalizeCard();
         break;
@@ -273,6 +318,53 @@ const App = {
         if (target.dataset.otherUserId) this.blockUser(target.dataset.otherUserId, friendName);
         break;
       }
+      case 'show-create-challenge-modal':
+        this.showCreateChallengeModal();
+        break;
+      case 'join-challenge':
+        if (target.dataset.challengeId) this.joinChallenge(target.dataset.challengeId);
+        break;
+      case 'leave-challenge':
+        if (target.dataset.challengeId) this.leaveChallenge(target.dataset.challengeId);
+        break;
+      case 'delete-challenge':
+        if (target.dataset.challengeId) this.deleteChallenge(target.dataset.challengeId);
+        break;
+      case 'show-create-org-modal':
+        this.showCreateOrgModal();
+        break;
+      case 'join-org':
+        if (target.dataset.orgId) this.joinOrg(target.dataset.orgId);
+        break;
+      case 'rename-org':
+        if (target.dataset.orgId) this.renameOrg(target.dataset.orgId);
+        break;
+      case 'delete-org':
+        if (target.dataset.orgId) this.deleteOrg(target.dataset.orgId);
+        break;
+      case 'leave-org':
+        if (target.dataset.orgId) this.leaveOrg(target.dataset.orgId);
+        break;
+      case 'set-org-role':
+        if (target.dataset.orgId && target.dataset.userId) {
+          this.setOrgRole(target.dataset.orgId, target.dataset.userId, target.dataset.role);
+        }
+        break;
+      case 'remove-org-member':
+        if (target.dataset.orgId && target.dataset.userId) this.removeOrgMember(target.dataset.orgId, target.dataset.userId);
+        break;
+      case 'view-org-member-cards':
+        if (target.dataset.orgId && target.dataset.userId) this.viewOrgMemberCards(target.dataset.orgId, target.dataset.userId);
+        break;
+      case 'create-org-invite':
+        if (target.dataset.orgId) this.createOrgInvite(target.dataset.orgId);
+        break;
+      case 'revoke-org-invite':
+        if (target.dataset.orgId && target.dataset.inviteId) this.revokeOrgInvite(target.dataset.orgId, target.dataset.inviteId);
+        break;
+      case 'remove-org-domain':
+        if (target.dataset.orgId && target.dataset.domain) this.removeOrgDomain(target.dataset.orgId, target.dataset.domain);
+        break;
       case 'unblock-user': {
         const friendName = target.closest('.friend-item')?.querySelector('strong')?.textContent?.trim() || 'this user';
         if (target.dataset.userId) this.unblockUser(target.dataset.userId, friendName);
@@ -304,6 +396,17 @@ const App = {
         this.closeModal();
         this.loadApiTokens();
         break;
+      case 'create-calendar-feed':
+        this.createCalendarFeed();
+        break;
+      case 'revoke-calendar-feed':
+        this.revokeCalendarFeed();
+        break;
+      case 'copy-calendar-feed': {
+        const urlEl = document.getElementById('calendar-feed-url');
+        if (urlEl?.textContent) this.copyToClipboard(urlEl.textContent);
+        break;
+      }
       default:
         break;
     }
@@ -334,6 +437,24 @@ const App = {
       case 'clone-card':
         this.handleCloneCard(event);
         break;
+      case 'search-templates':
+        this.handleSearchTemplates(event);
+        break;
+      case 'publish-template':
+        this.handlePublishTemplate(event);
+        break;
+      case 'use-template':
+        this.handleUseTemplate(event);
+        break;
+      case 'create-challenge':
+        this.handleCreateChallenge(event);
+        break;
+      case 'create-org':
+        this.handleCreateOrg(event);
+        break;
+      case 'add-org-domain':
+        this.handleAddOrgDomain(event, form);
+        break;
       case 'finalize-register':
         this.handleFinalizeRegister(event);
         break;
@@ -520,6 +641,8 @@ const App = {
         <div class="nav-menu">
           <a href="#profile" class="nav-link">Hi, ${this.escapeHtml(this.user.username)}</a>
           <a href="#friends" class="nav-link">Friends</a>
+          <a href="#orgs" class="nav-link">Orgs</a>
+          <a href="#templates" class="nav-link">Templates</a>
           <a href="#notifications" class="nav-link nav-link--notifications">
             <span>Notifications</span>
             <span class="nav-badge nav-badge--hidden" id="notification-badge" aria-hidden="true"></span>
@@ -536,6 +659,7 @@ const App = {
           <span class="hamburger-line"></span>
         </button>
         <div class="nav-menu">
+          <a href="#templates" class="nav-link">Templates</a>
           <a href="#faq" class="nav-link">FAQ</a>
         </div>
         <a href="#login" class="btn btn-ghost nav-auth-btn">Login</a>
@@ -757,6 +881,20 @@ const App = {
       }
       case 'friend_new_card':
         return `${actor} created a new card: ${cardName}.`;
+      case 'item_comment':
+        return `${actor} commented on ${cardName}.`;
+      case 'challenge_result': {
+        const challenge = notification.challenge_name || 'A challenge';
+        if (notification.actor_username) {
+          return `${challenge} has ended. ${notification.actor_username} won!`;
+        }
+        return `${challenge} has ended with no winner.`;
+      }
+      case 'org_template': {
+        const title = notification.template_title || 'a template';
+        const org = notification.org_name || 'your organization';
+        return `${actor} published ${title} for ${org}.`;
+      }
       default:
         return 'You have a new notification.';
     }
@@ -768,6 +906,20 @@ const App = {
         return `#friend-card/${notification.friendship_id}`;
       }
     }
+    if (notification.type === 'item_comment') {
+      if (notification.friendship_id) {
+        return `#friend-card/${notification.friendship_id}`;
+      }
+      if (notification.card_id) {
+        return `#card/${notification.card_id}`;
+      }
+    }
+    if (notification.type === 'challenge_result' && notification.challenge_id) {
+      return `#challenge/${notification.challenge_id}`;
+    }
+    if (notification.type === 'org_template' && notification.template_id) {
+      return `#template/${notification.template_id}`;
+    }
     return '#friends';
   },
 
@@ -923,6 +1075,18 @@ const App = {
               <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="in_app_friend_new_card" ${settings.in_app_friend_new_card ? 'checked' : ''}>
               <span>Friend creates a new card</span>
             </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="in_app_item_comment" ${settings.in_app_item_comment ? 'checked' : ''}>
+              <span>Comments on your items and replies</span>
+            </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="in_app_challenge_result" ${settings.in_app_challenge_result ? 'checked' : ''}>
+              <span>Challenge results</span>
+            </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="in_app_org_template" ${settings.in_app_org_template ? 'checked' : ''}>
+              <span>New organization templates</span>
+            </label>
           </div>
         </div>
         <div class="notification-channel">
@@ -948,6 +1112,18 @@ const App = {
               <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="email_friend_new_card" ${settings.email_friend_new_card ? 'checked' : ''}>
               <span>Friend creates a new card</span>
             </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="email_item_comment" ${settings.email_item_comment ? 'checked' : ''}>
+              <span>Comments on your items and replies</span>
+            </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="email_challenge_result" ${settings.email_challenge_result ? 'checked' : ''}>
+              <span>Challenge results</span>
+            </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="email_org_template" ${settings.email_org_template ? 'checked' : ''}>
+              <span>New organization templates</span>
+            </label>
           </div>
         </div>
       </div>
@@ -1308,6 +1484,13 @@ const App = {
     this.closeMobileMenu();
     window.scrollTo(0, 0);
     this.currentView = null;
+    // Public profile pages are served at /u/{username}; map them onto the
+    // hash route so they render without a session.
+    const profilePath = window.location.pathname.match(/^\/u\/([^/]+)\/?$/);
+    if (profilePath && !window.location.hash) {
+      window.location.replace(`/#u/${profilePath[1]}`);
+      return;
+    }
     const hash = window.location.hash.slice(1) || 'home';
     // Parse hash with query parameters: page?param=value
     const [pagePart, queryPart] = hash.split('?');
@@ -1372,6 +1555,31 @@ const App = {
       case 'friend-card':
         this.requireAuth(() => this.renderFriendCard(container, params[0]));
         break;
+      case 'challenge':
+        this.requireAuth(() => this.renderChallenge(container, params[0]));
+        break;
+      case 'orgs':
+        this.requireAuth(() => this.renderOrganizations(container));
+        break;
+      case 'org':
+        this.requireAuth(() => this.renderOrganization(container, params[0]));
+        break;
+      case 'org-invite':
+        if (this.user) {
+          this.renderOrgInviteAccept(container, params[0]);
+        } else {
+          this.renderOrgInviteGate(container, params[0]);
+        }
+        break;
+      case 'u':
+        this.renderPublicProfile(container, decodeURIComponent(params[0] || ''));
+        break;
+      case 'templates':
+        this.renderTemplates(container);
+        break;
+      case 'template':
+        this.renderTemplate(container, params[0]);
+        break;
       case 'archive':
         // Redirect to dashboard (archive merged into dashboard)
         window.location.hash = '#dashboard';
@@ -1413,22 +1621,26 @@ const App = {
     callback();
   },
 
-  storePendingInviteToken(token) {
+  // route is the hash page that accepts the token: friend-invite or org-invite.
+  storePendingInviteToken(token, route = 'friend-invite') {
     if (!token) return;
     sessionStorage.setItem('pendingInviteToken', token);
+    sessionStorage.setItem('pendingInviteRoute', route);
   },
 
   consumePendingInviteToken() {
     const token = sessionStorage.getItem('pendingInviteToken');
-    if (!token) return null;
+    const route = sessionStorage.getItem('pendingInviteRoute') || 'friend-invite';
     sessionStorage.removeItem('pendingInviteToken');
-    return token;
+    sessionStorage.removeItem('pendingInviteRoute');
+    if (!token) return null;
+    return `#${route}/${token}`;
   },
 
   redirectAfterAuth(defaultHash = '#dashboard') {
-    const token = this.consumePendingInviteToken();
-    if (token) {
-      window.location.hash = `#friend-invite/${token}`;
+    const inviteHash = this.consumePendingInviteToken();
+    if (inviteHash) {
+      window.location.hash = inviteHash;
       return;
     }
     window.location.hash = defaultHash;
@@ -2033,6 +2245,10 @@ const App = {
               <button class="dropdown-item ${hasSelection ? '' : 'dropdown-item--disabled'}" role="menuitem" data-action="export-cards" ${hasSelection ? '' : 'title="Select cards first"'}>
                 <i class="fas fa-download"></i> Export Cards
               </button>
+              <div class="dropdown-divider"></div>
+              <button class="dropdown-item" role="menuitem" data-action="show-trash">
+                <i class="fas fa-trash-restore"></i> Recently Deleted
+              </button>
             </div>
           </div>
           <button class="btn btn-primary" data-action="show-create-card-modal">+ Card</button>
@@ -2216,7 +2432,7 @@ const App = {
     }
 
     const count = this.selectedCards.length;
-    if (!confirm(`Are you sure you want to delete ${count} card${count !== 1 ? 's' : ''}? This cannot be undone.`)) {
+    if (!confirm(`Are you sure you want to delete ${count} card${count !== 1 ? 's' : ''}? You can restore deleted cards for 30 days from Actions → Recently Deleted.`)) {
       return;
     }
 
@@ -2234,6 +2450,47 @@ const App = {
     }
   },
 
+  async showTrash() {
+    document.querySelectorAll('.dropdown-menu--visible').forEach(menu => {
+      menu.classList.remove('dropdown-menu--visible');
+    });
+    try {
+      const response = await API.cards.trash();
+      const cards = response.cards || [];
+      const options = { month: 'short', day: 'numeric', year: 'numeric' };
+      const list = cards.length === 0
+        ? '<p class="text-muted">No recently deleted cards.</p>'
+        : cards.map(card => `
+          <div class="friend-item">
+            <div>
+              <strong>${this.getCardDisplayName(card)}</strong>
+              <div class="text-muted">
+                ${card.item_count} item${card.item_count !== 1 ? 's' : ''} · deleted ${new Date(card.deleted_at).toLocaleDateString('en-US', options)} · removed for good ${new Date(card.purge_at).toLocaleDateString('en-US', options)}
+              </div>
+            </div>
+            <div class="friend-actions">
+              <button class="btn btn-primary btn-sm" data-action="restore-trashed-card" data-card-id="${card.id}">Restore</button>
+            </div>
+          </div>
+        `).join('');
+      this.openModal('Recently Deleted', `<div class="friend-list">${list}</div>`);
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async restoreTrashedCard(cardId) {
+    if (!cardId) return;
+    try {
+      await API.cards.restoreFromTrash(cardId);
+      this.closeModal();
+      this.toast('Card restored', 'success');
+      this.renderDashboard(document.getElementById('main-container'));
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
   async exportSelectedCards() {
     // Close any open dropdowns
     document.querySelectorAll('.dropdown-menu--visible').forEach(menu => {
@@ -2313,7 +2570,7 @@ const App = {
       // Ignore - use default name
     }
 
-    if (!confirm(`Are you sure you want to delete "${cardName}"? This cannot be undone.`)) {
+    if (!confirm(`Are you sure you want to delete "${cardName}"? You can restore it for 30 days from Actions → Recently Deleted.`)) {
       return;
     }
 
@@ -2740,9 +2997,18 @@ const App = {
               🔀 Shuffle
             </button>
             ${!isAnon ? `
+              <button class="btn btn-secondary" data-action="undo-card-edit">
+                ↩️ Undo
+              </button>
+              <button class="btn btn-secondary" data-action="show-card-history">
+                🕘 History
+              </button>
               <button class="btn btn-secondary" data-action="show-clone-card-modal">
                 📄 Clone
               </button>
+              <button class="btn btn-secondary" data-action="show-publish-template-modal" ${itemCount === 0 ? 'disabled' : ''}>
+                📚 Publish
+              </button>
             ` : ''}
             <button class="btn btn-primary" id="finalize-btn" data-action="finalize-card" ${itemCount < capacity ? 'disabled' : ''}>
               ✓ Finalize Card
@@ -2974,6 +3240,13 @@ const App = {
 
     const visibilityIcon = this.currentCard.visible_to_friends ? 'eye' : 'eye-slash';
     const visibilityLabel = this.currentCard.visible_to_friends ? 'Visible to friends' : 'Private';
+    const isPublic = this.currentCard.visibility === 'public';
+    // Only offer the public override once the user has opted in to a profile.
+    const publicToggle = this.user?.public_profile ? `
+            <button class="btn btn-ghost btn-sm" data-action="toggle-card-public" data-card-id="${this.currentCard.id}" data-public="${!isPublic}" title="${isPublic ? 'Shown on your public profile' : 'Not on your public profile'}">
+              <i class="fas fa-${isPublic ? 'globe' : 'lock'}"></i>
+              <span>${isPublic ? 'Public' : 'Not public'}</span>
+            </button>` : '';
 
     container.innerHTML = `
       <div class="finalized-card-view">
@@ -2986,11 +3259,18 @@ const App = {
           </div>
           <div class="card-header-actions">
             <button class="btn btn-ghost btn-sm" data-action="edit-card-meta" title="Edit card name">✏️</button>
+            ${!this.isAnonymousMode ? `
+              <button class="btn btn-ghost btn-sm" data-action="undo-card-edit" title="Undo last change">↩️</button>
+              <button class="btn btn-ghost btn-sm" data-action="show-card-history" title="History">🕘</button>
+            ` : ''}
             <button class="btn btn-ghost btn-sm" data-action="show-clone-card-modal" title="Clone card">📄</button>
+            <button class="btn btn-ghost btn-sm" data-action="show-publish-template-modal" title="Publish as template">📚</button>
             <button class="visibility-toggle-btn ${this.currentCard.visible_to_friends ? 'visibility-toggle-btn--visible' : 'visibility-toggle-btn--private'}" data-action="toggle-card-visibility" data-card-id="${this.currentCard.id}" data-visible="${!this.currentCard.visible_to_friends}" title="${visibilityLabel}">
               <i class="fas fa-${visibilityIcon}"></i>
               <span>${visibilityLabel}</span>
             </button>
+            ${publicToggle}
+            <span id="card-org-toggle"></span>
           </div>
         </div>
 
@@ -3008,6 +3288,7 @@ const App = {
         </div>
       </div>
     `;
+    this.loadCardOrgToggle();
 
     this.setupFinalizedCardEvents();
   },
@@ -3245,6 +3526,24 @@ const App = {
             </button>
           </div>
         </form>
+        <form id="due-date-form" style="margin-top: 1.5rem; border-top: 1px solid var(--border-color, #ddd); padding-top: 1rem;">
+          <div style="display: flex; gap: 1rem;">
+            <div class="form-group" style="flex: 1;">
+              <label class="form-label" for="item-due-date">Due date (optional)</label>
+              <input type="date" id="item-due-date" class="form-input" value="${this.escapeHtml(item?.due_date || '')}">
+            </div>
+            <div class="form-group" style="flex: 1;">
+              <label class="form-label" for="item-reminder">Reminder</label>
+              <select id="item-reminder" class="form-input">
+                ${[['', 'None'], ['0', 'On the day'], ['1', '1 day before'], ['3', '3 days before'], ['7', '1 week before'], ['14', '2 weeks before'], ['30', '30 days before']]
+                  .map(([value, label]) => `<option value="${value}" ${String(item?.reminder_days_before ?? '') === value ? 'selected' : ''}>${label}</option>`)
+                  .join('')}
+              </select>
+            </div>
+          </div>
+          <p class="text-muted" style="font-size: 0.85rem;">Due dates appear in your calendar feed. Set one up on your profile page.</p>
+          <button type="submit" class="btn btn-secondary btn-sm">Save Due Date</button>
+        </form>
       `);
 
       document.getElementById('complete-form').addEventListener('submit', async (e) => {
@@ -3252,6 +3551,28 @@ const App = {
         const notes = document.getElementById('complete-notes').value;
         await this.completeItem(position, notes);
       });
+
+      document.getElementById('due-date-form').addEventListener('submit', async (e) => {
+        e.preventDefault();
+        const dueDate = document.getElementById('item-due-date').value || null;
+        const reminder = document.getElementById('item-reminder').value;
+        await this.setItemDueDate(position, dueDate, reminder === '' ? null : parseInt(reminder, 10));
+      });
+    }
+  },
+
+  async setItemDueDate(position, dueDate, reminderDaysBefore) {
+    try {
+      const response = await API.cards.setDueDate(this.currentCard.id, position, dueDate, reminderDaysBefore);
+      const item = this.currentCard.items?.find(i => i.position === position);
+      if (item) {
+        item.due_date = response.item?.due_date || null;
+        item.reminder_days_before = response.item?.reminder_days_before ?? null;
+      }
+      this.closeModal();
+      this.toast(dueDate ? 'Due date saved' : 'Due date cleared', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
     }
   },
 
@@ -4130,6 +4451,90 @@ const App = {
     }
   },
 
+  async undoCardEdit() {
+    if (!this.currentCard || this.isAnonymousMode) return;
+    try {
+      const response = await API.cards.undo(this.currentCard.id);
+      this.currentCard = response.card;
+      this.rerenderCurrentCard();
+      this.toast('Undone', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  rerenderCurrentCard() {
+    const container = document.getElementById('main-container');
+    if (!container) return;
+    if (this.currentCard.is_finalized) {
+      this.renderFinalizedCard(container);
+    } else {
+      this.renderCardEditor(container);
+    }
+  },
+
+  revisionLabel(action) {
+    const labels = {
+      add_item: 'Added an item',
+      update_item: 'Edited an item',
+      remove_item: 'Removed an item',
+      swap_items: 'Moved items',
+      shuffle: 'Shuffled',
+      update_config: 'Changed header or FREE space',
+      update_meta: 'Changed title or category',
+      restore: 'Restored an earlier version',
+      complete_item: 'Completed an item',
+      uncomplete_item: 'Marked an item incomplete',
+      update_notes: 'Changed notes or proof',
+      update_schedule: 'Changed a due date',
+      update_visibility: 'Changed who can see it',
+      archive: 'Archived or unarchived',
+    };
+    return labels[action] || action;
+  },
+
+  async showCardHistory() {
+    if (!this.currentCard || this.isAnonymousMode) return;
+    try {
+      const response = await API.cards.history(this.currentCard.id);
+      const revisions = response.revisions || [];
+      const list = revisions.length === 0
+        ? '<p class="text-muted">No edits recorded yet.</p>'
+        : revisions.map(rev => `
+          <div class="friend-item">
+            <div>
+              <strong>${this.escapeHtml(this.revisionLabel(rev.action))}</strong>
+              <div class="text-muted">
+                ${new Date(rev.created_at).toLocaleString()} · ${rev.after.items.length} item${rev.after.items.length !== 1 ? 's' : ''}${rev.undone_at ? ' · undone' : ''}
+              </div>
+            </div>
+            <div class="friend-actions">
+              <button class="btn btn-secondary btn-sm" data-action="restore-card-revision" data-revision-id="${rev.id}">Restore</button>
+            </div>
+          </div>
+        `).join('');
+      this.openModal('Card History', `
+        <p class="text-muted">Restoring puts the card back to how it looked right after that edit.</p>
+        <div class="friend-list">${list}</div>
+      `);
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async restoreCardRevision(revisionId) {
+    if (!this.currentCard || !revisionId) return;
+    try {
+      const response = await API.cards.restoreRevision(this.currentCard.id, revisionId);
+      this.currentCard = response.card;
+      this.closeModal();
+      this.rerenderCurrentCard();
+      this.toast('Card restored', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
   async updateDraftConfig({ headerText = null, hasFreeSpace = null } = {}) {
     if (!this.currentCard || this.currentCard.is_finalized) return;
 
@@ -4304,90 +4709,489 @@ const App = {
     }
   },
 
-  async toggleCardVisibility(cardId, visibleToFriends) {
+  async showPublishTemplateModal() {
+    if (!this.currentCard || this.isAnonymousMode) return;
+
+    let adminOrgs = [];
     try {
-      const response = await API.cards.updateVisibility(cardId, visibleToFriends);
-      this.currentCard = response.card;
-      this.toast(visibleToFriends ? 'Card is now visible to friends' : 'Card is now private', 'success');
-      // Re-render to update the UI
-      this.route();
+      const response = await API.orgs.list();
+      adminOrgs = (response.organizations || []).filter(org => org.role === 'admin');
     } catch (error) {
-      this.toast(error.message || 'Failed to update visibility', 'error');
-    }
-  },
-
-  async finalizeCard() {
-    // For anonymous users, show the auth modal instead of finalizing directly
-    if (this.isAnonymousMode) {
-      this.showFinalizeAuthModal();
-      return;
+      // Publishing to an organization is optional; fall back to public/unlisted.
     }
 
-    this.showFinalizeConfirmModal();
-  },
-
-  showFinalizeConfirmModal() {
-    this.openModal('Finalize Card', `
-      <div class="finalize-confirm-modal">
-        <p style="margin-bottom: 1.5rem;">
-          Are you sure you want to finalize this card? You won't be able to change the items after this.
+    this.openModal('Publish as Template', `
+      <form data-action="publish-template">
+        <p class="text-muted">
+          Others can start a new card from your layout and goals. Your progress, notes and proof links are not shared,
+          and later changes to this card won't change the template.
         </p>
-        <div style="margin-bottom: 1.5rem;">
-          <label class="checkbox-label" style="display: flex; align-items: center; gap: 0.5rem; cursor: pointer;">
-            <input type="checkbox" id="finalize-visibility" checked>
-            <span>Visible to friends</span>
+        <div class="form-group">
+          <label for="publish-template-title">Title</label>
+          <input type="text" id="publish-template-title" class="form-input" maxlength="100" required
+                 value="${this.escapeHtml(this.getCardDisplayName(this.currentCard))}">
+        </div>
+        <div class="form-group">
+          <label for="publish-template-description">
+            Description <span class="text-muted" style="font-weight: normal;">(optional)</span>
           </label>
-          <p class="text-muted" style="margin-top: 0.5rem; font-size: 0.875rem;">
-            If unchecked, friends won't be able to see this card.
-          </p>
+          <textarea id="publish-template-description" class="form-input" maxlength="500" rows="3"></textarea>
         </div>
-        <div style="display: flex; gap: 1rem; justify-content: flex-end;">
-          <button class="btn btn-ghost" data-action="close-modal">Cancel</button>
-          <button class="btn btn-primary" data-action="confirm-finalize">Finalize Card</button>
+        <div class="form-group">
+          <label style="display: flex; align-items: center; gap: 0.5rem;">
+            <input type="checkbox" id="publish-template-public">
+            <span>List in the public template gallery</span>
+          </label>
+          <small class="text-muted">Unlisted templates can only be opened with their link.</small>
         </div>
-      </div>
+        ${adminOrgs.length > 0 ? `
+          <div class="form-group">
+            <label for="publish-template-org">Organization</label>
+            <select id="publish-template-org" class="form-input">
+              <option value="">None</option>
+              ${adminOrgs.map(org => `<option value="${org.id}">${this.escapeHtml(org.name)}</option>`).join('')}
+            </select>
+            <small class="text-muted">Organization templates are only visible to its members, who are notified when you publish.</small>
+          </div>
+        ` : ''}
+        <div style="display: flex; gap: 1rem; margin-top: 1.5rem;">
+          <button type="button" class="btn btn-secondary" style="flex: 1;" data-action="close-modal">Cancel</button>
+          <button type="submit" class="btn btn-primary" style="flex: 1;">Publish</button>
+        </div>
+      </form>
     `);
   },
 
-  async confirmFinalize() {
-    const visibilityCheckbox = document.getElementById('finalize-visibility');
-    const visibleToFriends = visibilityCheckbox ? visibilityCheckbox.checked : true;
+  async handlePublishTemplate(event) {
+    event.preventDefault();
+    if (!this.currentCard) return;
+
+    const title = document.getElementById('publish-template-title').value.trim();
+    const description = document.getElementById('publish-template-description').value.trim();
+    const orgId = document.getElementById('publish-template-org')?.value || '';
+    const visibility = document.getElementById('publish-template-public').checked ? 'public' : 'unlisted';
+    const params = { title, description, visibility };
+    if (orgId) {
+      params.visibility = 'organization';
+      params.org_id = orgId;
+    }
 
     try {
+      const response = await API.templates.publish(this.currentCard.id, params);
       this.closeModal();
-      const response = await API.cards.finalize(this.currentCard.id, visibleToFriends);
-      this.currentCard = response.card;
-      this.renderFinalizedCard(document.getElementById('main-container'));
-      this.toast('Card finalized! Good luck with your goals! 🎉', 'success');
-      this.confetti(50);
+      this.toast('Template published!', 'success');
+      window.location.hash = `#template/${response.template.id}`;
     } catch (error) {
       this.toast(error.message, 'error');
     }
   },
 
-  // Show the auth modal when an anonymous user tries to finalize
-  showFinalizeAuthModal() {
-    this.openModal('Save Your Card', `
-      <div class="finalize-auth-modal">
-        <p style="margin-bottom: 1.5rem;">
-          Your bingo card is ready! Create an account to save and finalize it.
-        </p>
-        <div style="display: flex; flex-direction: column; gap: 1rem;">
-          <button class="btn btn-primary btn-lg" data-action="show-finalize-register-form">
-            Create Account
-          </button>
-          <button class="btn btn-secondary btn-lg" data-action="show-finalize-login-form">
-            I Already Have an Account
-          </button>
-          <button class="btn btn-ghost" data-action="close-modal">
-            Cancel
-          </button>
-        </div>
-      </div>
-    `);
-  },
+  async renderTemplates(container) {
+    let categories;
+    try {
+      const response = await API.cards.getCategories();
+      categories = response.categories || [];
+    } catch (error) {
+      categories = this.getFallbackCategories();
+    }
 
-  showAIAuthModal() {
+    const filters = this.templateFilters || {};
+    const categoryOptions = categories.map(c => `
+      <option value="${this.escapeHtml(c.id)}" ${filters.category === c.id ? 'selected' : ''}>${this.escapeHtml(c.name)}</option>
+    `).join('');
+    const sizeOptions = [2, 3, 4, 5].map(n => `
+      <option value="${n}" ${filters.grid_size === String(n) ? 'selected' : ''}>${n}x${n}</option>
+    `).join('');
+
+    container.innerHTML = `
+      <div class="friends-page">
+        <div class="friends-header">
+          <h2>Card Templates</h2>
+        </div>
+        <p class="text-muted">Start a new card from a layout someone else has shared.</p>
+
+        <div class="card">
+          <form data-action="search-templates" style="display: flex; gap: 0.5rem; flex-wrap: wrap;">
+            <input type="search" id="template-search" class="form-input" style="flex: 2; min-width: 12rem;"
+                   placeholder="Search templates" value="${this.escapeHtml(filters.q || '')}">
+            <select id="template-category" class="form-input" style="flex: 1;">
+              <option value="">All categories</option>
+              ${categoryOptions}
+            </select>
+            <select id="template-grid-size" class="form-input" style="flex: 1;">
+              <option value="">Any size</option>
+              ${sizeOptions}
+            </select>
+            <select id="template-sort" class="form-input" style="flex: 1;">
+              <option value="popular" ${filters.sort !== 'newest' ? 'selected' : ''}>Most used</option>
+              <option value="newest" ${filters.sort === 'newest' ? 'selected' : ''}>Newest</option>
+            </select>
+            <button type="submit" class="btn btn-primary">Search</button>
+          </form>
+        </div>
+
+        <div class="card">
+          <div class="friend-list" id="template-list">
+            <div class="text-center"><div class="spinner" style="margin: 2rem auto;"></div></div>
+          </div>
+          <div class="text-center hidden" id="template-load-more">
+            <button class="btn btn-secondary" data-action="load-more-templates">Load more</button>
+          </div>
+        </div>
+
+        ${this.user ? `
+          <div class="card">
+            <h3>My Templates</h3>
+            <div class="friend-list" id="my-template-list">
+              <div class="text-center"><div class="spinner" style="margin: 2rem auto;"></div></div>
+            </div>
+          </div>
+        ` : ''}
+      </div>
+    `;
+
+    await this.loadTemplates();
+    if (this.user) await this.loadMyTemplates();
+  },
+
+  renderTemplateListItem(template, actions = '') {
+    const goals = template.items.length;
+    return `
+      <div class="friend-item">
+        <div>
+          <a href="#template/${template.id}"><strong>${this.escapeHtml(template.title)}</strong></a>
+          <div class="text-muted">
+            ${template.grid_size}x${template.grid_size} · ${goals} goal${goals === 1 ? '' : 's'} ·
+            ${template.use_count} use${template.use_count === 1 ? '' : 's'} · by ${this.escapeHtml(template.owner_username)}
+          </div>
+        </div>
+        ${actions ? `<div class="friend-actions">${actions}</div>` : ''}
+      </div>
+    `;
+  },
+
+  async loadTemplates(append = false) {
+    const listEl = document.getElementById('template-list');
+    const moreEl = document.getElementById('template-load-more');
+    if (!listEl) return;
+
+    if (!append) this.templateOffset = 0;
+    try {
+      const response = await API.templates.list({ ...this.templateFilters, offset: this.templateOffset });
+      const templates = response.templates || [];
+      this.templateOffset += templates.length;
+
+      const html = templates.map(template => this.renderTemplateListItem(template)).join('');
+      if (append) {
+        listEl.insertAdjacentHTML('beforeend', html);
+      } else {
+        listEl.innerHTML = html || '<p class="text-muted">No templates found.</p>';
+      }
+      moreEl.classList.toggle('hidden', !response.has_more);
+    } catch (error) {
+      listEl.innerHTML = `<p class="text-muted">${this.escapeHtml(error.message)}</p>`;
+    }
+  },
+
+  async loadMyTemplates() {
+    const listEl = document.getElementById('my-template-list');
+    if (!listEl) return;
+
+    try {
+      const response = await API.templates.mine();
+      const templates = response.templates || [];
+      if (templates.length === 0) {
+        listEl.innerHTML = '<p class="text-muted">You haven\'t published any templates yet. Use 📚 Publish on one of your cards.</p>';
+        return;
+      }
+      listEl.innerHTML = templates.map(template => {
+        const isPublic = template.visibility === 'public';
+        const actions = `
+          ${template.hidden_at ? '<span class="text-muted">Hidden by a moderator</span>' : ''}
+          <button class="btn btn-ghost btn-sm" data-action="toggle-template-visibility" data-template-id="${template.id}" data-visibility="${isPublic ? 'unlisted' : 'public'}">
+            ${isPublic ? 'Unlist' : 'List publicly'}
+          </button>
+          <button class="btn btn-ghost btn-sm" data-action="delete-template" data-template-id="${template.id}">Delete</button>
+        `;
+        return this.renderTemplateListItem(template, actions);
+      }).join('');
+    } catch (error) {
+      listEl.innerHTML = `<p class="text-muted">${this.escapeHtml(error.message)}</p>`;
+    }
+  },
+
+  handleSearchTemplates(event) {
+    event.preventDefault();
+    this.templateFilters = {
+      q: document.getElementById('template-search').value.trim(),
+      category: document.getElementById('template-category').value,
+      grid_size: document.getElementById('template-grid-size').value,
+      sort: document.getElementById('template-sort').value,
+    };
+    this.loadTemplates();
+  },
+
+  async toggleTemplateVisibility(templateId, visibility) {
+    if (!templateId) return;
+    try {
+      await API.templates.update(templateId, { visibility });
+      this.toast(visibility === 'public' ? 'Template listed in the gallery' : 'Template is now unlisted', 'success');
+      await this.loadMyTemplates();
+      await this.loadTemplates();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async deleteTemplate(templateId) {
+    if (!templateId || !confirm('Delete this template? Cards already made from it are not affected.')) return;
+    try {
+      await API.templates.remove(templateId);
+      this.toast('Template deleted', 'success');
+      if (window.location.hash.startsWith('#template/')) {
+        window.location.hash = '#templates';
+        return;
+      }
+      await this.loadMyTemplates();
+      await this.loadTemplates();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async renderTemplate(container, templateId) {
+    container.innerHTML = `
+      <div class="text-center"><div class="spinner" style="margin: 2rem auto;"></div></div>
+    `;
+
+    let template;
+    try {
+      const response = await API.templates.get(templateId);
+      template = response.template;
+    } catch (error) {
+      container.innerHTML = `
+        <div class="card text-center" style="padding: 3rem;">
+          <h3>Template unavailable</h3>
+          <p class="text-muted">This template doesn't exist or is no longer shared.</p>
+          <a href="#templates" class="btn btn-primary">Browse Templates</a>
+        </div>
+      `;
+      return;
+    }
+
+    this.currentTemplate = template;
+    // renderGrid draws this.currentCard, and templates carry the same layout fields.
+    this.currentCard = { ...template, items: template.items.map(item => ({ ...item, id: '' })) };
+    const gridSize = this.getGridSize(this.currentCard);
+    const isOwner = this.user && template.owner_id === this.user.id;
+    const useButton = this.user
+      ? '<button class="btn btn-primary" data-action="show-use-template-modal">Use this template</button>'
+      : '<a href="#login" class="btn btn-primary">Log in to use this template</a>';
+
+    container.innerHTML = `
+      <div class="finalized-card-view">
+        <div class="friend-card-title">
+          <h3 style="margin: 0;">${this.escapeHtml(template.title)}</h3>
+          <p class="text-muted">
+            by ${this.escapeHtml(template.owner_username)} ·
+            ${template.use_count} use${template.use_count === 1 ? '' : 's'}${template.visibility === 'unlisted' ? ' · unlisted' : ''}
+          </p>
+          ${template.description ? `<p>${this.escapeHtml(template.description)}</p>` : ''}
+        </div>
+        <div class="bingo-container bingo-container--finalized">
+          <div class="bingo-grid bingo-grid--finalized" id="bingo-grid" style="--grid-size: ${gridSize};">
+            ${this.renderGrid(true)}
+          </div>
+        </div>
+        <div style="display: flex; gap: 1rem; justify-content: center; margin-top: 1rem;">
+          <a href="#templates" class="btn btn-secondary">All Templates</a>
+          ${isOwner ? `<button class="btn btn-ghost" data-action="delete-template" data-template-id="${template.id}">Delete</button>` : ''}
+          ${useButton}
+        </div>
+      </div>
+    `;
+  },
+
+  showUseTemplateModal() {
+    const template = this.currentTemplate;
+    if (!template) return;
+
+    const currentYear = new Date().getFullYear();
+    this.openModal('Use Template', `
+      <form data-action="use-template">
+        <div class="form-group">
+          <label for="use-template-year">Year</label>
+          <select id="use-template-year" class="form-input" required>
+            <option value="${currentYear}" selected>${currentYear}</option>
+            <option value="${currentYear + 1}">${currentYear + 1}</option>
+          </select>
+        </div>
+        <div class="form-group">
+          <label for="use-template-title">Title</label>
+          <input type="text" id="use-template-title" class="form-input" maxlength="100"
+                 value="${this.escapeHtml(template.title)}">
+          <small class="text-muted">Your new card starts as a draft you can edit before finalizing.</small>
+        </div>
+        <p class="text-muted text-danger hidden" id="use-template-error"></p>
+        <div style="display: flex; gap: 1rem; margin-top: 1.5rem;">
+          <button type="button" class="btn btn-secondary" style="flex: 1;" data-action="close-modal">Cancel</button>
+          <button type="submit" class="btn btn-primary" style="flex: 1;">Create Card</button>
+        </div>
+      </form>
+    `);
+  },
+
+  async handleUseTemplate(event) {
+    event.preventDefault();
+    const template = this.currentTemplate;
+    if (!template) return;
+
+    const year = parseInt(document.getElementById('use-template-year').value, 10);
+    const title = document.getElementById('use-template-title').value.trim() || null;
+    const errorEl = document.getElementById('use-template-error');
+
+    try {
+      const response = await API.templates.use(template.id, year, title);
+      if (response.error === 'card_exists') {
+        errorEl.textContent = `You already have a card called "${title || template.title}" for ${year}. Pick another title.`;
+        errorEl.classList.remove('hidden');
+        return;
+      }
+      this.closeModal();
+      this.toast('Card created from template!', 'success');
+      window.location.hash = `#card/${response.card.id}`;
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async toggleCardVisibility(cardId, visibleToFriends) {
+    try {
+      const response = await API.cards.updateVisibility(cardId, visibleToFriends);
+      this.currentCard = response.card;
+      this.toast(visibleToFriends ? 'Card is now visible to friends' : 'Card is now private', 'success');
+      // Re-render to update the UI
+      this.route();
+    } catch (error) {
+      this.toast(error.message || 'Failed to update visibility', 'error');
+    }
+  },
+
+  async toggleCardPublic(cardId, isPublic) {
+    try {
+      const response = await API.cards.setVisibility(cardId, isPublic ? 'public' : 'friends');
+      this.currentCard = response.card;
+      this.toast(isPublic ? 'Card is now on your public profile' : 'Card removed from your public profile', 'success');
+      this.route();
+    } catch (error) {
+      this.toast(error.message || 'Failed to update visibility', 'error');
+    }
+  },
+
+  // Members of at least one organization can share a card with their
+  // organizations; the toggle is filled in once we know whether they are.
+  async loadCardOrgToggle() {
+    const slot = document.getElementById('card-org-toggle');
+    const card = this.currentCard;
+    if (!slot || !card || card.visibility === 'public') return;
+    try {
+      const response = await API.orgs.list();
+      if ((response.organizations || []).length === 0 && card.visibility !== 'organization') return;
+    } catch (error) {
+      return;
+    }
+    const shared = card.visibility === 'organization';
+    slot.innerHTML = `
+      <button class="btn btn-ghost btn-sm" data-action="toggle-card-org" data-card-id="${card.id}" data-org="${!shared}" title="${shared ? 'Shared with your organizations' : 'Not shared with your organizations'}">
+        <i class="fas fa-${shared ? 'building' : 'lock'}"></i>
+        <span>${shared ? 'Orgs' : 'Not in orgs'}</span>
+      </button>
+    `;
+  },
+
+  async toggleCardOrg(cardId, shared) {
+    try {
+      const response = await API.cards.setVisibility(cardId, shared ? 'organization' : 'friends');
+      this.currentCard = response.card;
+      this.toast(shared ? 'Card is now shared with your organizations' : 'Card is no longer shared with your organizations', 'success');
+      this.route();
+    } catch (error) {
+      this.toast(error.message || 'Failed to update visibility', 'error');
+    }
+  },
+
+  async finalizeCard() {
+    // For anonymous users, show the auth modal instead of finalizing directly
+    if (this.isAnonymousMode) {
+      this.showFinalizeAuthModal();
+      return;
+    }
+
+    this.showFinalizeConfirmModal();
+  },
+
+  showFinalizeConfirmModal() {
+    this.openModal('Finalize Card', `
+      <div class="finalize-confirm-modal">
+        <p style="margin-bottom: 1.5rem;">
+          Are you sure you want to finalize this card? You won't be able to change the items after this.
+        </p>
+        <div style="margin-bottom: 1.5rem;">
+          <label class="checkbox-label" style="display: flex; align-items: center; gap: 0.5rem; cursor: pointer;">
+            <input type="checkbox" id="finalize-visibility" checked>
+            <span>Visible to friends</span>
+          </label>
+          <p class="text-muted" style="margin-top: 0.5rem; font-size: 0.875rem;">
+            If unchecked, friends won't be able to see this card.
+          </p>
+        </div>
+        <div style="display: flex; gap: 1rem; justify-content: flex-end;">
+          <button class="btn btn-ghost" data-action="close-modal">Cancel</button>
+          <button class="btn btn-primary" data-action="confirm-finalize">Finalize Card</button>
+        </div>
+      </div>
+    `);
+  },
+
+  async confirmFinalize() {
+    const visibilityCheckbox = document.getElementById('finalize-visibility');
+    const visibleToFriends = visibilityCheckbox ? visibilityCheckbox.checked : true;
+
+    try {
+      this.closeModal();
+      const response = await API.cards.finalize(this.currentCard.id, visibleToFriends);
+      this.currentCard = response.card;
+      this.renderFinalizedCard(document.getElementById('main-container'));
+      this.toast('Card finalized! Good luck with your goals! 🎉', 'success');
+      this.confetti(50);
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  // Show the auth modal when an anonymous user tries to finalize
+  showFinalizeAuthModal() {
+    this.openModal('Save Your Card', `
+      <div class="finalize-auth-modal">
+        <p style="margin-bottom: 1.5rem;">
+          Your bingo card is ready! Create an account to save and finalize it.
+        </p>
+        <div style="display: flex; flex-direction: column; gap: 1rem;">
+          <button class="btn btn-primary btn-lg" data-action="show-finalize-register-form">
+            Create Account
+          </button>
+          <button class="btn btn-secondary btn-lg" data-action="show-finalize-login-form">
+            I Already Have an Account
+          </button>
+          <button class="btn btn-ghost" data-action="close-modal">
+            Cancel
+          </button>
+        </div>
+      </div>
+    `);
+  },
+
+  showAIAuthModal() {
     this.openModal('Use the AI Goal Wizard', `
       <div class="finalize-auth-modal">
         <p style="margin-bottom: 1.5rem;">
@@ -4672,7 +5476,7 @@ const App = {
 
   // Handle conflict: replace existing card
   async handleConflictReplace(existingCardId) {
-    if (!confirm('Are you sure you want to replace your existing card? This cannot be undone.')) {
+    if (!confirm('Are you sure you want to replace your existing card? The old card stays in Recently Deleted for 30 days.')) {
       return;
     }
 
@@ -4814,7 +5618,7 @@ const App = {
 
   // Handle create conflict: delete existing and create new
   async handleCreateConflictReplace(existingCardId) {
-    if (!confirm('Are you sure you want to delete your existing card? This cannot be undone.')) {
+    if (!confirm('Are you sure you want to delete your existing card? It stays in Recently Deleted for 30 days.')) {
       return;
     }
 
@@ -4978,6 +5782,24 @@ const App = {
           <div id="search-results" class="search-results"></div>
         </div>
 
+        <div id="friend-suggestions" class="card" style="display: none;">
+          <h3>People You May Know</h3>
+          <div id="suggestions-list"></div>
+        </div>
+
+        <div class="card">
+          <h3>Find Friends From Your Contacts</h3>
+          <p class="text-muted" style="margin-bottom: 1rem;">
+            Paste email addresses, one per line. They're hashed in your browser before being sent,
+            so we never see them. Only people who are searchable and have verified their email can be found.
+          </p>
+          <textarea id="contact-emails" class="form-input" rows="3" placeholder="friend@example.com"></textarea>
+          <div class="search-input-group mt-md">
+            <button class="btn btn-primary" id="discover-btn">Find Contacts</button>
+          </div>
+          <div id="discover-results" class="search-results"></div>
+        </div>
+
         <div id="friend-requests" class="card" style="display: none;">
           <h3>Friend Requests</h3>
           <div id="requests-list"></div>
@@ -4995,10 +5817,27 @@ const App = {
           </div>
         </div>
 
-        <div id="blocked-users" class="card" style="display: none;">
-          <h3>Blocked Users</h3>
-          <div id="blocked-list"></div>
-        </div>
+        <div class="card">
+          <h3>${new Date().getFullYear()} Leaderboard</h3>
+          <div id="leaderboard">
+            <div class="text-center"><div class="spinner"></div></div>
+          </div>
+        </div>
+
+        <div class="card">
+          <div class="friends-header">
+            <h3>Challenges</h3>
+            <button class="btn btn-primary btn-sm" data-action="show-create-challenge-modal">New Challenge</button>
+          </div>
+          <div id="challenge-list">
+            <div class="text-center"><div class="spinner"></div></div>
+          </div>
+        </div>
+
+        <div id="blocked-users" class="card" style="display: none;">
+          <h3>Blocked Users</h3>
+          <div id="blocked-list"></div>
+        </div>
       </div>
     `;
 
@@ -5006,6 +5845,9 @@ const App = {
     await this.loadFriends();
     await this.loadInvites();
     await this.loadBlockedUsers();
+    await this.loadFriendSuggestions();
+    await this.loadLeaderboard();
+    await this.loadChallenges();
   },
 
   setupFriendsEvents() {
@@ -5025,6 +5867,77 @@ const App = {
     });
 
     createInviteBtn.addEventListener('click', () => this.createFriendInvite());
+    document.getElementById('discover-btn').addEventListener('click', () => this.discoverContacts());
+  },
+
+  renderUserResults(users) {
+    return users.map(user => `
+      <div class="search-result-item">
+        <div>
+          <strong>${this.escapeHtml(user.username)}</strong>
+          ${user.mutual_friends ? `<div class="text-muted">${user.mutual_friends} mutual friend${user.mutual_friends === 1 ? '' : 's'}</div>` : ''}
+        </div>
+        <button class="btn btn-primary btn-sm" data-action="send-friend-request" data-user-id="${user.id}">
+          Add Friend
+        </button>
+      </div>
+    `).join('');
+  },
+
+  async loadFriendSuggestions() {
+    const sectionEl = document.getElementById('friend-suggestions');
+    const listEl = document.getElementById('suggestions-list');
+    if (!sectionEl || !listEl) return;
+    try {
+      const response = await API.friends.suggestions();
+      const suggestions = response.suggestions || [];
+      if (suggestions.length === 0) {
+        sectionEl.style.display = 'none';
+        return;
+      }
+      sectionEl.style.display = 'block';
+      listEl.innerHTML = this.renderUserResults(suggestions);
+    } catch (error) {
+      sectionEl.style.display = 'none';
+    }
+  },
+
+  // hashEmail mirrors the server: hex SHA-256 of the trimmed, lowercased
+  // address.
+  async hashEmail(email) {
+    const data = new TextEncoder().encode(email.trim().toLowerCase());
+    const digest = await crypto.subtle.digest('SHA-256', data);
+    return Array.from(new Uint8Array(digest)).map(b => b.toString(16).padStart(2, '0')).join('');
+  },
+
+  async discoverContacts() {
+    const resultsEl = document.getElementById('discover-results');
+    const emails = document.getElementById('contact-emails').value
+      .split(/[\s,;]+/)
+      .map(email => email.trim())
+      .filter(email => email.includes('@'));
+
+    if (emails.length === 0) {
+      resultsEl.innerHTML = '<p class="text-muted">Enter at least one email address.</p>';
+      return;
+    }
+    if (emails.length > 500) {
+      resultsEl.innerHTML = '<p class="text-muted">You can check up to 500 addresses at a time.</p>';
+      return;
+    }
+
+    try {
+      const hashes = await Promise.all(emails.map(email => this.hashEmail(email)));
+      const response = await API.friends.discover(hashes);
+      const users = response.users || [];
+      resultsEl.innerHTML = users.length > 0
+        ? this.renderUserResults(users)
+        : '<p class="text-muted">None of your contacts could be found.</p>';
+    } catch (error) {
+      resultsEl.innerHTML = '<p class="text-muted" id="discover-error"></p>';
+      const errorEl = document.getElementById('discover-error');
+      if (errorEl) errorEl.textContent = error.message;
+    }
   },
 
   async searchFriends() {
@@ -5043,16 +5956,7 @@ const App = {
       if (users.length === 0) {
         resultsEl.innerHTML = '<p class="text-muted">No users found</p>';
       } else {
-        resultsEl.innerHTML = users.map(user => `
-          <div class="search-result-item">
-            <div>
-              <strong>${this.escapeHtml(user.username)}</strong>
-            </div>
-            <button class="btn btn-primary btn-sm" data-action="send-friend-request" data-user-id="${user.id}">
-              Add Friend
-            </button>
-          </div>
-        `).join('');
+        resultsEl.innerHTML = this.renderUserResults(users);
       }
     } catch (error) {
       resultsEl.innerHTML = '<p class="text-muted" id="friend-search-error"></p>';
@@ -5097,151 +6001,863 @@ const App = {
     });
   },
 
-  async loadInvites() {
-    const listEl = document.getElementById('invite-list');
-    if (!listEl) return;
+  async loadInvites() {
+    const listEl = document.getElementById('invite-list');
+    if (!listEl) return;
+    try {
+      const response = await API.friends.listInvites();
+      const invites = response.invites || [];
+      if (invites.length === 0) {
+        listEl.innerHTML = '<p class="text-muted">No active invites.</p>';
+        return;
+      }
+
+      listEl.innerHTML = invites.map(invite => `
+        <div class="friend-item">
+          <div>
+            <strong>Invite created</strong>
+            <div class="text-muted">
+              ${invite.expires_at ? `Expires ${new Date(invite.expires_at).toLocaleDateString()}` : 'No expiration'}
+            </div>
+          </div>
+          <button class="btn btn-ghost btn-sm" data-action="revoke-invite" data-invite-id="${invite.id}">Revoke</button>
+        </div>
+      `).join('');
+    } catch (error) {
+      listEl.innerHTML = '<p class="text-muted" id="invite-list-error"></p>';
+      const errorEl = document.getElementById('invite-list-error');
+      if (errorEl) errorEl.textContent = error.message;
+    }
+  },
+
+  async revokeInvite(inviteId) {
+    try {
+      await API.friends.revokeInvite(inviteId);
+      this.toast('Invite revoked', 'success');
+      await this.loadInvites();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async loadBlockedUsers() {
+    const blockedEl = document.getElementById('blocked-users');
+    const blockedListEl = document.getElementById('blocked-list');
+    if (!blockedEl || !blockedListEl) return;
+    try {
+      const response = await API.friends.listBlocked();
+      const blocked = response.blocked || [];
+      if (blocked.length === 0) {
+        blockedEl.style.display = 'none';
+        return;
+      }
+      blockedEl.style.display = 'block';
+      blockedListEl.innerHTML = blocked.map(user => `
+        <div class="friend-item">
+          <div>
+            <strong>${this.escapeHtml(user.username)}</strong>
+          </div>
+          <button class="btn btn-ghost btn-sm" data-action="unblock-user" data-user-id="${user.id}">Unblock</button>
+        </div>
+      `).join('');
+    } catch (error) {
+      blockedEl.style.display = 'block';
+      blockedListEl.innerHTML = '<p class="text-muted" id="blocked-error"></p>';
+      const errorEl = document.getElementById('blocked-error');
+      if (errorEl) errorEl.textContent = error.message;
+    }
+  },
+
+  async sendFriendRequest(friendId) {
+    try {
+      await API.friends.sendRequest(friendId);
+      this.toast('Friend request sent!', 'success');
+      document.getElementById('friend-search').value = '';
+      document.getElementById('search-results').innerHTML = '';
+      document.getElementById('discover-results').innerHTML = '';
+      await this.loadFriends();
+      await this.loadFriendSuggestions();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async loadFriends() {
+    try {
+      const response = await API.friends.list();
+      const { friends, requests, sent } = response;
+      this.friendsList = friends || [];
+
+      // Pending requests (received)
+      const requestsEl = document.getElementById('friend-requests');
+      const requestsListEl = document.getElementById('requests-list');
+      if (requests && requests.length > 0) {
+        requestsEl.style.display = 'block';
+        requestsListEl.innerHTML = requests.map(req => `
+          <div class="friend-item">
+            <div>
+              <strong>${this.escapeHtml(req.requester_username)}</strong>
+            </div>
+            <div class="friend-actions">
+              <button class="btn btn-primary btn-sm" data-action="accept-request" data-request-id="${req.id}">Accept</button>
+              <button class="btn btn-ghost btn-sm" data-action="reject-request" data-request-id="${req.id}">Reject</button>
+            </div>
+          </div>
+        `).join('');
+      } else {
+        requestsEl.style.display = 'none';
+      }
+
+      // Sent requests
+      const sentEl = document.getElementById('sent-requests');
+      const sentListEl = document.getElementById('sent-list');
+      if (sent && sent.length > 0) {
+        sentEl.style.display = 'block';
+        sentListEl.innerHTML = sent.map(req => `
+          <div class="friend-item">
+            <div>
+              <strong>${this.escapeHtml(req.friend_username)}</strong>
+            </div>
+            <button class="btn btn-ghost btn-sm" data-action="cancel-request" data-request-id="${req.id}">Cancel</button>
+          </div>
+        `).join('');
+      } else {
+        sentEl.style.display = 'none';
+      }
+
+      // Friends list
+      const friendsListEl = document.getElementById('friends-list');
+      if (friends && friends.length > 0) {
+        friendsListEl.innerHTML = friends.map(friend => {
+          const otherUserId = friend.user_id === this.user.id ? friend.friend_id : friend.user_id;
+          const friendName = this.escapeHtml(friend.friend_username);
+          return `
+            <div class="friend-item">
+              <div>
+                <strong>${friendName}</strong>
+              </div>
+              <div class="friend-actions">
+                <a href="#friend-card/${friend.id}" class="btn btn-secondary btn-sm">View Card</a>
+                <button class="btn btn-ghost btn-sm" data-action="remove-friend" data-friendship-id="${friend.id}">Remove</button>
+                <button class="btn btn-ghost btn-sm" data-action="block-user" data-other-user-id="${otherUserId}">Block</button>
+              </div>
+            </div>
+          `;
+        }).join('');
+      } else {
+        friendsListEl.innerHTML = '<p class="text-muted">No friends yet. Search for people to add!</p>';
+      }
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async loadLeaderboard() {
+    const listEl = document.getElementById('leaderboard');
+    if (!listEl) return;
+    try {
+      const response = await API.friends.leaderboard();
+      const board = response.leaderboard;
+      if (board.opted_out) {
+        listEl.innerHTML = '<p class="text-muted">You\'ve hidden yourself from leaderboards. You can change this in your <a href="#profile">Profile settings</a>.</p>';
+        return;
+      }
+      if (board.entries.length === 0) {
+        listEl.innerHTML = '<p class="text-muted">No finalized cards yet this year.</p>';
+        return;
+      }
+      listEl.innerHTML = board.entries.map(entry => `
+        <div class="friend-item">
+          <div>
+            <strong>#${entry.rank} ${this.escapeHtml(entry.username)}${entry.is_you ? ' (you)' : ''}</strong>
+            <div class="text-muted">
+              ${Math.round(entry.completion_rate * 100)}% complete · ${entry.bingos} bingo${entry.bingos === 1 ? '' : 's'} · ${entry.completed_items}/${entry.total_items} goals
+            </div>
+          </div>
+        </div>
+      `).join('');
+    } catch (error) {
+      listEl.innerHTML = '<p class="text-muted" id="leaderboard-error"></p>';
+      const errorEl = document.getElementById('leaderboard-error');
+      if (errorEl) errorEl.textContent = error.message;
+    }
+  },
+
+  async loadChallenges() {
+    const listEl = document.getElementById('challenge-list');
+    if (!listEl) return;
+    try {
+      const response = await API.challenges.list();
+      const challenges = response.challenges || [];
+      if (challenges.length === 0) {
+        listEl.innerHTML = '<p class="text-muted">No challenges yet. Start one and invite your friends!</p>';
+        return;
+      }
+      listEl.innerHTML = challenges.map(challenge => `
+        <div class="friend-item">
+          <div>
+            <strong><a href="#challenge/${challenge.id}">${this.escapeHtml(challenge.name)}</a></strong>
+            <div class="text-muted">
+              ${this.formatChallengeDates(challenge)} · ${challenge.participant_count} joined · ${this.escapeHtml(challenge.status)}
+            </div>
+          </div>
+          <div class="friend-actions">
+            ${!challenge.joined && challenge.status !== 'finished' ? `<button class="btn btn-primary btn-sm" data-action="join-challenge" data-challenge-id="${challenge.id}">Join</button>` : ''}
+            <a href="#challenge/${challenge.id}" class="btn btn-secondary btn-sm">View</a>
+          </div>
+        </div>
+      `).join('');
+    } catch (error) {
+      listEl.innerHTML = '<p class="text-muted" id="challenge-list-error"></p>';
+      const errorEl = document.getElementById('challenge-list-error');
+      if (errorEl) errorEl.textContent = error.message;
+    }
+  },
+
+  formatChallengeDates(challenge) {
+    const options = { month: 'short', day: 'numeric', year: 'numeric' };
+    const start = new Date(challenge.starts_at).toLocaleDateString('en-US', options);
+    const end = new Date(challenge.ends_at).toLocaleDateString('en-US', options);
+    return `${start} – ${end}`;
+  },
+
+  showCreateChallengeModal() {
+    const friends = this.friendsList || [];
+    const today = new Date().toISOString().slice(0, 10);
+    const friendOptions = friends.length > 0
+      ? friends.map(friend => {
+        const otherUserId = friend.user_id === this.user.id ? friend.friend_id : friend.user_id;
+        return `
+          <label class="checkbox-label">
+            <input type="checkbox" name="challenge-friend" value="${otherUserId}">
+            <span>${this.escapeHtml(friend.friend_username)}</span>
+          </label>
+        `;
+      }).join('')
+      : '<p class="text-muted">Add some friends first to invite them.</p>';
+
+    this.openModal('New Challenge', `
+      <form data-action="create-challenge">
+        <div class="form-group">
+          <label for="challenge-name">Name</label>
+          <input type="text" id="challenge-name" class="form-input" maxlength="100" required
+                 placeholder="e.g., Most goals in March">
+        </div>
+        <div class="form-group">
+          <label for="challenge-start">Starts</label>
+          <input type="date" id="challenge-start" class="form-input" value="${today}" required>
+        </div>
+        <div class="form-group">
+          <label for="challenge-end">Ends</label>
+          <input type="date" id="challenge-end" class="form-input" required>
+        </div>
+        <div class="form-group">
+          <label class="form-label">Invite friends</label>
+          ${friendOptions}
+        </div>
+        <div id="challenge-error" class="form-error hidden"></div>
+        <div style="display: flex; gap: 1rem; margin-top: 1.5rem;">
+          <button type="button" class="btn btn-ghost" style="flex: 1;" data-action="close-modal">Cancel</button>
+          <button type="submit" class="btn btn-primary" style="flex: 1;">Create</button>
+        </div>
+      </form>
+    `);
+  },
+
+  async handleCreateChallenge(event) {
+    event.preventDefault();
+    const errorEl = document.getElementById('challenge-error');
+    const name = document.getElementById('challenge-name').value.trim();
+    const start = document.getElementById('challenge-start').value;
+    const end = document.getElementById('challenge-end').value;
+    const friendIds = Array.from(document.querySelectorAll('input[name="challenge-friend"]:checked'))
+      .map(input => input.value);
+
+    try {
+      // Dates are local calendar days; the challenge runs from the start of
+      // the first day to the end of the last.
+      const startsAt = new Date(`${start}T00:00:00`).toISOString();
+      const endsAt = new Date(`${end}T23:59:59`).toISOString();
+      const response = await API.challenges.create(name, startsAt, endsAt, friendIds);
+      this.closeModal();
+      this.toast('Challenge created!', 'success');
+      window.location.hash = `#challenge/${response.challenge.id}`;
+    } catch (error) {
+      if (errorEl) {
+        errorEl.textContent = error.message;
+        errorEl.classList.remove('hidden');
+      }
+    }
+  },
+
+  async renderChallenge(container, challengeId) {
+    container.innerHTML = '<div class="text-center"><div class="spinner"></div></div>';
+    try {
+      const response = await API.challenges.get(challengeId);
+      const challenge = response.challenge;
+      const isCreator = challenge.creator_id === this.user.id;
+      const standings = challenge.standings || [];
+      const invited = (challenge.participants || []).filter(p => !p.joined);
+
+      let actions = '';
+      if (isCreator) {
+        actions = `<button class="btn btn-ghost btn-sm" data-action="delete-challenge" data-challenge-id="${challenge.id}">Delete</button>`;
+      } else if (challenge.joined) {
+        actions = `<button class="btn btn-ghost btn-sm" data-action="leave-challenge" data-challenge-id="${challenge.id}">Leave</button>`;
+      } else if (challenge.status !== 'finished') {
+        actions = `<button class="btn btn-primary btn-sm" data-action="join-challenge" data-challenge-id="${challenge.id}">Join</button>`;
+      }
+
+      container.innerHTML = `
+        <div class="friends-page">
+          <div class="friends-header">
+            <h2>${this.escapeHtml(challenge.name)}</h2>
+            <div class="friend-actions">${actions}</div>
+          </div>
+          <p class="text-muted">
+            ${this.formatChallengeDates(challenge)} · created by ${this.escapeHtml(challenge.creator_username)} · ${this.escapeHtml(challenge.status)}
+          </p>
+
+          <div class="card">
+            <h3>Standings</h3>
+            <p class="text-muted">Goals completed on finalized cards during the challenge.</p>
+            ${standings.length > 0 ? standings.map(standing => `
+              <div class="friend-item">
+                <div>
+                  <strong>#${standing.rank} ${this.escapeHtml(standing.username)}</strong>
+                </div>
+                <span>${standing.completions} goal${standing.completions === 1 ? '' : 's'}</span>
+              </div>
+            `).join('') : '<p class="text-muted">Nobody has joined yet.</p>'}
+          </div>
+
+          ${invited.length > 0 ? `
+            <div class="card">
+              <h3>Invited</h3>
+              ${invited.map(p => `<div class="friend-item"><div>${this.escapeHtml(p.username)}</div></div>`).join('')}
+            </div>
+          ` : ''}
+
+          <a href="#friends" class="btn btn-secondary">Back to Friends</a>
+        </div>
+      `;
+    } catch (error) {
+      container.innerHTML = `
+        <div class="card text-center" style="padding: 3rem;">
+          <h3>Challenge unavailable</h3>
+          <p class="text-muted mb-lg" id="challenge-error-message"></p>
+          <a href="#friends" class="btn btn-primary">Back to Friends</a>
+        </div>
+      `;
+      const errorEl = document.getElementById('challenge-error-message');
+      if (errorEl) errorEl.textContent = error.message;
+    }
+  },
+
+  async joinChallenge(challengeId) {
+    try {
+      await API.challenges.join(challengeId);
+      this.toast('You joined the challenge!', 'success');
+      this.route();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async leaveChallenge(challengeId) {
+    if (!confirm('Leave this challenge?')) return;
+    try {
+      await API.challenges.leave(challengeId);
+      this.toast('You left the challenge', 'success');
+      window.location.hash = '#friends';
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async deleteChallenge(challengeId) {
+    if (!confirm('Delete this challenge for everyone?')) return;
+    try {
+      await API.challenges.remove(challengeId);
+      this.toast('Challenge deleted', 'success');
+      window.location.hash = '#friends';
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async renderOrganizations(container) {
+    container.innerHTML = `
+      <div class="friends-page">
+        <div class="friends-header">
+          <h2>Organizations</h2>
+          <button class="btn btn-primary btn-sm" data-action="show-create-org-modal">New Organization</button>
+        </div>
+        <p class="text-muted">Share cards and templates with your team, club or school.</p>
+
+        <div class="card">
+          <h3>Your Organizations</h3>
+          <div class="friend-list" id="org-list">
+            <div class="text-center"><div class="spinner" style="margin: 2rem auto;"></div></div>
+          </div>
+        </div>
+
+        <div class="card hidden" id="joinable-org-card">
+          <h3>Join with Your Email</h3>
+          <p class="text-muted">These organizations accept members with your verified email domain.</p>
+          <div class="friend-list" id="joinable-org-list"></div>
+        </div>
+      </div>
+    `;
+
+    try {
+      const [orgsResponse, joinableResponse] = await Promise.all([API.orgs.list(), API.orgs.joinable()]);
+      const orgs = orgsResponse.organizations || [];
+      const joinable = joinableResponse.organizations || [];
+
+      document.getElementById('org-list').innerHTML = orgs.length > 0 ? orgs.map(org => `
+        <div class="friend-item">
+          <div>
+            <strong><a href="#org/${org.id}">${this.escapeHtml(org.name)}</a></strong>
+            <div class="text-muted">${org.member_count} member${org.member_count === 1 ? '' : 's'} · ${this.escapeHtml(org.role)}</div>
+          </div>
+          <div class="friend-actions">
+            <a href="#org/${org.id}" class="btn btn-secondary btn-sm">View</a>
+          </div>
+        </div>
+      `).join('') : '<p class="text-muted">You are not in any organizations yet.</p>';
+
+      if (joinable.length > 0) {
+        document.getElementById('joinable-org-card').classList.remove('hidden');
+        document.getElementById('joinable-org-list').innerHTML = joinable.map(org => `
+          <div class="friend-item">
+            <div>
+              <strong>${this.escapeHtml(org.name)}</strong>
+              <div class="text-muted">@${this.escapeHtml(org.domain)} · ${org.member_count} member${org.member_count === 1 ? '' : 's'}</div>
+            </div>
+            <div class="friend-actions">
+              <button class="btn btn-primary btn-sm" data-action="join-org" data-org-id="${org.id}">Join</button>
+            </div>
+          </div>
+        `).join('');
+      }
+    } catch (error) {
+      const listEl = document.getElementById('org-list');
+      if (listEl) {
+        listEl.innerHTML = '<p class="text-muted" id="org-list-error"></p>';
+        document.getElementById('org-list-error').textContent = error.message;
+      }
+    }
+  },
+
+  showCreateOrgModal() {
+    this.openModal('New Organization', `
+      <form data-action="create-org">
+        <div class="form-group">
+          <label for="org-name">Name</label>
+          <input type="text" id="org-name" class="form-input" maxlength="100" required>
+        </div>
+        <div id="org-error" class="form-error hidden"></div>
+        <div style="display: flex; gap: 1rem; margin-top: 1.5rem;">
+          <button type="button" class="btn btn-secondary" style="flex: 1;" data-action="close-modal">Cancel</button>
+          <button type="submit" class="btn btn-primary" style="flex: 1;">Create</button>
+        </div>
+      </form>
+    `);
+  },
+
+  async handleCreateOrg(event) {
+    event.preventDefault();
+    const errorEl = document.getElementById('org-error');
+    const name = document.getElementById('org-name').value.trim();
+    try {
+      const response = await API.orgs.create(name);
+      this.closeModal();
+      this.toast('Organization created!', 'success');
+      window.location.hash = `#org/${response.organization.id}`;
+    } catch (error) {
+      errorEl.textContent = error.message;
+      errorEl.classList.remove('hidden');
+    }
+  },
+
+  async joinOrg(orgId) {
+    try {
+      await API.orgs.join(orgId);
+      this.toast('Joined organization', 'success');
+      window.location.hash = `#org/${orgId}`;
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async renderOrganization(container, orgId) {
+    container.innerHTML = '<div class="text-center"><div class="spinner"></div></div>';
+    try {
+      const [orgResponse, membersResponse, templatesResponse] = await Promise.all([
+        API.orgs.get(orgId),
+        API.orgs.members(orgId),
+        API.orgs.templates(orgId),
+      ]);
+      const org = orgResponse.organization;
+      const members = membersResponse.members || [];
+      const templates = templatesResponse.templates || [];
+      const isAdmin = org.role === 'admin';
+
+      const memberRows = members.map(member => {
+        const isSelf = member.user_id === this.user.id;
+        let actions = `<button class="btn btn-ghost btn-sm" data-action="view-org-member-cards" data-org-id="${org.id}" data-user-id="${member.user_id}">Cards</button>`;
+        if (isAdmin && !isSelf) {
+          const nextRole = member.role === 'admin' ? 'member' : 'admin';
+          actions += `
+            <button class="btn btn-ghost btn-sm" data-action="set-org-role" data-org-id="${org.id}" data-user-id="${member.user_id}" data-role="${nextRole}">
+              ${nextRole === 'admin' ? 'Make admin' : 'Make member'}
+            </button>
+            <button class="btn btn-ghost btn-sm" data-action="remove-org-member" data-org-id="${org.id}" data-user-id="${member.user_id}">Remove</button>
+          `;
+        }
+        return `
+          <div class="friend-item">
+            <div>
+              <strong>${this.escapeHtml(member.username)}</strong>
+              <div class="text-muted">${this.escapeHtml(member.role)}${isSelf ? ' · you' : ''}</div>
+            </div>
+            <div class="friend-actions">${actions}</div>
+          </div>
+        `;
+      }).join('');
+
+      const headerActions = isAdmin
+        ? `<button class="btn btn-ghost btn-sm" data-action="rename-org" data-org-id="${org.id}">Rename</button>
+           <button class="btn btn-ghost btn-sm" data-action="delete-org" data-org-id="${org.id}">Delete</button>`
+        : '';
+
+      container.innerHTML = `
+        <div class="friends-page">
+          <div class="friends-header">
+            <h2>${this.escapeHtml(org.name)}</h2>
+            <div class="friend-actions">
+              ${headerActions}
+              <button class="btn btn-ghost btn-sm" data-action="leave-org" data-org-id="${org.id}">Leave</button>
+            </div>
+          </div>
+          <p class="text-muted">
+            ${org.member_count} member${org.member_count === 1 ? '' : 's'} · you are ${org.role === 'admin' ? 'an admin' : 'a member'}
+          </p>
+
+          <div class="card">
+            <h3>Members</h3>
+            <p class="text-muted">Members see each other's finalized cards shared with the organization or made public.</p>
+            <div class="friend-list">${memberRows}</div>
+          </div>
+
+          <div class="card">
+            <h3>Templates</h3>
+            <div class="friend-list">
+              ${templates.length > 0
+                ? templates.map(template => this.renderTemplateListItem(template)).join('')
+                : `<p class="text-muted">No organization templates yet.${isAdmin ? ' Publish one from any of your cards.' : ''}</p>`}
+            </div>
+          </div>
+
+          ${isAdmin ? `
+            <div class="card">
+              <h3>Invite Links</h3>
+              <p class="text-muted">Anyone with an active link can join until it expires or you revoke it.</p>
+              <div class="friend-list" id="org-invite-list">
+                <div class="text-center"><div class="spinner" style="margin: 2rem auto;"></div></div>
+              </div>
+              <button class="btn btn-secondary btn-sm" data-action="create-org-invite" data-org-id="${org.id}">Create invite link</button>
+              <div id="org-invite-url" class="hidden" style="margin-top: 1rem;">
+                <input type="text" class="form-input" readonly id="org-invite-url-input">
+              </div>
+            </div>
+
+            <div class="card">
+              <h3>Email Domains</h3>
+              <p class="text-muted">People with a verified email at these domains can join without an invite.</p>
+              <div class="friend-list">
+                ${(org.domains || []).map(domain => `
+                  <div class="friend-item">
+                    <div>@${this.escapeHtml(domain)}</div>
+                    <div class="friend-actions">
+                      <button class="btn btn-ghost btn-sm" data-action="remove-org-domain" data-org-id="${org.id}" data-domain="${this.escapeHtml(domain)}">Remove</button>
+                    </div>
+                  </div>
+                `).join('') || '<p class="text-muted">No domains yet.</p>'}
+              </div>
+              <form data-action="add-org-domain" data-org-id="${org.id}" style="display: flex; gap: 0.5rem; margin-top: 1rem;">
+                <input type="text" id="org-domain" class="form-input" placeholder="example.com" required>
+                <button type="submit" class="btn btn-secondary">Add</button>
+              </form>
+            </div>
+
+            <div class="card">
+              <h3>Stats</h3>
+              <div id="org-stats">
+                <div class="text-center"><div class="spinner" style="margin: 2rem auto;"></div></div>
+              </div>
+            </div>
+          ` : ''}
+
+          <a href="#orgs" class="btn btn-secondary">Back to Organizations</a>
+        </div>
+      `;
+
+      if (isAdmin) {
+        await Promise.all([this.loadOrgInvites(org.id), this.loadOrgStats(org.id)]);
+      }
+    } catch (error) {
+      container.innerHTML = `
+        <div class="card text-center" style="padding: 3rem;">
+          <h3>Organization unavailable</h3>
+          <p class="text-muted mb-lg" id="org-error-message"></p>
+          <a href="#orgs" class="btn btn-primary">Back to Organizations</a>
+        </div>
+      `;
+      const errorEl = document.getElementById('org-error-message');
+      if (errorEl) errorEl.textContent = error.message;
+    }
+  },
+
+  async loadOrgInvites(orgId) {
+    const listEl = document.getElementById('org-invite-list');
+    if (!listEl) return;
+    try {
+      const response = await API.orgs.invites(orgId);
+      const invites = response.invites || [];
+      listEl.innerHTML = invites.length > 0 ? invites.map(invite => `
+        <div class="friend-item">
+          <div>
+            <div>Expires ${new Date(invite.expires_at).toLocaleDateString()}</div>
+            <div class="text-muted">${invite.use_count} use${invite.use_count === 1 ? '' : 's'}</div>
+          </div>
+          <div class="friend-actions">
+            <button class="btn btn-ghost btn-sm" data-action="revoke-org-invite" data-org-id="${orgId}" data-invite-id="${invite.id}">Revoke</button>
+          </div>
+        </div>
+      `).join('') : '<p class="text-muted">No active invite links.</p>';
+    } catch (error) {
+      listEl.innerHTML = '<p class="text-muted" id="org-invite-error"></p>';
+      document.getElementById('org-invite-error').textContent = error.message;
+    }
+  },
+
+  async loadOrgStats(orgId) {
+    const statsEl = document.getElementById('org-stats');
+    if (!statsEl) return;
+    try {
+      const { stats } = await API.orgs.stats(orgId, new Date().getFullYear());
+      const months = ['Jan', 'Feb', 'Mar', 'Apr', 'May', 'Jun', 'Jul', 'Aug', 'Sep', 'Oct', 'Nov', 'Dec'];
+      const percent = stats.total_items > 0 ? Math.round((stats.completed_items / stats.total_items) * 100) : 0;
+      statsEl.innerHTML = `
+        <p class="text-muted">${stats.year} totals for finalized cards shared with the organization.</p>
+        <div class="friend-item"><div>Members sharing cards</div><span>${stats.sharing_members} of ${stats.member_count}</span></div>
+        <div class="friend-item"><div>Cards</div><span>${stats.card_count}</span></div>
+        <div class="friend-item"><div>Goals completed</div><span>${stats.completed_items} of ${stats.total_items} (${percent}%)</span></div>
+        <div class="friend-item"><div>Bingos</div><span>${stats.bingos}</span></div>
+        <div class="friend-item">
+          <div>By month</div>
+          <span class="text-muted">${stats.completions_by_month.map((count, i) => `${months[i]} ${count}`).join(' · ')}</span>
+        </div>
+        ${(stats.templates || []).map(usage => `
+          <div class="friend-item">
+            <div><a href="#template/${usage.template_id}">${this.escapeHtml(usage.title)}</a></div>
+            <span>${usage.use_count} card${usage.use_count === 1 ? '' : 's'}</span>
+          </div>
+        `).join('')}
+      `;
+    } catch (error) {
+      statsEl.innerHTML = '<p class="text-muted" id="org-stats-error"></p>';
+      document.getElementById('org-stats-error').textContent = error.message;
+    }
+  },
+
+  async viewOrgMemberCards(orgId, userId) {
+    try {
+      const response = await API.orgs.memberCards(orgId, userId);
+      const cards = response.cards || [];
+      this.openModal(`${response.username}'s Cards`, `
+        ${cards.length > 0 ? cards.map(card => {
+          const items = card.items || [];
+          const completed = items.filter(item => item.is_completed).length;
+          return `
+            <div class="friend-item">
+              <div>
+                <strong>${this.escapeHtml(this.getCardDisplayName(card))}</strong>
+                <div class="text-muted">${completed} of ${items.length} goals completed</div>
+              </div>
+            </div>
+          `;
+        }).join('') : '<p class="text-muted">No cards shared with the organization.</p>'}
+        <div style="margin-top: 1.5rem;">
+          <button type="button" class="btn btn-secondary" style="width: 100%;" data-action="close-modal">Close</button>
+        </div>
+      `);
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async renameOrg(orgId) {
+    const name = prompt('New organization name');
+    if (!name || !name.trim()) return;
+    try {
+      await API.orgs.rename(orgId, name.trim());
+      this.toast('Organization renamed', 'success');
+      this.route();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async deleteOrg(orgId) {
+    if (!confirm('Delete this organization for everyone? Its templates are deleted too.')) return;
+    try {
+      await API.orgs.remove(orgId);
+      this.toast('Organization deleted', 'success');
+      window.location.hash = '#orgs';
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async leaveOrg(orgId) {
+    if (!confirm('Leave this organization?')) return;
+    try {
+      await API.orgs.removeMember(orgId, this.user.id);
+      this.toast('You left the organization', 'success');
+      window.location.hash = '#orgs';
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async setOrgRole(orgId, userId, role) {
+    try {
+      await API.orgs.setRole(orgId, userId, role);
+      this.toast('Role updated', 'success');
+      this.route();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async removeOrgMember(orgId, userId) {
+    if (!confirm('Remove this member from the organization?')) return;
+    try {
+      await API.orgs.removeMember(orgId, userId);
+      this.toast('Member removed', 'success');
+      this.route();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async createOrgInvite(orgId) {
     try {
-      const response = await API.friends.listInvites();
-      const invites = response.invites || [];
-      if (invites.length === 0) {
-        listEl.innerHTML = '<p class="text-muted">No active invites.</p>';
-        return;
+      const response = await API.orgs.createInvite(orgId, 14);
+      const url = `${window.location.origin}/${response.url}`;
+      const wrapper = document.getElementById('org-invite-url');
+      const input = document.getElementById('org-invite-url-input');
+      if (wrapper && input) {
+        input.value = url;
+        wrapper.classList.remove('hidden');
+        input.select();
       }
-
-      listEl.innerHTML = invites.map(invite => `
-        <div class="friend-item">
-          <div>
-            <strong>Invite created</strong>
-            <div class="text-muted">
-              ${invite.expires_at ? `Expires ${new Date(invite.expires_at).toLocaleDateString()}` : 'No expiration'}
-            </div>
-          </div>
-          <button class="btn btn-ghost btn-sm" data-action="revoke-invite" data-invite-id="${invite.id}">Revoke</button>
-        </div>
-      `).join('');
+      this.toast('Invite link created. Copy it now; it is only shown once.', 'success');
+      await this.loadOrgInvites(orgId);
     } catch (error) {
-      listEl.innerHTML = '<p class="text-muted" id="invite-list-error"></p>';
-      const errorEl = document.getElementById('invite-list-error');
-      if (errorEl) errorEl.textContent = error.message;
+      this.toast(error.message, 'error');
     }
   },
 
-  async revokeInvite(inviteId) {
+  async revokeOrgInvite(orgId, inviteId) {
     try {
-      await API.friends.revokeInvite(inviteId);
-      this.toast('Invite revoked', 'success');
-      await this.loadInvites();
+      await API.orgs.revokeInvite(orgId, inviteId);
+      this.toast('Invite link revoked', 'success');
+      await this.loadOrgInvites(orgId);
     } catch (error) {
       this.toast(error.message, 'error');
     }
   },
 
-  async loadBlockedUsers() {
-    const blockedEl = document.getElementById('blocked-users');
-    const blockedListEl = document.getElementById('blocked-list');
-    if (!blockedEl || !blockedListEl) return;
+  async handleAddOrgDomain(event, form) {
+    event.preventDefault();
+    const orgId = form.dataset.orgId;
+    const domain = document.getElementById('org-domain').value.trim();
     try {
-      const response = await API.friends.listBlocked();
-      const blocked = response.blocked || [];
-      if (blocked.length === 0) {
-        blockedEl.style.display = 'none';
-        return;
-      }
-      blockedEl.style.display = 'block';
-      blockedListEl.innerHTML = blocked.map(user => `
-        <div class="friend-item">
-          <div>
-            <strong>${this.escapeHtml(user.username)}</strong>
-          </div>
-          <button class="btn btn-ghost btn-sm" data-action="unblock-user" data-user-id="${user.id}">Unblock</button>
-        </div>
-      `).join('');
+      await API.orgs.addDomain(orgId, domain);
+      this.toast('Domain added', 'success');
+      this.route();
     } catch (error) {
-      blockedEl.style.display = 'block';
-      blockedListEl.innerHTML = '<p class="text-muted" id="blocked-error"></p>';
-      const errorEl = document.getElementById('blocked-error');
-      if (errorEl) errorEl.textContent = error.message;
+      this.toast(error.message, 'error');
     }
   },
 
-  async sendFriendRequest(friendId) {
+  async removeOrgDomain(orgId, domain) {
+    if (!confirm(`Stop letting @${domain} addresses join?`)) return;
     try {
-      await API.friends.sendRequest(friendId);
-      this.toast('Friend request sent!', 'success');
-      document.getElementById('friend-search').value = '';
-      document.getElementById('search-results').innerHTML = '';
-      await this.loadFriends();
+      await API.orgs.removeDomain(orgId, domain);
+      this.toast('Domain removed', 'success');
+      this.route();
     } catch (error) {
       this.toast(error.message, 'error');
     }
   },
 
-  async loadFriends() {
-    try {
-      const response = await API.friends.list();
-      const { friends, requests, sent } = response;
+  renderOrgInviteGate(container, token) {
+    if (!token) {
+      container.innerHTML = `
+        <div class="card text-center" style="padding: 3rem;">
+          <h3>Invite link not found</h3>
+          <p class="text-muted mb-lg">This invite link is missing or invalid.</p>
+          <a href="#home" class="btn btn-primary">Go Home</a>
+        </div>
+      `;
+      return;
+    }
 
-      // Pending requests (received)
-      const requestsEl = document.getElementById('friend-requests');
-      const requestsListEl = document.getElementById('requests-list');
-      if (requests && requests.length > 0) {
-        requestsEl.style.display = 'block';
-        requestsListEl.innerHTML = requests.map(req => `
-          <div class="friend-item">
-            <div>
-              <strong>${this.escapeHtml(req.requester_username)}</strong>
-            </div>
-            <div class="friend-actions">
-              <button class="btn btn-primary btn-sm" data-action="accept-request" data-request-id="${req.id}">Accept</button>
-              <button class="btn btn-ghost btn-sm" data-action="reject-request" data-request-id="${req.id}">Reject</button>
-            </div>
-          </div>
-        `).join('');
-      } else {
-        requestsEl.style.display = 'none';
-      }
+    this.storePendingInviteToken(token, 'org-invite');
+    container.innerHTML = `
+      <div class="card text-center" style="padding: 3rem;">
+        <h3>Join Organization</h3>
+        <p class="text-muted mb-lg">Sign in or create an account to join this organization.</p>
+        <div style="display: flex; gap: 1rem; justify-content: center; flex-wrap: wrap;">
+          <a href="#login" class="btn btn-primary">Sign In</a>
+          <a href="#register" class="btn btn-secondary">Create Account</a>
+        </div>
+      </div>
+    `;
+  },
 
-      // Sent requests
-      const sentEl = document.getElementById('sent-requests');
-      const sentListEl = document.getElementById('sent-list');
-      if (sent && sent.length > 0) {
-        sentEl.style.display = 'block';
-        sentListEl.innerHTML = sent.map(req => `
-          <div class="friend-item">
-            <div>
-              <strong>${this.escapeHtml(req.friend_username)}</strong>
-            </div>
-            <button class="btn btn-ghost btn-sm" data-action="cancel-request" data-request-id="${req.id}">Cancel</button>
-          </div>
-        `).join('');
-      } else {
-        sentEl.style.display = 'none';
-      }
+  async renderOrgInviteAccept(container, token) {
+    if (!token) {
+      window.location.hash = '#orgs';
+      return;
+    }
 
-      // Friends list
-      const friendsListEl = document.getElementById('friends-list');
-      if (friends && friends.length > 0) {
-        friendsListEl.innerHTML = friends.map(friend => {
-          const otherUserId = friend.user_id === this.user.id ? friend.friend_id : friend.user_id;
-          const friendName = this.escapeHtml(friend.friend_username);
-          return `
-            <div class="friend-item">
-              <div>
-                <strong>${friendName}</strong>
-              </div>
-              <div class="friend-actions">
-                <a href="#friend-card/${friend.id}" class="btn btn-secondary btn-sm">View Card</a>
-                <button class="btn btn-ghost btn-sm" data-action="remove-friend" data-friendship-id="${friend.id}">Remove</button>
-                <button class="btn btn-ghost btn-sm" data-action="block-user" data-other-user-id="${otherUserId}">Block</button>
-              </div>
-            </div>
-          `;
-        }).join('');
-      } else {
-        friendsListEl.innerHTML = '<p class="text-muted">No friends yet. Search for people to add!</p>';
-      }
+    container.innerHTML = `
+      <div class="card text-center" style="padding: 3rem;">
+        <div class="spinner" style="margin: 2rem auto;"></div>
+        <p>Joining organization...</p>
+      </div>
+    `;
+
+    try {
+      const response = await API.orgs.acceptInvite(token);
+      this.toast('Joined organization', 'success');
+      window.location.hash = `#org/${response.organization.id}`;
     } catch (error) {
-      this.toast(error.message, 'error');
+      container.innerHTML = `
+        <div class="card text-center" style="padding: 3rem;">
+          <h3>Invite Error</h3>
+          <p class="text-muted mb-lg" id="org-invite-accept-error"></p>
+          <a href="#orgs" class="btn btn-primary">Back to Organizations</a>
+        </div>
+      `;
+      const errorEl = document.getElementById('org-invite-accept-error');
+      if (errorEl) errorEl.textContent = error.message;
     }
   },
 
@@ -5446,6 +7062,77 @@ const App = {
     return this.renderGrid(true);
   },
 
+  async renderPublicProfile(container, username) {
+    container.innerHTML = `
+      <div class="text-center"><div class="spinner" style="margin: 2rem auto;"></div></div>
+    `;
+
+    try {
+      const response = await API.profiles.get(username);
+      this.publicProfile = response.profile;
+      this.currentCard = this.publicProfile.cards[0] || null;
+      this.renderPublicProfileView(container);
+    } catch (error) {
+      container.innerHTML = `
+        <div class="card text-center" style="padding: 3rem;">
+          <h3>Profile Not Found</h3>
+          <p class="text-muted mb-lg">This profile doesn't exist or isn't public.</p>
+          <a href="#home" class="btn btn-primary">Go Home</a>
+        </div>
+      `;
+    }
+  },
+
+  renderPublicProfileView(container) {
+    const profile = this.publicProfile;
+    const stats = profile.stats;
+    const memberSince = new Date(profile.member_since).toLocaleDateString('en-US', { year: 'numeric', month: 'long' });
+    const badges = profile.badges.map(b => `
+      <span class="year-badge" title="${this.escapeHtml(b.description)}">${this.escapeHtml(b.name)}</span>
+    `).join('');
+    const cardTabs = profile.cards.length > 1 ? profile.cards.map(card => `
+      <button class="btn btn-sm ${card.id === this.currentCard.id ? 'btn-primary' : 'btn-ghost'}" data-action="switch-public-card" data-card-id="${card.id}">
+        ${this.getCardDisplayName(card)} (${card.year})
+      </button>
+    `).join('') : '';
+
+    let cardView = '<p class="text-muted text-center">No public cards yet.</p>';
+    if (this.currentCard) {
+      const gridSize = this.getGridSize(this.currentCard);
+      cardView = `
+        <div class="friend-card-title">
+          <h3 style="margin: 0;">${this.getCardDisplayName(this.currentCard)} <span class="year-badge">${this.currentCard.year}</span></h3>
+        </div>
+        <div class="bingo-container bingo-container--finalized">
+          <div class="bingo-grid bingo-grid--finalized" id="bingo-grid" style="--grid-size: ${gridSize};">
+            ${this.renderGrid(true)}
+          </div>
+        </div>
+      `;
+    }
+
+    container.innerHTML = `
+      <div class="finalized-card-view">
+        <div class="card profile-section text-center">
+          <h2 style="margin: 0;">${this.escapeHtml(profile.username)}</h2>
+          <p class="text-muted">Playing since ${memberSince}</p>
+          <p>${stats.completed_items}/${stats.total_items} goals completed across ${stats.card_count} ${stats.card_count === 1 ? 'card' : 'cards'} · ${stats.bingos} ${stats.bingos === 1 ? 'bingo' : 'bingos'}</p>
+          ${badges ? `<div style="display: flex; gap: 0.5rem; flex-wrap: wrap; justify-content: center;">${badges}</div>` : ''}
+        </div>
+        ${cardTabs ? `<div style="display: flex; gap: 0.5rem; flex-wrap: wrap; justify-content: center; margin: 1rem 0;">${cardTabs}</div>` : ''}
+        ${cardView}
+      </div>
+    `;
+  },
+
+  switchPublicProfileCard(cardId) {
+    const card = this.publicProfile?.cards.find(c => c.id === cardId);
+    if (card) {
+      this.currentCard = card;
+      this.renderPublicProfileView(document.getElementById('main-container'));
+    }
+  },
+
   setupFriendCardEvents() {
     document.getElementById('bingo-grid').addEventListener('click', async (e) => {
       const cell = e.target.closest('.bingo-cell');
@@ -5597,6 +7284,16 @@ const App = {
                 <span>Allow others to find me by username</span>
               </label>
               <small class="text-muted">When disabled, you won't appear in friend search results</small>
+              <label class="checkbox-label">
+                <input type="checkbox" id="public-profile-toggle" ${this.user.public_profile ? 'checked' : ''}>
+                <span>Public profile at <a href="/u/${encodeURIComponent(this.user.username)}">/u/${this.escapeHtml(this.user.username)}</a></span>
+              </label>
+              <small class="text-muted">Anyone can see your stats, badges and the cards you mark as public. Notes are never shown.</small>
+              <label class="checkbox-label">
+                <input type="checkbox" id="leaderboard-opt-out-toggle" ${this.user.leaderboard_opt_out ? 'checked' : ''}>
+                <span>Hide me from friends' leaderboards</span>
+              </label>
+              <small class="text-muted">Your friends won't see your ranking, and you won't see the leaderboard either</small>
             </div>
           </div>
 
@@ -5642,6 +7339,17 @@ const App = {
             </div>
           </div>
 
+          <div class="card profile-section">
+            <h3>Calendar Feed</h3>
+            <p class="text-muted" style="margin-bottom: 1rem;">
+              Subscribe to your goal due dates and completions in Google Calendar, Apple Calendar or Outlook.
+              The feed URL is private: anyone who has it can see your goals.
+            </p>
+            <div id="calendar-feed">
+              <div class="text-center"><div class="spinner spinner--small"></div></div>
+            </div>
+          </div>
+
           <div class="card profile-section">
             <h3>Account Actions</h3>
             <div class="profile-actions">
@@ -5655,6 +7363,7 @@ const App = {
     this.setupProfileEvents();
     this.loadNotificationSettings();
     this.loadApiTokens();
+    this.loadCalendarFeed();
   },
 
   setupProfileEvents() {
@@ -5674,6 +7383,30 @@ const App = {
       }
     });
 
+    const publicProfileToggle = document.getElementById('public-profile-toggle');
+    publicProfileToggle.addEventListener('change', async (e) => {
+      try {
+        const response = await API.auth.updatePublicProfile(e.target.checked);
+        this.user = response.user;
+        this.toast(e.target.checked ? 'Your profile is now public' : 'Your profile is now private', 'success');
+      } catch (error) {
+        e.target.checked = !e.target.checked; // Revert on error
+        this.toast(error.message, 'error');
+      }
+    });
+
+    const leaderboardOptOutToggle = document.getElementById('leaderboard-opt-out-toggle');
+    leaderboardOptOutToggle.addEventListener('change', async (e) => {
+      try {
+        const response = await API.auth.updateLeaderboardOptOut(e.target.checked);
+        this.user = response.user;
+        this.toast(e.target.checked ? 'You are hidden from leaderboards' : 'You are shown on leaderboards', 'success');
+      } catch (error) {
+        e.target.checked = !e.target.checked; // Revert on error
+        this.toast(error.message, 'error');
+      }
+    });
+
     form.addEventListener('submit', async (e) => {
       e.preventDefault();
       errorEl.classList.add('hidden');
@@ -5756,6 +7489,7 @@ const App = {
           </div>
           <div class="card-header-actions">
             <button class="btn btn-ghost btn-sm" data-action="show-clone-card-modal" title="Clone card">📄</button>
+            <button class="btn btn-ghost btn-sm" data-action="show-publish-template-modal" title="Publish as template">📚</button>
             <button class="visibility-toggle-btn ${this.currentCard.visible_to_friends ? 'visibility-toggle-btn--visible' : 'visibility-toggle-btn--private'}" data-action="toggle-card-visibility" data-card-id="${this.currentCard.id}" data-visible="${!this.currentCard.visible_to_friends}" title="${visibilityLabel}">
               <i class="fas fa-${visibilityIcon}"></i>
               <span>${visibilityLabel}</span>
@@ -5880,6 +7614,7 @@ const App = {
       this.stopNotificationPolling();
       this.setupNavigation();
       sessionStorage.removeItem('pendingInviteToken');
+      sessionStorage.removeItem('pendingInviteRoute');
       this._allowNextHashRoute = true;
       window.location.hash = '#home';
       this.toast('Logged out successfully', 'success');
@@ -6134,6 +7869,78 @@ const App = {
     `);
   },
 
+  async loadCalendarFeed() {
+    const feedEl = document.getElementById('calendar-feed');
+    if (!feedEl) return;
+
+    try {
+      const response = await API.calendar.getFeed();
+      this.renderCalendarFeed(response.feed);
+    } catch (error) {
+      feedEl.innerHTML = '<p class="text-muted text-danger" id="calendar-feed-error"></p>';
+      const errorEl = document.getElementById('calendar-feed-error');
+      if (errorEl) errorEl.textContent = `Failed to load calendar feed: ${error.message}`;
+    }
+  },
+
+  // The feed URL is only returned when the feed is created, so an existing
+  // feed can be rotated or revoked but not shown again.
+  renderCalendarFeed(feed) {
+    const feedEl = document.getElementById('calendar-feed');
+    if (!feedEl) return;
+
+    if (!feed) {
+      feedEl.innerHTML = '<button class="btn btn-secondary btn-sm" data-action="create-calendar-feed">Create Feed URL</button>';
+      return;
+    }
+
+    const urlSection = feed.url ? `
+      <div class="form-group">
+        <code id="calendar-feed-url" style="display: block; word-break: break-all; background: var(--surface-2); padding: 0.5rem; border-radius: 0.25rem;">${this.escapeHtml(feed.url)}</code>
+        <div style="display: flex; gap: 0.5rem; margin-top: 0.5rem;">
+          <button class="btn btn-secondary btn-sm" data-action="copy-calendar-feed">Copy URL</button>
+          <a class="btn btn-ghost btn-sm" href="${this.escapeHtml(feed.url.replace(/^https?:/, 'webcal:'))}">Open in Calendar App</a>
+        </div>
+        <small class="text-muted">Copy this URL now. It won't be shown again.</small>
+      </div>
+    ` : '';
+
+    feedEl.innerHTML = `
+      ${urlSection}
+      <p class="text-muted" style="font-size: 0.85rem;">
+        Created ${new Date(feed.created_at).toLocaleDateString()}
+        • Last fetched: ${feed.last_used_at ? new Date(feed.last_used_at).toLocaleString() : 'Never'}
+      </p>
+      <div style="display: flex; gap: 0.5rem;">
+        <button class="btn btn-secondary btn-sm" data-action="create-calendar-feed">Get New URL</button>
+        <button class="btn btn-ghost btn-sm" style="color: var(--color-danger);" data-action="revoke-calendar-feed">Revoke Feed</button>
+      </div>
+    `;
+  },
+
+  async createCalendarFeed() {
+    const existing = document.querySelector('[data-action="revoke-calendar-feed"]');
+    if (existing && !confirm('Create a new feed URL? Calendars subscribed to the old URL will stop updating.')) return;
+    try {
+      const response = await API.calendar.createFeed();
+      this.renderCalendarFeed(response.feed);
+      this.toast('Calendar feed created', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async revokeCalendarFeed() {
+    if (!confirm('Revoke your calendar feed? Subscribed calendars will stop updating.')) return;
+    try {
+      await API.calendar.revokeFeed();
+      this.renderCalendarFeed(null);
+      this.toast('Calendar feed revoked', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
   copyToClipboard(text) {
     navigator.clipboard.writeText(text).then(() => {
       this.toast('Copied to clipboard', 'success');